@@ -9,12 +9,15 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost-plugin-boards/server/app"
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -22,6 +25,7 @@ import (
 const (
 	HeaderRequestedWith    = "X-Requested-With"
 	HeaderRequestedWithXML = "XMLHttpRequest"
+	HeaderIfMatch          = "If-Match"
 	UploadFormFileKey      = "file"
 	True                   = "true"
 
@@ -43,6 +47,8 @@ type API struct {
 	permissions permissions.PermissionsService
 	logger      mlog.LoggerIFace
 	audit       *audit.Audit
+
+	incomingWebhookLimiter *utils.RateLimiter
 }
 
 func NewAPI(
@@ -59,10 +65,18 @@ func NewAPI(
 		permissions: permissions,
 		logger:      logger,
 		audit:       audit,
+
+		incomingWebhookLimiter: utils.NewRateLimiter(incomingWebhookRequestsPerSecond, incomingWebhookBurst),
 	}
 }
 
 func (a *API) RegisterRoutes(r *mux.Router) {
+	// Incoming webhooks are called by external systems that authenticate
+	// with a token instead of a session, so they skip the CSRF check
+	hooks := r.PathPrefix("/api/v2/hooks").Subrouter()
+	hooks.Use(a.panicHandler)
+	a.registerIncomingWebhookHooksRoutes(hooks)
+
 	apiv2 := r.PathPrefix("/api/v2").Subrouter()
 	apiv2.Use(a.panicHandler)
 	apiv2.Use(a.requireCSRFToken)
@@ -79,8 +93,12 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerCategoriesRoutes(apiv2)
 	a.registerSharingRoutes(apiv2)
 	a.registerTeamsRoutes(apiv2)
+	// before the archive routes, which match /archive/import/{format}
+	a.registerImportSessionsRoutes(apiv2)
 	a.registerAchivesRoutes(apiv2)
+	a.registerExportJobsRoutes(apiv2)
 	a.registerSubscriptionsRoutes(apiv2)
+	a.registerDigestSettingsRoutes(apiv2)
 	a.registerFilesRoutes(apiv2)
 	a.registerOnboardingRoutes(apiv2)
 	a.registerSearchRoutes(apiv2)
@@ -96,6 +114,17 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 
 	// V3 routes
 	a.registerCardsRoutes(apiv2)
+	a.registerCardRecurrencesRoutes(apiv2)
+	a.registerViewsRoutes(apiv2)
+	a.registerWebhooksRoutes(apiv2)
+	a.registerIncomingWebhooksRoutes(apiv2)
+	a.registerDueDateRemindersRoutes(apiv2)
+	a.registerBoardRulesRoutes(apiv2)
+	a.registerCardLinksRoutes(apiv2)
+	a.registerTimeEntriesRoutes(apiv2)
+	a.registerPresenceRoutes(apiv2)
+	a.registerChangesRoutes(apiv2)
+	a.registerCardRevisionsRoutes(apiv2)
 
 	// System routes are outside the /api/v2 path
 	a.registerSystemRoutes(r)
@@ -161,6 +190,23 @@ func (a *API) hasValidReadTokenForBoard(r *http.Request, boardID string) bool {
 	return isValid
 }
 
+// expectedUpdateAtFromRequest returns the update time of the If-Match
+// header, which patches use to only apply to the version of the entity
+// they were made from. It returns nil if the header is not set or is "*".
+func expectedUpdateAtFromRequest(r *http.Request) (*int64, error) {
+	value := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	updateAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("invalid %s header: %s", HeaderIfMatch, r.Header.Get(HeaderIfMatch)))
+	}
+	return &updateAt, nil
+}
+
 func (a *API) userIsGuest(userID string) (bool, error) {
 	return a.app.UserIsGuest(userID)
 }
@@ -173,6 +219,22 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	}
 	errorResponse := model.ErrorResponse{Error: err.Error()}
 
+	// conflicts are answered with the current version of the entity, so
+	// that the client can redo its changes on top of it
+	var conflict *model.ErrConflict
+	if errors.As(err, &conflict) && conflict.Current != nil {
+		a.logger.Debug("api conflict response",
+			mlog.Err(err),
+			mlog.String("api", r.URL.Path),
+		)
+
+		data, jsonErr := json.Marshal(conflict.Current)
+		if jsonErr == nil {
+			jsonBytesResponse(w, http.StatusConflict, data)
+			return
+		}
+	}
+
 	switch {
 	case model.IsErrBadRequest(err):
 		errorResponse.ErrorCode = http.StatusBadRequest
@@ -184,8 +246,12 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 		errorResponse.ErrorCode = http.StatusNotFound
 	case model.IsErrRequestEntityTooLarge(err):
 		errorResponse.ErrorCode = http.StatusRequestEntityTooLarge
+	case model.IsErrTooManyRequests(err):
+		errorResponse.ErrorCode = http.StatusTooManyRequests
 	case model.IsErrNotImplemented(err):
 		errorResponse.ErrorCode = http.StatusNotImplemented
+	case model.IsErrConflict(err):
+		errorResponse.ErrorCode = http.StatusConflict
 	default:
 		errorResponse.Error = "internal server error"
 		errorResponse.ErrorCode = http.StatusInternalServerError
@@ -15,6 +15,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/stretchr/testify/require"
 
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 	pluginapi "github.com/mattermost/mattermost/server/public/pluginapi"
 )
 
@@ -59,6 +60,10 @@ func TestErrorResponse(t *testing.T) {
 		{"ErrNotFound", model.ErrInsufficientLicense, http.StatusNotImplemented, "appropriate license required"},
 		{"ErrNotImplemented", model.NewErrNotImplemented("not implemented in plugin mode"), http.StatusNotImplemented, "plugin mode"},
 
+		// conflict
+		{"ErrConflict", model.NewErrConflict("block", &model.Block{ID: "current-block"}), http.StatusConflict, "current-block"},
+		{"ErrConflict without current", model.NewErrConflict("block", nil), http.StatusConflict, "has been modified"},
+
 		// internal server error
 		{"Any other error", ErrHandlerPanic, http.StatusInternalServerError, "internal server error"},
 	}
@@ -80,3 +85,36 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestExpectedUpdateAtFromRequest(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		IfMatch  string
+		Expected *int64
+		IsError  bool
+	}{
+		{"no header", "", nil, false},
+		{"any version", "*", nil, false},
+		{"update time", "1700000000000", mmModel.NewPointer(int64(1700000000000)), false},
+		{"quoted update time", `"1700000000000"`, mmModel.NewPointer(int64(1700000000000)), false},
+		{"weak update time", `W/"1700000000000"`, mmModel.NewPointer(int64(1700000000000)), false},
+		{"invalid update time", "yesterday", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/test", nil)
+			if tc.IfMatch != "" {
+				r.Header.Set(HeaderIfMatch, tc.IfMatch)
+			}
+
+			expected, err := expectedUpdateAtFromRequest(r)
+			if tc.IsError {
+				require.True(t, model.IsErrBadRequest(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, expected)
+		})
+	}
+}
@@ -4,9 +4,13 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -24,7 +28,10 @@ const (
 func (a *API) registerAchivesRoutes(r *mux.Router) {
 	// Archive APIs
 	r.HandleFunc("/boards/{boardID}/archive/export", a.sessionRequired(a.handleArchiveExportBoard)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/export", a.sessionRequired(a.handleExportBoardTable)).Methods("GET")
 	r.HandleFunc("/teams/{teamID}/archive/import", a.sessionRequired(a.handleArchiveImport)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/import/{format}", a.sessionRequired(a.handleExternalImport)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/import/csv", a.sessionRequired(a.handleImportCSV)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/archive/export", a.sessionRequired(a.handleArchiveExportTeam)).Methods("GET")
 }
 
@@ -107,6 +114,90 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleExportBoardTable(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/export exportBoardTable
+	//
+	// Exports the cards of a board, or of one of its views, as a CSV or XLSX table.
+	//
+	// ---
+	// produces:
+	// - text/csv
+	// - application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Id of board to export
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: File format, csv or xlsx (defaults to csv)
+	//   required: false
+	//   type: string
+	// - name: viewId
+	//   in: query
+	//   description: Id of a view of the board whose filter, sort and visible properties are applied
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     content:
+	//       application-octet-stream:
+	//         type: string
+	//         format: binary
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	opts := model.ExportTableOptions{
+		BoardID: boardID,
+		ViewID:  query.Get("viewId"),
+		Format:  model.TableExportFormat(query.Get("format")),
+		UserID:  userID,
+	}
+	if opts.Format == "" {
+		opts.Format = model.TableExportCSV
+	}
+	if err := opts.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "exportBoardTable", audit.Fail)
+	defer func() {
+		if a.audit != nil {
+			a.audit.LogRecord(audit.LevelRead, auditRec)
+		}
+	}()
+	auditRec.AddMeta("BoardID", boardID)
+	auditRec.AddMeta("ViewID", opts.ViewID)
+	auditRec.AddMeta("format", opts.Format)
+
+	filename := fmt.Sprintf("export-%s.%s", time.Now().Format("2006-01-02"), opts.Format)
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+
+	if err := a.app.ExportBoardTable(w, opts); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
 func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/archive/import archiveImport
 	//
@@ -203,6 +294,298 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleExternalImport(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import/{format} externalImport
+	//
+	// Imports a board exported from another tool: a Trello board JSON, Jira
+	// issues JSON or CSV, or an Asana project JSON.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: path
+	//   description: Format of the export (trello, jira or asana)
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: export file to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	format := model.ImportFormat(vars["format"])
+
+	if err := format.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board"))
+		return
+	}
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if isGuest {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board"))
+		return
+	}
+
+	if a.app.GetConfig().MaxFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, a.app.GetConfig().MaxFileSize)
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			a.errorResponse(w, r, model.ErrRequestEntityTooLarge)
+			return
+		}
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	auditRec := a.makeAuditRecord(r, "externalImport", audit.Fail)
+	defer func() {
+		if a.audit != nil {
+			a.audit.LogRecord(audit.LevelModify, auditRec)
+		}
+	}()
+	auditRec.AddMeta("format", format)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: userID,
+		BoardValidator: func(board *model.Board) error {
+			return a.authorizeBoardCreate(userID, teamID, board.Type)
+		},
+	}
+
+	board, err := a.app.ImportExternalBoard(file, format, opt)
+	if err != nil {
+		a.logger.Debug("Error importing external board",
+			mlog.String("team_id", teamID),
+			mlog.String("format", string(format)),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardID", board.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/import/csv importCSV
+	//
+	// Imports the rows of a CSV file as the cards of a new board, or of an
+	// existing board. Columns are mapped to card properties, which are created
+	// as needed. With dryRun, nothing is imported and the result reports the
+	// proposed card properties and the errors of the rows.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: CSV file to import
+	//   required: true
+	//   type: file
+	// - name: boardId
+	//   in: formData
+	//   description: Board to import the cards into. If empty, a new board is created
+	//   required: false
+	//   type: string
+	// - name: title
+	//   in: formData
+	//   description: Title of the new board. Defaults to the name of the file
+	//   required: false
+	//   type: string
+	// - name: titleColumn
+	//   in: formData
+	//   description: Column holding the titles of the cards
+	//   required: false
+	//   type: string
+	// - name: keyColumn
+	//   in: formData
+	//   description: Column identifying the cards, so that the matching cards are updated instead of created
+	//   required: false
+	//   type: string
+	// - name: mapping
+	//   in: formData
+	//   description: JSON object mapping column names to card property ids
+	//   required: false
+	//   type: string
+	// - name: dryRun
+	//   in: formData
+	//   description: If true, nothing is imported
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CSVImportResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	if a.app.GetConfig().MaxFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, a.app.GetConfig().MaxFileSize)
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			a.errorResponse(w, r, model.ErrRequestEntityTooLarge)
+			return
+		}
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	opt := model.ImportCSVOptions{
+		TeamID:      teamID,
+		ModifiedBy:  userID,
+		BoardID:     r.FormValue("boardId"),
+		Title:       r.FormValue("title"),
+		TitleColumn: r.FormValue("titleColumn"),
+		KeyColumn:   r.FormValue("keyColumn"),
+	}
+	if opt.Title == "" {
+		opt.Title = strings.TrimSuffix(handle.Filename, filepath.Ext(handle.Filename))
+	}
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opt.Mapping); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest("invalid mapping: "+err.Error()))
+			return
+		}
+	}
+	if dryRun := r.FormValue("dryRun"); dryRun != "" {
+		if opt.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest("invalid dryRun: "+err.Error()))
+			return
+		}
+	}
+
+	if opt.BoardID != "" {
+		if !a.permissions.HasPermissionToBoard(userID, opt.BoardID, model.PermissionManageBoardCards) ||
+			!a.permissions.HasPermissionToBoard(userID, opt.BoardID, model.PermissionManageBoardProperties) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to import cards into board"))
+			return
+		}
+	} else {
+		isGuest, err := a.userIsGuest(userID)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		if isGuest {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to create board"))
+			return
+		}
+		if err := a.authorizeBoardCreate(userID, teamID, model.BoardTypePrivate); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "importCSV", audit.Fail)
+	defer func() {
+		if a.audit != nil {
+			a.audit.LogRecord(audit.LevelModify, auditRec)
+		}
+	}()
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+	auditRec.AddMeta("boardID", opt.BoardID)
+	auditRec.AddMeta("dryRun", opt.DryRun)
+
+	result, err := a.app.ImportCSV(file, opt)
+	if err != nil {
+		a.logger.Debug("Error importing CSV file",
+			mlog.String("team_id", teamID),
+			mlog.String("board_id", opt.BoardID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("created", result.Created)
+	auditRec.AddMeta("updated", result.Updated)
+	auditRec.Success()
+}
+
 func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /teams/{teamID}/archive/export archiveExportTeam
 	//
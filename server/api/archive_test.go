@@ -41,7 +41,7 @@ func setupArchiveImportAPI(t *testing.T, maxFileSize int64) (*API, func()) {
 	authService := auth.New(&cfg, store, nil)
 	logger, _ := mlog.NewLogger()
 	wsserver := ws.NewServer(authService, logger, store)
-	webhookClient := webhook.NewClient(&cfg, logger)
+	webhookClient := webhook.NewClient(&cfg, store, logger)
 	metricsService := metrics.NewMetrics(metrics.InstanceInfo{})
 
 	permStore := permissionsMocks.NewMockStore(ctrl)
@@ -154,6 +154,11 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err = a.app.ResolveRollups(boardID, blocks, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetBlocks",
 		mlog.String("boardID", boardID),
 		mlog.String("parentID", parentID),
@@ -192,7 +197,7 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 	//   type: string
 	// - name: disable_notify
 	//   in: query
-	//   description: Disables notifications (for bulk inserting)
+	//   description: Disables notifications (for bulk inserting), the rules of the board still apply
 	//   required: false
 	//   type: bool
 	// - name: Body
@@ -527,7 +532,7 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//   type: string
 	// - name: disable_notify
 	//   in: query
-	//   description: Disables notifications (for bulk patching)
+	//   description: Disables notifications (for bulk patching), the rules of the board still apply
 	//   required: false
 	//   type: bool
 	// - name: Body
@@ -536,6 +541,11 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BlockPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: Update time of the block the patch was made from, the patch fails with a 409 if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -543,6 +553,10 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//     description: success
 	//   '404':
 	//     description: block not found
+	//   '409':
+	//     description: the block has been modified since the expected update time
+	//     schema:
+	//       $ref: '#/definitions/Block'
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -592,6 +606,18 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if patch == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("missing block patch"))
+		return
+	}
+
+	if patch.ExpectedUpdateAt == nil {
+		if patch.ExpectedUpdateAt, err = expectedUpdateAtFromRequest(r); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	auditRec := a.makeAuditRecord(r, "patchBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("boardID", boardID)
@@ -624,7 +650,7 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 	//   type: string
 	// - name: disable_notify
 	//   in: query
-	//   description: Disables notifications (for bulk patching)
+	//   description: Disables notifications (for bulk patching), the rules of the board still apply
 	//   required: false
 	//   type: bool
 	// - name: Body
@@ -638,6 +664,10 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 	// responses:
 	//   '200':
 	//     description: success
+	//   '409':
+	//     description: one of the blocks has been modified since the expected update time of its patch
+	//     schema:
+	//       $ref: '#/definitions/Block'
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -0,0 +1,467 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerBoardRulesRoutes(r *mux.Router) {
+	// Board automation rules APIs
+	r.HandleFunc("/boards/{boardID}/rules", a.sessionRequired(a.handleGetBoardRules)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/rules", a.sessionRequired(a.handleCreateBoardRule)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/rules/{ruleID}", a.sessionRequired(a.handlePatchBoardRule)).Methods("PATCH")
+	r.HandleFunc("/boards/{boardID}/rules/{ruleID}", a.sessionRequired(a.handleDeleteBoardRule)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/rules/{ruleID}/runs", a.sessionRequired(a.handleGetBoardRuleRuns)).Methods("GET")
+}
+
+// checkBoardRule returns an error if the rule doesn't exist or belongs to
+// another board.
+func (a *API) checkBoardRule(boardID, ruleID string) error {
+	rule, err := a.app.GetBoardRule(ruleID)
+	if err != nil {
+		return err
+	}
+	if rule.BoardID != boardID {
+		return model.NewErrNotFound(fmt.Sprintf("rule ID=%s on BoardID=%s", ruleID, boardID))
+	}
+	return nil
+}
+
+func (a *API) handleGetBoardRules(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/rules getBoardRules
+	//
+	// Returns the automation rules of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardRule"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board rules"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardRules", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	rules, err := a.app.GetBoardRules(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardRules",
+		mlog.String("boardID", boardID),
+		mlog.Int("rulesCount", len(rules)),
+	)
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("rulesCount", len(rules))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateBoardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/rules createBoardRule
+	//
+	// Creates an automation rule on a board. The properties used by the
+	// rule must exist on the board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the rule to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardRule"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/BoardRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board rules"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var rule *model.BoardRule
+	if err = json.Unmarshal(requestBody, &rule); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if rule == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid rule"))
+		return
+	}
+
+	// Stamp boardID from the URL
+	rule.BoardID = boardID
+
+	if err = rule.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createBoardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	newRule, err := a.app.CreateBoardRule(rule, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateBoardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", newRule.ID),
+	)
+
+	data, err := json.Marshal(newRule)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("ruleID", newRule.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchBoardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /boards/{boardID}/rules/{ruleID} patchBoardRule
+	//
+	// Partially updates a board automation rule
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Rule ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: rule patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardRulePatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/BoardRule'
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board rules"))
+		return
+	}
+
+	if err := a.checkBoardRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch *model.BoardRulePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if patch == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid rule patch"))
+		return
+	}
+
+	if err = patch.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchBoardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	rule, err := a.app.PatchBoardRule(ruleID, patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchBoardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+	)
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteBoardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/rules/{ruleID} deleteBoardRule
+	//
+	// Deletes a board automation rule and its runs
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Rule ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board rules"))
+		return
+	}
+
+	if err := a.checkBoardRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteBoardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	if err := a.app.DeleteBoardRule(ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteBoardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardRuleRuns(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/rules/{ruleID}/runs getBoardRuleRuns
+	//
+	// Returns the audit trail of a board automation rule, newest first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Rule ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of runs to return per page (default=100, max=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardRuleRun"
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+	query := r.URL.Query()
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board rules"))
+		return
+	}
+
+	if err := a.checkBoardRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 || perPage > model.BoardRuleRunsMaxPerPage {
+		perPage = model.BoardRuleRunsMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardRuleRuns", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	runs, err := a.app.GetBoardRuleRuns(ruleID, page, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardRuleRuns",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+		mlog.Int("runsCount", len(runs)),
+	)
+
+	data, err := json.Marshal(runs)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("runsCount", len(runs))
+	auditRec.Success()
+}
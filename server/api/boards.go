@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
@@ -61,6 +62,22 @@ func (a *API) authorizeBoardPatch(userID, boardID string, patch *model.BoardPatc
 		}
 	}
 
+	return a.authorizeRelationBoards(userID, patch.UpdatedCardProperties, boardID)
+}
+
+// authorizeRelationBoards checks that the user can view the boards the
+// relation properties of a board point to, as their rollups disclose the
+// content of the related cards. The boards being created or patched along
+// with the properties are skipped.
+func (a *API) authorizeRelationBoards(userID string, cardProperties []map[string]interface{}, ownBoardIDs ...string) error {
+	for _, relationBoardID := range model.RelationBoardIDs(cardProperties) {
+		if slices.Contains(ownBoardIDs, relationBoardID) {
+			continue
+		}
+		if !a.permissions.HasPermissionToBoard(userID, relationBoardID, model.PermissionViewBoard) {
+			return model.NewErrPermission("access denied to the board of a relation property")
+		}
+	}
 	return nil
 }
 
@@ -186,6 +203,11 @@ func (a *API) handleCreateBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = a.authorizeRelationBoards(userID, newBoard.CardProperties, newBoard.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	isGuest, err := a.userIsGuest(userID)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -345,6 +367,11 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BoardPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: Update time of the board the patch was made from, the patch fails with a 409 if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -354,6 +381,10 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//       $ref: '#/definitions/Board'
 	//   '404':
 	//     description: board not found
+	//   '409':
+	//     description: the board has been modified since the expected update time
+	//     schema:
+	//       $ref: '#/definitions/Board'
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -384,6 +415,13 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if patch.ExpectedUpdateAt == nil {
+		if patch.ExpectedUpdateAt, err = expectedUpdateAtFromRequest(r); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	if err = patch.IsValid(); err != nil {
 		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
 		return
@@ -110,11 +110,19 @@ func (a *API) handleCreateBoardsAndBlocks(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	newBoardIDs := make([]string, 0, len(newBab.Boards))
+	for _, board := range newBab.Boards {
+		newBoardIDs = append(newBoardIDs, board.ID)
+	}
 	for _, board := range newBab.Boards {
 		if err = a.authorizeBoardCreate(userID, board.TeamID, board.Type); err != nil {
 			a.errorResponse(w, r, err)
 			return
 		}
+		if err = a.authorizeRelationBoards(userID, board.CardProperties, newBoardIDs...); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
 	}
 
 	for _, block := range newBab.Blocks {
@@ -0,0 +1,397 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardLinksRoutes(r *mux.Router) {
+	// Card links APIs
+	r.HandleFunc("/cards/{cardID}/links", a.sessionRequired(a.handleGetCardLinks)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/links", a.sessionRequired(a.handleCreateCardLink)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/links/{linkID}", a.sessionRequired(a.handleDeleteCardLink)).Methods("DELETE")
+	r.HandleFunc("/cards/{cardID}/blockers", a.sessionRequired(a.handleGetCardBlockers)).Methods("GET")
+}
+
+// checkCardLink returns the link if it exists and one of its ends is the
+// card.
+func (a *API) checkCardLink(cardID, linkID string) (*model.CardLink, error) {
+	link, err := a.app.GetCardLink(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.SourceCardID != cardID && link.TargetCardID != cardID {
+		return nil, model.NewErrNotFound(fmt.Sprintf("link ID=%s on CardID=%s", linkID, cardID))
+	}
+	return link, nil
+}
+
+// otherBoardID returns the board of the card at the other end of the link.
+func otherBoardID(link *model.CardLink, cardID string) string {
+	if link.SourceCardID == cardID {
+		return link.TargetBoardID
+	}
+	return link.SourceBoardID
+}
+
+func (a *API) handleGetCardLinks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/links getCardLinks
+	//
+	// Returns the links starting from or pointing to a card. Links to cards
+	// of boards the user cannot view are omitted
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/CardLink"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card links"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardLinks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	links, err := a.app.GetCardLinks(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	visibleLinks := make([]*model.CardLink, 0, len(links))
+	for _, link := range links {
+		boardID := otherBoardID(link, card.ID)
+		if boardID == card.BoardID || a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+			visibleLinks = append(visibleLinks, link)
+		}
+	}
+
+	a.logger.Debug("GetCardLinks",
+		mlog.String("cardID", card.ID),
+		mlog.Int("linksCount", len(visibleLinks)),
+	)
+
+	data, err := json.Marshal(visibleLinks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("linksCount", len(visibleLinks))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateCardLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/links createCardLink
+	//
+	// Links a card to another card, which can be on another board. The link
+	// starts from the card of the path, and the type and targetCardId are
+	// read from the body
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the link to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardLink"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardLink'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var link *model.CardLink
+	if err = json.Unmarshal(requestBody, &link); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if link == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("missing card link"))
+		return
+	}
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to link card"))
+		return
+	}
+
+	target, err := a.app.GetCardByID(link.TargetCardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, target.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to linked card"))
+		return
+	}
+
+	link.SourceCardID = card.ID
+
+	auditRec := a.makeAuditRecord(r, "createCardLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("targetCardID", target.ID)
+	auditRec.AddMeta("linkType", link.Type)
+
+	link, err = a.app.CreateCardLink(link, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateCardLink",
+		mlog.String("linkID", link.ID),
+		mlog.String("sourceCardID", link.SourceCardID),
+		mlog.String("targetCardID", link.TargetCardID),
+	)
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("linkID", link.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCardLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /cards/{cardID}/links/{linkID} deleteCardLink
+	//
+	// Deletes a link of a card
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: linkID
+	//   in: path
+	//   description: Link ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: link not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	cardID := vars["cardID"]
+	linkID := vars["linkID"]
+	userID := getUserID(r)
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to unlink card"))
+		return
+	}
+
+	link, err := a.checkCardLink(card.ID, linkID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, otherBoardID(link, card.ID), model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to linked card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCardLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("linkID", linkID)
+
+	if err := a.app.DeleteCardLink(linkID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteCardLink",
+		mlog.String("cardID", card.ID),
+		mlog.String("linkID", linkID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+// visibleBlockers returns the blockers of a card that are on boards the
+// user can view.
+func (a *API) visibleBlockers(userID string, card *model.Card, blockers []*model.Card) []*model.Card {
+	visibleBlockers := make([]*model.Card, 0, len(blockers))
+	for _, blocker := range blockers {
+		if blocker.BoardID == card.BoardID || a.permissions.HasPermissionToBoard(userID, blocker.BoardID, model.PermissionViewBoard) {
+			visibleBlockers = append(visibleBlockers, blocker)
+		}
+	}
+	return visibleBlockers
+}
+
+func (a *API) handleGetCardBlockers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/blockers getCardBlockers
+	//
+	// Returns the cards blocking a card that are not done yet. Blockers on
+	// boards the user cannot view are omitted
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Card"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card blockers"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardBlockers", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	blockers, err := a.app.GetOpenBlockers(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	visibleBlockers := a.visibleBlockers(userID, card, blockers)
+
+	data, err := json.Marshal(visibleBlockers)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("blockersCount", len(visibleBlockers))
+	auditRec.Success()
+}
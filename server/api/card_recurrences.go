@@ -0,0 +1,244 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardRecurrencesRoutes(r *mux.Router) {
+	// Card recurrence APIs
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleGetCardRecurrence)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleSetCardRecurrence)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleDeleteCardRecurrence)).Methods("DELETE")
+}
+
+// getCardForRecurrence fetches the card of a recurrence request and checks
+// that the user has the permission on its board.
+func (a *API) getCardForRecurrence(userID, cardID string, permission *mmModel.Permission) (*model.Card, error) {
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("could not fetch card %s: %s", cardID, err))
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, permission) {
+		return nil, model.NewErrPermission("access denied to card recurrence")
+	}
+	return card, nil
+}
+
+func (a *API) handleGetCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/recurrence getCardRecurrence
+	//
+	// Returns the recurrence rule of a card
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardRecurrence"
+	//   '404':
+	//     description: the card doesn't recur
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.getCardForRecurrence(userID, cardID, model.PermissionViewBoard)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	recurrence, err := a.app.GetCardRecurrence(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(recurrence)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleSetCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/recurrence setCardRecurrence
+	//
+	// Sets the recurrence rule of a card. Each occurrence creates a copy of
+	// the card with its checkboxes unchecked and its dates shifted
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the recurrence of the card
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardRecurrence"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardRecurrence"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.getCardForRecurrence(userID, cardID, model.PermissionManageBoardCards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var recurrence model.CardRecurrence
+	if err = json.Unmarshal(requestBody, &recurrence); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// Stamp the card and board from the URL
+	recurrence.CardID = card.ID
+	recurrence.BoardID = card.BoardID
+
+	auditRec := a.makeAuditRecord(r, "setCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("frequency", recurrence.Rule.Frequency)
+	auditRec.AddMeta("enabled", recurrence.Enabled)
+
+	newRecurrence, err := a.app.SetCardRecurrence(&recurrence, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("SetCardRecurrence",
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+		mlog.Int("nextRunAt", newRecurrence.NextRunAt),
+	)
+
+	data, err := json.Marshal(newRecurrence)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /cards/{cardID}/recurrence deleteCardRecurrence
+	//
+	// Removes the recurrence rule of a card. The copies already created are kept
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: the card doesn't recur
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.getCardForRecurrence(userID, cardID, model.PermissionManageBoardCards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	if err := a.app.DeleteCardRecurrence(card.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteCardRecurrence",
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
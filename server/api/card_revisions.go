@@ -0,0 +1,333 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardRevisionsRoutes(r *mux.Router) {
+	// Card revisions APIs
+	r.HandleFunc("/cards/{cardID}/revisions", a.sessionRequired(a.handleGetCardRevisions)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/revisions/diff", a.sessionRequired(a.handleGetCardRevisionDiff)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/revisions/{updateAt}/restore", a.sessionRequired(a.handleRestoreCardRevision)).Methods("POST")
+}
+
+// getCardBoardID returns the board of a card, using its history so that
+// deleted cards are found too.
+func (a *API) getCardBoardID(cardID string) (string, error) {
+	block, err := a.app.GetLastBlockHistoryEntry(cardID)
+	if err != nil {
+		return "", err
+	}
+	if block == nil || block.Type != model.TypeCard {
+		return "", model.NewErrNotFound("card ID=" + cardID)
+	}
+	return block.BoardID, nil
+}
+
+func (a *API) handleGetCardRevisions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/revisions getCardRevisions
+	//
+	// Returns the revisions of a card and its content blocks, newest first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: before
+	//   in: query
+	//   description: Returns the revisions older than this update time, to fetch the next page
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of revisions to return (default=20, max=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/CardRevision"
+	//   '404':
+	//     description: card not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	query := r.URL.Query()
+	strBefore := query.Get("before")
+	strPerPage := query.Get("per_page")
+
+	boardID, err := a.getCardBoardID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card revisions"))
+		return
+	}
+
+	opts := model.QueryCardRevisionsOptions{PerPage: model.CardRevisionsDefaultPerPage}
+	if strBefore != "" {
+		opts.BeforeUpdateAt, err = strconv.ParseInt(strBefore, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `before` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+	if strPerPage != "" {
+		opts.PerPage, err = strconv.Atoi(strPerPage)
+		if err != nil || opts.PerPage <= 0 || opts.PerPage > model.CardRevisionsMaxPerPage {
+			message := fmt.Sprintf("invalid `per_page` parameter: must be between 1 and %d", model.CardRevisionsMaxPerPage)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardRevisions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+
+	revisions, err := a.app.GetCardRevisions(cardID, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetCardRevisions",
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", cardID),
+		mlog.Int("revisionsCount", len(revisions)),
+	)
+
+	data, err := json.Marshal(revisions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("revisionsCount", len(revisions))
+	auditRec.Success()
+}
+
+func (a *API) handleGetCardRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/revisions/diff getCardRevisionDiff
+	//
+	// Returns the changes of a card and its content blocks between two of its revisions
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: The update time of the older revision
+	//   required: true
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: The update time of the newer revision
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardRevisionDiff"
+	//   '404':
+	//     description: card or revision not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	query := r.URL.Query()
+	from, err := strconv.ParseInt(query.Get("from"), 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `from` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	to, err := strconv.ParseInt(query.Get("to"), 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `to` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	boardID, err := a.getCardBoardID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card revisions"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardRevisionDiff", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+	auditRec.AddMeta("from", from)
+	auditRec.AddMeta("to", to)
+
+	diff, err := a.app.GetCardRevisionDiff(cardID, from, to)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetCardRevisionDiff",
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", cardID),
+		mlog.Int("from", from),
+		mlog.Int("to", to),
+	)
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreCardRevision(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/revisions/{updateAt}/restore restoreCardRevision
+	//
+	// Restores a card and its content blocks to one of its revisions. The
+	// restore is saved as a new change
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: updateAt
+	//   in: path
+	//   description: The update time of the revision to restore
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   '404':
+	//     description: card or revision not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	cardID := vars["cardID"]
+
+	updateAt, err := strconv.ParseInt(vars["updateAt"], 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid revision: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	boardID, err := a.getCardBoardID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreCardRevision", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+	auditRec.AddMeta("updateAt", updateAt)
+
+	card, err := a.app.RestoreCardRevision(cardID, updateAt, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if err = a.app.ResolveCardRollups(card.BoardID, []*model.Card{card}, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreCardRevision",
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", cardID),
+		mlog.String("userID", userID),
+		mlog.Int("updateAt", updateAt),
+	)
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -26,6 +26,7 @@ func (a *API) registerCardsRoutes(r *mux.Router) {
 	// Cards APIs
 	r.HandleFunc("/boards/{boardID}/cards", a.sessionRequired(a.handleCreateCard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/cards", a.sessionRequired(a.handleGetCards)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/cards/query", a.sessionRequired(a.handleQueryCards)).Methods("POST")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handlePatchCard)).Methods("PATCH")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handleGetCard)).Methods("GET")
 }
@@ -52,7 +53,7 @@ func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 	//     "$ref": "#/definitions/Card"
 	// - name: disable_notify
 	//   in: query
-	//   description: Disables notifications (for bulk data inserting)
+	//   description: Disables notifications (for bulk data inserting), the rules of the board still apply
 	//   required: false
 	//   type: bool
 	// security:
@@ -210,6 +211,11 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = a.app.ResolveCardRollups(boardID, cards, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetCards",
 		mlog.String("boardID", boardID),
 		mlog.String("userID", userID),
@@ -230,6 +236,103 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleQueryCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/cards/query queryCards
+	//
+	// Fetches the cards of the specified board that match a filter, sorted
+	// by the given sort options and paginated with a cursor.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the query to run
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardQuery"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardQueryResult'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch cards"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var query model.CardQuery
+	if err = json.Unmarshal(requestBody, &query); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if query.PerPage == 0 {
+		query.PerPage, _ = strconv.Atoi(defaultPerPage)
+	}
+
+	if err = query.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "queryCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("per_page", query.PerPage)
+
+	result, err := a.app.QueryCards(boardID, &query)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if err = a.app.ResolveCardRollups(boardID, result.Cards, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("QueryCards",
+		mlog.String("boardID", boardID),
+		mlog.String("userID", userID),
+		mlog.Int("per_page", query.PerPage),
+		mlog.Int("count", len(result.Cards)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation PATCH /cards/{cardID}/cards patchCard
 	//
@@ -252,14 +355,23 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	//     "$ref": "#/definitions/CardPatch"
 	// - name: disable_notify
 	//   in: query
-	//   description: Disables notifications (for bulk data patching)
+	//   description: Disables notifications (for bulk data patching), the rules of the board still apply
 	//   required: false
 	//   type: bool
+	// - name: If-Match
+	//   in: header
+	//   description: Update time of the card the patch was made from, the patch fails with a 409 if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success. If the card is now done while some cards still block it, they are listed in openBlockers
+	//     schema:
+	//       $ref: '#/definitions/Card'
+	//   '409':
+	//     description: the card has been modified since the expected update time
 	//     schema:
 	//       $ref: '#/definitions/Card'
 	//   default:
@@ -302,6 +414,13 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if patch.ExpectedUpdateAt == nil {
+		if patch.ExpectedUpdateAt, err = expectedUpdateAtFromRequest(r); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	if err = patch.CheckValid(); err != nil {
 		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
 		return
@@ -325,6 +444,30 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		mlog.String("userID", userID),
 	)
 
+	// report cards completed while they are still blocked by other cards
+	if len(patch.UpdatedProperties) > 0 {
+		openBlockers, errBlockers := a.app.GetOpenBlockersIfDone(cardPatched)
+		if errBlockers != nil {
+			a.logger.Warn("Cannot check the blockers of a patched card",
+				mlog.String("cardID", cardPatched.ID),
+				mlog.Err(errBlockers),
+			)
+		} else if len(openBlockers) > 0 {
+			a.logger.Warn("Card marked as done while it has open blockers",
+				mlog.String("boardID", cardPatched.BoardID),
+				mlog.String("cardID", cardPatched.ID),
+				mlog.String("userID", userID),
+				mlog.Int("openBlockers", len(openBlockers)),
+			)
+			auditRec.AddMeta("openBlockers", len(openBlockers))
+
+			// the client warns the user about the blockers it can see
+			if visibleBlockers := a.visibleBlockers(userID, cardPatched, openBlockers); len(visibleBlockers) > 0 {
+				cardPatched.OpenBlockers = visibleBlockers
+			}
+		}
+	}
+
 	data, err := json.Marshal(cardPatched)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -383,6 +526,11 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", card.BoardID)
 	auditRec.AddMeta("cardID", card.ID)
 
+	if err = a.app.ResolveCardRollups(card.BoardID, []*model.Card{card}, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetCard",
 		mlog.String("boardID", card.BoardID),
 		mlog.String("cardID", card.ID),
@@ -0,0 +1,117 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerChangesRoutes(r *mux.Router) {
+	// Changes feed APIs
+	r.HandleFunc("/teams/{teamID}/changes", a.sessionRequired(a.handleGetChanges)).Methods("GET")
+}
+
+func (a *API) handleGetChanges(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/changes getChanges
+	//
+	// Returns the changes of the boards, blocks, board members and categories
+	// of a team that are visible to the user, oldest first, including the
+	// deletions. Pass the returned cursor as `since` to get the next changes.
+	// The removal of one of the user's board memberships is returned as a
+	// deleted member change. The feed stays a few seconds behind the
+	// current time.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: The cursor returned by a previous request. If empty, the changes start from the oldest one
+	//   required: false
+	//   type: string
+	// - name: per_page
+	//   in: query
+	//   description: Number of changes to return per page (default=100, max=1000)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ChangesResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	query := r.URL.Query()
+	since := query.Get("since")
+	strPerPage := query.Get("per_page")
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	perPage := model.ChangesDefaultPerPage
+	if strPerPage != "" {
+		var err error
+		perPage, err = strconv.Atoi(strPerPage)
+		if err != nil || perPage <= 0 || perPage > model.ChangesMaxPerPage {
+			message := fmt.Sprintf("invalid `per_page` parameter: must be between 1 and %d", model.ChangesMaxPerPage)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "getChanges", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("per_page", perPage)
+
+	response, err := a.app.GetChanges(teamID, userID, since, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetChanges",
+		mlog.String("teamID", teamID),
+		mlog.String("userID", userID),
+		mlog.Int("changesCount", len(response.Changes)),
+		mlog.Bool("hasNext", response.HasNext),
+	)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("changesCount", len(response.Changes))
+	auditRec.Success()
+}
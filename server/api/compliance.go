@@ -26,6 +26,7 @@ func (a *API) registerComplianceRoutes(r *mux.Router) {
 	r.HandleFunc("/admin/boards", a.sessionRequired(a.handleGetBoardsForCompliance)).Methods("GET")
 	r.HandleFunc("/admin/boards_history", a.sessionRequired(a.handleGetBoardsComplianceHistory)).Methods("GET")
 	r.HandleFunc("/admin/blocks_history", a.sessionRequired(a.handleGetBlocksComplianceHistory)).Methods("GET")
+	r.HandleFunc("/admin/time_entries", a.sessionRequired(a.handleGetTimeEntriesForCompliance)).Methods("GET")
 }
 
 func (a *API) handleGetBoardsForCompliance(w http.ResponseWriter, r *http.Request) {
@@ -448,3 +449,163 @@ func (a *API) handleGetBlocksComplianceHistory(w http.ResponseWriter, r *http.Re
 
 	jsonBytesResponse(w, http.StatusOK, data)
 }
+
+func (a *API) handleGetTimeEntriesForCompliance(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/time_entries getTimeEntriesForCompliance
+	//
+	// Returns time entries for a specific team, specific board, or all teams and boards.
+	//
+	// Requires a license that includes Compliance feature. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: modified_since
+	//   in: query
+	//   description: Filters for time entries modified since timestamp; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// - name: include_deleted
+	//   in: query
+	//   description: When true then deleted time entries are included. Default=false
+	//   required: false
+	//   type: boolean
+	// - name: team_id
+	//   in: query
+	//   description: Team ID. If empty then time entries across all teams are included
+	//   required: false
+	//   type: string
+	// - name: board_id
+	//   in: query
+	//   description: Board ID. If empty then time entries for all boards are included
+	//   required: false
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of time entries to return per page (default=60)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: object
+	//       items:
+	//         "$ref": "#/definitions/TimeEntriesComplianceResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	query := r.URL.Query()
+	strModifiedSince := query.Get("modified_since") // required, everything else optional
+	includeDeleted := query.Get("include_deleted") == "true"
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+	teamID := query.Get("team_id")
+	boardID := query.Get("board_id")
+
+	if strModifiedSince == "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("`modified_since` parameter required"))
+		return
+	}
+
+	// check for permission `manage_system`
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied Compliance Export getTimeEntries"))
+		return
+	}
+
+	// check for valid license feature: compliance
+	license := a.app.GetLicense()
+	if license == nil || license.Features == nil || license.Features.Compliance == nil || !(*license.Features.Compliance) {
+		a.errorResponse(w, r, model.NewErrNotImplemented("insufficient license Compliance Export getTimeEntries"))
+		return
+	}
+
+	// check for valid team if specified
+	if teamID != "" {
+		_, err := a.app.GetTeam(teamID)
+		if err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest("invalid team id: "+teamID))
+			return
+		}
+	}
+
+	// check for valid board if specified
+	if boardID != "" {
+		_, err := a.app.GetBoard(boardID)
+		if err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest("invalid board id: "+boardID))
+			return
+		}
+	}
+
+	if strPage == "" {
+		strPage = complianceDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = complianceDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	modifiedSince, err := strconv.ParseInt(strModifiedSince, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `modified_since` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	opts := model.QueryTimeEntriesComplianceOptions{
+		ModifiedSince:  modifiedSince,
+		IncludeDeleted: includeDeleted,
+		TeamID:         teamID,
+		BoardID:        boardID,
+		Page:           page,
+		PerPage:        perPage,
+	}
+
+	entries, more, err := a.app.GetTimeEntriesForCompliance(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetTimeEntriesForCompliance",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+		mlog.Int("entriesCount", len(entries)),
+		mlog.Bool("hasNext", more),
+	)
+
+	response := model.TimeEntriesComplianceResponse{
+		HasNext: more,
+		Results: entries,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
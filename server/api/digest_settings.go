@@ -0,0 +1,137 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerDigestSettingsRoutes(r *mux.Router) {
+	// Digest settings APIs
+	r.HandleFunc("/users/me/digest", a.sessionRequired(a.handleGetDigestSettings)).Methods("GET")
+	r.HandleFunc("/users/me/digest", a.sessionRequired(a.handlePostDigestSettings)).Methods("POST")
+}
+
+func (a *API) handleGetDigestSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/digest getDigestSettings
+	//
+	// Returns the digest settings of the current user
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DigestSettings"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "getDigestSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	settings, err := a.app.GetDigestSettings(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePostDigestSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /users/me/digest postDigestSettings
+	//
+	// Sets the digest settings of the current user. The changes of the
+	// blocks the user subscribed to in digest mode are sent in a single
+	// direct message, daily or weekly at the chosen hour of the user's
+	// timezone
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: digest settings
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/DigestSettings"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DigestSettings"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var settings model.DigestSettings
+	if err = json.Unmarshal(requestBody, &settings); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// Stamp the current user
+	settings.UserID = userID
+
+	auditRec := a.makeAuditRecord(r, "postDigestSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("frequency", settings.Frequency)
+
+	newSettings, err := a.app.SetDigestSettings(&settings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("POST digest settings",
+		mlog.String("userID", userID),
+		mlog.String("frequency", string(newSettings.Frequency)),
+	)
+
+	data, err := json.Marshal(newSettings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerDueDateRemindersRoutes(r *mux.Router) {
+	// Due date reminder settings APIs
+	r.HandleFunc("/boards/{boardID}/reminders", a.sessionRequired(a.handleGetDueDateReminderSettings)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/reminders", a.sessionRequired(a.handlePostDueDateReminderSettings)).Methods("POST")
+}
+
+func (a *API) handleGetDueDateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/reminders getDueDateReminderSettings
+	//
+	// Returns the due date reminder settings of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DueDateReminderSettings"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getDueDateReminderSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	settings, err := a.app.GetDueDateReminderSettings(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePostDueDateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/reminders postDueDateReminderSettings
+	//
+	// Sets the due date reminder settings of a board. The assignees and
+	// subscribers of a card are sent a direct message at each offset from
+	// the due dates of the card
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: due date reminder settings
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/DueDateReminderSettings"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DueDateReminderSettings"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board properties"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var settings model.DueDateReminderSettings
+	if err = json.Unmarshal(requestBody, &settings); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// Stamp the board from the URL
+	settings.BoardID = boardID
+
+	auditRec := a.makeAuditRecord(r, "postDueDateReminderSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("enabled", settings.Enabled)
+
+	newSettings, err := a.app.SetDueDateReminderSettings(&settings, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("POST due date reminder settings",
+		mlog.String("boardID", boardID),
+		mlog.Bool("enabled", newSettings.Enabled),
+	)
+
+	data, err := json.Marshal(newSettings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func (a *API) registerExportJobsRoutes(r *mux.Router) {
+	// Export job APIs
+	r.HandleFunc("/teams/{teamID}/archive/export/jobs", a.sessionRequired(a.handleCreateExportJob)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/export/jobs", a.sessionRequired(a.handleGetExportJobs)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/export/jobs/{jobID}", a.sessionRequired(a.handleGetExportJob)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/export/jobs/{jobID}/download", a.sessionRequired(a.handleDownloadExportJob)).Methods("GET")
+}
+
+// getExportJobForTeam fetches an export job and checks that it belongs to
+// the team of the request.
+func (a *API) getExportJobForTeam(teamID, jobID string) (*model.ExportJob, error) {
+	job, err := a.app.GetExportJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.TeamID != teamID {
+		return nil, model.NewErrNotFound("export job ID=" + jobID)
+	}
+	return job, nil
+}
+
+func (a *API) handleCreateExportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/export/jobs createExportJob
+	//
+	// Starts an asynchronous export of the boards of a team that the user
+	// can view to an archive. The archive can be downloaded by the user once
+	// the job succeeded
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Id of team
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the export job to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ExportJobCreate"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ExportJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to export team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var create model.ExportJobCreate
+	if err = json.Unmarshal(requestBody, &create); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createExportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("changedSince", create.ChangedSince)
+
+	job, err := a.app.CreateExportJob(teamID, &create, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("jobID", job.ID)
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetExportJobs(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/export/jobs getExportJobs
+	//
+	// Returns the most recent export jobs of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Id of team
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/ExportJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to export team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getExportJobs", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+
+	jobs, err := a.app.GetExportJobsForTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("jobCount", len(jobs))
+	auditRec.Success()
+}
+
+func (a *API) handleGetExportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/export/jobs/{jobID} getExportJob
+	//
+	// Returns the status and progress of an export job
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Id of team
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Id of the export job
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ExportJob"
+	//   '404':
+	//     description: export job not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	jobID := vars["jobID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to export team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getExportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("jobID", jobID)
+
+	job, err := a.getExportJobForTeam(teamID, jobID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDownloadExportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/export/jobs/{jobID}/download downloadExportJob
+	//
+	// Downloads the archive of a successful export job, which can be
+	// imported with the archive import API. Only the creator of the job and
+	// the system admins can download it
+	//
+	// ---
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Id of team
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Id of the export job
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     content:
+	//       application-octet-stream:
+	//         type: string
+	//         format: binary
+	//   '404':
+	//     description: export job not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	jobID := vars["jobID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to export team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "downloadExportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("jobID", jobID)
+
+	job, err := a.getExportJobForTeam(teamID, jobID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// the archive holds the boards its creator can view
+	if job.CreatedBy != userID && !a.permissions.HasPermissionTo(userID, mmModel.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to export job"))
+		return
+	}
+
+	reader, err := a.app.GetExportJobArchive(job)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	defer reader.Close()
+
+	completeAt := time.UnixMilli(job.CompleteAt)
+	filename := fmt.Sprintf("archive-%s%s", completeAt.Format("2006-01-02"), archiveExtension)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	// ServeContent supports range requests, so that large archives can be
+	// downloaded in parts
+	http.ServeContent(w, r, filename, completeAt, reader)
+
+	auditRec.Success()
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerImportSessionsRoutes(r *mux.Router) {
+	// Import session APIs
+	r.HandleFunc("/teams/{teamID}/archive/import/sessions", a.sessionRequired(a.handleCreateImportSession)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/import/sessions/{sessionID}", a.sessionRequired(a.handleGetImportSession)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/import/sessions/{sessionID}", a.sessionRequired(a.handleDeleteImportSession)).Methods("DELETE")
+	r.HandleFunc("/teams/{teamID}/archive/import/sessions/{sessionID}/confirm", a.sessionRequired(a.handleConfirmImportSession)).Methods("POST")
+}
+
+// checkImportPermission checks that the user can import boards in the team.
+func (a *API) checkImportPermission(userID, teamID string) error {
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		return model.NewErrPermission("access denied to create board")
+	}
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		return err
+	}
+	if isGuest {
+		return model.NewErrPermission("access denied to create board")
+	}
+	return nil
+}
+
+// getImportSessionForUser fetches an import session and checks that it was
+// created by the user for the team of the request.
+func (a *API) getImportSessionForUser(userID, teamID, sessionID string) (*model.ImportSession, error) {
+	session, err := a.app.GetImportSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.TeamID != teamID || session.CreatedBy != userID {
+		return nil, model.NewErrNotFound("import session ID=" + sessionID)
+	}
+	return session, nil
+}
+
+func (a *API) importSessionOptions(userID, teamID string) model.ImportArchiveOptions {
+	return model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: userID,
+		BoardValidator: func(board *model.Board) error {
+			return a.authorizeBoardCreate(userID, teamID, board.Type)
+		},
+	}
+}
+
+func (a *API) handleCreateImportSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import/sessions createImportSession
+	//
+	// Uploads an archive to import and returns a preview of its boards, card
+	// counts, users, files and validation errors. Nothing is imported until
+	// the session is confirmed
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: archive file to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportSession"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if err := a.checkImportPermission(userID, teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if a.app.GetConfig().MaxFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, a.app.GetConfig().MaxFileSize)
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			a.errorResponse(w, r, model.ErrRequestEntityTooLarge)
+			return
+		}
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	auditRec := a.makeAuditRecord(r, "createImportSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	session, err := a.app.CreateImportSession(file, a.importSessionOptions(userID, teamID))
+	if err != nil {
+		a.logger.Debug("Error creating import session",
+			mlog.String("team_id", teamID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("sessionID", session.ID)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetImportSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/import/sessions/{sessionID} getImportSession
+	//
+	// Returns an import session, with the result of the import of each
+	// board once confirmed
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: sessionID
+	//   in: path
+	//   description: Import session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportSession"
+	//   '404':
+	//     description: import session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	sessionID := vars["sessionID"]
+	userID := getUserID(r)
+
+	if err := a.checkImportPermission(userID, teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getImportSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("sessionID", sessionID)
+
+	session, err := a.getImportSessionForUser(userID, teamID, sessionID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleConfirmImportSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/import/sessions/{sessionID}/confirm confirmImportSession
+	//
+	// Imports the boards of an import session, remapping the users and
+	// skipping or renaming the boards as requested. Each board is imported
+	// in its own transaction
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: sessionID
+	//   in: path
+	//   description: Import session ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the user map and board resolutions of the import
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ImportSessionConfirm"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportSession"
+	//   '404':
+	//     description: import session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	sessionID := vars["sessionID"]
+	userID := getUserID(r)
+
+	if err := a.checkImportPermission(userID, teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var confirm model.ImportSessionConfirm
+	if err = json.Unmarshal(requestBody, &confirm); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "confirmImportSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("sessionID", sessionID)
+
+	session, err := a.getImportSessionForUser(userID, teamID, sessionID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	session, err = a.app.ConfirmImportSession(session, &confirm, a.importSessionOptions(userID, teamID))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteImportSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/archive/import/sessions/{sessionID} deleteImportSession
+	//
+	// Cancels an import session, removing its archive
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: sessionID
+	//   in: path
+	//   description: Import session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: import session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	sessionID := vars["sessionID"]
+	userID := getUserID(r)
+
+	if err := a.checkImportPermission(userID, teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteImportSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("sessionID", sessionID)
+
+	session, err := a.getImportSessionForUser(userID, teamID, sessionID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if err := a.app.DeleteImportSession(session); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
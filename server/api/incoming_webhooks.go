@@ -0,0 +1,296 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// incoming webhook requests are limited per webhook
+	incomingWebhookRequestsPerSecond = 1
+	incomingWebhookBurst             = 30
+
+	incomingWebhookMaxBodySize = 1 << 20
+)
+
+func (a *API) registerIncomingWebhooksRoutes(r *mux.Router) {
+	// Incoming webhook settings APIs
+	r.HandleFunc("/boards/{boardID}/incoming-webhook", a.sessionRequired(a.handleGetIncomingWebhook)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/incoming-webhook", a.sessionRequired(a.handlePostIncomingWebhook)).Methods("POST")
+}
+
+func (a *API) registerIncomingWebhookHooksRoutes(r *mux.Router) {
+	// Incoming webhook APIs, authenticated by the token
+	r.HandleFunc("/boards/{boardID}/{token}", a.handleIncomingWebhookCard).Methods("POST")
+}
+
+func (a *API) handleGetIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/incoming-webhook getIncomingWebhook
+	//
+	// Returns the incoming webhook settings of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/IncomingWebhook"
+	//   '404':
+	//     description: incoming webhook not configured
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	webhook, err := a.app.GetIncomingWebhook(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("GET incoming webhook",
+		mlog.String("boardID", boardID),
+		mlog.Bool("enabled", webhook.Enabled),
+	)
+	auditRec.AddMeta("enabled", webhook.Enabled)
+	auditRec.Success()
+}
+
+func (a *API) handlePostIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/incoming-webhook postIncomingWebhook
+	//
+	// Sets the incoming webhook settings of a board. If no token is
+	// provided a new one is generated
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: incoming webhook settings
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/IncomingWebhook"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/IncomingWebhook"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var webhook model.IncomingWebhook
+	if err = json.Unmarshal(requestBody, &webhook); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// Stamp boardID from the URL and the user that sets up the webhook,
+	// on behalf of whom the cards are created
+	webhook.ID = boardID
+	webhook.ModifiedBy = userID
+
+	auditRec := a.makeAuditRecord(r, "postIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("enabled", webhook.Enabled)
+
+	newWebhook, err := a.app.UpsertIncomingWebhook(webhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(newWebhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("POST incoming webhook",
+		mlog.String("boardID", boardID),
+		mlog.Bool("enabled", newWebhook.Enabled),
+	)
+	auditRec.Success()
+}
+
+func (a *API) handleIncomingWebhookCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /hooks/boards/{boardID}/{token} incomingWebhookCard
+	//
+	// Creates a card on a board, or updates one if the payload has a card
+	// ID. Properties can be referenced by name and select options by value.
+	// Requests are authenticated by the board's incoming webhook token and
+	// rate limited per board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: token
+	//   in: path
+	//   description: Incoming webhook token
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the card to create or update
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/IncomingWebhookCard"
+	// responses:
+	//   '200':
+	//     description: the card was updated
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   '201':
+	//     description: the card was created
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   '401':
+	//     description: invalid token
+	//   '429':
+	//     description: too many requests
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	token := vars["token"]
+
+	auditRec := a.makeAuditRecord(r, "incomingWebhookCard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	webhook, err := a.app.GetIncomingWebhookForToken(boardID, token)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// the limit applies once the token is checked, so that requests with
+	// invalid tokens can't use up the requests of the board's integrations
+	if !a.incomingWebhookLimiter.Allow(webhook.ID) {
+		a.errorResponse(w, r, model.ErrTooManyRequests)
+		return
+	}
+	// the request has no session, so the audit record is attributed to
+	// the user that set up the webhook
+	auditRec.UserID = webhook.ModifiedBy
+
+	requestBody, err := io.ReadAll(io.LimitReader(r.Body, incomingWebhookMaxBodySize+1))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if len(requestBody) > incomingWebhookMaxBodySize {
+		a.errorResponse(w, r, model.ErrRequestEntityTooLarge)
+		return
+	}
+
+	var payload model.IncomingWebhookCard
+	if err = json.Unmarshal(requestBody, &payload); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	card, created, err := a.app.ApplyIncomingWebhookCard(boardID, &payload, webhook.ModifiedBy)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("IncomingWebhookCard",
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", card.ID),
+		mlog.Bool("created", created),
+	)
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	jsonBytesResponse(w, status, data)
+
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("created", created)
+	auditRec.Success()
+}
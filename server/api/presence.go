@@ -0,0 +1,80 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerPresenceRoutes(r *mux.Router) {
+	// Presence APIs
+	r.HandleFunc("/boards/{boardID}/presence", a.sessionRequired(a.handleGetBoardPresence)).Methods("GET")
+}
+
+func (a *API) handleGetBoardPresence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/presence getBoardPresence
+	//
+	// Returns the users currently viewing or editing the board or its cards
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Presence"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board presence"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardPresence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	presences := a.app.GetBoardPresence(boardID)
+
+	a.logger.Debug("GetBoardPresence",
+		mlog.String("boardID", boardID),
+		mlog.Int("presenceCount", len(presences)),
+	)
+
+	data, err := json.Marshal(presences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("presenceCount", len(presences))
+	auditRec.Success()
+}
@@ -21,6 +21,7 @@ func (a *API) registerSubscriptionsRoutes(r *mux.Router) {
 	r.HandleFunc("/subscriptions", a.sessionRequired(a.handleCreateSubscription)).Methods("POST")
 	r.HandleFunc("/subscriptions/{blockID}/{subscriberID}", a.sessionRequired(a.handleDeleteSubscription)).Methods("DELETE")
 	r.HandleFunc("/subscriptions/{subscriberID}", a.sessionRequired(a.handleGetSubscriptions)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/watchers", a.sessionRequired(a.handleGetCardWatchers)).Methods("GET")
 }
 
 // subscriptions
@@ -28,7 +29,8 @@ func (a *API) registerSubscriptionsRoutes(r *mux.Router) {
 func (a *API) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /subscriptions createSubscription
 	//
-	// Creates a subscription to a block for a user. The user will receive change notifications for the block.
+	// Creates a subscription to a block for a user or a channel. The user or channel will receive change notifications for the block.
+	// Subscribing a channel requires permission to post in it and to administer the board.
 	//
 	// ---
 	// produces:
@@ -71,10 +73,18 @@ func (a *API) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// User can only create subscriptions for themselves (for now)
-	if userID != sub.SubscriberID {
-		a.errorResponse(w, r, model.NewErrBadRequest("userID and subscriberID mismatch"))
-		return
+	// Users can create subscriptions for themselves, or for the channels they can post to
+	switch sub.SubscriberType {
+	case model.SubTypeChannel:
+		if !a.permissions.HasPermissionToChannel(userID, sub.SubscriberID, model.PermissionCreatePost) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to channel"))
+			return
+		}
+	default:
+		if userID != sub.SubscriberID {
+			a.errorResponse(w, r, model.NewErrBadRequest("userID and subscriberID mismatch"))
+			return
+		}
 	}
 
 	// check for valid block and permissions to view its board
@@ -85,14 +95,28 @@ func (a *API) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.permissions.HasPermissionToBoard(sub.SubscriberID, block.BoardID, model.PermissionViewBoard) {
+	if !a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionViewBoard) {
 		a.errorResponse(w, r, model.NewErrPermission("access denied to block"))
 		return
 	}
 
+	if sub.SubscriberType == model.SubTypeChannel {
+		// the members of the channel may not have access to the board, so
+		// only the board admins can share its changes with a channel
+		if !a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionManageBoardRoles) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to subscribe a channel to the board"))
+			return
+		}
+		if err = a.app.ValidateSubscriptionChannel(block.BoardID, sub.SubscriberID); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	auditRec := a.makeAuditRecord(r, "createSubscription", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("subscriber_id", sub.SubscriberID)
+	auditRec.AddMeta("subscriber_type", sub.SubscriberType)
 	auditRec.AddMeta("block_id", sub.BlockID)
 
 	subNew, err := a.app.CreateSubscription(&sub)
@@ -119,7 +143,7 @@ func (a *API) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation DELETE /subscriptions/{blockID}/{subscriberID} deleteSubscription
 	//
-	// Deletes a subscription a user has for a a block. The user will no longer receive change notifications for the block.
+	// Deletes a subscription a user or channel has for a a block. The user or channel will no longer receive change notifications for the block.
 	//
 	// ---
 	// produces:
@@ -155,10 +179,18 @@ func (a *API) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("block_id", blockID)
 	auditRec.AddMeta("subscriber_id", subscriberID)
 
-	// User can only delete subscriptions for themselves
+	// Users can delete subscriptions for themselves, or for the channels they can post to
 	if userID != subscriberID {
-		a.errorResponse(w, r, model.NewErrPermission("access denied"))
-		return
+		sub, err := a.app.GetSubscription(blockID, subscriberID)
+		if err != nil && !model.IsErrNotFound(err) {
+			a.errorResponse(w, r, err)
+			return
+		}
+		if sub == nil || sub.SubscriberType != model.SubTypeChannel ||
+			!a.permissions.HasPermissionToChannel(userID, subscriberID, model.PermissionCreatePost) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied"))
+			return
+		}
 	}
 
 	if _, err := a.app.DeleteSubscription(blockID, subscriberID); err != nil {
@@ -178,7 +210,7 @@ func (a *API) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleGetSubscriptions(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /subscriptions/{subscriberID} getSubscriptions
 	//
-	// Gets subscriptions for a user.
+	// Gets subscriptions for a user, or for a channel the user can post to.
 	//
 	// ---
 	// produces:
@@ -210,10 +242,14 @@ func (a *API) handleGetSubscriptions(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("subscriber_id", subscriberID)
 
-	// User can only get subscriptions for themselves (for now)
+	// Users can get subscriptions for themselves, or for the channels they can post to
+	isChannel := false
 	if userID != subscriberID {
-		a.errorResponse(w, r, model.NewErrPermission("access denied"))
-		return
+		if !a.permissions.HasPermissionToChannel(userID, subscriberID, model.PermissionCreatePost) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied"))
+			return
+		}
+		isChannel = true
 	}
 
 	subs, err := a.app.GetSubscriptions(subscriberID)
@@ -222,6 +258,16 @@ func (a *API) handleGetSubscriptions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isChannel {
+		channelSubs := make([]*model.Subscription, 0, len(subs))
+		for _, sub := range subs {
+			if sub.SubscriberType == model.SubTypeChannel {
+				channelSubs = append(channelSubs, sub)
+			}
+		}
+		subs = channelSubs
+	}
+
 	a.logger.Debug("GET subscriptions",
 		mlog.String("subscriberID", subscriberID),
 		mlog.Int("count", len(subs)),
@@ -237,3 +283,72 @@ func (a *API) handleGetSubscriptions(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("subscription_count", len(subs))
 	auditRec.Success()
 }
+
+func (a *API) handleGetCardWatchers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/watchers getCardWatchers
+	//
+	// Gets the users and channels subscribed to a card, with their subscription filters.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Subscriber"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// only the users that manage the cards of the board can see who watches them
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to card watchers"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardWatchers", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	watchers, err := a.app.GetCardWatchers(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GET card watchers",
+		mlog.String("cardID", card.ID),
+		mlog.Int("count", len(watchers)),
+	)
+
+	json, err := json.Marshal(watchers)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	jsonBytesResponse(w, http.StatusOK, json)
+
+	auditRec.AddMeta("watcher_count", len(watchers))
+	auditRec.Success()
+}
@@ -0,0 +1,722 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerTimeEntriesRoutes(r *mux.Router) {
+	// Time tracking APIs
+	r.HandleFunc("/cards/{cardID}/time_entries", a.sessionRequired(a.handleGetTimeEntries)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/time_entries", a.sessionRequired(a.handleCreateTimeEntry)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/timer/start", a.sessionRequired(a.handleStartTimer)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/timer/stop", a.sessionRequired(a.handleStopTimer)).Methods("POST")
+	r.HandleFunc("/time_entries/{entryID}", a.sessionRequired(a.handlePatchTimeEntry)).Methods("PATCH")
+	r.HandleFunc("/time_entries/{entryID}", a.sessionRequired(a.handleDeleteTimeEntry)).Methods("DELETE")
+	r.HandleFunc("/users/me/timer", a.sessionRequired(a.handleGetRunningTimer)).Methods("GET")
+	r.HandleFunc("/users/me/time_summary", a.sessionRequired(a.handleGetUserTimeSummary)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/time_summary", a.sessionRequired(a.handleGetBoardTimeSummary)).Methods("GET")
+}
+
+// getTimeEntryForUser fetches a time entry and checks that it belongs to
+// the user, who can still manage the cards of its board.
+func (a *API) getTimeEntryForUser(userID, entryID string) (*model.TimeEntry, error) {
+	entry, err := a.app.GetTimeEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, model.NewErrNotFound("time entry ID=" + entryID)
+	}
+	if !a.permissions.HasPermissionToBoard(userID, entry.BoardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrPermission("access denied to time entry")
+	}
+	return entry, nil
+}
+
+// parseTimeRange reads the `from` and `to` query parameters, in
+// milliseconds since the current epoch.
+func parseTimeRange(r *http.Request) (int64, int64, error) {
+	query := r.URL.Query()
+	from, err := strconv.ParseInt(query.Get("from"), 10, 64)
+	if err != nil {
+		return 0, 0, model.NewErrBadRequest(fmt.Sprintf("invalid `from` parameter: %s", err))
+	}
+	to, err := strconv.ParseInt(query.Get("to"), 10, 64)
+	if err != nil {
+		return 0, 0, model.NewErrBadRequest(fmt.Sprintf("invalid `to` parameter: %s", err))
+	}
+	return from, to, nil
+}
+
+func (a *API) handleGetTimeEntries(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/time_entries getTimeEntries
+	//
+	// Returns the time entries of a card, the most recent first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/TimeEntry"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch time entries"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTimeEntries", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	entries, err := a.app.GetTimeEntriesForCard(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetTimeEntries",
+		mlog.String("cardID", card.ID),
+		mlog.Int("entriesCount", len(entries)),
+	)
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("entriesCount", len(entries))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/time_entries createTimeEntry
+	//
+	// Adds time spent by the user on a card. The startAt, duration and note
+	// are read from the body
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the time entry to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TimeEntry"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeEntry'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var entry *model.TimeEntry
+	if err = json.Unmarshal(requestBody, &entry); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if entry == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("missing time entry"))
+		return
+	}
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to track time on card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createTimeEntry", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	entry, err = a.app.CreateTimeEntry(entry, card.ID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateTimeEntry",
+		mlog.String("timeEntryID", entry.ID),
+		mlog.String("cardID", entry.CardID),
+	)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("timeEntryID", entry.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleStartTimer(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/timer/start startTimer
+	//
+	// Starts a timer for the user on a card. The timer the user was running
+	// on another card, if any, is stopped. An optional note is read from the
+	// body
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the note of the time entry
+	//   required: false
+	//   schema:
+	//     "$ref": "#/definitions/TimeEntryPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeEntry'
+	//   '409':
+	//     description: another timer of the user was started at the same time
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.TimeEntryPatch
+	if len(requestBody) != 0 {
+		if err = json.Unmarshal(requestBody, &patch); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	var note string
+	if patch.Note != nil {
+		note = *patch.Note
+	}
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to track time on card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "startTimer", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	entry, err := a.app.StartTimer(card.ID, userID, note)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("StartTimer",
+		mlog.String("timeEntryID", entry.ID),
+		mlog.String("cardID", entry.CardID),
+	)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("timeEntryID", entry.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleStopTimer(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/timer/stop stopTimer
+	//
+	// Stops the timer the user is running on a card
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeEntry'
+	//   '404':
+	//     description: no timer running on the card
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to track time on card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "stopTimer", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	entry, err := a.app.StopTimer(card.ID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("StopTimer",
+		mlog.String("timeEntryID", entry.ID),
+		mlog.String("cardID", entry.CardID),
+	)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("timeEntryID", entry.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetRunningTimer(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/timer getRunningTimer
+	//
+	// Returns the time entry of the timer the user is running
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeEntry'
+	//   '404':
+	//     description: no timer running
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "getRunningTimer", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	entry, err := a.app.GetRunningTimer(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("timeEntryID", entry.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchTimeEntry(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /time_entries/{entryID} patchTimeEntry
+	//
+	// Updates a time entry of the user
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: entryID
+	//   in: path
+	//   description: Time entry ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the time entry patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TimeEntryPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeEntry'
+	//   '404':
+	//     description: time entry not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	entryID := mux.Vars(r)["entryID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch *model.TimeEntryPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if patch == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("missing time entry patch"))
+		return
+	}
+
+	entry, err := a.getTimeEntryForUser(userID, entryID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchTimeEntry", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", entry.BoardID)
+	auditRec.AddMeta("cardID", entry.CardID)
+	auditRec.AddMeta("timeEntryID", entry.ID)
+
+	entry, err = a.app.PatchTimeEntry(entry, patch)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /time_entries/{entryID} deleteTimeEntry
+	//
+	// Deletes a time entry of the user
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: entryID
+	//   in: path
+	//   description: Time entry ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: time entry not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	entryID := mux.Vars(r)["entryID"]
+
+	entry, err := a.getTimeEntryForUser(userID, entryID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteTimeEntry", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", entry.BoardID)
+	auditRec.AddMeta("cardID", entry.CardID)
+	auditRec.AddMeta("timeEntryID", entry.ID)
+
+	if err := a.app.DeleteTimeEntry(entry.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteTimeEntry",
+		mlog.String("cardID", entry.CardID),
+		mlog.String("timeEntryID", entry.ID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardTimeSummary(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/time_summary getBoardTimeSummary
+	//
+	// Returns the time spent on the cards of a board over a date range, by
+	// card and by user. Only stopped timers are counted
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: Start of the range; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: End of the range, excluded; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeSummary'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board time summary"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardTimeSummary", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("from", from)
+	auditRec.AddMeta("to", to)
+
+	summary, err := a.app.GetBoardTimeSummary(boardID, from, to)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetUserTimeSummary(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/time_summary getUserTimeSummary
+	//
+	// Returns the time spent by the user over a date range, by board and by
+	// card. Only stopped timers are counted
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: from
+	//   in: query
+	//   description: Start of the range; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: End of the range, excluded; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/TimeSummary'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getUserTimeSummary", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("from", from)
+	auditRec.AddMeta("to", to)
+
+	summary, err := a.app.GetUserTimeSummary(userID, from, to)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerViewsRoutes(r *mux.Router) {
+	// Views APIs
+	r.HandleFunc("/boards/{boardID}/views/{viewID}/cards", a.sessionRequired(a.handleGetViewCards)).Methods("GET")
+}
+
+func (a *API) handleGetViewCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/views/{viewID}/cards getViewCards
+	//
+	// Returns the cards of a view, filtered, sorted and grouped as the view displays them.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: viewID
+	//   in: path
+	//   description: View ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/ViewCards'
+	//   '404':
+	//     description: view not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	viewID := vars["viewID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to view cards"))
+		return
+	}
+
+	block, err := a.app.GetBlockByID(viewID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if block.BoardID != boardID || block.Type != model.TypeView {
+		message := fmt.Sprintf("view ID=%s on BoardID=%s", viewID, boardID)
+		a.errorResponse(w, r, model.NewErrNotFound(message))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getViewCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("viewID", viewID)
+
+	viewCards, err := a.app.EvaluateView(viewID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetViewCards",
+		mlog.String("boardID", boardID),
+		mlog.String("viewID", viewID),
+		mlog.String("userID", userID),
+		mlog.Int("count", len(viewCards.Cards)),
+	)
+
+	data, err := json.Marshal(viewCards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
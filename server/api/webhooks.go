@@ -0,0 +1,468 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerWebhooksRoutes(r *mux.Router) {
+	// Board webhooks APIs
+	r.HandleFunc("/boards/{boardID}/webhooks", a.sessionRequired(a.handleGetBoardWebhooks)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/webhooks", a.sessionRequired(a.handleCreateBoardWebhook)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/webhooks/{webhookID}", a.sessionRequired(a.handlePatchBoardWebhook)).Methods("PATCH")
+	r.HandleFunc("/boards/{boardID}/webhooks/{webhookID}", a.sessionRequired(a.handleDeleteBoardWebhook)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/webhooks/{webhookID}/deliveries", a.sessionRequired(a.handleGetWebhookDeliveries)).Methods("GET")
+}
+
+// checkBoardWebhook returns an error if the webhook doesn't exist or
+// belongs to another board.
+func (a *API) checkBoardWebhook(boardID, webhookID string) error {
+	webhook, err := a.app.GetBoardWebhook(webhookID)
+	if err != nil {
+		return err
+	}
+	if webhook.BoardID != boardID {
+		return model.NewErrNotFound(fmt.Sprintf("webhook ID=%s on BoardID=%s", webhookID, boardID))
+	}
+	return nil
+}
+
+func (a *API) handleGetBoardWebhooks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/webhooks getBoardWebhooks
+	//
+	// Returns the webhooks of a board. Their secrets are not included
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardWebhook"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardWebhooks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	webhooks, err := a.app.GetBoardWebhooks(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardWebhooks",
+		mlog.String("boardID", boardID),
+		mlog.Int("webhooksCount", len(webhooks)),
+	)
+
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("webhooksCount", len(webhooks))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateBoardWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/webhooks createBoardWebhook
+	//
+	// Registers a webhook on a board. If no secret is provided one is
+	// generated. The response is the only one that includes the secret
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the webhook to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardWebhook"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/BoardWebhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var webhook *model.BoardWebhook
+	if err = json.Unmarshal(requestBody, &webhook); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if webhook == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid webhook"))
+		return
+	}
+
+	// Stamp boardID from the URL
+	webhook.BoardID = boardID
+
+	if err = webhook.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createBoardWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	newWebhook, err := a.app.CreateBoardWebhook(webhook, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateBoardWebhook",
+		mlog.String("boardID", boardID),
+		mlog.String("webhookID", newWebhook.ID),
+	)
+
+	data, err := json.Marshal(newWebhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("webhookID", newWebhook.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchBoardWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /boards/{boardID}/webhooks/{webhookID} patchBoardWebhook
+	//
+	// Partially updates a board webhook. The response includes the secret
+	// only if the patch changes it
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: webhook patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardWebhookPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/BoardWebhook'
+	//   '404':
+	//     description: webhook not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	webhookID := vars["webhookID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	if err := a.checkBoardWebhook(boardID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch *model.BoardWebhookPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if patch == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid webhook patch"))
+		return
+	}
+
+	if err = patch.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchBoardWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	webhook, err := a.app.PatchBoardWebhook(webhookID, patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchBoardWebhook",
+		mlog.String("boardID", boardID),
+		mlog.String("webhookID", webhookID),
+	)
+
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteBoardWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/webhooks/{webhookID} deleteBoardWebhook
+	//
+	// Deletes a board webhook and its delivery log
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: webhook not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	webhookID := vars["webhookID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	if err := a.checkBoardWebhook(boardID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteBoardWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	if err := a.app.DeleteBoardWebhook(webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteBoardWebhook",
+		mlog.String("boardID", boardID),
+		mlog.String("webhookID", webhookID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/webhooks/{webhookID}/deliveries getWebhookDeliveries
+	//
+	// Returns the delivery log of a board webhook, newest first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of deliveries to return per page (default=100, max=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/WebhookDelivery"
+	//   '404':
+	//     description: webhook not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	webhookID := vars["webhookID"]
+	query := r.URL.Query()
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardWebhooks) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	if err := a.checkBoardWebhook(boardID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 || perPage > model.WebhookDeliveriesMaxPerPage {
+		perPage = model.WebhookDeliveriesMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "getWebhookDeliveries", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	deliveries, err := a.app.GetWebhookDeliveries(webhookID, page, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetWebhookDeliveries",
+		mlog.String("boardID", boardID),
+		mlog.String("webhookID", webhookID),
+		mlog.Int("deliveriesCount", len(deliveries)),
+	)
+
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("deliveriesCount", len(deliveries))
+	auditRec.Success()
+}
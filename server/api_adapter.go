@@ -113,6 +113,25 @@ func (a *pluginAPIAdapter) GetUsersFromProfiles(options *mm_model.UserGetOptions
 	return users, normalizeAppErr(appErr)
 }
 
+func (a *pluginAPIAdapter) GetUserStatusesByIds(userIDs []string) ([]*mm_model.Status, error) {
+	statuses, appErr := a.api.GetUserStatusesByIds(userIDs)
+	return statuses, normalizeAppErr(appErr)
+}
+
+//
+// Group service.
+//
+
+func (a *pluginAPIAdapter) GetGroupByName(name string) (*mm_model.Group, error) {
+	group, appErr := a.api.GetGroupByName(name)
+	return group, normalizeAppErr(appErr)
+}
+
+func (a *pluginAPIAdapter) GetGroupMemberUsers(groupID string, page, perPage int) ([]*mm_model.User, error) {
+	users, appErr := a.api.GetGroupMemberUsers(groupID, page, perPage)
+	return users, normalizeAppErr(appErr)
+}
+
 //
 // Team service.
 //
@@ -13,12 +13,14 @@ import (
 	"github.com/mattermost/mattermost-plugin-boards/server/services/metrics"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/rules"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/webhook"
 	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 	"github.com/mattermost/mattermost-plugin-boards/server/ws"
 
 	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
 )
@@ -44,6 +46,10 @@ type fileBackend interface {
 	RemoveFile(path string) error
 }
 
+// MutexFactory creates cluster wide mutexes, used to run jobs in a single
+// server of a cluster.
+type MutexFactory func(name string) (*cluster.Mutex, error)
+
 type Services struct {
 	Auth             *auth.Auth
 	Store            store.Store
@@ -51,10 +57,12 @@ type Services struct {
 	Webhook          *webhook.Client
 	Metrics          *metrics.Metrics
 	Notifications    *notify.Service
+	Rules            *rules.Engine
 	Logger           mlog.LoggerIFace
 	Permissions      permissions.PermissionsService
 	SkipTemplateInit bool
 	ServicesAPI      servicesAPI
+	NewMutexFn       MutexFactory
 }
 
 type App struct {
@@ -66,10 +74,12 @@ type App struct {
 	webhook             *webhook.Client
 	metrics             *metrics.Metrics
 	notifications       *notify.Service
+	rules               *rules.Engine
 	logger              mlog.LoggerIFace
 	permissions         permissions.PermissionsService
 	blockChangeNotifier *utils.CallbackQueue
 	servicesAPI         servicesAPI
+	newMutexFn          MutexFactory
 
 	cardLimitMux sync.RWMutex
 	cardLimit    int
@@ -93,10 +103,12 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		webhook:             services.Webhook,
 		metrics:             services.Metrics,
 		notifications:       services.Notifications,
+		rules:               services.Rules,
 		logger:              services.Logger,
 		permissions:         services.Permissions,
 		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger),
 		servicesAPI:         services.ServicesAPI,
+		newMutexFn:          services.NewMutexFn,
 	}
 	app.initialize(services.SkipTemplateInit)
 	return app
@@ -61,6 +61,14 @@ func (a *App) DuplicateBlock(boardID string, blockID string, userID string, asTe
 		for _, block := range blocks {
 			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
 		}
+
+		// cards created from a template can trigger rules
+		if !asTemplate && len(blocks) > 0 && blocks[0].Type == model.TypeCard {
+			if change := a.ruleChangeForBlock(blocks[0], nil, userID); change != nil {
+				change.TemplateID = blockID
+				a.runBoardRules(change)
+			}
+		}
 		return nil
 	})
 
@@ -92,6 +100,11 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 		if err != nil {
 			return nil, err
 		}
+		if oldBlock.Type == model.TypeCard {
+			if err = validateCardPropertiesForBoard(board, blockPatch.UpdatedFields); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	err = a.store.PatchBlock(blockID, blockPatch, modifiedByID)
@@ -115,6 +128,9 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 		if !disableNotify {
 			a.notifyBlockChanged(notify.Update, block, oldBlock, modifiedByID)
 		}
+
+		// the rules of the board apply whether the change is notified or not
+		a.runBoardRules(a.ruleChangeForBlock(block, oldBlock, modifiedByID))
 		return nil
 	})
 	return block, nil
@@ -164,6 +180,11 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 		if err := a.validateFileRefsInFields(board.TeamID, block.BoardID, block.ID, patch.UpdatedFields); err != nil {
 			return err
 		}
+		if block.Type == model.TypeCard {
+			if err := validateCardPropertiesForBoard(board, patch.UpdatedFields); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := a.store.PatchBlocks(blockPatches, modifiedByID); err != nil {
@@ -172,6 +193,7 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.metrics.IncrementBlocksPatched(len(oldBlocks))
+		ruleChanges := []*model.RuleChange{}
 		for _, blockID := range blockPatches.BlockIDs {
 			newBlock, err := a.store.GetBlock(blockID)
 			if err != nil {
@@ -182,7 +204,9 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 			if !disableNotify {
 				a.notifyBlockChanged(notify.Update, newBlock, blockByID[blockID], modifiedByID)
 			}
+			ruleChanges = append(ruleChanges, a.ruleChangeForBlock(newBlock, blockByID[blockID], modifiedByID))
 		}
+		a.runBoardRules(ruleChanges...)
 		return nil
 	})
 	return nil
@@ -218,6 +242,11 @@ func (a *App) InsertBlockAndNotify(block *model.Block, modifiedByID string, disa
 	if err := a.validateFileRefsInFields(board.TeamID, block.BoardID, block.ID, block.Fields); err != nil {
 		return err
 	}
+	if block.Type == model.TypeCard {
+		if err := validateCardPropertiesForBoard(board, block.Fields); err != nil {
+			return err
+		}
+	}
 
 	err := a.store.InsertBlock(block, modifiedByID)
 	if err == nil {
@@ -228,6 +257,7 @@ func (a *App) InsertBlockAndNotify(block *model.Block, modifiedByID string, disa
 			if !disableNotify {
 				a.notifyBlockChanged(notify.Add, block, nil, modifiedByID)
 			}
+			a.runBoardRules(a.ruleChangeForBlock(block, nil, modifiedByID))
 			return nil
 		})
 	}
@@ -262,6 +292,11 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 		if err := a.validateFileRefsInFields(board.TeamID, boardID, block.ID, block.Fields); err != nil {
 			return nil, err
 		}
+		if block.Type == model.TypeCard {
+			if err := validateCardPropertiesForBoard(board, block.Fields); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	needsNotify := make([]*model.Block, 0, len(blocks))
@@ -320,13 +355,16 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 	}
 
 	a.blockChangeNotifier.Enqueue(func() error {
+		ruleChanges := []*model.RuleChange{}
 		for _, b := range needsNotify {
 			block := b
 			a.webhook.NotifyUpdate(block)
 			if !disableNotify {
 				a.notifyBlockChanged(notify.Add, block, nil, modifiedByID)
 			}
+			ruleChanges = append(ruleChanges, a.ruleChangeForBlock(block, nil, modifiedByID))
 		}
+		a.runBoardRules(ruleChanges...)
 		return nil
 	})
 
@@ -0,0 +1,275 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// maxRuleChainDepth is the number of rules that can be triggered by the
+	// changes of other rules, starting from a user change.
+	maxRuleChainDepth = 5
+)
+
+var (
+	errRuleAlreadyApplied = errors.New("rule already applied to the card by this chain of rules")
+	errRuleChainTooDeep   = errors.New("too many rules triggered by this chain of rules")
+)
+
+func (a *App) CreateBoardRule(rule *model.BoardRule, userID string) (*model.BoardRule, error) {
+	if err := a.isValidBoardRuleForBoard(rule); err != nil {
+		return nil, err
+	}
+
+	rule.ID = utils.NewID(utils.IDTypeNone)
+	rule.CreatedBy = userID
+	rule.ModifiedBy = userID
+
+	return a.store.InsertBoardRule(rule)
+}
+
+func (a *App) GetBoardRule(ruleID string) (*model.BoardRule, error) {
+	return a.store.GetBoardRule(ruleID)
+}
+
+func (a *App) GetBoardRules(boardID string) ([]*model.BoardRule, error) {
+	return a.store.GetBoardRulesForBoard(boardID)
+}
+
+func (a *App) PatchBoardRule(ruleID string, patch *model.BoardRulePatch, userID string) (*model.BoardRule, error) {
+	if err := patch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	rule, err := a.store.GetBoardRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.isValidBoardRuleForBoard(patch.Patch(rule)); err != nil {
+		return nil, err
+	}
+
+	return a.store.PatchBoardRule(ruleID, patch, userID)
+}
+
+func (a *App) DeleteBoardRule(ruleID string) error {
+	return a.store.DeleteBoardRule(ruleID)
+}
+
+func (a *App) GetBoardRuleRuns(ruleID string, page, perPage int) ([]*model.BoardRuleRun, error) {
+	return a.store.GetBoardRuleRuns(ruleID, page, perPage)
+}
+
+func (a *App) isValidBoardRuleForBoard(rule *model.BoardRule) error {
+	if err := rule.IsValid(); err != nil {
+		return err
+	}
+
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+	return rule.IsValidForSchema(schema)
+}
+
+// ruleChangeForBlock returns the change that can trigger rules for a block
+// that was inserted, if oldBlock is nil, or updated. It returns nil if the
+// change cannot trigger rules.
+func (a *App) ruleChangeForBlock(block *model.Block, oldBlock *model.Block, userID string) *model.RuleChange {
+	if a.rules == nil {
+		return nil
+	}
+
+	switch block.Type {
+	case model.TypeCard:
+		if isTemplate, _ := block.Fields["isTemplate"].(bool); isTemplate {
+			return nil
+		}
+		if oldBlock == nil {
+			return &model.RuleChange{Type: model.RuleTriggerCardCreated, Card: block, ActorID: userID}
+		}
+		return &model.RuleChange{Type: model.RuleTriggerPropertyChanged, Card: block, OldCard: oldBlock, ActorID: userID}
+	case model.TypeCheckbox:
+		if oldBlock == nil {
+			return nil
+		}
+		checked, _ := block.Fields["value"].(bool)
+		wasChecked, _ := oldBlock.Fields["value"].(bool)
+		if !checked || wasChecked {
+			return nil
+		}
+
+		card, err := a.store.GetBlock(block.ParentID)
+		if err != nil || card.Type != model.TypeCard {
+			return nil
+		}
+		return &model.RuleChange{Type: model.RuleTriggerCheckboxesChecked, Card: card, ActorID: userID}
+	}
+	return nil
+}
+
+// runBoardRules applies the rules triggered by a set of changes. Checking
+// several checkboxes of a card at once only triggers its rules once.
+func (a *App) runBoardRules(changes ...*model.RuleChange) {
+	if a.rules == nil {
+		return
+	}
+
+	checkedCards := map[string]bool{}
+	for _, change := range changes {
+		if change == nil {
+			continue
+		}
+		if change.Type == model.RuleTriggerCheckboxesChecked {
+			if checkedCards[change.Card.ID] {
+				continue
+			}
+			checkedCards[change.Card.ID] = true
+		}
+		a.runBoardRulesChain(change, 0, map[string]bool{})
+	}
+}
+
+// runBoardRulesChain applies the rules triggered by a change, and then the
+// rules triggered by the changes they make. Each rule is applied once per
+// chain, so rules that trigger each other cannot loop.
+func (a *App) runBoardRulesChain(change *model.RuleChange, depth int, applied map[string]bool) {
+	rules, err := a.rules.MatchingRules(change)
+	if err != nil {
+		a.logger.Error("Cannot get the rules triggered by a card change",
+			mlog.String("card_id", change.Card.ID),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	for _, rule := range rules {
+		run := &model.BoardRuleRun{
+			ID:       utils.NewID(utils.IDTypeNone),
+			RuleID:   rule.ID,
+			BoardID:  rule.BoardID,
+			CardID:   change.Card.ID,
+			ActorID:  change.ActorID,
+			Depth:    depth,
+			CreateAt: utils.GetMillis(),
+		}
+
+		var oldCard, newCard *model.Block
+		switch {
+		case applied[rule.ID]:
+			run.Status = model.BoardRuleRunSkipped
+			run.Error = errRuleAlreadyApplied.Error()
+		case depth >= maxRuleChainDepth:
+			run.Status = model.BoardRuleRunSkipped
+			run.Error = errRuleChainTooDeep.Error()
+		default:
+			applied[rule.ID] = true
+			oldCard, newCard, run.Changes, err = a.applyBoardRule(rule, change.Card.ID, change.ActorID)
+			if err != nil {
+				run.Status = model.BoardRuleRunFailed
+				run.Error = err.Error()
+			} else {
+				run.Status = model.BoardRuleRunSuccess
+			}
+		}
+
+		if run.Status != model.BoardRuleRunSuccess {
+			a.logger.Debug("Board rule not applied",
+				mlog.String("rule_id", rule.ID),
+				mlog.String("card_id", change.Card.ID),
+				mlog.String("status", string(run.Status)),
+				mlog.String("reason", run.Error),
+			)
+		}
+		a.rules.RecordRun(run)
+
+		if newCard != nil {
+			a.runBoardRulesChain(&model.RuleChange{
+				Type:    model.RuleTriggerPropertyChanged,
+				Card:    newCard,
+				OldCard: oldCard,
+				ActorID: change.ActorID,
+			}, depth+1, applied)
+		}
+	}
+}
+
+// applyBoardRule applies the actions of a rule to the current version of
+// a card as the Boards bot, and returns the card before and after the
+// change together with the properties that changed. The returned cards
+// are nil if the actions didn't change the card.
+func (a *App) applyBoardRule(rule *model.BoardRule, cardID string, actorID string) (*model.Block, *model.Block, map[string]any, error) {
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// the properties used by the rule may have been deleted since it was saved
+	if err = rule.IsValidForSchema(schema); err != nil {
+		return nil, nil, nil, err
+	}
+
+	properties, _ := card.Fields["properties"].(map[string]any)
+	updated := properties
+	now := utils.GetMillis()
+	for i := range rule.Actions {
+		updated = rule.Actions[i].Apply(updated, schema, actorID, now)
+	}
+
+	changes := map[string]any{}
+	for propID, value := range updated {
+		if !reflect.DeepEqual(properties[propID], value) {
+			changes[propID] = value
+		}
+	}
+	for propID := range properties {
+		if _, ok := updated[propID]; !ok {
+			changes[propID] = nil
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil, changes, nil
+	}
+
+	botID, err := a.rules.BotID()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	patch := &model.BlockPatch{UpdatedFields: map[string]any{"properties": changes}}
+	if err = a.store.PatchBlock(card.ID, patch, botID); err != nil {
+		return nil, nil, nil, err
+	}
+	a.metrics.IncrementBlocksPatched(1)
+
+	newCard, err := a.store.GetBlock(card.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	a.wsAdapter.BroadcastBlockChange(board.TeamID, newCard)
+	a.webhook.NotifyUpdate(newCard)
+	a.notifyBlockChanged(notify.Update, newCard, card, botID)
+
+	return card, newCard, changes, nil
+}
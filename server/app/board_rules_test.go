@@ -0,0 +1,241 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/rules"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func testRulesBoard() *model.Board {
+	return &model.Board{
+		ID:     "board-id",
+		TeamID: "team-id",
+		CardProperties: []map[string]any{
+			{"id": "status-id", "name": "Status", "type": "select"},
+			{"id": "priority-id", "name": "Priority", "type": "select"},
+			{"id": "completed-id", "name": "Completed", "type": "date"},
+			{"id": "owner-id", "name": "Owner", "type": "person"},
+		},
+	}
+}
+
+func TestCreateBoardRule(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("valid rule", func(t *testing.T) {
+		rule := &model.BoardRule{
+			BoardID: "board-id",
+			Enabled: true,
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerPropertyChanged, PropertyID: "status-id", Value: "done"},
+			Actions: []model.RuleAction{{Type: model.RuleActionSetDateNow, PropertyID: "completed-id"}},
+		}
+
+		th.Store.EXPECT().GetBoard("board-id").Return(testRulesBoard(), nil)
+		th.Store.EXPECT().InsertBoardRule(rule).DoAndReturn(func(r *model.BoardRule) (*model.BoardRule, error) {
+			require.NotEmpty(t, r.ID)
+			require.Equal(t, "user-id", r.CreatedBy)
+			return r, nil
+		})
+
+		_, err := th.App.CreateBoardRule(rule, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("not a date property", func(t *testing.T) {
+		rule := &model.BoardRule{
+			BoardID: "board-id",
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerCardCreated},
+			Actions: []model.RuleAction{{Type: model.RuleActionSetDateNow, PropertyID: "status-id"}},
+		}
+
+		th.Store.EXPECT().GetBoard("board-id").Return(testRulesBoard(), nil)
+
+		_, err := th.App.CreateBoardRule(rule, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestRunBoardRules(t *testing.T) {
+	// setupCard makes the store keep the card patched by the rules, and
+	// returns the recorded runs.
+	setupCard := func(t *testing.T, card *model.Block, boardRules []*model.BoardRule) (*TestHelper, *[]*model.BoardRuleRun, func()) {
+		th, tearDown := SetupTestHelper(t)
+		th.App.rules = rules.New(th.Store, mlog.CreateConsoleTestLogger(t))
+
+		runs := []*model.BoardRuleRun{}
+		th.Store.EXPECT().GetBoardRulesForBoard("board-id").Return(boardRules, nil).AnyTimes()
+		th.Store.EXPECT().GetBoard("board-id").Return(testRulesBoard(), nil).AnyTimes()
+		th.Store.EXPECT().GetBoardsBotID().Return("bot-id", nil).AnyTimes()
+		th.Store.EXPECT().GetMembersForBoard("board-id").Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetBlock(card.ID).DoAndReturn(func(blockID string) (*model.Block, error) {
+			properties := map[string]any{}
+			for propID, value := range card.Fields["properties"].(map[string]any) {
+				properties[propID] = value
+			}
+			return &model.Block{ID: card.ID, BoardID: card.BoardID, Type: card.Type, Fields: map[string]any{"properties": properties}}, nil
+		}).AnyTimes()
+		th.Store.EXPECT().PatchBlock(card.ID, gomock.Any(), "bot-id").DoAndReturn(func(blockID string, patch *model.BlockPatch, userID string) error {
+			patch.Patch(card)
+			return nil
+		}).AnyTimes()
+		th.Store.EXPECT().InsertBoardRuleRun(gomock.Any()).DoAndReturn(func(run *model.BoardRuleRun) error {
+			runs = append(runs, run)
+			return nil
+		}).AnyTimes()
+		return th, &runs, tearDown
+	}
+
+	t.Run("status done completes and unassigns the card", func(t *testing.T) {
+		card := &model.Block{
+			ID:      "card-id-1",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{"status-id": "done", "owner-id": "user-id"}},
+		}
+		oldCard := &model.Block{
+			ID:      "card-id-1",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{"status-id": "doing", "owner-id": "user-id"}},
+		}
+		th, runs, tearDown := setupCard(t, card, []*model.BoardRule{{
+			ID:      "rule-id",
+			BoardID: "board-id",
+			Enabled: true,
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerPropertyChanged, PropertyID: "status-id", Value: "done"},
+			Actions: []model.RuleAction{
+				{Type: model.RuleActionSetDateNow, PropertyID: "completed-id"},
+				{Type: model.RuleActionClearProperty, PropertyID: "owner-id"},
+			},
+		}})
+		defer tearDown()
+
+		th.App.runBoardRules(th.App.ruleChangeForBlock(card, oldCard, "user-id"))
+
+		properties := card.Fields["properties"].(map[string]any)
+		require.Equal(t, "done", properties["status-id"])
+		require.NotEmpty(t, properties["completed-id"])
+		require.NotContains(t, properties, "owner-id")
+
+		require.Len(t, *runs, 1)
+		run := (*runs)[0]
+		require.Equal(t, model.BoardRuleRunSuccess, run.Status)
+		require.Equal(t, "user-id", run.ActorID)
+		require.Contains(t, run.Changes, "completed-id")
+		require.Contains(t, run.Changes, "owner-id")
+	})
+
+	t.Run("rules that trigger each other don't loop", func(t *testing.T) {
+		card := &model.Block{
+			ID:      "card-id-2",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{"status-id": "done"}},
+		}
+		oldCard := &model.Block{
+			ID:      "card-id-2",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{}},
+		}
+		th, runs, tearDown := setupCard(t, card, []*model.BoardRule{
+			{
+				ID:      "status-rule",
+				BoardID: "board-id",
+				Enabled: true,
+				Trigger: model.RuleTrigger{Type: model.RuleTriggerPropertyChanged, PropertyID: "status-id"},
+				Actions: []model.RuleAction{{Type: model.RuleActionSetProperty, PropertyID: "priority-id", Value: "high"}},
+			},
+			{
+				ID:      "priority-rule",
+				BoardID: "board-id",
+				Enabled: true,
+				Trigger: model.RuleTrigger{Type: model.RuleTriggerPropertyChanged, PropertyID: "priority-id"},
+				Actions: []model.RuleAction{{Type: model.RuleActionSetProperty, PropertyID: "status-id", Value: "todo"}},
+			},
+		})
+		defer tearDown()
+
+		th.App.runBoardRules(th.App.ruleChangeForBlock(card, oldCard, "user-id"))
+
+		properties := card.Fields["properties"].(map[string]any)
+		require.Equal(t, "todo", properties["status-id"])
+		require.Equal(t, "high", properties["priority-id"])
+
+		require.Len(t, *runs, 3)
+		require.Equal(t, "status-rule", (*runs)[0].RuleID)
+		require.Equal(t, model.BoardRuleRunSuccess, (*runs)[0].Status)
+		require.Equal(t, "priority-rule", (*runs)[1].RuleID)
+		require.Equal(t, model.BoardRuleRunSuccess, (*runs)[1].Status)
+		require.Equal(t, 1, (*runs)[1].Depth)
+		require.Equal(t, "status-rule", (*runs)[2].RuleID)
+		require.Equal(t, model.BoardRuleRunSkipped, (*runs)[2].Status)
+	})
+
+	t.Run("card created from a template is assigned to its creator", func(t *testing.T) {
+		card := &model.Block{
+			ID:      "card-id-3",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{}},
+		}
+		th, runs, tearDown := setupCard(t, card, []*model.BoardRule{{
+			ID:      "template-rule",
+			BoardID: "board-id",
+			Enabled: true,
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerCardCreated, TemplateID: "template-id"},
+			Actions: []model.RuleAction{{Type: model.RuleActionAssignActor, PropertyID: "owner-id"}},
+		}})
+		defer tearDown()
+
+		change := th.App.ruleChangeForBlock(card, nil, "creator-id")
+		th.App.runBoardRules(change)
+		require.Empty(t, *runs)
+
+		change.TemplateID = "template-id"
+		th.App.runBoardRules(change)
+		require.Equal(t, "creator-id", card.Fields["properties"].(map[string]any)["owner-id"])
+		require.Len(t, *runs, 1)
+	})
+	t.Run("rules apply to changes that aren't notified", func(t *testing.T) {
+		card := &model.Block{
+			ID:      "card-id-4",
+			BoardID: "board-id",
+			Type:    model.TypeCard,
+			Fields:  map[string]any{"properties": map[string]any{"status-id": "doing"}},
+		}
+		th, runs, tearDown := setupCard(t, card, []*model.BoardRule{{
+			ID:      "rule-id",
+			BoardID: "board-id",
+			Enabled: true,
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerPropertyChanged, PropertyID: "status-id", Value: "done"},
+			Actions: []model.RuleAction{{Type: model.RuleActionSetDateNow, PropertyID: "completed-id"}},
+		}})
+		defer tearDown()
+
+		patch := &model.BlockPatch{UpdatedFields: map[string]any{"properties": map[string]any{"status-id": "done"}}}
+		th.Store.EXPECT().PatchBlock(card.ID, patch, "user-id").DoAndReturn(func(blockID string, patch *model.BlockPatch, userID string) error {
+			patch.Patch(card)
+			return nil
+		})
+
+		_, err := th.App.PatchBlockAndNotify(card.ID, patch, "user-id", true)
+		require.NoError(t, err)
+
+		// waits for the queued changes to be processed
+		th.App.Shutdown()
+
+		require.NotEmpty(t, card.Fields["properties"].(map[string]any)["completed-id"])
+		require.Len(t, *runs, 1)
+		require.Equal(t, model.BoardRuleRunSuccess, (*runs)[0].Status)
+	})
+}
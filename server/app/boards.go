@@ -607,12 +607,27 @@ func (a *App) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, e
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastMemberChange(board.TeamID, member.BoardID, member)
+		a.notifyMemberChanged(notify.Add, board, newMember)
 		return nil
 	})
 
 	return newMember, nil
 }
 
+func (a *App) notifyMemberChanged(action notify.Action, board *model.Board, member *model.BoardMember) {
+	if a.notifications == nil {
+		return
+	}
+
+	evt := notify.MemberChangeEvent{
+		Action: action,
+		TeamID: board.TeamID,
+		Board:  board,
+		Member: member,
+	}
+	a.notifications.MemberChanged(evt)
+}
+
 func (a *App) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember, error) {
 	board, bErr := a.store.GetBoard(member.BoardID)
 	if model.IsErrNotFound(bErr) {
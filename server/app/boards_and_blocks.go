@@ -11,25 +11,8 @@ import (
 )
 
 func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, addMember bool) (*model.BoardsAndBlocks, error) {
-	// Reject blocks that reference files not belonging to their board.
-	boardsByID := make(map[string]*model.Board, len(bab.Boards))
-	for _, board := range bab.Boards {
-		boardsByID[board.ID] = board
-	}
-	for _, block := range bab.Blocks {
-		board, ok := boardsByID[block.BoardID]
-		if !ok {
-			continue
-		}
-		if err := a.validateFileRefsInFields(board.TeamID, block.BoardID, block.ID, block.Fields); err != nil {
-			return nil, err
-		}
-	}
-
-	for _, board := range bab.Boards {
-		if err := a.validateBoardChannelReadAccess(userID, board.ChannelID); err != nil {
-			return nil, err
-		}
+	if err := a.validateBoardsAndBlocksForCreate(bab, userID); err != nil {
+		return nil, err
 	}
 
 	var newBab *model.BoardsAndBlocks
@@ -79,6 +62,77 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 	return newBab, nil
 }
 
+// createBoardsAndBlocksWithMembers creates the boards and blocks along with
+// their members in a single transaction.
+func (a *App) createBoardsAndBlocksWithMembers(bab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) (*model.BoardsAndBlocks, error) {
+	if err := a.validateBoardsAndBlocksForCreate(bab, userID); err != nil {
+		return nil, err
+	}
+
+	newBab, newMembers, err := a.store.CreateBoardsAndBlocksWithMembers(bab, members, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// all new boards should belong to the same team
+	teamID := newBab.Boards[0].TeamID
+
+	for _, board := range newBab.Boards {
+		a.wsAdapter.BroadcastBoardChange(teamID, board)
+	}
+
+	for _, block := range newBab.Blocks {
+		b := block
+		a.wsAdapter.BroadcastBlockChange(teamID, b)
+		a.metrics.IncrementBlocksInserted(1)
+		a.webhook.NotifyUpdate(b)
+		a.notifyBlockChanged(notify.Add, b, nil, userID)
+	}
+
+	boardsByID := make(map[string]*model.Board, len(newBab.Boards))
+	for _, board := range newBab.Boards {
+		boardsByID[board.ID] = board
+	}
+	for _, member := range newMembers {
+		a.wsAdapter.BroadcastMemberChange(teamID, member.BoardID, member)
+		board, ok := boardsByID[member.BoardID]
+		if !ok || board.IsTemplate {
+			continue
+		}
+		if err := a.addBoardsToDefaultCategory(member.UserID, teamID, []*model.Board{board}); err != nil {
+			return nil, err
+		}
+	}
+
+	return newBab, nil
+}
+
+// validateBoardsAndBlocksForCreate rejects the blocks that reference files
+// not belonging to their board, and the boards linked to channels the user
+// cannot read.
+func (a *App) validateBoardsAndBlocksForCreate(bab *model.BoardsAndBlocks, userID string) error {
+	boardsByID := make(map[string]*model.Board, len(bab.Boards))
+	for _, board := range bab.Boards {
+		boardsByID[board.ID] = board
+	}
+	for _, block := range bab.Blocks {
+		board, ok := boardsByID[block.BoardID]
+		if !ok {
+			continue
+		}
+		if err := a.validateFileRefsInFields(board.TeamID, block.BoardID, block.ID, block.Fields); err != nil {
+			return err
+		}
+	}
+
+	for _, board := range bab.Boards {
+		if err := a.validateBoardChannelReadAccess(userID, board.ChannelID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	for _, patch := range pbab.BlockPatches {
 		if patch == nil {
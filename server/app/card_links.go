@@ -0,0 +1,157 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// CreateCardLink links two cards, which can be on different boards. The
+// board ids of the link are taken from the cards.
+func (a *App) CreateCardLink(link *model.CardLink, userID string) (*model.CardLink, error) {
+	source, err := a.getCardBlock(link.SourceCardID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := a.getCardBlock(link.TargetCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	link.ID = utils.NewID(utils.IDTypeNone)
+	link.SourceBoardID = source.BoardID
+	link.TargetBoardID = target.BoardID
+	link.CreatedBy = userID
+
+	return a.store.InsertCardLink(link)
+}
+
+func (a *App) GetCardLink(linkID string) (*model.CardLink, error) {
+	return a.store.GetCardLink(linkID)
+}
+
+func (a *App) GetCardLinks(cardID string) ([]*model.CardLink, error) {
+	return a.store.GetCardLinksForCard(cardID)
+}
+
+func (a *App) DeleteCardLink(linkID string) error {
+	return a.store.DeleteCardLink(linkID)
+}
+
+// GetOpenBlockers returns the cards that block a card and are not done yet.
+// Blockers that were deleted are ignored.
+func (a *App) GetOpenBlockers(cardID string) ([]*model.Card, error) {
+	links, err := a.store.GetCardLinksForCard(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := map[string]model.PropSchema{}
+	blockers := []*model.Card{}
+	for _, link := range links {
+		if link.Type != model.CardLinkBlocks || link.TargetCardID != cardID {
+			continue
+		}
+
+		block, err := a.store.GetBlock(link.SourceCardID)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		schema, ok := schemas[block.BoardID]
+		if !ok {
+			board, err := a.store.GetBoard(block.BoardID)
+			if err != nil {
+				return nil, err
+			}
+			if schema, err = model.ParsePropertySchema(board); err != nil {
+				return nil, err
+			}
+			schemas[block.BoardID] = schema
+		}
+
+		if model.IsCardDone(block, schema) {
+			continue
+		}
+
+		blocker, err := model.Block2Card(block)
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, blocker)
+	}
+	return blockers, nil
+}
+
+// GetOpenBlockersIfDone returns the open blockers of a card that is done,
+// e.g. after a patch, so that finishing a blocked card can be reported.
+// It returns no blockers if the card isn't done.
+func (a *App) GetOpenBlockersIfDone(card *model.Card) ([]*model.Card, error) {
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.IsCardDone(model.Card2Block(card), schema) {
+		return []*model.Card{}, nil
+	}
+	return a.GetOpenBlockers(card.ID)
+}
+
+func (a *App) getCardBlock(cardID string) (*model.Block, error) {
+	block, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != model.TypeCard {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("block %s is not a card", cardID))
+	}
+	return block, nil
+}
+
+// importCardLinks creates the links of the cards of an archive between the
+// imported cards. `cards` maps the ids of the cards in the archive to the
+// imported cards. Links to cards that are not in the archive are skipped.
+func (a *App) importCardLinks(links []*model.CardLink, cards map[string]*model.Block, userID string) {
+	for _, link := range links {
+		source, sourceOK := cards[link.SourceCardID]
+		target, targetOK := cards[link.TargetCardID]
+		if !sourceOK || !targetOK {
+			a.logger.Debug("skipping link to a card outside of the archive",
+				mlog.String("sourceCardID", link.SourceCardID),
+				mlog.String("targetCardID", link.TargetCardID),
+			)
+			continue
+		}
+
+		_, err := a.store.InsertCardLink(&model.CardLink{
+			ID:            utils.NewID(utils.IDTypeNone),
+			Type:          link.Type,
+			SourceCardID:  source.ID,
+			SourceBoardID: source.BoardID,
+			TargetCardID:  target.ID,
+			TargetBoardID: target.BoardID,
+			CreatedBy:     userID,
+		})
+		if err != nil {
+			a.logger.Error("Cannot import card link",
+				mlog.String("sourceCardID", source.ID),
+				mlog.String("targetCardID", target.ID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testLinksBoard(boardID string) *model.Board {
+	return &model.Board{
+		ID:     boardID,
+		TeamID: "team-id",
+		CardProperties: []map[string]any{
+			{
+				"id":   "status-id",
+				"name": "Status",
+				"type": "select",
+				"options": []any{
+					map[string]any{"id": "todo-id", "value": "To Do"},
+					map[string]any{"id": "done-id", "value": "Done 🙌"},
+				},
+			},
+		},
+	}
+}
+
+func testLinksCard(cardID, boardID, status string) *model.Block {
+	return &model.Block{
+		ID:      cardID,
+		BoardID: boardID,
+		Type:    model.TypeCard,
+		Fields:  map[string]any{"properties": map[string]any{"status-id": status}},
+	}
+}
+
+func TestCreateCardLink(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("cards on different boards", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-1").Return(testLinksCard("card-1", "board-1", ""), nil)
+		th.Store.EXPECT().GetBlock("card-2").Return(testLinksCard("card-2", "board-2", ""), nil)
+		th.Store.EXPECT().InsertCardLink(gomock.Any()).DoAndReturn(func(l *model.CardLink) (*model.CardLink, error) {
+			require.NotEmpty(t, l.ID)
+			require.Equal(t, "board-1", l.SourceBoardID)
+			require.Equal(t, "board-2", l.TargetBoardID)
+			require.Equal(t, "user-id", l.CreatedBy)
+			return l, nil
+		})
+
+		link := &model.CardLink{Type: model.CardLinkBlocks, SourceCardID: "card-1", TargetCardID: "card-2"}
+		_, err := th.App.CreateCardLink(link, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("target is not a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-1").Return(testLinksCard("card-1", "board-1", ""), nil)
+		th.Store.EXPECT().GetBlock("view-1").Return(&model.Block{ID: "view-1", BoardID: "board-1", Type: model.TypeView}, nil)
+
+		link := &model.CardLink{Type: model.CardLinkRelatesTo, SourceCardID: "card-1", TargetCardID: "view-1"}
+		_, err := th.App.CreateCardLink(link, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestGetOpenBlockers(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	links := []*model.CardLink{
+		{ID: "link-1", Type: model.CardLinkBlocks, SourceCardID: "card-2", SourceBoardID: "board-1", TargetCardID: "card-1", TargetBoardID: "board-1"},
+		{ID: "link-2", Type: model.CardLinkBlocks, SourceCardID: "card-3", SourceBoardID: "board-2", TargetCardID: "card-1", TargetBoardID: "board-1"},
+		{ID: "link-3", Type: model.CardLinkBlocks, SourceCardID: "card-4", SourceBoardID: "board-1", TargetCardID: "card-1", TargetBoardID: "board-1"},
+		{ID: "link-4", Type: model.CardLinkBlocks, SourceCardID: "card-1", SourceBoardID: "board-1", TargetCardID: "card-5", TargetBoardID: "board-1"},
+		{ID: "link-5", Type: model.CardLinkRelatesTo, SourceCardID: "card-6", SourceBoardID: "board-1", TargetCardID: "card-1", TargetBoardID: "board-1"},
+	}
+
+	th.Store.EXPECT().GetCardLinksForCard("card-1").Return(links, nil)
+	th.Store.EXPECT().GetBlock("card-2").Return(testLinksCard("card-2", "board-1", "todo-id"), nil)
+	th.Store.EXPECT().GetBlock("card-3").Return(testLinksCard("card-3", "board-2", "done-id"), nil)
+	th.Store.EXPECT().GetBlock("card-4").Return(nil, model.NewErrNotFound("card-4"))
+	th.Store.EXPECT().GetBoard("board-1").Return(testLinksBoard("board-1"), nil)
+	th.Store.EXPECT().GetBoard("board-2").Return(testLinksBoard("board-2"), nil)
+
+	blockers, err := th.App.GetOpenBlockers("card-1")
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	require.Equal(t, "card-2", blockers[0].ID)
+}
+
+func TestImportCardLinks(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	cards := map[string]*model.Block{
+		"old-card-1": {ID: "new-card-1", BoardID: "new-board-1", Type: model.TypeCard},
+		"old-card-2": {ID: "new-card-2", BoardID: "new-board-2", Type: model.TypeCard},
+	}
+	links := []*model.CardLink{
+		{Type: model.CardLinkDuplicates, SourceCardID: "old-card-1", TargetCardID: "old-card-2"},
+		{Type: model.CardLinkRelatesTo, SourceCardID: "old-card-1", TargetCardID: "missing-card"},
+	}
+
+	th.Store.EXPECT().InsertCardLink(gomock.Any()).DoAndReturn(func(l *model.CardLink) (*model.CardLink, error) {
+		require.Equal(t, model.CardLinkDuplicates, l.Type)
+		require.Equal(t, "new-card-1", l.SourceCardID)
+		require.Equal(t, "new-board-1", l.SourceBoardID)
+		require.Equal(t, "new-card-2", l.TargetCardID)
+		require.Equal(t, "new-board-2", l.TargetBoardID)
+		return l, nil
+	})
+
+	th.App.importCardLinks(links, cards, "user-id")
+}
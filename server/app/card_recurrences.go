@@ -0,0 +1,242 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	recurringCardsMutexName   = "Boards_recurringCards"
+	recurringCardsBatchSize   = 100
+	recurringCardsLockTimeout = 30 * time.Second
+
+	maxRecurrenceDisabledReasonLength = 255
+)
+
+// SetCardRecurrence attaches a recurrence rule to a card, replacing the
+// existing one. The first occurrence is the next one of the rule, and the
+// date properties of the card refer to it.
+func (a *App) SetCardRecurrence(recurrence *model.CardRecurrence, userID string) (*model.CardRecurrence, error) {
+	if err := recurrence.Rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	existing, err := a.store.GetCardRecurrence(recurrence.CardID)
+	switch {
+	case err == nil:
+		recurrence.CreatedBy = existing.CreatedBy
+	case model.IsErrNotFound(err):
+		recurrence.CreatedBy = userID
+	default:
+		return nil, err
+	}
+	recurrence.ModifiedBy = userID
+	recurrence.DisabledReason = ""
+
+	recurrence.StartAt = 0
+	startAt, err := recurrence.NextRunAfter(utils.GetMillis())
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	recurrence.StartAt = startAt
+	recurrence.NextRunAt = startAt
+
+	if err := a.store.UpsertCardRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return a.store.GetCardRecurrence(recurrence.CardID)
+}
+
+func (a *App) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	return a.store.GetCardRecurrence(cardID)
+}
+
+func (a *App) DeleteCardRecurrence(cardID string) error {
+	return a.store.DeleteCardRecurrence(cardID)
+}
+
+// ProcessRecurringCards creates the copies of the recurring cards that are
+// due. In a cluster, a mutex makes a single server process them at a time.
+func (a *App) ProcessRecurringCards() {
+	if a.newMutexFn != nil {
+		mutex, err := a.newMutexFn(recurringCardsMutexName)
+		if err != nil {
+			a.logger.Error("Cannot create recurring cards mutex", mlog.Err(err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), recurringCardsLockTimeout)
+		defer cancel()
+		if err := mutex.LockWithContext(ctx); err != nil {
+			a.logger.Debug("Recurring cards are being processed by another server")
+			return
+		}
+		defer mutex.Unlock()
+	}
+
+	now := utils.GetMillis()
+	for {
+		recurrences, err := a.store.GetDueCardRecurrences(now, recurringCardsBatchSize)
+		if err != nil {
+			a.logger.Error("Cannot get due card recurrences", mlog.Err(err))
+			return
+		}
+
+		progress := false
+		for _, recurrence := range recurrences {
+			advanced, err := a.runCardRecurrence(recurrence, now)
+			if err != nil {
+				a.logger.Error("Cannot create recurring card",
+					mlog.String("cardID", recurrence.CardID),
+					mlog.String("boardID", recurrence.BoardID),
+					mlog.Err(err),
+				)
+			}
+			progress = progress || advanced
+		}
+
+		if len(recurrences) < recurringCardsBatchSize || !progress {
+			return
+		}
+	}
+}
+
+// runCardRecurrence creates the copy of the card for the due occurrence
+// of a recurrence and schedules the next one. The occurrence is only
+// recorded once the copy exists, so that it's retried after a transient
+// error, while the recurrences that can't create copies anymore are
+// disabled. It returns whether the recurrence was advanced.
+func (a *App) runCardRecurrence(recurrence *model.CardRecurrence, now int64) (bool, error) {
+	runAt := recurrence.NextRunAt
+
+	// occurrences missed while the server was down are skipped
+	nextRunAt, err := recurrence.NextRunAfter(max(runAt, now))
+	if err != nil {
+		return true, a.disableCardRecurrence(recurrence, err)
+	}
+
+	blocks, err := a.createRecurringCard(recurrence, runAt)
+	switch {
+	case model.IsErrNotFound(err):
+		// the card or its board were deleted
+		return true, a.store.DeleteCardRecurrence(recurrence.CardID)
+	case model.IsErrForbidden(err) || model.IsErrBadRequest(err):
+		// the user lost access to the board, or the card can't be copied
+		return true, a.disableCardRecurrence(recurrence, err)
+	case err != nil:
+		return false, err
+	}
+
+	advanced, err := a.store.AdvanceCardRecurrence(recurrence.CardID, runAt, nextRunAt)
+	if err != nil || !advanced {
+		// the occurrence couldn't be recorded, or another server already
+		// created it, so the copy is removed
+		a.deleteRecurringCard(recurrence, blocks)
+		return false, err
+	}
+	return true, nil
+}
+
+// disableCardRecurrence disables a recurrence that failed permanently,
+// with the error as the reason.
+func (a *App) disableCardRecurrence(recurrence *model.CardRecurrence, cause error) error {
+	a.logger.Warn("Disabling card recurrence",
+		mlog.String("cardID", recurrence.CardID),
+		mlog.String("boardID", recurrence.BoardID),
+		mlog.Err(cause),
+	)
+
+	reason := []rune(cause.Error())
+	if len(reason) > maxRecurrenceDisabledReasonLength {
+		reason = reason[:maxRecurrenceDisabledReasonLength]
+	}
+	return a.store.DisableCardRecurrence(recurrence.CardID, string(reason))
+}
+
+// createRecurringCard duplicates the card of a recurrence for the
+// occurrence at `runAt`, with its checkboxes unchecked and its dates
+// shifted from the first occurrence.
+func (a *App) createRecurringCard(recurrence *model.CardRecurrence, runAt int64) ([]*model.Block, error) {
+	userID := recurrence.ModifiedBy
+	if !a.permissions.HasPermissionToBoard(userID, recurrence.BoardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrPermission(fmt.Sprintf("user %s cannot create cards on board %s", userID, recurrence.BoardID))
+	}
+
+	board, err := a.store.GetBoard(recurrence.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := a.DuplicateBlock(recurrence.BoardID, recurrence.CardID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	patches := &model.BlockPatchBatch{}
+	for _, block := range blocks {
+		switch block.Type {
+		case model.TypeCard:
+			properties, ok := block.Fields["properties"].(map[string]any)
+			if !ok || runAt == recurrence.StartAt {
+				continue
+			}
+			patches.BlockIDs = append(patches.BlockIDs, block.ID)
+			patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{
+				UpdatedFields: map[string]any{
+					"properties": model.ShiftDateProperties(properties, schema, runAt-recurrence.StartAt),
+				},
+			})
+		case model.TypeCheckbox:
+			if checked, _ := block.Fields["value"].(bool); checked {
+				patches.BlockIDs = append(patches.BlockIDs, block.ID)
+				patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{
+					UpdatedFields: map[string]any{"value": false},
+				})
+			}
+		}
+	}
+
+	if len(patches.BlockIDs) > 0 {
+		if err = a.PatchBlocksAndNotify(board.TeamID, patches, userID, true); err != nil {
+			// the copy would be created again by the next run
+			a.deleteRecurringCard(recurrence, blocks)
+			return nil, err
+		}
+	}
+
+	a.logger.Debug("Created recurring card",
+		mlog.String("cardID", recurrence.CardID),
+		mlog.String("newCardID", blocks[0].ID),
+		mlog.Int("runAt", runAt),
+	)
+	return blocks, nil
+}
+
+// deleteRecurringCard deletes a copy of the card of a recurrence that
+// doesn't count as an occurrence.
+func (a *App) deleteRecurringCard(recurrence *model.CardRecurrence, blocks []*model.Block) {
+	if len(blocks) == 0 {
+		return
+	}
+	if err := a.DeleteBlock(blocks[0].ID, recurrence.ModifiedBy); err != nil {
+		a.logger.Error("Cannot delete the copy of a recurring card",
+			mlog.String("cardID", recurrence.CardID),
+			mlog.String("copyID", blocks[0].ID),
+			mlog.Err(err),
+		)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCardRecurrence(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("new recurrence", func(t *testing.T) {
+		recurrence := &model.CardRecurrence{
+			CardID:  "card-id",
+			BoardID: "board-id",
+			Rule:    model.RecurrenceRule{Frequency: model.RecurrenceDaily, Hour: 9},
+			Enabled: true,
+		}
+
+		now := utils.GetMillis()
+		th.Store.EXPECT().GetCardRecurrence("card-id").Return(nil, model.NewErrNotFound("card recurrence"))
+		th.Store.EXPECT().UpsertCardRecurrence(recurrence).DoAndReturn(func(r *model.CardRecurrence) error {
+			require.Equal(t, "user-id", r.CreatedBy)
+			require.Equal(t, "user-id", r.ModifiedBy)
+			require.Greater(t, r.StartAt, now)
+			require.LessOrEqual(t, r.StartAt, now+int64(24*time.Hour/time.Millisecond))
+			require.Equal(t, r.StartAt, r.NextRunAt)
+			return nil
+		})
+		th.Store.EXPECT().GetCardRecurrence("card-id").Return(recurrence, nil)
+
+		_, err := th.App.SetCardRecurrence(recurrence, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps the creator", func(t *testing.T) {
+		recurrence := &model.CardRecurrence{
+			CardID:  "card-id",
+			BoardID: "board-id",
+			Rule:    model.RecurrenceRule{Frequency: model.RecurrenceCron, Cron: "0 9 * * 1"},
+		}
+
+		th.Store.EXPECT().GetCardRecurrence("card-id").Return(&model.CardRecurrence{CardID: "card-id", CreatedBy: "creator-id"}, nil)
+		th.Store.EXPECT().UpsertCardRecurrence(recurrence).DoAndReturn(func(r *model.CardRecurrence) error {
+			require.Equal(t, "creator-id", r.CreatedBy)
+			require.Equal(t, "user-id", r.ModifiedBy)
+			return nil
+		})
+		th.Store.EXPECT().GetCardRecurrence("card-id").Return(recurrence, nil)
+
+		_, err := th.App.SetCardRecurrence(recurrence, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := th.App.SetCardRecurrence(&model.CardRecurrence{
+			CardID: "card-id",
+			Rule:   model.RecurrenceRule{Frequency: model.RecurrenceWeekly},
+		}, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestProcessRecurringCards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	const (
+		boardID = "board-id"
+		teamID  = "team-id"
+		cardID  = "card-id"
+		userID  = "user-id"
+	)
+	day := int64(24 * time.Hour / time.Millisecond)
+	startAt := utils.GetMillisForTime(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+
+	board := &model.Board{
+		ID:     boardID,
+		TeamID: teamID,
+		CardProperties: []map[string]any{
+			{"id": "due-id", "name": "Due", "type": "date"},
+		},
+	}
+
+	newRecurrence := func() *model.CardRecurrence {
+		return &model.CardRecurrence{
+			CardID:     cardID,
+			BoardID:    boardID,
+			Rule:       model.RecurrenceRule{Frequency: model.RecurrenceDaily, Hour: 9},
+			Enabled:    true,
+			StartAt:    startAt,
+			NextRunAt:  startAt + 2*day,
+			ModifiedBy: userID,
+		}
+	}
+
+	t.Run("creates the copy of the card", func(t *testing.T) {
+		recurrence := newRecurrence()
+		copies := []*model.Block{
+			{
+				ID:      "new-card-id",
+				BoardID: boardID,
+				Type:    model.TypeCard,
+				Fields: map[string]any{
+					"properties": map[string]any{"due-id": `{"from":1000}`},
+				},
+			},
+			{ID: "checked-id", BoardID: boardID, ParentID: "new-card-id", Type: model.TypeCheckbox, Fields: map[string]any{"value": true}},
+			{ID: "unchecked-id", BoardID: boardID, ParentID: "new-card-id", Type: model.TypeCheckbox, Fields: map[string]any{"value": false}},
+			{ID: "text-id", BoardID: boardID, ParentID: "new-card-id", Type: model.TypeText},
+		}
+
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.expectBoardEditor(userID, boardID, teamID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().GetBlock(gomock.Any()).Return(copies[0], nil).AnyTimes()
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		// the occurrence is only recorded once its card is created
+		gomock.InOrder(
+			th.Store.EXPECT().DuplicateBlock(boardID, cardID, userID, false).Return(copies, nil),
+			th.Store.EXPECT().GetBlocksByIDs(gomock.Any()).Return(copies, nil),
+			th.Store.EXPECT().PatchBlocks(gomock.Any(), userID).DoAndReturn(func(patches *model.BlockPatchBatch, _ string) error {
+				require.Equal(t, []string{"new-card-id", "checked-id"}, patches.BlockIDs)
+				require.Equal(t, map[string]any{"due-id": `{"from":172801000}`},
+					patches.BlockPatches[0].UpdatedFields["properties"])
+				require.Equal(t, false, patches.BlockPatches[1].UpdatedFields["value"])
+				return nil
+			}),
+			th.Store.EXPECT().AdvanceCardRecurrence(cardID, recurrence.NextRunAt, gomock.Any()).DoAndReturn(
+				func(_ string, runAt, nextRunAt int64) (bool, error) {
+					require.Greater(t, nextRunAt, utils.GetMillis())
+					return true, nil
+				}),
+		)
+
+		th.App.ProcessRecurringCards()
+	})
+
+	t.Run("occurrence created by another server", func(t *testing.T) {
+		recurrence := newRecurrence()
+		copies := []*model.Block{{ID: "new-card-id", BoardID: boardID, Type: model.TypeCard, Fields: map[string]any{}}}
+
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.expectBoardEditor(userID, boardID, teamID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().GetBlock("new-card-id").Return(copies[0], nil).AnyTimes()
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		// the copy is removed as the other server's one is kept
+		gomock.InOrder(
+			th.Store.EXPECT().DuplicateBlock(boardID, cardID, userID, false).Return(copies, nil),
+			th.Store.EXPECT().AdvanceCardRecurrence(cardID, recurrence.NextRunAt, gomock.Any()).Return(false, nil),
+			th.Store.EXPECT().DeleteBlock("new-card-id", userID).Return(nil),
+		)
+
+		th.App.ProcessRecurringCards()
+	})
+
+	t.Run("the copy isn't updated", func(t *testing.T) {
+		recurrence := newRecurrence()
+		copies := []*model.Block{
+			{ID: "new-card-id", BoardID: boardID, Type: model.TypeCard, Fields: map[string]any{}},
+			{ID: "checked-id", BoardID: boardID, ParentID: "new-card-id", Type: model.TypeCheckbox, Fields: map[string]any{"value": true}},
+		}
+
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.expectBoardEditor(userID, boardID, teamID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().GetBlock("new-card-id").Return(copies[0], nil).AnyTimes()
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		// the copy is removed and the occurrence isn't advanced, so that
+		// it's created again by the next run
+		gomock.InOrder(
+			th.Store.EXPECT().DuplicateBlock(boardID, cardID, userID, false).Return(copies, nil),
+			th.Store.EXPECT().GetBlocksByIDs(gomock.Any()).Return(copies, nil),
+			th.Store.EXPECT().PatchBlocks(gomock.Any(), userID).Return(errors.New("database error")),
+			th.Store.EXPECT().DeleteBlock("new-card-id", userID).Return(nil),
+		)
+
+		th.App.ProcessRecurringCards()
+	})
+
+	t.Run("the card isn't created", func(t *testing.T) {
+		recurrence := newRecurrence()
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.expectBoardEditor(userID, boardID, teamID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		// the occurrence isn't advanced, so that it's retried
+		th.Store.EXPECT().DuplicateBlock(boardID, cardID, userID, false).Return(nil, errors.New("database error"))
+
+		th.App.ProcessRecurringCards()
+	})
+
+	t.Run("the user lost access to the board", func(t *testing.T) {
+		recurrence := newRecurrence()
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.PermStore.EXPECT().GetBoard(boardID).Return(board, nil)
+		th.API.EXPECT().HasPermissionToTeam(userID, teamID, model.PermissionViewTeam).Return(false)
+		th.Store.EXPECT().DisableCardRecurrence(cardID, gomock.Any()).DoAndReturn(func(_ string, reason string) error {
+			require.Contains(t, reason, "cannot create cards")
+			return nil
+		})
+
+		th.App.ProcessRecurringCards()
+	})
+
+	t.Run("deleted card", func(t *testing.T) {
+		recurrence := newRecurrence()
+		th.Store.EXPECT().GetDueCardRecurrences(gomock.Any(), recurringCardsBatchSize).Return([]*model.CardRecurrence{recurrence}, nil)
+		th.expectBoardEditor(userID, boardID, teamID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().DuplicateBlock(boardID, cardID, userID, false).Return(nil, model.NewErrNotFound("block subtree"))
+		th.Store.EXPECT().DeleteCardRecurrence(cardID).Return(nil)
+
+		th.App.ProcessRecurringCards()
+	})
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// cardRevision is a revision of a card along with the card block it was
+// built from, which keeps the fields the card model doesn't have.
+type cardRevision struct {
+	*model.CardRevision
+	block *model.Block
+}
+
+// GetCardRevisions returns the revisions of a card and its content
+// blocks, newest first.
+func (a *App) GetCardRevisions(cardID string, opts model.QueryCardRevisionsOptions) ([]*model.CardRevision, error) {
+	revisions, err := a.getCardRevisions(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*model.CardRevision{}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if opts.BeforeUpdateAt != 0 && revisions[i].UpdateAt >= opts.BeforeUpdateAt {
+			continue
+		}
+		result = append(result, revisions[i].CardRevision)
+		if opts.PerPage > 0 && len(result) == opts.PerPage {
+			break
+		}
+	}
+	return result, nil
+}
+
+// GetCardRevisionDiff returns the changes of a card and its content
+// blocks between two of its revisions.
+func (a *App) GetCardRevisionDiff(cardID string, fromUpdateAt, toUpdateAt int64) (*model.CardRevisionDiff, error) {
+	revisions, err := a.getCardRevisions(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := findCardRevision(revisions, fromUpdateAt)
+	if from == nil {
+		return nil, model.NewErrNotFound(fmt.Sprintf("revision %d of card %s", fromUpdateAt, cardID))
+	}
+	to := findCardRevision(revisions, toUpdateAt)
+	if to == nil {
+		return nil, model.NewErrNotFound(fmt.Sprintf("revision %d of card %s", toUpdateAt, cardID))
+	}
+
+	return model.DiffCardRevisions(from.CardRevision, to.CardRevision), nil
+}
+
+// RestoreCardRevision brings a card and its content blocks back to a
+// previous revision. The restore is a new change made by the user, so
+// every block goes through the regular insert, patch and delete paths
+// and their broadcasts and notifications.
+func (a *App) RestoreCardRevision(cardID string, updateAt int64, userID string) (*model.Card, error) {
+	revisions, err := a.getCardRevisions(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := findCardRevision(revisions, updateAt)
+	if revision == nil {
+		return nil, model.NewErrNotFound(fmt.Sprintf("revision %d of card %s", updateAt, cardID))
+	}
+	if revision.block.DeleteAt != 0 {
+		return nil, model.NewErrBadRequest("cannot restore a revision where the card is deleted")
+	}
+
+	board, err := a.store.GetBoard(revision.block.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := &model.CardRevisionRestore{}
+	oldBlocks := map[string]*model.Block{}
+	restored := map[string]bool{}
+	for _, version := range append([]*model.Block{revision.block}, revision.Contents...) {
+		restored[version.ID] = true
+		var current *model.Block
+		current, err = a.restoreBlockVersion(board, version, restore)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil {
+			oldBlocks[current.ID] = current
+		}
+	}
+
+	children, err := a.store.GetBlocks(model.QueryBlocksOptions{
+		BoardID:  revision.block.BoardID,
+		ParentID: cardID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []*model.Block{}
+	for _, child := range children {
+		if child.Type == model.TypeComment || restored[child.ID] {
+			continue
+		}
+		restore.DeleteBlockIDs = append(restore.DeleteBlockIDs, child.ID)
+		deleted = append(deleted, child)
+	}
+
+	// the whole revision is restored at once, so that a failure doesn't
+	// leave the card halfway between two revisions
+	if err = a.store.RestoreCardRevision(restore, userID); err != nil {
+		return nil, err
+	}
+
+	changedIDs := make([]string, 0, len(restore.InsertBlocks)+len(restore.PatchBlocks.BlockIDs))
+	for _, block := range restore.InsertBlocks {
+		changedIDs = append(changedIDs, block.ID)
+	}
+	changedIDs = append(changedIDs, restore.PatchBlocks.BlockIDs...)
+	changed := []*model.Block{}
+	if len(changedIDs) > 0 {
+		if changed, err = a.store.GetBlocksByIDs(changedIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, block := range changed {
+			oldBlock := oldBlocks[block.ID]
+			action := notify.Update
+			if oldBlock == nil {
+				action = notify.Add
+				a.metrics.IncrementBlocksInserted(1)
+			} else {
+				a.metrics.IncrementBlocksPatched(1)
+			}
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.webhook.NotifyUpdate(block)
+			a.notifyBlockChanged(action, block, oldBlock, userID)
+			a.runBoardRules(a.ruleChangeForBlock(block, oldBlock, userID))
+		}
+		for _, block := range deleted {
+			a.wsAdapter.BroadcastBlockDelete(board.TeamID, block.ID, block.BoardID)
+			a.metrics.IncrementBlocksDeleted(1)
+			a.notifyBlockChanged(notify.Delete, block, block, userID)
+		}
+		return nil
+	})
+
+	a.logger.Debug("RestoreCardRevision",
+		mlog.String("cardID", cardID),
+		mlog.Int("updateAt", updateAt),
+		mlog.Int("contentsCount", len(revision.Contents)),
+	)
+
+	return a.GetCardByID(cardID)
+}
+
+// restoreBlockVersion adds to the restore the patch that brings a block
+// back to a previous version of it, or its insertion if it has been
+// deleted since. It returns the current block, if any.
+func (a *App) restoreBlockVersion(board *model.Board, version *model.Block, restore *model.CardRevisionRestore) (*model.Block, error) {
+	current, err := a.store.GetBlock(version.ID)
+	if model.IsErrNotFound(err) {
+		block := *version
+		block.DeleteAt = 0
+		if err = a.validateRestoredFields(board, &block, block.Fields); err != nil {
+			return nil, err
+		}
+		restore.InsertBlocks = append(restore.InsertBlocks, &block)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	patch := model.NewBlockRestorePatch(current, version)
+	if patch == nil {
+		return current, nil
+	}
+	if err = model.ValidateBlockPatch(patch); err != nil {
+		return nil, err
+	}
+	if patch.UpdatedFields != nil {
+		if err = a.validateRestoredFields(board, current, patch.UpdatedFields); err != nil {
+			return nil, err
+		}
+	}
+	restore.PatchBlocks.BlockIDs = append(restore.PatchBlocks.BlockIDs, version.ID)
+	restore.PatchBlocks.BlockPatches = append(restore.PatchBlocks.BlockPatches, *patch)
+	return current, nil
+}
+
+// validateRestoredFields runs on the restored fields of a block the same
+// checks as on the fields of the inserted and patched blocks.
+func (a *App) validateRestoredFields(board *model.Board, block *model.Block, fields map[string]interface{}) error {
+	if err := a.validateFileRefsInFields(board.TeamID, board.ID, block.ID, fields); err != nil {
+		return err
+	}
+	if block.Type == model.TypeCard {
+		return validateCardPropertiesForBoard(board, fields)
+	}
+	return nil
+}
+
+// getCardRevisions rebuilds the revisions of a card, oldest first, from
+// the history of the card and of its content blocks. The changes made in
+// the same millisecond are part of the same revision.
+func (a *App) getCardRevisions(cardID string) ([]*cardRevision, error) {
+	cardVersions, err := a.store.GetBlockHistory(cardID, model.QueryBlockHistoryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(cardVersions) == 0 || cardVersions[0].Type != model.TypeCard {
+		return nil, model.NewErrNotFound("card ID=" + cardID)
+	}
+
+	childVersions, err := a.store.GetBlockHistoryChildren(cardID, model.QueryBlockHistoryOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*model.Block, 0, len(cardVersions)+len(childVersions))
+	versions = append(versions, cardVersions...)
+	for _, version := range childVersions {
+		// comments are not part of the card content
+		if version.Type != model.TypeComment {
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].UpdateAt < versions[j].UpdateAt
+	})
+
+	revisions := []*cardRevision{}
+	var cardBlock *model.Block
+	contents := map[string]*model.Block{}
+	for i, version := range versions {
+		switch {
+		case version.ID == cardID:
+			cardBlock = version
+		case version.DeleteAt != 0:
+			delete(contents, version.ID)
+		default:
+			contents[version.ID] = version
+		}
+
+		if i+1 < len(versions) && versions[i+1].UpdateAt == version.UpdateAt {
+			continue
+		}
+		if cardBlock == nil {
+			continue
+		}
+
+		revision, err := newCardRevision(cardBlock, contents, version)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// newCardRevision returns the revision made by the last change, with the
+// content blocks in the order of the card.
+func newCardRevision(cardBlock *model.Block, contents map[string]*model.Block, lastChange *model.Block) (*cardRevision, error) {
+	card, err := model.Block2Card(cardBlock)
+	if err != nil {
+		return nil, fmt.Errorf("Block2Card fail: %w", err)
+	}
+
+	position := map[string]int{}
+	for i, id := range card.ContentOrder {
+		position[id] = i
+	}
+
+	blocks := make([]*model.Block, 0, len(contents))
+	for _, block := range contents {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		pi, iOrdered := position[blocks[i].ID]
+		pj, jOrdered := position[blocks[j].ID]
+		if iOrdered != jOrdered {
+			return iOrdered
+		}
+		if iOrdered && pi != pj {
+			return pi < pj
+		}
+		if blocks[i].CreateAt != blocks[j].CreateAt {
+			return blocks[i].CreateAt < blocks[j].CreateAt
+		}
+		return blocks[i].ID < blocks[j].ID
+	})
+
+	return &cardRevision{
+		CardRevision: &model.CardRevision{
+			UpdateAt:   lastChange.UpdateAt,
+			ModifiedBy: lastChange.ModifiedBy,
+			Card:       card,
+			Contents:   blocks,
+		},
+		block: cardBlock,
+	}, nil
+}
+
+func findCardRevision(revisions []*cardRevision, updateAt int64) *cardRevision {
+	for _, revision := range revisions {
+		if revision.UpdateAt == updateAt {
+			return revision
+		}
+	}
+	return nil
+}
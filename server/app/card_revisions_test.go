@@ -0,0 +1,172 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+func TestCardRevisions(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	boardID := testBoardID
+	cardID := "card-id"
+	userID := "user-id"
+
+	cardVersion := func(title string, updateAt int64, modifiedBy string) *model.Block {
+		return &model.Block{
+			ID:         cardID,
+			BoardID:    boardID,
+			Type:       model.TypeCard,
+			Title:      title,
+			ModifiedBy: modifiedBy,
+			CreateAt:   1000,
+			UpdateAt:   updateAt,
+			Fields:     map[string]interface{}{"properties": map[string]interface{}{}},
+		}
+	}
+	textVersion := func(updateAt, deleteAt int64) *model.Block {
+		return &model.Block{
+			ID:         "text-id",
+			BoardID:    boardID,
+			ParentID:   cardID,
+			Type:       model.TypeText,
+			Title:      "some text",
+			ModifiedBy: "other-user-id",
+			CreateAt:   1500,
+			UpdateAt:   updateAt,
+			DeleteAt:   deleteAt,
+			Fields:     map[string]interface{}{},
+		}
+	}
+
+	// the card is created, some text is added, the card is renamed and the
+	// text is deleted, along with a comment that isn't part of the revisions
+	cardVersions := []*model.Block{
+		cardVersion("first", 1000, userID),
+		cardVersion("second", 2000, userID),
+	}
+	childVersions := []*model.Block{
+		textVersion(1500, 0),
+		{ID: "comment-id", BoardID: boardID, ParentID: cardID, Type: model.TypeComment, CreateAt: 1700, UpdateAt: 1700},
+		textVersion(2500, 2500),
+	}
+
+	expectHistory := func() {
+		th.Store.EXPECT().GetBlockHistory(cardID, model.QueryBlockHistoryOptions{}).Return(cardVersions, nil)
+		th.Store.EXPECT().GetBlockHistoryChildren(cardID, model.QueryBlockHistoryOptions{}).Return(childVersions, nil)
+	}
+
+	t.Run("revisions are listed newest first", func(t *testing.T) {
+		expectHistory()
+
+		revisions, err := th.App.GetCardRevisions(cardID, model.QueryCardRevisionsOptions{})
+		require.NoError(t, err)
+		require.Len(t, revisions, 4)
+
+		require.Equal(t, int64(2500), revisions[0].UpdateAt)
+		require.Equal(t, "other-user-id", revisions[0].ModifiedBy)
+		require.Equal(t, "second", revisions[0].Card.Title)
+		require.Empty(t, revisions[0].Contents)
+
+		require.Equal(t, int64(2000), revisions[1].UpdateAt)
+		require.Equal(t, userID, revisions[1].ModifiedBy)
+		require.Equal(t, "second", revisions[1].Card.Title)
+		require.Len(t, revisions[1].Contents, 1)
+
+		require.Equal(t, int64(1500), revisions[2].UpdateAt)
+		require.Equal(t, "first", revisions[2].Card.Title)
+		require.Equal(t, "text-id", revisions[2].Contents[0].ID)
+
+		require.Equal(t, int64(1000), revisions[3].UpdateAt)
+		require.Empty(t, revisions[3].Contents)
+	})
+
+	t.Run("revisions are paged", func(t *testing.T) {
+		expectHistory()
+
+		revisions, err := th.App.GetCardRevisions(cardID, model.QueryCardRevisionsOptions{BeforeUpdateAt: 2000, PerPage: 1})
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		require.Equal(t, int64(1500), revisions[0].UpdateAt)
+	})
+
+	t.Run("not a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlockHistory("text-id", model.QueryBlockHistoryOptions{}).Return([]*model.Block{textVersion(1500, 0)}, nil)
+
+		_, err := th.App.GetCardRevisions("text-id", model.QueryCardRevisionsOptions{})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("diff between two revisions", func(t *testing.T) {
+		expectHistory()
+
+		diff, err := th.App.GetCardRevisionDiff(cardID, 1500, 2500)
+		require.NoError(t, err)
+		require.Equal(t, &model.CardValueChange{Old: "first", New: "second"}, diff.Title)
+		require.Len(t, diff.RemovedContents, 1)
+		require.Empty(t, diff.AddedContents)
+	})
+
+	t.Run("diff with an unknown revision", func(t *testing.T) {
+		expectHistory()
+
+		_, err := th.App.GetCardRevisionDiff(cardID, 1500, 1600)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("restore a revision", func(t *testing.T) {
+		expectHistory()
+
+		board := &model.Board{ID: boardID}
+		current := cardVersion("second", 2000, userID)
+		restored := cardVersion("first", 3000, userID)
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		th.Store.EXPECT().GetBlock(cardID).Return(current, nil)
+		th.Store.EXPECT().GetBlock("text-id").Return(nil, model.NewErrNotFound("block ID=text-id"))
+		th.Store.EXPECT().GetBlocks(model.QueryBlocksOptions{BoardID: boardID, ParentID: cardID}).Return([]*model.Block{
+			{ID: "new-text-id", BoardID: boardID, ParentID: cardID, Type: model.TypeText},
+			{ID: "new-comment-id", BoardID: boardID, ParentID: cardID, Type: model.TypeComment},
+		}, nil)
+
+		// the card is renamed back, the deleted text is inserted again and
+		// the text added since is deleted, all at once
+		restoredText := textVersion(1500, 0)
+		gomock.InOrder(
+			th.Store.EXPECT().RestoreCardRevision(&model.CardRevisionRestore{
+				InsertBlocks: []*model.Block{restoredText},
+				PatchBlocks: model.BlockPatchBatch{
+					BlockIDs:     []string{cardID},
+					BlockPatches: []model.BlockPatch{{Title: &restored.Title}},
+				},
+				DeleteBlockIDs: []string{"new-text-id"},
+			}, userID).Return(nil),
+			th.Store.EXPECT().GetBlocksByIDs([]string{"text-id", cardID}).Return([]*model.Block{restoredText, restored}, nil),
+			th.Store.EXPECT().GetBlock(cardID).Return(restored, nil),
+		)
+		th.Store.EXPECT().GetTimeSpentForCards([]string{cardID}).Return(map[string]int64{}, nil)
+
+		card, err := th.App.RestoreCardRevision(cardID, 1500, userID)
+		require.NoError(t, err)
+		require.Equal(t, "first", card.Title)
+	})
+
+	t.Run("restore a revision where the card is deleted", func(t *testing.T) {
+		deleted := cardVersion("second", 4000, userID)
+		deleted.DeleteAt = 4000
+		th.Store.EXPECT().GetBlockHistory(cardID, model.QueryBlockHistoryOptions{}).Return(append(cardVersions, deleted), nil)
+		th.Store.EXPECT().GetBlockHistoryChildren(cardID, model.QueryBlockHistoryOptions{}).Return(childVersions, nil)
+
+		_, err := th.App.RestoreCardRevision(cardID, 4000, userID)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
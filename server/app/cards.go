@@ -4,6 +4,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
@@ -59,6 +60,10 @@ func (a *App) GetCardsForBoard(boardID string, page int, perPage int) ([]*model.
 			cards = append(cards, card)
 		}
 	}
+
+	if err := a.addTimeSpent(cards...); err != nil {
+		return nil, err
+	}
 	return cards, nil
 }
 
@@ -69,6 +74,15 @@ func (a *App) PatchCard(cardPatch *model.CardPatch, cardID string, userID string
 	}
 
 	newBlock, err := a.PatchBlockAndNotify(cardID, blockPatch, userID, disableNotify)
+	var conflict *model.ErrConflict
+	if errors.As(err, &conflict) {
+		// the conflict is answered with the current card, not its block
+		if currentBlock, ok := conflict.Current.(*model.Block); ok {
+			if currentCard, cErr := model.Block2Card(currentBlock); cErr == nil {
+				return nil, model.NewErrConflict("card ID="+cardID, currentCard)
+			}
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot patch card %s: %w", cardID, err)
 	}
@@ -78,6 +92,9 @@ func (a *App) PatchCard(cardPatch *model.CardPatch, cardID string, userID string
 		return nil, err
 	}
 
+	if err := a.addTimeSpent(newCard); err != nil {
+		return nil, err
+	}
 	return newCard, nil
 }
 
@@ -92,5 +109,101 @@ func (a *App) GetCardByID(cardID string) (*model.Card, error) {
 		return nil, err
 	}
 
+	if err := a.addTimeSpent(card); err != nil {
+		return nil, err
+	}
 	return card, nil
 }
+
+// QueryCards returns a page of the cards of a board that match the
+// query, in the requested order.
+func (a *App) QueryCards(boardID string, query *model.CardQuery) (*model.CardQueryResult, error) {
+	opts := model.QueryCardsOptions{
+		BoardID:     boardID,
+		Filter:      query.Filter,
+		SortOptions: query.SortOptions,
+		PerPage:     query.PerPage,
+	}
+
+	if query.Cursor != "" {
+		cursor, err := model.DecodeCardsCursor(query.Cursor)
+		if err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+		opts.Cursor = cursor
+	}
+
+	blocks, nextCursor, err := a.store.QueryCards(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*model.Card, 0, len(blocks))
+	for _, b := range blocks {
+		card, err := model.Block2Card(b)
+		if err != nil {
+			return nil, fmt.Errorf("Block2Card fail: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	if err := a.addTimeSpent(cards...); err != nil {
+		return nil, err
+	}
+
+	return &model.CardQueryResult{
+		Cards:      cards,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// SearchCardsForUser returns the cards of the boards of a team that the
+// user can see that contain all the words of the term in their title or
+// in their text, checkbox or comment blocks.
+func (a *App) SearchCardsForUser(teamID, userID, term string, includePublicBoards bool, page, perPage int) ([]*model.CardSearchResult, error) {
+	words := model.SearchTermWords(term)
+	if len(words) == 0 {
+		return []*model.CardSearchResult{}, nil
+	}
+
+	opts := model.SearchCardsOptions{
+		TeamID:              teamID,
+		UserID:              userID,
+		Words:               words,
+		IncludePublicBoards: includePublicBoards,
+		Page:                page,
+		PerPage:             perPage,
+	}
+
+	matches, err := a.store.SearchCardsForUser(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	boards := map[string]*model.Board{}
+	results := make([]*model.CardSearchResult, 0, len(matches))
+	for _, match := range matches {
+		card, err := model.Block2Card(match.Card)
+		if err != nil {
+			return nil, fmt.Errorf("Block2Card fail: %w", err)
+		}
+
+		board, ok := boards[card.BoardID]
+		if !ok {
+			board, err = a.store.GetBoard(card.BoardID)
+			if err != nil {
+				return nil, err
+			}
+			boards[card.BoardID] = board
+		}
+
+		results = append(results, &model.CardSearchResult{
+			Card:      card,
+			Board:     board,
+			MatchType: match.MatchType,
+			Snippet:   model.SearchSnippet(match.MatchText, words),
+		})
+	}
+
+	return results, nil
+}
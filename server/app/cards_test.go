@@ -13,6 +13,8 @@ import (
 	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 )
 
 func TestCreateCard(t *testing.T) {
@@ -94,10 +96,13 @@ func TestGetCards(t *testing.T) {
 		}
 
 		th.Store.EXPECT().GetBlocks(opts).Return(blocks, nil)
+		th.Store.EXPECT().GetTimeSpentForCards(gomock.Len(cardCount)).Return(map[string]int64{blocks[0].ID: 3600000}, nil)
 
 		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0)
 		require.NoError(t, err)
 		assert.Len(t, cards, cardCount)
+		assert.Equal(t, int64(3600000), cards[0].TimeSpent)
+		assert.Zero(t, cards[1].TimeSpent)
 	})
 
 	t.Run("error scenario", func(t *testing.T) {
@@ -114,6 +119,69 @@ func TestGetCards(t *testing.T) {
 	})
 }
 
+func TestQueryCards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	blocks := []*model.Block{
+		{ID: utils.NewID(utils.IDTypeCard), BoardID: boardID, Type: model.TypeCard, Title: "card 1"},
+		{ID: utils.NewID(utils.IDTypeCard), BoardID: boardID, Type: model.TypeCard, Title: "card 2"},
+	}
+
+	filter := &model.FilterGroup{
+		Operation: model.FilterOperationAnd,
+		Filters: []model.FilterGroupItem{
+			{Clause: &model.FilterClause{PropertyID: model.TitleFilterPropertyID, Condition: model.FilterConditionContains, Values: []string{"card"}}},
+		},
+	}
+	sortOptions := []model.CardSortOption{{PropertyID: model.TitlePropertyID}}
+
+	t.Run("success scenario", func(t *testing.T) {
+		cursor := &model.CardsCursor{Keys: []interface{}{float64(0), "card 0"}, ID: "card-0"}
+		query := &model.CardQuery{
+			Filter:      filter,
+			SortOptions: sortOptions,
+			Cursor:      cursor.Encode(),
+			PerPage:     2,
+		}
+
+		opts := model.QueryCardsOptions{
+			BoardID:     boardID,
+			Filter:      filter,
+			SortOptions: sortOptions,
+			Cursor:      cursor,
+			PerPage:     2,
+		}
+		th.Store.EXPECT().QueryCards(opts).Return(blocks, "next-cursor", nil)
+		th.Store.EXPECT().GetTimeSpentForCards([]string{blocks[0].ID, blocks[1].ID}).Return(map[string]int64{}, nil)
+
+		result, err := th.App.QueryCards(boardID, query)
+		require.NoError(t, err)
+		require.Len(t, result.Cards, 2)
+		assert.Equal(t, blocks[0].ID, result.Cards[0].ID)
+		assert.Equal(t, blocks[1].ID, result.Cards[1].ID)
+		assert.Equal(t, "next-cursor", result.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		result, err := th.App.QueryCards(boardID, &model.CardQuery{Cursor: "garbage"})
+		require.Error(t, err)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+
+	t.Run("error scenario", func(t *testing.T) {
+		opts := model.QueryCardsOptions{BoardID: boardID}
+		th.Store.EXPECT().QueryCards(opts).Return(nil, "", blockError{"error"})
+
+		result, err := th.App.QueryCards(boardID, &model.CardQuery{})
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+}
+
 func TestPatchCard(t *testing.T) {
 	th, tearDown := SetupTestHelper(t)
 	defer tearDown()
@@ -154,6 +222,7 @@ func TestPatchCard(t *testing.T) {
 		th.Store.EXPECT().PatchBlock(card.ID, gomock.AssignableToTypeOf(reflect.TypeOf(blockPatch)), userID).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(board.ID).Return([]*model.BoardMember{}, nil)
 		th.Store.EXPECT().GetBlock(card.ID).Return(expectedPatchedBlock, nil).AnyTimes()
+		th.Store.EXPECT().GetTimeSpentForCards(gomock.Len(1)).Return(map[string]int64{}, nil)
 
 		patchedCard, err := th.App.PatchCard(cardPatch, card.ID, userID, false)
 
@@ -165,6 +234,26 @@ func TestPatchCard(t *testing.T) {
 		require.EqualValues(t, expectedPatchedCard.Properties, patchedCard.Properties)
 	})
 
+	t.Run("conflict scenario", func(t *testing.T) {
+		currentBlock := model.Card2Block(card)
+		currentBlock.UpdateAt = 2000
+
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().PatchBlock(card.ID, gomock.Any(), userID).Return(model.NewErrConflict("block ID="+card.ID, currentBlock))
+
+		stalePatch := &model.CardPatch{Title: &newTitle, ExpectedUpdateAt: mmModel.NewPointer(int64(1000))}
+		patchedCard, err := th.App.PatchCard(stalePatch, card.ID, userID, false)
+
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, patchedCard)
+
+		var conflict *model.ErrConflict
+		require.ErrorAs(t, err, &conflict)
+		currentCard, ok := conflict.Current.(*model.Card)
+		require.True(t, ok)
+		require.Equal(t, card.Title, currentCard.Title)
+	})
+
 	t.Run("error scenario", func(t *testing.T) {
 		var blockPatch *model.BlockPatch
 		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
@@ -204,10 +293,12 @@ func TestGetCard(t *testing.T) {
 
 	t.Run("success scenario", func(t *testing.T) {
 		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil)
+		th.Store.EXPECT().GetTimeSpentForCards([]string{block.ID}).Return(map[string]int64{block.ID: 90000}, nil)
 
 		card, err := th.App.GetCardByID(block.ID)
 
 		require.NoError(t, err)
+		require.Equal(t, int64(90000), card.TimeSpent)
 		require.Equal(t, boardID, card.BoardID)
 		require.Equal(t, block.Title, card.Title)
 		require.Equal(t, "😀", card.Icon)
@@ -269,3 +360,58 @@ func modifyProps(m map[string]any) map[string]any {
 	}
 	return out
 }
+
+func TestSearchCardsForUser(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	teamID := utils.NewID(utils.IDTypeTeam)
+	userID := utils.NewID(utils.IDTypeUser)
+	board := &model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: teamID}
+
+	card1 := &model.Block{ID: utils.NewID(utils.IDTypeCard), BoardID: board.ID, Type: model.TypeCard, Title: "Release plan"}
+	card2 := &model.Block{ID: utils.NewID(utils.IDTypeCard), BoardID: board.ID, Type: model.TypeCard, Title: "Other"}
+
+	t.Run("success scenario", func(t *testing.T) {
+		opts := model.SearchCardsOptions{
+			TeamID:              teamID,
+			UserID:              userID,
+			Words:               []string{"release"},
+			IncludePublicBoards: true,
+			PerPage:             10,
+		}
+		th.Store.EXPECT().SearchCardsForUser(opts).Return([]*model.CardSearchMatch{
+			{Card: card1, MatchType: model.TypeCard, MatchText: card1.Title},
+			{Card: card2, MatchType: model.TypeText, MatchText: "Notes for the release"},
+		}, nil)
+		// boards are only fetched once
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil).Times(1)
+
+		results, err := th.App.SearchCardsForUser(teamID, userID, "Release!", true, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		assert.Equal(t, card1.ID, results[0].Card.ID)
+		assert.Equal(t, board, results[0].Board)
+		assert.EqualValues(t, model.TypeCard, results[0].MatchType)
+		assert.Equal(t, "**Release** plan", results[0].Snippet)
+
+		assert.Equal(t, card2.ID, results[1].Card.ID)
+		assert.EqualValues(t, model.TypeText, results[1].MatchType)
+		assert.Equal(t, "Notes for the **release**", results[1].Snippet)
+	})
+
+	t.Run("empty term", func(t *testing.T) {
+		results, err := th.App.SearchCardsForUser(teamID, userID, " ?! ", true, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("error scenario", func(t *testing.T) {
+		th.Store.EXPECT().SearchCardsForUser(gomock.Any()).Return(nil, blockError{"error"})
+
+		results, err := th.App.SearchCardsForUser(teamID, userID, "release", false, 0, 10)
+		require.Error(t, err)
+		require.Nil(t, results)
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// GetChanges returns a page of the changes of the boards, blocks,
+// memberships and categories of a team that are visible to a user, after
+// the cursor returned by a previous call. An empty cursor starts from the
+// oldest change. The feed stays model.ChangesSafetyWindow behind the
+// current time, so that the changes of the transactions that are still
+// running aren't skipped.
+func (a *App) GetChanges(teamID, userID, since string, perPage int) (*model.ChangesResponse, error) {
+	if perPage <= 0 || perPage > model.ChangesMaxPerPage {
+		perPage = model.ChangesDefaultPerPage
+	}
+
+	var cursor *model.ChangesCursor
+	if since != "" {
+		var err error
+		if cursor, err = model.DecodeChangesCursor(since); err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+	}
+
+	// guests only have access to the boards they are a member of
+	isGuest, err := a.UserIsGuest(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := model.QueryChangesOptions{
+		TeamID:                teamID,
+		UserID:                userID,
+		IncludeImplicitBoards: !isGuest,
+		Cursor:                cursor,
+		Until:                 utils.GetMillis() - model.ChangesSafetyWindow.Milliseconds(),
+		PerPage:               perPage,
+	}
+
+	changes, hasNext, err := a.store.GetChanges(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// without new changes the client keeps polling from the same cursor
+	nextCursor := since
+	if len(changes) > 0 {
+		nextCursor = changes[len(changes)-1].Cursor().Encode()
+	}
+
+	return &model.ChangesResponse{
+		Changes: changes,
+		Cursor:  nextCursor,
+		HasNext: hasNext,
+	}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+func TestGetChanges(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	teamID := "team-id"
+	userID := "user-id"
+
+	// the changes are queried up to the safety window before now
+	expectGetChanges := func(isGuest bool, opts model.QueryChangesOptions, changes []*model.Change, hasNext bool) {
+		th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID, IsGuest: isGuest}, nil)
+		th.Store.EXPECT().GetChanges(gomock.Any()).DoAndReturn(func(got model.QueryChangesOptions) ([]*model.Change, bool, error) {
+			until := utils.GetMillis() - model.ChangesSafetyWindow.Milliseconds()
+			require.InDelta(t, until, got.Until, 1000)
+			got.Until = 0
+			require.Equal(t, opts, got)
+			return changes, hasNext, nil
+		})
+	}
+
+	t.Run("the cursor of the last change is returned", func(t *testing.T) {
+		changes := []*model.Change{
+			model.NewBoardChange(&model.Board{ID: "board-id", UpdateAt: 1000}),
+			model.NewBlockChange(&model.Block{ID: "block-id", BoardID: "board-id", UpdateAt: 2000}),
+		}
+		opts := model.QueryChangesOptions{TeamID: teamID, UserID: userID, IncludeImplicitBoards: true, PerPage: 2}
+		expectGetChanges(false, opts, changes, true)
+
+		response, err := th.App.GetChanges(teamID, userID, "", 2)
+		require.NoError(t, err)
+		require.Equal(t, changes, response.Changes)
+		require.True(t, response.HasNext)
+		require.Equal(t, changes[1].Cursor().Encode(), response.Cursor)
+
+		cursor := changes[1].Cursor()
+		opts = model.QueryChangesOptions{TeamID: teamID, UserID: userID, IncludeImplicitBoards: true, Cursor: cursor, PerPage: 2}
+		expectGetChanges(false, opts, []*model.Change{}, false)
+
+		response, err = th.App.GetChanges(teamID, userID, cursor.Encode(), 2)
+		require.NoError(t, err)
+		require.Empty(t, response.Changes)
+		require.False(t, response.HasNext)
+		require.Equal(t, cursor.Encode(), response.Cursor)
+	})
+
+	t.Run("the page size defaults when out of range", func(t *testing.T) {
+		opts := model.QueryChangesOptions{TeamID: teamID, UserID: userID, IncludeImplicitBoards: true, PerPage: model.ChangesDefaultPerPage}
+		expectGetChanges(false, opts, []*model.Change{}, false)
+
+		_, err := th.App.GetChanges(teamID, userID, "", model.ChangesMaxPerPage+1)
+		require.NoError(t, err)
+	})
+
+	t.Run("guests only get the changes of the boards they are a member of", func(t *testing.T) {
+		opts := model.QueryChangesOptions{TeamID: teamID, UserID: userID, PerPage: 10}
+		expectGetChanges(true, opts, []*model.Change{}, false)
+
+		_, err := th.App.GetChanges(teamID, userID, "", 10)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := th.App.GetChanges(teamID, userID, "garbage", 10)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
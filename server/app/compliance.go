@@ -16,3 +16,7 @@ func (a *App) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistory
 func (a *App) GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error) {
 	return a.store.GetBlocksComplianceHistory(opts)
 }
+
+func (a *App) GetTimeEntriesForCompliance(opts model.QueryTimeEntriesComplianceOptions) ([]*model.TimeEntry, bool, error) {
+	return a.store.GetTimeEntriesForCompliance(opts)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// GetDigestSettings returns the digest schedule of a user. Users without
+// settings get a daily digest.
+func (a *App) GetDigestSettings(userID string) (*model.DigestSettings, error) {
+	settings, err := a.store.GetDigestSettings(userID)
+	if model.IsErrNotFound(err) {
+		return model.NewDigestSettings(userID), nil
+	}
+	return settings, err
+}
+
+// SetDigestSettings validates and stores the digest schedule of a user.
+func (a *App) SetDigestSettings(settings *model.DigestSettings) (*model.DigestSettings, error) {
+	if err := settings.IsValid(); err != nil {
+		return nil, err
+	}
+
+	// the first digest contains the changes made after the settings are created.
+	settings.LastSentAt = utils.GetMillis()
+
+	if err := a.store.UpsertDigestSettings(settings); err != nil {
+		return nil, err
+	}
+	return a.store.GetDigestSettings(settings.UserID)
+}
+
+// ensureDigestSettings creates the default digest settings of a user that
+// subscribes to a block in digest mode, so that their digest is scheduled.
+func (a *App) ensureDigestSettings(userID string) error {
+	_, err := a.store.GetDigestSettings(userID)
+	if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	settings := model.NewDigestSettings(userID)
+	settings.LastSentAt = utils.GetMillis()
+	return a.store.UpsertDigestSettings(settings)
+}
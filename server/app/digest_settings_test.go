@@ -0,0 +1,94 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDigestSettings(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("user without settings", func(t *testing.T) {
+		th.Store.EXPECT().GetDigestSettings("user-id").Return(nil, model.NewErrNotFound("digest settings"))
+
+		settings, err := th.App.GetDigestSettings("user-id")
+		require.NoError(t, err)
+		require.Equal(t, "user-id", settings.UserID)
+		require.Equal(t, model.DigestFrequencyDaily, settings.Frequency)
+		require.Equal(t, model.DefaultDigestHour, settings.Hour)
+	})
+}
+
+func TestSetDigestSettings(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("valid settings", func(t *testing.T) {
+		settings := &model.DigestSettings{
+			UserID:    "user-id",
+			Frequency: model.DigestFrequencyWeekly,
+			Hour:      17,
+			Weekday:   5,
+		}
+
+		th.Store.EXPECT().UpsertDigestSettings(settings).DoAndReturn(func(s *model.DigestSettings) error {
+			require.NotZero(t, s.LastSentAt)
+			return nil
+		})
+		th.Store.EXPECT().GetDigestSettings("user-id").Return(settings, nil)
+
+		stored, err := th.App.SetDigestSettings(settings)
+		require.NoError(t, err)
+		require.Equal(t, settings, stored)
+	})
+
+	t.Run("invalid hour", func(t *testing.T) {
+		settings := model.NewDigestSettings("user-id")
+		settings.Hour = 25
+
+		_, err := th.App.SetDigestSettings(settings)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestCreateDigestSubscription(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	sub := &model.Subscription{
+		BlockType:      model.TypeCard,
+		BlockID:        "card-id",
+		SubscriberType: model.SubTypeUser,
+		SubscriberID:   "user-id",
+		Digest:         true,
+	}
+
+	t.Run("creates the default settings", func(t *testing.T) {
+		th.Store.EXPECT().GetDigestSettings("user-id").Return(nil, model.NewErrNotFound("digest settings"))
+		th.Store.EXPECT().UpsertDigestSettings(gomock.Any()).DoAndReturn(func(s *model.DigestSettings) error {
+			require.Equal(t, "user-id", s.UserID)
+			require.Equal(t, model.DigestFrequencyDaily, s.Frequency)
+			require.NotZero(t, s.LastSentAt)
+			return nil
+		})
+		th.Store.EXPECT().CreateSubscription(sub).Return(sub, nil)
+
+		_, err := th.App.CreateSubscription(sub)
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps existing settings", func(t *testing.T) {
+		th.Store.EXPECT().GetDigestSettings("user-id").Return(model.NewDigestSettings("user-id"), nil)
+		th.Store.EXPECT().CreateSubscription(sub).Return(sub, nil)
+
+		_, err := th.App.CreateSubscription(sub)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// GetDueDateReminderSettings returns the due date reminder settings of a
+// board. Boards without settings have their reminders disabled.
+func (a *App) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
+	settings, err := a.store.GetDueDateReminderSettings(boardID)
+	if model.IsErrNotFound(err) {
+		return &model.DueDateReminderSettings{
+			BoardID:     boardID,
+			PropertyIDs: []string{},
+			Offsets:     []int64{},
+		}, nil
+	}
+	return settings, err
+}
+
+// SetDueDateReminderSettings validates the due date reminder settings
+// against the board's card properties and stores them.
+func (a *App) SetDueDateReminderSettings(settings *model.DueDateReminderSettings, userID string) (*model.DueDateReminderSettings, error) {
+	board, err := a.store.GetBoard(settings.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := settings.IsValid(schema); err != nil {
+		return nil, err
+	}
+	settings.ModifiedBy = userID
+
+	if err := a.store.UpsertDueDateReminderSettings(settings); err != nil {
+		return nil, err
+	}
+	return a.store.GetDueDateReminderSettings(settings.BoardID)
+}
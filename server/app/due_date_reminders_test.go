@@ -0,0 +1,69 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDueDateReminderSettings(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("board without settings", func(t *testing.T) {
+		th.Store.EXPECT().GetDueDateReminderSettings("board-id").Return(nil, model.NewErrNotFound("due date reminder settings"))
+
+		settings, err := th.App.GetDueDateReminderSettings("board-id")
+		require.NoError(t, err)
+		require.Equal(t, "board-id", settings.BoardID)
+		require.False(t, settings.Enabled)
+	})
+}
+
+func TestSetDueDateReminderSettings(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-id",
+		CardProperties: []map[string]any{
+			{"id": "due-id", "name": "Due", "type": "date"},
+			{"id": "owner-id", "name": "Owner", "type": "person"},
+		},
+	}
+
+	t.Run("valid settings", func(t *testing.T) {
+		settings := &model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{-1440, 0},
+		}
+
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().UpsertDueDateReminderSettings(settings).DoAndReturn(func(s *model.DueDateReminderSettings) error {
+			require.Equal(t, "user-id", s.ModifiedBy)
+			return nil
+		})
+		th.Store.EXPECT().GetDueDateReminderSettings("board-id").Return(settings, nil)
+
+		_, err := th.App.SetDueDateReminderSettings(settings, "user-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("not a date property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+
+		_, err := th.App.SetDueDateReminderSettings(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"owner-id"},
+			Offsets:     []int64{0},
+		}, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
@@ -122,6 +122,33 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 		}
 	}
 
+	// write the links starting from the board's cards, links to cards
+	// of boards that are not in the archive are dropped on import
+	cardLinks, err := a.store.GetCardLinksForBoard(board.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, cardLink := range cardLinks {
+		if cardLink.SourceBoardID != board.ID {
+			continue
+		}
+		if err = a.writeArchiveCardLinkLine(w, cardLink); err != nil {
+			return err
+		}
+	}
+
+	timeEntries, err := a.store.GetTimeEntriesForBoard(board.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, timeEntry := range timeEntries {
+		if err = a.writeArchiveTimeEntryLine(w, timeEntry); err != nil {
+			return err
+		}
+	}
+
 	// write the files
 	for _, filename := range files {
 		if err := a.writeArchiveFile(zw, filename, board.ID, opt); err != nil {
@@ -156,6 +183,56 @@ func (a *App) writeArchiveBoardMemberLine(w io.Writer, boardMember *model.BoardM
 	return err
 }
 
+// writeArchiveCardLinkLine writes a single card link to the archive.
+func (a *App) writeArchiveCardLinkLine(w io.Writer, cardLink *model.CardLink) error {
+	cl, err := json.Marshal(&cardLink)
+	if err != nil {
+		return err
+	}
+	line := model.ArchiveLine{
+		Type: "cardLink",
+		Data: cl,
+	}
+
+	cl, err = json.Marshal(&line)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(cl)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(newline)
+	return err
+}
+
+// writeArchiveTimeEntryLine writes a single time entry to the archive.
+func (a *App) writeArchiveTimeEntryLine(w io.Writer, timeEntry *model.TimeEntry) error {
+	te, err := json.Marshal(&timeEntry)
+	if err != nil {
+		return err
+	}
+	line := model.ArchiveLine{
+		Type: "timeEntry",
+		Data: te,
+	}
+
+	te, err = json.Marshal(&line)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(te)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(newline)
+	return err
+}
+
 // writeArchiveBlockLine writes a single block to the archive.
 func (a *App) writeArchiveBlockLine(w io.Writer, block *model.Block) error {
 	b, err := json.Marshal(&block)
@@ -0,0 +1,326 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	exportJobsMutexName   = "Boards_exportJobs"
+	exportJobsLockTimeout = 30 * time.Second
+	exportJobsBatchSize   = 10
+	exportJobsMaxPerTeam  = 50
+
+	// exportJobsRunDuration is how long a run keeps writing chunks, the
+	// jobs that are not complete then are resumed by the next run.
+	exportJobsRunDuration = 5 * time.Minute
+
+	// exportJobChunkSize is the number of boards written per chunk.
+	exportJobChunkSize = 20
+)
+
+var errExportJobNotComplete = errors.New("export job is not complete")
+
+// CreateExportJob queues an export of the boards of a team that the user
+// can view, which is run in the background by ProcessExportJobs.
+func (a *App) CreateExportJob(teamID string, create *model.ExportJobCreate, userID string) (*model.ExportJob, error) {
+	if err := create.IsValid(); err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	job := &model.ExportJob{
+		ID:           utils.NewID(utils.IDTypeNone),
+		TeamID:       teamID,
+		Status:       model.ExportJobPending,
+		ChangedSince: create.ChangedSince,
+		CreatedBy:    userID,
+		CreateAt:     now,
+		UpdateAt:     now,
+	}
+	if err := a.store.InsertExportJob(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (a *App) GetExportJob(jobID string) (*model.ExportJob, error) {
+	return a.store.GetExportJob(jobID)
+}
+
+func (a *App) GetExportJobsForTeam(teamID string) ([]*model.ExportJob, error) {
+	return a.store.GetExportJobsForTeam(teamID, exportJobsMaxPerTeam)
+}
+
+// GetExportJobArchive returns the archive written by a successful job.
+func (a *App) GetExportJobArchive(job *model.ExportJob) (ReadCloseSeeker, error) {
+	if job.Status != model.ExportJobSuccess {
+		return nil, model.NewErrBadRequest(errExportJobNotComplete.Error())
+	}
+	return a.filesBackend.Reader(exportJobArchivePath(job))
+}
+
+// ProcessExportJobs writes the chunks of the pending and running export
+// jobs, the oldest first. In a cluster, a mutex makes a single server
+// process them at a time.
+func (a *App) ProcessExportJobs() {
+	if a.newMutexFn != nil {
+		mutex, err := a.newMutexFn(exportJobsMutexName)
+		if err != nil {
+			a.logger.Error("Cannot create export jobs mutex", mlog.Err(err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), exportJobsLockTimeout)
+		defer cancel()
+		if err := mutex.LockWithContext(ctx); err != nil {
+			a.logger.Debug("Export jobs are being processed by another server")
+			return
+		}
+		defer mutex.Unlock()
+	}
+
+	jobs, err := a.store.GetActiveExportJobs(exportJobsBatchSize)
+	if err != nil {
+		a.logger.Error("Cannot get active export jobs", mlog.Err(err))
+		return
+	}
+
+	deadline := time.Now().Add(exportJobsRunDuration)
+	for _, job := range jobs {
+		if time.Now().After(deadline) {
+			return
+		}
+		if err := a.runExportJob(job, deadline); err != nil {
+			a.logger.Error("Export job failed",
+				mlog.String("jobID", job.ID),
+				mlog.String("teamID", job.TeamID),
+				mlog.Err(err),
+			)
+			a.failExportJob(job, err)
+		}
+	}
+}
+
+// runExportJob writes the chunks of a job until all its boards are written
+// or the deadline passes. The progress is saved after each chunk, so that
+// the job resumes from there if the server stops.
+func (a *App) runExportJob(job *model.ExportJob, deadline time.Time) error {
+	if job.Status == model.ExportJobPending {
+		teamBoardIDs, err := a.store.GetBoardIDsChangedSince(job.TeamID, job.ChangedSince)
+		if err != nil {
+			return fmt.Errorf("cannot get boards to export: %w", err)
+		}
+
+		// only the boards the creator of the job can view are exported
+		boardIDs := make([]string, 0, len(teamBoardIDs))
+		for _, boardID := range teamBoardIDs {
+			if a.permissions.HasPermissionToBoard(job.CreatedBy, boardID, model.PermissionViewBoard) {
+				boardIDs = append(boardIDs, boardID)
+			}
+		}
+		job.Status = model.ExportJobRunning
+		job.BoardIDs = boardIDs
+		job.BoardsTotal = len(boardIDs)
+		if err := a.updateExportJob(job); err != nil {
+			return err
+		}
+	}
+
+	for job.BoardsDone < len(job.BoardIDs) {
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		end := min(job.BoardsDone+exportJobChunkSize, len(job.BoardIDs))
+		if err := a.writeExportJobChunk(job, job.BoardIDs[job.BoardsDone:end]); err != nil {
+			return fmt.Errorf("cannot write chunk %d: %w", job.Chunks, err)
+		}
+		job.BoardsDone = end
+		job.Chunks++
+		if err := a.updateExportJob(job); err != nil {
+			return err
+		}
+	}
+
+	return a.completeExportJob(job)
+}
+
+// writeExportJobChunk writes the boards to the next chunk of the job. A
+// chunk written again after a restart replaces the previous one.
+func (a *App) writeExportJobChunk(job *model.ExportJob, boardIDs []string) error {
+	_, err := a.writeToFileStore(exportJobChunkPath(job, job.Chunks), func(w io.Writer) error {
+		zw := zip.NewWriter(w)
+		opt := model.ExportArchiveOptions{TeamID: job.TeamID}
+		for _, boardID := range boardIDs {
+			board, err := a.store.GetBoard(boardID)
+			if model.IsErrNotFound(err) {
+				// deleted since the job started
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := a.writeArchiveBoard(zw, *board, opt); err != nil {
+				return fmt.Errorf("cannot export board %s: %w", boardID, err)
+			}
+		}
+		return zw.Close()
+	})
+	return err
+}
+
+// completeExportJob joins the chunks of a job in the archive, which can be
+// imported like the archives exported by boards, and removes them.
+func (a *App) completeExportJob(job *model.ExportJob) error {
+	size, err := a.writeToFileStore(exportJobArchivePath(job), func(w io.Writer) error {
+		zw := zip.NewWriter(w)
+		if err := a.writeArchiveVersion(zw); err != nil {
+			return err
+		}
+		for chunk := 0; chunk < job.Chunks; chunk++ {
+			if err := a.copyExportJobChunk(zw, exportJobChunkPath(job, chunk)); err != nil {
+				return fmt.Errorf("cannot copy chunk %d: %w", chunk, err)
+			}
+		}
+		return zw.Close()
+	})
+	if err != nil {
+		return err
+	}
+	a.removeExportJobChunks(job)
+
+	now := utils.GetMillis()
+	job.Status = model.ExportJobSuccess
+	job.Size = size
+	job.CompleteAt = now
+
+	a.logger.Debug("Export job complete",
+		mlog.String("jobID", job.ID),
+		mlog.String("teamID", job.TeamID),
+		mlog.Int("boards", job.BoardsTotal),
+		mlog.Int("size", size),
+	)
+	return a.updateExportJob(job)
+}
+
+// copyExportJobChunk copies the entries of a chunk to the archive, without
+// compressing them again.
+func (a *App) copyExportJobChunk(zw *zip.Writer, chunkPath string) error {
+	reader, err := a.filesBackend.Reader(chunkPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	zr, err := newZipReader(reader)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range zr.File {
+		if err := zw.Copy(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) failExportJob(job *model.ExportJob, jobErr error) {
+	a.removeExportJobChunks(job)
+
+	job.Status = model.ExportJobFailed
+	job.Error = jobErr.Error()
+	job.CompleteAt = utils.GetMillis()
+	if err := a.updateExportJob(job); err != nil {
+		a.logger.Error("Cannot update failed export job", mlog.String("jobID", job.ID), mlog.Err(err))
+	}
+}
+
+func (a *App) removeExportJobChunks(job *model.ExportJob) {
+	for chunk := 0; chunk < job.Chunks; chunk++ {
+		if err := a.filesBackend.RemoveFile(exportJobChunkPath(job, chunk)); err != nil {
+			a.logger.Warn("Cannot remove export job chunk",
+				mlog.String("jobID", job.ID),
+				mlog.Int("chunk", chunk),
+				mlog.Err(err),
+			)
+		}
+	}
+}
+
+func (a *App) updateExportJob(job *model.ExportJob) error {
+	job.UpdateAt = utils.GetMillis()
+	return a.store.UpdateExportJob(job)
+}
+
+// writeToFileStore streams what `write` writes to a file of the file
+// store, and returns the size of the file.
+func (a *App) writeToFileStore(path string, write func(w io.Writer) error) (int64, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(write(pw))
+	}()
+
+	size, err := a.filesBackend.WriteFile(pr, path)
+	// unblock the writer if the file store stopped reading
+	pr.CloseWithError(io.ErrClosedPipe)
+	return size, err
+}
+
+func exportJobDir(job *model.ExportJob) string {
+	return filepath.Join("boards", "exports", job.TeamID, job.ID)
+}
+
+func exportJobChunkPath(job *model.ExportJob, chunk int) string {
+	return filepath.Join(exportJobDir(job), fmt.Sprintf("chunk-%05d.zip", chunk))
+}
+
+func exportJobArchivePath(job *model.ExportJob) string {
+	return filepath.Join(exportJobDir(job), "export.boardarchive")
+}
+
+// newZipReader opens a zip file of the file store, reading its entries at
+// their offsets.
+func newZipReader(reader io.ReadSeeker) (*zip.Reader, error) {
+	size, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	readerAt, ok := reader.(io.ReaderAt)
+	if !ok {
+		readerAt = seekerReaderAt{reader}
+	}
+	return zip.NewReader(readerAt, size)
+}
+
+// seekerReaderAt reads a seekable reader at offsets, so that the zip files
+// of the file store can be read without loading them in memory.
+type seekerReaderAt struct {
+	r io.ReadSeeker
+}
+
+func (s seekerReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := s.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(s.r, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// memFileBackend is a file store keeping the files in memory.
+type memFileBackend struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemFileBackend() *memFileBackend {
+	return &memFileBackend{files: map[string][]byte{}}
+}
+
+type memFileReader struct {
+	*bytes.Reader
+}
+
+func (memFileReader) Close() error { return nil }
+
+func (b *memFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.files[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return memFileReader{bytes.NewReader(data)}, nil
+}
+
+func (b *memFileBackend) FileExists(path string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.files[path]
+	return ok, nil
+}
+
+func (b *memFileBackend) CopyFile(oldPath, newPath string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.files[oldPath]
+	if !ok {
+		return os.ErrNotExist
+	}
+	b.files[newPath] = data
+	return nil
+}
+
+func (b *memFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.CopyFile(oldPath, newPath); err != nil {
+		return err
+	}
+	return b.RemoveFile(oldPath)
+}
+
+func (b *memFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	data, err := io.ReadAll(fr)
+	if err != nil {
+		return 0, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[path] = data
+	return int64(len(data)), nil
+}
+
+func (b *memFileBackend) RemoveFile(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.files, path)
+	return nil
+}
+
+func (b *memFileBackend) paths() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	paths := make([]string, 0, len(b.files))
+	for path := range b.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (th *TestHelper) expectExportJobBoard(boardID string) {
+	th.Store.EXPECT().GetBoard(boardID).Return(&model.Board{ID: boardID, TeamID: importTestTeamID, Title: boardID}, nil).AnyTimes()
+	th.Store.EXPECT().GetBlocksForBoard(boardID).Return([]*model.Block{}, nil)
+	th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
+	th.Store.EXPECT().GetCardLinksForBoard(boardID).Return([]*model.CardLink{}, nil)
+	th.Store.EXPECT().GetTimeEntriesForBoard(boardID).Return([]*model.TimeEntry{}, nil)
+}
+
+func archiveEntries(t *testing.T, files *memFileBackend, path string) []string {
+	reader, err := files.Reader(path)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	names := make([]string, 0, len(zr.File))
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestCreateExportJob(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("queues the job", func(t *testing.T) {
+		th.Store.EXPECT().InsertExportJob(gomock.Any()).Return(nil)
+
+		job, err := th.App.CreateExportJob(importTestTeamID, &model.ExportJobCreate{ChangedSince: 1000}, "user-id")
+		require.NoError(t, err)
+		assert.NotEmpty(t, job.ID)
+		assert.Equal(t, model.ExportJobPending, job.Status)
+		assert.Equal(t, int64(1000), job.ChangedSince)
+		assert.Equal(t, "user-id", job.CreatedBy)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := th.App.CreateExportJob(importTestTeamID, &model.ExportJobCreate{ChangedSince: -1}, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestProcessExportJobs(t *testing.T) {
+	t.Run("exports the boards of a pending job", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		job := &model.ExportJob{ID: "job-id", TeamID: importTestTeamID, Status: model.ExportJobPending, CreatedBy: "user-id"}
+		th.Store.EXPECT().GetActiveExportJobs(exportJobsBatchSize).Return([]*model.ExportJob{job}, nil)
+		th.Store.EXPECT().GetBoardIDsChangedSince(importTestTeamID, int64(0)).Return([]string{"board-1", "board-2", "private-board"}, nil)
+		th.expectBoardEditor("user-id", "board-1", importTestTeamID)
+		th.expectBoardEditor("user-id", "board-2", importTestTeamID)
+		// the boards the creator can't view are not exported
+		th.PermStore.EXPECT().GetBoard("private-board").Return(&model.Board{ID: "private-board", TeamID: importTestTeamID}, nil)
+		th.API.EXPECT().HasPermissionToTeam("user-id", importTestTeamID, model.PermissionViewTeam).Return(true)
+		th.PermStore.EXPECT().GetMemberForBoard("private-board", "user-id").Return(nil, model.NewErrNotFound("member"))
+		th.expectExportJobBoard("board-1")
+		th.expectExportJobBoard("board-2")
+		th.Store.EXPECT().UpdateExportJob(job).Return(nil).MinTimes(1)
+
+		th.App.ProcessExportJobs()
+
+		require.Equal(t, model.ExportJobSuccess, job.Status, job.Error)
+		assert.Equal(t, 2, job.BoardsTotal)
+		assert.Equal(t, 2, job.BoardsDone)
+		assert.Equal(t, 100, job.Progress())
+		assert.NotZero(t, job.CompleteAt)
+
+		// the chunks are removed once joined
+		assert.Equal(t, []string{exportJobArchivePath(job)}, files.paths())
+		assert.Equal(t, []string{"version.json", "board-1/board.jsonl", "board-2/board.jsonl"}, archiveEntries(t, files, exportJobArchivePath(job)))
+
+		reader, err := th.App.GetExportJobArchive(job)
+		require.NoError(t, err)
+		size, err := reader.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, job.Size, size)
+	})
+
+	t.Run("resumes a running job after the last chunk", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		job := &model.ExportJob{
+			ID:          "job-id",
+			TeamID:      importTestTeamID,
+			Status:      model.ExportJobRunning,
+			BoardIDs:    []string{"board-1", "board-2"},
+			BoardsTotal: 2,
+		}
+		th.expectExportJobBoard("board-1")
+		require.NoError(t, th.App.writeExportJobChunk(job, []string{"board-1"}))
+		job.BoardsDone = 1
+		job.Chunks = 1
+
+		th.Store.EXPECT().GetActiveExportJobs(exportJobsBatchSize).Return([]*model.ExportJob{job}, nil)
+		th.expectExportJobBoard("board-2")
+		th.Store.EXPECT().UpdateExportJob(job).Return(nil).MinTimes(1)
+
+		th.App.ProcessExportJobs()
+
+		require.Equal(t, model.ExportJobSuccess, job.Status, job.Error)
+		assert.Equal(t, 2, job.Chunks)
+		assert.Equal(t, []string{"version.json", "board-1/board.jsonl", "board-2/board.jsonl"}, archiveEntries(t, files, exportJobArchivePath(job)))
+	})
+
+	t.Run("fails the job on error", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		job := &model.ExportJob{ID: "job-id", TeamID: importTestTeamID, Status: model.ExportJobPending, CreatedBy: "user-id"}
+		th.Store.EXPECT().GetActiveExportJobs(exportJobsBatchSize).Return([]*model.ExportJob{job}, nil)
+		th.Store.EXPECT().GetBoardIDsChangedSince(importTestTeamID, int64(0)).Return([]string{"board-1"}, nil)
+		th.expectBoardEditor("user-id", "board-1", importTestTeamID)
+		th.Store.EXPECT().GetBoard("board-1").Return(nil, errors.New("db error"))
+		th.Store.EXPECT().UpdateExportJob(job).Return(nil).MinTimes(1)
+
+		th.App.ProcessExportJobs()
+
+		assert.Equal(t, model.ExportJobFailed, job.Status)
+		assert.Contains(t, job.Error, "db error")
+		assert.Empty(t, files.paths())
+
+		_, err := th.App.GetExportJobArchive(job)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// exportTablePageSize is the number of cards fetched at once while
+// exporting a board, so that large boards are streamed.
+const exportTablePageSize = 500
+
+// tableWriter writes the rows of an exported table.
+type tableWriter interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// tableColumn is a column of an exported table.
+type tableColumn struct {
+	name  string
+	value func(card *model.Block) string
+}
+
+// ExportBoardTable writes the cards of a board as a table with one row per
+// card and one column per property. If a view is given, its filter, sort
+// and visible properties are honored. Cards are fetched and written in
+// pages, except for views sorted manually, which need all the cards.
+func (a *App) ExportBoardTable(w io.Writer, opts model.ExportTableOptions) error {
+	if err := opts.IsValid(); err != nil {
+		return err
+	}
+
+	board, err := a.store.GetBoard(opts.BoardID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	var view *model.View
+	queryOpts := model.QueryCardsOptions{
+		BoardID:     board.ID,
+		SortOptions: viewSortOptions(&model.View{}),
+	}
+	if opts.ViewID != "" {
+		if view, err = a.getView(opts.ViewID); err != nil {
+			return err
+		}
+		if view.BoardID != board.ID {
+			return model.NewErrNotFound(fmt.Sprintf("view ID=%s on BoardID=%s", opts.ViewID, board.ID))
+		}
+		queryOpts = viewQueryCardsOptions(view)
+	}
+
+	columns := a.tableColumns(schema, view)
+
+	var tw tableWriter
+	if opts.Format == model.TableExportXLSX {
+		tw, err = newXLSXTableWriter(w, board.Title)
+	} else {
+		tw, err = newCSVTableWriter(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.name)
+	}
+	if err := tw.WriteRow(header); err != nil {
+		return err
+	}
+
+	writeCards := func(cards []*model.Block) error {
+		if err := model.ResolveRollups(board, cards, a.store, a.canViewRelatedBoard(opts.UserID)); err != nil {
+			return err
+		}
+		for _, card := range cards {
+			row := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row = append(row, column.value(card))
+			}
+			if err := tw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if view != nil && len(view.SortOptions) == 0 {
+		// the manual order of the view is applied to all the cards at once
+		cards, _, err := a.store.QueryCards(queryOpts)
+		if err != nil {
+			return err
+		}
+		less := manualOrderLess(view.CardOrder)
+		sort.SliceStable(cards, func(i, j int) bool {
+			return less(cards[i].ID, cards[j].ID)
+		})
+		if err := writeCards(cards); err != nil {
+			return err
+		}
+		return tw.Close()
+	}
+
+	queryOpts.PerPage = exportTablePageSize
+	for {
+		cards, nextCursor, err := a.store.QueryCards(queryOpts)
+		if err != nil {
+			return err
+		}
+		if err := writeCards(cards); err != nil {
+			return err
+		}
+		if nextCursor == "" {
+			break
+		}
+		if queryOpts.Cursor, err = model.DecodeCardsCursor(nextCursor); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// tableColumns returns the title column followed by a column per property,
+// in the order of the board. If a view is given, only its visible properties
+// are exported.
+func (a *App) tableColumns(schema model.PropSchema, view *model.View) []tableColumn {
+	props := make([]model.PropDef, 0, len(schema))
+	for _, pd := range schema {
+		if view == nil || slices.Contains(view.VisiblePropertyIDs, pd.ID) {
+			props = append(props, pd)
+		}
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].Index < props[j].Index })
+
+	resolver := newCachingUserResolver(a.store)
+	userName := func(userID string) string {
+		user, _ := resolver.GetUserByID(userID)
+		if user == nil {
+			return userID
+		}
+		return user.Username
+	}
+
+	columns := make([]tableColumn, 0, len(props)+1)
+	columns = append(columns, tableColumn{
+		name:  "Title",
+		value: func(card *model.Block) string { return card.Title },
+	})
+
+	for _, prop := range props {
+		pd := prop
+		column := tableColumn{name: pd.Name}
+		switch pd.Type {
+		case "createdTime":
+			column.value = func(card *model.Block) string { return formatExportTime(card.CreateAt) }
+		case "updatedTime":
+			column.value = func(card *model.Block) string { return formatExportTime(card.UpdateAt) }
+		case "createdBy":
+			column.value = func(card *model.Block) string { return userName(card.CreatedBy) }
+		case "updatedBy":
+			column.value = func(card *model.Block) string { return userName(card.ModifiedBy) }
+		default:
+			column.value = func(card *model.Block) string {
+				properties, _ := card.Fields[model.BlockFieldProperties].(map[string]any)
+				v, ok := properties[pd.ID]
+				if !ok || v == nil {
+					return ""
+				}
+				if pd.Type == "select" || pd.Type == "multiSelect" {
+					return optionValues(pd, v)
+				}
+				value, err := pd.GetValue(v, resolver)
+				if err != nil {
+					// e.g. the option was deleted
+					return ""
+				}
+				return value
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// optionValues returns the values of the options of a select property as
+// they are displayed, skipping the options that were deleted.
+func optionValues(pd model.PropDef, v interface{}) string {
+	var ids []interface{}
+	switch value := v.(type) {
+	case string:
+		ids = []interface{}{value}
+	case []interface{}:
+		ids = value
+	}
+
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		optionID, _ := id.(string)
+		if opt, ok := pd.Options[optionID]; ok {
+			values = append(values, opt.Value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func formatExportTime(millis int64) string {
+	return utils.GetTimeForMillis(millis).Format("January 02, 2006 15:04")
+}
+
+// cachingUserResolver caches the users resolved while exporting a board.
+// Users that cannot be fetched are resolved as nil, so that their id is
+// exported instead.
+type cachingUserResolver struct {
+	resolver model.PropValueResolver
+	users    map[string]*model.User
+}
+
+func newCachingUserResolver(resolver model.PropValueResolver) *cachingUserResolver {
+	return &cachingUserResolver{
+		resolver: resolver,
+		users:    map[string]*model.User{},
+	}
+}
+
+func (r *cachingUserResolver) GetUserByID(userID string) (*model.User, error) {
+	if user, ok := r.users[userID]; ok {
+		return user, nil
+	}
+
+	user, err := r.resolver.GetUserByID(userID)
+	if err != nil {
+		user = nil
+	}
+	r.users[userID] = user
+	return user, nil
+}
+
+// csvTableWriter writes a table as CSV. Spreadsheets detect the encoding
+// with the byte order mark.
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func newCSVTableWriter(w io.Writer) (*csvTableWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvTableWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (t *csvTableWriter) WriteRow(cells []string) error {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, escapeCSVFormula(cell))
+	}
+	return t.w.Write(escaped)
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// escapeCSVFormula prevents spreadsheets from evaluating cells that start
+// like a formula.
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+func TestExportBoardTable(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID:    utils.NewID(utils.IDTypeBoard),
+		Title: "Roadmap: Q3/Q4",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "notes",
+				"name": "Notes",
+				"type": "text",
+			},
+			{
+				"id":   "owner",
+				"name": "Owner",
+				"type": "person",
+			},
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do", "color": ""},
+					map[string]interface{}{"id": "done", "value": "Done", "color": ""},
+				},
+			},
+		},
+	}
+
+	newCard := func(id string, properties map[string]interface{}) *model.Block {
+		return &model.Block{
+			ID:      id,
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Title:   id,
+			Fields:  map[string]interface{}{"properties": properties},
+		}
+	}
+	card1 := newCard("card1", map[string]interface{}{"status": "todo", "owner": "user1", "notes": "=1+1"})
+	card2 := newCard("card2", map[string]interface{}{"status": "deleted-option", "notes": "a, \"quoted\"\nnote"})
+	card3 := newCard("card3", map[string]interface{}{"status": "done", "owner": "deleted-user"})
+
+	readCSV := func(t *testing.T, data []byte) [][]string {
+		bom := "\ufeff"
+		require.True(t, bytes.HasPrefix(data, []byte(bom)))
+		records, err := csv.NewReader(bytes.NewReader(data[len(bom):])).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	t.Run("all cards as csv, in pages", func(t *testing.T) {
+		cursor := &model.CardsCursor{Keys: []interface{}{"card2"}, ID: "card2"}
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+			PerPage:     exportTablePageSize,
+		}).Return([]*model.Block{card1, card2}, cursor.Encode(), nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+			PerPage:     exportTablePageSize,
+			Cursor:      cursor,
+		}).Return([]*model.Block{card3}, "", nil)
+		th.Store.EXPECT().GetUserByID("user1").Return(&model.User{ID: "user1", Username: "alice"}, nil)
+		th.Store.EXPECT().GetUserByID("deleted-user").Return(nil, model.NewErrNotFound("deleted-user"))
+
+		var buf bytes.Buffer
+		err := th.App.ExportBoardTable(&buf, model.ExportTableOptions{
+			BoardID: board.ID,
+			Format:  model.TableExportCSV,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{
+			{"Title", "Notes", "Owner", "Status"},
+			{"card1", "'=1+1", "alice", "To do"},
+			{"card2", "a, \"quoted\"\nnote", "", ""},
+			{"card3", "", "deleted-user", "Done"},
+		}, readCSV(t, buf.Bytes()))
+	})
+
+	t.Run("view as csv", func(t *testing.T) {
+		view := &model.Block{
+			ID:      utils.NewID(utils.IDTypeView),
+			BoardID: board.ID,
+			Type:    model.TypeView,
+			Fields: map[string]interface{}{
+				"viewType":           "table",
+				"sortOptions":        []interface{}{},
+				"visiblePropertyIds": []interface{}{"status"},
+				"cardOrder":          []interface{}{"card3", "card1"},
+				"filter": map[string]interface{}{
+					"operation": "and",
+					"filters": []interface{}{
+						map[string]interface{}{"propertyId": "status", "condition": "isNotEmpty", "values": []interface{}{}},
+					},
+				},
+			},
+		}
+
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlock(view.ID).Return(view, nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID: board.ID,
+			Filter: &model.FilterGroup{
+				Operation: model.FilterOperationAnd,
+				Filters: []model.FilterGroupItem{
+					{Clause: &model.FilterClause{PropertyID: "status", Condition: model.FilterConditionIsNotEmpty, Values: []string{}}},
+				},
+			},
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+		}).Return([]*model.Block{card1, card3}, "", nil)
+
+		var buf bytes.Buffer
+		err := th.App.ExportBoardTable(&buf, model.ExportTableOptions{
+			BoardID: board.ID,
+			ViewID:  view.ID,
+			Format:  model.TableExportCSV,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{
+			{"Title", "Status"},
+			{"card3", "Done"},
+			{"card1", "To do"},
+		}, readCSV(t, buf.Bytes()))
+	})
+
+	t.Run("view of another board", func(t *testing.T) {
+		view := &model.Block{
+			ID:      utils.NewID(utils.IDTypeView),
+			BoardID: utils.NewID(utils.IDTypeBoard),
+			Type:    model.TypeView,
+			Fields:  map[string]interface{}{"viewType": "table"},
+		}
+
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlock(view.ID).Return(view, nil)
+
+		err := th.App.ExportBoardTable(io.Discard, model.ExportTableOptions{
+			BoardID: board.ID,
+			ViewID:  view.ID,
+			Format:  model.TableExportCSV,
+		})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("all cards as xlsx", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+			PerPage:     exportTablePageSize,
+		}).Return([]*model.Block{card2}, "", nil)
+
+		var buf bytes.Buffer
+		err := th.App.ExportBoardTable(&buf, model.ExportTableOptions{
+			BoardID: board.ID,
+			Format:  model.TableExportXLSX,
+		})
+		require.NoError(t, err)
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		files := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			files[f.Name] = string(data)
+		}
+
+		require.Contains(t, files, "[Content_Types].xml")
+		require.Contains(t, files, "_rels/.rels")
+		require.Contains(t, files, "xl/_rels/workbook.xml.rels")
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Roadmap Q3Q4"`)
+
+		sheet := files["xl/worksheets/sheet1.xml"]
+		assert.Contains(t, sheet, `<c r="D1" t="inlineStr"><is><t xml:space="preserve">Status</t></is></c>`)
+		assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">a, &#34;quoted&#34;&#xA;note</t></is></c>`)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		err := th.App.ExportBoardTable(io.Discard, model.ExportTableOptions{
+			BoardID: board.ID,
+			Format:  "pdf",
+		})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	assert.Equal(t, "Roadmap Q3Q4", xlsxSheetName("Roadmap: Q3/Q4"))
+	assert.Equal(t, "Cards", xlsxSheetName("[?]"))
+	assert.Equal(t, "Cards", xlsxSheetName(""))
+	assert.Len(t, []rune(xlsxSheetName("a very long board title that does not fit")), 31)
+}
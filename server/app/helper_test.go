@@ -39,7 +39,7 @@ func SetupTestHelper(t *testing.T) (*TestHelper, func()) {
 	auth := auth.New(&cfg, store, nil)
 	logger, _ := mlog.NewLogger()
 	wsserver := ws.NewServer(auth, logger, store)
-	webhook := webhook.NewClient(&cfg, logger)
+	webhook := webhook.NewClient(&cfg, store, logger)
 	metricsService := metrics.NewMetrics(metrics.InstanceInfo{})
 
 	mockStore := permissionsMocks.NewMockStore(ctrl)
@@ -67,7 +67,7 @@ func (a *App) validateArchiveBoards(r io.Reader, opt model.ImportArchiveOptions)
 	br := bufio.NewReader(r)
 	peek, err := br.Peek(len(legacyFileBegin))
 	if err == nil && string(peek) == legacyFileBegin {
-		bab, _, parseErr := a.parseBoardsAndBlocks(br, opt)
+		bab, _, _, _, parseErr := a.parseBoardsAndBlocks(br, opt)
 		if parseErr != nil {
 			return parseErr
 		}
@@ -89,7 +89,7 @@ func (a *App) validateArchiveBoards(r io.Reader, opt model.ImportArchiveOptions)
 			continue
 		}
 
-		bab, _, parseErr := a.parseBoardsAndBlocks(zr, opt)
+		bab, _, _, _, parseErr := a.parseBoardsAndBlocks(zr, opt)
 		if parseErr != nil {
 			return fmt.Errorf("cannot import board %s: %w", path.Clean(dir), parseErr)
 		}
@@ -114,12 +114,15 @@ func (a *App) importArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 
 	boardMap := make(map[string]*model.Board) // maps old board ids to new
 	fileMap := make(map[string]string)        // maps old fileIds to new
+	cardMap := make(map[string]*model.Block)  // maps old card ids to new cards
+	var cardLinks []*model.CardLink
 
 	for {
 		hdr, err := zr.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				a.fixImagesAttachments(boardMap, fileMap, opt.TeamID, opt.ModifiedBy)
+				a.importCardLinks(cardLinks, cardMap, opt.ModifiedBy)
 				a.logger.Debug("import archive - done", mlog.Int("boards_imported", len(boardMap)))
 				return nil
 			}
@@ -141,11 +144,15 @@ func (a *App) importArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 				return model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
 			}
 		case "board.jsonl":
-			board, err := a.ImportBoardJSONL(zr, opt)
+			board, boardCardLinks, boardCardMap, err := a.importBoardJSONL(zr, opt)
 			if err != nil {
 				return fmt.Errorf("cannot import board %s: %w", dir, err)
 			}
 			boardMap[dir] = board
+			cardLinks = append(cardLinks, boardCardLinks...)
+			for oldID, card := range boardCardMap {
+				cardMap[oldID] = card
+			}
 		default:
 			// import file/image;  dir is the old board id
 
@@ -246,8 +253,8 @@ func (a *App) fixImagesAttachments(boardMap map[string]*model.Board, fileMap map
 }
 
 // parseBoardsAndBlocks parses a JSONL file describing one board into its boards,
-// blocks, and members without persisting anything.
-func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+// blocks, members, card links and time entries without persisting anything.
+func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions) (*model.BoardsAndBlocks, []*model.BoardMember, []*model.CardLink, []*model.TimeEntry, error) {
 	// TODO: Stream this once `model.GenerateBlockIDs` can take a stream of blocks.
 	//       We don't want to load the whole file in memory, even though it's a single board.
 	boardsAndBlocks := &model.BoardsAndBlocks{
@@ -261,12 +268,14 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 	now := utils.GetMillis()
 	var boardID string
 	var boardMembers []*model.BoardMember
+	var cardLinks []*model.CardLink
+	var timeEntries []*model.TimeEntry
 
 	lineNum := 1
 	firstLine := true
 	for scanner.Scan() {
 		if lineReader.N <= 0 {
-			return nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, errSizeLimitExceeded)
+			return nil, nil, nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, errSizeLimitExceeded)
 		}
 
 		line := bytes.TrimSpace(scanner.Bytes())
@@ -282,7 +291,7 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 			if !skip {
 				var archiveLine model.ArchiveLine
 				if err := json.Unmarshal(line, &archiveLine); err != nil {
-					return nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, err)
+					return nil, nil, nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, err)
 				}
 
 				// first line must be a board
@@ -294,13 +303,13 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 				case "board":
 					var board model.Board
 					if err2 := json.Unmarshal(archiveLine.Data, &board); err2 != nil {
-						return nil, nil, fmt.Errorf("invalid board in archive line %d: %w", lineNum, err2)
+						return nil, nil, nil, nil, fmt.Errorf("invalid board in archive line %d: %w", lineNum, err2)
 					}
 					board.ModifiedBy = userID
 					board.UpdateAt = now
 					board.TeamID = opt.TeamID
 					if err := a.validateBoardForImport(userID, opt.TeamID, &board); err != nil {
-						return nil, nil, err
+						return nil, nil, nil, nil, err
 					}
 					boardsAndBlocks.Boards = append(boardsAndBlocks.Boards, &board)
 					boardID = board.ID
@@ -308,26 +317,26 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 					// legacy archives encoded boards as blocks; we need to convert them to real boards.
 					var block *model.Block
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
-						return nil, nil, fmt.Errorf("invalid board block in archive line %d: %w", lineNum, err2)
+						return nil, nil, nil, nil, fmt.Errorf("invalid board block in archive line %d: %w", lineNum, err2)
 					}
 					block.ModifiedBy = userID
 					block.UpdateAt = now
 					board, err := a.blockToBoard(block, opt)
 					if err != nil {
-						return nil, nil, fmt.Errorf("cannot convert archive line %d to board: %w", lineNum, err)
+						return nil, nil, nil, nil, fmt.Errorf("cannot convert archive line %d to board: %w", lineNum, err)
 					}
 					if err := a.validateBoardForImport(userID, opt.TeamID, board); err != nil {
-						return nil, nil, err
+						return nil, nil, nil, nil, err
 					}
 					boardsAndBlocks.Boards = append(boardsAndBlocks.Boards, board)
 					boardID = board.ID
 				case "block":
 					var block *model.Block
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
-						return nil, nil, fmt.Errorf("invalid block in archive line %d: %w", lineNum, err2)
+						return nil, nil, nil, nil, fmt.Errorf("invalid block in archive line %d: %w", lineNum, err2)
 					}
 					if err := block.IsValidForImport(); err != nil {
-						return nil, nil, err
+						return nil, nil, nil, nil, err
 					}
 					block.ModifiedBy = userID
 					block.UpdateAt = now
@@ -336,11 +345,23 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 				case "boardMember":
 					var boardMember *model.BoardMember
 					if err2 := json.Unmarshal(archiveLine.Data, &boardMember); err2 != nil {
-						return nil, nil, fmt.Errorf("invalid board Member in archive line %d: %w", lineNum, err2)
+						return nil, nil, nil, nil, fmt.Errorf("invalid board Member in archive line %d: %w", lineNum, err2)
 					}
 					boardMembers = append(boardMembers, boardMember)
+				case "cardLink":
+					var cardLink *model.CardLink
+					if err2 := json.Unmarshal(archiveLine.Data, &cardLink); err2 != nil {
+						return nil, nil, nil, nil, fmt.Errorf("invalid card link in archive line %d: %w", lineNum, err2)
+					}
+					cardLinks = append(cardLinks, cardLink)
+				case "timeEntry":
+					var timeEntry *model.TimeEntry
+					if err2 := json.Unmarshal(archiveLine.Data, &timeEntry); err2 != nil {
+						return nil, nil, nil, nil, fmt.Errorf("invalid time entry in archive line %d: %w", lineNum, err2)
+					}
+					timeEntries = append(timeEntries, timeEntry)
 				default:
-					return nil, nil, model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
+					return nil, nil, nil, nil, model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
 				}
 				firstLine = false
 			}
@@ -348,21 +369,47 @@ func (a *App) parseBoardsAndBlocks(r io.Reader, opt model.ImportArchiveOptions)
 	}
 
 	if errRead := scanner.Err(); errRead != nil {
-		return nil, nil, fmt.Errorf("error reading archive line %d: %w", lineNum, errRead)
+		return nil, nil, nil, nil, fmt.Errorf("error reading archive line %d: %w", lineNum, errRead)
 	}
 
-	return boardsAndBlocks, boardMembers, nil
+	return boardsAndBlocks, boardMembers, cardLinks, timeEntries, nil
 }
 
 // ImportBoardJSONL imports a JSONL file containing blocks for one board. The resulting
 // board id is returned. Board authorization is expected to have already run via
 // ImportArchive; this persists the parsed board without re-validating it.
 func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.Board, error) {
-	boardsAndBlocks, boardMembers, err := a.parseBoardsAndBlocks(r, opt)
+	board, cardLinks, cardMap, err := a.importBoardJSONL(r, opt)
 	if err != nil {
 		return nil, err
 	}
+	a.importCardLinks(cardLinks, cardMap, opt.ModifiedBy)
+	return board, nil
+}
+
+// importBoardJSONL imports a JSONL file containing blocks for one board. The card
+// links of the board are returned along with a map of the card ids in the file to
+// the imported cards, so that links between boards of an archive can be created
+// once all of them are imported.
+func (a *App) importBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.Board, []*model.CardLink, map[string]*model.Block, error) {
+	boardsAndBlocks, boardMembers, cardLinks, timeEntries, err := a.parseBoardsAndBlocks(r, opt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
+	board, cardMap, err := a.createImportedBoard(boardsAndBlocks, boardMembers, opt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	a.importTimeEntries(timeEntries, cardMap, nil)
+	return board, cardLinks, cardMap, nil
+}
+
+// createImportedBoard creates a board parsed from an import, after running the
+// modifiers of the options and regenerating the ids. The board members that are
+// not users of the system are skipped. A map of the card ids before the import
+// to the created cards is returned along with the board.
+func (a *App) createImportedBoard(boardsAndBlocks *model.BoardsAndBlocks, boardMembers []*model.BoardMember, opt model.ImportArchiveOptions) (*model.Board, map[string]*model.Block, error) {
 	// loop to remove the people how are not part of the team and system
 	for i := len(boardMembers) - 1; i >= 0; i-- {
 		if _, getErr := a.GetUser(boardMembers[i].UserID); getErr != nil {
@@ -372,25 +419,38 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 
 	a.fixBoardsandBlocks(boardsAndBlocks, opt)
 
-	boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
+	// the ids are regenerated in place, keep track of the original card ids
+	oldCardIDs := make(map[*model.Block]string)
+	for _, block := range boardsAndBlocks.Blocks {
+		if block.Type == model.TypeCard {
+			oldCardIDs[block] = block.ID
+		}
+	}
+
+	boardsAndBlocks, err := model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
 	if err != nil {
-		return nil, fmt.Errorf("error generating archive block IDs: %w", err)
+		return nil, nil, fmt.Errorf("error generating archive block IDs: %w", err)
+	}
+
+	cardMap := make(map[string]*model.Block, len(oldCardIDs))
+	for block, oldID := range oldCardIDs {
+		cardMap[oldID] = block
 	}
 
 	boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+		return nil, nil, fmt.Errorf("error inserting archive blocks: %w", err)
 	}
 
 	if err := a.addUserToNewBoard(boardsAndBlocks, opt, boardMembers); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// find new board id
 	for _, board := range boardsAndBlocks.Boards {
-		return board, nil
+		return board, cardMap, nil
 	}
-	return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+	return nil, nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
 }
 
 func (a *App) addUserToNewBoard(boardsAndBlocks *model.BoardsAndBlocks, opt model.ImportArchiveOptions, boardMembers []*model.BoardMember) error {
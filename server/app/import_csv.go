@@ -0,0 +1,727 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+const (
+	// csvImportMaxRows is the maximum number of rows of an imported CSV file.
+	csvImportMaxRows = 10000
+
+	// csvImportMaxSelectOptions is the maximum number of distinct values of
+	// a column for it to be imported as a new select property.
+	csvImportMaxSelectOptions = 20
+
+	csvImportTitleColumn = "Title"
+	csvImportOptionColor = "propColorDefault"
+	csvImportViewTitle   = "Table view"
+)
+
+// csvImportDateLayouts are the layouts of the dates accepted by the CSV
+// importer, tried in order.
+var csvImportDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	time.RFC3339,
+	"01/02/2006",
+	"January 02, 2006",
+	"January 02, 2006 15:04",
+	"Jan 2, 2006",
+}
+
+var errCSVImportUnknownUser = errors.New("unknown user")
+
+// csvImport holds the state of the import of a CSV file: the card properties
+// of the board, which grow as new properties and options are found, and the
+// users resolved from the values of the person properties.
+type csvImport struct {
+	app            *App
+	teamID         string
+	userID         string
+	matchEmails    *bool // whether person values can be matched by email, once known
+	cardProperties []map[string]any
+	schema         model.PropSchema
+	changed        map[string]bool // ids of the card properties created or updated by the import
+	users          map[string]*model.User
+}
+
+// ImportCSV imports the rows of a CSV file as the cards of a new or an
+// existing board. Each column is imported into a card property, which is
+// created if needed, with its type inferred from the values of the column.
+// Rows that cannot be imported are reported in the result. If the options
+// are a dry run then nothing is imported.
+func (a *App) ImportCSV(r io.Reader, opt model.ImportCSVOptions) (*model.CSVImportResult, error) {
+	if err := opt.IsValid(); err != nil {
+		return nil, err
+	}
+
+	header, rows, err := readCSVImportFile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &model.Board{
+		TeamID:         opt.TeamID,
+		Type:           model.BoardTypePrivate,
+		Title:          opt.Title,
+		CardProperties: []map[string]any{},
+	}
+	if opt.BoardID != "" {
+		if board, err = a.store.GetBoard(opt.BoardID); err != nil {
+			return nil, err
+		}
+		if board.TeamID != opt.TeamID {
+			return nil, model.NewErrNotFound("board ID=" + opt.BoardID)
+		}
+	}
+
+	imp, err := newCSVImport(a, board, opt.ModifiedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	titleIndex, keyIndex, err := csvImportColumnIndexes(header, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := imp.mapColumns(header, rows, titleIndex, opt.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	if keyIndex >= 0 && keyIndex != titleIndex && columns[keyIndex].PropertyID == "" {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("column %q cannot be used as key", header[keyIndex]))
+	}
+
+	result := &model.CSVImportResult{
+		BoardID: opt.BoardID,
+		Columns: columns,
+		Errors:  []model.CSVImportRowError{},
+	}
+
+	// the existing cards by the value of the key column
+	existing := map[string]*model.Block{}
+	if keyIndex >= 0 && opt.BoardID != "" {
+		blocks, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard})
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			key := block.Title
+			if keyIndex != titleIndex {
+				properties, _ := block.Fields[model.BlockFieldProperties].(map[string]any)
+				key = csvImportKey(properties[columns[keyIndex].PropertyID])
+			}
+			if key != "" {
+				existing[key] = block
+			}
+		}
+	}
+
+	newCards := []*model.Block{}
+	patches := &model.BlockPatchBatch{}
+	seenKeys := map[string]int{}
+	now := utils.GetMillis()
+
+	for i, row := range rows {
+		line := i + 2
+		title := strings.TrimSpace(csvImportCell(row, titleIndex))
+		properties, rowErrors := imp.convertRow(row, columns, line)
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+
+		var existingCard *model.Block
+		if keyIndex >= 0 {
+			key := title
+			if keyIndex != titleIndex {
+				key = csvImportKey(properties[columns[keyIndex].PropertyID])
+			}
+			if key == "" {
+				result.Errors = append(result.Errors, model.CSVImportRowError{Row: line, Column: header[keyIndex], Message: "missing key"})
+				continue
+			}
+			if first, ok := seenKeys[key]; ok {
+				result.Errors = append(result.Errors, model.CSVImportRowError{
+					Row:     line,
+					Column:  header[keyIndex],
+					Message: fmt.Sprintf("duplicate key of row %d", first),
+				})
+				continue
+			}
+			seenKeys[key] = line
+			existingCard = existing[key]
+		}
+
+		if existingCard == nil {
+			newCards = append(newCards, &model.Block{
+				ID:       utils.NewID(utils.IDTypeCard),
+				ParentID: board.ID,
+				BoardID:  board.ID,
+				Type:     model.TypeCard,
+				Title:    title,
+				Fields: map[string]any{
+					model.BlockFieldProperties: csvImportSetProperties(map[string]any{}, properties),
+					"contentOrder":             []any{},
+				},
+				CreatedBy:  opt.ModifiedBy,
+				ModifiedBy: opt.ModifiedBy,
+				CreateAt:   now,
+				UpdateAt:   now,
+			})
+			continue
+		}
+
+		// the properties not in the file are kept, and the empty ones are
+		// kept as nil so that the patch, which is merged per property,
+		// removes them
+		oldProperties, _ := existingCard.Fields[model.BlockFieldProperties].(map[string]any)
+		merged := make(map[string]any, len(oldProperties)+len(properties))
+		for id, value := range oldProperties {
+			merged[id] = value
+		}
+		for id, value := range properties {
+			merged[id] = value
+		}
+		patches.BlockIDs = append(patches.BlockIDs, existingCard.ID)
+		patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{
+			Title:         &title,
+			UpdatedFields: map[string]any{model.BlockFieldProperties: merged},
+		})
+	}
+
+	result.CardProperties = imp.cardProperties
+	result.Created = len(newCards)
+	result.Updated = len(patches.BlockIDs)
+	if opt.DryRun {
+		return result, nil
+	}
+
+	if opt.BoardID == "" {
+		board.CardProperties = imp.cardProperties
+		newBoard, err := a.createCSVImportBoard(board, newCards, opt.ModifiedBy)
+		if err != nil {
+			return nil, err
+		}
+		result.BoardID = newBoard.ID
+		return result, nil
+	}
+
+	if len(imp.changed) > 0 {
+		patch := &model.BoardPatch{}
+		for _, prop := range imp.cardProperties {
+			if id, _ := prop["id"].(string); imp.changed[id] {
+				patch.UpdatedCardProperties = append(patch.UpdatedCardProperties, prop)
+			}
+		}
+		if _, err := a.PatchBoard(patch, board.ID, opt.ModifiedBy); err != nil {
+			return nil, fmt.Errorf("cannot update the card properties of board %s: %w", board.ID, err)
+		}
+	}
+
+	if _, err := a.InsertBlocksAndNotify(newCards, opt.ModifiedBy, true); err != nil {
+		return nil, fmt.Errorf("cannot create the cards of board %s: %w", board.ID, err)
+	}
+	if len(patches.BlockIDs) > 0 {
+		if err := a.PatchBlocksAndNotify(board.TeamID, patches, opt.ModifiedBy, true); err != nil {
+			return nil, fmt.Errorf("cannot update the cards of board %s: %w", board.ID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// createCSVImportBoard creates the board of an import along with its cards
+// and a table view showing all the properties.
+func (a *App) createCSVImportBoard(board *model.Board, cards []*model.Block, userID string) (*model.Board, error) {
+	board.ID = utils.NewID(utils.IDTypeBoard)
+	board.CreatedBy = userID
+	board.ModifiedBy = userID
+
+	visiblePropertyIDs := make([]any, 0, len(board.CardProperties))
+	for _, prop := range board.CardProperties {
+		visiblePropertyIDs = append(visiblePropertyIDs, prop["id"])
+	}
+
+	now := utils.GetMillis()
+	blocks := []*model.Block{{
+		ID:       utils.NewID(utils.IDTypeView),
+		ParentID: board.ID,
+		BoardID:  board.ID,
+		Type:     model.TypeView,
+		Title:    csvImportViewTitle,
+		Fields: map[string]any{
+			"viewType":           "table",
+			"sortOptions":        []any{},
+			"visiblePropertyIds": visiblePropertyIDs,
+			"visibleOptionIds":   []any{},
+			"hiddenOptionIds":    []any{},
+			"collapsedOptionIds": []any{},
+			"filter":             map[string]any{"operation": "and", "filters": []any{}},
+			"cardOrder":          []any{},
+			"columnWidths":       map[string]any{},
+			"columnCalculations": map[string]any{},
+			"kanbanCalculations": map[string]any{},
+			"defaultTemplateId":  "",
+		},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+		CreateAt:   now,
+		UpdateAt:   now,
+	}}
+	for _, card := range cards {
+		card.ParentID = board.ID
+		card.BoardID = board.ID
+		blocks = append(blocks, card)
+	}
+
+	bab, err := a.CreateBoardsAndBlocks(&model.BoardsAndBlocks{Boards: []*model.Board{board}, Blocks: blocks}, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the imported board: %w", err)
+	}
+	return bab.Boards[0], nil
+}
+
+// readCSVImportFile reads the column names and the rows of a CSV file.
+func readCSVImportFile(r io.Reader) ([]string, [][]string, error) {
+	lr := newLimitedReader(r, importMaxFileSize)
+	br := bufio.NewReader(lr)
+
+	// spreadsheets prefix the files with a byte order mark
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\ufeff" {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, model.NewErrBadRequest("the file is empty")
+	}
+	if err != nil {
+		return nil, nil, model.NewErrBadRequest(fmt.Sprintf("cannot read the column names: %s", err))
+	}
+
+	rows := [][]string{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if limitedReaderExceeded(lr) {
+				return nil, nil, model.ErrRequestEntityTooLarge
+			}
+			return nil, nil, model.NewErrBadRequest(err.Error())
+		}
+		if len(rows) == csvImportMaxRows {
+			return nil, nil, model.NewErrBadRequest(fmt.Sprintf("the file has more than %d rows", csvImportMaxRows))
+		}
+		rows = append(rows, row)
+	}
+	if limitedReaderExceeded(lr) {
+		return nil, nil, model.ErrRequestEntityTooLarge
+	}
+
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+		if header[i] == "" {
+			return nil, nil, model.NewErrBadRequest(fmt.Sprintf("column %d has no name", i+1))
+		}
+		if seen[strings.ToLower(header[i])] {
+			return nil, nil, model.NewErrBadRequest(fmt.Sprintf("duplicate column %q", header[i]))
+		}
+		seen[strings.ToLower(header[i])] = true
+	}
+
+	return header, rows, nil
+}
+
+// csvImportColumnIndexes returns the indexes of the title column and of the
+// key column, -1 if there is no key column.
+func csvImportColumnIndexes(header []string, opt model.ImportCSVOptions) (int, int, error) {
+	indexOf := func(name string) int {
+		for i, column := range header {
+			if strings.EqualFold(column, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	titleIndex := 0
+	if opt.TitleColumn != "" {
+		if titleIndex = indexOf(opt.TitleColumn); titleIndex < 0 {
+			return 0, 0, model.NewErrBadRequest(fmt.Sprintf("unknown title column %q", opt.TitleColumn))
+		}
+	} else if i := indexOf(csvImportTitleColumn); i >= 0 {
+		titleIndex = i
+	}
+
+	keyIndex := -1
+	if opt.KeyColumn != "" {
+		if keyIndex = indexOf(opt.KeyColumn); keyIndex < 0 {
+			return 0, 0, model.NewErrBadRequest(fmt.Sprintf("unknown key column %q", opt.KeyColumn))
+		}
+	}
+
+	return titleIndex, keyIndex, nil
+}
+
+func newCSVImport(a *App, board *model.Board, userID string) (*csvImport, error) {
+	// the properties are copied, as new options may be added to them
+	cardProperties := make([]map[string]any, 0, len(board.CardProperties))
+	for _, prop := range board.CardProperties {
+		cp := make(map[string]any, len(prop))
+		for k, v := range prop {
+			cp[k] = v
+		}
+		cardProperties = append(cardProperties, cp)
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvImport{
+		app:            a,
+		teamID:         board.TeamID,
+		userID:         userID,
+		cardProperties: cardProperties,
+		schema:         schema,
+		changed:        map[string]bool{},
+		users:          map[string]*model.User{},
+	}, nil
+}
+
+// mapColumns maps each column to a card property: the one given by the
+// mapping, or else the one with the name of the column, or else a new one.
+func (imp *csvImport) mapColumns(header []string, rows [][]string, titleIndex int, mapping map[string]string) ([]model.CSVImportColumn, error) {
+	columns := make([]model.CSVImportColumn, len(header))
+	for i, name := range header {
+		columns[i].Name = name
+		if i == titleIndex {
+			continue
+		}
+
+		var pd model.PropDef
+		var ok bool
+		if propertyID, mapped := mapping[name]; mapped {
+			if pd, ok = imp.schema[propertyID]; !ok {
+				return nil, model.NewErrBadRequest(fmt.Sprintf("column %q is mapped to unknown property %q", name, propertyID))
+			}
+			if !csvImportableType(pd.Type) {
+				return nil, model.NewErrBadRequest(fmt.Sprintf("column %q is mapped to property %q, which cannot be imported", name, pd.Name))
+			}
+		} else if pd, ok = imp.schema.GetByName(name); ok && !csvImportableType(pd.Type) {
+			// e.g. a column of an exported board with the creation time
+			columns[i].Ignored = true
+			continue
+		}
+
+		if !ok {
+			values := make([]string, 0, len(rows))
+			for _, row := range rows {
+				if value := strings.TrimSpace(csvImportCell(row, i)); value != "" {
+					values = append(values, value)
+				}
+			}
+			pd = imp.addProperty(name, imp.inferPropertyType(values))
+			columns[i].New = true
+		}
+		columns[i].PropertyID = pd.ID
+	}
+	return columns, nil
+}
+
+// inferPropertyType returns the type of the property for the values of a
+// column.
+func (imp *csvImport) inferPropertyType(values []string) string {
+	if len(values) == 0 {
+		return "text"
+	}
+
+	all := func(check func(string) bool) bool {
+		for _, value := range values {
+			if !check(value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case all(func(v string) bool { _, err := strconv.ParseFloat(v, 64); return err == nil }):
+		return "number"
+	case all(func(v string) bool { _, err := strconv.ParseBool(v); return err == nil }):
+		return "checkbox"
+	case all(func(v string) bool { _, err := parseCSVImportDate(v); return err == nil }):
+		return "date"
+	case all(func(v string) bool { return !strings.ContainsAny(v, " \t") && imp.getUser(v) != nil }):
+		return "person"
+	}
+
+	distinct := map[string]bool{}
+	for _, value := range values {
+		distinct[strings.ToLower(value)] = true
+	}
+	if len(distinct) <= csvImportMaxSelectOptions && len(distinct) < len(values) {
+		return "select"
+	}
+	return "text"
+}
+
+// convertRow converts the cells of a row to the values of the card
+// properties. Empty cells are converted to nil values, which unset the
+// property.
+func (imp *csvImport) convertRow(row []string, columns []model.CSVImportColumn, line int) (map[string]any, []model.CSVImportRowError) {
+	properties := map[string]any{}
+	var rowErrors []model.CSVImportRowError
+	for i, column := range columns {
+		if column.PropertyID == "" {
+			continue
+		}
+
+		value, err := imp.convertValue(imp.schema[column.PropertyID], strings.TrimSpace(csvImportCell(row, i)))
+		if err != nil {
+			rowErrors = append(rowErrors, model.CSVImportRowError{Row: line, Column: column.Name, Message: err.Error()})
+			continue
+		}
+		properties[column.PropertyID] = value
+	}
+	return properties, rowErrors
+}
+
+// convertValue converts the value of a cell to the value of a property.
+// New options are added to select properties for the values without one.
+func (imp *csvImport) convertValue(pd model.PropDef, value string) (any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	switch pd.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return value, nil
+
+	case "checkbox":
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return strconv.FormatBool(checked), nil
+
+	case "date":
+		date, err := parseCSVImportDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date", value)
+		}
+		return fmt.Sprintf(`{"from":%d}`, utils.GetMillisForTime(date)), nil
+
+	case "select":
+		return imp.optionID(pd.ID, value), nil
+
+	case "multiSelect":
+		optionIDs := []any{}
+		for _, v := range splitCSVImportValues(value) {
+			optionIDs = append(optionIDs, imp.optionID(pd.ID, v))
+		}
+		return optionIDs, nil
+
+	case "person":
+		user := imp.getUser(value)
+		if user == nil {
+			return nil, fmt.Errorf("%w %q", errCSVImportUnknownUser, value)
+		}
+		return user.ID, nil
+
+	case "multiPerson":
+		userIDs := []any{}
+		for _, v := range splitCSVImportValues(value) {
+			user := imp.getUser(v)
+			if user == nil {
+				return nil, fmt.Errorf("%w %q", errCSVImportUnknownUser, v)
+			}
+			userIDs = append(userIDs, user.ID)
+		}
+		return userIDs, nil
+	}
+
+	return value, nil
+}
+
+// addProperty adds a new card property to the board.
+func (imp *csvImport) addProperty(name, propertyType string) model.PropDef {
+	pd := model.PropDef{
+		ID:      utils.NewID(utils.IDTypeNone),
+		Index:   len(imp.cardProperties),
+		Name:    name,
+		Type:    propertyType,
+		Options: map[string]model.PropDefOption{},
+	}
+	imp.cardProperties = append(imp.cardProperties, map[string]any{
+		"id":      pd.ID,
+		"name":    name,
+		"type":    propertyType,
+		"options": []any{},
+	})
+	imp.schema[pd.ID] = pd
+	imp.changed[pd.ID] = true
+	return pd
+}
+
+// optionID returns the id of the option of a select property with the
+// given value, adding the option if there is none.
+func (imp *csvImport) optionID(propertyID, value string) string {
+	pd := imp.schema[propertyID]
+	if id, ok := pd.GetOptionID(value); ok {
+		return id
+	}
+
+	option := model.PropDefOption{
+		ID:    utils.NewID(utils.IDTypeNone),
+		Index: len(pd.Options),
+		Value: value,
+		Color: csvImportOptionColor,
+	}
+	pd.Options[option.ID] = option
+
+	for _, prop := range imp.cardProperties {
+		if prop["id"] != propertyID {
+			continue
+		}
+		var options []any
+		switch opts := prop["options"].(type) {
+		case []any:
+			options = append(options, opts...)
+		case []map[string]any:
+			for _, opt := range opts {
+				options = append(options, opt)
+			}
+		}
+		prop["options"] = append(options, map[string]any{
+			"id":    option.ID,
+			"value": option.Value,
+			"color": option.Color,
+		})
+	}
+	imp.changed[propertyID] = true
+	return option.ID
+}
+
+// getUser returns the member of the team of the board with the given
+// username or email, nil if there is none. A leading @ of usernames is
+// ignored, and emails are only matched if the importing user can see them.
+func (imp *csvImport) getUser(value string) *model.User {
+	key := strings.ToLower(strings.TrimPrefix(value, "@"))
+	if user, ok := imp.users[key]; ok {
+		return user
+	}
+
+	var user *model.User
+	var err error
+	switch {
+	case !strings.Contains(key, "@"):
+		user, err = imp.app.store.GetUserByUsername(key)
+	case imp.canMatchEmails():
+		user, err = imp.app.store.GetUserByEmail(key)
+	}
+	if err != nil || (user != nil && !imp.app.permissions.HasPermissionToTeam(user.ID, imp.teamID, model.PermissionViewTeam)) {
+		user = nil
+	}
+	imp.users[key] = user
+	return user
+}
+
+// canMatchEmails returns whether the importing user can see the emails of
+// the users, so that an import doesn't tell whom an address belongs to.
+func (imp *csvImport) canMatchEmails() bool {
+	if imp.matchEmails == nil {
+		match := imp.app.config.ShowEmailAddress || imp.app.permissions.HasPermissionTo(imp.userID, model.PermissionManageSystem)
+		imp.matchEmails = &match
+	}
+	return *imp.matchEmails
+}
+
+func csvImportableType(propertyType string) bool {
+	switch propertyType {
+	case "createdTime", "updatedTime", "createdBy", "updatedBy", model.PropTypeRelation, model.PropTypeRollup:
+		return false
+	}
+	return true
+}
+
+func csvImportCell(row []string, index int) string {
+	if index < len(row) {
+		return row[index]
+	}
+	return ""
+}
+
+// csvImportKey returns the key of a card from the value of the key property.
+func csvImportKey(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		keys := make([]string, 0, len(v))
+		for _, item := range v {
+			keys = append(keys, fmt.Sprint(item))
+		}
+		return strings.Join(keys, ",")
+	}
+	return ""
+}
+
+// csvImportSetProperties sets the imported values of the properties of a
+// card, removing the ones that are empty.
+func csvImportSetProperties(properties map[string]any, values map[string]any) map[string]any {
+	for id, value := range values {
+		if value == nil {
+			delete(properties, id)
+		} else {
+			properties[id] = value
+		}
+	}
+	return properties
+}
+
+func splitCSVImportValues(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func parseCSVImportDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range csvImportDateLayouts {
+		var date time.Time
+		if date, err = time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
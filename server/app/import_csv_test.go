@@ -0,0 +1,321 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+func TestImportCSV(t *testing.T) {
+	const teamID = "team-id"
+	const userID = "user-id"
+
+	propertyByName := func(t *testing.T, cardProperties []map[string]any, name string) map[string]any {
+		for _, prop := range cardProperties {
+			if prop["name"] == name {
+				return prop
+			}
+		}
+		require.Failf(t, "missing property", "property %q", name)
+		return nil
+	}
+
+	t.Run("dry run into a new board infers the properties", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		th.App.config.ShowEmailAddress = true
+		th.Store.EXPECT().GetUserByUsername("alice").Return(&model.User{ID: "user-alice"}, nil)
+		th.Store.EXPECT().GetUserByEmail("bob@example.com").Return(&model.User{ID: "user-bob"}, nil)
+		th.Store.EXPECT().GetUserByUsername(gomock.Any()).Return(nil, model.NewErrNotFound("user")).AnyTimes()
+		th.API.EXPECT().HasPermissionToTeam("user-alice", teamID, model.PermissionViewTeam).Return(true)
+		th.API.EXPECT().HasPermissionToTeam("user-bob", teamID, model.PermissionViewTeam).Return(true)
+
+		file := "\ufeffName,Status,Points,Due,Owner,Done,Notes,Title\n" +
+			"ignored,To do,3,2024-01-15,@alice,true,first note,Write docs\n" +
+			",Done,1.5,01/20/2024,bob@example.com,false,second note,Fix bug\n" +
+			",to do,,,alice,,third note,Release\n"
+
+		result, err := th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			Title:      "Imported",
+			DryRun:     true,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, result.BoardID)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, 3, result.Created)
+		assert.Equal(t, 0, result.Updated)
+
+		require.Len(t, result.Columns, 8)
+		assert.Equal(t, model.CSVImportColumn{Name: "Title"}, result.Columns[7])
+		for _, column := range result.Columns[:7] {
+			assert.True(t, column.New, column.Name)
+			assert.NotEmpty(t, column.PropertyID, column.Name)
+		}
+
+		types := map[string]any{}
+		for _, prop := range result.CardProperties {
+			types[prop["name"].(string)] = prop["type"]
+		}
+		assert.Equal(t, map[string]any{
+			"Name":   "text",
+			"Status": "select",
+			"Points": "number",
+			"Due":    "date",
+			"Owner":  "person",
+			"Done":   "checkbox",
+			"Notes":  "text",
+		}, types)
+
+		// values are matched with the options case insensitively
+		options := propertyByName(t, result.CardProperties, "Status")["options"].([]any)
+		require.Len(t, options, 2)
+		assert.Equal(t, "To do", options[0].(map[string]any)["value"])
+		assert.Equal(t, "Done", options[1].(map[string]any)["value"])
+		require.NoError(t, model.ValidateCardPropertyTemplates(result.CardProperties))
+	})
+
+	t.Run("people are only matched with the members of the team", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		board := &model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: teamID,
+			CardProperties: []map[string]any{
+				{"id": "owner-id", "name": "Owner", "type": "person"},
+			},
+		}
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+
+		// the emails are hidden to the importing user
+		th.API.EXPECT().HasPermissionTo(userID, model.PermissionManageSystem).Return(false)
+		th.Store.EXPECT().GetUserByUsername("alice").Return(&model.User{ID: "user-alice"}, nil)
+		th.Store.EXPECT().GetUserByUsername("mallory").Return(&model.User{ID: "user-mallory"}, nil)
+		th.API.EXPECT().HasPermissionToTeam("user-alice", teamID, model.PermissionViewTeam).Return(true)
+		th.API.EXPECT().HasPermissionToTeam("user-mallory", teamID, model.PermissionViewTeam).Return(false)
+
+		file := "Title,Owner\n" +
+			"First,alice\n" +
+			"Second,bob@example.com\n" +
+			"Third,mallory\n"
+
+		result, err := th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			BoardID:    board.ID,
+			DryRun:     true,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		assert.Contains(t, result.Errors[0].Message, "bob@example.com")
+		assert.Equal(t, 4, result.Errors[1].Row)
+		assert.Contains(t, result.Errors[1].Message, "mallory")
+	})
+
+	t.Run("upsert into an existing board", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		board := &model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: teamID,
+			CardProperties: []map[string]any{
+				{"id": "key-id", "name": "Key", "type": "text"},
+				{"id": "estimate-id", "name": "Estimate", "type": "number"},
+				{"id": "status-id", "name": "Status", "type": "select", "options": []any{
+					map[string]any{"id": "todo-id", "value": "To do", "color": "propColorGray"},
+				}},
+				{"id": "created-id", "name": "Created", "type": "createdTime"},
+			},
+		}
+		existingCard := &model.Block{
+			ID:      utils.NewID(utils.IDTypeCard),
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Title:   "Old title",
+			Fields: map[string]any{"properties": map[string]any{
+				"key-id":    "K-1",
+				"status-id": "todo-id",
+				"other-id":  "kept",
+			}},
+		}
+
+		file := "Key,Title,Size,Status,Created\n" +
+			"K-1,New title,8,Blocked,\"January 02, 2024\"\n" +
+			"K-2,Second,not a number,To do,\n" +
+			"K-3,Third,5,,\n" +
+			"K-1,Duplicate,1,To do,\n"
+
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil).AnyTimes()
+		th.Store.EXPECT().GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard}).
+			Return([]*model.Block{existingCard}, nil)
+
+		var boardPatch *model.BoardPatch
+		th.Store.EXPECT().PatchBoard(board.ID, gomock.Any(), userID).DoAndReturn(
+			func(_ string, patch *model.BoardPatch, _ string) (*model.Board, error) {
+				boardPatch = patch
+				return board, nil
+			})
+
+		var inserted []*model.Block
+		th.Store.EXPECT().GetBlock(gomock.Any()).Return(nil, model.NewErrNotFound("block")).AnyTimes()
+		th.Store.EXPECT().InsertBlock(gomock.Any(), userID).DoAndReturn(
+			func(block *model.Block, _ string) error {
+				inserted = append(inserted, block)
+				return nil
+			})
+
+		var blockPatches *model.BlockPatchBatch
+		th.Store.EXPECT().GetBlocksByIDs([]string{existingCard.ID}).Return([]*model.Block{existingCard}, nil)
+		th.Store.EXPECT().PatchBlocks(gomock.Any(), userID).DoAndReturn(
+			func(patches *model.BlockPatchBatch, _ string) error {
+				blockPatches = patches
+				return nil
+			})
+		th.Store.EXPECT().GetMembersForBoard(board.ID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		result, err := th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			BoardID:    board.ID,
+			Mapping:    map[string]string{"Size": "estimate-id"},
+			KeyColumn:  "key",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, board.ID, result.BoardID)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, []model.CSVImportColumn{
+			{Name: "Key", PropertyID: "key-id"},
+			{Name: "Title"},
+			{Name: "Size", PropertyID: "estimate-id"},
+			{Name: "Status", PropertyID: "status-id"},
+			{Name: "Created", Ignored: true},
+		}, result.Columns)
+		assert.Equal(t, []model.CSVImportRowError{
+			{Row: 3, Column: "Size", Message: `"not a number" is not a number`},
+			{Row: 5, Column: "Key", Message: "duplicate key of row 2"},
+		}, result.Errors)
+
+		// the new option is added to the board
+		require.NotNil(t, boardPatch)
+		require.Len(t, boardPatch.UpdatedCardProperties, 1)
+		options := boardPatch.UpdatedCardProperties[0]["options"].([]any)
+		require.Len(t, options, 2)
+		blocked := options[1].(map[string]any)
+		assert.Equal(t, "Blocked", blocked["value"])
+		// the board returned by the store is not modified
+		assert.Len(t, board.CardProperties[2]["options"], 1)
+
+		require.Len(t, inserted, 1)
+		assert.Equal(t, "Third", inserted[0].Title)
+		assert.Equal(t, map[string]any{"key-id": "K-3", "estimate-id": "5"}, inserted[0].Fields["properties"])
+
+		require.NotNil(t, blockPatches)
+		require.Equal(t, []string{existingCard.ID}, blockPatches.BlockIDs)
+		assert.Equal(t, "New title", *blockPatches.BlockPatches[0].Title)
+		assert.Equal(t, map[string]any{
+			"key-id":      "K-1",
+			"estimate-id": "8",
+			"status-id":   blocked["id"],
+			"other-id":    "kept",
+		}, blockPatches.BlockPatches[0].UpdatedFields["properties"])
+	})
+
+	t.Run("into a new board", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		var bab *model.BoardsAndBlocks
+		th.Store.EXPECT().CreateBoardsAndBlocksWithAdmin(gomock.Any(), userID).DoAndReturn(
+			func(newBab *model.BoardsAndBlocks, _ string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+				bab = newBab
+				return newBab, []*model.BoardMember{}, nil
+			})
+		th.Store.EXPECT().GetMembersForBoard(gomock.Any()).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetUserCategoryBoards(userID, teamID).Return([]model.CategoryBoards{
+			{Category: model.Category{ID: "default_category_id", Name: "Boards", Type: "system"}},
+		}, nil).Times(2)
+		th.Store.EXPECT().AddUpdateCategoryBoard(userID, "default_category_id", gomock.Any()).Return(nil)
+
+		file := "Title,Notes\nFirst,a note\nSecond,another note\n"
+		result, err := th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			Title:      "Imported",
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, bab)
+		require.Len(t, bab.Boards, 1)
+		board := bab.Boards[0]
+		assert.Equal(t, result.BoardID, board.ID)
+		assert.Equal(t, "Imported", board.Title)
+		assert.Equal(t, teamID, board.TeamID)
+		assert.Equal(t, model.BoardTypePrivate, board.Type)
+		require.Len(t, board.CardProperties, 1)
+		notesID := board.CardProperties[0]["id"]
+
+		// a table view showing the properties, and the cards
+		require.Len(t, bab.Blocks, 3)
+		view := bab.Blocks[0]
+		assert.EqualValues(t, model.TypeView, view.Type)
+		assert.Equal(t, []any{notesID}, view.Fields["visiblePropertyIds"])
+		for i, title := range []string{"First", "Second"} {
+			card := bab.Blocks[i+1]
+			assert.EqualValues(t, model.TypeCard, card.Type)
+			assert.Equal(t, board.ID, card.BoardID)
+			assert.Equal(t, board.ID, card.ParentID)
+			assert.Equal(t, title, card.Title)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		file := "Title,Notes\nA,B\n"
+
+		_, err := th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{TeamID: teamID, ModifiedBy: userID})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			Title:      "Imported",
+			KeyColumn:  "Missing",
+		})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = th.App.ImportCSV(strings.NewReader(file), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			Title:      "Imported",
+			Mapping:    map[string]string{"Notes": "missing-id"},
+		})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = th.App.ImportCSV(strings.NewReader("Title,title\nA,B\n"), model.ImportCSVOptions{
+			TeamID:     teamID,
+			ModifiedBy: userID,
+			Title:      "Imported",
+		})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/importers"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// ImportExternalBoard imports a board exported from another tool, like Trello,
+// Jira or Asana. The converted board goes through the same modifiers, validation
+// and size limits as the boards of an archive.
+func (a *App) ImportExternalBoard(r io.Reader, format model.ImportFormat, opt model.ImportArchiveOptions) (*model.Board, error) {
+	if err := format.IsValid(); err != nil {
+		return nil, err
+	}
+
+	lr := newLimitedReader(r, a.effectiveArchiveEntryMaxSize())
+	boardsAndBlocks, err := importers.Convert(format, lr, a.store)
+	if limitedReaderExceeded(lr) {
+		return nil, fmt.Errorf("cannot import %s export: %w", format, model.ErrRequestEntityTooLarge)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	for _, board := range boardsAndBlocks.Boards {
+		board.TeamID = opt.TeamID
+		board.CreatedBy = opt.ModifiedBy
+		board.ModifiedBy = opt.ModifiedBy
+		board.UpdateAt = now
+		if err := a.validateBoardForImport(opt.ModifiedBy, opt.TeamID, board); err != nil {
+			return nil, err
+		}
+	}
+	for _, block := range boardsAndBlocks.Blocks {
+		block.CreatedBy = opt.ModifiedBy
+		block.ModifiedBy = opt.ModifiedBy
+	}
+
+	if err := validateImportedBoards(boardsAndBlocks.Boards, opt.BoardValidator); err != nil {
+		return nil, err
+	}
+
+	board, _, err := a.createImportedBoard(boardsAndBlocks, nil, opt)
+	return board, err
+}
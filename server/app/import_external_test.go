@@ -0,0 +1,141 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+const trelloExport = `{
+	"name": "Roadmap",
+	"lists": [{"id": "l1", "name": "To do", "pos": 1}],
+	"cards": [
+		{"id": "c1", "name": "Plan", "idList": "l1", "pos": 1, "desc": "Details"},
+		{"id": "c2", "name": "Skipped", "idList": "l1", "pos": 2}
+	]
+}`
+
+func TestImportExternalBoard(t *testing.T) {
+	const userID = "user-id"
+
+	t.Run("runs the modifiers and creates the board", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+
+		var bab *model.BoardsAndBlocks
+		th.Store.EXPECT().CreateBoardsAndBlocks(gomock.Any(), userID).DoAndReturn(
+			func(newBab *model.BoardsAndBlocks, _ string) (*model.BoardsAndBlocks, error) {
+				bab = newBab
+				return newBab, nil
+			})
+		th.Store.EXPECT().GetMembersForBoard(gomock.Any()).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetUserCategoryBoards(userID, importTestTeamID).Return([]model.CategoryBoards{
+			{Category: model.Category{ID: "default_category_id", Name: "Boards", Type: model.CategoryTypeSystem}},
+		}, nil).AnyTimes()
+		th.Store.EXPECT().AddUpdateCategoryBoard(userID, "default_category_id", gomock.Any()).Return(nil).AnyTimes()
+		th.Store.EXPECT().GetBoard(gomock.Any()).DoAndReturn(func(boardID string) (*model.Board, error) {
+			return bab.Boards[0], nil
+		}).AnyTimes()
+		th.Store.EXPECT().GetMemberForBoard(gomock.Any(), userID).Return(nil, model.NewErrNotFound("member")).AnyTimes()
+		th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil).AnyTimes()
+		th.Store.EXPECT().SaveMember(gomock.Any()).DoAndReturn(func(member *model.BoardMember) (*model.BoardMember, error) {
+			assert.Equal(t, userID, member.UserID)
+			assert.True(t, member.SchemeAdmin)
+			return member, nil
+		})
+
+		var validated []*model.Board
+		board, err := th.App.ImportExternalBoard(strings.NewReader(trelloExport), model.ImportFormatTrello, model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+			BoardModifier: func(board *model.Board, _ map[string]interface{}) bool {
+				board.Title += " (imported)"
+				return true
+			},
+			BlockModifier: func(block *model.Block, _ map[string]interface{}) bool {
+				return block.Title != "Skipped"
+			},
+			BoardValidator: func(board *model.Board) error {
+				validated = append(validated, board)
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, validated, 1)
+		require.NotNil(t, bab)
+		assert.Equal(t, "Roadmap (imported)", board.Title)
+		assert.Equal(t, importTestTeamID, board.TeamID)
+		assert.Equal(t, model.BoardTypePrivate, board.Type)
+
+		var cards, texts int
+		for _, block := range bab.Blocks {
+			assert.Equal(t, board.ID, block.BoardID)
+			switch block.Type {
+			case model.TypeCard:
+				cards++
+				assert.Equal(t, "Plan", block.Title)
+			case model.TypeText:
+				texts++
+			}
+		}
+		assert.Equal(t, 1, cards)
+		assert.Equal(t, 1, texts)
+	})
+
+	t.Run("board validator aborts the import", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+
+		validatorErr := model.NewErrPermission("access denied to create private boards")
+		_, err := th.App.ImportExternalBoard(strings.NewReader(trelloExport), model.ImportFormatTrello, model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+			BoardValidator: func(board *model.Board) error {
+				return validatorErr
+			},
+		})
+		require.ErrorIs(t, err, validatorErr)
+	})
+
+	t.Run("rejects exports over the size limit", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		th.App.config.MaxFileSize = 16
+		_, err := th.App.ImportExternalBoard(strings.NewReader(trelloExport), model.ImportFormatTrello, model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+		})
+		require.True(t, model.IsErrRequestEntityTooLarge(err))
+	})
+
+	t.Run("rejects invalid exports", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		_, err := th.App.ImportExternalBoard(strings.NewReader(trelloExport), model.ImportFormat("monday"), model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+		})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = th.App.ImportExternalBoard(strings.NewReader("{not json"), model.ImportFormatJira, model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+		})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
@@ -0,0 +1,628 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// importSessionExpiry is how long a session is kept after its last
+	// update, confirmed or not.
+	importSessionExpiry            = 24 * time.Hour
+	importSessionsCleanupBatchSize = 100
+)
+
+var (
+	errImportSessionNotPending = errors.New("import session is already confirmed")
+	errImportSessionImporting  = errors.New("import session is being imported")
+	errArchiveChanged          = errors.New("import archive doesn't match its session")
+	errNoBoardInArchive        = errors.New("the archive contains no board")
+)
+
+// archiveBoardEntry is a board of an import archive, with its files.
+type archiveBoardEntry struct {
+	dir   string
+	open  func() (io.ReadCloser, error)
+	files []*zip.File
+}
+
+// CreateImportSession stores an archive in the file store and returns a
+// preview of its import: the boards with their card counts and conflicts,
+// the users referenced, and the validation errors. Nothing is imported until
+// the session is confirmed with ConfirmImportSession.
+func (a *App) CreateImportSession(r io.Reader, opt model.ImportArchiveOptions) (*model.ImportSession, error) {
+	now := utils.GetMillis()
+	session := &model.ImportSession{
+		ID:        utils.NewID(utils.IDTypeNone),
+		TeamID:    opt.TeamID,
+		Status:    model.ImportSessionPending,
+		CreatedBy: opt.ModifiedBy,
+		CreateAt:  now,
+		UpdateAt:  now,
+	}
+
+	if _, err := a.filesBackend.WriteFile(r, importSessionArchivePath(session)); err != nil {
+		return nil, fmt.Errorf("cannot store import archive: %w", err)
+	}
+
+	if err := a.previewImportSession(session, opt); err != nil {
+		a.removeImportSessionArchive(session)
+		return nil, err
+	}
+
+	if err := a.store.InsertImportSession(session); err != nil {
+		a.removeImportSessionArchive(session)
+		return nil, err
+	}
+	return session, nil
+}
+
+func (a *App) GetImportSession(sessionID string) (*model.ImportSession, error) {
+	return a.store.GetImportSession(sessionID)
+}
+
+// DeleteImportSession cancels a session, removing its archive.
+func (a *App) DeleteImportSession(session *model.ImportSession) error {
+	if session.Status == model.ImportSessionImporting {
+		return model.NewErrBadRequest(errImportSessionImporting.Error())
+	}
+	if session.Status == model.ImportSessionPending {
+		a.removeImportSessionArchive(session)
+	}
+	return a.store.DeleteImportSession(session.ID)
+}
+
+// ConfirmImportSession imports the boards of a session. Each board is
+// created in its own transaction with its blocks and members, after its
+// files are stored, so that a failed board leaves nothing behind. The
+// result of the import of each board is set in the returned session.
+func (a *App) ConfirmImportSession(session *model.ImportSession, confirm *model.ImportSessionConfirm, opt model.ImportArchiveOptions) (*model.ImportSession, error) {
+	if err := confirm.IsValid(); err != nil {
+		return nil, err
+	}
+	if session.Status != model.ImportSessionPending {
+		return nil, model.NewErrBadRequest(errImportSessionNotPending.Error())
+	}
+	if err := a.validateImportSessionConfirm(session, confirm); err != nil {
+		return nil, err
+	}
+
+	started, err := a.store.UpdateImportSessionStatus(session.ID, model.ImportSessionPending, model.ImportSessionImporting)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, model.NewErrBadRequest(errImportSessionNotPending.Error())
+	}
+
+	if err := a.importSessionBoards(session, confirm, opt); err != nil {
+		// nothing was imported, the session can be confirmed again
+		if _, statusErr := a.store.UpdateImportSessionStatus(session.ID, model.ImportSessionImporting, model.ImportSessionPending); statusErr != nil {
+			a.logger.Error("Cannot reset import session status", mlog.String("sessionID", session.ID), mlog.Err(statusErr))
+		}
+		return nil, err
+	}
+
+	session.Status = model.ImportSessionCompleted
+	session.UpdateAt = utils.GetMillis()
+	if err := a.store.UpdateImportSession(session); err != nil {
+		return nil, err
+	}
+	a.removeImportSessionArchive(session)
+	return session, nil
+}
+
+// CleanupImportSessions removes the sessions, and their archives, that were
+// not updated for importSessionExpiry.
+func (a *App) CleanupImportSessions() {
+	before := utils.GetMillis() - importSessionExpiry.Milliseconds()
+	sessions, err := a.store.GetImportSessionsUpdatedBefore(before, importSessionsCleanupBatchSize)
+	if err != nil {
+		a.logger.Error("Cannot get expired import sessions", mlog.Err(err))
+		return
+	}
+
+	for _, session := range sessions {
+		if session.Status != model.ImportSessionCompleted {
+			a.removeImportSessionArchive(session)
+		}
+		if err := a.store.DeleteImportSession(session.ID); err != nil && !model.IsErrNotFound(err) {
+			a.logger.Error("Cannot delete expired import session", mlog.String("sessionID", session.ID), mlog.Err(err))
+		}
+	}
+}
+
+func (a *App) previewImportSession(session *model.ImportSession, opt model.ImportArchiveOptions) error {
+	reader, err := a.filesBackend.Reader(importSessionArchivePath(session))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	entries, orphans, err := a.readImportArchive(reader)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return model.NewErrBadRequest(errNoBoardInArchive.Error())
+	}
+	for _, dir := range orphans {
+		session.Errors = append(session.Errors, fmt.Sprintf("files of %s don't belong to a board and will be skipped", dir))
+	}
+
+	existingBoards, err := a.store.GetBoardsForUserAndTeam(opt.ModifiedBy, opt.TeamID, true)
+	if err != nil {
+		return err
+	}
+
+	var userIDs []string
+	seenUsers := map[string]bool{}
+	session.Boards = make([]*model.ImportSessionBoard, 0, len(entries))
+	for _, entry := range entries {
+		sessionBoard, boardUserIDs := a.previewArchiveBoard(entry, existingBoards, opt)
+		session.Boards = append(session.Boards, sessionBoard)
+		for _, userID := range boardUserIDs {
+			if !seenUsers[userID] {
+				seenUsers[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	session.Users = userIDs
+	session.UnresolvedUsers = []string{}
+	for _, userID := range userIDs {
+		if userID == model.SystemUserID {
+			continue
+		}
+		if _, err := a.GetUser(userID); err != nil {
+			session.UnresolvedUsers = append(session.UnresolvedUsers, userID)
+		}
+	}
+	return nil
+}
+
+// previewArchiveBoard describes a board of an archive, and returns the
+// users it references.
+func (a *App) previewArchiveBoard(entry *archiveBoardEntry, existingBoards []*model.Board, opt model.ImportArchiveOptions) (*model.ImportSessionBoard, []string) {
+	sessionBoard := &model.ImportSessionBoard{ArchiveID: entry.dir}
+
+	maxEntry := a.effectiveArchiveEntryMaxSize()
+	for _, file := range entry.files {
+		sessionBoard.Files++
+		sessionBoard.FilesSize += int64(file.UncompressedSize64)
+		if int64(file.UncompressedSize64) > maxEntry {
+			sessionBoard.Errors = append(sessionBoard.Errors, fmt.Sprintf("file %s: %s", path.Base(file.Name), errSizeLimitExceeded))
+		}
+	}
+
+	bab, members, _, timeEntries, err := a.parseArchiveBoard(entry, opt)
+	if err != nil {
+		sessionBoard.Errors = append(sessionBoard.Errors, err.Error())
+		return sessionBoard, nil
+	}
+
+	board := bab.Boards[0]
+	sessionBoard.ArchiveID = board.ID
+	sessionBoard.Title = board.Title
+	sessionBoard.Type = board.Type
+	sessionBoard.IsTemplate = board.IsTemplate
+	sessionBoard.Blocks = len(bab.Blocks)
+	for _, block := range bab.Blocks {
+		if block.Type == model.TypeCard {
+			sessionBoard.Cards++
+		}
+	}
+
+	if err := validateImportedBoards(bab.Boards, opt.BoardValidator); err != nil {
+		sessionBoard.Errors = append(sessionBoard.Errors, err.Error())
+	}
+
+	for _, existing := range existingBoards {
+		if existing.ID == board.ID || (existing.Title == board.Title && existing.IsTemplate == board.IsTemplate) {
+			sessionBoard.ExistingBoardID = existing.ID
+			break
+		}
+	}
+
+	var userIDs []string
+	mapImportedUsers(bab, members, func(userID string) string {
+		userIDs = append(userIDs, userID)
+		return userID
+	})
+	for _, timeEntry := range timeEntries {
+		userIDs = append(userIDs, timeEntry.UserID)
+	}
+	return sessionBoard, userIDs
+}
+
+// validateImportSessionConfirm checks that the resolutions and the user map
+// of a confirmation can be applied to the session.
+func (a *App) validateImportSessionConfirm(session *model.ImportSession, confirm *model.ImportSessionConfirm) error {
+	archiveIDs := make(map[string]bool, len(session.Boards))
+	for _, sessionBoard := range session.Boards {
+		archiveIDs[sessionBoard.ArchiveID] = true
+		if confirm.Resolution(sessionBoard.ArchiveID).Action == model.ImportBoardSkip {
+			continue
+		}
+		if len(sessionBoard.Errors) != 0 {
+			return model.NewErrBadRequest(fmt.Sprintf("board %s cannot be imported and must be skipped: %s", sessionBoard.ArchiveID, sessionBoard.Errors[0]))
+		}
+	}
+
+	for archiveID := range confirm.Boards {
+		if !archiveIDs[archiveID] {
+			return model.NewErrBadRequest(fmt.Sprintf("board %s is not in the archive", archiveID))
+		}
+	}
+
+	for _, userID := range confirm.UserMap {
+		if _, err := a.GetUser(userID); err != nil {
+			return model.NewErrBadRequest(fmt.Sprintf("cannot map to user %s: %s", userID, err))
+		}
+	}
+	return nil
+}
+
+// importSessionBoards imports the boards of a session. An error is returned
+// only if the archive cannot be read, in which case nothing is imported.
+func (a *App) importSessionBoards(session *model.ImportSession, confirm *model.ImportSessionConfirm, opt model.ImportArchiveOptions) error {
+	reader, err := a.filesBackend.Reader(importSessionArchivePath(session))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	entries, _, err := a.readImportArchive(reader)
+	if err != nil {
+		return err
+	}
+	if len(entries) != len(session.Boards) {
+		return errArchiveChanged
+	}
+
+	cardMap := make(map[string]*model.Block)
+	var cardLinks []*model.CardLink
+	for i, entry := range entries {
+		sessionBoard := session.Boards[i]
+		resolution := confirm.Resolution(sessionBoard.ArchiveID)
+		sessionBoard.Action = resolution.Action
+		if resolution.Action == model.ImportBoardSkip {
+			continue
+		}
+
+		board, boardCardLinks, boardCardMap, err := a.importArchiveBoard(entry, resolution, confirm, opt)
+		if err != nil {
+			a.logger.Warn("Cannot import board of import session",
+				mlog.String("sessionID", session.ID),
+				mlog.String("archiveID", sessionBoard.ArchiveID),
+				mlog.Err(err),
+			)
+			sessionBoard.ImportError = err.Error()
+			continue
+		}
+		sessionBoard.BoardID = board.ID
+		cardLinks = append(cardLinks, boardCardLinks...)
+		for oldID, card := range boardCardMap {
+			cardMap[oldID] = card
+		}
+	}
+
+	a.importCardLinks(cardLinks, cardMap, opt.ModifiedBy)
+	return nil
+}
+
+// importArchiveBoard imports a board of an archive. Its files are stored
+// first, so that the board, its blocks and its members can be created in a
+// single transaction with the file ids already replaced.
+func (a *App) importArchiveBoard(entry *archiveBoardEntry, resolution model.ImportBoardResolution, confirm *model.ImportSessionConfirm, opt model.ImportArchiveOptions) (*model.Board, []*model.CardLink, map[string]*model.Block, error) {
+	bab, members, cardLinks, timeEntries, err := a.parseArchiveBoard(entry, opt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err = validateImportedBoards(bab.Boards, opt.BoardValidator); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if resolution.Action == model.ImportBoardRename {
+		for _, board := range bab.Boards {
+			board.Title = resolution.Title
+		}
+	}
+	mapImportedUsers(bab, members, confirm.MapUser)
+
+	a.fixBoardsandBlocks(bab, opt)
+	if len(bab.Boards) == 0 {
+		return nil, nil, nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+	}
+
+	// the ids are regenerated in place, keep track of the original card ids
+	oldCardIDs := make(map[*model.Block]string)
+	for _, block := range bab.Blocks {
+		if block.Type == model.TypeCard {
+			oldCardIDs[block] = block.ID
+		}
+	}
+
+	bab, err = model.GenerateBoardsAndBlocksIDs(bab, a.logger)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error generating archive block IDs: %w", err)
+	}
+	board := bab.Boards[0]
+
+	filePaths, fileMap, err := a.saveArchiveBoardFiles(entry, board, opt)
+	if err != nil {
+		a.removeImportedFiles(filePaths)
+		return nil, nil, nil, err
+	}
+	replaceImportedFileIDs(bab.Blocks, fileMap)
+
+	newBab, err := a.createBoardsAndBlocksWithMembers(bab, a.importedBoardMembers(board, members, opt), opt.ModifiedBy)
+	if err != nil {
+		a.removeImportedFiles(filePaths)
+		return nil, nil, nil, fmt.Errorf("error inserting archive blocks: %w", err)
+	}
+
+	cardMap := make(map[string]*model.Block, len(oldCardIDs))
+	for block, oldID := range oldCardIDs {
+		cardMap[oldID] = block
+	}
+	a.importTimeEntries(timeEntries, cardMap, confirm.MapUser)
+	return newBab.Boards[0], cardLinks, cardMap, nil
+}
+
+// parseArchiveBoard parses the board.jsonl of a board of an archive.
+func (a *App) parseArchiveBoard(entry *archiveBoardEntry, opt model.ImportArchiveOptions) (*model.BoardsAndBlocks, []*model.BoardMember, []*model.CardLink, []*model.TimeEntry, error) {
+	rc, err := entry.open()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rc.Close()
+
+	bab, members, cardLinks, timeEntries, err := a.parseBoardsAndBlocks(rc, opt)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if len(bab.Boards) == 0 {
+		return nil, nil, nil, nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+	}
+	return bab, members, cardLinks, timeEntries, nil
+}
+
+// importedBoardMembers returns the members of an imported board: the user
+// importing it as admin, and the members of the archive that are users of
+// the system.
+func (a *App) importedBoardMembers(board *model.Board, boardMembers []*model.BoardMember, opt model.ImportArchiveOptions) []*model.BoardMember {
+	members := []*model.BoardMember{{
+		BoardID:     board.ID,
+		UserID:      opt.ModifiedBy,
+		SchemeAdmin: true,
+	}}
+
+	seen := map[string]bool{opt.ModifiedBy: true}
+	for _, boardMember := range boardMembers {
+		if seen[boardMember.UserID] {
+			continue
+		}
+		seen[boardMember.UserID] = true
+		if _, err := a.GetUser(boardMember.UserID); err != nil {
+			continue
+		}
+		members = append(members, &model.BoardMember{
+			BoardID:         board.ID,
+			UserID:          boardMember.UserID,
+			Roles:           boardMember.Roles,
+			MinimumRole:     boardMember.MinimumRole,
+			SchemeEditor:    boardMember.SchemeEditor,
+			SchemeCommenter: boardMember.SchemeCommenter,
+			SchemeViewer:    boardMember.SchemeViewer,
+			Synthetic:       boardMember.Synthetic,
+		})
+	}
+	return members
+}
+
+// saveArchiveBoardFiles stores the files of a board of an archive. The paths
+// of the stored files are returned along with a map of the file ids of the
+// archive to the new ones.
+func (a *App) saveArchiveBoardFiles(entry *archiveBoardEntry, board *model.Board, opt model.ImportArchiveOptions) ([]string, map[string]string, error) {
+	maxEntry := a.effectiveArchiveEntryMaxSize()
+	filePaths := make([]string, 0, len(entry.files))
+	fileMap := make(map[string]string, len(entry.files))
+
+	for _, file := range entry.files {
+		filename := path.Base(file.Name)
+		rc, err := file.Open()
+		if err != nil {
+			return filePaths, nil, fmt.Errorf("cannot read file %s: %w", filename, err)
+		}
+		fileReader := newLimitedReader(rc, maxEntry)
+		newFileName, err := a.SaveFile(fileReader, opt.TeamID, board.ID, filename, board.IsTemplate)
+		rc.Close()
+		if err != nil {
+			return filePaths, nil, fmt.Errorf("cannot import file %s: %w", filename, err)
+		}
+
+		filePath, err := getDestinationFilePath(board.IsTemplate, opt.TeamID, board.ID, newFileName)
+		if err == nil {
+			filePaths = append(filePaths, filePath)
+		}
+		if limitedReaderExceeded(fileReader) {
+			return filePaths, nil, fmt.Errorf("cannot import file %s: %w", filename, errSizeLimitExceeded)
+		}
+		fileMap[filename] = newFileName
+	}
+	return filePaths, fileMap, nil
+}
+
+func (a *App) removeImportedFiles(filePaths []string) {
+	for _, filePath := range filePaths {
+		if err := a.filesBackend.RemoveFile(filePath); err != nil {
+			a.logger.Warn("Cannot remove imported file", mlog.String("path", filePath), mlog.Err(err))
+		}
+	}
+}
+
+// readImportArchive lists the boards of an archive with their files. The
+// directories with files but no board are returned as orphans. Legacy
+// archives, a single JSONL file, contain a single board without files.
+func (a *App) readImportArchive(reader ReadCloseSeeker) ([]*archiveBoardEntry, []string, error) {
+	head := make([]byte, len(legacyFileBegin))
+	if _, err := io.ReadFull(reader, head); err == nil && string(head) == legacyFileBegin {
+		return []*archiveBoardEntry{{
+			open: func() (io.ReadCloser, error) {
+				if _, err := reader.Seek(0, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return io.NopCloser(reader), nil
+			},
+		}}, nil, nil
+	}
+
+	zr, err := newZipReader(reader)
+	if err != nil {
+		return nil, nil, model.NewErrBadRequest(fmt.Sprintf("invalid archive: %s", err))
+	}
+
+	maxEntry := a.effectiveArchiveEntryMaxSize()
+	var entries []*archiveBoardEntry
+	entriesByDir := make(map[string]*archiveBoardEntry)
+	filesByDir := make(map[string][]*zip.File)
+	var dirs []string
+
+	for _, file := range zr.File {
+		dir, filename := filepath.Split(file.Name)
+		dir = path.Clean(dir)
+
+		switch filename {
+		case "":
+			// directory entry
+		case "version.json":
+			rc, err := file.Open()
+			if err != nil {
+				return nil, nil, err
+			}
+			ver, err := parseVersionFile(rc, maxEntry)
+			rc.Close()
+			if err != nil {
+				return nil, nil, model.NewErrBadRequest(err.Error())
+			}
+			if ver != archiveVersion {
+				return nil, nil, model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
+			}
+		case "board.jsonl":
+			boardFile := file
+			entry := &archiveBoardEntry{
+				dir:  dir,
+				open: boardFile.Open,
+			}
+			entries = append(entries, entry)
+			entriesByDir[dir] = entry
+		default:
+			if _, ok := filesByDir[dir]; !ok {
+				dirs = append(dirs, dir)
+			}
+			filesByDir[dir] = append(filesByDir[dir], file)
+		}
+	}
+
+	var orphans []string
+	for _, dir := range dirs {
+		entry, ok := entriesByDir[dir]
+		if !ok {
+			orphans = append(orphans, dir)
+			continue
+		}
+		entry.files = filesByDir[dir]
+	}
+	return entries, orphans, nil
+}
+
+func (a *App) removeImportSessionArchive(session *model.ImportSession) {
+	if err := a.filesBackend.RemoveFile(importSessionArchivePath(session)); err != nil {
+		a.logger.Warn("Cannot remove import session archive", mlog.String("sessionID", session.ID), mlog.Err(err))
+	}
+}
+
+func importSessionArchivePath(session *model.ImportSession) string {
+	return filepath.Join("boards", "imports", session.TeamID, session.ID+".boardarchive")
+}
+
+// mapImportedUsers calls mapUser with the users referenced by the boards,
+// blocks and members of an import, and replaces them with the returned ids.
+// The users set in the person properties of the cards are mapped too.
+func mapImportedUsers(bab *model.BoardsAndBlocks, members []*model.BoardMember, mapUser func(userID string) string) {
+	mapID := func(userID string) string {
+		if userID == "" {
+			return ""
+		}
+		return mapUser(userID)
+	}
+
+	schemas := make(map[string]model.PropSchema, len(bab.Boards))
+	for _, board := range bab.Boards {
+		board.CreatedBy = mapID(board.CreatedBy)
+		if schema, err := model.ParsePropertySchema(board); err == nil {
+			schemas[board.ID] = schema
+		}
+	}
+
+	for _, member := range members {
+		member.UserID = mapID(member.UserID)
+	}
+
+	for _, block := range bab.Blocks {
+		block.CreatedBy = mapID(block.CreatedBy)
+		if block.Type != model.TypeCard {
+			continue
+		}
+		properties, ok := block.Fields["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		schema := schemas[block.BoardID]
+		for propID, value := range properties {
+			switch schema[propID].Type {
+			case "person":
+				if userID, ok := value.(string); ok {
+					properties[propID] = mapID(userID)
+				}
+			case "multiPerson":
+				if values, ok := value.([]interface{}); ok {
+					for i, v := range values {
+						if userID, ok := v.(string); ok {
+							values[i] = mapID(userID)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// replaceImportedFileIDs replaces the file ids of the image and attachment
+// blocks of an import with the ids of the stored files.
+func replaceImportedFileIDs(blocks []*model.Block, fileMap map[string]string) {
+	for _, block := range blocks {
+		for _, field := range []string{model.BlockFieldFileId, model.BlockFieldAttachmentId} {
+			if fileID, ok := block.Fields[field].(string); ok {
+				if newFileID, ok := fileMap[fileID]; ok {
+					block.Fields[field] = newFileID
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	sessionTestBoardA = "bfoi6yy6pa3yzika53spj7pq9ee"
+	sessionTestBoardB = "bjaqxtbyqz3bu7pgyddpgpms74a"
+	sessionTestFileID = "7xhwgf5r15fr3dryfozf1dmy41r.png"
+	sessionOldUserID  = "nto73edn5ir6ifimo5a53y1dwa"
+	sessionKnownUser  = "f1tydgc697fcbp8ampr6881jea"
+)
+
+//nolint:lll
+const sessionBoardAJSONL = `{"type":"board","data":{"id":"` + sessionTestBoardA + `","createdBy":"` + sessionOldUserID + `","type":"P","title":"Roadmap","cardProperties":[{"id":"aohjkzt769rxhtcz1o9xcoce5to","name":"Owner","options":[],"type":"person"}],"createAt":1,"updateAt":1}}
+{"type":"block","data":{"id":"ckpc3b1dp3pbw7bqntfryy9jbzo","parentId":"` + sessionTestBoardA + `","createdBy":"` + sessionOldUserID + `","schema":1,"type":"card","title":"Plan","fields":{"properties":{"aohjkzt769rxhtcz1o9xcoce5to":"` + sessionOldUserID + `"}},"createAt":1,"updateAt":1,"boardId":"` + sessionTestBoardA + `"}}
+{"type":"block","data":{"id":"ixcsbtngyb3gb8kr3x1fmxzx9ta","parentId":"ckpc3b1dp3pbw7bqntfryy9jbzo","createdBy":"` + sessionOldUserID + `","schema":1,"type":"image","title":"","fields":{"fileId":"` + sessionTestFileID + `"},"createAt":1,"updateAt":1,"boardId":"` + sessionTestBoardA + `"}}
+{"type":"boardMember","data":{"boardId":"` + sessionTestBoardA + `","userId":"` + sessionOldUserID + `","schemeEditor":true}}
+`
+
+//nolint:lll
+const sessionBoardBJSONL = `{"type":"board","data":{"id":"` + sessionTestBoardB + `","createdBy":"` + sessionKnownUser + `","type":"P","title":"Existing","createAt":1,"updateAt":1}}
+`
+
+func buildImportSessionArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	writeZipEntry(t, zw, "version.json", []byte(`{"version":2}`))
+	writeZipEntry(t, zw, sessionTestBoardA+"/board.jsonl", []byte(sessionBoardAJSONL))
+	writeZipEntry(t, zw, sessionTestBoardA+"/"+sessionTestFileID, []byte("image"))
+	writeZipEntry(t, zw, sessionTestBoardB+"/board.jsonl", []byte(sessionBoardBJSONL))
+	writeZipEntry(t, zw, "orphan/file.png", []byte("orphan"))
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func (th *TestHelper) createImportSession(t *testing.T, userID string) *model.ImportSession {
+	th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+	th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+	th.Store.EXPECT().GetBoardsForUserAndTeam(userID, importTestTeamID, true).Return([]*model.Board{
+		{ID: "existing-board-id", TeamID: importTestTeamID, Title: "Existing"},
+	}, nil)
+	th.Store.EXPECT().GetUserByID(gomock.Any()).DoAndReturn(func(id string) (*model.User, error) {
+		if id == sessionOldUserID {
+			return nil, model.NewErrNotFound("user")
+		}
+		return &model.User{ID: id}, nil
+	}).AnyTimes()
+	th.Store.EXPECT().InsertImportSession(gomock.Any()).Return(nil)
+
+	session, err := th.App.CreateImportSession(bytes.NewReader(buildImportSessionArchive(t)), model.ImportArchiveOptions{
+		TeamID:     importTestTeamID,
+		ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func TestCreateImportSession(t *testing.T) {
+	const userID = "user-id"
+
+	t.Run("previews the archive", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		session := th.createImportSession(t, userID)
+
+		assert.Equal(t, model.ImportSessionPending, session.Status)
+		assert.Equal(t, []string{importSessionArchivePath(session)}, files.paths())
+		require.Len(t, session.Boards, 2)
+
+		boardA := session.Boards[0]
+		assert.Equal(t, sessionTestBoardA, boardA.ArchiveID)
+		assert.Equal(t, "Roadmap", boardA.Title)
+		assert.Equal(t, 1, boardA.Cards)
+		assert.Equal(t, 2, boardA.Blocks)
+		assert.Equal(t, 1, boardA.Files)
+		assert.Empty(t, boardA.ExistingBoardID)
+		assert.Empty(t, boardA.Errors)
+
+		boardB := session.Boards[1]
+		assert.Equal(t, "Existing", boardB.Title)
+		assert.Equal(t, "existing-board-id", boardB.ExistingBoardID)
+
+		assert.Equal(t, []string{sessionOldUserID, sessionKnownUser}, session.Users)
+		assert.Equal(t, []string{sessionOldUserID}, session.UnresolvedUsers)
+		require.Len(t, session.Errors, 1)
+		assert.Contains(t, session.Errors[0], "orphan")
+	})
+
+	t.Run("rejects invalid archives", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		_, err := th.App.CreateImportSession(strings.NewReader("not an archive"), model.ImportArchiveOptions{
+			TeamID:     importTestTeamID,
+			ModifiedBy: userID,
+		})
+		require.True(t, model.IsErrBadRequest(err))
+		assert.Empty(t, files.paths())
+	})
+}
+
+func TestConfirmImportSession(t *testing.T) {
+	const userID = "user-id"
+
+	t.Run("imports the boards with the resolutions", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		session := th.createImportSession(t, userID)
+
+		th.Store.EXPECT().UpdateImportSessionStatus(session.ID, model.ImportSessionPending, model.ImportSessionImporting).Return(true, nil)
+		th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+		var savedFile *mm_model.FileInfo
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).DoAndReturn(func(fileInfo *mm_model.FileInfo) error {
+			savedFile = fileInfo
+			return nil
+		})
+		th.Store.EXPECT().GetFileInfo(gomock.Any()).DoAndReturn(func(id string) (*mm_model.FileInfo, error) {
+			return savedFile, nil
+		})
+
+		var bab *model.BoardsAndBlocks
+		var members []*model.BoardMember
+		th.Store.EXPECT().CreateBoardsAndBlocksWithMembers(gomock.Any(), gomock.Any(), userID).DoAndReturn(
+			func(newBab *model.BoardsAndBlocks, newMembers []*model.BoardMember, _ string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+				bab = newBab
+				members = newMembers
+				return newBab, newMembers, nil
+			})
+		th.Store.EXPECT().GetMembersForBoard(gomock.Any()).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetUserCategoryBoards(userID, importTestTeamID).Return([]model.CategoryBoards{
+			{Category: model.Category{ID: "default_category_id", Name: "Boards", Type: model.CategoryTypeSystem}},
+		}, nil).AnyTimes()
+		th.Store.EXPECT().AddUpdateCategoryBoard(userID, "default_category_id", gomock.Any()).Return(nil).AnyTimes()
+		th.Store.EXPECT().UpdateImportSession(session).Return(nil)
+
+		session, err := th.App.ConfirmImportSession(session, &model.ImportSessionConfirm{
+			UserMap: map[string]string{sessionOldUserID: userID},
+			Boards: map[string]model.ImportBoardResolution{
+				sessionTestBoardA: {Action: model.ImportBoardRename, Title: "Roadmap (restored)"},
+				sessionTestBoardB: {Action: model.ImportBoardSkip},
+			},
+		}, model.ImportArchiveOptions{TeamID: importTestTeamID, ModifiedBy: userID})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ImportSessionCompleted, session.Status)
+		assert.Equal(t, model.ImportBoardSkip, session.Boards[1].Action)
+		assert.Empty(t, session.Boards[1].BoardID)
+		assert.Equal(t, model.ImportBoardRename, session.Boards[0].Action)
+		assert.Empty(t, session.Boards[0].ImportError)
+
+		require.NotNil(t, bab)
+		require.Len(t, bab.Boards, 1)
+		board := bab.Boards[0]
+		assert.Equal(t, session.Boards[0].BoardID, board.ID)
+		assert.NotEqual(t, sessionTestBoardA, board.ID)
+		assert.Equal(t, "Roadmap (restored)", board.Title)
+		assert.Equal(t, userID, board.CreatedBy)
+
+		for _, block := range bab.Blocks {
+			assert.Equal(t, userID, block.CreatedBy)
+			switch block.Type {
+			case model.TypeCard:
+				properties := block.Fields["properties"].(map[string]interface{})
+				assert.Equal(t, userID, properties["aohjkzt769rxhtcz1o9xcoce5to"])
+			case model.TypeImage:
+				fileID := block.Fields[model.BlockFieldFileId].(string)
+				assert.NotEqual(t, sessionTestFileID, fileID)
+				filePath, err := getDestinationFilePath(false, importTestTeamID, board.ID, fileID)
+				require.NoError(t, err)
+				assert.Equal(t, filePath, savedFile.Path)
+			}
+		}
+
+		// the archive user is mapped to the importing user, who is added once
+		require.Len(t, members, 1)
+		assert.Equal(t, userID, members[0].UserID)
+		assert.True(t, members[0].SchemeAdmin)
+
+		// the archive is removed once imported
+		assert.Equal(t, []string{savedFile.Path}, files.paths())
+	})
+
+	t.Run("a failed board leaves no files", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		files := newMemFileBackend()
+		th.App.filesBackend = files
+
+		session := th.createImportSession(t, userID)
+
+		th.Store.EXPECT().UpdateImportSessionStatus(session.ID, model.ImportSessionPending, model.ImportSessionImporting).Return(true, nil)
+		th.expectBoardImportPermissions(userID, importTestTeamID, model.BoardTypePrivate)
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+		th.Store.EXPECT().GetFileInfo(gomock.Any()).Return(nil, model.NewErrNotFound("file info"))
+		th.Store.EXPECT().CreateBoardsAndBlocksWithMembers(gomock.Any(), gomock.Any(), userID).Return(nil, nil, errors.New("db error"))
+		th.Store.EXPECT().UpdateImportSession(session).Return(nil)
+
+		session, err := th.App.ConfirmImportSession(session, &model.ImportSessionConfirm{
+			Boards: map[string]model.ImportBoardResolution{
+				sessionTestBoardB: {Action: model.ImportBoardSkip},
+			},
+		}, model.ImportArchiveOptions{TeamID: importTestTeamID, ModifiedBy: userID})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ImportSessionCompleted, session.Status)
+		assert.Empty(t, session.Boards[0].BoardID)
+		assert.Contains(t, session.Boards[0].ImportError, "db error")
+		assert.Empty(t, files.paths())
+	})
+
+	t.Run("rejects invalid confirmations", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		newSession := func() *model.ImportSession {
+			return &model.ImportSession{
+				ID:     "session-id",
+				Status: model.ImportSessionPending,
+				Boards: []*model.ImportSessionBoard{
+					{ArchiveID: "board-1"},
+					{ArchiveID: "board-2", Errors: []string{"access denied"}},
+				},
+			}
+		}
+		opt := model.ImportArchiveOptions{TeamID: importTestTeamID, ModifiedBy: userID}
+
+		// a board with errors must be skipped
+		_, err := th.App.ConfirmImportSession(newSession(), &model.ImportSessionConfirm{}, opt)
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = th.App.ConfirmImportSession(newSession(), &model.ImportSessionConfirm{
+			Boards: map[string]model.ImportBoardResolution{
+				"board-2": {Action: model.ImportBoardSkip},
+				"board-3": {Action: model.ImportBoardSkip},
+			},
+		}, opt)
+		require.True(t, model.IsErrBadRequest(err))
+
+		th.Store.EXPECT().GetUserByID("missing-user").Return(nil, model.NewErrNotFound("user"))
+		_, err = th.App.ConfirmImportSession(newSession(), &model.ImportSessionConfirm{
+			UserMap: map[string]string{"old-user": "missing-user"},
+			Boards: map[string]model.ImportBoardResolution{
+				"board-2": {Action: model.ImportBoardSkip},
+			},
+		}, opt)
+		require.True(t, model.IsErrBadRequest(err))
+
+		confirmed := newSession()
+		confirmed.Status = model.ImportSessionCompleted
+		_, err = th.App.ConfirmImportSession(confirmed, &model.ImportSessionConfirm{}, opt)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
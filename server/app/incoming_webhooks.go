@@ -0,0 +1,101 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/subtle"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+func (a *App) GetIncomingWebhook(boardID string) (*model.IncomingWebhook, error) {
+	return a.store.GetIncomingWebhook(boardID)
+}
+
+// UpsertIncomingWebhook stores the incoming webhook settings of a board,
+// generating a token if none is provided.
+func (a *App) UpsertIncomingWebhook(webhook model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	if webhook.Token == "" {
+		webhook.Token = utils.NewID(utils.IDTypeToken)
+	}
+
+	if err := a.store.UpsertIncomingWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return a.store.GetIncomingWebhook(webhook.ID)
+}
+
+// GetIncomingWebhookForToken returns the incoming webhook of a board if it is
+// enabled and the token matches.
+func (a *App) GetIncomingWebhookForToken(boardID, token string) (*model.IncomingWebhook, error) {
+	webhook, err := a.store.GetIncomingWebhook(boardID)
+	if model.IsErrNotFound(err) {
+		return nil, model.NewErrUnauthorized("invalid incoming webhook token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !webhook.Enabled || subtle.ConstantTimeCompare([]byte(webhook.Token), []byte(token)) != 1 {
+		return nil, model.NewErrUnauthorized("invalid incoming webhook token")
+	}
+
+	// cards are created on behalf of the user that set up the webhook,
+	// who must still be able to do so
+	if !a.permissions.HasPermissionToBoard(webhook.ModifiedBy, boardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrPermission("incoming webhook owner cannot manage the board cards")
+	}
+
+	return webhook, nil
+}
+
+// ApplyIncomingWebhookCard creates a card from the payload, or patches the
+// card referenced by the payload, on behalf of the user. It returns the card
+// and whether it was created.
+func (a *App) ApplyIncomingWebhookCard(boardID string, payload *model.IncomingWebhookCard, userID string) (*model.Card, bool, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if payload.CardID == "" {
+		card, err := payload.ToCard(board)
+		if err != nil {
+			return nil, false, err
+		}
+		card.PopulateWithBoardID(boardID)
+		if err := card.CheckValid(); err != nil {
+			return nil, false, model.NewErrBadRequest(err.Error())
+		}
+
+		newCard, err := a.CreateCard(card, boardID, userID, false)
+		if err != nil {
+			return nil, false, err
+		}
+		return newCard, true, nil
+	}
+
+	card, err := a.GetCardByID(payload.CardID)
+	if err != nil {
+		return nil, false, err
+	}
+	if card.BoardID != boardID {
+		return nil, false, model.NewErrNotFound("card ID=" + payload.CardID)
+	}
+
+	patch, err := payload.ToCardPatch(board)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := patch.CheckValid(); err != nil {
+		return nil, false, model.NewErrBadRequest(err.Error())
+	}
+
+	newCard, err := a.PatchCard(patch, card.ID, userID, false)
+	if err != nil {
+		return nil, false, err
+	}
+	return newCard, false, nil
+}
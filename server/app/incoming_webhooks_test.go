@@ -0,0 +1,117 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertIncomingWebhook(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.Store.EXPECT().UpsertIncomingWebhook(gomock.Any()).DoAndReturn(func(webhook model.IncomingWebhook) error {
+		require.NotEmpty(t, webhook.Token)
+		return nil
+	})
+	th.Store.EXPECT().GetIncomingWebhook("board-id").Return(&model.IncomingWebhook{ID: "board-id", Token: "token"}, nil)
+
+	webhook, err := th.App.UpsertIncomingWebhook(model.IncomingWebhook{ID: "board-id", Enabled: true, ModifiedBy: "user-id"})
+	require.NoError(t, err)
+	require.Equal(t, "token", webhook.Token)
+}
+
+func TestGetIncomingWebhookForToken(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	testCases := []struct {
+		name    string
+		webhook *model.IncomingWebhook
+		err     error
+		token   string
+	}{
+		{"not configured", nil, model.NewErrNotFound("incoming webhook"), "token"},
+		{"disabled", &model.IncomingWebhook{ID: "board-id", Token: "token"}, nil, "token"},
+		{"wrong token", &model.IncomingWebhook{ID: "board-id", Enabled: true, Token: "token"}, nil, "other"},
+		{"empty token", &model.IncomingWebhook{ID: "board-id", Enabled: true, Token: "token"}, nil, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			th.Store.EXPECT().GetIncomingWebhook("board-id").Return(tc.webhook, tc.err)
+
+			webhook, err := th.App.GetIncomingWebhookForToken("board-id", tc.token)
+			require.Nil(t, webhook)
+			require.True(t, model.IsErrUnauthorized(err))
+		})
+	}
+}
+
+func TestApplyIncomingWebhookCard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-id",
+		CardProperties: []map[string]any{
+			{
+				"id":   "status-id",
+				"name": "Status",
+				"type": "select",
+				"options": []any{
+					map[string]any{"id": "done-id", "value": "Done"},
+				},
+			},
+		},
+	}
+	title := "Build failed"
+
+	t.Run("create card", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil).Times(2)
+		th.Store.EXPECT().GetBlock(gomock.Any()).Return(nil, model.NewErrNotFound("block not found"))
+		th.Store.EXPECT().InsertBlock(gomock.AssignableToTypeOf(reflect.TypeOf(&model.Block{})), "user-id").Return(nil)
+		th.Store.EXPECT().GetMembersForBoard(gomock.Any()).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		card, created, err := th.App.ApplyIncomingWebhookCard("board-id", &model.IncomingWebhookCard{
+			Title:      &title,
+			Properties: map[string]any{"Status": "Done"},
+		}, "user-id")
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, title, card.Title)
+		require.Equal(t, "user-id", card.CreatedBy)
+		require.Equal(t, map[string]any{"status-id": "done-id"}, card.Properties)
+	})
+
+	t.Run("unknown property", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+
+		_, _, err := th.App.ApplyIncomingWebhookCard("board-id", &model.IncomingWebhookCard{
+			Properties: map[string]any{"Priority": "High"},
+		}, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("card from another board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetBlock("card-id").Return(&model.Block{
+			ID:      "card-id",
+			BoardID: "other-board-id",
+			Type:    model.TypeCard,
+		}, nil)
+		th.Store.EXPECT().GetTimeSpentForCards([]string{"card-id"}).Return(map[string]int64{}, nil)
+
+		_, _, err := th.App.ApplyIncomingWebhookCard("board-id", &model.IncomingWebhookCard{
+			CardID: "card-id",
+			Title:  &title,
+		}, "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// GetBoardPresence returns the users currently viewing or editing a board
+// or its cards.
+func (a *App) GetBoardPresence(boardID string) []*model.Presence {
+	return a.wsAdapter.GetBoardPresence(boardID)
+}
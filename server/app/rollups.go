@@ -0,0 +1,71 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// ResolveRollups computes the values of the rollup properties of the cards
+// of a board for a user. The rollups of related boards the user can't view
+// are left empty. Blocks that are not cards are left untouched.
+func (a *App) ResolveRollups(boardID string, blocks []*model.Block, userID string) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+	return model.ResolveRollups(board, blocks, a.store, a.canViewRelatedBoard(userID))
+}
+
+// canViewRelatedBoard returns the check of the related boards of the
+// rollups computed for a user.
+func (a *App) canViewRelatedBoard(userID string) func(*model.Board) bool {
+	return func(relatedBoard *model.Board) bool {
+		return userID != "" && a.permissions.HasPermissionToBoard(userID, relatedBoard.ID, model.PermissionViewBoard)
+	}
+}
+
+// ResolveCardRollups computes the values of the rollup properties of cards
+// of a board for a user.
+func (a *App) ResolveCardRollups(boardID string, cards []*model.Card, userID string) error {
+	blocks := make([]*model.Block, 0, len(cards))
+	for _, card := range cards {
+		blocks = append(blocks, model.Card2Block(card))
+	}
+
+	if err := a.ResolveRollups(boardID, blocks, userID); err != nil {
+		return err
+	}
+
+	for i, card := range cards {
+		if properties, ok := blocks[i].Fields[model.BlockFieldProperties].(map[string]any); ok {
+			card.Properties = properties
+		}
+	}
+	return nil
+}
+
+// validateCardPropertiesForBoard checks the properties in the fields of a
+// card against the schema of its board, and drops the values of computed
+// properties so that they are never stored.
+func validateCardPropertiesForBoard(board *model.Board, fields map[string]any) error {
+	properties, ok := fields[model.BlockFieldProperties].(map[string]any)
+	if !ok || len(properties) == 0 {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+	if err := schema.ValidateCardPropertyValues(properties); err != nil {
+		return model.NewErrBadRequest(err.Error())
+	}
+	schema.RemoveComputedProperties(properties)
+	return nil
+}
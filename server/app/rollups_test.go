@@ -0,0 +1,57 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+func TestResolveRollups(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID:     "board-1",
+		TeamID: "team-1",
+		CardProperties: []map[string]any{
+			{"id": "tasks-id", "name": "Tasks", "type": model.PropTypeRelation, "relationBoardId": "board-2"},
+			{"id": "count-id", "name": "Count", "type": model.PropTypeRollup, "rollup": map[string]any{
+				"relationPropertyId": "tasks-id", "function": "count",
+			}},
+		},
+	}
+	relatedBoard := &model.Board{ID: "board-2", TeamID: "team-1", Type: model.BoardTypePrivate}
+	task := &model.Block{ID: "task-1", BoardID: relatedBoard.ID, Type: model.TypeCard}
+
+	newCard := func() *model.Block {
+		return &model.Block{ID: "card-1", BoardID: board.ID, Type: model.TypeCard, Fields: map[string]any{
+			"properties": map[string]any{"tasks-id": []any{task.ID}},
+		}}
+	}
+
+	t.Run("the user can view the related board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{task.ID}).Return([]*model.Block{task}, nil)
+		th.Store.EXPECT().GetBoard(relatedBoard.ID).Return(relatedBoard, nil)
+		th.expectBoardEditor("user-id", relatedBoard.ID, relatedBoard.TeamID)
+
+		card := newCard()
+		require.NoError(t, th.App.ResolveRollups(board.ID, []*model.Block{card}, "user-id"))
+		require.Equal(t, "1", card.Fields["properties"].(map[string]any)["count-id"])
+	})
+
+	t.Run("the related board is hidden from anonymous users", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{task.ID}).Return([]*model.Block{task}, nil)
+		th.Store.EXPECT().GetBoard(relatedBoard.ID).Return(relatedBoard, nil)
+
+		card := newCard()
+		require.NoError(t, th.App.ResolveRollups(board.ID, []*model.Block{card}, ""))
+		require.NotContains(t, card.Fields["properties"], "count-id")
+	})
+}
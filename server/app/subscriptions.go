@@ -11,6 +11,12 @@ import (
 )
 
 func (a *App) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
+	if sub.Digest {
+		if err := a.ensureDigestSettings(sub.SubscriberID); err != nil {
+			return nil, err
+		}
+	}
+
 	sub, err := a.store.CreateSubscription(sub)
 	if err != nil {
 		return nil, err
@@ -34,10 +40,41 @@ func (a *App) DeleteSubscription(blockID string, subscriberID string) (*model.Su
 	return sub, nil
 }
 
+func (a *App) GetSubscription(blockID string, subscriberID string) (*model.Subscription, error) {
+	return a.store.GetSubscription(blockID, subscriberID)
+}
+
 func (a *App) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
 	return a.store.GetSubscriptions(subscriberID)
 }
 
+// ValidateSubscriptionChannel checks that the changes of a board can be
+// posted into a channel, which must belong to the team of the board.
+func (a *App) ValidateSubscriptionChannel(boardID string, channelID string) error {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+
+	channel, err := a.store.GetChannel(board.TeamID, channelID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrBadRequest("invalid channel for subscription")
+	}
+	if err != nil {
+		return err
+	}
+
+	if channel.DeleteAt != 0 || channel.TeamId != board.TeamID {
+		return model.NewErrBadRequest("the channel must belong to the team of the board")
+	}
+	return nil
+}
+
+// GetCardWatchers returns the users and channels subscribed to a card.
+func (a *App) GetCardWatchers(cardID string) ([]*model.Subscriber, error) {
+	return a.store.GetSubscribersForBlock(cardID)
+}
+
 func (a *App) notifySubscriptionChanged(subscription *model.Subscription) {
 	if a.notifications == nil {
 		return
@@ -0,0 +1,48 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestValidateSubscriptionChannel(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: "board-id", TeamID: "team-id"}
+
+	t.Run("channel of the team of the board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetChannel("team-id", "channel-id").Return(&mmModel.Channel{Id: "channel-id", TeamId: "team-id"}, nil)
+
+		require.NoError(t, th.App.ValidateSubscriptionChannel("board-id", "channel-id"))
+	})
+
+	t.Run("channel of another team", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetChannel("team-id", "channel-id").Return(&mmModel.Channel{Id: "channel-id", TeamId: "other-team-id"}, nil)
+
+		require.True(t, model.IsErrBadRequest(th.App.ValidateSubscriptionChannel("board-id", "channel-id")))
+	})
+
+	t.Run("deleted channel", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetChannel("team-id", "channel-id").Return(&mmModel.Channel{Id: "channel-id", TeamId: "team-id", DeleteAt: 1}, nil)
+
+		require.True(t, model.IsErrBadRequest(th.App.ValidateSubscriptionChannel("board-id", "channel-id")))
+	})
+
+	t.Run("missing channel", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(board, nil)
+		th.Store.EXPECT().GetChannel("team-id", "channel-id").Return(nil, model.NewErrNotFound("channel-id"))
+
+		require.True(t, model.IsErrBadRequest(th.App.ValidateSubscriptionChannel("board-id", "channel-id")))
+	})
+}
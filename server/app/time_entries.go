@@ -0,0 +1,202 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// CreateTimeEntry adds time spent by a user on a card, e.g. work that
+// wasn't timed.
+func (a *App) CreateTimeEntry(entry *model.TimeEntry, cardID, userID string) (*model.TimeEntry, error) {
+	card, err := a.getCardBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	entry.ID = utils.NewID(utils.IDTypeNone)
+	entry.BoardID = card.BoardID
+	entry.CardID = card.ID
+	entry.UserID = userID
+	entry.Running = false
+	entry.CreateAt = now
+	entry.UpdateAt = now
+	entry.DeleteAt = 0
+
+	if err := a.store.InsertTimeEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (a *App) GetTimeEntry(entryID string) (*model.TimeEntry, error) {
+	return a.store.GetTimeEntry(entryID)
+}
+
+func (a *App) GetTimeEntriesForCard(cardID string) ([]*model.TimeEntry, error) {
+	return a.store.GetTimeEntriesForCard(cardID)
+}
+
+// PatchTimeEntry updates the start, duration or note of a time entry. The
+// duration of a running entry is set when its timer is stopped.
+func (a *App) PatchTimeEntry(entry *model.TimeEntry, patch *model.TimeEntryPatch) (*model.TimeEntry, error) {
+	entry = patch.Patch(entry)
+	entry.UpdateAt = utils.GetMillis()
+
+	if err := a.store.UpdateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (a *App) DeleteTimeEntry(entryID string) error {
+	return a.store.DeleteTimeEntry(entryID)
+}
+
+// StartTimer starts a timer for a user on a card. The timer the user was
+// running, if any, is stopped, so that a user has at most one running timer.
+func (a *App) StartTimer(cardID, userID, note string) (*model.TimeEntry, error) {
+	card, err := a.getCardBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	entry := &model.TimeEntry{
+		ID:       utils.NewID(utils.IDTypeNone),
+		BoardID:  card.BoardID,
+		CardID:   card.ID,
+		UserID:   userID,
+		StartAt:  now,
+		Running:  true,
+		Note:     note,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	stopped, err := a.store.StartTimeEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stopped {
+		a.logger.Debug("StartTimer stopped running timer",
+			mlog.String("userID", userID),
+			mlog.String("cardID", s.CardID),
+			mlog.String("timeEntryID", s.ID),
+		)
+	}
+	return entry, nil
+}
+
+// StopTimer stops the timer a user is running on a card.
+func (a *App) StopTimer(cardID, userID string) (*model.TimeEntry, error) {
+	entry, err := a.store.GetRunningTimeEntry(userID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.CardID != cardID {
+		return nil, model.NewErrNotFound("running time entry for cardID=" + cardID)
+	}
+
+	entry.Stop(utils.GetMillis())
+	if err := a.store.UpdateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetRunningTimer returns the time entry of the timer a user is running.
+func (a *App) GetRunningTimer(userID string) (*model.TimeEntry, error) {
+	return a.store.GetRunningTimeEntry(userID)
+}
+
+// GetBoardTimeSummary returns the time spent on the cards of a board over a
+// date range.
+func (a *App) GetBoardTimeSummary(boardID string, from, to int64) (*model.TimeSummary, error) {
+	return a.getTimeSummary(model.QueryTimeSummaryOptions{BoardID: boardID, From: from, To: to})
+}
+
+// GetUserTimeSummary returns the time spent by a user over a date range.
+func (a *App) GetUserTimeSummary(userID string, from, to int64) (*model.TimeSummary, error) {
+	return a.getTimeSummary(model.QueryTimeSummaryOptions{UserID: userID, From: from, To: to})
+}
+
+func (a *App) getTimeSummary(opts model.QueryTimeSummaryOptions) (*model.TimeSummary, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+
+	spent, err := a.store.GetTimeSpent(opts)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewTimeSummary(opts.From, opts.To, spent), nil
+}
+
+// addTimeSpent sets the time spent on the cards.
+func (a *App) addTimeSpent(cards ...*model.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+
+	timeSpent, err := a.store.GetTimeSpentForCards(cardIDs)
+	if err != nil {
+		return err
+	}
+	for _, card := range cards {
+		card.TimeSpent = timeSpent[card.ID]
+	}
+	return nil
+}
+
+// importTimeEntries creates the time entries of an archive on the imported
+// cards. `cards` maps the ids of the cards in the archive to the imported
+// cards, and `mapUser` maps the users of the archive to users of the system.
+// Running timers and entries of cards that are not in the archive are
+// skipped.
+func (a *App) importTimeEntries(entries []*model.TimeEntry, cards map[string]*model.Block, mapUser func(string) string) {
+	now := utils.GetMillis()
+	for _, entry := range entries {
+		card, ok := cards[entry.CardID]
+		if !ok || entry.Running {
+			a.logger.Debug("skipping time entry of archive",
+				mlog.String("cardID", entry.CardID),
+				mlog.Bool("running", entry.Running),
+			)
+			continue
+		}
+
+		userID := entry.UserID
+		if mapUser != nil {
+			userID = mapUser(userID)
+		}
+
+		err := a.store.InsertTimeEntry(&model.TimeEntry{
+			ID:       utils.NewID(utils.IDTypeNone),
+			BoardID:  card.BoardID,
+			CardID:   card.ID,
+			UserID:   userID,
+			StartAt:  entry.StartAt,
+			Duration: entry.Duration,
+			Note:     entry.Note,
+			CreateAt: now,
+			UpdateAt: now,
+		})
+		if err != nil {
+			a.logger.Error("Cannot import time entry",
+				mlog.String("cardID", card.ID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTimeEntry(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("entry on a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-1").Return(testLinksCard("card-1", "board-1", ""), nil)
+		th.Store.EXPECT().InsertTimeEntry(gomock.Any()).DoAndReturn(func(e *model.TimeEntry) error {
+			require.NotEmpty(t, e.ID)
+			require.Equal(t, "board-1", e.BoardID)
+			require.Equal(t, "card-1", e.CardID)
+			require.Equal(t, "user-id", e.UserID)
+			require.False(t, e.Running)
+			return nil
+		})
+
+		entry, err := th.App.CreateTimeEntry(&model.TimeEntry{StartAt: 1000, Duration: 60000, Running: true, UserID: "other-user"}, "card-1", "user-id")
+		require.NoError(t, err)
+		require.Equal(t, int64(60000), entry.Duration)
+	})
+
+	t.Run("not a card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("view-1").Return(&model.Block{ID: "view-1", BoardID: "board-1", Type: model.TypeView}, nil)
+
+		_, err := th.App.CreateTimeEntry(&model.TimeEntry{StartAt: 1000, Duration: 60000}, "view-1", "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestStartTimer(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.Store.EXPECT().GetBlock("card-1").Return(testLinksCard("card-1", "board-1", ""), nil)
+	th.Store.EXPECT().StartTimeEntry(gomock.Any()).DoAndReturn(func(e *model.TimeEntry) ([]*model.TimeEntry, error) {
+		require.True(t, e.Running)
+		require.Zero(t, e.Duration)
+		require.NotZero(t, e.StartAt)
+		require.Equal(t, "user-id", e.UserID)
+		require.Equal(t, "card-1", e.CardID)
+		return []*model.TimeEntry{{ID: "previous", CardID: "card-2"}}, nil
+	})
+
+	entry, err := th.App.StartTimer("card-1", "user-id", "fixing the build")
+	require.NoError(t, err)
+	require.Equal(t, "fixing the build", entry.Note)
+}
+
+func TestStopTimer(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("running on the card", func(t *testing.T) {
+		running := &model.TimeEntry{ID: "entry-1", CardID: "card-1", UserID: "user-id", StartAt: 1000, Running: true}
+		th.Store.EXPECT().GetRunningTimeEntry("user-id").Return(running, nil)
+		th.Store.EXPECT().UpdateTimeEntry(running).Return(nil)
+
+		entry, err := th.App.StopTimer("card-1", "user-id")
+		require.NoError(t, err)
+		require.False(t, entry.Running)
+		require.NotZero(t, entry.Duration)
+	})
+
+	t.Run("running on another card", func(t *testing.T) {
+		running := &model.TimeEntry{ID: "entry-1", CardID: "card-2", UserID: "user-id", StartAt: 1000, Running: true}
+		th.Store.EXPECT().GetRunningTimeEntry("user-id").Return(running, nil)
+
+		_, err := th.App.StopTimer("card-1", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("no timer running", func(t *testing.T) {
+		th.Store.EXPECT().GetRunningTimeEntry("user-id").Return(nil, model.NewErrNotFound("running time entry"))
+
+		_, err := th.App.StopTimer("card-1", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func TestGetBoardTimeSummary(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("sums the time spent", func(t *testing.T) {
+		opts := model.QueryTimeSummaryOptions{BoardID: "board-1", From: 1000, To: 2000}
+		th.Store.EXPECT().GetTimeSpent(opts).Return([]*model.TimeSpent{
+			{BoardID: "board-1", CardID: "card-1", UserID: "user-1", Duration: 100, Count: 1},
+			{BoardID: "board-1", CardID: "card-2", UserID: "user-1", Duration: 200, Count: 2},
+		}, nil)
+
+		summary, err := th.App.GetBoardTimeSummary("board-1", 1000, 2000)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), summary.Duration)
+		require.Len(t, summary.Cards, 2)
+		require.Equal(t, []*model.TimeSummaryItem{{ID: "user-1", Duration: 300, Count: 3}}, summary.Users)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := th.App.GetBoardTimeSummary("board-1", 2000, 1000)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestImportTimeEntries(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	cards := map[string]*model.Block{
+		"old-card": {ID: "new-card", BoardID: "new-board", Type: model.TypeCard},
+	}
+	entries := []*model.TimeEntry{
+		{ID: "entry-1", BoardID: "old-board", CardID: "old-card", UserID: "old-user", StartAt: 1000, Duration: 500, Note: "note"},
+		{ID: "entry-2", BoardID: "old-board", CardID: "old-card", UserID: "old-user", StartAt: 2000, Running: true},
+		{ID: "entry-3", BoardID: "old-board", CardID: "other-card", UserID: "old-user", StartAt: 3000, Duration: 500},
+	}
+
+	th.Store.EXPECT().InsertTimeEntry(gomock.Any()).DoAndReturn(func(e *model.TimeEntry) error {
+		require.NotEqual(t, "entry-1", e.ID)
+		require.Equal(t, "new-board", e.BoardID)
+		require.Equal(t, "new-card", e.CardID)
+		require.Equal(t, "new-user", e.UserID)
+		require.Equal(t, int64(1000), e.StartAt)
+		require.Equal(t, int64(500), e.Duration)
+		require.Equal(t, "note", e.Note)
+		return nil
+	})
+
+	th.App.importTimeEntries(entries, cards, func(userID string) string {
+		require.Equal(t, "old-user", userID)
+		return "new-user"
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// EvaluateView returns the cards of a view filtered, sorted and grouped
+// the same way the webapp renders them.
+func (a *App) EvaluateView(viewID string) (*model.ViewCards, error) {
+	view, err := a.getView(viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(view.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, _, err := a.store.QueryCards(viewQueryCardsOptions(view))
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*model.Card, 0, len(blocks))
+	for _, b := range blocks {
+		card, err := model.Block2Card(b)
+		if err != nil {
+			return nil, fmt.Errorf("Block2Card fail: %w", err)
+		}
+		cards = append(cards, card)
+	}
+
+	if len(view.SortOptions) == 0 {
+		sortCardsManually(cards, view.CardOrder)
+	}
+
+	result := &model.ViewCards{
+		ViewID:             view.ID,
+		ViewType:           view.ViewType,
+		VisiblePropertyIDs: view.VisiblePropertyIDs,
+		Cards:              cards,
+	}
+	if result.VisiblePropertyIDs == nil {
+		result.VisiblePropertyIDs = []string{}
+	}
+
+	if view.GroupByID == "" || (view.ViewType != "board" && view.ViewType != "table") {
+		return result, nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	groupBy, ok := schema[view.GroupByID]
+	if !ok {
+		// the group by property was deleted, so the view shows no groups
+		return result, nil
+	}
+
+	result.GroupByID = view.GroupByID
+	switch groupBy.Type {
+	case "person", "createdBy", "updatedBy":
+		result.Groups, result.HiddenGroups = groupCardsByPerson(cards, groupBy, view)
+	default:
+		result.Groups, result.HiddenGroups = groupCardsByOption(cards, groupBy, view)
+	}
+
+	return result, nil
+}
+
+// getView fetches a view block and parses its fields.
+func (a *App) getView(viewID string) (*model.View, error) {
+	block, err := a.store.GetBlock(viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := model.Block2View(block)
+	if errors.Is(err, model.ErrNotViewBlock) {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("block %s is not a view", viewID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// viewQueryCardsOptions returns the options of the query of the cards of
+// a view, with its filter and sort options.
+func viewQueryCardsOptions(view *model.View) model.QueryCardsOptions {
+	opts := model.QueryCardsOptions{
+		BoardID:     view.BoardID,
+		Filter:      view.Filter,
+		SortOptions: viewSortOptions(view),
+	}
+	if view.Filter != nil && view.Filter.IsValid() != nil {
+		// the webapp ignores malformed filters rather than failing
+		opts.Filter = nil
+	}
+	return opts
+}
+
+// viewSortOptions translates the sort options of a view into the ones of
+// a card query. The webapp applies each sort option on top of the previous
+// one, so the last option is the one with the most precedence, and ties
+// are broken by title.
+func viewSortOptions(view *model.View) []model.CardSortOption {
+	sortOptions := make([]model.CardSortOption, 0, len(view.SortOptions)+1)
+	hasTitle := false
+	for i := len(view.SortOptions) - 1; i >= 0; i-- {
+		sortOptions = append(sortOptions, view.SortOptions[i])
+		hasTitle = hasTitle || view.SortOptions[i].PropertyID == model.TitlePropertyID
+	}
+
+	if !hasTitle {
+		sortOptions = append(sortOptions, model.CardSortOption{PropertyID: model.TitlePropertyID})
+	}
+	return sortOptions
+}
+
+// sortCardsManually orders the cards following the manual card order of
+// a view. Cards missing from the order keep their relative position and
+// go after the ones that are in it.
+func sortCardsManually(cards []*model.Card, cardOrder []string) {
+	less := manualOrderLess(cardOrder)
+	sort.SliceStable(cards, func(i, j int) bool {
+		return less(cards[i].ID, cards[j].ID)
+	})
+}
+
+// manualOrderLess returns a function that compares card ids following the
+// manual card order of a view.
+func manualOrderLess(cardOrder []string) func(id1, id2 string) bool {
+	position := make(map[string]int, len(cardOrder))
+	for i, id := range cardOrder {
+		if _, ok := position[id]; !ok {
+			position[id] = i
+		}
+	}
+
+	return func(id1, id2 string) bool {
+		p1, ok1 := position[id1]
+		p2, ok2 := position[id2]
+		if ok1 && ok2 {
+			return p1 < p2
+		}
+		return ok1 && !ok2
+	}
+}
+
+// groupCardsByOption groups the cards by the option of a select property,
+// following the column order of the view. Options that are neither
+// visible nor hidden go after the visible ones, and the group of cards
+// without a value goes first unless the view placed it somewhere else.
+func groupCardsByOption(cards []*model.Card, groupBy model.PropDef, view *model.View) ([]*model.ViewCardGroup, []*model.ViewCardGroup) {
+	options := make([]model.PropDefOption, 0, len(groupBy.Options))
+	for _, opt := range groupBy.Options {
+		options = append(options, opt)
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].Index < options[j].Index })
+
+	visibleIDs := append([]string{}, view.VisibleOptionIDs...)
+	for _, opt := range options {
+		if !slices.Contains(view.VisibleOptionIDs, opt.ID) && !slices.Contains(view.HiddenOptionIDs, opt.ID) {
+			visibleIDs = append(visibleIDs, opt.ID)
+		}
+	}
+	if !slices.Contains(visibleIDs, "") && !slices.Contains(view.HiddenOptionIDs, "") {
+		visibleIDs = append([]string{""}, visibleIDs...)
+	}
+
+	makeGroups := func(optionIDs []string) []*model.ViewCardGroup {
+		groups := make([]*model.ViewCardGroup, 0, len(optionIDs))
+		for _, optionID := range optionIDs {
+			group := &model.ViewCardGroup{
+				OptionID:  optionID,
+				Collapsed: slices.Contains(view.CollapsedOptionIDs, optionID),
+				Cards:     []*model.Card{},
+			}
+
+			if optionID == "" {
+				group.Value = "No " + groupBy.Name
+			} else {
+				opt, ok := groupBy.Options[optionID]
+				if !ok {
+					// deleted options are ignored
+					continue
+				}
+				group.Value = opt.Value
+			}
+
+			for _, card := range cards {
+				value, _ := card.Properties[groupBy.ID].(string)
+				if _, ok := groupBy.Options[value]; !ok {
+					value = ""
+				}
+				if value == optionID {
+					group.Cards = append(group.Cards, card)
+				}
+			}
+			groups = append(groups, group)
+		}
+		return groups
+	}
+
+	return makeGroups(visibleIDs), makeGroups(view.HiddenOptionIDs)
+}
+
+// groupCardsByPerson groups the cards by the user of a person, createdBy
+// or updatedBy property, in the order in which each user first appears.
+func groupCardsByPerson(cards []*model.Card, groupBy model.PropDef, view *model.View) ([]*model.ViewCardGroup, []*model.ViewCardGroup) {
+	groupsByUser := map[string]*model.ViewCardGroup{}
+	visible := []*model.ViewCardGroup{}
+	hidden := []*model.ViewCardGroup{}
+
+	for _, card := range cards {
+		var userID string
+		switch groupBy.Type {
+		case "createdBy":
+			userID = card.CreatedBy
+		case "updatedBy":
+			userID = card.ModifiedBy
+		default:
+			userID, _ = card.Properties[groupBy.ID].(string)
+		}
+
+		group, ok := groupsByUser[userID]
+		if !ok {
+			group = &model.ViewCardGroup{
+				OptionID:  userID,
+				Value:     userID,
+				Collapsed: slices.Contains(view.CollapsedOptionIDs, userID),
+				Cards:     []*model.Card{},
+			}
+			groupsByUser[userID] = group
+
+			if slices.Contains(view.HiddenOptionIDs, userID) {
+				hidden = append(hidden, group)
+			} else {
+				visible = append(visible, group)
+			}
+		}
+		group.Cards = append(group.Cards, card)
+	}
+
+	return visible, hidden
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateView(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: utils.NewID(utils.IDTypeBoard),
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do", "color": ""},
+					map[string]interface{}{"id": "doing", "value": "Doing", "color": ""},
+					map[string]interface{}{"id": "done", "value": "Done", "color": ""},
+				},
+			},
+		},
+	}
+
+	newCard := func(id, status string) *model.Block {
+		properties := map[string]interface{}{}
+		if status != "" {
+			properties["status"] = status
+		}
+		return &model.Block{
+			ID:      id,
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Title:   id,
+			Fields:  map[string]interface{}{"properties": properties},
+		}
+	}
+	blocks := []*model.Block{
+		newCard("card1", "todo"),
+		newCard("card2", "done"),
+		newCard("card3", ""),
+		newCard("card4", "todo"),
+		newCard("card5", "deleted-option"),
+	}
+
+	filter := map[string]interface{}{
+		"operation": "and",
+		"filters":   []interface{}{},
+	}
+
+	cardIDs := func(cards []*model.Card) []string {
+		ids := make([]string, 0, len(cards))
+		for _, c := range cards {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	t.Run("manual order and groups", func(t *testing.T) {
+		view := &model.Block{
+			ID:      utils.NewID(utils.IDTypeView),
+			BoardID: board.ID,
+			Type:    model.TypeView,
+			Fields: map[string]interface{}{
+				"viewType":           "board",
+				"groupById":          "status",
+				"sortOptions":        []interface{}{},
+				"visibleOptionIds":   []interface{}{"done"},
+				"hiddenOptionIds":    []interface{}{"doing"},
+				"collapsedOptionIds": []interface{}{"todo"},
+				"cardOrder":          []interface{}{"card4", "card2", "card1"},
+				"filter":             filter,
+			},
+		}
+
+		th.Store.EXPECT().GetBlock(view.ID).Return(view, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			Filter:      &model.FilterGroup{Operation: model.FilterOperationAnd, Filters: []model.FilterGroupItem{}},
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+		}).Return(blocks, "", nil)
+
+		result, err := th.App.EvaluateView(view.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, view.ID, result.ViewID)
+		assert.Equal(t, "status", result.GroupByID)
+		assert.Equal(t, []string{"card4", "card2", "card1", "card3", "card5"}, cardIDs(result.Cards))
+
+		require.Len(t, result.Groups, 3)
+		assert.Equal(t, "", result.Groups[0].OptionID)
+		assert.Equal(t, "No Status", result.Groups[0].Value)
+		assert.Equal(t, []string{"card3", "card5"}, cardIDs(result.Groups[0].Cards))
+		assert.Equal(t, "done", result.Groups[1].OptionID)
+		assert.Equal(t, []string{"card2"}, cardIDs(result.Groups[1].Cards))
+		assert.Equal(t, "todo", result.Groups[2].OptionID)
+		assert.True(t, result.Groups[2].Collapsed)
+		assert.Equal(t, []string{"card4", "card1"}, cardIDs(result.Groups[2].Cards))
+
+		require.Len(t, result.HiddenGroups, 1)
+		assert.Equal(t, "doing", result.HiddenGroups[0].OptionID)
+		assert.Empty(t, result.HiddenGroups[0].Cards)
+	})
+
+	t.Run("sort options take precedence from last to first", func(t *testing.T) {
+		view := &model.Block{
+			ID:      utils.NewID(utils.IDTypeView),
+			BoardID: board.ID,
+			Type:    model.TypeView,
+			Fields: map[string]interface{}{
+				"viewType": "table",
+				"sortOptions": []interface{}{
+					map[string]interface{}{"propertyId": model.TitlePropertyID, "reversed": false},
+					map[string]interface{}{"propertyId": "status", "reversed": true},
+				},
+				"cardOrder": []interface{}{"card4"},
+			},
+		}
+
+		th.Store.EXPECT().GetBlock(view.ID).Return(view, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().QueryCards(model.QueryCardsOptions{
+			BoardID: board.ID,
+			SortOptions: []model.CardSortOption{
+				{PropertyID: "status", Reversed: true},
+				{PropertyID: model.TitlePropertyID},
+			},
+		}).Return(blocks, "", nil)
+
+		result, err := th.App.EvaluateView(view.ID)
+		require.NoError(t, err)
+
+		// the store order is kept, as there is no manual order
+		assert.Equal(t, []string{"card1", "card2", "card3", "card4", "card5"}, cardIDs(result.Cards))
+		assert.Empty(t, result.GroupByID)
+		assert.Nil(t, result.Groups)
+	})
+
+	t.Run("not a view", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card1").Return(blocks[0], nil)
+
+		result, err := th.App.EvaluateView("card1")
+		require.Error(t, err)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// CreateBoardWebhook registers a webhook on a board. If no secret is
+// provided one is generated; the returned webhook is the only place where
+// it can be read.
+func (a *App) CreateBoardWebhook(webhook *model.BoardWebhook, userID string) (*model.BoardWebhook, error) {
+	webhook.ID = utils.NewID(utils.IDTypeNone)
+	webhook.CreatedBy = userID
+	webhook.ModifiedBy = userID
+	if webhook.Secret == "" {
+		webhook.Secret = utils.NewID(utils.IDTypeToken)
+	}
+
+	return a.store.InsertBoardWebhook(webhook)
+}
+
+func (a *App) GetBoardWebhook(webhookID string) (*model.BoardWebhook, error) {
+	webhook, err := a.store.GetBoardWebhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Sanitize()
+	return webhook, nil
+}
+
+func (a *App) GetBoardWebhooks(boardID string) ([]*model.BoardWebhook, error) {
+	webhooks, err := a.store.GetBoardWebhooksForBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Sanitize()
+	}
+	return webhooks, nil
+}
+
+// PatchBoardWebhook updates a webhook. The secret is only returned if the
+// patch changes it.
+func (a *App) PatchBoardWebhook(webhookID string, patch *model.BoardWebhookPatch, userID string) (*model.BoardWebhook, error) {
+	webhook, err := a.store.PatchBoardWebhook(webhookID, patch, userID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Secret == nil {
+		webhook.Sanitize()
+	}
+	return webhook, nil
+}
+
+func (a *App) DeleteBoardWebhook(webhookID string) error {
+	return a.store.DeleteBoardWebhook(webhookID)
+}
+
+func (a *App) GetWebhookDeliveries(webhookID string, page, perPage int) ([]*model.WebhookDelivery, error) {
+	return a.store.GetWebhookDeliveries(webhookID, page, perPage)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBoardWebhook(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("generates id and secret", func(t *testing.T) {
+		webhook := &model.BoardWebhook{
+			BoardID: "board-id",
+			URL:     "https://example.com/hook",
+			Events:  []model.WebhookEvent{model.WebhookEventCardCreated},
+			Enabled: true,
+		}
+
+		th.Store.EXPECT().InsertBoardWebhook(gomock.Any()).DoAndReturn(func(wh *model.BoardWebhook) (*model.BoardWebhook, error) {
+			return wh, nil
+		})
+
+		created, err := th.App.CreateBoardWebhook(webhook, "user-id")
+		require.NoError(t, err)
+		require.NotEmpty(t, created.ID)
+		require.NotEmpty(t, created.Secret)
+		require.Equal(t, "user-id", created.CreatedBy)
+		require.Equal(t, "user-id", created.ModifiedBy)
+	})
+
+	t.Run("keeps the provided secret", func(t *testing.T) {
+		webhook := &model.BoardWebhook{
+			BoardID: "board-id",
+			URL:     "https://example.com/hook",
+			Secret:  "my-secret",
+			Events:  []model.WebhookEvent{model.WebhookEventCardCreated},
+		}
+
+		th.Store.EXPECT().InsertBoardWebhook(gomock.Any()).DoAndReturn(func(wh *model.BoardWebhook) (*model.BoardWebhook, error) {
+			return wh, nil
+		})
+
+		created, err := th.App.CreateBoardWebhook(webhook, "user-id")
+		require.NoError(t, err)
+		require.Equal(t, "my-secret", created.Secret)
+	})
+}
+
+func TestGetBoardWebhooks(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.Store.EXPECT().GetBoardWebhooksForBoard("board-id").Return([]*model.BoardWebhook{
+		{ID: "webhook-1", BoardID: "board-id", Secret: "secret-1"},
+		{ID: "webhook-2", BoardID: "board-id", Secret: "secret-2"},
+	}, nil)
+
+	webhooks, err := th.App.GetBoardWebhooks("board-id")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	for _, webhook := range webhooks {
+		require.Empty(t, webhook.Secret)
+	}
+}
+
+func TestPatchBoardWebhook(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("secret is hidden if not changed", func(t *testing.T) {
+		enabled := false
+		patch := &model.BoardWebhookPatch{Enabled: &enabled}
+		th.Store.EXPECT().PatchBoardWebhook("webhook-id", patch, "user-id").
+			Return(&model.BoardWebhook{ID: "webhook-id", Secret: "secret"}, nil)
+
+		webhook, err := th.App.PatchBoardWebhook("webhook-id", patch, "user-id")
+		require.NoError(t, err)
+		require.Empty(t, webhook.Secret)
+	})
+
+	t.Run("new secret is returned", func(t *testing.T) {
+		secret := "new-secret"
+		patch := &model.BoardWebhookPatch{Secret: &secret}
+		th.Store.EXPECT().PatchBoardWebhook("webhook-id", patch, "user-id").
+			Return(&model.BoardWebhook{ID: "webhook-id", Secret: secret}, nil)
+
+		webhook, err := th.App.PatchBoardWebhook("webhook-id", patch, "user-id")
+		require.NoError(t, err)
+		require.Equal(t, secret, webhook.Secret)
+	})
+}
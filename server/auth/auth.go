@@ -15,6 +15,7 @@ import (
 type AuthInterface interface {
 	IsValidReadToken(boardID string, readToken string) (bool, error)
 	DoesUserHaveTeamAccess(userID string, teamID string) bool
+	DoesUserHaveBoardAccess(userID string, boardID string) bool
 }
 
 // Auth authenticates sessions.
@@ -53,3 +54,7 @@ func (a *Auth) IsValidReadToken(boardID string, readToken string) (bool, error)
 func (a *Auth) DoesUserHaveTeamAccess(userID string, teamID string) bool {
 	return a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam)
 }
+
+func (a *Auth) DoesUserHaveBoardAccess(userID string, boardID string) bool {
+	return a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+}
@@ -33,6 +33,20 @@ func (m *MockAuthInterface) EXPECT() *MockAuthInterfaceMockRecorder {
 	return m.recorder
 }
 
+// DoesUserHaveBoardAccess mocks base method.
+func (m *MockAuthInterface) DoesUserHaveBoardAccess(arg0, arg1 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoesUserHaveBoardAccess", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DoesUserHaveBoardAccess indicates an expected call of DoesUserHaveBoardAccess.
+func (mr *MockAuthInterfaceMockRecorder) DoesUserHaveBoardAccess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoesUserHaveBoardAccess", reflect.TypeOf((*MockAuthInterface)(nil).DoesUserHaveBoardAccess), arg0, arg1)
+}
+
 // DoesUserHaveTeamAccess mocks base method.
 func (m *MockAuthInterface) DoesUserHaveTeamAccess(arg0, arg1 string) bool {
 	m.ctrl.T.Helper()
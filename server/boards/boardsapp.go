@@ -73,17 +73,19 @@ func NewBoardsApp(api model.ServicesAPI, manifest *mm_model.Manifest) (*BoardsAp
 		return nil, fmt.Errorf("cannot access database while initializing Boards: %w", err)
 	}
 
+	newMutexFn := func(name string) (*cluster.Mutex, error) {
+		return cluster.NewMutex(&mutexAPIAdapter{api: api}, name)
+	}
+
 	storeParams := sqlstore.Params{
 		DBType:           cfg.DBType,
 		ConnectionString: cfg.DBConfigString,
 		TablePrefix:      cfg.DBTablePrefix,
 		Logger:           logger,
 		DB:               sqlDB,
-		NewMutexFn: func(name string) (*cluster.Mutex, error) {
-			return cluster.NewMutex(&mutexAPIAdapter{api: api}, name)
-		},
-		ServicesAPI: api,
-		ConfigFn:    api.GetConfig,
+		NewMutexFn:       newMutexFn,
+		ServicesAPI:      api,
+		ConfigFn:         api.GetConfig,
 	}
 
 	var db store.Store
@@ -120,6 +122,12 @@ func NewBoardsApp(api model.ServicesAPI, manifest *mm_model.Manifest) (*BoardsAp
 	notifyBackends = append(notifyBackends, subscriptionsBackend)
 	mentionsBackend.AddListener(subscriptionsBackend)
 
+	remindersBackend, err := createRemindersNotifyBackend(backendParams)
+	if err != nil {
+		return nil, fmt.Errorf("error creating reminder notifications backend: %w", err)
+	}
+	notifyBackends = append(notifyBackends, remindersBackend)
+
 	params := server.Params{
 		Cfg:                cfg,
 		SingleUserToken:    "",
@@ -129,6 +137,7 @@ func NewBoardsApp(api model.ServicesAPI, manifest *mm_model.Manifest) (*BoardsAp
 		WSAdapter:          wsPluginAdapter,
 		NotifyBackends:     notifyBackends,
 		PermissionsService: permissionsService,
+		NewMutexFn:         newMutexFn,
 		IsPlugin:           true,
 	}
 
@@ -169,6 +178,7 @@ func (b *BoardsApp) Start() error {
 	}
 
 	b.servicesAPI.RegisterRouter(b.server.GetRootRouter())
+	b.wsPluginAdapter.StartHeartbeat()
 
 	b.logger.Info("Boards product successfully started.")
 
@@ -176,6 +186,7 @@ func (b *BoardsApp) Start() error {
 }
 
 func (b *BoardsApp) Stop() error {
+	b.wsPluginAdapter.StopHeartbeat()
 	return b.server.Shutdown()
 }
 
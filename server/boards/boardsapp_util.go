@@ -75,6 +75,11 @@ func createBoardsConfig(mmconfig mm_model.Config, baseURL string, serverID strin
 		showEmailAddress = *mmconfig.PrivacySettings.ShowEmailAddress
 	}
 
+	allowedInternalHosts := ""
+	if mmconfig.ServiceSettings.AllowedUntrustedInternalConnections != nil {
+		allowedInternalHosts = *mmconfig.ServiceSettings.AllowedUntrustedInternalConnections
+	}
+
 	showFullName := false
 	if mmconfig.PrivacySettings.ShowFullName != nil {
 		showFullName = *mmconfig.PrivacySettings.ShowFullName
@@ -98,6 +103,7 @@ func createBoardsConfig(mmconfig mm_model.Config, baseURL string, serverID strin
 		Telemetry:                enableTelemetry,
 		TelemetryID:              serverID,
 		WebhookUpdate:            []string{},
+		AllowedInternalHosts:     allowedInternalHosts,
 		SessionExpireTime:        2592000,
 		SessionRefreshTime:       18000,
 		LocalOnly:                false,
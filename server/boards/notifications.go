@@ -10,6 +10,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/config"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify/notifymentions"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify/notifyreminders"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify/notifysubscriptions"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify/plugindelivery"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
@@ -65,6 +66,23 @@ func createSubscriptionsNotifyBackend(params notifyBackendParams) (*notifysubscr
 	return backend, nil
 }
 
+func createRemindersNotifyBackend(params notifyBackendParams) (*notifyreminders.Backend, error) {
+	delivery, err := createDelivery(params.servicesAPI, params.serverRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	backendParams := notifyreminders.BackendParams{
+		AppAPI:      params.appAPI,
+		Permissions: params.permissions,
+		Delivery:    delivery,
+		Logger:      params.logger,
+	}
+	backend := notifyreminders.New(backendParams)
+
+	return backend, nil
+}
+
 func createDelivery(servicesAPI model.ServicesAPI, serverRoot string) (*plugindelivery.PluginDelivery, error) {
 	bot := model.FocalboardBot
 
@@ -102,6 +120,10 @@ func (a *appAPI) GetBlockHistoryNewestChildren(parentID string, opts model.Query
 	return a.store.GetBlockHistoryNewestChildren(parentID, opts)
 }
 
+func (a *appAPI) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return a.store.GetBlockHistoryDescendants(boardID, opts)
+}
+
 func (a *appAPI) GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error) {
 	return a.store.GetBoardAndCardByID(blockID)
 }
@@ -110,10 +132,18 @@ func (a *appAPI) GetUserByID(userID string) (*model.User, error) {
 	return a.store.GetUserByID(userID)
 }
 
+func (a *appAPI) GetUserTimezone(userID string) (string, error) {
+	return a.store.GetUserTimezone(userID)
+}
+
 func (a *appAPI) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
 	return a.app.CreateSubscription(sub)
 }
 
+func (a *appAPI) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
+	return a.store.GetSubscriptions(subscriberID)
+}
+
 func (a *appAPI) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
 	return a.store.GetSubscribersForBlock(blockID)
 }
@@ -130,10 +160,46 @@ func (a *appAPI) GetNextNotificationHint(remove bool) (*model.NotificationHint,
 	return a.store.GetNextNotificationHint(remove)
 }
 
+func (a *appAPI) GetDigestSettingsList() ([]*model.DigestSettings, error) {
+	return a.store.GetDigestSettingsList()
+}
+
+func (a *appAPI) MarkDigestSent(userID string, lastSentAt, sentAt int64) (bool, error) {
+	return a.store.MarkDigestSent(userID, lastSentAt, sentAt)
+}
+
 func (a *appAPI) GetMemberForBoard(boardID, userID string) (*model.BoardMember, error) {
 	return a.store.GetMemberForBoard(boardID, userID)
 }
 
+func (a *appAPI) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	return a.store.GetMembersForBoard(boardID)
+}
+
 func (a *appAPI) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, error) {
 	return a.app.AddMemberToBoard(member)
 }
+
+func (a *appAPI) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	return a.store.GetEnabledDueDateReminderSettings()
+}
+
+func (a *appAPI) MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error) {
+	return a.store.MarkDueDateReminderSent(reminder)
+}
+
+func (a *appAPI) DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error) {
+	return a.store.DeleteDueDateRemindersSentBefore(sentAt)
+}
+
+func (a *appAPI) GetBoard(boardID string) (*model.Board, error) {
+	return a.store.GetBoard(boardID)
+}
+
+func (a *appAPI) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
+	return a.store.GetBlocks(opts)
+}
+
+func (a *appAPI) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
+	return a.store.GetBlocksByIDs(ids)
+}
@@ -10,6 +10,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/mattermost-plugin-boards/server/api"
@@ -384,6 +386,38 @@ func (c *Client) GetCards(boardID string, page int, perPage int) ([]*model.Card,
 	return cards, BuildResponse(r)
 }
 
+func (c *Client) QueryCards(boardID string, query *model.CardQuery) (*model.CardQueryResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/cards/query", toJSON(query))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var result *model.CardQueryResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) GetViewCards(boardID, viewID string) (*model.ViewCards, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/views/"+viewID+"/cards", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var viewCards *model.ViewCards
+	if err := json.NewDecoder(r.Body).Decode(&viewCards); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return viewCards, BuildResponse(r)
+}
+
 func (c *Client) PatchCard(cardID string, cardPatch *model.CardPatch, disableNotify bool) (*model.Card, *Response) {
 	var queryParams string
 	if disableNotify {
@@ -495,23 +529,614 @@ func (c *Client) ReorderCategoryBoards(teamID, categoryID string, newOrder []str
 	}
 	defer closeBody(r)
 
-	var updatedBoardsOrder []string
-	_ = json.NewDecoder(r.Body).Decode(&updatedBoardsOrder)
-	return updatedBoardsOrder, BuildResponse(r)
+	var updatedBoardsOrder []string
+	_ = json.NewDecoder(r.Body).Decode(&updatedBoardsOrder)
+	return updatedBoardsOrder, BuildResponse(r)
+}
+
+func (c *Client) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks) (*model.BoardsAndBlocks, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardsAndBlocksRoute(), toJSON(pbab))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsAndBlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardsAndBlocksRoute(), toJSON(dbab))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+// Sharing
+
+func (c *Client) GetSharingRoute(boardID string) string {
+	return fmt.Sprintf("%s/sharing", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetSharing(boardID string) (*model.Sharing, *Response) {
+	r, err := c.DoAPIGet(c.GetSharingRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	sharing := model.SharingFromJSON(r.Body)
+	return &sharing, BuildResponse(r)
+}
+
+func (c *Client) PostSharing(sharing *model.Sharing) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetSharingRoute(sharing.ID), toJSON(sharing))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetCardRecurrenceRoute(cardID string) string {
+	return fmt.Sprintf("%s/recurrence", c.GetCardRoute(cardID))
+}
+
+func (c *Client) GetCardRecurrence(cardID string) (*model.CardRecurrence, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRecurrenceRoute(cardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var recurrence *model.CardRecurrence
+	if err := json.NewDecoder(r.Body).Decode(&recurrence); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return recurrence, BuildResponse(r)
+}
+
+func (c *Client) SetCardRecurrence(cardID string, recurrence *model.CardRecurrence) (*model.CardRecurrence, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRecurrenceRoute(cardID), toJSON(recurrence))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newRecurrence *model.CardRecurrence
+	if err := json.NewDecoder(r.Body).Decode(&newRecurrence); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newRecurrence, BuildResponse(r)
+}
+
+func (c *Client) DeleteCardRecurrence(cardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetCardRecurrenceRoute(cardID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBoardRulesRoute(boardID string) string {
+	return fmt.Sprintf("%s/rules", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetBoardRuleRoute(boardID, ruleID string) string {
+	return fmt.Sprintf("%s/%s", c.GetBoardRulesRoute(boardID), ruleID)
+}
+
+func (c *Client) GetBoardRules(boardID string) ([]*model.BoardRule, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRulesRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var rules []*model.BoardRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rules, BuildResponse(r)
+}
+
+func (c *Client) CreateBoardRule(rule *model.BoardRule) (*model.BoardRule, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRulesRoute(rule.BoardID), toJSON(rule))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newRule *model.BoardRule
+	if err := json.NewDecoder(r.Body).Decode(&newRule); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newRule, BuildResponse(r)
+}
+
+func (c *Client) PatchBoardRule(boardID, ruleID string, patch *model.BoardRulePatch) (*model.BoardRule, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardRuleRoute(boardID, ruleID), toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var rule *model.BoardRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rule, BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardRule(boardID, ruleID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRuleRoute(boardID, ruleID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBoardRuleRuns(boardID, ruleID string, page, perPage int) ([]*model.BoardRuleRun, *Response) {
+	query := fmt.Sprintf("page=%d&per_page=%d", page, perPage)
+	r, err := c.DoAPIGet(c.GetBoardRuleRoute(boardID, ruleID)+"/runs?"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var runs []*model.BoardRuleRun
+	if err := json.NewDecoder(r.Body).Decode(&runs); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return runs, BuildResponse(r)
+}
+
+func (c *Client) GetCardLinksRoute(cardID string) string {
+	return fmt.Sprintf("%s/links", c.GetCardRoute(cardID))
+}
+
+func (c *Client) GetCardLinkRoute(cardID, linkID string) string {
+	return fmt.Sprintf("%s/%s", c.GetCardLinksRoute(cardID), linkID)
+}
+
+func (c *Client) GetCardLinks(cardID string) ([]*model.CardLink, *Response) {
+	r, err := c.DoAPIGet(c.GetCardLinksRoute(cardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var links []*model.CardLink
+	if err := json.NewDecoder(r.Body).Decode(&links); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return links, BuildResponse(r)
+}
+
+func (c *Client) CreateCardLink(link *model.CardLink) (*model.CardLink, *Response) {
+	r, err := c.DoAPIPost(c.GetCardLinksRoute(link.SourceCardID), toJSON(link))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newLink *model.CardLink
+	if err := json.NewDecoder(r.Body).Decode(&newLink); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newLink, BuildResponse(r)
+}
+
+func (c *Client) DeleteCardLink(cardID, linkID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetCardLinkRoute(cardID, linkID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetCardBlockers(cardID string) ([]*model.Card, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(cardID)+"/blockers", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var blockers []*model.Card
+	if err := json.NewDecoder(r.Body).Decode(&blockers); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return blockers, BuildResponse(r)
+}
+
+func (c *Client) GetTimeEntriesRoute(cardID string) string {
+	return fmt.Sprintf("%s/time_entries", c.GetCardRoute(cardID))
+}
+
+func (c *Client) GetTimeEntryRoute(entryID string) string {
+	return fmt.Sprintf("/time_entries/%s", entryID)
+}
+
+func (c *Client) GetTimeEntries(cardID string) ([]*model.TimeEntry, *Response) {
+	r, err := c.DoAPIGet(c.GetTimeEntriesRoute(cardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var entries []*model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return entries, BuildResponse(r)
+}
+
+func (c *Client) CreateTimeEntry(cardID string, entry *model.TimeEntry) (*model.TimeEntry, *Response) {
+	r, err := c.DoAPIPost(c.GetTimeEntriesRoute(cardID), toJSON(entry))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newEntry *model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&newEntry); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newEntry, BuildResponse(r)
+}
+
+func (c *Client) PatchTimeEntry(entryID string, patch *model.TimeEntryPatch) (*model.TimeEntry, *Response) {
+	r, err := c.DoAPIPatch(c.GetTimeEntryRoute(entryID), toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var entry *model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return entry, BuildResponse(r)
+}
+
+func (c *Client) DeleteTimeEntry(entryID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTimeEntryRoute(entryID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBoardPresenceRoute(boardID string) string {
+	return fmt.Sprintf("%s/presence", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetBoardPresence(boardID string) ([]*model.Presence, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardPresenceRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var presences []*model.Presence
+	if err := json.NewDecoder(r.Body).Decode(&presences); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return presences, BuildResponse(r)
+}
+
+func (c *Client) GetChangesRoute(teamID string) string {
+	return fmt.Sprintf("%s/changes", c.GetTeamRoute(teamID))
+}
+
+func (c *Client) GetChanges(teamID, since string, perPage int) (*model.ChangesResponse, *Response) {
+	query := fmt.Sprintf("?since=%s&per_page=%d", url.QueryEscape(since), perPage)
+	r, err := c.DoAPIGet(c.GetChangesRoute(teamID)+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var changes *model.ChangesResponse
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return changes, BuildResponse(r)
+}
+
+func (c *Client) GetCardRevisionsRoute(cardID string) string {
+	return fmt.Sprintf("%s/revisions", c.GetCardRoute(cardID))
+}
+
+func (c *Client) GetCardRevisions(cardID string, before int64, perPage int) ([]*model.CardRevision, *Response) {
+	query := fmt.Sprintf("?before=%d&per_page=%d", before, perPage)
+	r, err := c.DoAPIGet(c.GetCardRevisionsRoute(cardID)+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var revisions []*model.CardRevision
+	if err := json.NewDecoder(r.Body).Decode(&revisions); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return revisions, BuildResponse(r)
+}
+
+func (c *Client) GetCardRevisionDiff(cardID string, from, to int64) (*model.CardRevisionDiff, *Response) {
+	query := fmt.Sprintf("/diff?from=%d&to=%d", from, to)
+	r, err := c.DoAPIGet(c.GetCardRevisionsRoute(cardID)+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var diff *model.CardRevisionDiff
+	if err := json.NewDecoder(r.Body).Decode(&diff); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return diff, BuildResponse(r)
+}
+
+func (c *Client) RestoreCardRevision(cardID string, updateAt int64) (*model.Card, *Response) {
+	r, err := c.DoAPIPost(fmt.Sprintf("%s/%d/restore", c.GetCardRevisionsRoute(cardID), updateAt), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var card *model.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return card, BuildResponse(r)
+}
+
+func (c *Client) StartTimer(cardID, note string) (*model.TimeEntry, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/timer/start", toJSON(&model.TimeEntryPatch{Note: &note}))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var entry *model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return entry, BuildResponse(r)
+}
+
+func (c *Client) StopTimer(cardID string) (*model.TimeEntry, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/timer/stop", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var entry *model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return entry, BuildResponse(r)
+}
+
+func (c *Client) GetRunningTimer() (*model.TimeEntry, *Response) {
+	r, err := c.DoAPIGet("/users/me/timer", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var entry *model.TimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return entry, BuildResponse(r)
+}
+
+func (c *Client) GetBoardTimeSummary(boardID string, from, to int64) (*model.TimeSummary, *Response) {
+	query := fmt.Sprintf("?from=%d&to=%d", from, to)
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/time_summary"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var summary *model.TimeSummary
+	if err := json.NewDecoder(r.Body).Decode(&summary); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return summary, BuildResponse(r)
+}
+
+func (c *Client) GetUserTimeSummary(from, to int64) (*model.TimeSummary, *Response) {
+	query := fmt.Sprintf("?from=%d&to=%d", from, to)
+	r, err := c.DoAPIGet("/users/me/time_summary"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var summary *model.TimeSummary
+	if err := json.NewDecoder(r.Body).Decode(&summary); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return summary, BuildResponse(r)
+}
+
+func (c *Client) GetDueDateReminderSettingsRoute(boardID string) string {
+	return fmt.Sprintf("%s/reminders", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, *Response) {
+	r, err := c.DoAPIGet(c.GetDueDateReminderSettingsRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var settings *model.DueDateReminderSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return settings, BuildResponse(r)
+}
+
+func (c *Client) PostDueDateReminderSettings(settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, *Response) {
+	r, err := c.DoAPIPost(c.GetDueDateReminderSettingsRoute(settings.BoardID), toJSON(settings))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newSettings *model.DueDateReminderSettings
+	if err := json.NewDecoder(r.Body).Decode(&newSettings); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newSettings, BuildResponse(r)
+}
+
+func (c *Client) GetIncomingWebhookRoute(boardID string) string {
+	return fmt.Sprintf("%s/incoming-webhook", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetIncomingWebhook(boardID string) (*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIGet(c.GetIncomingWebhookRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var webhook *model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhook, BuildResponse(r)
+}
+
+func (c *Client) PostIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIPost(c.GetIncomingWebhookRoute(webhook.ID), toJSON(webhook))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newWebhook *model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&newWebhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newWebhook, BuildResponse(r)
+}
+
+// PostIncomingWebhookCard calls the incoming webhook of a board, which
+// doesn't require a session.
+func (c *Client) PostIncomingWebhookCard(boardID, token string, payload *model.IncomingWebhookCard) (*model.Card, *Response) {
+	r, err := c.DoAPIPost(fmt.Sprintf("/hooks/boards/%s/%s", boardID, token), toJSON(payload))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var card *model.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return card, BuildResponse(r)
+}
+
+func (c *Client) GetBoardWebhooksRoute(boardID string) string {
+	return fmt.Sprintf("%s/webhooks", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetBoardWebhookRoute(boardID, webhookID string) string {
+	return fmt.Sprintf("%s/%s", c.GetBoardWebhooksRoute(boardID), webhookID)
+}
+
+func (c *Client) GetBoardWebhooks(boardID string) ([]*model.BoardWebhook, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardWebhooksRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var webhooks []*model.BoardWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhooks); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhooks, BuildResponse(r)
+}
+
+func (c *Client) CreateBoardWebhook(webhook *model.BoardWebhook) (*model.BoardWebhook, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardWebhooksRoute(webhook.BoardID), toJSON(webhook))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newWebhook *model.BoardWebhook
+	if err := json.NewDecoder(r.Body).Decode(&newWebhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newWebhook, BuildResponse(r)
 }
 
-func (c *Client) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks) (*model.BoardsAndBlocks, *Response) {
-	r, err := c.DoAPIPatch(c.GetBoardsAndBlocksRoute(), toJSON(pbab))
+func (c *Client) PatchBoardWebhook(boardID, webhookID string, patch *model.BoardWebhookPatch) (*model.BoardWebhook, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardWebhookRoute(boardID, webhookID), toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsAndBlocksFromJSON(r.Body), BuildResponse(r)
+	var webhook *model.BoardWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhook, BuildResponse(r)
 }
 
-func (c *Client) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks) (bool, *Response) {
-	r, err := c.DoAPIDelete(c.GetBoardsAndBlocksRoute(), toJSON(dbab))
+func (c *Client) DeleteBoardWebhook(boardID, webhookID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardWebhookRoute(boardID, webhookID), "")
 	if err != nil {
 		return false, BuildErrorResponse(r, err)
 	}
@@ -520,31 +1145,20 @@ func (c *Client) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks) (bool,
 	return true, BuildResponse(r)
 }
 
-// Sharing
-
-func (c *Client) GetSharingRoute(boardID string) string {
-	return fmt.Sprintf("%s/sharing", c.GetBoardRoute(boardID))
-}
-
-func (c *Client) GetSharing(boardID string) (*model.Sharing, *Response) {
-	r, err := c.DoAPIGet(c.GetSharingRoute(boardID), "")
+func (c *Client) GetWebhookDeliveries(boardID, webhookID string, page, perPage int) ([]*model.WebhookDelivery, *Response) {
+	query := fmt.Sprintf("page=%d&per_page=%d", page, perPage)
+	r, err := c.DoAPIGet(c.GetBoardWebhookRoute(boardID, webhookID)+"/deliveries?"+query, "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	sharing := model.SharingFromJSON(r.Body)
-	return &sharing, BuildResponse(r)
-}
-
-func (c *Client) PostSharing(sharing *model.Sharing) (bool, *Response) {
-	r, err := c.DoAPIPost(c.GetSharingRoute(sharing.ID), toJSON(sharing))
-	if err != nil {
-		return false, BuildErrorResponse(r, err)
+	var deliveries []*model.WebhookDelivery
+	if err := json.NewDecoder(r.Body).Decode(&deliveries); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	}
-	defer closeBody(r)
 
-	return true, BuildResponse(r)
+	return deliveries, BuildResponse(r)
 }
 
 func (c *Client) GetMeRoute() string {
@@ -712,6 +1326,22 @@ func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Resp
 	return model.BoardsFromJSON(r.Body), BuildResponse(r)
 }
 
+func (c *Client) SearchCards(teamID, term string, page, perPage int) ([]*model.CardSearchResult, *Response) {
+	query := fmt.Sprintf("q=%s&page=%d&per_page=%d", url.QueryEscape(term), page, perPage)
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/cards/search?"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var results []*model.CardSearchResult
+	if err := json.NewDecoder(r.Body).Decode(&results); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return results, BuildResponse(r)
+}
+
 func (c *Client) GetMembersForBoard(boardID string) ([]*model.BoardMember, *Response) {
 	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/members", "")
 	if err != nil {
@@ -867,6 +1497,56 @@ func (c *Client) GetSubscriptions(subscriberID string) ([]*model.Subscription, *
 	return subs, BuildResponse(r)
 }
 
+func (c *Client) GetCardWatchers(cardID string) ([]*model.Subscriber, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(cardID)+"/watchers", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var watchers []*model.Subscriber
+	err = json.NewDecoder(r.Body).Decode(&watchers)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return watchers, BuildResponse(r)
+}
+
+func (c *Client) GetDigestSettingsRoute() string {
+	return fmt.Sprintf("%s/digest", c.GetMeRoute())
+}
+
+func (c *Client) GetDigestSettings() (*model.DigestSettings, *Response) {
+	r, err := c.DoAPIGet(c.GetDigestSettingsRoute(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var settings *model.DigestSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return settings, BuildResponse(r)
+}
+
+func (c *Client) PostDigestSettings(settings *model.DigestSettings) (*model.DigestSettings, *Response) {
+	r, err := c.DoAPIPost(c.GetDigestSettingsRoute(), toJSON(settings))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var newSettings *model.DigestSettings
+	if err := json.NewDecoder(r.Body).Decode(&newSettings); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return newSettings, BuildResponse(r)
+}
+
 func (c *Client) GetTemplatesForTeam(teamID string) ([]*model.Board, *Response) {
 	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/templates", "")
 	if err != nil {
@@ -891,6 +1571,84 @@ func (c *Client) ExportBoardArchive(boardID string) ([]byte, *Response) {
 	return buf, BuildResponse(r)
 }
 
+func (c *Client) ExportBoardTable(boardID, viewID string, format model.TableExportFormat) ([]byte, *Response) {
+	query := fmt.Sprintf("?format=%s&viewId=%s", format, viewID)
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/export"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+func (c *Client) GetExportJobsRoute(teamID string) string {
+	return c.GetTeamRoute(teamID) + "/archive/export/jobs"
+}
+
+func (c *Client) CreateExportJob(teamID string, create *model.ExportJobCreate) (*model.ExportJob, *Response) {
+	r, err := c.DoAPIPost(c.GetExportJobsRoute(teamID), toJSON(create))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var job *model.ExportJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return job, BuildResponse(r)
+}
+
+func (c *Client) GetExportJobs(teamID string) ([]*model.ExportJob, *Response) {
+	r, err := c.DoAPIGet(c.GetExportJobsRoute(teamID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var jobs []*model.ExportJob
+	if err := json.NewDecoder(r.Body).Decode(&jobs); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return jobs, BuildResponse(r)
+}
+
+func (c *Client) GetExportJob(teamID, jobID string) (*model.ExportJob, *Response) {
+	r, err := c.DoAPIGet(c.GetExportJobsRoute(teamID)+"/"+jobID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var job *model.ExportJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return job, BuildResponse(r)
+}
+
+func (c *Client) DownloadExportJob(teamID, jobID string) ([]byte, *Response) {
+	r, err := c.DoAPIGet(c.GetExportJobsRoute(teamID)+"/"+jobID+"/download", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
 func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -916,6 +1674,154 @@ func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 	return BuildResponse(r)
 }
 
+func (c *Client) ImportExternalBoard(teamID string, format model.ImportFormat, data io.Reader) (*model.Board, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/archive/import/"+string(format), body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetImportSessionsRoute(teamID string) string {
+	return c.GetTeamRoute(teamID) + "/archive/import/sessions"
+}
+
+func (c *Client) CreateImportSession(teamID string, data io.Reader) (*model.ImportSession, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetImportSessionsRoute(teamID), body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var session *model.ImportSession
+	if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return session, BuildResponse(r)
+}
+
+func (c *Client) GetImportSession(teamID, sessionID string) (*model.ImportSession, *Response) {
+	r, err := c.DoAPIGet(c.GetImportSessionsRoute(teamID)+"/"+sessionID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var session *model.ImportSession
+	if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return session, BuildResponse(r)
+}
+
+func (c *Client) ConfirmImportSession(teamID, sessionID string, confirm *model.ImportSessionConfirm) (*model.ImportSession, *Response) {
+	r, err := c.DoAPIPost(c.GetImportSessionsRoute(teamID)+"/"+sessionID+"/confirm", toJSON(confirm))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var session *model.ImportSession
+	if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return session, BuildResponse(r)
+}
+
+func (c *Client) DeleteImportSession(teamID, sessionID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetImportSessionsRoute(teamID)+"/"+sessionID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) ImportCSV(teamID string, data io.Reader, opt model.ImportCSVOptions) (*model.CSVImportResult, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file.csv")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+
+	fields := map[string]string{
+		"boardId":     opt.BoardID,
+		"title":       opt.Title,
+		"titleColumn": opt.TitleColumn,
+		"keyColumn":   opt.KeyColumn,
+		"dryRun":      strconv.FormatBool(opt.DryRun),
+	}
+	if len(opt.Mapping) > 0 {
+		mapping, err := json.Marshal(opt.Mapping)
+		if err != nil {
+			return nil, &Response{Error: err}
+		}
+		fields["mapping"] = string(mapping)
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, &Response{Error: err}
+		}
+	}
+	writer.Close()
+
+	reqOpt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/import/csv", body, "", reqOpt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.CSVImportResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return result, BuildResponse(r)
+}
+
 func (c *Client) MoveContentBlock(srcBlockID string, dstBlockID string, where string, userID string) (bool, *Response) {
 	r, err := c.DoAPIPost("/content-blocks/"+srcBlockID+"/moveto/"+where+"/"+dstBlockID, "")
 	if err != nil {
@@ -983,6 +1889,26 @@ func (c *Client) GetBlocksComplianceHistory(
 	return res, BuildResponse(r)
 }
 
+func (c *Client) GetTimeEntriesForCompliance(
+	modifiedSince int64, includeDeleted bool, teamID, boardID string, page, perPage int,
+) (*model.TimeEntriesComplianceResponse, *Response) {
+	query := fmt.Sprintf("?modified_since=%d&include_deleted=%t&team_id=%s&board_id=%s&page=%d&per_page=%d",
+		modifiedSince, includeDeleted, teamID, boardID, page, perPage)
+	r, err := c.DoAPIGet("/admin/time_entries"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var res *model.TimeEntriesComplianceResponse
+	err = json.NewDecoder(r.Body).Decode(&res)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return res, BuildResponse(r)
+}
+
 func (c *Client) HideBoard(teamID, categoryID, boardID string) *Response {
 	r, err := c.DoAPIPut(c.GetTeamRoute(teamID)+"/categories/"+categoryID+"/boards/"+boardID+"/hide", "")
 	if err != nil {
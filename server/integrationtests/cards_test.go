@@ -310,6 +310,96 @@ func TestPatchCard(t *testing.T) {
 		th.CheckOK(resp)
 		require.Equal(t, card.Properties[propertyID], fetchedCard.Properties[propertyID])
 	})
+
+	t.Run("a stale patch should conflict", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+
+		clients := setupClients(th)
+		th.Client = clients.TeamMember
+		teamID := mmModel.NewId()
+		_, cards := th.CreateBoardAndCards(teamID, model.BoardTypeOpen, 1)
+		card := cards[0]
+
+		firstTitle := "first title"
+		patchedCard, resp := th.Client.PatchCard(card.ID, &model.CardPatch{Title: &firstTitle, ExpectedUpdateAt: &card.UpdateAt}, false)
+		th.CheckOK(resp)
+		require.Equal(t, firstTitle, patchedCard.Title)
+
+		// the second patch was made from the card before the first one
+		secondTitle := "second title"
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{Title: &secondTitle, ExpectedUpdateAt: &card.UpdateAt}, false)
+		th.CheckConflict(resp)
+
+		fetchedCard, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, firstTitle, fetchedCard.Title)
+	})
+
+	t.Run("patches of different properties should not overwrite each other", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+
+		clients := setupClients(th)
+		th.Client = clients.TeamMember
+		teamID := mmModel.NewId()
+		_, cards := th.CreateBoardAndCards(teamID, model.BoardTypeOpen, 1)
+		card := cards[0]
+
+		propertyIDs := make([]string, 0, len(card.Properties))
+		for id := range card.Properties {
+			propertyIDs = append(propertyIDs, id)
+		}
+		require.GreaterOrEqual(t, len(propertyIDs), 2)
+
+		_, resp := th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{propertyIDs[0]: "first"}}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{propertyIDs[1]: "second"}}, false)
+		th.CheckOK(resp)
+
+		fetchedCard, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "first", fetchedCard.Properties[propertyIDs[0]])
+		require.Equal(t, "second", fetchedCard.Properties[propertyIDs[1]])
+	})
+
+	t.Run("marking a blocked card as done should return its open blockers", func(t *testing.T) {
+		th := SetupTestHelperPluginMode(t)
+		defer th.TearDown()
+
+		clients := setupClients(th)
+		th.Client = clients.TeamMember
+		board, resp := th.Client.CreateBoard(&model.Board{
+			TeamID: mmModel.NewId(),
+			Type:   model.BoardTypeOpen,
+			CardProperties: []map[string]any{{
+				"id":   "status-id",
+				"name": "Status",
+				"type": "select",
+				"options": []any{
+					map[string]any{"id": "todo-id", "value": "To do"},
+					map[string]any{"id": "done-id", "value": "Done"},
+				},
+			}},
+		})
+		th.CheckOK(resp)
+
+		blocker, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "blocker"}, true)
+		th.CheckOK(resp)
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "blocked"}, true)
+		th.CheckOK(resp)
+		_, resp = th.Client.CreateCardLink(&model.CardLink{Type: model.CardLinkBlocks, SourceCardID: blocker.ID, TargetCardID: card.ID})
+		th.CheckOK(resp)
+
+		patchedCard, resp := th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{"status-id": "todo-id"}}, false)
+		th.CheckOK(resp)
+		require.Empty(t, patchedCard.OpenBlockers)
+
+		patchedCard, resp = th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{"status-id": "done-id"}}, false)
+		th.CheckOK(resp)
+		require.Len(t, patchedCard.OpenBlockers, 1)
+		require.Equal(t, blocker.ID, patchedCard.OpenBlockers[0].ID)
+	})
 }
 
 func TestGetCard(t *testing.T) {
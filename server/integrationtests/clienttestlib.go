@@ -805,6 +805,11 @@ func (th *TestHelper) CheckNotImplemented(r *client.Response) {
 	require.Error(th.T, r.Error)
 }
 
+func (th *TestHelper) CheckConflict(r *client.Response) {
+	require.Equal(th.T, http.StatusConflict, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
+
 // AddUserToTeamMembers inserts a user into the TeamMembers table for the given team.
 // This is useful for tests that need to ensure a user is a team member so that
 // SearchBoardsForTeam can find public boards associated with that team.
@@ -2686,6 +2686,38 @@ func TestPermissionsCreateSubscription(t *testing.T) {
 	runTestCases(t, ttCases(), testData, clients, testTeamID, otherTeamID, emptyTeamID)
 }
 
+func TestPermissionsCreateChannelSubscription(t *testing.T) {
+	subscription := func(channelID string) string {
+		return toJSON(t, model.Subscription{
+			BlockType:      "card",
+			BlockID:        "block-3",
+			SubscriberType: "channel",
+			SubscriberID:   channelID,
+			CreateAt:       model.GetMillis(),
+		})
+	}
+	ttCases := []TestCase{
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userAnon, http.StatusUnauthorized, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userNoTeamMember, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userTeamMember, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userViewer, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userCommenter, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userEditor, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userAdmin, http.StatusOK, 1},
+		{"/subscriptions", methodPost, subscription("valid-channel-id"), userGuest, http.StatusForbidden, 0},
+
+		{"/subscriptions", methodPost, subscription("no-post-channel"), userViewer, http.StatusForbidden, 0},
+		{"/subscriptions", methodPost, subscription("no-post-channel"), userAdmin, http.StatusForbidden, 0},
+	}
+
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	testData := setupData(t, th)
+	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
+	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
+}
+
 func TestPermissionsGetSubscriptions(t *testing.T) {
 	ttCases := []TestCase{
 		{"/subscriptions/{USER_ANON_ID}", methodGet, "", userAnon, http.StatusUnauthorized, 0},
@@ -2764,6 +2796,26 @@ func TestPermissionsDeleteSubscription(t *testing.T) {
 	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
 }
 
+func TestPermissionsGetCardWatchers(t *testing.T) {
+	ttCases := []TestCase{
+		{"/cards/block-3/watchers", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/cards/block-3/watchers", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-3/watchers", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-3/watchers", methodGet, "", userViewer, http.StatusForbidden, 0},
+		{"/cards/block-3/watchers", methodGet, "", userCommenter, http.StatusForbidden, 0},
+		{"/cards/block-3/watchers", methodGet, "", userEditor, http.StatusOK, 0},
+		{"/cards/block-3/watchers", methodGet, "", userAdmin, http.StatusOK, 0},
+		{"/cards/block-3/watchers", methodGet, "", userGuest, http.StatusForbidden, 0},
+	}
+
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	testData := setupData(t, th)
+	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
+	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
+}
+
 func TestPermissionsOnboard(t *testing.T) {
 	ttCases := []TestCase{
 		{"/teams/test-team/onboard", methodPost, "", userAnon, http.StatusUnauthorized, 0},
@@ -3018,3 +3070,84 @@ func TestPermissionsGetStatistics(t *testing.T) {
 	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
 	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
 }
+
+func TestPermissionsGetBoardPresence(t *testing.T) {
+	ttCases := []TestCase{
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userViewer, http.StatusOK, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userCommenter, http.StatusOK, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userEditor, http.StatusOK, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userAdmin, http.StatusOK, 0},
+		{"/boards/{PRIVATE_BOARD_ID}/presence", methodGet, "", userGuest, http.StatusOK, 0},
+
+		{"/boards/{PUBLIC_BOARD_ID}/presence", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/presence", methodGet, "", userViewer, http.StatusOK, 0},
+		{"/boards/{PUBLIC_BOARD_ID}/presence", methodGet, "", userGuest, http.StatusForbidden, 0},
+	}
+
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	testData := setupData(t, th)
+	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
+	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
+}
+
+func TestPermissionsGetChanges(t *testing.T) {
+	ttCases := []TestCase{
+		{"/teams/test-team/changes", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/teams/test-team/changes", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/teams/test-team/changes", methodGet, "", userTeamMember, http.StatusOK, 1},
+		{"/teams/test-team/changes", methodGet, "", userViewer, http.StatusOK, 1},
+		{"/teams/test-team/changes", methodGet, "", userAdmin, http.StatusOK, 1},
+		{"/teams/test-team/changes", methodGet, "", userGuest, http.StatusOK, 1},
+
+		{"/teams/test-team/changes?since=invalid", methodGet, "", userViewer, http.StatusBadRequest, 0},
+		{"/teams/test-team/changes?per_page=0", methodGet, "", userViewer, http.StatusBadRequest, 0},
+	}
+
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	testData := setupData(t, th)
+	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
+	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
+}
+
+func TestPermissionsCardRevisions(t *testing.T) {
+	ttCases := []TestCase{
+		{"/cards/block-4/revisions", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/cards/block-4/revisions", methodGet, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions", methodGet, "", userViewer, http.StatusOK, 1},
+		{"/cards/block-4/revisions", methodGet, "", userCommenter, http.StatusOK, 1},
+		{"/cards/block-4/revisions", methodGet, "", userEditor, http.StatusOK, 1},
+		{"/cards/block-4/revisions", methodGet, "", userAdmin, http.StatusOK, 1},
+		{"/cards/block-4/revisions", methodGet, "", userGuest, http.StatusOK, 1},
+		{"/cards/block-4/revisions?per_page=0", methodGet, "", userViewer, http.StatusBadRequest, 0},
+
+		{"/cards/block-4/revisions/diff?from=1&to=2", methodGet, "", userAnon, http.StatusUnauthorized, 0},
+		{"/cards/block-4/revisions/diff?from=1&to=2", methodGet, "", userTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions/diff?from=1&to=2", methodGet, "", userViewer, http.StatusNotFound, 0},
+		{"/cards/block-4/revisions/diff?from=1", methodGet, "", userViewer, http.StatusBadRequest, 0},
+
+		// the revision doesn't exist, so allowed requests fail after the permission check
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userAnon, http.StatusUnauthorized, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userNoTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userTeamMember, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userViewer, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userCommenter, http.StatusForbidden, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userEditor, http.StatusNotFound, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userAdmin, http.StatusNotFound, 0},
+		{"/cards/block-4/revisions/1/restore", methodPost, "", userGuest, http.StatusForbidden, 0},
+	}
+
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	testData := setupData(t, th)
+	testTeamID, otherTeamID, emptyTeamID := th.GetTestTeamIDs()
+	runTestCases(t, ttCases, testData, clients, testTeamID, otherTeamID, emptyTeamID)
+}
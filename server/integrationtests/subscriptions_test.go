@@ -166,3 +166,165 @@ func TestDeleteSubscription(t *testing.T) {
 		require.Error(t, resp.Error)
 	})
 }
+
+func TestCardWatchers(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+
+	t.Run("Subscription filter is updated and listed", func(t *testing.T) {
+		subs, userID, err := createTestSubscriptions(th.Client, 1)
+		require.NoError(t, err)
+		require.Len(t, subs, 1)
+		require.Nil(t, subs[0].Filter)
+
+		watchers, resp := th.Client.GetCardWatchers(subs[0].BlockID)
+		require.NoError(t, resp.Error)
+		require.Len(t, watchers, 1)
+		require.Equal(t, userID, watchers[0].SubscriberID)
+		require.Nil(t, watchers[0].Filter)
+
+		filter := &model.SubscriptionFilter{Comments: true, Properties: []string{"status"}}
+		sub := *subs[0]
+		sub.Filter = filter
+		subNew, resp := th.Client.CreateSubscription(&sub)
+		require.NoError(t, resp.Error)
+		require.Equal(t, filter, subNew.Filter)
+
+		watchers, resp = th.Client.GetCardWatchers(subs[0].BlockID)
+		require.NoError(t, resp.Error)
+		require.Len(t, watchers, 1)
+		require.Equal(t, filter, watchers[0].Filter)
+
+		subsFound, resp := th.Client.GetSubscriptions(userID)
+		require.NoError(t, resp.Error)
+		require.Len(t, subsFound, 1)
+		require.Equal(t, filter, subsFound[0].Filter)
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		subs, _, err := createTestSubscriptions(th.Client, 1)
+		require.NoError(t, err)
+
+		sub := *subs[0]
+		sub.Filter = &model.SubscriptionFilter{Properties: []string{""}}
+		_, resp := th.Client.CreateSubscription(&sub)
+		require.Error(t, resp.Error)
+	})
+}
+
+func TestDigestSubscriptions(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+
+	t.Run("Digest subscription schedules a daily digest", func(t *testing.T) {
+		subs, userID, err := createTestSubscriptions(th.Client, 1)
+		require.NoError(t, err)
+		require.False(t, subs[0].Digest)
+
+		sub := *subs[0]
+		sub.Digest = true
+		subNew, resp := th.Client.CreateSubscription(&sub)
+		require.NoError(t, resp.Error)
+		require.True(t, subNew.Digest)
+
+		watchers, resp := th.Client.GetCardWatchers(subs[0].BlockID)
+		require.NoError(t, resp.Error)
+		require.Len(t, watchers, 1)
+		require.True(t, watchers[0].Digest)
+
+		settings, resp := th.Client.GetDigestSettings()
+		require.NoError(t, resp.Error)
+		require.Equal(t, userID, settings.UserID)
+		require.Equal(t, model.DigestFrequencyDaily, settings.Frequency)
+		require.NotZero(t, settings.LastSentAt)
+	})
+
+	t.Run("Update digest settings", func(t *testing.T) {
+		settings := &model.DigestSettings{
+			Frequency: model.DigestFrequencyWeekly,
+			Hour:      17,
+			Weekday:   5,
+		}
+		newSettings, resp := th.Client.PostDigestSettings(settings)
+		require.NoError(t, resp.Error)
+		require.Equal(t, model.DigestFrequencyWeekly, newSettings.Frequency)
+		require.Equal(t, 17, newSettings.Hour)
+		require.Equal(t, 5, newSettings.Weekday)
+
+		settings.Hour = 24
+		_, resp = th.Client.PostDigestSettings(settings)
+		require.Error(t, resp.Error)
+	})
+}
+
+func TestChannelSubscriptions(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+
+	subs, _, err := createTestSubscriptions(th.Client, 1)
+	require.NoError(t, err)
+	cardID := subs[0].BlockID
+
+	t.Run("Subscribe, list and unsubscribe a channel", func(t *testing.T) {
+		filter := &model.SubscriptionFilter{DueDates: true}
+		sub := &model.Subscription{
+			BlockType:      model.TypeCard,
+			BlockID:        cardID,
+			SubscriberType: model.SubTypeChannel,
+			SubscriberID:   "valid-channel-id",
+			Filter:         filter,
+		}
+		subNew, resp := th.Client.CreateSubscription(sub)
+		require.NoError(t, resp.Error)
+		require.Equal(t, model.SubscriberType(model.SubTypeChannel), subNew.SubscriberType)
+		require.Equal(t, filter, subNew.Filter)
+
+		watchers, resp := th.Client.GetCardWatchers(cardID)
+		require.NoError(t, resp.Error)
+		require.Len(t, watchers, 2)
+
+		channelSubs, resp := th.Client.GetSubscriptions("valid-channel-id")
+		require.NoError(t, resp.Error)
+		require.Len(t, channelSubs, 1)
+		require.Equal(t, filter, channelSubs[0].Filter)
+
+		resp = th.Client.DeleteSubscription(cardID, "valid-channel-id")
+		require.NoError(t, resp.Error)
+
+		channelSubs, resp = th.Client.GetSubscriptions("valid-channel-id")
+		require.NoError(t, resp.Error)
+		require.Empty(t, channelSubs)
+	})
+
+	t.Run("Cannot subscribe a channel without permission to post", func(t *testing.T) {
+		sub := &model.Subscription{
+			BlockType:      model.TypeCard,
+			BlockID:        cardID,
+			SubscriberType: model.SubTypeChannel,
+			SubscriberID:   "invalid-channel-id",
+		}
+		_, resp := th.Client.CreateSubscription(sub)
+		require.Error(t, resp.Error)
+	})
+
+	t.Run("Channels cannot receive digests", func(t *testing.T) {
+		sub := &model.Subscription{
+			BlockType:      model.TypeCard,
+			BlockID:        cardID,
+			SubscriberType: model.SubTypeChannel,
+			SubscriberID:   "valid-channel-id",
+			Digest:         true,
+		}
+		_, resp := th.Client.CreateSubscription(sub)
+		require.Error(t, resp.Error)
+	})
+}
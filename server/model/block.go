@@ -119,6 +119,12 @@ type BlockPatch struct {
 	// The block removed fields
 	// required: false
 	DeletedFields []string `json:"deletedFields"`
+
+	// The update time of the block the patch was made from, in
+	// miliseconds since the current epoch. If set, the patch is only
+	// applied if the block has not been modified since
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt,omitempty"`
 }
 
 // BlockPatchBatch is a batch of IDs and patches for modify blocks
@@ -333,6 +339,12 @@ func (p *BlockPatch) Patch(block *Block) *Block {
 	}
 
 	for key, field := range p.UpdatedFields {
+		if key == BlockFieldProperties {
+			if properties, ok := field.(map[string]interface{}); ok {
+				block.Fields[key] = mergeProperties(block.Fields[key], properties)
+				continue
+			}
+		}
 		block.Fields[key] = field
 	}
 
@@ -343,6 +355,33 @@ func (p *BlockPatch) Patch(block *Block) *Block {
 	return block
 }
 
+// IsStale returns true if the patch expects a version of the block that
+// is not the current one.
+func (p *BlockPatch) IsStale(block *Block) bool {
+	return p.ExpectedUpdateAt != nil && *p.ExpectedUpdateAt != block.UpdateAt
+}
+
+// mergeProperties applies the updated card properties to the existing
+// ones per property key, so that patches changing different properties
+// do not overwrite each other. A nil value removes the property.
+func mergeProperties(existing interface{}, updated map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	if existingProperties, ok := existing.(map[string]interface{}); ok {
+		for key, value := range existingProperties {
+			merged[key] = value
+		}
+	}
+
+	for key, value := range updated {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
 type QueryBlocksOptions struct {
 	BoardID   string    // if not empty then filter for blocks belonging to specified board
 	ParentID  string    // if not empty then filter for blocks belonging to specified parent
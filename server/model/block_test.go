@@ -312,6 +312,48 @@ func TestStampModificationMetadata(t *testing.T) {
 		assert.NotEmpty(t, blocks[0].UpdateAt)
 	})
 }
+func TestBlockPatchPatch(t *testing.T) {
+	t.Run("card properties are merged per key", func(t *testing.T) {
+		block := &Block{Fields: map[string]interface{}{
+			"icon":       "😀",
+			"properties": map[string]interface{}{"kept": "a", "changed": "b", "removed": "c"},
+		}}
+		patch := &BlockPatch{UpdatedFields: map[string]interface{}{
+			"properties": map[string]interface{}{"changed": "new", "added": "d", "removed": nil},
+		}}
+
+		patched := patch.Patch(block)
+		require.Equal(t, "😀", patched.Fields["icon"])
+		require.Equal(t, map[string]interface{}{"kept": "a", "changed": "new", "added": "d"}, patched.Fields["properties"])
+	})
+
+	t.Run("properties are added to blocks without them", func(t *testing.T) {
+		block := &Block{Fields: map[string]interface{}{}}
+		patch := &BlockPatch{UpdatedFields: map[string]interface{}{
+			"properties": map[string]interface{}{"added": "d"},
+		}}
+
+		require.Equal(t, map[string]interface{}{"added": "d"}, patch.Patch(block).Fields["properties"])
+	})
+
+	t.Run("other fields are replaced", func(t *testing.T) {
+		block := &Block{Fields: map[string]interface{}{"filter": map[string]interface{}{"a": "b"}}}
+		patch := &BlockPatch{UpdatedFields: map[string]interface{}{
+			"filter": map[string]interface{}{"c": "d"},
+		}}
+
+		require.Equal(t, map[string]interface{}{"c": "d"}, patch.Patch(block).Fields["filter"])
+	})
+}
+
+func TestBlockPatchIsStale(t *testing.T) {
+	block := &Block{UpdateAt: 1000}
+
+	require.False(t, (&BlockPatch{}).IsStale(block))
+	require.False(t, (&BlockPatch{ExpectedUpdateAt: mmModel.NewPointer(int64(1000))}).IsStale(block))
+	require.True(t, (&BlockPatch{ExpectedUpdateAt: mmModel.NewPointer(int64(999))}).IsStale(block))
+}
+
 func TestValidateBlockPatch(t *testing.T) {
 	t.Run("Should return nil for block patch with valid updated fields", func(t *testing.T) {
 		patch := &BlockPatch{
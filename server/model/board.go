@@ -171,6 +171,12 @@ type BoardPatch struct {
 	// The board removed card properties
 	// required: false
 	DeletedCardProperties []string `json:"deletedCardProperties"`
+
+	// The update time of the board the patch was made from, in
+	// miliseconds since the current epoch. If set, the patch is only
+	// applied if the board has not been modified since
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt,omitempty"`
 }
 
 // BoardMember stores the information of the membership of a user on a board
@@ -267,6 +273,12 @@ func BoardMetadataFromJSON(data io.Reader) *BoardMetadata {
 	return boardMetadata
 }
 
+// IsStale returns true if the patch expects a version of the board that
+// is not the current one.
+func (p *BoardPatch) IsStale(board *Board) bool {
+	return p.ExpectedUpdateAt != nil && *p.ExpectedUpdateAt != board.UpdateAt
+}
+
 // Patch returns an updated version of the board.
 func (p *BoardPatch) Patch(board *Board) *Board {
 	if p.Type != nil {
@@ -0,0 +1,403 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// BoardRuleRunsMaxPerPage is the maximum number of rule runs that can be requested in a single page.
+	BoardRuleRunsMaxPerPage = 100
+
+	// BoardRuleMaxActions is the maximum number of actions of a rule.
+	BoardRuleMaxActions = 20
+)
+
+// RuleTriggerType is the kind of card change that triggers a rule.
+type RuleTriggerType string
+
+const (
+	// RuleTriggerCardCreated triggers when a card is created, optionally
+	// only from a given card template.
+	RuleTriggerCardCreated RuleTriggerType = "card_created"
+
+	// RuleTriggerPropertyChanged triggers when a card property changes,
+	// optionally only to a given value.
+	RuleTriggerPropertyChanged RuleTriggerType = "property_changed"
+
+	// RuleTriggerCheckboxesChecked triggers when the last unchecked
+	// checkbox of a card is checked.
+	RuleTriggerCheckboxesChecked RuleTriggerType = "checkboxes_checked"
+)
+
+// RuleActionType is the kind of change a rule makes to a card.
+type RuleActionType string
+
+const (
+	// RuleActionSetProperty sets a card property to a value. Setting the
+	// property the board is grouped by moves the card.
+	RuleActionSetProperty RuleActionType = "set_property"
+
+	// RuleActionSetDateNow sets a date property to the current time.
+	RuleActionSetDateNow RuleActionType = "set_date_now"
+
+	// RuleActionClearProperty clears a card property, e.g. to unassign it.
+	RuleActionClearProperty RuleActionType = "clear_property"
+
+	// RuleActionAssignActor assigns a person property to the user whose
+	// change triggered the rule, e.g. the creator of a card.
+	RuleActionAssignActor RuleActionType = "assign_actor"
+)
+
+// BoardRuleRunStatus is the outcome of a rule run.
+type BoardRuleRunStatus string
+
+const (
+	BoardRuleRunSuccess BoardRuleRunStatus = "success"
+	BoardRuleRunFailed  BoardRuleRunStatus = "failed"
+	BoardRuleRunSkipped BoardRuleRunStatus = "skipped"
+)
+
+// RuleTrigger is the "when" part of a rule
+// swagger:model
+type RuleTrigger struct {
+	// The kind of change that triggers the rule
+	// required: true
+	Type RuleTriggerType `json:"type"`
+
+	// For card_created, the card template the card must be created from
+	// required: false
+	TemplateID string `json:"templateId,omitempty"`
+
+	// For property_changed, the property that changes
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// For property_changed, the value the property changes to. If empty,
+	// any change triggers the rule
+	// required: false
+	Value any `json:"value,omitempty"`
+}
+
+// RuleAction is one of the "then" parts of a rule
+// swagger:model
+type RuleAction struct {
+	// The kind of change made to the card
+	// required: true
+	Type RuleActionType `json:"type"`
+
+	// The property that is changed
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// For set_property, the value the property is set to
+	// required: false
+	Value any `json:"value,omitempty"`
+}
+
+// BoardRule is an automation rule of a board: when a card change matches
+// the trigger, the actions are applied to the card by the Boards bot
+// swagger:model
+type BoardRule struct {
+	// The id of the rule
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the board the rule belongs to
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The title of the rule
+	// required: false
+	Title string `json:"title"`
+
+	// If false the rule is not triggered
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// When the rule is triggered
+	// required: true
+	Trigger RuleTrigger `json:"trigger"`
+
+	// The changes made to the card
+	// required: true
+	Actions []RuleAction `json:"actions"`
+
+	// The id of the user who created the rule
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The id of the user who last modified the rule
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// BoardRulePatch is a patch for modifying a rule
+// swagger:model
+type BoardRulePatch struct {
+	// The title of the rule
+	// required: false
+	Title *string `json:"title"`
+
+	// If false the rule is not triggered
+	// required: false
+	Enabled *bool `json:"enabled"`
+
+	// When the rule is triggered
+	// required: false
+	Trigger *RuleTrigger `json:"trigger"`
+
+	// The changes made to the card
+	// required: false
+	Actions []RuleAction `json:"actions"`
+}
+
+// BoardRuleRun is an entry of the audit trail of a rule
+// swagger:model
+type BoardRuleRun struct {
+	// The id of the run
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the rule
+	// required: true
+	RuleID string `json:"ruleId"`
+
+	// The id of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the card the rule was applied to
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the user whose change triggered the rule
+	// required: true
+	ActorID string `json:"actorId"`
+
+	// The number of rules run before this one in the same chain. Zero if
+	// the rule was triggered by a user change
+	// required: true
+	Depth int `json:"depth"`
+
+	// The outcome of the run
+	// required: true
+	Status BoardRuleRunStatus `json:"status"`
+
+	// The card properties set by the run, keyed by property id
+	// required: false
+	Changes map[string]any `json:"changes,omitempty"`
+
+	// The reason the run failed or was skipped
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The time of the run in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// RuleChange is a card change that can trigger the rules of its board.
+type RuleChange struct {
+	Type RuleTriggerType
+
+	// The card after the change
+	Card *Block
+
+	// The card before the change, for property changes
+	OldCard *Block
+
+	// The card template the card was created from, if any
+	TemplateID string
+
+	// The user whose change started the chain of rules
+	ActorID string
+}
+
+// IsValid checks the rule fields that can be set by users.
+func (r *BoardRule) IsValid() error {
+	if r.BoardID == "" {
+		return NewErrBadRequest("rule is missing its board id")
+	}
+	if err := r.Trigger.IsValid(); err != nil {
+		return err
+	}
+	return isValidRuleActions(r.Actions)
+}
+
+// IsValidForSchema checks that the properties used by the rule exist on
+// the board and have the right types.
+func (r *BoardRule) IsValidForSchema(schema PropSchema) error {
+	if r.Trigger.Type == RuleTriggerPropertyChanged {
+		if _, ok := schema[r.Trigger.PropertyID]; !ok {
+			return NewErrBadRequest(fmt.Sprintf("trigger property %s does not exist", r.Trigger.PropertyID))
+		}
+	}
+
+	for _, action := range r.Actions {
+		pd, ok := schema[action.PropertyID]
+		if !ok {
+			return NewErrBadRequest(fmt.Sprintf("action property %s does not exist", action.PropertyID))
+		}
+		switch action.Type {
+		case RuleActionSetDateNow:
+			if pd.Type != "date" {
+				return NewErrBadRequest(fmt.Sprintf("property %s is not a date property", pd.Name))
+			}
+		case RuleActionAssignActor:
+			if pd.Type != "person" && pd.Type != "multiPerson" {
+				return NewErrBadRequest(fmt.Sprintf("property %s is not a person property", pd.Name))
+			}
+		}
+	}
+	return nil
+}
+
+func (t *RuleTrigger) IsValid() error {
+	switch t.Type {
+	case RuleTriggerCardCreated, RuleTriggerCheckboxesChecked:
+		return nil
+	case RuleTriggerPropertyChanged:
+		if t.PropertyID == "" {
+			return NewErrBadRequest("property_changed trigger is missing its property id")
+		}
+		return nil
+	}
+	return NewErrBadRequest(fmt.Sprintf("invalid rule trigger %q", t.Type))
+}
+
+func isValidRuleActions(actions []RuleAction) error {
+	if len(actions) == 0 {
+		return NewErrBadRequest("rule must have at least one action")
+	}
+	if len(actions) > BoardRuleMaxActions {
+		return NewErrBadRequest(fmt.Sprintf("rule cannot have more than %d actions", BoardRuleMaxActions))
+	}
+
+	for _, action := range actions {
+		if action.PropertyID == "" {
+			return NewErrBadRequest(fmt.Sprintf("%s action is missing its property id", action.Type))
+		}
+		switch action.Type {
+		case RuleActionSetProperty:
+			if action.Value == nil {
+				return NewErrBadRequest("set_property action is missing its value")
+			}
+		case RuleActionSetDateNow, RuleActionClearProperty, RuleActionAssignActor:
+		default:
+			return NewErrBadRequest(fmt.Sprintf("invalid rule action %q", action.Type))
+		}
+	}
+	return nil
+}
+
+// Patch returns an updated version of the rule.
+func (p *BoardRulePatch) Patch(r *BoardRule) *BoardRule {
+	if p.Title != nil {
+		r.Title = *p.Title
+	}
+
+	if p.Enabled != nil {
+		r.Enabled = *p.Enabled
+	}
+
+	if p.Trigger != nil {
+		r.Trigger = *p.Trigger
+	}
+
+	if p.Actions != nil {
+		r.Actions = p.Actions
+	}
+
+	return r
+}
+
+func (p *BoardRulePatch) IsValid() error {
+	if p.Trigger != nil {
+		if err := p.Trigger.IsValid(); err != nil {
+			return err
+		}
+	}
+	if p.Actions != nil {
+		return isValidRuleActions(p.Actions)
+	}
+	return nil
+}
+
+// Matches returns true if the change triggers the rule. Checkbox changes
+// match if all the checkboxes of the card are checked, which the caller
+// must verify.
+func (t *RuleTrigger) Matches(change *RuleChange) bool {
+	if change.Type != t.Type {
+		return false
+	}
+
+	switch t.Type {
+	case RuleTriggerCardCreated:
+		return t.TemplateID == "" || t.TemplateID == change.TemplateID
+	case RuleTriggerPropertyChanged:
+		if change.OldCard == nil {
+			return false
+		}
+		oldValue := getCardProperty(change.OldCard, t.PropertyID)
+		newValue := getCardProperty(change.Card, t.PropertyID)
+		if reflect.DeepEqual(oldValue, newValue) {
+			return false
+		}
+		return t.Value == nil || reflect.DeepEqual(t.Value, newValue)
+	case RuleTriggerCheckboxesChecked:
+		return true
+	}
+	return false
+}
+
+// Apply returns the card properties with the action applied. `now` is in
+// milliseconds since the current epoch.
+func (a *RuleAction) Apply(properties map[string]any, schema PropSchema, actorID string, now int64) map[string]any {
+	updated := make(map[string]any, len(properties)+1)
+	for propID, value := range properties {
+		updated[propID] = value
+	}
+
+	switch a.Type {
+	case RuleActionSetProperty:
+		updated[a.PropertyID] = a.Value
+	case RuleActionSetDateNow:
+		updated[a.PropertyID] = `{"from":` + strconv.FormatInt(now, 10) + `}`
+	case RuleActionClearProperty:
+		delete(updated, a.PropertyID)
+	case RuleActionAssignActor:
+		if schema[a.PropertyID].Type != "multiPerson" {
+			updated[a.PropertyID] = actorID
+			break
+		}
+		assignees, _ := updated[a.PropertyID].([]any)
+		for _, assignee := range assignees {
+			if assignee == actorID {
+				return updated
+			}
+		}
+		updated[a.PropertyID] = append(append([]any{}, assignees...), actorID)
+	}
+	return updated
+}
+
+func getCardProperty(card *Block, propertyID string) any {
+	if card == nil {
+		return nil
+	}
+	properties, _ := card.Fields["properties"].(map[string]any)
+	return properties[propertyID]
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardRuleIsValid(t *testing.T) {
+	validRule := func() *BoardRule {
+		return &BoardRule{
+			BoardID: "board-id",
+			Trigger: RuleTrigger{Type: RuleTriggerPropertyChanged, PropertyID: "status-id"},
+			Actions: []RuleAction{{Type: RuleActionSetProperty, PropertyID: "priority-id", Value: "high"}},
+		}
+	}
+
+	require.NoError(t, validRule().IsValid())
+
+	rule := validRule()
+	rule.Trigger.PropertyID = ""
+	require.True(t, IsErrBadRequest(rule.IsValid()))
+
+	rule = validRule()
+	rule.Trigger.Type = "card_moved"
+	require.True(t, IsErrBadRequest(rule.IsValid()))
+
+	rule = validRule()
+	rule.Actions = nil
+	require.True(t, IsErrBadRequest(rule.IsValid()))
+
+	rule = validRule()
+	rule.Actions[0].Value = nil
+	require.True(t, IsErrBadRequest(rule.IsValid()))
+
+	rule = validRule()
+	rule.Actions[0].Type = "delete_card"
+	require.True(t, IsErrBadRequest(rule.IsValid()))
+}
+
+func TestRuleTriggerMatches(t *testing.T) {
+	card := func(status any) *Block {
+		properties := map[string]any{}
+		if status != nil {
+			properties["status-id"] = status
+		}
+		return &Block{ID: "card-id", Type: TypeCard, Fields: map[string]any{"properties": properties}}
+	}
+
+	t.Run("property changed", func(t *testing.T) {
+		anyChange := RuleTrigger{Type: RuleTriggerPropertyChanged, PropertyID: "status-id"}
+		toDone := RuleTrigger{Type: RuleTriggerPropertyChanged, PropertyID: "status-id", Value: "done"}
+
+		change := &RuleChange{Type: RuleTriggerPropertyChanged, OldCard: card("todo"), Card: card("done")}
+		require.True(t, anyChange.Matches(change))
+		require.True(t, toDone.Matches(change))
+
+		change = &RuleChange{Type: RuleTriggerPropertyChanged, OldCard: card(nil), Card: card("todo")}
+		require.True(t, anyChange.Matches(change))
+		require.False(t, toDone.Matches(change))
+
+		change = &RuleChange{Type: RuleTriggerPropertyChanged, OldCard: card("done"), Card: card("done")}
+		require.False(t, anyChange.Matches(change))
+		require.False(t, toDone.Matches(change))
+	})
+
+	t.Run("card created", func(t *testing.T) {
+		anyCard := RuleTrigger{Type: RuleTriggerCardCreated}
+		fromTemplate := RuleTrigger{Type: RuleTriggerCardCreated, TemplateID: "template-id"}
+
+		change := &RuleChange{Type: RuleTriggerCardCreated, Card: card(nil)}
+		require.True(t, anyCard.Matches(change))
+		require.False(t, fromTemplate.Matches(change))
+
+		change.TemplateID = "template-id"
+		require.True(t, anyCard.Matches(change))
+		require.True(t, fromTemplate.Matches(change))
+
+		change.Type = RuleTriggerPropertyChanged
+		require.False(t, anyCard.Matches(change))
+	})
+}
+
+func TestRuleActionApply(t *testing.T) {
+	schema := PropSchema{
+		"status-id":    {ID: "status-id", Name: "Status", Type: "select"},
+		"completed-id": {ID: "completed-id", Name: "Completed", Type: "date"},
+		"owner-id":     {ID: "owner-id", Name: "Owner", Type: "person"},
+		"team-id":      {ID: "team-id", Name: "Team", Type: "multiPerson"},
+	}
+	properties := map[string]any{
+		"status-id": "todo",
+		"owner-id":  "user-1",
+		"team-id":   []any{"user-1"},
+	}
+
+	apply := func(action RuleAction) map[string]any {
+		return action.Apply(properties, schema, "user-2", 1000)
+	}
+
+	updated := apply(RuleAction{Type: RuleActionSetProperty, PropertyID: "status-id", Value: "done"})
+	require.Equal(t, "done", updated["status-id"])
+	require.Equal(t, "todo", properties["status-id"])
+
+	updated = apply(RuleAction{Type: RuleActionSetDateNow, PropertyID: "completed-id"})
+	require.Equal(t, `{"from":1000}`, updated["completed-id"])
+
+	updated = apply(RuleAction{Type: RuleActionClearProperty, PropertyID: "owner-id"})
+	require.NotContains(t, updated, "owner-id")
+	require.Contains(t, properties, "owner-id")
+
+	updated = apply(RuleAction{Type: RuleActionAssignActor, PropertyID: "owner-id"})
+	require.Equal(t, "user-2", updated["owner-id"])
+
+	updated = apply(RuleAction{Type: RuleActionAssignActor, PropertyID: "team-id"})
+	require.Equal(t, []any{"user-1", "user-2"}, updated["team-id"])
+	require.Equal(t, []any{"user-1"}, properties["team-id"])
+}
@@ -87,6 +87,15 @@ type Card struct {
 	// The deleted time in milliseconds since the current epoch. Set to indicate this card is deleted
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
+
+	// The time spent on this card in milliseconds, summed from its stopped time entries
+	// required: false
+	TimeSpent int64 `json:"timeSpent"`
+
+	// The cards blocking this card that are not done yet. Only set in the
+	// response to a patch that marks the card as done while it is blocked
+	// required: false
+	OpenBlockers []*Card `json:"openBlockers,omitempty"`
 }
 
 // Populate populates a Card with default values.
@@ -155,9 +164,16 @@ type CardPatch struct {
 	// required: false
 	Icon *string `json:"icon"`
 
-	// A map of property ids to property option ids to be updated
+	// A map of property ids to property option ids to be updated. A null
+	// value removes the property from the card
 	// required: false
 	UpdatedProperties map[string]any `json:"updatedProperties"`
+
+	// The update time of the card the patch was made from, in miliseconds
+	// since the current epoch. If set, the patch is only applied if the
+	// card has not been modified since
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt,omitempty"`
 }
 
 // Patch returns an updated version of the card.
@@ -179,12 +195,16 @@ func (p *CardPatch) Patch(card *Card) *Card {
 	}
 
 	// if there are properties marked for update, we replace the
-	// existing ones or add them
+	// existing ones or add them, and remove the ones set to null
 	for propID, propVal := range p.UpdatedProperties {
 		if !IsValidCardPropertyValue(propVal) {
 			continue
 		}
 
+		if propVal == nil {
+			delete(card.Properties, propID)
+			continue
+		}
 		card.Properties[propID] = propVal
 	}
 
@@ -303,7 +323,8 @@ func CardPatch2BlockPatch(cardPatch *CardPatch) (*BlockPatch, error) {
 	}
 
 	blockPatch := &BlockPatch{
-		Title: cardPatch.Title,
+		Title:            cardPatch.Title,
+		ExpectedUpdateAt: cardPatch.ExpectedUpdateAt,
 	}
 
 	updatedFields := make(map[string]any, 0)
@@ -0,0 +1,125 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CardLinkType is the kind of relationship between two cards.
+type CardLinkType string
+
+const (
+	// CardLinkBlocks means that the source card blocks the target card.
+	CardLinkBlocks CardLinkType = "blocks"
+
+	// CardLinkBlockedBy means that the source card is blocked by the target
+	// card. It is only accepted when creating links, which are stored as
+	// CardLinkBlocks with their ends swapped.
+	CardLinkBlockedBy CardLinkType = "blocked_by"
+
+	// CardLinkRelatesTo means that the cards are related.
+	CardLinkRelatesTo CardLinkType = "relates_to"
+
+	// CardLinkDuplicates means that the source card duplicates the target card.
+	CardLinkDuplicates CardLinkType = "duplicates"
+)
+
+// CardLink is a typed link between two cards, possibly on different boards
+// swagger:model
+type CardLink struct {
+	// The id of the link
+	// required: true
+	ID string `json:"id"`
+
+	// The kind of relationship
+	// required: true
+	Type CardLinkType `json:"type"`
+
+	// The id of the card the link starts from
+	// required: true
+	SourceCardID string `json:"sourceCardId"`
+
+	// The board of the source card
+	// required: true
+	SourceBoardID string `json:"sourceBoardId"`
+
+	// The id of the card the link points to
+	// required: true
+	TargetCardID string `json:"targetCardId"`
+
+	// The board of the target card
+	// required: true
+	TargetBoardID string `json:"targetBoardId"`
+
+	// The id of the user who created the link
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// Normalize stores "blocked by" links as "blocks" links from the other card.
+func (l *CardLink) Normalize() {
+	if l.Type == CardLinkBlockedBy {
+		l.Type = CardLinkBlocks
+		l.SourceCardID, l.TargetCardID = l.TargetCardID, l.SourceCardID
+		l.SourceBoardID, l.TargetBoardID = l.TargetBoardID, l.SourceBoardID
+	}
+}
+
+func (l *CardLink) IsValid() error {
+	switch l.Type {
+	case CardLinkBlocks, CardLinkRelatesTo, CardLinkDuplicates:
+	default:
+		return NewErrBadRequest(fmt.Sprintf("invalid card link type %q", l.Type))
+	}
+
+	if l.SourceCardID == "" || l.TargetCardID == "" {
+		return NewErrBadRequest("card link is missing a card id")
+	}
+	if l.SourceCardID == l.TargetCardID {
+		return NewErrBadRequest("a card cannot be linked to itself")
+	}
+	if l.SourceBoardID == "" || l.TargetBoardID == "" {
+		return NewErrBadRequest("card link is missing a board id")
+	}
+	return nil
+}
+
+// OtherCardID returns the id of the card at the other end of the link.
+func (l *CardLink) OtherCardID(cardID string) string {
+	if l.SourceCardID == cardID {
+		return l.TargetCardID
+	}
+	return l.SourceCardID
+}
+
+// IsDoneOptionValue returns true if the value of a select option marks cards
+// as done, following the names used by the built-in templates, e.g. "Done",
+// "Completed 🙌" or "Complete".
+func IsDoneOptionValue(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.HasPrefix(value, "done") || strings.HasPrefix(value, "complete")
+}
+
+// IsCardDone returns true if one of the select properties of the card is
+// set to an option that marks it as done.
+func IsCardDone(card *Block, schema PropSchema) bool {
+	properties, _ := card.Fields["properties"].(map[string]any)
+	for propID, value := range properties {
+		pd, ok := schema[propID]
+		if !ok || pd.Type != "select" {
+			continue
+		}
+		optionID, _ := value.(string)
+		if option, ok := pd.Options[optionID]; ok && IsDoneOptionValue(option.Value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardLinkNormalize(t *testing.T) {
+	link := &CardLink{
+		Type:          CardLinkBlockedBy,
+		SourceCardID:  "card-1",
+		SourceBoardID: "board-1",
+		TargetCardID:  "card-2",
+		TargetBoardID: "board-2",
+	}
+	link.Normalize()
+	require.Equal(t, CardLinkBlocks, link.Type)
+	require.Equal(t, "card-2", link.SourceCardID)
+	require.Equal(t, "board-2", link.SourceBoardID)
+	require.Equal(t, "card-1", link.TargetCardID)
+	require.Equal(t, "board-1", link.TargetBoardID)
+	require.NoError(t, link.IsValid())
+
+	link.Type = CardLinkBlockedBy
+	link.TargetCardID = link.SourceCardID
+	require.True(t, IsErrBadRequest(link.IsValid()))
+
+	link = &CardLink{Type: "follows", SourceCardID: "card-1", TargetCardID: "card-2", SourceBoardID: "b", TargetBoardID: "b"}
+	require.True(t, IsErrBadRequest(link.IsValid()))
+	require.Equal(t, "card-2", link.OtherCardID("card-1"))
+	require.Equal(t, "card-1", link.OtherCardID("card-2"))
+}
+
+func TestIsCardDone(t *testing.T) {
+	schema := PropSchema{
+		"status-id": {
+			ID:   "status-id",
+			Type: "select",
+			Options: map[string]PropDefOption{
+				"todo-id":      {ID: "todo-id", Value: "Not Started"},
+				"done-id":      {ID: "done-id", Value: "Done 🙌"},
+				"completed-id": {ID: "completed-id", Value: "Completed"},
+			},
+		},
+		"title-id": {ID: "title-id", Type: "text"},
+	}
+	card := func(properties map[string]any) *Block {
+		return &Block{Type: TypeCard, Fields: map[string]any{"properties": properties}}
+	}
+
+	require.False(t, IsCardDone(card(map[string]any{}), schema))
+	require.False(t, IsCardDone(card(map[string]any{"status-id": "todo-id"}), schema))
+	require.True(t, IsCardDone(card(map[string]any{"status-id": "done-id"}), schema))
+	require.True(t, IsCardDone(card(map[string]any{"status-id": "completed-id"}), schema))
+	require.False(t, IsCardDone(card(map[string]any{"title-id": "done-id"}), schema))
+	require.False(t, IsCardDone(&Block{Type: TypeCard}, schema))
+}
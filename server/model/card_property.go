@@ -63,9 +63,45 @@ func ValidateCardPropertyTemplate(template map[string]any) error {
 		}
 	}
 
+	switch template["type"] {
+	case PropTypeRelation:
+		if boardID, ok := template["relationBoardId"].(string); !ok || boardID == "" {
+			return NewErrInvalidCardProperty(fmt.Sprintf("relation property %q must have a relationBoardId", id))
+		}
+	case PropTypeRollup:
+		if err := validateCardPropertyRollup(id, template["rollup"]); err != nil {
+			return err
+		}
+	}
+
 	return validateCardPropertyOptions(id, template["options"])
 }
 
+func validateCardPropertyRollup(propertyID string, rollup any) error {
+	rollupMap, ok := rollup.(map[string]any)
+	if !ok {
+		return NewErrInvalidCardProperty(fmt.Sprintf("rollup property %q must have a rollup object", propertyID))
+	}
+
+	for _, field := range []string{"relationPropertyId", "targetPropertyId", "function"} {
+		value, exists := rollupMap[field]
+		if !exists {
+			continue
+		}
+
+		if _, ok := value.(string); !ok {
+			return NewErrInvalidCardProperty(fmt.Sprintf("rollup %s of property %q must be a string", field, propertyID))
+		}
+	}
+
+	def := RollupDef{
+		RelationPropertyID: getMapString("relationPropertyId", rollupMap),
+		TargetPropertyID:   getMapString("targetPropertyId", rollupMap),
+		Function:           RollupFunction(getMapString("function", rollupMap)),
+	}
+	return def.IsValid()
+}
+
 func ValidateCardPropertyTemplates(templates []map[string]any) error {
 	for _, template := range templates {
 		if err := ValidateCardPropertyTemplate(template); err != nil {
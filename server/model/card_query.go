@@ -0,0 +1,278 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// TitlePropertyID is the pseudo property id used by views to sort by card title.
+	TitlePropertyID = "__title"
+
+	// TitleFilterPropertyID is the pseudo property id used by view filters to match the card title.
+	TitleFilterPropertyID = "title"
+
+	// CardQueryMaxPerPage is the maximum number of cards that can be requested in a single page.
+	CardQueryMaxPerPage = 1000
+
+	maxFilterGroupDepth = 10
+)
+
+var ErrInvalidCardsCursor = errors.New("invalid cards cursor")
+
+// FilterOperation is the boolean operation used to combine the filters of a FilterGroup.
+type FilterOperation string
+
+const (
+	FilterOperationAnd FilterOperation = "and"
+	FilterOperationOr  FilterOperation = "or"
+)
+
+// FilterCondition is the condition a FilterClause applies to a card property.
+type FilterCondition string
+
+const (
+	FilterConditionIncludes      FilterCondition = "includes"
+	FilterConditionNotIncludes   FilterCondition = "notIncludes"
+	FilterConditionIsEmpty       FilterCondition = "isEmpty"
+	FilterConditionIsNotEmpty    FilterCondition = "isNotEmpty"
+	FilterConditionIsSet         FilterCondition = "isSet"
+	FilterConditionIsNotSet      FilterCondition = "isNotSet"
+	FilterConditionIs            FilterCondition = "is"
+	FilterConditionContains      FilterCondition = "contains"
+	FilterConditionNotContains   FilterCondition = "notContains"
+	FilterConditionStartsWith    FilterCondition = "startsWith"
+	FilterConditionNotStartsWith FilterCondition = "notStartsWith"
+	FilterConditionEndsWith      FilterCondition = "endsWith"
+	FilterConditionNotEndsWith   FilterCondition = "notEndsWith"
+	FilterConditionIsBefore      FilterCondition = "isBefore"
+	FilterConditionIsAfter       FilterCondition = "isAfter"
+)
+
+func (c FilterCondition) IsValid() bool {
+	switch c {
+	case FilterConditionIncludes, FilterConditionNotIncludes,
+		FilterConditionIsEmpty, FilterConditionIsNotEmpty,
+		FilterConditionIsSet, FilterConditionIsNotSet,
+		FilterConditionIs,
+		FilterConditionContains, FilterConditionNotContains,
+		FilterConditionStartsWith, FilterConditionNotStartsWith,
+		FilterConditionEndsWith, FilterConditionNotEndsWith,
+		FilterConditionIsBefore, FilterConditionIsAfter:
+		return true
+	}
+	return false
+}
+
+// FilterClause is a single condition over a card property, as stored in
+// the `filter` field of view blocks.
+// swagger:model
+type FilterClause struct {
+	// The id of the property to check, or "title" for the card title
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The condition to apply
+	// required: true
+	Condition FilterCondition `json:"condition"`
+
+	// The values the condition is checked against. Option ids for select
+	// and multiSelect properties, user ids for person properties and
+	// timestamps in milliseconds for dates
+	// required: false
+	Values []string `json:"values"`
+}
+
+// FilterGroup combines filter clauses and nested groups with a boolean
+// operation. It has the same shape as the `filter` field of view blocks.
+// swagger:model
+type FilterGroup struct {
+	// The operation used to combine the filters, "and" or "or"
+	// required: true
+	Operation FilterOperation `json:"operation"`
+
+	// The filters of the group, each one a clause or a nested group
+	// required: true
+	Filters []FilterGroupItem `json:"filters"`
+}
+
+// FilterGroupItem is an element of a FilterGroup, holding either a
+// clause or a nested group.
+type FilterGroupItem struct {
+	Clause *FilterClause
+	Group  *FilterGroup
+}
+
+func (fi FilterGroupItem) MarshalJSON() ([]byte, error) {
+	if fi.Group != nil {
+		return json.Marshal(fi.Group)
+	}
+	return json.Marshal(fi.Clause)
+}
+
+func (fi *FilterGroupItem) UnmarshalJSON(data []byte) error {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	// same check as isAFilterGroupInstance in the webapp
+	_, hasOperation := probe["operation"]
+	_, hasFilters := probe["filters"]
+	if hasOperation && hasFilters {
+		fi.Group = &FilterGroup{}
+		return json.Unmarshal(data, fi.Group)
+	}
+
+	fi.Clause = &FilterClause{}
+	return json.Unmarshal(data, fi.Clause)
+}
+
+// IsValid checks that the group and all of its nested filters are well formed.
+func (fg *FilterGroup) IsValid() error {
+	return fg.isValid(0)
+}
+
+func (fg *FilterGroup) isValid(depth int) error {
+	if depth > maxFilterGroupDepth {
+		return NewErrBadRequest("filter groups are nested too deeply")
+	}
+
+	if fg.Operation != FilterOperationAnd && fg.Operation != FilterOperationOr {
+		return NewErrBadRequest(fmt.Sprintf("invalid filter operation %q", fg.Operation))
+	}
+
+	for _, item := range fg.Filters {
+		switch {
+		case item.Group != nil:
+			if err := item.Group.isValid(depth + 1); err != nil {
+				return err
+			}
+		case item.Clause != nil:
+			if item.Clause.PropertyID == "" {
+				return NewErrBadRequest("filter clause is missing its property id")
+			}
+			if !item.Clause.Condition.IsValid() {
+				return NewErrBadRequest(fmt.Sprintf("invalid filter condition %q", item.Clause.Condition))
+			}
+		default:
+			return NewErrBadRequest("empty filter")
+		}
+	}
+	return nil
+}
+
+// CardSortOption is a sort key for card queries, with the same shape as
+// the `sortOptions` field of view blocks.
+// swagger:model
+type CardSortOption struct {
+	// The id of the property to sort by, or "__title" for the card title
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// If true the sort is descending
+	// required: false
+	Reversed bool `json:"reversed"`
+}
+
+// CardQuery is the body of a card query request
+// swagger:model
+type CardQuery struct {
+	// The filter to apply to the cards of the board
+	// required: false
+	Filter *FilterGroup `json:"filter,omitempty"`
+
+	// The sort keys, in order of precedence
+	// required: false
+	SortOptions []CardSortOption `json:"sortOptions,omitempty"`
+
+	// The cursor returned by a previous query to fetch the next page
+	// required: false
+	Cursor string `json:"cursor,omitempty"`
+
+	// The number of cards per page
+	// required: false
+	PerPage int `json:"perPage,omitempty"`
+}
+
+// IsValid checks the query for errors before running it.
+func (q *CardQuery) IsValid() error {
+	if q.Filter != nil {
+		if err := q.Filter.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	for _, opt := range q.SortOptions {
+		if opt.PropertyID == "" {
+			return NewErrBadRequest("sort option is missing its property id")
+		}
+	}
+
+	if q.PerPage < 0 || q.PerPage > CardQueryMaxPerPage {
+		return NewErrBadRequest(fmt.Sprintf("perPage must be between 0 and %d", CardQueryMaxPerPage))
+	}
+
+	if q.Cursor != "" {
+		if _, err := DecodeCardsCursor(q.Cursor); err != nil {
+			return NewErrBadRequest(err.Error())
+		}
+	}
+	return nil
+}
+
+// CardQueryResult is the response to a card query
+// swagger:model
+type CardQueryResult struct {
+	// The cards of the page
+	// required: true
+	Cards []*Card `json:"cards"`
+
+	// The cursor to fetch the next page, empty if there are no more cards
+	// required: false
+	NextCursor string `json:"nextCursor"`
+}
+
+// QueryCardsOptions are query options that can be passed to QueryCards.
+type QueryCardsOptions struct {
+	BoardID     string           // the board the cards belong to
+	Filter      *FilterGroup     // if not nil then filter the cards with it
+	SortOptions []CardSortOption // sort keys in order of precedence; cards are always tie-broken by id
+	Cursor      *CardsCursor     // if not nil then return the cards after the cursor
+	PerPage     int              // number of cards per page (0 means unlimited)
+}
+
+// CardsCursor is the position of the last card of a page within a sorted
+// card query. Keys holds the values of the sort keys for that card.
+type CardsCursor struct {
+	Keys []interface{} `json:"k"`
+	ID   string        `json:"id"`
+}
+
+// Encode returns the opaque representation of the cursor handed to clients.
+func (c *CardsCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCardsCursor parses a cursor returned by CardsCursor.Encode.
+func DecodeCardsCursor(s string) (*CardsCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCardsCursor
+	}
+
+	var cursor CardsCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCardsCursor
+	}
+	if cursor.ID == "" {
+		return nil, ErrInvalidCardsCursor
+	}
+	return &cursor, nil
+}
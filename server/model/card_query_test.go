@@ -0,0 +1,127 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterGroupJSON(t *testing.T) {
+	t.Run("clauses and nested groups", func(t *testing.T) {
+		data := `{
+			"operation": "and",
+			"filters": [
+				{"propertyId": "prop1", "condition": "includes", "values": ["opt1", "opt2"]},
+				{"operation": "or", "filters": [
+					{"propertyId": "title", "condition": "contains", "values": ["foo"]},
+					{"propertyId": "prop2", "condition": "isEmpty", "values": []}
+				]}
+			]
+		}`
+
+		var group FilterGroup
+		require.NoError(t, json.Unmarshal([]byte(data), &group))
+		require.NoError(t, group.IsValid())
+
+		assert.Equal(t, FilterOperationAnd, group.Operation)
+		require.Len(t, group.Filters, 2)
+
+		require.NotNil(t, group.Filters[0].Clause)
+		assert.Nil(t, group.Filters[0].Group)
+		assert.Equal(t, "prop1", group.Filters[0].Clause.PropertyID)
+		assert.Equal(t, FilterConditionIncludes, group.Filters[0].Clause.Condition)
+		assert.Equal(t, []string{"opt1", "opt2"}, group.Filters[0].Clause.Values)
+
+		nested := group.Filters[1].Group
+		require.NotNil(t, nested)
+		assert.Nil(t, group.Filters[1].Clause)
+		assert.Equal(t, FilterOperationOr, nested.Operation)
+		require.Len(t, nested.Filters, 2)
+		assert.Equal(t, TitleFilterPropertyID, nested.Filters[0].Clause.PropertyID)
+
+		out, err := json.Marshal(group)
+		require.NoError(t, err)
+
+		var roundTrip FilterGroup
+		require.NoError(t, json.Unmarshal(out, &roundTrip))
+		assert.Equal(t, group, roundTrip)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		group := FilterGroup{Operation: "xor"}
+		err := group.IsValid()
+		require.Error(t, err)
+		assert.True(t, IsErrBadRequest(err))
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		group := FilterGroup{
+			Operation: FilterOperationAnd,
+			Filters: []FilterGroupItem{
+				{Clause: &FilterClause{PropertyID: "prop1", Condition: "matches"}},
+			},
+		}
+		err := group.IsValid()
+		require.Error(t, err)
+		assert.True(t, IsErrBadRequest(err))
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		group := &FilterGroup{Operation: FilterOperationAnd}
+		for i := 0; i <= maxFilterGroupDepth; i++ {
+			group = &FilterGroup{
+				Operation: FilterOperationOr,
+				Filters:   []FilterGroupItem{{Group: group}},
+			}
+		}
+		err := group.IsValid()
+		require.Error(t, err)
+		assert.True(t, IsErrBadRequest(err))
+	})
+}
+
+func TestCardsCursor(t *testing.T) {
+	t.Run("encode and decode", func(t *testing.T) {
+		cursor := &CardsCursor{
+			Keys: []interface{}{float64(0), "some title", float64(1234)},
+			ID:   "card-id",
+		}
+
+		decoded, err := DecodeCardsCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+
+	t.Run("invalid cursors", func(t *testing.T) {
+		for _, s := range []string{"not base64!", "bm90IGpzb24", (&CardsCursor{}).Encode()} {
+			_, err := DecodeCardsCursor(s)
+			assert.ErrorIs(t, err, ErrInvalidCardsCursor, s)
+		}
+	})
+}
+
+func TestCardQueryIsValid(t *testing.T) {
+	t.Run("empty query", func(t *testing.T) {
+		require.NoError(t, (&CardQuery{}).IsValid())
+	})
+
+	t.Run("per page out of bounds", func(t *testing.T) {
+		require.Error(t, (&CardQuery{PerPage: -1}).IsValid())
+		require.Error(t, (&CardQuery{PerPage: CardQueryMaxPerPage + 1}).IsValid())
+	})
+
+	t.Run("sort option without property", func(t *testing.T) {
+		require.Error(t, (&CardQuery{SortOptions: []CardSortOption{{}}}).IsValid())
+	})
+
+	t.Run("bad cursor", func(t *testing.T) {
+		err := (&CardQuery{Cursor: "garbage"}).IsValid()
+		require.Error(t, err)
+		assert.True(t, IsErrBadRequest(err))
+	})
+}
@@ -0,0 +1,437 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// maxRecurrenceSearchDays bounds the search for the next occurrence of a
+// rule, so that rules that can never match (e.g. February 30th) end.
+const maxRecurrenceSearchDays = 366 * 5
+
+var ErrNoNextRecurrence = errors.New("recurrence rule has no next occurrence")
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+	RecurrenceCron    RecurrenceFrequency = "cron"
+)
+
+// RecurrenceRule describes when a recurring card is repeated.
+// swagger:model
+type RecurrenceRule struct {
+	// The frequency of the rule: daily, weekly, monthly or cron
+	// required: true
+	Frequency RecurrenceFrequency `json:"frequency"`
+
+	// Repeat every `interval` days, weeks or months. Defaults to 1
+	// required: false
+	Interval int `json:"interval,omitempty"`
+
+	// The days of the week of a weekly rule, from 0 (Sunday) to 6 (Saturday)
+	// required: false
+	Weekdays []int `json:"weekdays,omitempty"`
+
+	// The day of the month of a monthly rule. Months with less days repeat on their last day
+	// required: false
+	DayOfMonth int `json:"dayOfMonth,omitempty"`
+
+	// The hour of the day of daily, weekly and monthly rules
+	// required: false
+	Hour int `json:"hour"`
+
+	// The minute of the hour of daily, weekly and monthly rules
+	// required: false
+	Minute int `json:"minute"`
+
+	// A cron expression with five fields (minute hour day-of-month month day-of-week)
+	// required: false
+	Cron string `json:"cron,omitempty"`
+
+	// The IANA time zone the rule is evaluated in. Defaults to UTC
+	// required: false
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// CardRecurrence is a recurrence rule attached to a card. Each occurrence
+// creates a copy of the card.
+// swagger:model
+type CardRecurrence struct {
+	// The id of the card that is repeated
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the board of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The rule
+	// required: true
+	Rule RecurrenceRule `json:"rule"`
+
+	// Is the recurrence enabled
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Why the server disabled the recurrence, if it can't create the copies of the card anymore
+	// required: false
+	DisabledReason string `json:"disabledReason,omitempty"`
+
+	// The time of the first occurrence in milliseconds since the current epoch. The date
+	// properties of the card refer to it, and are shifted for the next occurrences
+	// required: false
+	StartAt int64 `json:"startAt"`
+
+	// The time of the next occurrence in milliseconds since the current epoch
+	// required: false
+	NextRunAt int64 `json:"nextRunAt"`
+
+	// The time of the last occurrence in milliseconds since the current epoch
+	// required: false
+	LastRunAt int64 `json:"lastRunAt"`
+
+	// The id of the user that created the recurrence
+	// required: false
+	CreatedBy string `json:"createdBy"`
+
+	// The id of the user that last modified the recurrence. Copies are created on behalf of this user
+	// required: false
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid returns an error if the rule can't be evaluated.
+func (r *RecurrenceRule) IsValid() error {
+	if r.Interval < 0 {
+		return NewErrBadRequest("recurrence interval cannot be negative")
+	}
+	if _, err := r.location(); err != nil {
+		return NewErrBadRequest(fmt.Sprintf("invalid recurrence timezone %q", r.Timezone))
+	}
+
+	switch r.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		if r.Hour < 0 || r.Hour > 23 || r.Minute < 0 || r.Minute > 59 {
+			return NewErrBadRequest("invalid recurrence time of the day")
+		}
+	case RecurrenceCron:
+		if _, err := parseCronExpression(r.Cron); err != nil {
+			return NewErrBadRequest(err.Error())
+		}
+		return nil
+	default:
+		return NewErrBadRequest(fmt.Sprintf("invalid recurrence frequency %q", r.Frequency))
+	}
+
+	if r.Frequency == RecurrenceWeekly {
+		if len(r.Weekdays) == 0 {
+			return NewErrBadRequest("weekly recurrence requires at least one weekday")
+		}
+		for _, weekday := range r.Weekdays {
+			if weekday < 0 || weekday > 6 {
+				return NewErrBadRequest(fmt.Sprintf("invalid recurrence weekday %d", weekday))
+			}
+		}
+	}
+
+	if r.Frequency == RecurrenceMonthly && (r.DayOfMonth < 1 || r.DayOfMonth > 31) {
+		return NewErrBadRequest("monthly recurrence requires a day of the month between 1 and 31")
+	}
+
+	return nil
+}
+
+func (r *RecurrenceRule) location() (*time.Location, error) {
+	if r.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(r.Timezone)
+}
+
+func (r *RecurrenceRule) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// Next returns the first occurrence of the rule after `after`. Intervals
+// greater than one count days, weeks and months from `start`, the first
+// occurrence of the rule, or from `after` if `start` is zero.
+func (r *RecurrenceRule) Next(start, after time.Time) (time.Time, error) {
+	loc, err := r.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	after = after.In(loc)
+	if start.IsZero() {
+		start = after
+	}
+	start = start.In(loc)
+
+	matchesDay, minutes, err := r.schedule(start)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i < maxRecurrenceSearchDays; i++ {
+		if matchesDay(day) {
+			for _, minute := range minutes {
+				t := time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, loc)
+				if t.After(after) {
+					return t, nil
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return time.Time{}, ErrNoNextRecurrence
+}
+
+// schedule returns the days on which the rule occurs and the minutes of
+// the day of its occurrences, in ascending order.
+func (r *RecurrenceRule) schedule(start time.Time) (func(day time.Time) bool, []int, error) {
+	interval := r.interval()
+	minutes := []int{r.Hour*60 + r.Minute}
+
+	switch r.Frequency {
+	case RecurrenceDaily:
+		return func(day time.Time) bool {
+			return daysBetween(start, day)%interval == 0
+		}, minutes, nil
+
+	case RecurrenceWeekly:
+		weekdays := map[time.Weekday]bool{}
+		for _, weekday := range r.Weekdays {
+			weekdays[time.Weekday(weekday)] = true
+		}
+		return func(day time.Time) bool {
+			weeks := daysBetween(startOfWeek(start), startOfWeek(day)) / 7
+			return weekdays[day.Weekday()] && weeks%interval == 0
+		}, minutes, nil
+
+	case RecurrenceMonthly:
+		return func(day time.Time) bool {
+			months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+			if months%interval != 0 {
+				return false
+			}
+			lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+			dayOfMonth := r.DayOfMonth
+			if dayOfMonth > lastDay {
+				dayOfMonth = lastDay
+			}
+			return day.Day() == dayOfMonth
+		}, minutes, nil
+
+	case RecurrenceCron:
+		expr, err := parseCronExpression(r.Cron)
+		if err != nil {
+			return nil, nil, err
+		}
+		return expr.matchesDay, expr.minutesOfDay(), nil
+	}
+
+	return nil, nil, fmt.Errorf("invalid recurrence frequency %q", r.Frequency)
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
+
+func startOfWeek(t time.Time) time.Time {
+	return t.AddDate(0, 0, -int(t.Weekday()))
+}
+
+// cronExpression is a parsed five field cron expression. Each field is the
+// set of values it matches.
+type cronExpression struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// as in cron, if both days fields are restricted a day matches if
+	// either of them matches
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func parseCronExpression(s string) (*cronExpression, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", s)
+	}
+
+	bounds := []struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", s, err)
+		}
+		sets[i] = set
+	}
+
+	// both 0 and 7 are Sunday
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronExpression{
+		minutes:       sets[0],
+		hours:         sets[1],
+		daysOfMonth:   sets[2],
+		months:        sets[3],
+		daysOfWeek:    sets[4],
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a comma separated list of `*`, values and ranges,
+// each with an optional `/step`.
+func parseCronField(field string, minValue, maxValue int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		from, to := minValue, maxValue
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value in %q", part)
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid range in %q", part)
+				}
+			} else if step > 1 {
+				to = maxValue
+			}
+		}
+		if from < minValue || to > maxValue || from > to {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, minValue, maxValue)
+		}
+
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (c *cronExpression) matchesDay(day time.Time) bool {
+	if !c.months[int(day.Month())] {
+		return false
+	}
+
+	dayOfMonth := c.daysOfMonth[day.Day()]
+	dayOfWeek := c.daysOfWeek[int(day.Weekday())]
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+func (c *cronExpression) minutesOfDay() []int {
+	minutes := []int{}
+	for hour := 0; hour < 24; hour++ {
+		if !c.hours[hour] {
+			continue
+		}
+		for minute := 0; minute < 60; minute++ {
+			if c.minutes[minute] {
+				minutes = append(minutes, hour*60+minute)
+			}
+		}
+	}
+	return minutes
+}
+
+// ShiftDateProperties returns a copy of the card properties with the date
+// properties of the board shifted by `delta` milliseconds.
+func ShiftDateProperties(properties map[string]any, schema PropSchema, delta int64) map[string]any {
+	shifted := make(map[string]any, len(properties))
+	for propID, value := range properties {
+		shifted[propID] = value
+
+		pd, ok := schema[propID]
+		if !ok || pd.Type != "date" || delta == 0 {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok || s == "" {
+			continue
+		}
+
+		var date map[string]int64
+		if err := json.Unmarshal([]byte(s), &date); err != nil {
+			continue
+		}
+		for key, ts := range date {
+			if key == "from" || key == "to" {
+				date[key] = ts + delta
+			}
+		}
+		if data, err := json.Marshal(date); err == nil {
+			shifted[propID] = string(data)
+		}
+	}
+	return shifted
+}
+
+// NextRunAfter computes the occurrence that follows `after`, in
+// milliseconds since the current epoch.
+func (cr *CardRecurrence) NextRunAfter(after int64) (int64, error) {
+	var start time.Time
+	if cr.StartAt != 0 {
+		start = utils.GetTimeForMillis(cr.StartAt)
+	}
+	next, err := cr.Rule.Next(start, utils.GetTimeForMillis(after))
+	if err != nil {
+		return 0, err
+	}
+	return utils.GetMillisForTime(next), nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceRuleIsValid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rule  RecurrenceRule
+		valid bool
+	}{
+		{"daily", RecurrenceRule{Frequency: RecurrenceDaily, Hour: 9}, true},
+		{"weekly", RecurrenceRule{Frequency: RecurrenceWeekly, Weekdays: []int{1, 5}}, true},
+		{"monthly", RecurrenceRule{Frequency: RecurrenceMonthly, DayOfMonth: 31}, true},
+		{"cron", RecurrenceRule{Frequency: RecurrenceCron, Cron: "*/15 9-17 * * 1-5"}, true},
+		{"timezone", RecurrenceRule{Frequency: RecurrenceDaily, Timezone: "Europe/Madrid"}, true},
+		{"unknown frequency", RecurrenceRule{Frequency: "yearly"}, false},
+		{"invalid hour", RecurrenceRule{Frequency: RecurrenceDaily, Hour: 24}, false},
+		{"negative interval", RecurrenceRule{Frequency: RecurrenceDaily, Interval: -1}, false},
+		{"weekly without weekdays", RecurrenceRule{Frequency: RecurrenceWeekly}, false},
+		{"invalid weekday", RecurrenceRule{Frequency: RecurrenceWeekly, Weekdays: []int{7}}, false},
+		{"monthly without day", RecurrenceRule{Frequency: RecurrenceMonthly}, false},
+		{"cron with 4 fields", RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 9 * *"}, false},
+		{"cron out of range", RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 25 * * *"}, false},
+		{"cron with invalid step", RecurrenceRule{Frequency: RecurrenceCron, Cron: "*/0 * * * *"}, false},
+		{"unknown timezone", RecurrenceRule{Frequency: RecurrenceDaily, Timezone: "Mars/Olympus"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.IsValid()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.True(t, IsErrBadRequest(err))
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02 15:04", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	testCases := []struct {
+		name     string
+		rule     RecurrenceRule
+		start    string
+		after    string
+		expected string
+	}{
+		{"daily, later today", RecurrenceRule{Frequency: RecurrenceDaily, Hour: 9}, "", "2024-03-04 08:00", "2024-03-04 09:00"},
+		{"daily, tomorrow", RecurrenceRule{Frequency: RecurrenceDaily, Hour: 9}, "", "2024-03-04 09:00", "2024-03-05 09:00"},
+		{"every 3 days", RecurrenceRule{Frequency: RecurrenceDaily, Interval: 3, Hour: 9}, "2024-03-04 09:00", "2024-03-04 09:00", "2024-03-07 09:00"},
+		{"weekly", RecurrenceRule{Frequency: RecurrenceWeekly, Weekdays: []int{1, 4}, Hour: 8, Minute: 30}, "", "2024-03-04 09:00", "2024-03-07 08:30"},
+		{"every 2 weeks", RecurrenceRule{Frequency: RecurrenceWeekly, Interval: 2, Weekdays: []int{1}}, "2024-03-04 00:00", "2024-03-04 00:00", "2024-03-18 00:00"},
+		{"monthly", RecurrenceRule{Frequency: RecurrenceMonthly, DayOfMonth: 15, Hour: 12}, "", "2024-03-20 00:00", "2024-04-15 12:00"},
+		{"monthly, short month", RecurrenceRule{Frequency: RecurrenceMonthly, DayOfMonth: 31}, "", "2024-02-01 00:00", "2024-02-29 00:00"},
+		{"every 3 months", RecurrenceRule{Frequency: RecurrenceMonthly, Interval: 3, DayOfMonth: 1}, "2024-01-01 00:00", "2024-01-01 00:00", "2024-04-01 00:00"},
+		{"cron, weekdays", RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 9 * * 1-5"}, "", "2024-03-08 10:00", "2024-03-11 09:00"},
+		{"cron, steps", RecurrenceRule{Frequency: RecurrenceCron, Cron: "*/20 * * * *"}, "", "2024-03-08 10:45", "2024-03-08 11:00"},
+		{"cron, sunday as 7", RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 0 * * 7"}, "", "2024-03-08 10:00", "2024-03-10 00:00"},
+		{"cron, day of month or week", RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 0 15 * 1"}, "", "2024-03-12 00:00", "2024-03-15 00:00"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var start time.Time
+			if tc.start != "" {
+				start = date(tc.start)
+			}
+			next, err := tc.rule.Next(start, date(tc.after))
+			require.NoError(t, err)
+			require.Equal(t, date(tc.expected), next.UTC())
+		})
+	}
+
+	t.Run("timezone", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: RecurrenceDaily, Hour: 9, Timezone: "America/New_York"}
+		next, err := rule.Next(time.Time{}, date("2024-07-01 12:00"))
+		require.NoError(t, err)
+		require.Equal(t, date("2024-07-01 13:00"), next.UTC())
+	})
+
+	t.Run("no next occurrence", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: RecurrenceCron, Cron: "0 0 30 2 *"}
+		_, err := rule.Next(time.Time{}, date("2024-01-01 00:00"))
+		require.ErrorIs(t, err, ErrNoNextRecurrence)
+	})
+}
+
+func TestShiftDateProperties(t *testing.T) {
+	schema := PropSchema{
+		"due-id":    {ID: "due-id", Type: "date"},
+		"status-id": {ID: "status-id", Type: "select"},
+	}
+	properties := map[string]any{
+		"due-id":    `{"from":1000,"to":2000}`,
+		"status-id": "done-id",
+	}
+
+	shifted := ShiftDateProperties(properties, schema, 500)
+	require.Equal(t, map[string]any{
+		"due-id":    `{"from":1500,"to":2500}`,
+		"status-id": "done-id",
+	}, shifted)
+	require.Equal(t, `{"from":1000,"to":2000}`, properties["due-id"])
+}
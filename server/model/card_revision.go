@@ -0,0 +1,249 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"reflect"
+	"sort"
+)
+
+const (
+	CardRevisionsDefaultPerPage = 20
+	CardRevisionsMaxPerPage     = 100
+)
+
+// CardRevision is the state of a card and its content blocks after a
+// change to any of them.
+// swagger:model
+type CardRevision struct {
+	// The time of the change, in miliseconds since the current epoch. It
+	// identifies the revision
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The ID of the user that made the change
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The card at the revision
+	// required: true
+	Card *Card `json:"card"`
+
+	// The content blocks of the card at the revision, comments excluded
+	// required: true
+	Contents []*Block `json:"contents"`
+}
+
+// QueryCardRevisionsOptions are query options that can be passed to GetCardRevisions.
+type QueryCardRevisionsOptions struct {
+	BeforeUpdateAt int64 // if non-zero then return the revisions older than BeforeUpdateAt
+	PerPage        int   // number of revisions to return, newest first
+}
+
+// CardRevisionRestore is the set of block changes that restore a card
+// and its content blocks to a revision, which are applied together.
+type CardRevisionRestore struct {
+	InsertBlocks   []*Block        // the blocks deleted since the revision
+	PatchBlocks    BlockPatchBatch // the blocks changed since the revision
+	DeleteBlockIDs []string        // the blocks added since the revision
+}
+
+// CardValueChange is the old and new value of a field of a card.
+// swagger:model
+type CardValueChange struct {
+	// The value in the older revision
+	// required: true
+	Old interface{} `json:"old"`
+
+	// The value in the newer revision
+	// required: true
+	New interface{} `json:"new"`
+}
+
+// CardPropertyChange is the old and new value of a property of a card.
+// swagger:model
+type CardPropertyChange struct {
+	// The ID of the property template
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	CardValueChange
+}
+
+// CardContentChange is a content block that changed between two revisions.
+// swagger:model
+type CardContentChange struct {
+	// The block in the older revision
+	// required: true
+	Old *Block `json:"old"`
+
+	// The block in the newer revision
+	// required: true
+	New *Block `json:"new"`
+}
+
+// CardRevisionDiff is the difference between two revisions of a card.
+// swagger:model
+type CardRevisionDiff struct {
+	// The update time of the older revision
+	// required: true
+	From int64 `json:"from"`
+
+	// The update time of the newer revision
+	// required: true
+	To int64 `json:"to"`
+
+	// The title change, if it changed
+	// required: false
+	Title *CardValueChange `json:"title,omitempty"`
+
+	// The icon change, if it changed
+	// required: false
+	Icon *CardValueChange `json:"icon,omitempty"`
+
+	// The properties that changed, sorted by property ID
+	// required: true
+	Properties []*CardPropertyChange `json:"properties"`
+
+	// The content blocks that exist in the newer revision only
+	// required: true
+	AddedContents []*Block `json:"addedContents"`
+
+	// The content blocks that exist in the older revision only
+	// required: true
+	RemovedContents []*Block `json:"removedContents"`
+
+	// The content blocks that changed between the revisions
+	// required: true
+	ChangedContents []*CardContentChange `json:"changedContents"`
+}
+
+// DiffCardRevisions returns the changes to go from one revision of a card
+// to another.
+func DiffCardRevisions(from, to *CardRevision) *CardRevisionDiff {
+	diff := &CardRevisionDiff{
+		From:            from.UpdateAt,
+		To:              to.UpdateAt,
+		Properties:      []*CardPropertyChange{},
+		AddedContents:   []*Block{},
+		RemovedContents: []*Block{},
+		ChangedContents: []*CardContentChange{},
+	}
+
+	if from.Card.Title != to.Card.Title {
+		diff.Title = &CardValueChange{Old: from.Card.Title, New: to.Card.Title}
+	}
+	if from.Card.Icon != to.Card.Icon {
+		diff.Icon = &CardValueChange{Old: from.Card.Icon, New: to.Card.Icon}
+	}
+
+	propertyIDs := map[string]bool{}
+	for id := range from.Card.Properties {
+		propertyIDs[id] = true
+	}
+	for id := range to.Card.Properties {
+		propertyIDs[id] = true
+	}
+	for id := range propertyIDs {
+		oldValue, newValue := from.Card.Properties[id], to.Card.Properties[id]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diff.Properties = append(diff.Properties, &CardPropertyChange{
+				PropertyID:      id,
+				CardValueChange: CardValueChange{Old: oldValue, New: newValue},
+			})
+		}
+	}
+	sort.Slice(diff.Properties, func(i, j int) bool {
+		return diff.Properties[i].PropertyID < diff.Properties[j].PropertyID
+	})
+
+	fromContents := map[string]*Block{}
+	for _, block := range from.Contents {
+		fromContents[block.ID] = block
+	}
+	toContents := map[string]*Block{}
+	for _, block := range to.Contents {
+		toContents[block.ID] = block
+		oldBlock, ok := fromContents[block.ID]
+		if !ok {
+			diff.AddedContents = append(diff.AddedContents, block)
+			continue
+		}
+		if oldBlock.Type != block.Type || oldBlock.Title != block.Title || !reflect.DeepEqual(oldBlock.Fields, block.Fields) {
+			diff.ChangedContents = append(diff.ChangedContents, &CardContentChange{Old: oldBlock, New: block})
+		}
+	}
+	for _, block := range from.Contents {
+		if _, ok := toContents[block.ID]; !ok {
+			diff.RemovedContents = append(diff.RemovedContents, block)
+		}
+	}
+
+	return diff
+}
+
+// NewBlockRestorePatch returns the patch that changes the title and the
+// fields of a block to the ones of a previous version of it, or nil if
+// they are the same. As patches merge the properties per key, the
+// properties missing in the previous version are removed with a nil value.
+func NewBlockRestorePatch(current, previous *Block) *BlockPatch {
+	patch := &BlockPatch{}
+	changed := false
+
+	if current.Title != previous.Title {
+		title := previous.Title
+		patch.Title = &title
+		changed = true
+	}
+
+	updatedFields := map[string]interface{}{}
+	for key, value := range previous.Fields {
+		if key == BlockFieldProperties {
+			continue
+		}
+		if !reflect.DeepEqual(current.Fields[key], value) {
+			updatedFields[key] = value
+		}
+	}
+
+	currentProperties, _ := current.Fields[BlockFieldProperties].(map[string]interface{})
+	previousProperties, _ := previous.Fields[BlockFieldProperties].(map[string]interface{})
+	properties := map[string]interface{}{}
+	for key, value := range previousProperties {
+		if !reflect.DeepEqual(currentProperties[key], value) {
+			properties[key] = value
+		}
+	}
+	for key := range currentProperties {
+		if _, ok := previousProperties[key]; !ok {
+			properties[key] = nil
+		}
+	}
+	if len(properties) > 0 {
+		updatedFields[BlockFieldProperties] = properties
+	}
+
+	if len(updatedFields) > 0 {
+		patch.UpdatedFields = updatedFields
+		changed = true
+	}
+
+	for key := range current.Fields {
+		if key == BlockFieldProperties {
+			continue
+		}
+		if _, ok := previous.Fields[key]; !ok {
+			patch.DeletedFields = append(patch.DeletedFields, key)
+		}
+	}
+	if len(patch.DeletedFields) > 0 {
+		sort.Strings(patch.DeletedFields)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return patch
+}
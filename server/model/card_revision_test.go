@@ -0,0 +1,103 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCardRevisions(t *testing.T) {
+	text := &Block{ID: "text-id", Type: TypeText, Title: "first"}
+	image := &Block{ID: "image-id", Type: TypeImage}
+	checkbox := &Block{ID: "checkbox-id", Type: TypeCheckbox, Title: "todo"}
+
+	from := &CardRevision{
+		UpdateAt: 1000,
+		Card: &Card{
+			Title:      "old title",
+			Icon:       "🚀",
+			Properties: map[string]interface{}{"status": "open", "removed": "value"},
+		},
+		Contents: []*Block{text, image},
+	}
+	to := &CardRevision{
+		UpdateAt: 2000,
+		Card: &Card{
+			Title:      "new title",
+			Icon:       "🚀",
+			Properties: map[string]interface{}{"status": "done", "added": "value"},
+		},
+		Contents: []*Block{{ID: "text-id", Type: TypeText, Title: "second"}, checkbox},
+	}
+
+	diff := DiffCardRevisions(from, to)
+	require.Equal(t, int64(1000), diff.From)
+	require.Equal(t, int64(2000), diff.To)
+	require.Equal(t, &CardValueChange{Old: "old title", New: "new title"}, diff.Title)
+	require.Nil(t, diff.Icon)
+	require.Equal(t, []*CardPropertyChange{
+		{PropertyID: "added", CardValueChange: CardValueChange{Old: nil, New: "value"}},
+		{PropertyID: "removed", CardValueChange: CardValueChange{Old: "value", New: nil}},
+		{PropertyID: "status", CardValueChange: CardValueChange{Old: "open", New: "done"}},
+	}, diff.Properties)
+	require.Equal(t, []*Block{checkbox}, diff.AddedContents)
+	require.Equal(t, []*Block{image}, diff.RemovedContents)
+	require.Len(t, diff.ChangedContents, 1)
+	require.Equal(t, "first", diff.ChangedContents[0].Old.Title)
+	require.Equal(t, "second", diff.ChangedContents[0].New.Title)
+
+	t.Run("a revision has no changes with itself", func(t *testing.T) {
+		diff := DiffCardRevisions(from, from)
+		require.Nil(t, diff.Title)
+		require.Empty(t, diff.Properties)
+		require.Empty(t, diff.AddedContents)
+		require.Empty(t, diff.RemovedContents)
+		require.Empty(t, diff.ChangedContents)
+	})
+}
+
+func TestNewBlockRestorePatch(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		block := &Block{
+			Title:  "title",
+			Fields: map[string]interface{}{"icon": "🚀", "properties": map[string]interface{}{"status": "open"}},
+		}
+		require.Nil(t, NewBlockRestorePatch(block, block))
+	})
+
+	t.Run("the patch brings back the previous version", func(t *testing.T) {
+		current := &Block{
+			Title: "new title",
+			Fields: map[string]interface{}{
+				"icon":         "🚀",
+				"isTemplate":   true,
+				"contentOrder": []interface{}{"b", "a"},
+				"properties":   map[string]interface{}{"status": "done", "added": "value", "same": "value"},
+			},
+		}
+		previous := &Block{
+			Title: "old title",
+			Fields: map[string]interface{}{
+				"icon":         "🚀",
+				"contentOrder": []interface{}{"a", "b"},
+				"properties":   map[string]interface{}{"status": "open", "removed": "value", "same": "value"},
+			},
+		}
+
+		patch := NewBlockRestorePatch(current, previous)
+		require.NotNil(t, patch)
+		require.Equal(t, "old title", *patch.Title)
+		require.Equal(t, map[string]interface{}{
+			"contentOrder": []interface{}{"a", "b"},
+			"properties":   map[string]interface{}{"status": "open", "removed": "value", "added": nil},
+		}, patch.UpdatedFields)
+		require.Equal(t, []string{"isTemplate"}, patch.DeletedFields)
+
+		restored := patch.Patch(current)
+		require.Equal(t, previous.Title, restored.Title)
+		require.Equal(t, previous.Fields, restored.Fields)
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// CardSearchMaxPerPage is the maximum number of cards a search can return per page.
+	CardSearchMaxPerPage = 100
+
+	// SearchSnippetContext is the number of characters shown around
+	// the first match in a search snippet.
+	SearchSnippetContext = 60
+
+	searchHighlightMarker = "**"
+)
+
+// SearchCardsOptions are the options of a card search.
+type SearchCardsOptions struct {
+	TeamID              string   // the team to search in
+	UserID              string   // only cards of boards the user can see are returned
+	Words               []string // the words to search for, as returned by SearchTermWords
+	IncludePublicBoards bool     // if true, cards of open boards are returned even if the user is not a member
+	Page                int      // page to select
+	PerPage             int      // number of cards per page
+}
+
+// CardSearchMatch is a card found by a search, together with the block
+// that matched the search: the card itself, or one of its text, checkbox
+// or comment blocks.
+type CardSearchMatch struct {
+	Card      *Block
+	MatchType BlockType
+	MatchText string
+}
+
+// CardSearchResult is a card that matched a search
+// swagger:model
+type CardSearchResult struct {
+	// The card that matched the search
+	// required: true
+	Card *Card `json:"card"`
+
+	// The board the card belongs to
+	// required: true
+	Board *Board `json:"board"`
+
+	// The type of block that matched: card for the card title, or text,
+	// checkbox or comment for the card content
+	// required: true
+	MatchType BlockType `json:"matchType"`
+
+	// A fragment of the matching text with the search words surrounded
+	// by double asterisks
+	// required: true
+	Snippet string `json:"snippet"`
+}
+
+// SearchTermWords splits a search term into the words to look for.
+// Punctuation is treated as a separator, so the words only contain
+// letters and digits and are safe to use in full text queries.
+func SearchTermWords(term string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, len(fields))
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// SearchSnippet returns a fragment of text around the first occurrence
+// of any of the words, with all the occurrences in the fragment
+// highlighted. Matching is case insensitive and by prefix, like the full
+// text search.
+func SearchSnippet(text string, words []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// some characters change their length when lowercased, so
+		// positions can't be shared between both versions
+		lower = runes
+	}
+
+	type match struct{ start, end int }
+	matches := []match{}
+	for i := 0; i < len(lower); {
+		found := 0
+		for _, w := range words {
+			wr := []rune(w)
+			if len(wr) > found && hasRunePrefix(lower[i:], wr) && (i == 0 || !isWordRune(lower[i-1])) {
+				found = len(wr)
+			}
+		}
+		if found == 0 {
+			i++
+			continue
+		}
+
+		// extend the match to the end of the word
+		end := i + found
+		for end < len(lower) && isWordRune(lower[end]) {
+			end++
+		}
+		matches = append(matches, match{i, end})
+		i = end
+	}
+
+	if len(matches) == 0 {
+		return truncateRunes(runes, 2*SearchSnippetContext)
+	}
+
+	start := matches[0].start - SearchSnippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := matches[0].end + SearchSnippetContext
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.start >= end {
+			break
+		}
+		mEnd := m.end
+		if mEnd > end {
+			mEnd = end
+		}
+		sb.WriteString(string(runes[pos:m.start]))
+		sb.WriteString(searchHighlightMarker)
+		sb.WriteString(string(runes[m.start:mEnd]))
+		sb.WriteString(searchHighlightMarker)
+		pos = mEnd
+	}
+	sb.WriteString(string(runes[pos:end]))
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func truncateRunes(runes []rune, n int) string {
+	if n >= len(runes) {
+		return strings.Join(strings.Fields(string(runes)), " ")
+	}
+	return strings.Join(strings.Fields(string(runes[:n])), " ") + "…"
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTermWords(t *testing.T) {
+	testCases := []struct {
+		name     string
+		term     string
+		expected []string
+	}{
+		{"empty", "", []string{}},
+		{"only punctuation", " -- !! ", []string{}},
+		{"lowercased", "Hello World", []string{"hello", "world"}},
+		{"punctuation splits words", "foo-bar's (baz) & qux:*", []string{"foo", "bar", "s", "baz", "qux"}},
+		{"duplicates removed", "one two ONE", []string{"one", "two"}},
+		{"unicode letters", "Größe café", []string{"größe", "café"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SearchTermWords(tc.term))
+		})
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	t.Run("highlights whole words matching by prefix", func(t *testing.T) {
+		snippet := SearchSnippet("Review the Deployment plan before deploying", []string{"deploy"})
+		assert.Equal(t, "Review the **Deployment** plan before **deploying**", snippet)
+	})
+
+	t.Run("does not match inside words", func(t *testing.T) {
+		snippet := SearchSnippet("redeploy and deploy", []string{"deploy"})
+		assert.Equal(t, "redeploy and **deploy**", snippet)
+	})
+
+	t.Run("long text is cut around the first match", func(t *testing.T) {
+		text := strings.Repeat("lorem ", 30) + "needle " + strings.Repeat("ipsum ", 30)
+		snippet := SearchSnippet(text, []string{"needle"})
+
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.Contains(t, snippet, "**needle**")
+		assert.Less(t, len([]rune(snippet)), 2*SearchSnippetContext+20)
+	})
+
+	t.Run("no match returns the start of the text", func(t *testing.T) {
+		assert.Equal(t, "some text", SearchSnippet("some\n  text", []string{"other"}))
+
+		text := strings.Repeat("a", 3*SearchSnippetContext)
+		assert.Equal(t, strings.Repeat("a", 2*SearchSnippetContext)+"…", SearchSnippet(text, []string{"other"}))
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	ChangesDefaultPerPage = 100
+	ChangesMaxPerPage     = 1000
+
+	// ChangesSafetyWindow is how far behind the current time the changes
+	// feed stays. The update time of a change is set before its
+	// transaction commits, so a change could otherwise become visible
+	// after the cursor of a client has moved past it.
+	ChangesSafetyWindow = 5 * time.Second
+)
+
+var ErrInvalidChangesCursor = errors.New("invalid changes cursor")
+
+// ChangeType is the kind of entity a change is about.
+type ChangeType string
+
+const (
+	ChangeTypeBoard    ChangeType = "board"
+	ChangeTypeBlock    ChangeType = "block"
+	ChangeTypeMember   ChangeType = "member"
+	ChangeTypeCategory ChangeType = "category"
+)
+
+// Order returns the position of the changes of the type among the changes
+// that happened in the same millisecond, so the feed has a total order.
+func (t ChangeType) Order() int {
+	switch t {
+	case ChangeTypeBoard:
+		return 0
+	case ChangeTypeBlock:
+		return 1
+	case ChangeTypeMember:
+		return 2
+	case ChangeTypeCategory:
+		return 3
+	}
+	return -1
+}
+
+// Change is an entry of the changes feed of a team. Only the field that
+// matches its type is set, with the state of the entity after the change.
+// swagger:model
+type Change struct {
+	// The type of the entity that changed: board, block, member or category
+	// required: true
+	Type ChangeType `json:"type"`
+
+	// The ID of the board, block or category, or the user ID of the member
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the board the entity belongs to, if any
+	// required: false
+	BoardID string `json:"boardId,omitempty"`
+
+	// The time of the change, in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The deletion time of the entity, zero if the change isn't a deletion
+	// required: true
+	DeleteAt int64 `json:"deleteAt"`
+
+	// The board, for board changes
+	// required: false
+	Board *Board `json:"board,omitempty"`
+
+	// The block, for block changes
+	// required: false
+	Block *Block `json:"block,omitempty"`
+
+	// The membership history entry, for member changes
+	// required: false
+	Member *BoardMemberHistoryEntry `json:"member,omitempty"`
+
+	// The category, for category changes
+	// required: false
+	Category *Category `json:"category,omitempty"`
+}
+
+// Cursor returns the position of the change in the feed.
+func (c *Change) Cursor() *ChangesCursor {
+	cursor := &ChangesCursor{
+		UpdateAt: c.UpdateAt,
+		Type:     c.Type,
+		ID:       c.ID,
+	}
+	if c.Type == ChangeTypeMember && c.Member != nil {
+		// membership history is only ordered by its insertion time
+		insertAt := c.Member.InsertAt
+		cursor.BoardID = c.BoardID
+		cursor.InsertAt = &insertAt
+	}
+	return cursor
+}
+
+// NewBoardChange returns the change for a version of a board.
+func NewBoardChange(board *Board) *Change {
+	return &Change{
+		Type:     ChangeTypeBoard,
+		ID:       board.ID,
+		BoardID:  board.ID,
+		UpdateAt: board.UpdateAt,
+		DeleteAt: board.DeleteAt,
+		Board:    board,
+	}
+}
+
+// NewBlockChange returns the change for a version of a block.
+func NewBlockChange(block *Block) *Change {
+	return &Change{
+		Type:     ChangeTypeBlock,
+		ID:       block.ID,
+		BoardID:  block.BoardID,
+		UpdateAt: block.UpdateAt,
+		DeleteAt: block.DeleteAt,
+		Block:    block,
+	}
+}
+
+// NewMemberChange returns the change for a membership history entry.
+func NewMemberChange(entry *BoardMemberHistoryEntry) *Change {
+	change := &Change{
+		Type:     ChangeTypeMember,
+		ID:       entry.UserID,
+		BoardID:  entry.BoardID,
+		UpdateAt: entry.InsertAt.UnixMilli(),
+		Member:   entry,
+	}
+	if entry.Action == "deleted" {
+		change.DeleteAt = change.UpdateAt
+	}
+	return change
+}
+
+// NewCategoryChange returns the change for a category.
+func NewCategoryChange(category *Category) *Change {
+	return &Change{
+		Type:     ChangeTypeCategory,
+		ID:       category.ID,
+		UpdateAt: category.UpdateAt,
+		DeleteAt: category.DeleteAt,
+		Category: category,
+	}
+}
+
+// ChangesResponse is the response body to a request for the changes of a team.
+// swagger:model
+type ChangesResponse struct {
+	// The changes after the requested cursor, oldest first
+	// required: true
+	Changes []*Change `json:"changes"`
+
+	// The cursor to request the changes after these ones. It is returned
+	// even if there are no more changes, to poll for new ones
+	// required: true
+	Cursor string `json:"cursor"`
+
+	// True if there are more changes after this page
+	// required: true
+	HasNext bool `json:"hasNext"`
+}
+
+// QueryChangesOptions are query options that can be passed to GetChanges.
+type QueryChangesOptions struct {
+	TeamID                string         // the team of the boards
+	UserID                string         // only the changes of the boards the user has access to, of their own memberships and of their categories, are included
+	IncludeImplicitBoards bool           // if true then the boards of the user's channels and the open templates of the team are accessible without a membership
+	Cursor                *ChangesCursor // if not nil then return the changes after the cursor
+	Until                 int64          // if non-zero then only return the changes before Until
+	PerPage               int            // number of changes per page
+}
+
+// ChangesCursor is the position of the last change of a page of the
+// changes feed.
+type ChangesCursor struct {
+	UpdateAt int64      `json:"at"`
+	Type     ChangeType `json:"t"`
+	ID       string     `json:"id"`
+	BoardID  string     `json:"b,omitempty"`
+	InsertAt *time.Time `json:"i,omitempty"`
+}
+
+// Encode returns the opaque representation of the cursor handed to clients.
+func (c *ChangesCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeChangesCursor parses a cursor returned by ChangesCursor.Encode.
+func DecodeChangesCursor(s string) (*ChangesCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidChangesCursor
+	}
+
+	var cursor ChangesCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidChangesCursor
+	}
+	if cursor.ID == "" || cursor.Type.Order() < 0 {
+		return nil, ErrInvalidChangesCursor
+	}
+	if cursor.Type == ChangeTypeMember && (cursor.BoardID == "" || cursor.InsertAt == nil) {
+		return nil, ErrInvalidChangesCursor
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesCursor(t *testing.T) {
+	t.Run("a block change cursor survives encoding", func(t *testing.T) {
+		change := NewBlockChange(&Block{ID: "block-id", BoardID: "board-id", UpdateAt: 1000})
+
+		cursor, err := DecodeChangesCursor(change.Cursor().Encode())
+		require.NoError(t, err)
+		require.Equal(t, &ChangesCursor{UpdateAt: 1000, Type: ChangeTypeBlock, ID: "block-id"}, cursor)
+	})
+
+	t.Run("a member change cursor keeps the insertion time", func(t *testing.T) {
+		insertAt := time.Date(2024, 1, 2, 3, 4, 5, 678901000, time.UTC)
+		change := NewMemberChange(&BoardMemberHistoryEntry{BoardID: "board-id", UserID: "user-id", Action: "created", InsertAt: insertAt})
+		require.Equal(t, insertAt.UnixMilli(), change.UpdateAt)
+		require.Zero(t, change.DeleteAt)
+
+		cursor, err := DecodeChangesCursor(change.Cursor().Encode())
+		require.NoError(t, err)
+		require.Equal(t, ChangeTypeMember, cursor.Type)
+		require.Equal(t, "board-id", cursor.BoardID)
+		require.Equal(t, "user-id", cursor.ID)
+		require.True(t, insertAt.Equal(*cursor.InsertAt))
+	})
+
+	t.Run("invalid cursors are rejected", func(t *testing.T) {
+		for _, s := range []string{
+			"not base64!",
+			(&ChangesCursor{UpdateAt: 1000, Type: ChangeTypeBlock}).Encode(),
+			(&ChangesCursor{UpdateAt: 1000, Type: "view", ID: "view-id"}).Encode(),
+			(&ChangesCursor{UpdateAt: 1000, Type: ChangeTypeMember, ID: "user-id"}).Encode(),
+		} {
+			_, err := DecodeChangesCursor(s)
+			require.ErrorIs(t, err, ErrInvalidChangesCursor, s)
+		}
+	})
+}
+
+func TestNewMemberChange(t *testing.T) {
+	insertAt := time.Now()
+	change := NewMemberChange(&BoardMemberHistoryEntry{BoardID: "board-id", UserID: "user-id", Action: "deleted", InsertAt: insertAt})
+	require.Equal(t, ChangeTypeMember, change.Type)
+	require.Equal(t, change.UpdateAt, change.DeleteAt)
+}
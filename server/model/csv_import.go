@@ -0,0 +1,117 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// ImportCSVOptions provides options when importing the cards of a board
+// from a CSV file. The first line of the file holds the column names.
+type ImportCSVOptions struct {
+	TeamID     string
+	ModifiedBy string
+
+	// BoardID is the board to import the cards into. Empty means that a
+	// new board, named Title, is created.
+	BoardID string
+	Title   string
+
+	// TitleColumn is the column holding the titles of the cards. Empty
+	// means the column named "Title", or else the first column.
+	TitleColumn string
+
+	// Mapping maps column names to the ids of existing card properties.
+	// Columns not in the mapping are matched with the properties by name,
+	// and new properties are created for the columns that do not match.
+	Mapping map[string]string
+
+	// KeyColumn is the column identifying the cards. If not empty, the
+	// cards whose value for the column matches a row are updated instead
+	// of creating new ones.
+	KeyColumn string
+
+	// DryRun means that nothing is imported, the result only reports the
+	// proposed card properties and the errors of the rows.
+	DryRun bool
+}
+
+func (o ImportCSVOptions) IsValid() error {
+	if o.TeamID == "" {
+		return NewErrBadRequest("missing team id")
+	}
+	if o.ModifiedBy == "" {
+		return NewErrBadRequest("missing user id")
+	}
+	if o.BoardID == "" && o.Title == "" {
+		return NewErrBadRequest("missing board id or title")
+	}
+	return nil
+}
+
+// CSVImportColumn describes how a column of an imported CSV file is mapped.
+// swagger:model
+type CSVImportColumn struct {
+	// The name of the column
+	// required: true
+	Name string `json:"name"`
+
+	// The id of the card property the column is imported into. Empty for
+	// the title column and for ignored columns
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// True if the card property is created by the import
+	// required: false
+	New bool `json:"new,omitempty"`
+
+	// True if the column is not imported, as it maps to a computed
+	// property like the creation time
+	// required: false
+	Ignored bool `json:"ignored,omitempty"`
+}
+
+// CSVImportRowError is an error found in a row of an imported CSV file.
+// Rows with errors are not imported.
+// swagger:model
+type CSVImportRowError struct {
+	// The line of the row in the file, starting at 2 as the first line
+	// holds the column names
+	// required: true
+	Row int `json:"row"`
+
+	// The name of the column with the error
+	// required: false
+	Column string `json:"column,omitempty"`
+
+	// The error
+	// required: true
+	Message string `json:"message"`
+}
+
+// CSVImportResult is the result of importing, or of a dry run of the
+// import of, a CSV file.
+// swagger:model
+type CSVImportResult struct {
+	// The id of the board. Empty for dry runs into a new board
+	// required: false
+	BoardID string `json:"boardId,omitempty"`
+
+	// The card properties of the board after the import, including the
+	// new properties and options
+	// required: true
+	CardProperties []map[string]any `json:"cardProperties"`
+
+	// How each column of the file is mapped
+	// required: true
+	Columns []CSVImportColumn `json:"columns"`
+
+	// The number of cards created, or that would be created by a dry run
+	// required: true
+	Created int `json:"created"`
+
+	// The number of cards updated, or that would be updated by a dry run
+	// required: true
+	Updated int `json:"updated"`
+
+	// The errors of the rows that were not imported
+	// required: true
+	Errors []CSVImportRowError `json:"errors"`
+}
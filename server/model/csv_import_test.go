@@ -0,0 +1,19 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCSVOptionsIsValid(t *testing.T) {
+	require.NoError(t, ImportCSVOptions{TeamID: "team-id", ModifiedBy: "user-id", Title: "Imported"}.IsValid())
+	require.NoError(t, ImportCSVOptions{TeamID: "team-id", ModifiedBy: "user-id", BoardID: "board-id"}.IsValid())
+
+	require.True(t, IsErrBadRequest(ImportCSVOptions{ModifiedBy: "user-id", Title: "Imported"}.IsValid()))
+	require.True(t, IsErrBadRequest(ImportCSVOptions{TeamID: "team-id", Title: "Imported"}.IsValid()))
+	require.True(t, IsErrBadRequest(ImportCSVOptions{TeamID: "team-id", ModifiedBy: "user-id"}.IsValid()))
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+
+	// DefaultDigestHour is the local hour digests are sent at when the
+	// user didn't choose one.
+	DefaultDigestHour = 9
+)
+
+// DigestSettings is the schedule of the digest a user receives with the
+// changes of the blocks they subscribed to in digest mode.
+// swagger:model
+type DigestSettings struct {
+	// ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// How often the digest is sent, daily or weekly
+	// required: true
+	Frequency DigestFrequency `json:"frequency"`
+
+	// Hour of the day the digest is sent at, in the timezone of the user
+	// required: true
+	Hour int `json:"hour"`
+
+	// Day of the week weekly digests are sent on, 0 being Sunday
+	// required: true
+	Weekday int `json:"weekday"`
+
+	// Time the last digest was sent in miliseconds since the current epoch.
+	// The next digest contains the changes made after it
+	// required: false
+	LastSentAt int64 `json:"lastSentAt"`
+
+	// Updated time in miliseconds since the current epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// NewDigestSettings returns the default digest settings of a user, a
+// daily digest sent in the morning.
+func NewDigestSettings(userID string) *DigestSettings {
+	return &DigestSettings{
+		UserID:    userID,
+		Frequency: DigestFrequencyDaily,
+		Hour:      DefaultDigestHour,
+		Weekday:   int(time.Monday),
+	}
+}
+
+func (s *DigestSettings) IsValid() error {
+	if s.UserID == "" {
+		return NewErrBadRequest("digest settings require a user")
+	}
+	if s.Frequency != DigestFrequencyDaily && s.Frequency != DigestFrequencyWeekly {
+		return NewErrBadRequest(fmt.Sprintf("invalid digest frequency %q", s.Frequency))
+	}
+	if s.Hour < 0 || s.Hour > 23 {
+		return NewErrBadRequest(fmt.Sprintf("invalid digest hour %d", s.Hour))
+	}
+	if s.Weekday < int(time.Sunday) || s.Weekday > int(time.Saturday) {
+		return NewErrBadRequest(fmt.Sprintf("invalid digest weekday %d", s.Weekday))
+	}
+	return nil
+}
+
+// Period returns the time span a digest covers.
+func (s *DigestSettings) Period() time.Duration {
+	if s.Frequency == DigestFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// LastScheduledAt returns the latest time at or before `now` a digest is
+// scheduled for. The schedule follows the location of `now`, which should
+// be the timezone of the user.
+func (s *DigestSettings) LastScheduledAt(now time.Time) time.Time {
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, now.Location())
+	days := 1
+	if s.Frequency == DigestFrequencyWeekly {
+		days = 7
+		scheduled = scheduled.AddDate(0, 0, -((int(now.Weekday()) - s.Weekday + 7) % 7))
+	}
+	if scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -days)
+	}
+	return scheduled
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestSettingsIsValid(t *testing.T) {
+	require.NoError(t, NewDigestSettings("user-1").IsValid())
+
+	settings := NewDigestSettings("")
+	require.True(t, IsErrBadRequest(settings.IsValid()))
+
+	settings = NewDigestSettings("user-1")
+	settings.Frequency = "hourly"
+	require.True(t, IsErrBadRequest(settings.IsValid()))
+
+	settings = NewDigestSettings("user-1")
+	settings.Hour = 24
+	require.True(t, IsErrBadRequest(settings.IsValid()))
+
+	settings = NewDigestSettings("user-1")
+	settings.Weekday = 7
+	require.True(t, IsErrBadRequest(settings.IsValid()))
+}
+
+func TestDigestSettingsLastScheduledAt(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Wednesday
+	morning := time.Date(2024, time.March, 13, 7, 30, 0, 0, loc)
+	noon := time.Date(2024, time.March, 13, 12, 0, 0, 0, loc)
+
+	t.Run("daily", func(t *testing.T) {
+		settings := NewDigestSettings("user-1")
+		require.Equal(t, time.Date(2024, time.March, 12, 9, 0, 0, 0, loc), settings.LastScheduledAt(morning))
+		require.Equal(t, time.Date(2024, time.March, 13, 9, 0, 0, 0, loc), settings.LastScheduledAt(noon))
+		require.Equal(t, 24*time.Hour, settings.Period())
+	})
+
+	t.Run("weekly", func(t *testing.T) {
+		settings := NewDigestSettings("user-1")
+		settings.Frequency = DigestFrequencyWeekly
+		require.Equal(t, time.Date(2024, time.March, 11, 9, 0, 0, 0, loc), settings.LastScheduledAt(noon))
+
+		settings.Weekday = int(time.Wednesday)
+		require.Equal(t, time.Date(2024, time.March, 6, 9, 0, 0, 0, loc), settings.LastScheduledAt(morning))
+		require.Equal(t, time.Date(2024, time.March, 13, 9, 0, 0, 0, loc), settings.LastScheduledAt(noon))
+	})
+
+	t.Run("follows the timezone", func(t *testing.T) {
+		settings := NewDigestSettings("user-1")
+		// 13:30 UTC is 09:30 in New York after the daylight saving time change
+		now := time.Date(2024, time.March, 13, 13, 30, 0, 0, time.UTC)
+		require.Equal(t, time.Date(2024, time.March, 13, 9, 0, 0, 0, loc), settings.LastScheduledAt(now.In(loc)))
+		require.Equal(t, time.Date(2024, time.March, 13, 9, 0, 0, 0, time.UTC), settings.LastScheduledAt(now))
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxDueDateReminderOffset is the largest offset, in minutes, of a due
+	// date reminder before or after the due date (30 days).
+	MaxDueDateReminderOffset = 30 * 24 * 60
+)
+
+// DueDateReminderSettings designates the date properties of a board that
+// hold due dates, and when their reminders are sent.
+// swagger:model
+type DueDateReminderSettings struct {
+	// ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// Are the reminders enabled
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// IDs of the date properties that hold due dates
+	// required: true
+	PropertyIDs []string `json:"propertyIds"`
+
+	// Offsets of the reminders from the due date, in minutes. Negative
+	// offsets are sent before the due date, positive ones when overdue
+	// required: true
+	Offsets []int64 `json:"offsets"`
+
+	// ID of the user who last modified this
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// Updated time in miliseconds since the current epoch. Reminders
+	// that were due before are not sent
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid checks the settings against the board's property schema.
+func (s *DueDateReminderSettings) IsValid(schema PropSchema) error {
+	if s.Enabled && len(s.PropertyIDs) == 0 {
+		return NewErrBadRequest("due date reminders require at least one property")
+	}
+	if s.Enabled && len(s.Offsets) == 0 {
+		return NewErrBadRequest("due date reminders require at least one offset")
+	}
+
+	for _, propID := range s.PropertyIDs {
+		pd, ok := schema[propID]
+		if !ok {
+			return NewErrBadRequest(fmt.Sprintf("property %s does not exist", propID))
+		}
+		if pd.Type != "date" {
+			return NewErrBadRequest(fmt.Sprintf("property %s is not a date property", pd.Name))
+		}
+	}
+
+	seen := map[int64]bool{}
+	for _, offset := range s.Offsets {
+		if offset < -MaxDueDateReminderOffset || offset > MaxDueDateReminderOffset {
+			return NewErrBadRequest(fmt.Sprintf("invalid due date reminder offset %d", offset))
+		}
+		if seen[offset] {
+			return NewErrBadRequest(fmt.Sprintf("duplicated due date reminder offset %d", offset))
+		}
+		seen[offset] = true
+	}
+	return nil
+}
+
+// DueDateReminder identifies a reminder sent for the due date of a card.
+// Including the due date makes a new reminder be sent if it changes.
+type DueDateReminder struct {
+	CardID     string `json:"cardId"`
+	PropertyID string `json:"propertyId"`
+	Offset     int64  `json:"offset"`
+	DueAt      int64  `json:"dueAt"`
+	SentAt     int64  `json:"sentAt"`
+}
+
+// GetDueDate returns the due date of a date property value, in milliseconds
+// since the current epoch. For date ranges, it is the end of the range.
+func GetDueDate(value any) (int64, bool) {
+	s, ok := value.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return 0, false
+	}
+
+	var date map[string]int64
+	if err := json.Unmarshal([]byte(s), &date); err != nil {
+		return 0, false
+	}
+	if to, ok := date["to"]; ok && to != 0 {
+		return to, true
+	}
+	from, ok := date["from"]
+	return from, ok && from != 0
+}
+
+// GetCardAssignees returns the users set in the person and multi person
+// properties of a card.
+func GetCardAssignees(properties map[string]any, schema PropSchema) []string {
+	var userIDs []string
+	seen := map[string]bool{}
+	add := func(v any) {
+		if userID, ok := v.(string); ok && userID != "" && !seen[userID] {
+			seen[userID] = true
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	for propID, value := range properties {
+		pd, ok := schema[propID]
+		if !ok {
+			continue
+		}
+		switch pd.Type {
+		case "person":
+			add(value)
+		case "multiPerson":
+			if values, ok := value.([]any); ok {
+				for _, v := range values {
+					add(v)
+				}
+			}
+		}
+	}
+	return userIDs
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueDateReminderSettingsIsValid(t *testing.T) {
+	schema := PropSchema{
+		"due-id":    {ID: "due-id", Name: "Due", Type: "date"},
+		"status-id": {ID: "status-id", Name: "Status", Type: "select"},
+	}
+
+	testCases := []struct {
+		name     string
+		settings DueDateReminderSettings
+		valid    bool
+	}{
+		{"valid", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"due-id"}, Offsets: []int64{-1440, 0, 1440}}, true},
+		{"disabled without properties", DueDateReminderSettings{}, true},
+		{"enabled without properties", DueDateReminderSettings{Enabled: true, Offsets: []int64{0}}, false},
+		{"enabled without offsets", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"due-id"}}, false},
+		{"unknown property", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"unknown-id"}, Offsets: []int64{0}}, false},
+		{"not a date property", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"status-id"}, Offsets: []int64{0}}, false},
+		{"offset out of range", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"due-id"}, Offsets: []int64{MaxDueDateReminderOffset + 1}}, false},
+		{"duplicated offset", DueDateReminderSettings{Enabled: true, PropertyIDs: []string{"due-id"}, Offsets: []int64{60, 60}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.IsValid(schema)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.True(t, IsErrBadRequest(err))
+			}
+		})
+	}
+}
+
+func TestGetDueDate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected int64
+		ok       bool
+	}{
+		{"date", `{"from":1000}`, 1000, true},
+		{"date range", `{"from":1000,"to":2000}`, 2000, true},
+		{"empty", "", 0, false},
+		{"invalid json", "tomorrow", 0, false},
+		{"not a string", 1000, 0, false},
+		{"missing from", `{}`, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dueAt, ok := GetDueDate(tc.value)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, dueAt)
+		})
+	}
+}
+
+func TestGetCardAssignees(t *testing.T) {
+	schema := PropSchema{
+		"owner-id":     {ID: "owner-id", Type: "person"},
+		"reviewers-id": {ID: "reviewers-id", Type: "multiPerson"},
+		"text-id":      {ID: "text-id", Type: "text"},
+	}
+	properties := map[string]any{
+		"owner-id":     "user-1",
+		"reviewers-id": []any{"user-2", "user-1", ""},
+		"text-id":      "user-3",
+		"unknown-id":   "user-4",
+	}
+
+	require.ElementsMatch(t, []string{"user-1", "user-2"}, GetCardAssignees(properties, schema))
+}
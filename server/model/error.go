@@ -28,6 +28,8 @@ var (
 
 	ErrRequestEntityTooLarge = errors.New("request entity too large")
 
+	ErrTooManyRequests = errors.New("too many requests")
+
 	ErrInvalidBoardSearchField = errors.New("invalid board search field")
 )
 
@@ -163,6 +165,26 @@ func (ni *ErrNotImplemented) Error() string {
 	return ni.msg
 }
 
+// ErrConflict can be returned when a patch expects a version of an
+// entity that is not the current one anymore. Current holds the current
+// version of the entity.
+type ErrConflict struct {
+	entity  string
+	Current interface{}
+}
+
+// NewErrConflict creates a new ErrConflict instance.
+func NewErrConflict(entity string, current interface{}) *ErrConflict {
+	return &ErrConflict{
+		entity:  entity,
+		Current: current,
+	}
+}
+
+func (c *ErrConflict) Error() string {
+	return fmt.Sprintf("{%s} has been modified", c.entity)
+}
+
 // IsErrBadRequest returns true if `err` is or wraps one of:
 // - model.ErrBadRequest
 // - model.ErrViewsLimitReached
@@ -308,6 +330,13 @@ func IsErrRequestEntityTooLarge(err error) bool {
 	return errors.Is(err, ErrRequestEntityTooLarge)
 }
 
+// IsErrTooManyRequests returns true if `err` is or wraps one of:
+// - model.ErrTooManyRequests.
+func IsErrTooManyRequests(err error) bool {
+	// check if this is a model.ErrTooManyRequests
+	return errors.Is(err, ErrTooManyRequests)
+}
+
 // IsErrNotImplemented returns true if `err` is or wraps one of:
 // - model.ErrNotImplemented
 // - model.ErrInsufficientLicense.
@@ -325,3 +354,9 @@ func IsErrNotImplemented(err error) bool {
 	// check if this is a model.ErrInsufficientLicense
 	return errors.Is(err, ErrInsufficientLicense)
 }
+
+// IsErrConflict returns true if `err` is or wraps a model.ErrConflict.
+func IsErrConflict(err error) bool {
+	var c *ErrConflict
+	return errors.As(err, &c)
+}
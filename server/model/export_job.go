@@ -0,0 +1,129 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// ExportJobStatus is the state of an export job.
+type ExportJobStatus string
+
+const (
+	ExportJobPending ExportJobStatus = "pending"
+	ExportJobRunning ExportJobStatus = "running"
+	ExportJobSuccess ExportJobStatus = "success"
+	ExportJobFailed  ExportJobStatus = "failed"
+)
+
+// IsActive returns true if the job is still to be processed.
+func (s ExportJobStatus) IsActive() bool {
+	return s == ExportJobPending || s == ExportJobRunning
+}
+
+// ExportJob is an asynchronous export of the boards of a team to an
+// archive in the file store. The boards are written in chunks, so that a
+// job interrupted by a restart resumes after the last chunk written.
+// swagger:model
+type ExportJob struct {
+	// The id of the job
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the team whose boards are exported
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The state of the job
+	// required: true
+	Status ExportJobStatus `json:"status"`
+
+	// If not zero, only the boards changed since this time in miliseconds,
+	// or having content changed since then, are exported
+	// required: false
+	ChangedSince int64 `json:"changedSince"`
+
+	// The ids of the boards to export, set when the job starts
+	// required: false
+	BoardIDs []string `json:"-"`
+
+	// The number of boards to export
+	// required: true
+	BoardsTotal int `json:"boardsTotal"`
+
+	// The number of boards exported so far
+	// required: true
+	BoardsDone int `json:"boardsDone"`
+
+	// The number of chunks written so far
+	// required: false
+	Chunks int `json:"-"`
+
+	// The size in bytes of the archive, once the job succeeded
+	// required: false
+	Size int64 `json:"size"`
+
+	// The error of a failed job
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The id of the user who created the job
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The completion time in miliseconds since the current epoch
+	// required: false
+	CompleteAt int64 `json:"completeAt"`
+}
+
+// Progress returns the percentage of the boards exported so far.
+func (j *ExportJob) Progress() int {
+	switch {
+	case j.Status == ExportJobSuccess:
+		return 100
+	case j.BoardsTotal == 0:
+		return 0
+	default:
+		return j.BoardsDone * 100 / j.BoardsTotal
+	}
+}
+
+// MarshalJSON adds the progress to the JSON of the job.
+func (j *ExportJob) MarshalJSON() ([]byte, error) {
+	type exportJob ExportJob
+	return json.Marshal(struct {
+		*exportJob
+		Progress int `json:"progress"`
+	}{(*exportJob)(j), j.Progress()})
+}
+
+// ExportJobCreate is the request to create an export job.
+// swagger:model
+type ExportJobCreate struct {
+	// If not zero, only the boards changed since this time in miliseconds
+	// are exported
+	// required: false
+	ChangedSince int64 `json:"changedSince"`
+}
+
+func (c *ExportJobCreate) IsValid() error {
+	if c.ChangedSince < 0 {
+		return NewErrBadRequest(fmt.Sprintf("invalid changedSince %d", c.ChangedSince))
+	}
+	if c.ChangedSince > utils.GetMillis() {
+		return NewErrBadRequest("changedSince cannot be in the future")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+func TestExportJobProgress(t *testing.T) {
+	assert.Equal(t, 0, (&ExportJob{Status: ExportJobPending}).Progress())
+	assert.Equal(t, 40, (&ExportJob{Status: ExportJobRunning, BoardsTotal: 5, BoardsDone: 2}).Progress())
+	assert.Equal(t, 100, (&ExportJob{Status: ExportJobSuccess}).Progress())
+}
+
+func TestExportJobJSON(t *testing.T) {
+	job := &ExportJob{
+		ID:          "job-id",
+		Status:      ExportJobRunning,
+		BoardIDs:    []string{"board-id-1", "board-id-2"},
+		BoardsTotal: 2,
+		BoardsDone:  1,
+		Chunks:      1,
+	}
+
+	data, err := json.Marshal(job)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "job-id", fields["id"])
+	assert.EqualValues(t, 50, fields["progress"])
+	assert.NotContains(t, fields, "BoardIDs")
+	assert.NotContains(t, fields, "Chunks")
+	assert.NotContains(t, fields, "error")
+}
+
+func TestExportJobCreateIsValid(t *testing.T) {
+	assert.NoError(t, (&ExportJobCreate{}).IsValid())
+	assert.NoError(t, (&ExportJobCreate{ChangedSince: utils.GetMillis() - 1000}).IsValid())
+
+	err := (&ExportJobCreate{ChangedSince: -1}).IsValid()
+	assert.True(t, IsErrBadRequest(err))
+
+	err = (&ExportJobCreate{ChangedSince: utils.GetMillis() + 60000}).IsValid()
+	assert.True(t, IsErrBadRequest(err))
+}
@@ -52,6 +52,24 @@ type ImportArchiveOptions struct {
 	BoardValidator BoardValidator
 }
 
+// ImportFormat is the format of a board exported from another tool.
+type ImportFormat string
+
+const (
+	ImportFormatTrello ImportFormat = "trello" // Trello board JSON
+	ImportFormatJira   ImportFormat = "jira"   // Jira issues JSON or CSV
+	ImportFormatAsana  ImportFormat = "asana"  // Asana project JSON
+)
+
+func (f ImportFormat) IsValid() error {
+	switch f {
+	case ImportFormatTrello, ImportFormatJira, ImportFormatAsana:
+		return nil
+	default:
+		return NewErrBadRequest(fmt.Sprintf("unsupported import format %q", f))
+	}
+}
+
 // ErrUnsupportedArchiveVersion is an error returned when trying to import an
 // archive with a version that this server does not support.
 type ErrUnsupportedArchiveVersion struct {
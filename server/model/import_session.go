@@ -0,0 +1,192 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportSessionStatus is the state of an import session.
+type ImportSessionStatus string
+
+const (
+	ImportSessionPending   ImportSessionStatus = "pending"
+	ImportSessionImporting ImportSessionStatus = "importing"
+	ImportSessionCompleted ImportSessionStatus = "completed"
+)
+
+// ImportBoardAction is what the confirmation of an import session does
+// with a board of the archive.
+type ImportBoardAction string
+
+const (
+	ImportBoardCreate ImportBoardAction = "create"
+	ImportBoardSkip   ImportBoardAction = "skip"
+	ImportBoardRename ImportBoardAction = "rename"
+)
+
+// ImportSession is an archive uploaded for import, with a preview of its
+// content. Nothing is imported until the session is confirmed.
+// swagger:model
+type ImportSession struct {
+	// The id of the session
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the team the boards are imported to
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The state of the session
+	// required: true
+	Status ImportSessionStatus `json:"status"`
+
+	// The boards of the archive
+	// required: true
+	Boards []*ImportSessionBoard `json:"boards"`
+
+	// The ids of the users referenced by the boards of the archive
+	// required: true
+	Users []string `json:"users"`
+
+	// The ids of the referenced users that are not users of the system
+	// required: true
+	UnresolvedUsers []string `json:"unresolvedUsers"`
+
+	// The errors of the archive that don't belong to a board
+	// required: false
+	Errors []string `json:"errors,omitempty"`
+
+	// The id of the user who uploaded the archive
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// ImportSessionBoard describes a board of an import session archive and,
+// once the session is confirmed, the result of its import.
+// swagger:model
+type ImportSessionBoard struct {
+	// The id of the board in the archive
+	// required: true
+	ArchiveID string `json:"archiveId"`
+
+	// The title of the board
+	// required: true
+	Title string `json:"title"`
+
+	// The type of the board
+	// required: true
+	Type BoardType `json:"type"`
+
+	// Marks the board as a template
+	// required: true
+	IsTemplate bool `json:"isTemplate"`
+
+	// The number of cards of the board
+	// required: true
+	Cards int `json:"cards"`
+
+	// The number of blocks of the board, cards included
+	// required: true
+	Blocks int `json:"blocks"`
+
+	// The number of files of the board
+	// required: true
+	Files int `json:"files"`
+
+	// The total size in bytes of the files of the board
+	// required: true
+	FilesSize int64 `json:"filesSize"`
+
+	// The id of a board of the team with the same id or title
+	// required: false
+	ExistingBoardID string `json:"existingBoardId,omitempty"`
+
+	// The errors preventing the import of the board
+	// required: false
+	Errors []string `json:"errors,omitempty"`
+
+	// What the confirmation did with the board
+	// required: false
+	Action ImportBoardAction `json:"action,omitempty"`
+
+	// The id of the imported board
+	// required: false
+	BoardID string `json:"boardId,omitempty"`
+
+	// The error of a failed import
+	// required: false
+	ImportError string `json:"importError,omitempty"`
+}
+
+// ImportBoardResolution is what to do with a board of the archive.
+// swagger:model
+type ImportBoardResolution struct {
+	// The action for the board, create by default
+	// required: true
+	Action ImportBoardAction `json:"action"`
+
+	// The title of the board for the rename action
+	// required: false
+	Title string `json:"title,omitempty"`
+}
+
+// ImportSessionConfirm is the request to run the import of a session.
+// swagger:model
+type ImportSessionConfirm struct {
+	// Maps the ids of users of the archive to the ids of users of the system
+	// required: false
+	UserMap map[string]string `json:"userMap"`
+
+	// The resolutions of the boards, by archive board id. The boards without
+	// resolution are created
+	// required: false
+	Boards map[string]ImportBoardResolution `json:"boards"`
+}
+
+func (c *ImportSessionConfirm) IsValid() error {
+	for fromID, toID := range c.UserMap {
+		if fromID == "" || toID == "" {
+			return NewErrBadRequest("invalid user map entry")
+		}
+	}
+
+	for archiveID, resolution := range c.Boards {
+		switch resolution.Action {
+		case ImportBoardCreate, ImportBoardSkip:
+		case ImportBoardRename:
+			if strings.TrimSpace(resolution.Title) == "" {
+				return NewErrBadRequest(fmt.Sprintf("missing title to rename board %s", archiveID))
+			}
+		default:
+			return NewErrBadRequest(fmt.Sprintf("invalid action %q for board %s", resolution.Action, archiveID))
+		}
+	}
+	return nil
+}
+
+// Resolution returns the resolution of a board of the archive.
+func (c *ImportSessionConfirm) Resolution(archiveID string) ImportBoardResolution {
+	if resolution, ok := c.Boards[archiveID]; ok {
+		return resolution
+	}
+	return ImportBoardResolution{Action: ImportBoardCreate}
+}
+
+// MapUser returns the id of the system user an archive user is mapped to.
+func (c *ImportSessionConfirm) MapUser(userID string) string {
+	if mappedID, ok := c.UserMap[userID]; ok {
+		return mappedID
+	}
+	return userID
+}
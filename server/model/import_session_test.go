@@ -0,0 +1,45 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportSessionConfirmIsValid(t *testing.T) {
+	valid := &ImportSessionConfirm{
+		UserMap: map[string]string{"old-user": "user-id"},
+		Boards: map[string]ImportBoardResolution{
+			"board-1": {Action: ImportBoardSkip},
+			"board-2": {Action: ImportBoardRename, Title: "Renamed"},
+			"board-3": {Action: ImportBoardCreate},
+		},
+	}
+	assert.NoError(t, valid.IsValid())
+	assert.NoError(t, (&ImportSessionConfirm{}).IsValid())
+
+	invalid := []*ImportSessionConfirm{
+		{UserMap: map[string]string{"old-user": ""}},
+		{Boards: map[string]ImportBoardResolution{"board-1": {Action: ImportBoardRename, Title: " "}}},
+		{Boards: map[string]ImportBoardResolution{"board-1": {Action: "merge"}}},
+		{Boards: map[string]ImportBoardResolution{"board-1": {}}},
+	}
+	for _, confirm := range invalid {
+		assert.True(t, IsErrBadRequest(confirm.IsValid()))
+	}
+}
+
+func TestImportSessionConfirmResolution(t *testing.T) {
+	confirm := &ImportSessionConfirm{
+		UserMap: map[string]string{"old-user": "user-id"},
+		Boards:  map[string]ImportBoardResolution{"board-1": {Action: ImportBoardSkip}},
+	}
+
+	assert.Equal(t, ImportBoardSkip, confirm.Resolution("board-1").Action)
+	assert.Equal(t, ImportBoardCreate, confirm.Resolution("board-2").Action)
+	assert.Equal(t, "user-id", confirm.MapUser("old-user"))
+	assert.Equal(t, "other-user", confirm.MapUser("other-user"))
+}
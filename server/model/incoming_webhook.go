@@ -0,0 +1,169 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+)
+
+// IncomingWebhook holds the token that external systems use to create and
+// update the cards of a board without a session.
+// swagger:model
+type IncomingWebhook struct {
+	// ID of the board
+	// required: true
+	ID string `json:"id"`
+
+	// Is the incoming webhook enabled
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Access token
+	// required: true
+	Token string `json:"token"`
+
+	// ID of the user who last modified this. Cards are created and
+	// updated on behalf of this user
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// Updated time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"update_at,omitempty"`
+}
+
+// IncomingWebhookCard is the payload of an incoming webhook request. If
+// CardID is set the card is patched, otherwise a new card is created.
+// swagger:model
+type IncomingWebhookCard struct {
+	// The id of the card to update
+	// required: false
+	CardID string `json:"cardId"`
+
+	// The display title
+	// required: false
+	Title *string `json:"title"`
+
+	// The icon of the card
+	// required: false
+	Icon *string `json:"icon"`
+
+	// A map of property names (or ids) to property values. Select and
+	// multi select values can be option values instead of option ids
+	// required: false
+	Properties map[string]any `json:"properties"`
+}
+
+// ResolveProperties maps the payload properties to the property ids and
+// option ids of the board's card properties.
+func (c *IncomingWebhookCard) ResolveProperties(board *Board) (map[string]any, error) {
+	properties := make(map[string]any, len(c.Properties))
+	if len(c.Properties) == 0 {
+		return properties, nil
+	}
+
+	schema, err := ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range c.Properties {
+		pd, ok := schema[key]
+		if !ok {
+			if pd, ok = schema.GetByName(key); !ok {
+				return nil, NewErrBadRequest(fmt.Sprintf("unknown property %q", key))
+			}
+		}
+
+		resolved, err := resolveIncomingPropertyValue(pd, value)
+		if err != nil {
+			return nil, err
+		}
+		properties[pd.ID] = resolved
+	}
+
+	return properties, nil
+}
+
+func resolveIncomingPropertyValue(pd PropDef, value any) (any, error) {
+	if !IsValidCardPropertyValue(value) {
+		return nil, NewErrBadRequest(fmt.Sprintf("value of property %q must be a string or an array of strings", pd.Name))
+	}
+
+	resolveOption := func(v string) (string, error) {
+		optionID, ok := pd.GetOptionID(v)
+		if !ok {
+			return "", NewErrBadRequest(fmt.Sprintf("unknown option %q for property %q", v, pd.Name))
+		}
+		return optionID, nil
+	}
+
+	switch pd.Type {
+	case "select":
+		s, ok := value.(string)
+		if !ok || s == "" {
+			return value, nil
+		}
+		return resolveOption(s)
+
+	case "multiSelect":
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		case []any:
+			for _, item := range v {
+				values = append(values, item.(string))
+			}
+		}
+
+		optionIDs := make([]any, 0, len(values))
+		for _, v := range values {
+			optionID, err := resolveOption(v)
+			if err != nil {
+				return nil, err
+			}
+			optionIDs = append(optionIDs, optionID)
+		}
+		return optionIDs, nil
+	}
+
+	return value, nil
+}
+
+// ToCard returns the card to create for the payload.
+func (c *IncomingWebhookCard) ToCard(board *Board) (*Card, error) {
+	properties, err := c.ResolveProperties(board)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &Card{
+		ContentOrder: []string{},
+		Properties:   properties,
+	}
+	if c.Title != nil {
+		card.Title = *c.Title
+	}
+	if c.Icon != nil {
+		card.Icon = *c.Icon
+	}
+	return card, nil
+}
+
+// ToCardPatch returns the patch to apply to the card for the payload.
+func (c *IncomingWebhookCard) ToCardPatch(board *Board) (*CardPatch, error) {
+	properties, err := c.ResolveProperties(board)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CardPatch{
+		Title:             c.Title,
+		Icon:              c.Icon,
+		UpdatedProperties: properties,
+	}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func incomingWebhookTestBoard() *Board {
+	return &Board{
+		ID: "board-id",
+		CardProperties: []map[string]any{
+			{
+				"id":   "status-id",
+				"name": "Status",
+				"type": "select",
+				"options": []any{
+					map[string]any{"id": "todo-id", "value": "To Do"},
+					map[string]any{"id": "done-id", "value": "Done"},
+				},
+			},
+			{
+				"id":   "labels-id",
+				"name": "Labels",
+				"type": "multiSelect",
+				"options": []any{
+					map[string]any{"id": "bug-id", "value": "Bug"},
+					map[string]any{"id": "ci-id", "value": "CI"},
+				},
+			},
+			{
+				"id":   "notes-id",
+				"name": "Notes",
+				"type": "text",
+			},
+		},
+	}
+}
+
+func TestIncomingWebhookCardResolveProperties(t *testing.T) {
+	board := incomingWebhookTestBoard()
+
+	t.Run("names and option values", func(t *testing.T) {
+		payload := &IncomingWebhookCard{
+			Properties: map[string]any{
+				"status": "done",
+				"Labels": []any{"Bug", "ci-id"},
+				"Notes":  "build #42 failed",
+			},
+		}
+
+		properties, err := payload.ResolveProperties(board)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"status-id": "done-id",
+			"labels-id": []any{"bug-id", "ci-id"},
+			"notes-id":  "build #42 failed",
+		}, properties)
+	})
+
+	t.Run("ids", func(t *testing.T) {
+		payload := &IncomingWebhookCard{
+			Properties: map[string]any{"status-id": "todo-id", "labels-id": "CI"},
+		}
+
+		properties, err := payload.ResolveProperties(board)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"status-id": "todo-id", "labels-id": []any{"ci-id"}}, properties)
+	})
+
+	t.Run("clear a select", func(t *testing.T) {
+		payload := &IncomingWebhookCard{Properties: map[string]any{"Status": ""}}
+
+		properties, err := payload.ResolveProperties(board)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"status-id": ""}, properties)
+	})
+
+	testCases := []struct {
+		name       string
+		properties map[string]any
+	}{
+		{"unknown property", map[string]any{"Priority": "High"}},
+		{"unknown option", map[string]any{"Status": "Blocked"}},
+		{"unknown multi select option", map[string]any{"Labels": []any{"Bug", "Feature"}}},
+		{"invalid value", map[string]any{"Notes": 42}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := &IncomingWebhookCard{Properties: tc.properties}
+			_, err := payload.ResolveProperties(board)
+			require.Error(t, err)
+			require.True(t, IsErrBadRequest(err))
+		})
+	}
+}
+
+func TestIncomingWebhookCardToCardPatch(t *testing.T) {
+	title := "Deploy failed"
+	payload := &IncomingWebhookCard{
+		CardID:     "card-id",
+		Title:      &title,
+		Properties: map[string]any{"Status": "To Do"},
+	}
+
+	patch, err := payload.ToCardPatch(incomingWebhookTestBoard())
+	require.NoError(t, err)
+	require.Equal(t, &title, patch.Title)
+	require.Nil(t, patch.Icon)
+	require.Equal(t, map[string]any{"status-id": "todo-id"}, patch.UpdatedProperties)
+
+	card, err := payload.ToCard(incomingWebhookTestBoard())
+	require.NoError(t, err)
+	require.Equal(t, title, card.Title)
+	require.Equal(t, map[string]any{"status-id": "todo-id"}, card.Properties)
+}
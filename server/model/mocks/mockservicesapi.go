@@ -214,6 +214,36 @@ func (mr *MockServicesAPIMockRecorder) GetFileInfo(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockServicesAPI)(nil).GetFileInfo), arg0)
 }
 
+// GetGroupByName mocks base method.
+func (m *MockServicesAPI) GetGroupByName(arg0 string) (*model.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupByName", arg0)
+	ret0, _ := ret[0].(*model.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupByName indicates an expected call of GetGroupByName.
+func (mr *MockServicesAPIMockRecorder) GetGroupByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByName", reflect.TypeOf((*MockServicesAPI)(nil).GetGroupByName), arg0)
+}
+
+// GetGroupMemberUsers mocks base method.
+func (m *MockServicesAPI) GetGroupMemberUsers(arg0 string, arg1, arg2 int) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupMemberUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupMemberUsers indicates an expected call of GetGroupMemberUsers.
+func (mr *MockServicesAPIMockRecorder) GetGroupMemberUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMemberUsers", reflect.TypeOf((*MockServicesAPI)(nil).GetGroupMemberUsers), arg0, arg1, arg2)
+}
+
 // GetLicense mocks base method.
 func (m *MockServicesAPI) GetLicense() *model.License {
 	m.ctrl.T.Helper()
@@ -332,6 +362,21 @@ func (mr *MockServicesAPIMockRecorder) GetUserByUsername(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockServicesAPI)(nil).GetUserByUsername), arg0)
 }
 
+// GetUserStatusesByIds mocks base method.
+func (m *MockServicesAPI) GetUserStatusesByIds(arg0 []string) ([]*model.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStatusesByIds", arg0)
+	ret0, _ := ret[0].([]*model.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStatusesByIds indicates an expected call of GetUserStatusesByIds.
+func (mr *MockServicesAPIMockRecorder) GetUserStatusesByIds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStatusesByIds", reflect.TypeOf((*MockServicesAPI)(nil).GetUserStatusesByIds), arg0)
+}
+
 // GetUsersFromProfiles mocks base method.
 func (m *MockServicesAPI) GetUsersFromProfiles(arg0 *model.UserGetOptions) ([]*model.User, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,65 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+)
+
+type PresenceActivity string
+
+const (
+	PresenceActivityViewing PresenceActivity = "viewing"
+	PresenceActivityEditing PresenceActivity = "editing"
+)
+
+func (pa PresenceActivity) IsValid() bool {
+	switch pa {
+	case PresenceActivityViewing, PresenceActivityEditing:
+		return true
+	}
+	return false
+}
+
+// Presence is a user currently viewing or editing a board or one of its
+// cards. Presence is ephemeral and is not stored.
+// swagger:model
+type Presence struct {
+	// ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// ID of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// ID of the card the user is on, or empty if the user is on the board
+	// required: false
+	CardID string `json:"cardId,omitempty"`
+
+	// What the user is doing, viewing or editing
+	// required: true
+	Activity PresenceActivity `json:"activity"`
+
+	// Time the presence was last announced in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+func (p *Presence) IsValid() error {
+	if p.UserID == "" {
+		return NewErrBadRequest("presence requires a user")
+	}
+	if p.BoardID == "" {
+		return NewErrBadRequest("presence requires a board")
+	}
+	if !p.Activity.IsValid() {
+		return NewErrBadRequest(fmt.Sprintf("invalid presence activity %q", p.Activity))
+	}
+	return nil
+}
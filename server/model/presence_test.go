@@ -0,0 +1,30 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceIsValid(t *testing.T) {
+	valid := func() *Presence {
+		return &Presence{
+			UserID:   "user-1",
+			TeamID:   "team-1",
+			BoardID:  "board-1",
+			Activity: PresenceActivityViewing,
+		}
+	}
+	require.NoError(t, valid().IsValid())
+
+	presence := valid()
+	presence.BoardID = ""
+	require.True(t, IsErrBadRequest(presence.IsValid()))
+
+	presence = valid()
+	presence.Activity = "sleeping"
+	require.True(t, IsErrBadRequest(presence.IsValid()))
+}
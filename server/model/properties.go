@@ -56,6 +56,12 @@ type PropDef struct {
 	Name    string                   `json:"name"`
 	Type    string                   `json:"type"`
 	Options map[string]PropDefOption `json:"options"`
+
+	// RelationBoardID is the board of the related cards of a relation property
+	RelationBoardID string `json:"relationBoardId,omitempty"`
+
+	// Rollup describes how the value of a rollup property is computed
+	Rollup *RollupDef `json:"rollup,omitempty"`
 }
 
 // GetValue resolves the value of a property if the passed value is an ID for an option,
@@ -157,6 +163,10 @@ func (pd PropDef) GetValue(v interface{}, resolver PropValueResolver) (string, e
 			sb.WriteString(strings.ToUpper(opt.Value))
 		}
 		return sb.String(), nil
+
+	case PropTypeRelation:
+		// v is a slice of card IDs
+		return strings.Join(RelationCardIDs(v), ", "), nil
 	}
 	return fmt.Sprintf("%v", v), nil
 }
@@ -202,6 +212,15 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 			Name:    getMapString("name", prop),
 			Type:    getMapString("type", prop),
 			Options: make(map[string]PropDefOption),
+
+			RelationBoardID: getMapString("relationBoardId", prop),
+		}
+		if rollup, ok := prop["rollup"].(map[string]interface{}); ok {
+			pd.Rollup = &RollupDef{
+				RelationPropertyID: getMapString("relationPropertyId", rollup),
+				TargetPropertyID:   getMapString("targetPropertyId", rollup),
+				Function:           RollupFunction(getMapString("function", rollup)),
+			}
 		}
 		optsIface, ok := prop["options"]
 		if ok {
@@ -228,6 +247,31 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 	return schema, nil
 }
 
+// GetByName returns the property definition with the given name. Names are
+// matched case insensitively.
+func (ps PropSchema) GetByName(name string) (PropDef, bool) {
+	for _, pd := range ps {
+		if strings.EqualFold(pd.Name, name) {
+			return pd, true
+		}
+	}
+	return PropDef{}, false
+}
+
+// GetOptionID returns the id of the option whose value or id matches `v`.
+// Values are matched case insensitively.
+func (pd PropDef) GetOptionID(v string) (string, bool) {
+	if _, ok := pd.Options[v]; ok {
+		return v, true
+	}
+	for _, opt := range pd.Options {
+		if strings.EqualFold(opt.Value, v) {
+			return opt.ID, true
+		}
+	}
+	return "", false
+}
+
 func getMapString(key string, m map[string]interface{}) string {
 	iface, ok := m[key]
 	if !ok {
@@ -0,0 +1,320 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PropTypeRelation is the type of the properties whose values are the
+	// ids of cards of another board.
+	PropTypeRelation = "relation"
+
+	// PropTypeRollup is the type of the properties that aggregate a property
+	// of the cards of a relation. Their values are computed by the server
+	// and never stored.
+	PropTypeRollup = "rollup"
+)
+
+// RollupFunction is the aggregation applied by a rollup property.
+type RollupFunction string
+
+const (
+	RollupCount          RollupFunction = "count"
+	RollupSum            RollupFunction = "sum"
+	RollupMin            RollupFunction = "min"
+	RollupMax            RollupFunction = "max"
+	RollupPercentChecked RollupFunction = "percent_checked"
+)
+
+// RollupDef describes how a rollup property is computed.
+type RollupDef struct {
+	// The id of the relation property of the card
+	RelationPropertyID string `json:"relationPropertyId"`
+
+	// The id of the aggregated property of the related cards, unused by count
+	TargetPropertyID string `json:"targetPropertyId"`
+
+	// The aggregation applied to the values of the related cards
+	Function RollupFunction `json:"function"`
+}
+
+func (r RollupDef) IsValid() error {
+	if r.RelationPropertyID == "" {
+		return NewErrInvalidCardProperty("rollup is missing a relation property")
+	}
+
+	switch r.Function {
+	case RollupCount:
+		return nil
+	case RollupSum, RollupMin, RollupMax, RollupPercentChecked:
+		if r.TargetPropertyID == "" {
+			return NewErrInvalidCardProperty(fmt.Sprintf("rollup function %q needs a target property", r.Function))
+		}
+		return nil
+	default:
+		return NewErrInvalidCardProperty(fmt.Sprintf("invalid rollup function %q", r.Function))
+	}
+}
+
+// RollupResolver allows ResolveRollups to fetch the related cards and their
+// boards.
+type RollupResolver interface {
+	GetBlocksByIDs(ids []string) ([]*Block, error)
+	GetBoard(boardID string) (*Board, error)
+}
+
+// RelationBoardIDs returns the boards of the relation properties of a list
+// of card property templates.
+func RelationBoardIDs(cardProperties []map[string]any) []string {
+	boardIDs := []string{}
+	for _, prop := range cardProperties {
+		if getMapString("type", prop) != PropTypeRelation {
+			continue
+		}
+		if boardID := getMapString("relationBoardId", prop); boardID != "" {
+			boardIDs = append(boardIDs, boardID)
+		}
+	}
+	return boardIDs
+}
+
+// RelationCardIDs returns the card ids of the value of a relation property.
+func RelationCardIDs(value any) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			if id, ok := item.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	default:
+		return nil
+	}
+}
+
+// HasRollups returns true if the schema contains rollup properties.
+func (ps PropSchema) HasRollups() bool {
+	for _, pd := range ps {
+		if pd.Type == PropTypeRollup {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCardPropertyValues checks the values of the properties of a card
+// against the schema of its board, on top of the checks done for all values.
+// The values of relation properties must be card ids. Values of rollup
+// properties are accepted, but they are computed and never stored.
+func (ps PropSchema) ValidateCardPropertyValues(values map[string]any) error {
+	if err := ValidateCardPropertyValues(values); err != nil {
+		return err
+	}
+
+	for propertyID, value := range values {
+		pd, ok := ps[propertyID]
+		if !ok || pd.Type != PropTypeRelation {
+			continue
+		}
+		for _, cardID := range RelationCardIDs(value) {
+			if err := IsValidId(cardID); err != nil {
+				return NewErrInvalidCardProperty(fmt.Sprintf("value of relation property %q contains an invalid card id", propertyID))
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveComputedProperties removes the values of the rollup properties.
+func (ps PropSchema) RemoveComputedProperties(values map[string]any) {
+	for propertyID := range values {
+		if pd, ok := ps[propertyID]; ok && pd.Type == PropTypeRollup {
+			delete(values, propertyID)
+		}
+	}
+}
+
+// ResolveRollups computes the values of the rollup properties of the cards
+// of a board and sets them in their properties. Related cards must be on the
+// board of the relation property, in the same team, and canView must allow
+// the related board for the values to be computed, as they disclose its
+// content to whoever reads them. Rollups of other boards are left empty.
+func ResolveRollups(board *Board, cards []*Block, resolver RollupResolver, canView func(relatedBoard *Board) bool) error {
+	schema, err := ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+	if !schema.HasRollups() {
+		return nil
+	}
+
+	// fetch all the related cards at once
+	cardIDs := []string{}
+	for _, card := range cards {
+		properties, _ := card.Fields[BlockFieldProperties].(map[string]any)
+		for propertyID, value := range properties {
+			if pd, ok := schema[propertyID]; ok && pd.Type == PropTypeRelation {
+				cardIDs = append(cardIDs, RelationCardIDs(value)...)
+			}
+		}
+	}
+
+	related := map[string]*Block{}
+	if len(cardIDs) > 0 {
+		blocks, err := resolver.GetBlocksByIDs(cardIDs)
+		if err != nil {
+			return err
+		}
+		for _, block := range blocks {
+			if block.Type == TypeCard {
+				related[block.ID] = block
+			}
+		}
+	}
+
+	// the schemas of the related boards, nil if the board cannot be used
+	relatedSchemas := map[string]PropSchema{}
+	relatedSchema := func(boardID string) (PropSchema, error) {
+		if s, ok := relatedSchemas[boardID]; ok {
+			return s, nil
+		}
+		relatedBoard, err := resolver.GetBoard(boardID)
+		if IsErrNotFound(err) {
+			relatedSchemas[boardID] = nil
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var s PropSchema
+		if relatedBoard.TeamID == board.TeamID && (relatedBoard.ID == board.ID || canView(relatedBoard)) {
+			if s, err = ParsePropertySchema(relatedBoard); err != nil {
+				return nil, err
+			}
+		}
+		relatedSchemas[boardID] = s
+		return s, nil
+	}
+
+	for _, card := range cards {
+		if card.Type != TypeCard {
+			continue
+		}
+		properties, _ := card.Fields[BlockFieldProperties].(map[string]any)
+		if properties == nil {
+			properties = map[string]any{}
+			if card.Fields == nil {
+				card.Fields = map[string]any{}
+			}
+			card.Fields[BlockFieldProperties] = properties
+		}
+
+		for _, pd := range schema {
+			if pd.Type != PropTypeRollup || pd.Rollup == nil {
+				continue
+			}
+			relation, ok := schema[pd.Rollup.RelationPropertyID]
+			if !ok || relation.Type != PropTypeRelation || relation.RelationBoardID == "" {
+				delete(properties, pd.ID)
+				continue
+			}
+
+			s, err := relatedSchema(relation.RelationBoardID)
+			if err != nil {
+				return err
+			}
+			if s == nil {
+				delete(properties, pd.ID)
+				continue
+			}
+
+			relatedCards := []*Block{}
+			for _, cardID := range RelationCardIDs(properties[relation.ID]) {
+				if relatedCard, ok := related[cardID]; ok && relatedCard.BoardID == relation.RelationBoardID {
+					relatedCards = append(relatedCards, relatedCard)
+				}
+			}
+			properties[pd.ID] = pd.Rollup.Compute(s[pd.Rollup.TargetPropertyID], relatedCards)
+		}
+	}
+	return nil
+}
+
+// Compute aggregates the values of the target property of the related cards.
+// Values that are not numbers are ignored by sum, min and max, which return
+// an empty string if there is nothing to aggregate.
+func (r RollupDef) Compute(target PropDef, cards []*Block) string {
+	if r.Function == RollupCount {
+		return strconv.Itoa(len(cards))
+	}
+
+	var values []any
+	for _, card := range cards {
+		properties, _ := card.Fields[BlockFieldProperties].(map[string]any)
+		values = append(values, properties[target.ID])
+	}
+
+	if r.Function == RollupPercentChecked {
+		if len(values) == 0 {
+			return ""
+		}
+		checked := 0
+		for _, value := range values {
+			if value == "true" {
+				checked++
+			}
+		}
+		return formatRollupNumber(math.Round(float64(checked)*100/float64(len(values)))) + "%"
+	}
+
+	var numbers []float64
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+
+	switch r.Function {
+	case RollupSum:
+		sum := 0.0
+		for _, n := range numbers {
+			sum += n
+		}
+		return formatRollupNumber(sum)
+	case RollupMin, RollupMax:
+		if len(numbers) == 0 {
+			return ""
+		}
+		result := numbers[0]
+		for _, n := range numbers[1:] {
+			if (r.Function == RollupMin && n < result) || (r.Function == RollupMax && n > result) {
+				result = n
+			}
+		}
+		return formatRollupNumber(result)
+	}
+	return ""
+}
+
+func formatRollupNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+type testRollupResolver struct {
+	blocks map[string]*Block
+	boards map[string]*Board
+}
+
+func (r *testRollupResolver) GetBlocksByIDs(ids []string) ([]*Block, error) {
+	blocks := []*Block{}
+	for _, id := range ids {
+		if block, ok := r.blocks[id]; ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (r *testRollupResolver) GetBoard(boardID string) (*Board, error) {
+	board, ok := r.boards[boardID]
+	if !ok {
+		return nil, NewErrNotFound(boardID)
+	}
+	return board, nil
+}
+
+func TestRollupCompute(t *testing.T) {
+	card := func(value any) *Block {
+		return &Block{Type: TypeCard, Fields: map[string]any{"properties": map[string]any{"target-id": value}}}
+	}
+	cards := []*Block{card("3"), card("1.5"), card("not a number"), card(nil)}
+	target := PropDef{ID: "target-id", Type: "number"}
+
+	require.Equal(t, "4", RollupDef{Function: RollupCount}.Compute(target, cards))
+	require.Equal(t, "4.5", RollupDef{Function: RollupSum}.Compute(target, cards))
+	require.Equal(t, "1.5", RollupDef{Function: RollupMin}.Compute(target, cards))
+	require.Equal(t, "3", RollupDef{Function: RollupMax}.Compute(target, cards))
+	require.Equal(t, "", RollupDef{Function: RollupMax}.Compute(target, nil))
+	require.Equal(t, "0", RollupDef{Function: RollupSum}.Compute(target, nil))
+
+	checkboxes := []*Block{card("true"), card("false"), card(nil)}
+	require.Equal(t, "33%", RollupDef{Function: RollupPercentChecked}.Compute(target, checkboxes))
+	require.Equal(t, "", RollupDef{Function: RollupPercentChecked}.Compute(target, nil))
+}
+
+func TestSchemaValidateCardPropertyValues(t *testing.T) {
+	schema := PropSchema{
+		"tasks-id": {ID: "tasks-id", Type: PropTypeRelation, RelationBoardID: "board-2"},
+		"done-id":  {ID: "done-id", Type: PropTypeRollup},
+	}
+
+	require.NoError(t, schema.ValidateCardPropertyValues(map[string]any{
+		"tasks-id": []any{utils.NewID(utils.IDTypeCard)},
+		"done-id":  "50%",
+	}))
+	require.Error(t, schema.ValidateCardPropertyValues(map[string]any{
+		"tasks-id": []any{"not-a-card-id"},
+	}))
+	require.Error(t, schema.ValidateCardPropertyValues(map[string]any{
+		"tasks-id": 3,
+	}))
+
+	values := map[string]any{"tasks-id": []any{}, "done-id": "50%"}
+	schema.RemoveComputedProperties(values)
+	require.Equal(t, map[string]any{"tasks-id": []any{}}, values)
+}
+
+func TestResolveRollups(t *testing.T) {
+	board := &Board{
+		ID:     "board-1",
+		TeamID: "team-1",
+		CardProperties: []map[string]any{
+			{"id": "tasks-id", "name": "Tasks", "type": PropTypeRelation, "relationBoardId": "board-2"},
+			{"id": "count-id", "name": "Count", "type": PropTypeRollup, "rollup": map[string]any{
+				"relationPropertyId": "tasks-id", "function": "count",
+			}},
+			{"id": "estimate-id", "name": "Estimate", "type": PropTypeRollup, "rollup": map[string]any{
+				"relationPropertyId": "tasks-id", "targetPropertyId": "points-id", "function": "sum",
+			}},
+		},
+	}
+	task := func(id, boardID, points string) *Block {
+		return &Block{ID: id, BoardID: boardID, Type: TypeCard, Fields: map[string]any{"properties": map[string]any{"points-id": points}}}
+	}
+	resolver := &testRollupResolver{
+		blocks: map[string]*Block{
+			"task-1": task("task-1", "board-2", "2"),
+			"task-2": task("task-2", "board-2", "3"),
+			"task-3": task("task-3", "board-3", "5"),
+		},
+		boards: map[string]*Board{
+			"board-2": {ID: "board-2", TeamID: "team-1", CardProperties: []map[string]any{
+				{"id": "points-id", "name": "Points", "type": "number"},
+			}},
+		},
+	}
+
+	card := &Block{ID: "card-1", BoardID: "board-1", Type: TypeCard, Fields: map[string]any{
+		"properties": map[string]any{"tasks-id": []any{"task-1", "task-2", "task-3", "deleted-task"}},
+	}}
+	empty := &Block{ID: "card-2", BoardID: "board-1", Type: TypeCard, Fields: map[string]any{}}
+
+	canView := func(*Board) bool { return true }
+	require.NoError(t, ResolveRollups(board, []*Block{card, empty}, resolver, canView))
+
+	// task-3 is not on the related board
+	properties := card.Fields["properties"].(map[string]any)
+	require.Equal(t, "2", properties["count-id"])
+	require.Equal(t, "5", properties["estimate-id"])
+
+	properties = empty.Fields["properties"].(map[string]any)
+	require.Equal(t, "0", properties["count-id"])
+	require.Equal(t, "0", properties["estimate-id"])
+
+	t.Run("related board of another team", func(t *testing.T) {
+		resolver.boards["board-2"].TeamID = "team-2"
+		card.Fields["properties"] = map[string]any{"tasks-id": []any{"task-1"}, "count-id": "1"}
+
+		require.NoError(t, ResolveRollups(board, []*Block{card}, resolver, canView))
+		require.NotContains(t, card.Fields["properties"], "count-id")
+	})
+
+	t.Run("related board the user can't view", func(t *testing.T) {
+		resolver.boards["board-2"].TeamID = "team-1"
+		card.Fields["properties"] = map[string]any{"tasks-id": []any{"task-1"}, "count-id": "1"}
+
+		cannotView := func(relatedBoard *Board) bool {
+			require.Equal(t, "board-2", relatedBoard.ID)
+			return false
+		}
+		require.NoError(t, ResolveRollups(board, []*Block{card}, resolver, cannotView))
+		require.NotContains(t, card.Fields["properties"], "count-id")
+		require.NotContains(t, card.Fields["properties"], "estimate-id")
+	})
+}
+
+func TestRelationBoardIDs(t *testing.T) {
+	require.Equal(t, []string{"board-2"}, RelationBoardIDs([]map[string]any{
+		{"id": "tasks-id", "type": PropTypeRelation, "relationBoardId": "board-2"},
+		{"id": "status-id", "type": "select"},
+		{"id": "other-id", "type": PropTypeRelation},
+	}))
+}
+
+func TestValidateCardPropertyTemplateRelations(t *testing.T) {
+	require.NoError(t, ValidateCardPropertyTemplate(map[string]any{
+		"id": "tasks-id", "type": PropTypeRelation, "relationBoardId": "board-2",
+	}))
+	require.Error(t, ValidateCardPropertyTemplate(map[string]any{
+		"id": "tasks-id", "type": PropTypeRelation,
+	}))
+	require.NoError(t, ValidateCardPropertyTemplate(map[string]any{
+		"id": "count-id", "type": PropTypeRollup, "rollup": map[string]any{"relationPropertyId": "tasks-id", "function": "count"},
+	}))
+	require.Error(t, ValidateCardPropertyTemplate(map[string]any{
+		"id": "sum-id", "type": PropTypeRollup, "rollup": map[string]any{"relationPropertyId": "tasks-id", "function": "sum"},
+	}))
+	require.Error(t, ValidateCardPropertyTemplate(map[string]any{
+		"id": "avg-id", "type": PropTypeRollup, "rollup": map[string]any{"relationPropertyId": "tasks-id", "function": "average"},
+	}))
+}
@@ -44,6 +44,11 @@ type ServicesAPI interface {
 	GetUserByEmail(email string) (*mm_model.User, error)
 	UpdateUser(user *mm_model.User) (*mm_model.User, error)
 	GetUsersFromProfiles(options *mm_model.UserGetOptions) ([]*mm_model.User, error)
+	GetUserStatusesByIds(userIDs []string) ([]*mm_model.Status, error)
+
+	// Group service
+	GetGroupByName(name string) (*mm_model.Group, error)
+	GetGroupMemberUsers(groupID string, page, perPage int) ([]*mm_model.User, error)
 
 	// Team service
 	GetTeamMember(teamID string, userID string) (*mm_model.TeamMember, error)
@@ -5,12 +5,18 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 )
 
 const (
 	SubTypeUser    = "user"
 	SubTypeChannel = "channel"
+
+	// SubscriptionFilterMaxProperties is the maximum number of properties
+	// a subscription filter can match.
+	SubscriptionFilterMaxProperties = 100
 )
 
 type SubscriberType string
@@ -53,6 +59,14 @@ type Subscription struct {
 	// DeleteAt is the timestamp this subscription was deleted in miliseconds since the current epoch, or zero if not deleted
 	// required: true
 	DeleteAt int64 `json:"deleteAt"`
+
+	// Filter restricts the changes the subscriber is notified of, or nil to be notified of all changes
+	// required: false
+	Filter *SubscriptionFilter `json:"filter,omitempty"`
+
+	// Digest is true if the changes are sent in the subscriber's digest instead of immediately
+	// required: false
+	Digest bool `json:"digest,omitempty"`
 }
 
 func (s *Subscription) IsValid() error {
@@ -73,6 +87,12 @@ func (s *Subscription) IsValid() error {
 	if !s.SubscriberType.IsValid() {
 		return ErrInvalidSubscription{"invalid subscriber type"}
 	}
+	if err := s.Filter.IsValid(); err != nil {
+		return ErrInvalidSubscription{err.Error()}
+	}
+	if s.Digest && s.SubscriberType != SubTypeUser {
+		return ErrInvalidSubscription{"only users can receive digests"}
+	}
 	return nil
 }
 
@@ -105,4 +125,75 @@ type Subscriber struct {
 
 	// NotifiedAt is the timestamp this subscriber was last notified
 	NotifiedAt int64 `json:"notified_at"`
+
+	// Filter restricts the changes the subscriber is notified of, or nil to be notified of all changes
+	Filter *SubscriptionFilter `json:"filter,omitempty"`
+
+	// Digest is true if the changes are sent in the subscriber's digest instead of immediately
+	Digest bool `json:"digest,omitempty"`
+}
+
+// SubscriptionFilter restricts the changes of a card a subscriber is notified
+// of. A change is notified if any of the filter criteria matches it. Cards
+// being added or deleted are always notified.
+// swagger:model
+type SubscriptionFilter struct {
+	// Notify of added and deleted comments
+	// required: false
+	Comments bool `json:"comments,omitempty"`
+
+	// Notify of changes to person and multi person properties
+	// required: false
+	Assignments bool `json:"assignments,omitempty"`
+
+	// Notify of changes to date properties
+	// required: false
+	DueDates bool `json:"dueDates,omitempty"`
+
+	// IDs of the properties to notify the changes of
+	// required: false
+	Properties []string `json:"properties,omitempty"`
+}
+
+// IsEmpty returns true if the filter matches all changes.
+func (f *SubscriptionFilter) IsEmpty() bool {
+	return f == nil || (!f.Comments && !f.Assignments && !f.DueDates && len(f.Properties) == 0)
+}
+
+func (f *SubscriptionFilter) IsValid() error {
+	if f == nil {
+		return nil
+	}
+	if len(f.Properties) > SubscriptionFilterMaxProperties {
+		return fmt.Errorf("filter cannot have more than %d properties", SubscriptionFilterMaxProperties)
+	}
+	for _, propID := range f.Properties {
+		if propID == "" {
+			return errors.New("filter has an empty property id")
+		}
+	}
+	return nil
+}
+
+// MatchesProperty returns true if changes to the property are notified.
+func (f *SubscriptionFilter) MatchesProperty(pd PropDef) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	switch pd.Type {
+	case "person", "multiPerson":
+		if f.Assignments {
+			return true
+		}
+	case "date":
+		if f.DueDates {
+			return true
+		}
+	}
+	for _, propID := range f.Properties {
+		if propID == pd.ID {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,63 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionFilterIsValid(t *testing.T) {
+	var filter *SubscriptionFilter
+	require.NoError(t, filter.IsValid())
+	require.True(t, filter.IsEmpty())
+
+	filter = &SubscriptionFilter{Comments: true, Properties: []string{"prop-1"}}
+	require.NoError(t, filter.IsValid())
+	require.False(t, filter.IsEmpty())
+
+	filter = &SubscriptionFilter{Properties: []string{""}}
+	require.Error(t, filter.IsValid())
+
+	filter = &SubscriptionFilter{Properties: make([]string, SubscriptionFilterMaxProperties+1)}
+	for i := range filter.Properties {
+		filter.Properties[i] = "prop"
+	}
+	require.Error(t, filter.IsValid())
+
+	sub := &Subscription{
+		BlockType:      TypeCard,
+		BlockID:        utils.NewID(utils.IDTypeCard),
+		SubscriberType: SubTypeUser,
+		SubscriberID:   "user-1",
+		Filter:         &SubscriptionFilter{Properties: []string{""}},
+	}
+	require.Error(t, sub.IsValid())
+}
+
+func TestSubscriptionFilterMatchesProperty(t *testing.T) {
+	person := PropDef{ID: "assignee", Type: "person"}
+	multiPerson := PropDef{ID: "reviewers", Type: "multiPerson"}
+	date := PropDef{ID: "due", Type: "date"}
+	status := PropDef{ID: "status", Type: "select"}
+
+	var filter *SubscriptionFilter
+	require.True(t, filter.MatchesProperty(status))
+
+	filter = &SubscriptionFilter{Comments: true}
+	require.False(t, filter.MatchesProperty(status))
+	require.False(t, filter.MatchesProperty(person))
+
+	filter = &SubscriptionFilter{Assignments: true}
+	require.True(t, filter.MatchesProperty(person))
+	require.True(t, filter.MatchesProperty(multiPerson))
+	require.False(t, filter.MatchesProperty(date))
+
+	filter = &SubscriptionFilter{DueDates: true, Properties: []string{"status"}}
+	require.True(t, filter.MatchesProperty(date))
+	require.True(t, filter.MatchesProperty(status))
+	require.False(t, filter.MatchesProperty(person))
+}
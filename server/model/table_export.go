@@ -0,0 +1,44 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import "fmt"
+
+// TableExportFormat is the file format of a board exported as a table.
+type TableExportFormat string
+
+const (
+	TableExportCSV  TableExportFormat = "csv"
+	TableExportXLSX TableExportFormat = "xlsx"
+)
+
+// ContentType returns the MIME type of the exported file.
+func (f TableExportFormat) ContentType() string {
+	if f == TableExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportTableOptions are the options of the export of the cards of a board
+// as a table, with one row per card and one column per property.
+type ExportTableOptions struct {
+	BoardID string
+	ViewID  string // if not empty then honor the filter, sort and visible properties of the view
+	Format  TableExportFormat
+	UserID  string // the user the rollups are computed for
+}
+
+func (o ExportTableOptions) IsValid() error {
+	if o.BoardID == "" {
+		return NewErrBadRequest("missing board id")
+	}
+
+	switch o.Format {
+	case TableExportCSV, TableExportXLSX:
+		return nil
+	default:
+		return NewErrBadRequest(fmt.Sprintf("invalid export format %q", o.Format))
+	}
+}
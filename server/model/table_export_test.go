@@ -0,0 +1,21 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTableOptionsIsValid(t *testing.T) {
+	require.NoError(t, ExportTableOptions{BoardID: "board-id", Format: TableExportCSV}.IsValid())
+	require.NoError(t, ExportTableOptions{BoardID: "board-id", ViewID: "view-id", Format: TableExportXLSX}.IsValid())
+
+	err := ExportTableOptions{Format: TableExportCSV}.IsValid()
+	require.True(t, IsErrBadRequest(err))
+
+	err = ExportTableOptions{BoardID: "board-id", Format: "pdf"}.IsValid()
+	require.True(t, IsErrBadRequest(err))
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// TimeEntryNoteMaxRunes is the maximum length of the note of a time entry.
+	TimeEntryNoteMaxRunes = 1000
+
+	// TimeEntryMaxDuration is the maximum duration of a time entry, in
+	// milliseconds.
+	TimeEntryMaxDuration = int64(24 * 60 * 60 * 1000)
+
+	// TimeSummaryMaxRange is the maximum range of a time summary, in
+	// milliseconds.
+	TimeSummaryMaxRange = int64(366 * 24 * 60 * 60 * 1000)
+)
+
+// TimeEntry is time spent by a user on a card. While its timer is running,
+// its duration is zero.
+// swagger:model
+type TimeEntry struct {
+	// The id of the time entry
+	// required: true
+	ID string `json:"id"`
+
+	// The board of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The card the time was spent on
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The user who spent the time
+	// required: true
+	UserID string `json:"userId"`
+
+	// The start of the time entry in miliseconds since the current epoch
+	// required: true
+	StartAt int64 `json:"startAt"`
+
+	// The time spent in miliseconds
+	// required: true
+	Duration int64 `json:"duration"`
+
+	// True while the timer of the entry is running
+	// required: true
+	Running bool `json:"running"`
+
+	// A note about the work done
+	// required: false
+	Note string `json:"note"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The deleted time in miliseconds since the current epoch, or zero
+	// required: true
+	DeleteAt int64 `json:"deleteAt"`
+}
+
+func (e *TimeEntry) IsValid() error {
+	if e.CardID == "" || e.BoardID == "" {
+		return NewErrBadRequest("time entry is missing a card id")
+	}
+	if e.UserID == "" {
+		return NewErrBadRequest("time entry is missing a user id")
+	}
+	if e.StartAt <= 0 {
+		return NewErrBadRequest("time entry is missing a start time")
+	}
+	if e.Duration < 0 || e.Duration > TimeEntryMaxDuration {
+		return NewErrBadRequest(fmt.Sprintf("invalid time entry duration %d", e.Duration))
+	}
+	if e.Running && e.Duration != 0 {
+		return NewErrBadRequest("a running time entry cannot have a duration")
+	}
+	if utf8.RuneCountInString(e.Note) > TimeEntryNoteMaxRunes {
+		return NewErrBadRequest(fmt.Sprintf("time entry note exceeds %d characters", TimeEntryNoteMaxRunes))
+	}
+	return nil
+}
+
+// Stop stops the timer of a running entry at `now`.
+func (e *TimeEntry) Stop(now int64) {
+	e.Running = false
+	e.Duration = now - e.StartAt
+	if e.Duration < 0 {
+		e.Duration = 0
+	}
+	if e.Duration > TimeEntryMaxDuration {
+		e.Duration = TimeEntryMaxDuration
+	}
+	e.UpdateAt = now
+}
+
+// TimeEntryPatch is a patch for a time entry.
+// swagger:model
+type TimeEntryPatch struct {
+	// The start of the time entry in miliseconds since the current epoch
+	// required: false
+	StartAt *int64 `json:"startAt"`
+
+	// The time spent in miliseconds
+	// required: false
+	Duration *int64 `json:"duration"`
+
+	// A note about the work done
+	// required: false
+	Note *string `json:"note"`
+}
+
+// Patch returns an updated version of the time entry.
+func (p *TimeEntryPatch) Patch(entry *TimeEntry) *TimeEntry {
+	if p.StartAt != nil {
+		entry.StartAt = *p.StartAt
+	}
+	if p.Duration != nil && !entry.Running {
+		entry.Duration = *p.Duration
+	}
+	if p.Note != nil {
+		entry.Note = *p.Note
+	}
+	return entry
+}
+
+// TimeEntriesComplianceResponse is the response body to a request for time
+// entries.
+// swagger:model
+type TimeEntriesComplianceResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The array of time entries
+	// required: true
+	Results []*TimeEntry `json:"results"`
+}
+
+type QueryTimeEntriesComplianceOptions struct {
+	ModifiedSince  int64  // if non-zero then filter for records with update_at greater than ModifiedSince
+	IncludeDeleted bool   // if true then deleted time entries are included
+	TeamID         string // if not empty then filter for specific team, otherwise all teams are included
+	BoardID        string // if not empty then filter for specific board, otherwise all boards are included
+	Page           int    // page number to select when paginating
+	PerPage        int    // number of time entries per page (default=60)
+}
+
+// QueryTimeSummaryOptions selects the stopped time entries of a board or of
+// a user that start within a date range.
+type QueryTimeSummaryOptions struct {
+	BoardID string // if not empty then filter for specific board
+	UserID  string // if not empty then filter for specific user
+	From    int64  // entries starting at or after From are included
+	To      int64  // entries starting before To are included
+}
+
+func (o QueryTimeSummaryOptions) IsValid() error {
+	if o.From <= 0 || o.To <= 0 {
+		return NewErrBadRequest("`from` and `to` are required")
+	}
+	if o.To <= o.From {
+		return NewErrBadRequest("`to` must be after `from`")
+	}
+	if o.To-o.From > TimeSummaryMaxRange {
+		return NewErrBadRequest("time summary range is too long")
+	}
+	return nil
+}
+
+// TimeSpent is the time spent on a card by a user, as summed by the store.
+type TimeSpent struct {
+	BoardID  string
+	CardID   string
+	UserID   string
+	Duration int64
+	Count    int
+}
+
+// TimeSummaryItem is the time spent on a board, on a card or by a user.
+// swagger:model
+type TimeSummaryItem struct {
+	// The id of the board, card or user
+	// required: true
+	ID string `json:"id"`
+
+	// The time spent in miliseconds
+	// required: true
+	Duration int64 `json:"duration"`
+
+	// The number of time entries
+	// required: true
+	Count int `json:"count"`
+}
+
+// TimeSummary is the time spent over a date range, by board, by card and by
+// user.
+// swagger:model
+type TimeSummary struct {
+	// The start of the range in miliseconds since the current epoch
+	// required: true
+	From int64 `json:"from"`
+
+	// The end of the range in miliseconds since the current epoch
+	// required: true
+	To int64 `json:"to"`
+
+	// The total time spent in miliseconds
+	// required: true
+	Duration int64 `json:"duration"`
+
+	// The time spent by board, the longest first
+	// required: true
+	Boards []*TimeSummaryItem `json:"boards"`
+
+	// The time spent by card, the longest first
+	// required: true
+	Cards []*TimeSummaryItem `json:"cards"`
+
+	// The time spent by user, the longest first
+	// required: true
+	Users []*TimeSummaryItem `json:"users"`
+}
+
+// NewTimeSummary sums the time spent over a date range.
+func NewTimeSummary(from, to int64, spent []*TimeSpent) *TimeSummary {
+	boards := map[string]*TimeSummaryItem{}
+	cards := map[string]*TimeSummaryItem{}
+	users := map[string]*TimeSummaryItem{}
+
+	summary := &TimeSummary{From: from, To: to}
+	for _, s := range spent {
+		summary.Duration += s.Duration
+		addTimeSummaryItem(boards, s.BoardID, s)
+		addTimeSummaryItem(cards, s.CardID, s)
+		addTimeSummaryItem(users, s.UserID, s)
+	}
+
+	summary.Boards = sortedTimeSummaryItems(boards)
+	summary.Cards = sortedTimeSummaryItems(cards)
+	summary.Users = sortedTimeSummaryItems(users)
+	return summary
+}
+
+func addTimeSummaryItem(items map[string]*TimeSummaryItem, id string, spent *TimeSpent) {
+	item, ok := items[id]
+	if !ok {
+		item = &TimeSummaryItem{ID: id}
+		items[id] = item
+	}
+	item.Duration += spent.Duration
+	item.Count += spent.Count
+}
+
+func sortedTimeSummaryItems(items map[string]*TimeSummaryItem) []*TimeSummaryItem {
+	sorted := make([]*TimeSummaryItem, 0, len(items))
+	for _, item := range items {
+		sorted = append(sorted, item)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Duration != sorted[j].Duration {
+			return sorted[i].Duration > sorted[j].Duration
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEntryIsValid(t *testing.T) {
+	valid := func() *TimeEntry {
+		return &TimeEntry{
+			BoardID:  "board-1",
+			CardID:   "card-1",
+			UserID:   "user-1",
+			StartAt:  1000,
+			Duration: 60000,
+		}
+	}
+	require.NoError(t, valid().IsValid())
+
+	entry := valid()
+	entry.CardID = ""
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+
+	entry = valid()
+	entry.StartAt = 0
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+
+	entry = valid()
+	entry.Duration = -1
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+
+	entry = valid()
+	entry.Duration = TimeEntryMaxDuration + 1
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+
+	entry = valid()
+	entry.Running = true
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+	entry.Duration = 0
+	require.NoError(t, entry.IsValid())
+
+	entry = valid()
+	entry.Note = strings.Repeat("é", TimeEntryNoteMaxRunes+1)
+	require.True(t, IsErrBadRequest(entry.IsValid()))
+}
+
+func TestTimeEntryStop(t *testing.T) {
+	entry := &TimeEntry{StartAt: 1000, Running: true}
+	entry.Stop(61000)
+	require.False(t, entry.Running)
+	require.Equal(t, int64(60000), entry.Duration)
+	require.Equal(t, int64(61000), entry.UpdateAt)
+
+	entry = &TimeEntry{StartAt: 1000, Running: true}
+	entry.Stop(1000 + 2*TimeEntryMaxDuration)
+	require.Equal(t, TimeEntryMaxDuration, entry.Duration)
+
+	entry = &TimeEntry{StartAt: 1000, Running: true}
+	entry.Stop(500)
+	require.Zero(t, entry.Duration)
+}
+
+func TestTimeEntryPatch(t *testing.T) {
+	startAt := int64(2000)
+	duration := int64(5000)
+	note := "review"
+
+	entry := (&TimeEntryPatch{StartAt: &startAt, Duration: &duration, Note: &note}).Patch(&TimeEntry{StartAt: 1000})
+	require.Equal(t, startAt, entry.StartAt)
+	require.Equal(t, duration, entry.Duration)
+	require.Equal(t, note, entry.Note)
+
+	// the duration of a running entry is set when it is stopped
+	entry = (&TimeEntryPatch{Duration: &duration}).Patch(&TimeEntry{StartAt: 1000, Running: true})
+	require.Zero(t, entry.Duration)
+}
+
+func TestQueryTimeSummaryOptionsIsValid(t *testing.T) {
+	require.NoError(t, QueryTimeSummaryOptions{From: 1000, To: 2000}.IsValid())
+	require.True(t, IsErrBadRequest(QueryTimeSummaryOptions{To: 2000}.IsValid()))
+	require.True(t, IsErrBadRequest(QueryTimeSummaryOptions{From: 2000, To: 1000}.IsValid()))
+	require.True(t, IsErrBadRequest(QueryTimeSummaryOptions{From: 1, To: 2 + TimeSummaryMaxRange}.IsValid()))
+}
+
+func TestNewTimeSummary(t *testing.T) {
+	summary := NewTimeSummary(1000, 2000, []*TimeSpent{
+		{BoardID: "board-1", CardID: "card-1", UserID: "user-1", Duration: 100, Count: 1},
+		{BoardID: "board-1", CardID: "card-1", UserID: "user-2", Duration: 300, Count: 2},
+		{BoardID: "board-2", CardID: "card-2", UserID: "user-1", Duration: 50, Count: 1},
+	})
+
+	require.Equal(t, int64(1000), summary.From)
+	require.Equal(t, int64(2000), summary.To)
+	require.Equal(t, int64(450), summary.Duration)
+	require.Equal(t, []*TimeSummaryItem{
+		{ID: "board-1", Duration: 400, Count: 3},
+		{ID: "board-2", Duration: 50, Count: 1},
+	}, summary.Boards)
+	require.Equal(t, []*TimeSummaryItem{
+		{ID: "card-1", Duration: 400, Count: 3},
+		{ID: "card-2", Duration: 50, Count: 1},
+	}, summary.Cards)
+	require.Equal(t, []*TimeSummaryItem{
+		{ID: "user-2", Duration: 300, Count: 2},
+		{ID: "user-1", Duration: 150, Count: 2},
+	}, summary.Users)
+
+	summary = NewTimeSummary(1000, 2000, nil)
+	require.Zero(t, summary.Duration)
+	require.Empty(t, summary.Cards)
+	require.NotNil(t, summary.Cards)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrNotViewBlock = errors.New("not a view block")
+
+// View is a board view, as stored in the fields of a block of type view.
+type View struct {
+	ID                 string           `json:"id"`
+	BoardID            string           `json:"boardId"`
+	Title              string           `json:"title"`
+	ViewType           string           `json:"viewType"`
+	GroupByID          string           `json:"groupById"`
+	SortOptions        []CardSortOption `json:"sortOptions"`
+	VisiblePropertyIDs []string         `json:"visiblePropertyIds"`
+	VisibleOptionIDs   []string         `json:"visibleOptionIds"`
+	HiddenOptionIDs    []string         `json:"hiddenOptionIds"`
+	CollapsedOptionIDs []string         `json:"collapsedOptionIds"`
+	Filter             *FilterGroup     `json:"filter"`
+	CardOrder          []string         `json:"cardOrder"`
+}
+
+// Block2View parses the fields of a view block.
+func Block2View(block *Block) (*View, error) {
+	if block.Type != TypeView {
+		return nil, fmt.Errorf("cannot convert block to view: %w", ErrNotViewBlock)
+	}
+
+	data, err := json.Marshal(block.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &View{}
+	if err := json.Unmarshal(data, view); err != nil {
+		return nil, fmt.Errorf("cannot parse view fields: %w", err)
+	}
+
+	view.ID = block.ID
+	view.BoardID = block.BoardID
+	view.Title = block.Title
+
+	return view, nil
+}
+
+// ViewCardGroup is a group of cards of a view, like a column of a board
+// view, made of the cards that have the same value of the group by property.
+// swagger:model
+type ViewCardGroup struct {
+	// The id of the option, or the user id for person properties. Empty
+	// for the group of the cards without a value
+	// required: true
+	OptionID string `json:"optionId"`
+
+	// The value of the option, or the user id for person properties
+	// required: true
+	Value string `json:"value"`
+
+	// True if the group is collapsed in the view
+	// required: false
+	Collapsed bool `json:"collapsed"`
+
+	// The cards of the group, in the order of the view
+	// required: true
+	Cards []*Card `json:"cards"`
+}
+
+// ViewCards is the result of evaluating a view: its cards filtered,
+// sorted and grouped as the view displays them.
+// swagger:model
+type ViewCards struct {
+	// The id of the view
+	// required: true
+	ViewID string `json:"viewId"`
+
+	// The type of the view
+	// required: true
+	ViewType string `json:"viewType"`
+
+	// The id of the property the cards are grouped by
+	// required: false
+	GroupByID string `json:"groupById,omitempty"`
+
+	// The ids of the properties displayed by the view
+	// required: true
+	VisiblePropertyIDs []string `json:"visiblePropertyIds"`
+
+	// All the cards of the view, in order
+	// required: true
+	Cards []*Card `json:"cards"`
+
+	// The visible groups, in order. Only present if the view is grouped
+	// required: false
+	Groups []*ViewCardGroup `json:"groups,omitempty"`
+
+	// The groups hidden by the view. Only present if the view is grouped
+	// required: false
+	HiddenGroups []*ViewCardGroup `json:"hiddenGroups,omitempty"`
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	// WebhookSignatureHeader holds the HMAC-SHA256 of the request body,
+	// keyed with the webhook secret, as "sha256=<hex digest>".
+	WebhookSignatureHeader = "X-Boards-Signature"
+
+	// WebhookEventHeader holds the event type of a delivery.
+	WebhookEventHeader = "X-Boards-Event"
+
+	// WebhookDeliveryHeader holds the id of a delivery, which is kept
+	// across retries so receivers can deduplicate them.
+	WebhookDeliveryHeader = "X-Boards-Delivery"
+
+	// WebhookDeliveriesMaxPerPage is the maximum number of deliveries that can be requested in a single page.
+	WebhookDeliveriesMaxPerPage = 100
+)
+
+// WebhookEvent is the type of a board event that webhooks can subscribe to.
+type WebhookEvent string
+
+const (
+	WebhookEventCardCreated         WebhookEvent = "card_created"
+	WebhookEventCardPropertyChanged WebhookEvent = "card_property_changed"
+	WebhookEventCommentAdded        WebhookEvent = "comment_added"
+	WebhookEventCardMoved           WebhookEvent = "card_moved"
+	WebhookEventMemberAdded         WebhookEvent = "member_added"
+)
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventCardCreated, WebhookEventCardPropertyChanged,
+		WebhookEventCommentAdded, WebhookEventCardMoved, WebhookEventMemberAdded:
+		return true
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	WebhookDeliverySuccess WebhookDeliveryStatus = "success"
+	WebhookDeliveryFailed  WebhookDeliveryStatus = "failed"
+)
+
+// BoardWebhook is an outgoing webhook registered on a board
+// swagger:model
+type BoardWebhook struct {
+	// The id of the webhook
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the board the webhook belongs to
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The URL the events are posted to
+	// required: true
+	URL string `json:"url"`
+
+	// The secret used to sign the deliveries. Only returned when the
+	// webhook is created or its secret is changed
+	// required: false
+	Secret string `json:"secret,omitempty"`
+
+	// The events the webhook is subscribed to
+	// required: true
+	Events []WebhookEvent `json:"events"`
+
+	// If false no events are delivered
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// The id of the user who created the webhook
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The id of the user who last modified the webhook
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid checks the webhook fields that can be set by users.
+func (wh *BoardWebhook) IsValid() error {
+	if wh.BoardID == "" {
+		return NewErrBadRequest("webhook is missing its board id")
+	}
+	if err := isValidWebhookURL(wh.URL); err != nil {
+		return err
+	}
+	return isValidWebhookEvents(wh.Events)
+}
+
+// Subscribes returns true if the webhook is enabled and subscribed to the event.
+func (wh *BoardWebhook) Subscribes(event WebhookEvent) bool {
+	if !wh.Enabled {
+		return false
+	}
+	for _, e := range wh.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Sanitize removes the secret so the webhook can be listed.
+func (wh *BoardWebhook) Sanitize() {
+	wh.Secret = ""
+}
+
+// BoardWebhookPatch is a patch for modifying a webhook
+// swagger:model
+type BoardWebhookPatch struct {
+	// The URL the events are posted to
+	// required: false
+	URL *string `json:"url"`
+
+	// The secret used to sign the deliveries
+	// required: false
+	Secret *string `json:"secret"`
+
+	// The events the webhook is subscribed to
+	// required: false
+	Events []WebhookEvent `json:"events"`
+
+	// If false no events are delivered
+	// required: false
+	Enabled *bool `json:"enabled"`
+}
+
+// Patch returns an updated version of the webhook.
+func (p *BoardWebhookPatch) Patch(wh *BoardWebhook) *BoardWebhook {
+	if p.URL != nil {
+		wh.URL = *p.URL
+	}
+
+	if p.Secret != nil {
+		wh.Secret = *p.Secret
+	}
+
+	if p.Events != nil {
+		wh.Events = p.Events
+	}
+
+	if p.Enabled != nil {
+		wh.Enabled = *p.Enabled
+	}
+
+	return wh
+}
+
+func (p *BoardWebhookPatch) IsValid() error {
+	if p.URL != nil {
+		if err := isValidWebhookURL(*p.URL); err != nil {
+			return err
+		}
+	}
+	if p.Events != nil {
+		return isValidWebhookEvents(p.Events)
+	}
+	return nil
+}
+
+func isValidWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewErrBadRequest(fmt.Sprintf("invalid webhook url %q", rawURL))
+	}
+	return nil
+}
+
+func isValidWebhookEvents(events []WebhookEvent) error {
+	if len(events) == 0 {
+		return NewErrBadRequest("webhook must subscribe to at least one event")
+	}
+	for _, e := range events {
+		if !e.IsValid() {
+			return NewErrBadRequest(fmt.Sprintf("invalid webhook event %q", e))
+		}
+	}
+	return nil
+}
+
+// WebhookPropertyChange is the change of a card property.
+type WebhookPropertyChange struct {
+	PropertyID   string      `json:"propertyId"`
+	PropertyName string      `json:"propertyName,omitempty"`
+	OldValue     interface{} `json:"oldValue"`
+	NewValue     interface{} `json:"newValue"`
+}
+
+// WebhookPayload is the body posted to a webhook URL.
+type WebhookPayload struct {
+	DeliveryID string                  `json:"deliveryId"`
+	Event      WebhookEvent            `json:"event"`
+	Timestamp  int64                   `json:"timestamp"`
+	TeamID     string                  `json:"teamId"`
+	BoardID    string                  `json:"boardId"`
+	ActorID    string                  `json:"actorId,omitempty"`
+	Card       *Block                  `json:"card,omitempty"`
+	Comment    *Block                  `json:"comment,omitempty"`
+	Member     *BoardMember            `json:"member,omitempty"`
+	Changes    []WebhookPropertyChange `json:"changes,omitempty"`
+}
+
+// WebhookDelivery is an attempt, or series of attempts, to post an
+// event to a webhook
+// swagger:model
+type WebhookDelivery struct {
+	// The id of the delivery
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the webhook
+	// required: true
+	WebhookID string `json:"webhookId"`
+
+	// The id of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The event delivered
+	// required: true
+	Event WebhookEvent `json:"event"`
+
+	// The JSON body posted to the webhook
+	// required: true
+	Payload string `json:"payload"`
+
+	// The state of the delivery: pending, success or failed
+	// required: true
+	Status WebhookDeliveryStatus `json:"status"`
+
+	// The number of attempts made so far
+	// required: true
+	Attempts int `json:"attempts"`
+
+	// The time of the next attempt in miliseconds since the current epoch, for pending deliveries
+	// required: false
+	NextAttemptAt int64 `json:"nextAttemptAt"`
+
+	// The time of the last attempt in miliseconds since the current epoch
+	// required: false
+	LastAttemptAt int64 `json:"lastAttemptAt"`
+
+	// The HTTP status code of the last attempt, 0 if no response was received
+	// required: false
+	ResponseCode int `json:"responseCode"`
+
+	// The error of the last attempt, if it failed
+	// required: false
+	Error string `json:"error"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
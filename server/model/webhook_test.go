@@ -0,0 +1,85 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardWebhookIsValid(t *testing.T) {
+	valid := func() *BoardWebhook {
+		return &BoardWebhook{
+			BoardID: "board-id",
+			URL:     "https://example.com/hook",
+			Events:  []WebhookEvent{WebhookEventCardCreated},
+		}
+	}
+
+	require.NoError(t, valid().IsValid())
+
+	testCases := []struct {
+		name   string
+		modify func(wh *BoardWebhook)
+	}{
+		{"missing board", func(wh *BoardWebhook) { wh.BoardID = "" }},
+		{"relative url", func(wh *BoardWebhook) { wh.URL = "/hook" }},
+		{"unsupported scheme", func(wh *BoardWebhook) { wh.URL = "ftp://example.com/hook" }},
+		{"no events", func(wh *BoardWebhook) { wh.Events = nil }},
+		{"unknown event", func(wh *BoardWebhook) { wh.Events = []WebhookEvent{"card_exploded"} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := valid()
+			tc.modify(wh)
+			err := wh.IsValid()
+			require.Error(t, err)
+			require.True(t, IsErrBadRequest(err))
+		})
+	}
+}
+
+func TestBoardWebhookSubscribes(t *testing.T) {
+	wh := &BoardWebhook{
+		Events:  []WebhookEvent{WebhookEventCardCreated, WebhookEventMemberAdded},
+		Enabled: true,
+	}
+
+	require.True(t, wh.Subscribes(WebhookEventCardCreated))
+	require.True(t, wh.Subscribes(WebhookEventMemberAdded))
+	require.False(t, wh.Subscribes(WebhookEventCommentAdded))
+
+	wh.Enabled = false
+	require.False(t, wh.Subscribes(WebhookEventCardCreated))
+}
+
+func TestBoardWebhookPatch(t *testing.T) {
+	wh := &BoardWebhook{
+		URL:     "https://example.com/hook",
+		Secret:  "secret",
+		Events:  []WebhookEvent{WebhookEventCardCreated},
+		Enabled: true,
+	}
+
+	newURL := "https://example.com/other"
+	enabled := false
+	patch := &BoardWebhookPatch{
+		URL:     &newURL,
+		Events:  []WebhookEvent{WebhookEventCommentAdded},
+		Enabled: &enabled,
+	}
+	require.NoError(t, patch.IsValid())
+
+	patched := patch.Patch(wh)
+	require.Equal(t, newURL, patched.URL)
+	require.Equal(t, "secret", patched.Secret)
+	require.Equal(t, []WebhookEvent{WebhookEventCommentAdded}, patched.Events)
+	require.False(t, patched.Enabled)
+
+	invalidURL := "not a url"
+	require.Error(t, (&BoardWebhookPatch{URL: &invalidURL}).IsValid())
+	require.Error(t, (&BoardWebhookPatch{Events: []WebhookEvent{}}).IsValid())
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-boards/server/services/metrics"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/notify/notifylogger"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/rules"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/scheduler"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/store/sqlstore"
@@ -43,6 +44,9 @@ import (
 const (
 	cleanupSessionTaskFrequency = 10 * time.Minute
 	updateMetricsTaskFrequency  = 15 * time.Minute
+	recurringCardsTaskFrequency = 1 * time.Minute
+	exportJobsTaskFrequency     = 30 * time.Second
+	importSessionsTaskFrequency = 1 * time.Hour
 )
 
 type Server struct {
@@ -57,6 +61,9 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	recurringCardsTask     *scheduler.ScheduledTask
+	exportJobsTask         *scheduler.ScheduledTask
+	importSessionsTask     *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -102,7 +109,8 @@ func New(params Params) (*Server, error) {
 		return nil, errors.New("unable to initialize the files storage")
 	}
 
-	webhookClient := webhook.NewClient(params.Cfg, params.Logger)
+	webhookClient := webhook.NewClient(params.Cfg, params.DBStore, params.Logger)
+	rulesEngine := rules.New(params.DBStore, params.Logger)
 
 	// Init metrics
 	instanceInfo := metrics.InstanceInfo{
@@ -123,7 +131,9 @@ func New(params Params) (*Server, error) {
 	}
 
 	// Init notification services
-	notificationService, errNotify := initNotificationService(params.NotifyBackends, params.Logger)
+	// the webhook client delivers the events of the board webhooks
+	notifyBackends := append([]notify.Backend{webhookClient}, params.NotifyBackends...)
+	notificationService, errNotify := initNotificationService(notifyBackends, params.Logger)
 	if errNotify != nil {
 		return nil, fmt.Errorf("cannot initialize notification service(s): %w", errNotify)
 	}
@@ -135,9 +145,11 @@ func New(params Params) (*Server, error) {
 		Webhook:          webhookClient,
 		Metrics:          metricsService,
 		Notifications:    notificationService,
+		Rules:            rulesEngine,
 		Logger:           params.Logger,
 		Permissions:      params.PermissionsService,
 		ServicesAPI:      params.ServicesAPI,
+		NewMutexFn:       params.NewMutexFn,
 		SkipTemplateInit: utils.IsRunningUnitTests(),
 	}
 	app := app.New(params.Cfg, wsAdapter, appServices)
@@ -276,6 +288,12 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	s.recurringCardsTask = scheduler.CreateRecurringTask("recurringCards", s.app.ProcessRecurringCards, recurringCardsTaskFrequency)
+
+	s.exportJobsTask = scheduler.CreateRecurringTask("exportJobs", s.app.ProcessExportJobs, exportJobsTaskFrequency)
+
+	s.importSessionsTask = scheduler.CreateRecurringTask("importSessions", s.app.CleanupImportSessions, importSessionsTaskFrequency)
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -317,6 +335,18 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.recurringCardsTask != nil {
+		s.recurringCardsTask.Cancel()
+	}
+
+	if s.exportJobsTask != nil {
+		s.exportJobsTask.Cancel()
+	}
+
+	if s.importSessionsTask != nil {
+		s.importSessionsTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importers
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type asanaExport struct {
+	Data []asanaTask `json:"data"`
+}
+
+type asanaTask struct {
+	Name        string     `json:"name"`
+	Notes       string     `json:"notes"`
+	Completed   bool       `json:"completed"`
+	DueOn       string     `json:"due_on"`
+	CreatedAt   string     `json:"created_at"`
+	Assignee    *asanaUser `json:"assignee"`
+	Memberships []struct {
+		Project asanaNamed `json:"project"`
+		Section asanaNamed `json:"section"`
+	} `json:"memberships"`
+	Tags     []asanaNamed `json:"tags"`
+	Subtasks []struct {
+		Name      string `json:"name"`
+		Completed bool   `json:"completed"`
+	} `json:"subtasks"`
+	Stories []struct {
+		Type      string     `json:"type"`
+		Text      string     `json:"text"`
+		CreatedAt string     `json:"created_at"`
+		CreatedBy *asanaUser `json:"created_by"`
+	} `json:"stories"`
+}
+
+type asanaNamed struct {
+	Name string `json:"name"`
+}
+
+type asanaUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// convertAsana converts the JSON export of an Asana project. The sections
+// are the options of the select property the view is grouped by.
+func convertAsana(data []byte, users UserResolver) (*boardBuilder, error) {
+	var export asanaExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if len(export.Data) == 0 {
+		return nil, errNothingToImport
+	}
+
+	title := ""
+	for _, membership := range export.Data[0].Memberships {
+		if membership.Project.Name != "" {
+			title = membership.Project.Name
+			break
+		}
+	}
+
+	b := newBoardBuilder(title, "", users)
+	b.groupBy("Section")
+	for _, task := range export.Data {
+		card := b.addCard(task.Name, millis(parseTime(task.CreatedAt, time.RFC3339)))
+		for _, membership := range task.Memberships {
+			if membership.Section.Name != "" {
+				b.setSelect(card, "Section", membership.Section.Name, "")
+				break
+			}
+		}
+		b.setCheckbox(card, "Completed", task.Completed)
+		for _, tag := range task.Tags {
+			b.addMultiSelect(card, "Tags", tag.Name, "")
+		}
+		if task.Assignee != nil {
+			b.setPerson(card, "Assignee", "", task.Assignee.Email)
+		}
+		b.setDate(card, "Due date", parseTime(task.DueOn, "2006-01-02"))
+
+		b.addText(card, task.Notes)
+		for _, subtask := range task.Subtasks {
+			b.addCheckbox(card, subtask.Name, subtask.Completed)
+		}
+		for _, story := range task.Stories {
+			if story.Type != "comment" {
+				continue
+			}
+			author := ""
+			if story.CreatedBy != nil {
+				author = story.CreatedBy.Name
+			}
+			b.addComment(card, story.Text, author, millis(parseTime(story.CreatedAt, time.RFC3339)))
+		}
+	}
+
+	return b, nil
+}
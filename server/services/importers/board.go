@@ -0,0 +1,320 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+const (
+	importViewTitle    = "Board view"
+	defaultOptionColor = "propColorDefault"
+)
+
+// boardBuilder builds a board and its blocks from the content of an export.
+// Card properties and their options are created as the values are set.
+type boardBuilder struct {
+	board     *model.Board
+	blocks    []*model.Block
+	groupByID string
+
+	propertyIDs map[string]string            // ids of the card properties by name
+	optionIDs   map[string]map[string]string // ids of the options by property id and lower case value
+
+	users   UserResolver
+	userIDs map[string]string // ids of the users by username or email, empty if not found
+}
+
+func newBoardBuilder(title, description string, users UserResolver) *boardBuilder {
+	if title == "" {
+		title = "Untitled board"
+	}
+	return &boardBuilder{
+		board: &model.Board{
+			ID:              utils.NewID(utils.IDTypeBoard),
+			Type:            model.BoardTypePrivate,
+			Title:           title,
+			Description:     description,
+			ShowDescription: description != "",
+			Properties:      map[string]any{},
+			CardProperties:  []map[string]any{},
+		},
+		propertyIDs: map[string]string{},
+		optionIDs:   map[string]map[string]string{},
+		users:       users,
+		userIDs:     map[string]string{},
+	}
+}
+
+// property returns the id of the card property with the given name,
+// creating it if needed.
+func (b *boardBuilder) property(name, propType string) string {
+	if id, ok := b.propertyIDs[name]; ok {
+		return id
+	}
+
+	id := utils.NewID(utils.IDTypeNone)
+	b.board.CardProperties = append(b.board.CardProperties, map[string]any{
+		"id":      id,
+		"name":    name,
+		"type":    propType,
+		"options": []any{},
+	})
+	b.propertyIDs[name] = id
+	b.optionIDs[id] = map[string]string{}
+	return id
+}
+
+// option returns the id of the option of a select property with the given
+// value, creating it if needed.
+func (b *boardBuilder) option(propertyID, value, color string) string {
+	key := strings.ToLower(value)
+	if id, ok := b.optionIDs[propertyID][key]; ok {
+		return id
+	}
+
+	if color == "" {
+		color = defaultOptionColor
+	}
+	id := utils.NewID(utils.IDTypeNone)
+	for _, prop := range b.board.CardProperties {
+		if prop["id"] == propertyID {
+			prop["options"] = append(prop["options"].([]any), map[string]any{
+				"id":    id,
+				"value": value,
+				"color": color,
+			})
+		}
+	}
+	b.optionIDs[propertyID][key] = id
+	return id
+}
+
+// groupBy sets the select property the view of the board is grouped by.
+func (b *boardBuilder) groupBy(name string) {
+	b.groupByID = b.property(name, "select")
+}
+
+// userID returns the id of the user with the given email or username,
+// empty if there is none.
+func (b *boardBuilder) userID(username, email string) string {
+	for _, key := range []string{email, username} {
+		if key == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		id, ok := b.userIDs[key]
+		if !ok {
+			var user *model.User
+			var err error
+			if strings.Contains(key, "@") {
+				user, err = b.users.GetUserByEmail(key)
+			} else {
+				user, err = b.users.GetUserByUsername(key)
+			}
+			if err == nil && user != nil {
+				id = user.ID
+			}
+			b.userIDs[key] = id
+		}
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// addCard adds a card to the board.
+func (b *boardBuilder) addCard(title string, createAt int64) *model.Block {
+	card := b.newBlock(model.TypeCard, b.board.ID, title, createAt)
+	card.Fields = map[string]any{
+		"icon":         "",
+		"isTemplate":   false,
+		"properties":   map[string]any{},
+		"contentOrder": []any{},
+	}
+	b.blocks = append(b.blocks, card)
+	return card
+}
+
+// setSelect sets the value of a select property of a card.
+func (b *boardBuilder) setSelect(card *model.Block, name, value, color string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	propertyID := b.property(name, "select")
+	b.setValue(card, propertyID, b.option(propertyID, value, color))
+}
+
+// addMultiSelect adds a value to a multi select property of a card.
+func (b *boardBuilder) addMultiSelect(card *model.Block, name, value, color string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	propertyID := b.property(name, "multiSelect")
+	values, _ := b.cardProperties(card)[propertyID].([]any)
+	b.setValue(card, propertyID, append(values, b.option(propertyID, value, color)))
+}
+
+// setPerson sets a person property of a card to the user with the given
+// email or username, if there is one.
+func (b *boardBuilder) setPerson(card *model.Block, name, username, email string) {
+	if userID := b.userID(username, email); userID != "" {
+		b.setValue(card, b.property(name, "person"), userID)
+	}
+}
+
+// addPerson adds the user with the given email or username, if there is
+// one, to a multi person property of a card.
+func (b *boardBuilder) addPerson(card *model.Block, name, username, email string) {
+	userID := b.userID(username, email)
+	if userID == "" {
+		return
+	}
+	propertyID := b.property(name, "multiPerson")
+	values, _ := b.cardProperties(card)[propertyID].([]any)
+	b.setValue(card, propertyID, append(values, userID))
+}
+
+// setDate sets a date property of a card.
+func (b *boardBuilder) setDate(card *model.Block, name string, date time.Time) {
+	if date.IsZero() {
+		return
+	}
+	b.setValue(card, b.property(name, "date"), fmt.Sprintf(`{"from":%d}`, utils.GetMillisForTime(date)))
+}
+
+// setText sets a text property of a card.
+func (b *boardBuilder) setText(card *model.Block, name, value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	b.setValue(card, b.property(name, "text"), value)
+}
+
+// setCheckbox sets a checkbox property of a card.
+func (b *boardBuilder) setCheckbox(card *model.Block, name string, checked bool) {
+	b.setValue(card, b.property(name, "checkbox"), fmt.Sprint(checked))
+}
+
+func (b *boardBuilder) cardProperties(card *model.Block) map[string]any {
+	return card.Fields["properties"].(map[string]any)
+}
+
+func (b *boardBuilder) setValue(card *model.Block, propertyID string, value any) {
+	b.cardProperties(card)[propertyID] = value
+}
+
+// addText adds a text block to the content of a card.
+func (b *boardBuilder) addText(card *model.Block, text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	b.addContent(card, b.newBlock(model.TypeText, card.ID, text, card.CreateAt))
+}
+
+// addCheckbox adds a checkbox block to the content of a card.
+func (b *boardBuilder) addCheckbox(card *model.Block, title string, checked bool) {
+	checkbox := b.newBlock(model.TypeCheckbox, card.ID, title, card.CreateAt)
+	checkbox.Fields["value"] = checked
+	b.addContent(card, checkbox)
+}
+
+// addComment adds a comment to a card. The comments are created by the
+// user importing the board, so the author is prepended to the text.
+func (b *boardBuilder) addComment(card *model.Block, text, author string, createAt int64) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	if author != "" {
+		text = fmt.Sprintf("**%s**: %s", author, text)
+	}
+	b.blocks = append(b.blocks, b.newBlock(model.TypeComment, card.ID, text, createAt))
+}
+
+func (b *boardBuilder) addContent(card *model.Block, block *model.Block) {
+	card.Fields["contentOrder"] = append(card.Fields["contentOrder"].([]any), block.ID)
+	b.blocks = append(b.blocks, block)
+}
+
+func (b *boardBuilder) newBlock(blockType model.BlockType, parentID, title string, createAt int64) *model.Block {
+	if createAt == 0 {
+		createAt = utils.GetMillis()
+	}
+	if runes := []rune(title); len(runes) > model.BlockTitleMaxRunes {
+		title = string(runes[:model.BlockTitleMaxRunes])
+	}
+	return &model.Block{
+		ID:       utils.NewID(model.BlockType2IDType(blockType)),
+		ParentID: parentID,
+		BoardID:  b.board.ID,
+		Schema:   1,
+		Type:     blockType,
+		Title:    title,
+		Fields:   map[string]any{},
+		CreateAt: createAt,
+		UpdateAt: createAt,
+	}
+}
+
+// build returns the board with a view showing all the properties, grouped
+// by the group by property if any, followed by the blocks.
+func (b *boardBuilder) build() *model.BoardsAndBlocks {
+	visiblePropertyIDs := make([]any, 0, len(b.board.CardProperties))
+	for _, prop := range b.board.CardProperties {
+		if prop["id"] != b.groupByID {
+			visiblePropertyIDs = append(visiblePropertyIDs, prop["id"])
+		}
+	}
+
+	viewType := "board"
+	if b.groupByID == "" {
+		viewType = "table"
+	}
+	view := b.newBlock(model.TypeView, b.board.ID, importViewTitle, 0)
+	view.Fields = map[string]any{
+		"viewType":           viewType,
+		"groupById":          b.groupByID,
+		"sortOptions":        []any{},
+		"visiblePropertyIds": visiblePropertyIDs,
+		"visibleOptionIds":   []any{},
+		"hiddenOptionIds":    []any{},
+		"collapsedOptionIds": []any{},
+		"filter":             map[string]any{"operation": "and", "filters": []any{}},
+		"cardOrder":          []any{},
+		"columnWidths":       map[string]any{},
+		"columnCalculations": map[string]any{},
+		"kanbanCalculations": map[string]any{},
+		"defaultTemplateId":  "",
+	}
+
+	return &model.BoardsAndBlocks{
+		Boards: []*model.Board{b.board},
+		Blocks: append([]*model.Block{view}, b.blocks...),
+	}
+}
+
+// parseTime parses a time in one of the layouts, returning the zero time
+// if none matches.
+func parseTime(value string, layouts ...string) time.Time {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// millis returns the milliseconds of a time, zero for the zero time.
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return utils.GetMillisForTime(t)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package importers converts the boards exported by other tools into
+// boards and blocks that can be imported like the ones of an archive.
+package importers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+var errNothingToImport = errors.New("nothing to import")
+
+// UserResolver finds the users matching the people of the exports, so
+// that they can be set in person properties.
+type UserResolver interface {
+	GetUserByUsername(username string) (*model.User, error)
+	GetUserByEmail(email string) (*model.User, error)
+}
+
+// Convert reads a board exported in the given format and converts it to a
+// board with its cards, their content and comments, and a view. The ids of
+// the board and of the blocks are temporary and must be regenerated.
+func Convert(format model.ImportFormat, r io.Reader, users UserResolver) (*model.BoardsAndBlocks, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var b *boardBuilder
+	switch format {
+	case model.ImportFormatTrello:
+		b, err = convertTrello(data, users)
+	case model.ImportFormatJira:
+		b, err = convertJira(data, users)
+	case model.ImportFormatAsana:
+		b, err = convertAsana(data, users)
+	default:
+		return nil, format.IsValid()
+	}
+	if err != nil {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("cannot read %s export: %s", format, err))
+	}
+
+	return b.build(), nil
+}
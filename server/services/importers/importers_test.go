@@ -0,0 +1,340 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+type testUsers map[string]string
+
+func (u testUsers) GetUserByUsername(username string) (*model.User, error) {
+	return u.get(username)
+}
+
+func (u testUsers) GetUserByEmail(email string) (*model.User, error) {
+	return u.get(email)
+}
+
+func (u testUsers) get(key string) (*model.User, error) {
+	if id, ok := u[key]; ok {
+		return &model.User{ID: id}, nil
+	}
+	return nil, model.NewErrNotFound("user")
+}
+
+// convertedBoard gives access to the properties and the blocks of a
+// converted board by name and title.
+type convertedBoard struct {
+	t   *testing.T
+	bab *model.BoardsAndBlocks
+}
+
+func convert(t *testing.T, format model.ImportFormat, data string) convertedBoard {
+	bab, err := Convert(format, strings.NewReader(data), testUsers{"alice": "user-alice", "bob@example.com": "user-bob"})
+	require.NoError(t, err)
+	require.Len(t, bab.Boards, 1)
+	require.NoError(t, bab.IsValid())
+	require.NoError(t, model.ValidateCardPropertyTemplates(bab.Boards[0].CardProperties))
+	return convertedBoard{t: t, bab: bab}
+}
+
+func (c convertedBoard) property(name string) map[string]any {
+	for _, prop := range c.bab.Boards[0].CardProperties {
+		if prop["name"] == name {
+			return prop
+		}
+	}
+	require.Failf(c.t, "missing property", "property %q", name)
+	return nil
+}
+
+func (c convertedBoard) hasProperty(name string) bool {
+	for _, prop := range c.bab.Boards[0].CardProperties {
+		if prop["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c convertedBoard) optionValues(name string) []string {
+	var values []string
+	for _, option := range c.property(name)["options"].([]any) {
+		values = append(values, option.(map[string]any)["value"].(string))
+	}
+	return values
+}
+
+func (c convertedBoard) optionID(name, value string) string {
+	for _, option := range c.property(name)["options"].([]any) {
+		if option.(map[string]any)["value"] == value {
+			return option.(map[string]any)["id"].(string)
+		}
+	}
+	require.Failf(c.t, "missing option", "option %q of property %q", value, name)
+	return ""
+}
+
+func (c convertedBoard) value(card *model.Block, name string) any {
+	return card.Fields["properties"].(map[string]any)[c.property(name)["id"].(string)]
+}
+
+func (c convertedBoard) blocks(blockType model.BlockType) []*model.Block {
+	var blocks []*model.Block
+	for _, block := range c.bab.Blocks {
+		if block.Type == blockType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (c convertedBoard) children(parent *model.Block, blockType model.BlockType) []*model.Block {
+	var blocks []*model.Block
+	for _, block := range c.blocks(blockType) {
+		if block.ParentID == parent.ID {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (c convertedBoard) view() *model.Block {
+	views := c.blocks(model.TypeView)
+	require.Len(c.t, views, 1)
+	return views[0]
+}
+
+func TestConvertTrello(t *testing.T) {
+	data := `{
+		"name": "Roadmap",
+		"desc": "What's next",
+		"lists": [
+			{"id": "l2", "name": "Done", "pos": 2},
+			{"id": "l1", "name": "To do", "pos": 1},
+			{"id": "l3", "name": "Old", "pos": 3, "closed": true},
+			{"id": "l4", "name": "Later", "pos": 4}
+		],
+		"labels": [
+			{"id": "lb1", "name": "Bug", "color": "red"},
+			{"id": "lb2", "name": "", "color": "sky"}
+		],
+		"members": [
+			{"id": "m1", "username": "alice"},
+			{"id": "m2", "username": "unknown"}
+		],
+		"cards": [
+			{"id": "c1", "name": "Ship it", "idList": "l2", "pos": 1, "desc": "Release notes", "idLabels": ["lb1", "lb2"], "idMembers": ["m1", "m2"], "due": "2024-03-01T12:00:00.000Z"},
+			{"id": "c2", "name": "Plan", "idList": "l1", "pos": 2},
+			{"id": "c3", "name": "Archived", "idList": "l1", "pos": 3, "closed": true},
+			{"id": "c4", "name": "In closed list", "idList": "l3", "pos": 1}
+		],
+		"checklists": [
+			{"idCard": "c1", "name": "Steps", "pos": 1, "checkItems": [
+				{"name": "Tag", "state": "complete", "pos": 2},
+				{"name": "Build", "state": "incomplete", "pos": 1}
+			]}
+		],
+		"actions": [
+			{"type": "commentCard", "date": "2024-02-02T10:00:00.000Z", "data": {"text": "second", "card": {"id": "c1"}}, "memberCreator": {"username": "alice", "fullName": "Alice"}},
+			{"type": "updateCard", "data": {"card": {"id": "c1"}}},
+			{"type": "commentCard", "date": "2024-02-01T10:00:00.000Z", "data": {"text": "first", "card": {"id": "c1"}}, "memberCreator": {"username": "bob"}}
+		]
+	}`
+
+	c := convert(t, model.ImportFormatTrello, data)
+	board := c.bab.Boards[0]
+	assert.Equal(t, "Roadmap", board.Title)
+	assert.Equal(t, "What's next", board.Description)
+
+	// the open lists are the options of the group by property, in order
+	assert.Equal(t, []string{"To do", "Done", "Later"}, c.optionValues("List"))
+	assert.Equal(t, c.property("List")["id"], c.view().Fields["groupById"])
+	assert.Equal(t, "board", c.view().Fields["viewType"])
+
+	cards := c.blocks(model.TypeCard)
+	require.Len(t, cards, 2)
+	assert.Equal(t, "Plan", cards[0].Title)
+	assert.Equal(t, c.optionID("List", "To do"), c.value(cards[0], "List"))
+
+	card := cards[1]
+	assert.Equal(t, "Ship it", card.Title)
+	assert.Equal(t, c.optionID("List", "Done"), c.value(card, "List"))
+	assert.Equal(t, "multiSelect", c.property("Labels")["type"])
+	assert.Equal(t, []string{"Bug", "sky"}, c.optionValues("Labels"))
+	assert.Equal(t, "propColorRed", c.property("Labels")["options"].([]any)[0].(map[string]any)["color"])
+	assert.Equal(t, []any{c.optionID("Labels", "Bug"), c.optionID("Labels", "sky")}, c.value(card, "Labels"))
+	assert.Equal(t, "multiPerson", c.property("Members")["type"])
+	assert.Equal(t, []any{"user-alice"}, c.value(card, "Members"))
+	assert.Equal(t, `{"from":1709294400000}`, c.value(card, "Due date"))
+
+	texts := c.children(card, model.TypeText)
+	require.Len(t, texts, 2)
+	assert.Equal(t, "Release notes", texts[0].Title)
+	assert.Equal(t, "### Steps", texts[1].Title)
+	checkboxes := c.children(card, model.TypeCheckbox)
+	require.Len(t, checkboxes, 2)
+	assert.Equal(t, "Build", checkboxes[0].Title)
+	assert.Equal(t, false, checkboxes[0].Fields["value"])
+	assert.Equal(t, "Tag", checkboxes[1].Title)
+	assert.Equal(t, true, checkboxes[1].Fields["value"])
+	assert.Equal(t, []any{texts[0].ID, texts[1].ID, checkboxes[0].ID, checkboxes[1].ID}, card.Fields["contentOrder"])
+
+	comments := c.children(card, model.TypeComment)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "**bob**: first", comments[0].Title)
+	assert.Equal(t, "**Alice**: second", comments[1].Title)
+}
+
+func TestConvertJira(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		data := `{"issues": [
+			{"key": "PRJ-1", "fields": {
+				"summary": "Login fails",
+				"description": {"type": "doc", "content": [
+					{"type": "paragraph", "content": [{"type": "text", "text": "Steps"}]},
+					{"type": "paragraph", "content": [{"type": "text", "text": "to reproduce"}]}
+				]},
+				"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+				"priority": {"name": "High"},
+				"issuetype": {"name": "Bug"},
+				"project": {"name": "Project"},
+				"labels": ["backend", "auth"],
+				"assignee": {"emailAddress": "bob@example.com", "displayName": "Bob"},
+				"reporter": {"name": "alice", "displayName": "Alice"},
+				"duedate": "2024-05-01",
+				"comment": {"comments": [{"author": {"displayName": "Alice"}, "body": "Confirmed"}]},
+				"subtasks": [
+					{"fields": {"summary": "Fix", "status": {"name": "Done", "statusCategory": {"key": "done"}}}},
+					{"fields": {"summary": "Test", "status": {"name": "To Do", "statusCategory": {"key": "new"}}}}
+				]
+			}},
+			{"key": "PRJ-2", "fields": {"summary": "Docs", "status": {"name": "Done", "statusCategory": {"key": "done"}}}}
+		]}`
+
+		c := convert(t, model.ImportFormatJira, data)
+		assert.Equal(t, "Project", c.bab.Boards[0].Title)
+		assert.Equal(t, []string{"In Progress", "Done"}, c.optionValues("Status"))
+		assert.Equal(t, "propColorGreen", c.property("Status")["options"].([]any)[1].(map[string]any)["color"])
+		assert.Equal(t, c.property("Status")["id"], c.view().Fields["groupById"])
+
+		cards := c.blocks(model.TypeCard)
+		require.Len(t, cards, 2)
+		card := cards[0]
+		assert.Equal(t, "Login fails", card.Title)
+		assert.Equal(t, c.optionID("Status", "In Progress"), c.value(card, "Status"))
+		assert.Equal(t, c.optionID("Priority", "High"), c.value(card, "Priority"))
+		assert.Equal(t, c.optionID("Type", "Bug"), c.value(card, "Type"))
+		assert.Equal(t, []any{c.optionID("Labels", "backend"), c.optionID("Labels", "auth")}, c.value(card, "Labels"))
+		assert.Equal(t, "user-bob", c.value(card, "Assignee"))
+		assert.Equal(t, "user-alice", c.value(card, "Reporter"))
+		assert.Equal(t, `{"from":1714521600000}`, c.value(card, "Due date"))
+		assert.Equal(t, "PRJ-1", c.value(card, "Key"))
+
+		texts := c.children(card, model.TypeText)
+		require.Len(t, texts, 1)
+		assert.Equal(t, "Steps\nto reproduce", texts[0].Title)
+		checkboxes := c.children(card, model.TypeCheckbox)
+		require.Len(t, checkboxes, 2)
+		assert.Equal(t, true, checkboxes[0].Fields["value"])
+		assert.Equal(t, false, checkboxes[1].Fields["value"])
+		comments := c.children(card, model.TypeComment)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "**Alice**: Confirmed", comments[0].Title)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		data := "\ufeffSummary,Issue key,Issue Type,Status,Priority,Assignee,Due Date,Labels,Labels,Description,Comment,Project name\n" +
+			"Login fails,PRJ-1,Bug,To Do,High,alice,01/May/24 12:00 AM,backend,auth,Steps,\"01/Apr/24 10:00 AM;abc;Confirmed\",Project\n" +
+			"Docs,PRJ-2,Task,Done,,,,,,,,Project\n"
+
+		c := convert(t, model.ImportFormatJira, data)
+		assert.Equal(t, "Project", c.bab.Boards[0].Title)
+		assert.Equal(t, []string{"To Do", "Done"}, c.optionValues("Status"))
+
+		cards := c.blocks(model.TypeCard)
+		require.Len(t, cards, 2)
+		card := cards[0]
+		assert.Equal(t, "Login fails", card.Title)
+		assert.Equal(t, "PRJ-1", c.value(card, "Key"))
+		assert.Equal(t, []any{c.optionID("Labels", "backend"), c.optionID("Labels", "auth")}, c.value(card, "Labels"))
+		assert.Equal(t, "user-alice", c.value(card, "Assignee"))
+		assert.Equal(t, `{"from":1714521600000}`, c.value(card, "Due date"))
+		require.Len(t, c.children(card, model.TypeText), 1)
+		comments := c.children(card, model.TypeComment)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "Confirmed", comments[0].Title)
+	})
+}
+
+func TestConvertAsana(t *testing.T) {
+	data := `{"data": [
+		{
+			"name": "Write brief",
+			"notes": "Two pages",
+			"completed": true,
+			"due_on": "2024-06-10",
+			"assignee": {"name": "Bob", "email": "bob@example.com"},
+			"memberships": [{"project": {"name": "Launch"}, "section": {"name": "Doing"}}],
+			"tags": [{"name": "marketing"}],
+			"subtasks": [{"name": "Outline", "completed": true}],
+			"stories": [
+				{"type": "system", "text": "assigned"},
+				{"type": "comment", "text": "Looks good", "created_by": {"name": "Alice"}}
+			]
+		},
+		{"name": "Review", "memberships": [{"project": {"name": "Launch"}, "section": {"name": "To do"}}]}
+	]}`
+
+	c := convert(t, model.ImportFormatAsana, data)
+	assert.Equal(t, "Launch", c.bab.Boards[0].Title)
+	assert.Equal(t, []string{"Doing", "To do"}, c.optionValues("Section"))
+	assert.Equal(t, c.property("Section")["id"], c.view().Fields["groupById"])
+
+	cards := c.blocks(model.TypeCard)
+	require.Len(t, cards, 2)
+	card := cards[0]
+	assert.Equal(t, "Write brief", card.Title)
+	assert.Equal(t, c.optionID("Section", "Doing"), c.value(card, "Section"))
+	assert.Equal(t, "true", c.value(card, "Completed"))
+	assert.Equal(t, "false", c.value(cards[1], "Completed"))
+	assert.Equal(t, []any{c.optionID("Tags", "marketing")}, c.value(card, "Tags"))
+	assert.Equal(t, "user-bob", c.value(card, "Assignee"))
+	assert.Equal(t, `{"from":1717977600000}`, c.value(card, "Due date"))
+
+	texts := c.children(card, model.TypeText)
+	require.Len(t, texts, 1)
+	assert.Equal(t, "Two pages", texts[0].Title)
+	require.Len(t, c.children(card, model.TypeCheckbox), 1)
+	comments := c.children(card, model.TypeComment)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "**Alice**: Looks good", comments[0].Title)
+}
+
+func TestConvertInvalid(t *testing.T) {
+	users := testUsers{}
+
+	_, err := Convert(model.ImportFormat("monday"), strings.NewReader("{}"), users)
+	require.True(t, model.IsErrBadRequest(err))
+
+	for _, format := range []model.ImportFormat{model.ImportFormatTrello, model.ImportFormatJira, model.ImportFormatAsana} {
+		_, err = Convert(format, strings.NewReader("{not json"), users)
+		require.True(t, model.IsErrBadRequest(err), format)
+
+		_, err = Convert(format, strings.NewReader("{}"), users)
+		require.True(t, model.IsErrBadRequest(err), format)
+	}
+
+	// a board without optional properties does not have them
+	c := convert(t, model.ImportFormatTrello, `{"name": "Empty", "lists": [{"id": "l1", "name": "To do"}]}`)
+	assert.False(t, c.hasProperty("Labels"))
+	assert.Empty(t, c.blocks(model.TypeCard))
+}
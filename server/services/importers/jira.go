@@ -0,0 +1,245 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var jiraDateLayouts = []string{"2006-01-02", "02/Jan/06 3:04 PM", "02/Jan/06", time.RFC3339, "2006-01-02T15:04:05.000-0700"}
+
+type jiraExport struct {
+	Issues []jiraIssue `json:"issues"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Status      jiraStatus      `json:"status"`
+		Priority    jiraNamed       `json:"priority"`
+		IssueType   jiraNamed       `json:"issuetype"`
+		Project     jiraNamed       `json:"project"`
+		Labels      []string        `json:"labels"`
+		Assignee    *jiraUser       `json:"assignee"`
+		Reporter    *jiraUser       `json:"reporter"`
+		DueDate     string          `json:"duedate"`
+		Created     string          `json:"created"`
+		Comment     struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+		Subtasks []struct {
+			Fields struct {
+				Summary string     `json:"summary"`
+				Status  jiraStatus `json:"status"`
+			} `json:"fields"`
+		} `json:"subtasks"`
+	} `json:"fields"`
+}
+
+type jiraNamed struct {
+	Name string `json:"name"`
+}
+
+type jiraStatus struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type jiraUser struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+type jiraComment struct {
+	Author  *jiraUser       `json:"author"`
+	Body    json.RawMessage `json:"body"`
+	Created string          `json:"created"`
+}
+
+// jiraStatusColors maps the status categories of Jira to the colors of the
+// property options.
+var jiraStatusColors = map[string]string{
+	"new":           "propColorGray",
+	"indeterminate": "propColorBlue",
+	"done":          "propColorGreen",
+}
+
+// convertJira converts the issues exported from Jira, either as the JSON
+// of a search of the REST API or as CSV. The statuses are the options of
+// the select property the view is grouped by.
+func convertJira(data []byte, users UserResolver) (*boardBuilder, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return convertJiraJSON(trimmed, users)
+	}
+	return convertJiraCSV(data, users)
+}
+
+func convertJiraJSON(data []byte, users UserResolver) (*boardBuilder, error) {
+	var export jiraExport
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &export.Issues); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if len(export.Issues) == 0 {
+		return nil, errNothingToImport
+	}
+
+	b := newBoardBuilder(export.Issues[0].Fields.Project.Name, "", users)
+	b.groupBy("Status")
+	for _, issue := range export.Issues {
+		fields := issue.Fields
+		card := b.addCard(fields.Summary, millis(parseTime(fields.Created, jiraDateLayouts...)))
+		b.setSelect(card, "Status", fields.Status.Name, jiraStatusColors[fields.Status.StatusCategory.Key])
+		b.setSelect(card, "Priority", fields.Priority.Name, "")
+		b.setSelect(card, "Type", fields.IssueType.Name, "")
+		for _, label := range fields.Labels {
+			b.addMultiSelect(card, "Labels", label, "")
+		}
+		if fields.Assignee != nil {
+			b.setPerson(card, "Assignee", fields.Assignee.Name, fields.Assignee.EmailAddress)
+		}
+		if fields.Reporter != nil {
+			b.setPerson(card, "Reporter", fields.Reporter.Name, fields.Reporter.EmailAddress)
+		}
+		b.setDate(card, "Due date", parseTime(fields.DueDate, jiraDateLayouts...))
+		b.setText(card, "Key", issue.Key)
+
+		b.addText(card, jiraText(fields.Description))
+		for _, subtask := range fields.Subtasks {
+			b.addCheckbox(card, subtask.Fields.Summary, subtask.Fields.Status.StatusCategory.Key == "done")
+		}
+		for _, comment := range fields.Comment.Comments {
+			author := ""
+			if comment.Author != nil {
+				author = comment.Author.DisplayName
+			}
+			b.addComment(card, jiraText(comment.Body), author, millis(parseTime(comment.Created, jiraDateLayouts...)))
+		}
+	}
+
+	return b, nil
+}
+
+// jiraText returns the text of a description or of a comment, which is
+// either a string or a document of the Atlassian Document Format.
+func jiraText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var doc jiraDocNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	doc.writeText(&sb)
+	return strings.TrimSpace(sb.String())
+}
+
+type jiraDocNode struct {
+	Type    string        `json:"type"`
+	Text    string        `json:"text"`
+	Content []jiraDocNode `json:"content"`
+}
+
+func (n jiraDocNode) writeText(sb *strings.Builder) {
+	switch n.Type {
+	case "text":
+		sb.WriteString(n.Text)
+	case "hardBreak":
+		sb.WriteString("\n")
+	}
+	for _, child := range n.Content {
+		child.writeText(sb)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "listItem", "codeBlock", "blockquote":
+		sb.WriteString("\n")
+	}
+}
+
+// convertJiraCSV converts the issues of a CSV export. The labels and the
+// comments are repeated in several columns with the same header, and the
+// comments are formatted as "date;author id;body".
+func convertJiraCSV(data []byte, users UserResolver) (*boardBuilder, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, errNothingToImport
+	}
+
+	columns := map[string][]int{}
+	for i, header := range records[0] {
+		name := strings.ToLower(strings.TrimSpace(header))
+		columns[name] = append(columns[name], i)
+	}
+	if _, ok := columns["summary"]; !ok {
+		return nil, errors.New("missing Summary column")
+	}
+	values := func(record []string, name string) []string {
+		var result []string
+		for _, i := range columns[name] {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				result = append(result, strings.TrimSpace(record[i]))
+			}
+		}
+		return result
+	}
+	value := func(record []string, name string) string {
+		if v := values(record, name); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	b := newBoardBuilder(value(records[1], "project name"), "", users)
+	b.groupBy("Status")
+	for _, record := range records[1:] {
+		card := b.addCard(value(record, "summary"), millis(parseTime(value(record, "created"), jiraDateLayouts...)))
+		b.setSelect(card, "Status", value(record, "status"), "")
+		b.setSelect(card, "Priority", value(record, "priority"), "")
+		b.setSelect(card, "Type", value(record, "issue type"), "")
+		for _, label := range values(record, "labels") {
+			b.addMultiSelect(card, "Labels", label, "")
+		}
+		b.setPerson(card, "Assignee", value(record, "assignee"), "")
+		b.setPerson(card, "Reporter", value(record, "reporter"), "")
+		b.setDate(card, "Due date", parseTime(value(record, "due date"), jiraDateLayouts...))
+		b.setText(card, "Key", value(record, "issue key"))
+
+		b.addText(card, value(record, "description"))
+		for _, comment := range values(record, "comment") {
+			parts := strings.SplitN(comment, ";", 3)
+			if len(parts) < 3 {
+				b.addComment(card, comment, "", 0)
+				continue
+			}
+			b.addComment(card, parts[2], "", millis(parseTime(parts[0], jiraDateLayouts...)))
+		}
+	}
+
+	return b, nil
+}
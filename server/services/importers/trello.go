@@ -0,0 +1,194 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importers
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// trelloColors maps the colors of the Trello labels to the colors of the
+// property options.
+var trelloColors = map[string]string{
+	"green":  "propColorGreen",
+	"yellow": "propColorYellow",
+	"orange": "propColorOrange",
+	"red":    "propColorRed",
+	"purple": "propColorPurple",
+	"blue":   "propColorBlue",
+	"sky":    "propColorBlue",
+	"lime":   "propColorGreen",
+	"pink":   "propColorPink",
+	"black":  "propColorGray",
+}
+
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Desc       string            `json:"desc"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Labels     []trelloLabel     `json:"labels"`
+	Members    []trelloMember    `json:"members"`
+	Checklists []trelloChecklist `json:"checklists"`
+	Actions    []trelloAction    `json:"actions"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Desc      string   `json:"desc"`
+	Closed    bool     `json:"closed"`
+	IDList    string   `json:"idList"`
+	IDLabels  []string `json:"idLabels"`
+	IDMembers []string `json:"idMembers"`
+	Due       string   `json:"due"`
+	Pos       float64  `json:"pos"`
+}
+
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloMember struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+}
+
+type trelloChecklist struct {
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+type trelloAction struct {
+	Type string `json:"type"`
+	Date string `json:"date"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+	MemberCreator trelloMember `json:"memberCreator"`
+}
+
+// convertTrello converts the JSON export of a Trello board. The lists are
+// the options of the select property the view is grouped by, and the
+// archived lists and cards are skipped.
+func convertTrello(data []byte, users UserResolver) (*boardBuilder, error) {
+	var tb trelloBoard
+	if err := json.Unmarshal(data, &tb); err != nil {
+		return nil, err
+	}
+	if tb.Name == "" && len(tb.Lists) == 0 && len(tb.Cards) == 0 {
+		return nil, errNothingToImport
+	}
+
+	b := newBoardBuilder(tb.Name, tb.Desc, users)
+	b.groupBy("List")
+
+	lists := make(map[string]trelloList, len(tb.Lists))
+	for _, list := range tb.Lists {
+		lists[list.ID] = list
+	}
+	sort.SliceStable(tb.Lists, func(i, j int) bool { return tb.Lists[i].Pos < tb.Lists[j].Pos })
+	for _, list := range tb.Lists {
+		if !list.Closed {
+			b.option(b.groupByID, list.Name, "")
+		}
+	}
+
+	labels := make(map[string]trelloLabel, len(tb.Labels))
+	for _, label := range tb.Labels {
+		labels[label.ID] = label
+	}
+	members := make(map[string]trelloMember, len(tb.Members))
+	for _, member := range tb.Members {
+		members[member.ID] = member
+	}
+
+	checklists := map[string][]trelloChecklist{}
+	for _, checklist := range tb.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+	comments := map[string][]trelloAction{}
+	for _, action := range tb.Actions {
+		if action.Type == "commentCard" {
+			comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], action)
+		}
+	}
+
+	sort.SliceStable(tb.Cards, func(i, j int) bool {
+		li, lj := lists[tb.Cards[i].IDList], lists[tb.Cards[j].IDList]
+		if li.Pos != lj.Pos {
+			return li.Pos < lj.Pos
+		}
+		return tb.Cards[i].Pos < tb.Cards[j].Pos
+	})
+	for _, tc := range tb.Cards {
+		list, ok := lists[tc.IDList]
+		if tc.Closed || (ok && list.Closed) {
+			continue
+		}
+
+		card := b.addCard(tc.Name, 0)
+		b.setSelect(card, "List", list.Name, "")
+		for _, labelID := range tc.IDLabels {
+			label := labels[labelID]
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			b.addMultiSelect(card, "Labels", name, trelloColors[label.Color])
+		}
+		for _, memberID := range tc.IDMembers {
+			b.addPerson(card, "Members", members[memberID].Username, "")
+		}
+		b.setDate(card, "Due date", parseTime(tc.Due, time.RFC3339))
+
+		b.addText(card, tc.Desc)
+
+		cardChecklists := checklists[tc.ID]
+		sort.SliceStable(cardChecklists, func(i, j int) bool { return cardChecklists[i].Pos < cardChecklists[j].Pos })
+		for _, checklist := range cardChecklists {
+			b.addText(card, "### "+checklist.Name)
+			items := checklist.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				b.addCheckbox(card, item.Name, item.State == "complete")
+			}
+		}
+
+		// the actions are exported from the most recent
+		cardComments := comments[tc.ID]
+		for i := len(cardComments) - 1; i >= 0; i-- {
+			comment := cardComments[i]
+			author := comment.MemberCreator.FullName
+			if author == "" {
+				author = comment.MemberCreator.Username
+			}
+			b.addComment(card, comment.Data.Text, author, millis(parseTime(comment.Date, time.RFC3339)))
+		}
+	}
+
+	return b, nil
+}
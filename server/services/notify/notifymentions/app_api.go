@@ -6,5 +6,6 @@ import "github.com/mattermost/mattermost-plugin-boards/server/model"
 
 type AppAPI interface {
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
+	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 	AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, error)
 }
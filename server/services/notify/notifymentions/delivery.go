@@ -15,4 +15,6 @@ import (
 type MentionDelivery interface {
 	MentionDeliver(mentionedUser *mm_model.User, extract string, evt notify.BlockChangeEvent) (string, error)
 	UserByUsername(mentionUsername string) (*mm_model.User, error)
+	GroupMembersByName(groupName string) ([]*mm_model.User, error)
+	OnlineUserIDs(userIDs []string) ([]string, error)
 }
@@ -13,11 +13,18 @@ import (
 	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
 	"github.com/wiggin77/merror"
 
+	mm_model "github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 const (
 	backendName = "notifyMentions"
+
+	// boardMention notifies all the members of the board.
+	boardMention = "board"
+
+	// hereMention notifies the members of the board that are online.
+	hereMention = "here"
 )
 
 var (
@@ -116,12 +123,22 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 	copy(listeners, b.listeners)
 	b.mux.RUnlock()
 
+	// users mentioned directly are notified first, so that users also
+	// mentioned through a group are only notified once.
+	notified := make(map[string]struct{})
+	var groupMentions []string
+
 	for username := range mentions {
 		if _, exists := oldMentions[username]; exists {
 			// the mention already existed; no need to notify again
 			continue
 		}
 
+		if username == boardMention || username == hereMention {
+			groupMentions = append(groupMentions, username)
+			continue
+		}
+
 		extract := extractText(evt.BlockChanged.Title, username, newLimits())
 
 		userID, err := b.deliverMentionNotification(username, extract, evt)
@@ -131,12 +148,15 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 			} else {
 				merr.Append(fmt.Errorf("cannot deliver notification for @%s: %w", username, err))
 			}
+			continue
 		}
 
 		if userID == "" {
-			// was a `@` followed by something other than a username.
+			// was a `@` followed by something other than a username; could be a user group.
+			groupMentions = append(groupMentions, username)
 			continue
 		}
+		notified[userID] = struct{}{}
 
 		b.logger.Debug("Mention notification delivered",
 			mlog.String("user", username),
@@ -147,6 +167,35 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 			safeCallListener(listener, userID, evt, b.logger)
 		}
 	}
+
+	for _, mention := range groupMentions {
+		extract := extractText(evt.BlockChanged.Title, mention, newLimits())
+
+		userIDs, err := b.deliverGroupMentionNotification(mention, extract, evt, notified)
+		if err != nil {
+			if errors.Is(err, ErrMentionPermission) {
+				b.logger.Debug("Cannot deliver group notification", mlog.String("group", mention), mlog.Err(err))
+			} else {
+				merr.Append(fmt.Errorf("cannot deliver notification for @%s: %w", mention, err))
+			}
+		}
+
+		if len(userIDs) == 0 {
+			continue
+		}
+
+		b.logger.Debug("Group mention notification delivered",
+			mlog.String("group", mention),
+			mlog.Int("user_count", len(userIDs)),
+			mlog.Int("listener_count", len(listeners)),
+		)
+
+		for _, userID := range userIDs {
+			for _, listener := range listeners {
+				safeCallListener(listener, userID, evt, b.logger)
+			}
+		}
+	}
 	return merr.ErrorOrNil()
 }
 
@@ -239,3 +288,86 @@ func (b *Backend) deliverMentionNotification(username string, extract string, ev
 
 	return b.delivery.MentionDeliver(mentionedUser, extract, evt)
 }
+
+// deliverGroupMentionNotification notifies the users mentioned through `@board`, `@here` or a
+// user group. The author, users already notified and users that cannot view the board are
+// skipped. The ids of the users notified are returned.
+func (b *Backend) deliverGroupMentionNotification(mention string, extract string, evt notify.BlockChangeEvent,
+	notified map[string]struct{}) ([]string, error) {
+	if evt.ModifiedBy == nil {
+		return nil, fmt.Errorf("invalid user cannot mention: %w", ErrMentionPermission)
+	}
+
+	recipients, err := b.getGroupMentionRecipients(mention, evt.Board.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	if evt.ModifiedBy.SchemeViewer && !evt.ModifiedBy.SchemeCommenter && !evt.ModifiedBy.SchemeEditor && !evt.ModifiedBy.SchemeAdmin {
+		// viewer should not have gotten this far since they cannot add text to a card
+		return nil, fmt.Errorf("%s (viewer) cannot mention @%s: %w", evt.ModifiedBy.UserID, mention, ErrMentionPermission)
+	}
+
+	var userIDs []string
+	merr := merror.New()
+	for _, userID := range recipients {
+		if _, ok := notified[userID]; ok || userID == evt.ModifiedBy.UserID {
+			continue
+		}
+
+		// group mentions never add users to the board.
+		if !b.permissions.HasPermissionToBoard(userID, evt.Board.ID, model.PermissionViewBoard) {
+			b.logger.Debug("skipping group mention of non-board member",
+				mlog.String("group", mention),
+				mlog.String("user_id", userID),
+				mlog.String("board_id", evt.Board.ID),
+			)
+			continue
+		}
+
+		if _, err := b.delivery.MentionDeliver(&mm_model.User{Id: userID}, extract, evt); err != nil {
+			merr.Append(fmt.Errorf("cannot deliver notification to user %s: %w", userID, err))
+			continue
+		}
+		notified[userID] = struct{}{}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, merr.ErrorOrNil()
+}
+
+// getGroupMentionRecipients returns the ids of the users a group mention refers to,
+// or none if the mention is not a group.
+func (b *Backend) getGroupMentionRecipients(mention string, boardID string) ([]string, error) {
+	switch mention {
+	case boardMention, hereMention:
+		members, err := b.appAPI.GetMembersForBoard(boardID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get members of board %s: %w", boardID, err)
+		}
+		userIDs := make([]string, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+		if mention == hereMention {
+			return b.delivery.OnlineUserIDs(userIDs)
+		}
+		return userIDs, nil
+	default:
+		users, err := b.delivery.GroupMembersByName(mention)
+		if err != nil {
+			if model.IsErrNotFound(err) {
+				// not really an error; could just be someone typed "@sometext"
+				return nil, nil
+			}
+			return nil, fmt.Errorf("cannot lookup mentioned group: %w", err)
+		}
+		userIDs := make([]string, 0, len(users))
+		for _, user := range users {
+			userIDs = append(userIDs, user.Id)
+		}
+		return userIDs, nil
+	}
+}
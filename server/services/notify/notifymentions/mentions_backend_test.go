@@ -0,0 +1,199 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifymentions
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/stretchr/testify/require"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testAppAPI struct {
+	members []*model.BoardMember
+}
+
+func (a *testAppAPI) GetMemberForBoard(boardID, userID string) (*model.BoardMember, error) {
+	for _, member := range a.members {
+		if member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, model.NewErrNotFound(userID)
+}
+
+func (a *testAppAPI) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	return a.members, nil
+}
+
+func (a *testAppAPI) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, error) {
+	a.members = append(a.members, member)
+	return member, nil
+}
+
+type testPermissions struct {
+	boardMembers map[string]bool
+}
+
+func (p *testPermissions) HasPermissionTo(userID string, permission *mm_model.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToTeam(userID, teamID string, permission *mm_model.Permission) bool {
+	return true
+}
+
+func (p *testPermissions) HasPermissionToChannel(userID, channelID string, permission *mm_model.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToBoard(userID, boardID string, permission *mm_model.Permission) bool {
+	return p.boardMembers[userID]
+}
+
+type testDelivery struct {
+	users    map[string]*mm_model.User
+	groups   map[string][]*mm_model.User
+	online   map[string]bool
+	extracts map[string]string
+}
+
+func (d *testDelivery) MentionDeliver(mentionedUser *mm_model.User, extract string, evt notify.BlockChangeEvent) (string, error) {
+	d.extracts[mentionedUser.Id] = extract
+	return mentionedUser.Id, nil
+}
+
+func (d *testDelivery) UserByUsername(username string) (*mm_model.User, error) {
+	user, ok := d.users[username]
+	if !ok {
+		return nil, model.NewErrNotFound(username)
+	}
+	return user, nil
+}
+
+func (d *testDelivery) GroupMembersByName(groupName string) ([]*mm_model.User, error) {
+	members, ok := d.groups[groupName]
+	if !ok {
+		return nil, model.NewErrNotFound(groupName)
+	}
+	return members, nil
+}
+
+func (d *testDelivery) OnlineUserIDs(userIDs []string) ([]string, error) {
+	var online []string
+	for _, userID := range userIDs {
+		if d.online[userID] {
+			online = append(online, userID)
+		}
+	}
+	return online, nil
+}
+
+type testListener struct {
+	userIDs []string
+}
+
+func (l *testListener) OnMention(userID string, evt notify.BlockChangeEvent) {
+	l.userIDs = append(l.userIDs, userID)
+}
+
+func TestBlockChangedGroupMentions(t *testing.T) {
+	users := map[string]*mm_model.User{
+		"author":   {Id: "author-id", Username: "author"},
+		"member-1": {Id: "member-1-id", Username: "member-1"},
+		"member-2": {Id: "member-2-id", Username: "member-2"},
+		"outsider": {Id: "outsider-id", Username: "outsider"},
+	}
+
+	newTest := func() (*Backend, *testDelivery, *testListener) {
+		delivery := &testDelivery{
+			users: users,
+			groups: map[string][]*mm_model.User{
+				"developers": {users["member-2"], users["outsider"], users["author"]},
+			},
+			online:   map[string]bool{"member-1-id": true, "author-id": true},
+			extracts: map[string]string{},
+		}
+		backend := New(BackendParams{
+			AppAPI: &testAppAPI{members: []*model.BoardMember{
+				{UserID: "author-id", SchemeEditor: true},
+				{UserID: "member-1-id", SchemeViewer: true},
+				{UserID: "member-2-id", SchemeViewer: true},
+			}},
+			Permissions: &testPermissions{boardMembers: map[string]bool{"author-id": true, "member-1-id": true, "member-2-id": true}},
+			Delivery:    delivery,
+			Logger:      mlog.CreateConsoleTestLogger(t),
+		})
+		listener := &testListener{}
+		backend.AddListener(listener)
+		return backend, delivery, listener
+	}
+
+	makeEvent := func(text string, modifiedBy *model.BoardMember) notify.BlockChangeEvent {
+		return notify.BlockChangeEvent{
+			Action:       notify.Add,
+			TeamID:       "team-id",
+			Board:        &model.Board{ID: "board-id", Type: model.BoardTypePrivate},
+			Card:         &model.Block{ID: "card-id", Type: model.TypeCard},
+			BlockChanged: &model.Block{ID: "comment-id", Type: model.TypeComment, Title: text},
+			ModifiedBy:   modifiedBy,
+		}
+	}
+	author := &model.BoardMember{UserID: "author-id", SchemeEditor: true}
+
+	sorted := func(userIDs []string) []string {
+		sort.Strings(userIDs)
+		return userIDs
+	}
+
+	t.Run("@board notifies all board members but the author", func(t *testing.T) {
+		backend, delivery, listener := newTest()
+
+		require.NoError(t, backend.BlockChanged(makeEvent("Please review @board", author)))
+		require.Equal(t, []string{"member-1-id", "member-2-id"}, sorted(listener.userIDs))
+		require.Equal(t, "Please review @board", delivery.extracts["member-1-id"])
+	})
+
+	t.Run("@here notifies the online board members", func(t *testing.T) {
+		backend, _, listener := newTest()
+
+		require.NoError(t, backend.BlockChanged(makeEvent("Anyone around @here?", author)))
+		require.Equal(t, []string{"member-1-id"}, listener.userIDs)
+	})
+
+	t.Run("user group members need access to the board", func(t *testing.T) {
+		backend, delivery, listener := newTest()
+
+		require.NoError(t, backend.BlockChanged(makeEvent("Ping @developers", author)))
+		require.Equal(t, []string{"member-2-id"}, listener.userIDs)
+		require.NotContains(t, delivery.extracts, "outsider-id")
+	})
+
+	t.Run("users are notified once", func(t *testing.T) {
+		backend, _, listener := newTest()
+
+		require.NoError(t, backend.BlockChanged(makeEvent("@member-2 and @board and @developers", author)))
+		require.Equal(t, []string{"member-1-id", "member-2-id"}, sorted(listener.userIDs))
+	})
+
+	t.Run("unknown mentions are ignored", func(t *testing.T) {
+		backend, _, listener := newTest()
+
+		require.NoError(t, backend.BlockChanged(makeEvent("Hello @nobody", author)))
+		require.Empty(t, listener.userIDs)
+	})
+
+	t.Run("viewers cannot mention groups", func(t *testing.T) {
+		backend, _, listener := newTest()
+
+		viewer := &model.BoardMember{UserID: "member-1-id", SchemeViewer: true}
+		require.NoError(t, backend.BlockChanged(makeEvent("Hello @board", viewer)))
+		require.Empty(t, listener.userIDs)
+	})
+}
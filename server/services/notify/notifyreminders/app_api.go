@@ -0,0 +1,18 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyreminders
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+type AppAPI interface {
+	GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error)
+	MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error)
+	DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error)
+
+	GetBoard(boardID string) (*model.Board, error)
+	GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error)
+	GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error)
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyreminders
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+)
+
+// ReminderDelivery provides an interface for delivering due date reminders to other systems, such as
+// channels server via plugin API.
+type ReminderDelivery interface {
+	DueDateReminderDeliver(userID string, evt notify.DueDateReminderEvent) error
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyreminders
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backendName = "notifyReminders"
+
+	defReminderScanFrequency = time.Minute * 5
+
+	// reminders due longer ago are not sent, e.g. when the server was down.
+	reminderMaxDelay = time.Hour * 24
+
+	// sent reminders are kept until they can no longer be due.
+	reminderRetention = reminderMaxDelay * 2
+)
+
+type BackendParams struct {
+	AppAPI        AppAPI
+	Permissions   permissions.PermissionsService
+	Delivery      ReminderDelivery
+	Logger        mlog.LoggerIFace
+	ScanFrequency time.Duration
+}
+
+// Backend sends reminders when the due dates of cards arrive. Every server
+// looks for due reminders, and the sent state stored before delivering
+// makes each reminder be sent by only one of them.
+type Backend struct {
+	appAPI        AppAPI
+	permissions   permissions.PermissionsService
+	delivery      ReminderDelivery
+	logger        mlog.LoggerIFace
+	scanFrequency time.Duration
+
+	mux  sync.Mutex
+	done chan struct{}
+}
+
+func New(params BackendParams) *Backend {
+	scanFrequency := params.ScanFrequency
+	if scanFrequency <= 0 {
+		scanFrequency = defReminderScanFrequency
+	}
+
+	return &Backend{
+		appAPI:        params.AppAPI,
+		permissions:   params.Permissions,
+		delivery:      params.Delivery,
+		logger:        params.Logger,
+		scanFrequency: scanFrequency,
+	}
+}
+
+func (b *Backend) Start() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.logger.Debug("Starting reminders backend", mlog.Duration("scan_frequency", b.scanFrequency))
+
+	if b.done == nil {
+		b.done = make(chan struct{})
+		go b.loop(b.done)
+	}
+	return nil
+}
+
+func (b *Backend) ShutDown() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.logger.Debug("Stopping reminders backend")
+
+	if b.done != nil {
+		close(b.done)
+		b.done = nil
+	}
+	_ = b.logger.Flush()
+	return nil
+}
+
+// BlockChanged is a no-op, due dates are checked periodically.
+func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
+	return nil
+}
+
+func (b *Backend) Name() string {
+	return backendName
+}
+
+func (b *Backend) loop(done chan struct{}) {
+	ticker := time.NewTicker(b.scanFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			b.safeSendDueReminders()
+		}
+	}
+}
+
+func (b *Backend) safeSendDueReminders() {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("panic recovered in reminders loop",
+				mlog.Any("panic", r),
+				mlog.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+	b.sendDueReminders(utils.GetMillis())
+}
+
+// sendDueReminders sends the reminders of the boards with reminders enabled
+// that are due at `now`.
+func (b *Backend) sendDueReminders(now int64) {
+	settingsList, err := b.appAPI.GetEnabledDueDateReminderSettings()
+	if err != nil {
+		b.logger.Error("Cannot get due date reminder settings", mlog.Err(err))
+		return
+	}
+
+	for _, settings := range settingsList {
+		if err := b.sendBoardReminders(settings, now); err != nil {
+			b.logger.Error("Error sending due date reminders",
+				mlog.String("board_id", settings.BoardID),
+				mlog.Err(err),
+			)
+		}
+	}
+
+	if _, err := b.appAPI.DeleteDueDateRemindersSentBefore(now - reminderRetention.Milliseconds()); err != nil {
+		b.logger.Error("Cannot delete sent due date reminders", mlog.Err(err))
+	}
+}
+
+func (b *Backend) sendBoardReminders(settings *model.DueDateReminderSettings, now int64) error {
+	board, err := b.appAPI.GetBoard(settings.BoardID)
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if board.IsTemplate || board.DeleteAt != 0 {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	cards, err := b.appAPI.GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard})
+	if err != nil {
+		return err
+	}
+
+	merr := merror.New()
+	for _, card := range cards {
+		// card templates aren't due
+		if isTemplate, _ := card.Fields["isTemplate"].(bool); isTemplate {
+			continue
+		}
+
+		properties, _ := card.Fields["properties"].(map[string]any)
+		for _, propID := range settings.PropertyIDs {
+			dueAt, ok := model.GetDueDate(properties[propID])
+			if !ok {
+				continue
+			}
+
+			for _, offset := range settings.Offsets {
+				remindAt := dueAt + (time.Duration(offset) * time.Minute).Milliseconds()
+				// reminders due before they were configured are not sent
+				if remindAt > now || remindAt < settings.UpdateAt || now-remindAt > reminderMaxDelay.Milliseconds() {
+					continue
+				}
+
+				marked, err := b.appAPI.MarkDueDateReminderSent(&model.DueDateReminder{
+					CardID:     card.ID,
+					PropertyID: propID,
+					Offset:     offset,
+					DueAt:      dueAt,
+					SentAt:     now,
+				})
+				if err != nil {
+					merr.Append(err)
+					continue
+				}
+				if !marked {
+					// already sent, possibly by another server
+					continue
+				}
+
+				evt := notify.DueDateReminderEvent{
+					TeamID:       board.TeamID,
+					Board:        board,
+					Card:         card,
+					PropertyName: schema[propID].Name,
+					DueAt:        dueAt,
+					Offset:       offset,
+				}
+				if err := b.deliver(evt, properties, schema); err != nil {
+					merr.Append(err)
+				}
+			}
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
+// deliver sends a reminder to the assignees and the subscribers of the card
+// that can still view the board.
+func (b *Backend) deliver(evt notify.DueDateReminderEvent, properties map[string]any, schema model.PropSchema) error {
+	userIDs := model.GetCardAssignees(properties, schema)
+
+	subscribers, err := b.appAPI.GetSubscribersForBlock(evt.Card.ID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subscribers {
+		if sub.SubscriberType == model.SubTypeUser {
+			userIDs = append(userIDs, sub.SubscriberID)
+		}
+	}
+
+	merr := merror.New()
+	sent := map[string]bool{}
+	for _, userID := range userIDs {
+		if sent[userID] {
+			continue
+		}
+		sent[userID] = true
+
+		if !b.permissions.HasPermissionToBoard(userID, evt.Board.ID, model.PermissionViewBoard) {
+			b.logger.Debug("Skipping due date reminder for user without access to the board",
+				mlog.String("user_id", userID),
+				mlog.String("board_id", evt.Board.ID),
+			)
+			continue
+		}
+
+		if err := b.delivery.DueDateReminderDeliver(userID, evt); err != nil {
+			merr.Append(err)
+			continue
+		}
+
+		b.logger.Debug("Due date reminder sent",
+			mlog.String("user_id", userID),
+			mlog.String("card_id", evt.Card.ID),
+			mlog.Int("offset", evt.Offset),
+		)
+	}
+	return merr.ErrorOrNil()
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyreminders
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testAppAPI struct {
+	settings    []*model.DueDateReminderSettings
+	boards      map[string]*model.Board
+	cards       []*model.Block
+	subscribers []*model.Subscriber
+	sent        map[string]bool
+}
+
+func (a *testAppAPI) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	return a.settings, nil
+}
+
+func (a *testAppAPI) MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%d/%d", reminder.CardID, reminder.PropertyID, reminder.Offset, reminder.DueAt)
+	if a.sent[key] {
+		return false, nil
+	}
+	a.sent[key] = true
+	return true, nil
+}
+
+func (a *testAppAPI) DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error) {
+	return 0, nil
+}
+
+func (a *testAppAPI) GetBoard(boardID string) (*model.Board, error) {
+	board, ok := a.boards[boardID]
+	if !ok {
+		return nil, model.NewErrNotFound("board ID=" + boardID)
+	}
+	return board, nil
+}
+
+func (a *testAppAPI) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
+	return a.cards, nil
+}
+
+func (a *testAppAPI) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
+	return a.subscribers, nil
+}
+
+type testPermissions struct {
+	members map[string]bool
+}
+
+func (p *testPermissions) HasPermissionTo(userID string, permission *mmModel.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToTeam(userID, teamID string, permission *mmModel.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToChannel(userID, channelID string, permission *mmModel.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToBoard(userID, boardID string, permission *mmModel.Permission) bool {
+	return p.members[userID]
+}
+
+type testDelivery struct {
+	delivered []string
+}
+
+func (d *testDelivery) DueDateReminderDeliver(userID string, evt notify.DueDateReminderEvent) error {
+	d.delivered = append(d.delivered, fmt.Sprintf("%s:%d", userID, evt.Offset))
+	return nil
+}
+
+func TestSendDueReminders(t *testing.T) {
+	minute := time.Minute.Milliseconds()
+	now := int64(1_000_000_000)
+	dueAt := now + 60*minute
+
+	newBackend := func(settings *model.DueDateReminderSettings) (*Backend, *testAppAPI, *testDelivery) {
+		appAPI := &testAppAPI{
+			settings: []*model.DueDateReminderSettings{settings},
+			boards: map[string]*model.Board{
+				"board-id": {
+					ID:     "board-id",
+					TeamID: "team-id",
+					CardProperties: []map[string]any{
+						{"id": "due-id", "name": "Due", "type": "date"},
+						{"id": "owner-id", "name": "Owner", "type": "person"},
+					},
+				},
+			},
+			cards: []*model.Block{
+				{
+					ID:      "card-id",
+					BoardID: "board-id",
+					Type:    model.TypeCard,
+					Fields: map[string]any{
+						"properties": map[string]any{
+							"due-id":   fmt.Sprintf(`{"from":%d}`, dueAt),
+							"owner-id": "owner",
+						},
+					},
+				},
+			},
+			subscribers: []*model.Subscriber{
+				{SubscriberType: model.SubTypeUser, SubscriberID: "watcher"},
+				{SubscriberType: model.SubTypeUser, SubscriberID: "owner"},
+				{SubscriberType: model.SubTypeUser, SubscriberID: "former-member"},
+				{SubscriberType: model.SubTypeChannel, SubscriberID: "channel-id"},
+			},
+			sent: map[string]bool{},
+		}
+		delivery := &testDelivery{}
+		backend := New(BackendParams{
+			AppAPI:      appAPI,
+			Permissions: &testPermissions{members: map[string]bool{"owner": true, "watcher": true}},
+			Delivery:    delivery,
+			Logger:      mlog.CreateConsoleTestLogger(t),
+		})
+		return backend, appAPI, delivery
+	}
+
+	t.Run("sends due reminders once", func(t *testing.T) {
+		backend, _, delivery := newBackend(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{-60, 0},
+		})
+
+		backend.sendDueReminders(now)
+		require.Equal(t, []string{"owner:-60", "watcher:-60"}, delivery.delivered)
+
+		backend.sendDueReminders(now + minute)
+		require.Len(t, delivery.delivered, 2)
+
+		backend.sendDueReminders(now + 60*minute)
+		require.Equal(t, []string{"owner:-60", "watcher:-60", "owner:0", "watcher:0"}, delivery.delivered)
+	})
+
+	t.Run("skips reminders due before the settings", func(t *testing.T) {
+		backend, _, delivery := newBackend(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{-60},
+			UpdateAt:    now + minute,
+		})
+
+		backend.sendDueReminders(now + 2*minute)
+		require.Empty(t, delivery.delivered)
+	})
+
+	t.Run("skips reminders due too long ago", func(t *testing.T) {
+		backend, _, delivery := newBackend(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{0},
+		})
+
+		backend.sendDueReminders(dueAt + reminderMaxDelay.Milliseconds() + minute)
+		require.Empty(t, delivery.delivered)
+	})
+
+	t.Run("skips card templates", func(t *testing.T) {
+		backend, appAPI, delivery := newBackend(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{0},
+		})
+		appAPI.cards[0].Fields["isTemplate"] = true
+
+		backend.sendDueReminders(dueAt)
+		require.Empty(t, delivery.delivered)
+		require.Empty(t, appAPI.sent)
+	})
+
+	t.Run("sends a new reminder when the due date changes", func(t *testing.T) {
+		backend, appAPI, delivery := newBackend(&model.DueDateReminderSettings{
+			BoardID:     "board-id",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{0},
+		})
+
+		backend.sendDueReminders(dueAt)
+		require.Len(t, delivery.delivered, 2)
+
+		properties := appAPI.cards[0].Fields["properties"].(map[string]any)
+		properties["due-id"] = fmt.Sprintf(`{"from":%d}`, dueAt+minute)
+		backend.sendDueReminders(dueAt + minute)
+		require.Len(t, delivery.delivered, 4)
+	})
+}
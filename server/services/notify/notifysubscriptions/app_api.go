@@ -12,14 +12,22 @@ import (
 type AppAPI interface {
 	GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
+	GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error)
+	GetBlocksByIDs(ids []string) ([]*model.Block, error)
+	GetBoard(boardID string) (*model.Board, error)
 
 	GetUserByID(userID string) (*model.User, error)
+	GetUserTimezone(userID string) (string, error)
 
 	CreateSubscription(sub *model.Subscription) (*model.Subscription, error)
+	GetSubscriptions(subscriberID string) ([]*model.Subscription, error)
 	GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error)
 	UpdateSubscribersNotifiedAt(blockID string, notifyAt int64) error
 
 	UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error)
 	GetNextNotificationHint(remove bool) (*model.NotificationHint, error)
+
+	GetDigestSettingsList() ([]*model.DigestSettings, error)
+	MarkDigestSent(userID string, lastSentAt, sentAt int64) (bool, error)
 }
@@ -14,4 +14,5 @@ import (
 type SubscriptionDelivery interface {
 	SubscriptionDeliverSlackAttachments(teamID string, subscriberID string, subscriberType model.SubscriberType,
 		attachments []*mm_model.SlackAttachment) error
+	SubscriptionDeliverDigest(teamID string, userID string, message string) error
 }
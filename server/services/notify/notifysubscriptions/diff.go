@@ -266,6 +266,10 @@ func (dg *diffGenerator) generateDiffForBlock(newBlock *model.Block, schema mode
 		}
 	}
 
+	if newBlock.Type == model.TypeCard && schema.HasRollups() {
+		dg.resolveRollups(oldBlock, newBlock)
+	}
+
 	propDiffs := dg.generatePropDiffs(oldBlock, newBlock, schema)
 
 	dg.logger.Debug("generateDiffForBlock - results",
@@ -352,6 +356,27 @@ func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, sche
 	return sortPropDiffs(propDiffs)
 }
 
+// resolveRollups computes the rollup properties of both versions of a card
+// from their relations, so that the diff shows resolved values.
+func (dg *diffGenerator) resolveRollups(oldBlock, newBlock *model.Block) {
+	blocks := []*model.Block{newBlock}
+	if oldBlock != nil {
+		blocks = append(blocks, oldBlock)
+	}
+
+	// the notifications are read by every subscriber, so only the rollups
+	// of boards open to the team are computed
+	isOpen := func(relatedBoard *model.Board) bool {
+		return relatedBoard.Type == model.BoardTypeOpen
+	}
+	if err := model.ResolveRollups(dg.board, blocks, dg.store, isOpen); err != nil {
+		dg.logger.Error("Cannot resolve rollup properties",
+			mlog.String("block_id", newBlock.ID),
+			mlog.Err(err),
+		)
+	}
+}
+
 func safeBlockID(b *model.Block) string {
 	if b == nil {
 		return ""
@@ -12,6 +12,7 @@ import (
 	"text/template"
 
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 	"github.com/wiggin77/merror"
 
 	mm_model "github.com/mattermost/mattermost/server/public/model"
@@ -39,6 +40,21 @@ type DiffConvOpts struct {
 	Logger        mlog.LoggerIFace
 }
 
+// makeDiffConvOpts returns the options to convert diffs with links to the
+// cards and boards on the server.
+func makeDiffConvOpts(serverRoot string, logger mlog.LoggerIFace) DiffConvOpts {
+	return DiffConvOpts{
+		Language: "en", // TODO: use correct language when i18n is available on server.
+		MakeCardLink: func(block *model.Block, board *model.Board, card *model.Block) string {
+			return fmt.Sprintf("[%s](%s)", block.Title, utils.MakeCardLink(serverRoot, board.TeamID, board.ID, card.ID))
+		},
+		MakeBoardLink: func(board *model.Board) string {
+			return fmt.Sprintf("[%s](%s)", board.Title, utils.MakeBoardLink(serverRoot, board.TeamID, board.ID))
+		},
+		Logger: logger,
+	}
+}
+
 // getTemplate returns a new or cached named template based on the language specified.
 func getTemplate(name string, opts DiffConvOpts, def string) (*template.Template, error) {
 	templateCacheMux.Lock()
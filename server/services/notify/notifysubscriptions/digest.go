@@ -0,0 +1,382 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/permissions"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	defDigestScanFrequency = time.Minute * 5
+)
+
+// digestBoard holds the changes of a board sent in a digest.
+type digestBoard struct {
+	board *model.Board
+	diffs []*Diff
+}
+
+// digester sends the users that subscribed to blocks in digest mode one
+// message with all the changes made since their previous digest, daily or
+// weekly at the hour they chose in their own timezone.
+type digester struct {
+	serverRoot    string
+	store         AppAPI
+	permissions   permissions.PermissionsService
+	delivery      SubscriptionDelivery
+	logger        mlog.LoggerIFace
+	scanFrequency time.Duration
+
+	mux  sync.Mutex
+	done chan struct{}
+}
+
+func newDigester(params BackendParams) *digester {
+	scanFrequency := params.DigestScanFrequency
+	if scanFrequency <= 0 {
+		scanFrequency = defDigestScanFrequency
+	}
+
+	return &digester{
+		serverRoot:    params.ServerRoot,
+		store:         params.AppAPI,
+		permissions:   params.Permissions,
+		delivery:      params.Delivery,
+		logger:        params.Logger,
+		scanFrequency: scanFrequency,
+	}
+}
+
+func (d *digester) start() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.done == nil {
+		d.done = make(chan struct{})
+		go d.loop(d.done)
+	}
+}
+
+func (d *digester) stop() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.done != nil {
+		close(d.done)
+		d.done = nil
+	}
+}
+
+func (d *digester) loop(done chan struct{}) {
+	ticker := time.NewTicker(d.scanFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			d.safeSendDigests()
+		}
+	}
+}
+
+func (d *digester) safeSendDigests() {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Error("panic recovered in digest loop",
+				mlog.Any("panic", r),
+				mlog.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+	d.sendDigests(time.Now())
+}
+
+// sendDigests sends the digests that are due at `now`.
+func (d *digester) sendDigests(now time.Time) {
+	settingsList, err := d.store.GetDigestSettingsList()
+	if err != nil {
+		d.logger.Error("Cannot get digest settings", mlog.Err(err))
+		return
+	}
+
+	for _, settings := range settingsList {
+		if err := d.sendDigest(settings, now); err != nil {
+			d.logger.Error("Error sending digest",
+				mlog.String("user_id", settings.UserID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
+
+func (d *digester) sendDigest(settings *model.DigestSettings, now time.Time) error {
+	scheduledAt := settings.LastScheduledAt(now.In(d.getUserLocation(settings.UserID)))
+	if utils.GetMillisForTime(scheduledAt) <= settings.LastSentAt {
+		return nil
+	}
+
+	sentAt := utils.GetMillisForTime(now)
+	marked, err := d.store.MarkDigestSent(settings.UserID, settings.LastSentAt, sentAt)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		// already sent, possibly by another server
+		return nil
+	}
+
+	// changes older than one period are left out, e.g. when the server was down.
+	from := settings.LastSentAt
+	if minFrom := utils.GetMillisForTime(scheduledAt.Add(-settings.Period())); from < minFrom {
+		from = minFrom
+	}
+
+	boards, err := d.generateDigest(settings.UserID, from)
+	if err != nil {
+		return err
+	}
+	if len(boards) == 0 {
+		d.logger.Debug("sendDigest - no changes", mlog.String("user_id", settings.UserID))
+		return nil
+	}
+
+	title := fmt.Sprintf("Your %s digest of board changes", settings.Frequency) // TODO: localize when i18n added to server
+	message := digest2Markdown(title, boards, makeDiffConvOpts(d.serverRoot, d.logger))
+
+	d.logger.Debug("sendDigest - deliver",
+		mlog.String("user_id", settings.UserID),
+		mlog.Int("board_count", len(boards)),
+	)
+	return d.delivery.SubscriptionDeliverDigest(boards[0].board.TeamID, settings.UserID, message)
+}
+
+// getUserLocation returns the timezone of a user, UTC if it cannot be found.
+func (d *digester) getUserLocation(userID string) *time.Location {
+	timezone, err := d.store.GetUserTimezone(userID)
+	if err != nil || timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		d.logger.Debug("Invalid user timezone for digest",
+			mlog.String("user_id", userID),
+			mlog.String("timezone", timezone),
+		)
+		return time.UTC
+	}
+	return loc
+}
+
+// generateDigest returns the changes made after `from` to the boards and
+// cards a user subscribed to in digest mode, grouped by board.
+func (d *digester) generateDigest(userID string, from int64) ([]*digestBoard, error) {
+	subs, err := d.store.GetSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardSubs := map[string]bool{}
+	cardSubs := map[string]*model.Subscription{}
+	cardIDs := []string{}
+	for _, sub := range subs {
+		if !sub.Digest {
+			continue
+		}
+		switch sub.BlockType {
+		case model.TypeBoard:
+			boardSubs[sub.BlockID] = true
+		case model.TypeCard:
+			cardSubs[sub.BlockID] = sub
+			cardIDs = append(cardIDs, sub.BlockID)
+		}
+	}
+
+	boardIDs := map[string]bool{}
+	for boardID := range boardSubs {
+		boardIDs[boardID] = true
+	}
+	if len(cardIDs) != 0 {
+		cards, err := d.store.GetBlocksByIDs(cardIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range cards {
+			boardIDs[card.BoardID] = true
+		}
+	}
+
+	sortedBoardIDs := make([]string, 0, len(boardIDs))
+	for boardID := range boardIDs {
+		sortedBoardIDs = append(sortedBoardIDs, boardID)
+	}
+	sort.Strings(sortedBoardIDs)
+
+	var boards []*digestBoard
+	merr := merror.New()
+	for _, boardID := range sortedBoardIDs {
+		db, err := d.generateBoardDigest(userID, boardID, from, boardSubs[boardID], cardSubs)
+		if err != nil {
+			merr.Append(fmt.Errorf("cannot generate digest for board %s: %w", boardID, err))
+			continue
+		}
+		if db != nil {
+			boards = append(boards, db)
+		}
+	}
+	return boards, merr.ErrorOrNil()
+}
+
+func (d *digester) generateBoardDigest(userID, boardID string, from int64, allCards bool, cardSubs map[string]*model.Subscription) (*digestBoard, error) {
+	board, err := d.store.GetBoard(boardID)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if board.DeleteAt != 0 || !d.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard) {
+		return nil, nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := d.store.GetBlockHistoryDescendants(board.ID, model.QueryBlockHistoryOptions{AfterUpdateAt: from})
+	if err != nil {
+		return nil, err
+	}
+
+	// the changed cards, in the order of their first change.
+	var changedCardIDs []string
+	seen := map[string]bool{}
+	for _, block := range blocks {
+		cardID := block.ParentID
+		if block.Type == model.TypeCard {
+			cardID = block.ID
+		}
+		if seen[cardID] || (!allCards && cardSubs[cardID] == nil) {
+			continue
+		}
+		seen[cardID] = true
+		changedCardIDs = append(changedCardIDs, cardID)
+	}
+
+	db := &digestBoard{board: board}
+	for _, cardID := range changedCardIDs {
+		history, err := d.store.GetBlockHistory(cardID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+		if err != nil {
+			return nil, err
+		}
+		if len(history) == 0 || history[0].Type != model.TypeCard {
+			continue
+		}
+		card := history[0]
+
+		dg := &diffGenerator{
+			board:        board,
+			card:         card,
+			store:        d.store,
+			lastNotifyAt: from,
+			logger:       d.logger,
+		}
+		cardDiff, err := dg.generateDiffsForCard(card, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		// don't send users their own changes.
+		if _, isAuthor := cardDiff.Authors[userID]; isAuthor && len(cardDiff.Authors) == 1 {
+			continue
+		}
+
+		var filter *model.SubscriptionFilter
+		if sub := cardSubs[cardID]; sub != nil && !allCards {
+			filter = sub.Filter
+		}
+		db.diffs = append(db.diffs, filterDiffs([]*Diff{cardDiff}, filter, schema)...)
+	}
+
+	if len(db.diffs) == 0 {
+		return nil, nil
+	}
+	return db, nil
+}
+
+// digest2Markdown renders the changes of a digest as a single markdown
+// message, grouped by board.
+func digest2Markdown(title string, boards []*digestBoard, opts DiffConvOpts) string {
+	sb := &strings.Builder{}
+	sb.WriteString("#### ")
+	sb.WriteString(title)
+	sb.WriteString("\n")
+
+	for _, db := range boards {
+		cards := &strings.Builder{}
+		for _, diff := range db.diffs {
+			cards.WriteString(cardDiff2Markdown(diff, opts))
+		}
+		if cards.Len() == 0 {
+			continue
+		}
+
+		sb.WriteString("\n##### ")
+		sb.WriteString(opts.MakeBoardLink(db.board))
+		sb.WriteString("\n")
+		sb.WriteString(cards.String())
+	}
+	return sb.String()
+}
+
+// cardDiff2Markdown renders the changes of a card as a markdown list item,
+// or an empty string if nothing worth notifying changed.
+func cardDiff2Markdown(cardDiff *Diff, opts DiffConvOpts) string {
+	if cardDiff.NewBlock == nil && cardDiff.OldBlock == nil {
+		return ""
+	}
+
+	link := opts.MakeCardLink(cardDiff.Card, cardDiff.Board, cardDiff.Card)
+	authors := makeAuthorsList(cardDiff.Authors, "unknown_user")
+
+	// TODO: localize when i18n added to server
+	switch {
+	case cardDiff.OldBlock == nil:
+		return fmt.Sprintf("- %s was added by %s\n", link, authors)
+	case cardDiff.NewBlock == nil || cardDiff.NewBlock.DeleteAt != 0:
+		return fmt.Sprintf("- %s was deleted by %s\n", link, authors)
+	}
+
+	fields := appendTitleChanges(nil, cardDiff)
+	fields = appendPropertyChanges(fields, cardDiff)
+	fields = appendCommentChanges(fields, cardDiff)
+	fields = appendAttachmentChanges(fields, cardDiff)
+	fields = appendContentChanges(fields, cardDiff, opts.Logger)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "- %s was modified by %s\n", link, authors)
+	for _, field := range fields {
+		value := strings.ReplaceAll(fmt.Sprint(field.Value), "\n", "\n    ")
+		fmt.Fprintf(sb, "  - **%s**: %s\n", field.Title, value)
+	}
+	return sb.String()
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testAppAPI struct {
+	boards        map[string]*model.Board
+	history       []*model.Block
+	subscriptions []*model.Subscription
+	subscribers   []*model.Subscriber
+	timezone      string
+	marked        []int64
+	alreadySent   bool
+}
+
+func (a *testAppAPI) filterHistory(match func(*model.Block) bool, opts model.QueryBlockHistoryOptions) []*model.Block {
+	var blocks []*model.Block
+	for _, b := range a.history {
+		if !match(b) ||
+			(opts.BeforeUpdateAt != 0 && b.UpdateAt >= opts.BeforeUpdateAt) ||
+			(opts.AfterUpdateAt != 0 && b.UpdateAt <= opts.AfterUpdateAt) {
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if opts.Descending {
+			return blocks[i].UpdateAt > blocks[j].UpdateAt
+		}
+		return blocks[i].UpdateAt < blocks[j].UpdateAt
+	})
+	if opts.Limit != 0 && uint64(len(blocks)) > opts.Limit {
+		blocks = blocks[:opts.Limit]
+	}
+	return blocks
+}
+
+func (a *testAppAPI) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return a.filterHistory(func(b *model.Block) bool { return b.ID == blockID }, opts), nil
+}
+
+func (a *testAppAPI) GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	newest := map[string]*model.Block{}
+	for _, b := range a.history {
+		if b.ParentID == parentID && b.UpdateAt > opts.AfterUpdateAt && (newest[b.ID] == nil || newest[b.ID].UpdateAt < b.UpdateAt) {
+			newest[b.ID] = b
+		}
+	}
+	blocks := make([]*model.Block, 0, len(newest))
+	for _, b := range newest {
+		blocks = append(blocks, b)
+	}
+	return blocks, false, nil
+}
+
+func (a *testAppAPI) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return a.filterHistory(func(b *model.Block) bool { return b.BoardID == boardID }, opts), nil
+}
+
+func (a *testAppAPI) GetBoardAndCardByID(blockID string) (*model.Board, *model.Block, error) {
+	blocks, _ := a.GetBlocksByIDs([]string{blockID})
+	if len(blocks) == 0 {
+		return nil, nil, model.NewErrNotFound(blockID)
+	}
+	board, err := a.GetBoard(blocks[0].BoardID)
+	return board, blocks[0], err
+}
+
+func (a *testAppAPI) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
+	var blocks []*model.Block
+	for _, id := range ids {
+		if history, _ := a.GetBlockHistory(id, model.QueryBlockHistoryOptions{Limit: 1, Descending: true}); len(history) != 0 {
+			blocks = append(blocks, history[0])
+		}
+	}
+	return blocks, nil
+}
+
+func (a *testAppAPI) GetBoard(boardID string) (*model.Board, error) {
+	board, ok := a.boards[boardID]
+	if !ok {
+		return nil, model.NewErrNotFound("board ID=" + boardID)
+	}
+	return board, nil
+}
+
+func (a *testAppAPI) GetUserByID(userID string) (*model.User, error) {
+	return &model.User{ID: userID, Username: "username-" + userID}, nil
+}
+
+func (a *testAppAPI) GetUserTimezone(userID string) (string, error) {
+	return a.timezone, nil
+}
+
+func (a *testAppAPI) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
+	return sub, nil
+}
+
+func (a *testAppAPI) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
+	return a.subscriptions, nil
+}
+
+func (a *testAppAPI) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
+	return a.subscribers, nil
+}
+
+func (a *testAppAPI) UpdateSubscribersNotifiedAt(blockID string, notifyAt int64) error {
+	return nil
+}
+
+func (a *testAppAPI) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
+	return hint, nil
+}
+
+func (a *testAppAPI) GetNextNotificationHint(remove bool) (*model.NotificationHint, error) {
+	return nil, model.NewErrNotFound("hint")
+}
+
+func (a *testAppAPI) GetDigestSettingsList() ([]*model.DigestSettings, error) {
+	return nil, nil
+}
+
+func (a *testAppAPI) MarkDigestSent(userID string, lastSentAt, sentAt int64) (bool, error) {
+	if a.alreadySent {
+		return false, nil
+	}
+	a.marked = append(a.marked, lastSentAt)
+	return true, nil
+}
+
+type testPermissions struct {
+	members map[string]bool
+}
+
+func (p *testPermissions) HasPermissionTo(userID string, permission *mm_model.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToTeam(userID, teamID string, permission *mm_model.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToChannel(userID, channelID string, permission *mm_model.Permission) bool {
+	return false
+}
+
+func (p *testPermissions) HasPermissionToBoard(userID, boardID string, permission *mm_model.Permission) bool {
+	return p.members[userID]
+}
+
+type testDelivery struct {
+	attachments map[string][]*mm_model.SlackAttachment
+	digests     map[string]string
+}
+
+func newTestDelivery() *testDelivery {
+	return &testDelivery{
+		attachments: map[string][]*mm_model.SlackAttachment{},
+		digests:     map[string]string{},
+	}
+}
+
+func (d *testDelivery) SubscriptionDeliverSlackAttachments(teamID string, subscriberID string, subscriberType model.SubscriberType,
+	attachments []*mm_model.SlackAttachment) error {
+	d.attachments[subscriberID] = attachments
+	return nil
+}
+
+func (d *testDelivery) SubscriptionDeliverDigest(teamID string, userID string, message string) error {
+	d.digests[userID] = message
+	return nil
+}
+
+func TestSendDigest(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	now := time.Date(2024, time.March, 13, 9, 5, 0, 0, loc)
+	scheduledAt := utils.GetMillisForTime(time.Date(2024, time.March, 13, 9, 0, 0, 0, loc))
+	lastSentAt := scheduledAt - 24*time.Hour.Milliseconds()
+
+	board := &model.Board{
+		ID:     "board-id",
+		TeamID: "team-id",
+		Title:  "Roadmap",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "text"},
+		},
+	}
+
+	cardVersion := func(status string, updateAt int64, modifiedBy string) *model.Block {
+		return &model.Block{
+			ID:         "card-id",
+			BoardID:    board.ID,
+			ParentID:   board.ID,
+			Type:       model.TypeCard,
+			Title:      "Launch",
+			ModifiedBy: modifiedBy,
+			UpdateAt:   updateAt,
+			Fields:     map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+		}
+	}
+
+	newTest := func() (*digester, *testAppAPI, *testDelivery) {
+		appAPI := &testAppAPI{
+			boards: map[string]*model.Board{board.ID: board},
+			history: []*model.Block{
+				cardVersion("todo", lastSentAt-1000, "user-2"),
+				cardVersion("done", lastSentAt+2000, "user-2"),
+				{
+					ID:         "comment-id",
+					BoardID:    board.ID,
+					ParentID:   "card-id",
+					Type:       model.TypeComment,
+					Title:      "shipped!",
+					ModifiedBy: "user-2",
+					UpdateAt:   lastSentAt + 1000,
+				},
+			},
+			subscriptions: []*model.Subscription{
+				{BlockType: model.TypeCard, BlockID: "card-id", SubscriberType: model.SubTypeUser, SubscriberID: "user-1", Digest: true},
+			},
+			timezone: "Europe/Madrid",
+		}
+		delivery := newTestDelivery()
+		d := newDigester(BackendParams{
+			ServerRoot:  "http://localhost",
+			AppAPI:      appAPI,
+			Permissions: &testPermissions{members: map[string]bool{"user-1": true}},
+			Delivery:    delivery,
+			Logger:      mlog.CreateConsoleTestLogger(t),
+		})
+		return d, appAPI, delivery
+	}
+
+	t.Run("sends the changes grouped by board", func(t *testing.T) {
+		d, appAPI, delivery := newTest()
+		settings := &model.DigestSettings{UserID: "user-1", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: lastSentAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Equal(t, []int64{lastSentAt}, appAPI.marked)
+
+		message := delivery.digests["user-1"]
+		require.Contains(t, message, "Your daily digest of board changes")
+		require.Contains(t, message, "[Roadmap](")
+		require.Contains(t, message, "[Launch](")
+		require.Contains(t, message, "@username-user-2")
+		require.Contains(t, message, "**Status**: done  ~~`todo`~~")
+		require.Contains(t, message, "shipped!")
+	})
+
+	t.Run("not due yet", func(t *testing.T) {
+		d, appAPI, delivery := newTest()
+		settings := &model.DigestSettings{UserID: "user-1", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: scheduledAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Empty(t, appAPI.marked)
+		require.Empty(t, delivery.digests)
+	})
+
+	t.Run("sent by another server", func(t *testing.T) {
+		d, appAPI, delivery := newTest()
+		appAPI.alreadySent = true
+		settings := &model.DigestSettings{UserID: "user-1", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: lastSentAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Empty(t, delivery.digests)
+	})
+
+	t.Run("filtered out changes", func(t *testing.T) {
+		d, appAPI, delivery := newTest()
+		appAPI.subscriptions[0].Filter = &model.SubscriptionFilter{Assignments: true}
+		settings := &model.DigestSettings{UserID: "user-1", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: lastSentAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Len(t, appAPI.marked, 1)
+		require.Empty(t, delivery.digests)
+	})
+
+	t.Run("immediate subscriptions are not in the digest", func(t *testing.T) {
+		d, appAPI, delivery := newTest()
+		appAPI.subscriptions[0].Digest = false
+		settings := &model.DigestSettings{UserID: "user-1", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: lastSentAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Empty(t, delivery.digests)
+	})
+
+	t.Run("own changes are not sent", func(t *testing.T) {
+		d, _, delivery := newTest()
+		settings := &model.DigestSettings{UserID: "user-2", Frequency: model.DigestFrequencyDaily, Hour: 9, LastSentAt: lastSentAt}
+
+		require.NoError(t, d.sendDigest(settings, now))
+		require.Empty(t, delivery.digests)
+	})
+}
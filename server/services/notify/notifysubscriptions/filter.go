@@ -0,0 +1,58 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+)
+
+// filterDiffs returns the card diffs restricted to the changes matched by a
+// subscription filter. Cards being added or deleted are always kept, and card
+// diffs left without any change are dropped.
+func filterDiffs(diffs []*Diff, filter *model.SubscriptionFilter, schema model.PropSchema) []*Diff {
+	if filter.IsEmpty() {
+		return diffs
+	}
+
+	var filtered []*Diff
+	for _, d := range diffs {
+		if d.BlockType != model.TypeCard {
+			continue
+		}
+
+		if d.OldBlock == nil || d.NewBlock == nil || d.NewBlock.DeleteAt != 0 {
+			filtered = append(filtered, d)
+			continue
+		}
+
+		cardDiff := *d
+
+		// title changes are not matched by any filter.
+		oldBlock := *d.OldBlock
+		oldBlock.Title = d.NewBlock.Title
+		cardDiff.OldBlock = &oldBlock
+
+		cardDiff.PropDiffs = nil
+		for _, propDiff := range d.PropDiffs {
+			if pd, ok := schema[propDiff.ID]; ok && filter.MatchesProperty(pd) {
+				cardDiff.PropDiffs = append(cardDiff.PropDiffs, propDiff)
+			}
+		}
+
+		cardDiff.Diffs = nil
+		if filter.Comments {
+			for _, child := range d.Diffs {
+				if child.BlockType == model.TypeComment {
+					cardDiff.Diffs = append(cardDiff.Diffs, child)
+				}
+			}
+		}
+
+		if len(cardDiff.PropDiffs) == 0 && len(cardDiff.Diffs) == 0 {
+			continue
+		}
+		filtered = append(filtered, &cardDiff)
+	}
+	return filtered
+}
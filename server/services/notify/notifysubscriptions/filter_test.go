@@ -0,0 +1,84 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterDiffs(t *testing.T) {
+	schema := model.PropSchema{
+		"status":   {ID: "status", Name: "Status", Type: "select"},
+		"assignee": {ID: "assignee", Name: "Assignee", Type: "person"},
+		"due":      {ID: "due", Name: "Due", Type: "date"},
+	}
+
+	newCardDiff := func() *Diff {
+		return &Diff{
+			BlockType: model.TypeCard,
+			OldBlock:  &model.Block{ID: "card-1", Title: "old title"},
+			NewBlock:  &model.Block{ID: "card-1", Title: "new title"},
+			PropDiffs: []PropDiff{
+				{ID: "status", NewValue: "Done"},
+				{ID: "assignee", NewValue: "john"},
+				{ID: "due", NewValue: "tomorrow"},
+			},
+			Diffs: []*Diff{
+				{BlockType: model.TypeComment, NewBlock: &model.Block{ID: "comment-1"}},
+				{BlockType: model.TypeText, NewBlock: &model.Block{ID: "text-1"}},
+			},
+		}
+	}
+
+	t.Run("empty filter", func(t *testing.T) {
+		diffs := []*Diff{newCardDiff()}
+		require.Equal(t, diffs, filterDiffs(diffs, nil, schema))
+		require.Equal(t, diffs, filterDiffs(diffs, &model.SubscriptionFilter{}, schema))
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		diff := newCardDiff()
+		filtered := filterDiffs([]*Diff{diff}, &model.SubscriptionFilter{Comments: true}, schema)
+		require.Len(t, filtered, 1)
+		require.Empty(t, filtered[0].PropDiffs)
+		require.Equal(t, []*Diff{diff.Diffs[0]}, filtered[0].Diffs)
+		require.Equal(t, filtered[0].NewBlock.Title, filtered[0].OldBlock.Title)
+
+		// the original diff is left untouched
+		require.Equal(t, "old title", diff.OldBlock.Title)
+		require.Len(t, diff.PropDiffs, 3)
+		require.Len(t, diff.Diffs, 2)
+	})
+
+	t.Run("assignments, due dates and properties", func(t *testing.T) {
+		filtered := filterDiffs([]*Diff{newCardDiff()}, &model.SubscriptionFilter{Assignments: true}, schema)
+		require.Len(t, filtered, 1)
+		require.Equal(t, []PropDiff{{ID: "assignee", NewValue: "john"}}, filtered[0].PropDiffs)
+		require.Empty(t, filtered[0].Diffs)
+
+		filtered = filterDiffs([]*Diff{newCardDiff()}, &model.SubscriptionFilter{DueDates: true, Properties: []string{"status"}}, schema)
+		require.Len(t, filtered, 1)
+		require.Equal(t, []PropDiff{{ID: "status", NewValue: "Done"}, {ID: "due", NewValue: "tomorrow"}}, filtered[0].PropDiffs)
+	})
+
+	t.Run("no matching changes", func(t *testing.T) {
+		diff := newCardDiff()
+		diff.Diffs = nil
+		require.Empty(t, filterDiffs([]*Diff{diff}, &model.SubscriptionFilter{Comments: true}, schema))
+	})
+
+	t.Run("added and deleted cards are kept", func(t *testing.T) {
+		added := &Diff{BlockType: model.TypeCard, NewBlock: &model.Block{ID: "card-2"}}
+		deleted := &Diff{
+			BlockType: model.TypeCard,
+			OldBlock:  &model.Block{ID: "card-3"},
+			NewBlock:  &model.Block{ID: "card-3", DeleteAt: 1000},
+		}
+		filtered := filterDiffs([]*Diff{added, deleted}, &model.SubscriptionFilter{Comments: true}, schema)
+		require.Equal(t, []*Diff{added, deleted}, filtered)
+	})
+}
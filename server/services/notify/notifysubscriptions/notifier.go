@@ -228,25 +228,35 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 		diffAuthors.Append(d.Authors)
 	}
 
-	opts := DiffConvOpts{
-		Language: "en", // TODO: use correct language when i18n is available on server.
-		MakeCardLink: func(block *model.Block, board *model.Board, card *model.Block) string {
-			return fmt.Sprintf("[%s](%s)", block.Title, utils.MakeCardLink(n.serverRoot, board.TeamID, board.ID, card.ID))
-		},
-		MakeBoardLink: func(board *model.Board) string {
-			return fmt.Sprintf("[%s](%s)", board.Title, utils.MakeBoardLink(n.serverRoot, board.TeamID, board.ID))
-		},
-		Logger: n.logger,
-	}
+	opts := makeDiffConvOpts(n.serverRoot, n.logger)
 
 	attachments, err := Diffs2SlackAttachments(diffs, opts)
 	if err != nil {
 		return err
 	}
 
+	// the board schema is only needed to apply the filters of subscribers.
+	var schema model.PropSchema
+	for _, sub := range subs {
+		if !sub.Filter.IsEmpty() {
+			if schema, err = model.ParsePropertySchema(board); err != nil {
+				n.logger.Error("notifySubscribers - cannot parse board schema",
+					mlog.String("board_id", board.ID),
+					mlog.Err(err),
+				)
+			}
+			break
+		}
+	}
+
 	merr := merror.New()
 	if len(attachments) > 0 {
 		for _, sub := range subs {
+			// changes for digest subscribers are sent in their next digest.
+			if sub.Digest {
+				continue
+			}
+
 			// don't notify the author of their own changes.
 			authorName, isAuthor := diffAuthors[sub.SubscriberID]
 			if isAuthor && len(diffAuthors) == 1 {
@@ -258,8 +268,10 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 				continue
 			}
 
-			// make sure the subscriber still has permissions for the board.
-			if !n.permissions.HasPermissionToBoard(sub.SubscriberID, board.ID, model.PermissionViewBoard) {
+			// make sure the subscriber still has permissions for the board. Channels were
+			// checked when subscribed by a user that can view the board and post to them.
+			if sub.SubscriberType == model.SubTypeUser &&
+				!n.permissions.HasPermissionToBoard(sub.SubscriberID, board.ID, model.PermissionViewBoard) {
 				n.logger.Debug("notifySubscribers - skipping non-board member",
 					mlog.Any("hint", hint),
 					mlog.String("subscriber_id", sub.SubscriberID),
@@ -268,6 +280,23 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 				continue
 			}
 
+			subAttachments := attachments
+			if !sub.Filter.IsEmpty() {
+				subAttachments, err = Diffs2SlackAttachments(filterDiffs(diffs, sub.Filter, schema), opts)
+				if err != nil {
+					merr.Append(fmt.Errorf("cannot filter notification for subscriber %s [%s]: %w",
+						sub.SubscriberID, sub.SubscriberType, err))
+					continue
+				}
+				if len(subAttachments) == 0 {
+					n.logger.Debug("notifySubscribers - skipping filtered out changes",
+						mlog.Any("hint", hint),
+						mlog.String("subscriber_id", sub.SubscriberID),
+					)
+					continue
+				}
+			}
+
 			n.logger.Debug("notifySubscribers - deliver",
 				mlog.Any("hint", hint),
 				mlog.String("modified_by_id", hint.ModifiedByID),
@@ -275,7 +304,7 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 				mlog.String("subscriber_type", string(sub.SubscriberType)),
 			)
 
-			if err = n.delivery.SubscriptionDeliverSlackAttachments(board.TeamID, sub.SubscriberID, sub.SubscriberType, attachments); err != nil {
+			if err = n.delivery.SubscriptionDeliverSlackAttachments(board.TeamID, sub.SubscriberID, sub.SubscriberType, subAttachments); err != nil {
 				merr.Append(fmt.Errorf("cannot deliver notification to subscriber %s [%s]: %w",
 					sub.SubscriberID, sub.SubscriberType, err))
 			}
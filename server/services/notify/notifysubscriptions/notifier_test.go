@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -56,3 +57,79 @@ func TestSafeLoop_RestartsAfterPanic(t *testing.T) {
 	}
 	require.Equal(t, 3, calls, "loop should restart after each panic until normal return")
 }
+
+func TestNotifySubscribers(t *testing.T) {
+	board := &model.Board{
+		ID:     "board-id",
+		TeamID: "team-id",
+		Title:  "Roadmap",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "text"},
+		},
+	}
+
+	cardVersion := func(status string, updateAt int64) *model.Block {
+		return &model.Block{
+			ID:         "card-id",
+			BoardID:    board.ID,
+			ParentID:   board.ID,
+			Type:       model.TypeCard,
+			Title:      "Launch",
+			ModifiedBy: "user-2",
+			UpdateAt:   updateAt,
+			Fields:     map[string]interface{}{"properties": map[string]interface{}{"status": status}},
+		}
+	}
+
+	newTest := func(subscribers ...*model.Subscriber) (*notifier, *testDelivery) {
+		appAPI := &testAppAPI{
+			boards:      map[string]*model.Board{board.ID: board},
+			history:     []*model.Block{cardVersion("todo", 1000), cardVersion("done", 3000)},
+			subscribers: subscribers,
+		}
+		delivery := newTestDelivery()
+		n := newNotifier(BackendParams{
+			ServerRoot:  "http://localhost",
+			AppAPI:      appAPI,
+			Permissions: &testPermissions{members: map[string]bool{"user-1": true}},
+			Delivery:    delivery,
+			Logger:      mlog.CreateConsoleTestLogger(t),
+		})
+		return n, delivery
+	}
+	hint := &model.NotificationHint{BlockType: model.TypeCard, BlockID: "card-id", ModifiedByID: "user-2"}
+
+	t.Run("users and channels are notified", func(t *testing.T) {
+		n, delivery := newTest(
+			&model.Subscriber{SubscriberType: model.SubTypeUser, SubscriberID: "user-1", NotifiedAt: 2000},
+			&model.Subscriber{SubscriberType: model.SubTypeChannel, SubscriberID: "channel-1", NotifiedAt: 2000},
+		)
+
+		require.NoError(t, n.notifySubscribers(hint))
+		require.Len(t, delivery.attachments, 2)
+		require.NotEmpty(t, delivery.attachments["user-1"])
+		require.NotEmpty(t, delivery.attachments["channel-1"])
+	})
+
+	t.Run("users without access to the board are not notified", func(t *testing.T) {
+		n, delivery := newTest(
+			&model.Subscriber{SubscriberType: model.SubTypeUser, SubscriberID: "user-3", NotifiedAt: 2000},
+		)
+
+		require.NoError(t, n.notifySubscribers(hint))
+		require.Empty(t, delivery.attachments)
+	})
+
+	t.Run("channel filters apply", func(t *testing.T) {
+		n, delivery := newTest(
+			&model.Subscriber{SubscriberType: model.SubTypeChannel, SubscriberID: "channel-1", NotifiedAt: 2000,
+				Filter: &model.SubscriptionFilter{Properties: []string{"status"}}},
+			&model.Subscriber{SubscriberType: model.SubTypeChannel, SubscriberID: "channel-2", NotifiedAt: 2000,
+				Filter: &model.SubscriptionFilter{Comments: true}},
+		)
+
+		require.NoError(t, n.notifySubscribers(hint))
+		require.Len(t, delivery.attachments, 1)
+		require.NotEmpty(t, delivery.attachments["channel-1"])
+	})
+}
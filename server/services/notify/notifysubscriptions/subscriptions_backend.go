@@ -29,6 +29,7 @@ type BackendParams struct {
 	Logger                 mlog.LoggerIFace
 	NotifyFreqCardSeconds  int
 	NotifyFreqBoardSeconds int
+	DigestScanFrequency    time.Duration
 }
 
 // Backend provides the notification backend for subscriptions.
@@ -37,6 +38,7 @@ type Backend struct {
 	permissions            permissions.PermissionsService
 	delivery               SubscriptionDelivery
 	notifier               *notifier
+	digester               *digester
 	logger                 mlog.LoggerIFace
 	notifyFreqCardSeconds  int
 	notifyFreqBoardSeconds int
@@ -48,6 +50,7 @@ func New(params BackendParams) *Backend {
 		delivery:               params.Delivery,
 		permissions:            params.Permissions,
 		notifier:               newNotifier(params),
+		digester:               newDigester(params),
 		logger:                 params.Logger,
 		notifyFreqCardSeconds:  params.NotifyFreqCardSeconds,
 		notifyFreqBoardSeconds: params.NotifyFreqBoardSeconds,
@@ -60,12 +63,14 @@ func (b *Backend) Start() error {
 		mlog.Int("freq_board", b.notifyFreqBoardSeconds),
 	)
 	b.notifier.start()
+	b.digester.start()
 	return nil
 }
 
 func (b *Backend) ShutDown() error {
 	b.logger.Debug("Stopping subscriptions backend")
 	b.notifier.stop()
+	b.digester.stop()
 	_ = b.logger.Flush()
 	return nil
 }
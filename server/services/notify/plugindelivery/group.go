@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	groupMembersPerPage = 200
+)
+
+// GroupMembersByName returns the members of the user group with the specified name.
+// Only groups that allow being mentioned are found.
+func (pd *PluginDelivery) GroupMembersByName(name string) ([]*mm_model.User, error) {
+	group, err := pd.api.GetGroupByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || group.DeleteAt != 0 || !group.AllowReference {
+		return nil, model.NewErrNotFound("group " + name)
+	}
+
+	var members []*mm_model.User
+	for page := 0; ; page++ {
+		users, err := pd.api.GetGroupMemberUsers(group.Id, page, groupMembersPerPage)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get members of group %s: %w", group.Id, err)
+		}
+		members = append(members, users...)
+		if len(users) < groupMembersPerPage {
+			break
+		}
+	}
+	return members, nil
+}
+
+// OnlineUserIDs returns the ids of the specified users that are currently online.
+func (pd *PluginDelivery) OnlineUserIDs(userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	statuses, err := pd.api.GetUserStatusesByIds(userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get user statuses: %w", err)
+	}
+
+	online := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Status == mm_model.StatusOnline {
+			online = append(online, status.UserId)
+		}
+	}
+	return online, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+type groupServicesAPIMock struct {
+	servicesAPIMock
+	groups   map[string]*mm_model.Group
+	members  map[string][]*mm_model.User
+	statuses []*mm_model.Status
+}
+
+func (m *groupServicesAPIMock) GetGroupByName(name string) (*mm_model.Group, error) {
+	group, ok := m.groups[name]
+	if !ok {
+		return nil, model.NewErrNotFound(name)
+	}
+	return group, nil
+}
+
+func (m *groupServicesAPIMock) GetGroupMemberUsers(groupID string, page, perPage int) ([]*mm_model.User, error) {
+	members := m.members[groupID]
+	start := page * perPage
+	if start >= len(members) {
+		return nil, nil
+	}
+	end := start + perPage
+	if end > len(members) {
+		end = len(members)
+	}
+	return members[start:end], nil
+}
+
+func (m *groupServicesAPIMock) GetUserStatusesByIds(userIDs []string) ([]*mm_model.Status, error) {
+	return m.statuses, nil
+}
+
+func TestGroupMembersByName(t *testing.T) {
+	var manyUsers []*mm_model.User
+	for i := 0; i < groupMembersPerPage+1; i++ {
+		manyUsers = append(manyUsers, &mm_model.User{Id: mm_model.NewId()})
+	}
+
+	servicesAPI := &groupServicesAPIMock{
+		servicesAPIMock: newServicesAPIMock(mockUsers),
+		groups: map[string]*mm_model.Group{
+			"developers": {Id: "developers-id", AllowReference: true},
+			"large":      {Id: "large-id", AllowReference: true},
+			"private":    {Id: "private-id", AllowReference: false},
+			"deleted":    {Id: "deleted-id", AllowReference: true, DeleteAt: 1},
+		},
+		members: map[string][]*mm_model.User{
+			"developers-id": {user1, user2},
+			"large-id":      manyUsers,
+			"private-id":    {user1},
+			"deleted-id":    {user1},
+		},
+	}
+	delivery := New("bot_id", "server_root", servicesAPI)
+
+	t.Run("group members", func(t *testing.T) {
+		members, err := delivery.GroupMembersByName("developers")
+		require.NoError(t, err)
+		require.Equal(t, []*mm_model.User{user1, user2}, members)
+	})
+
+	t.Run("all the pages of members", func(t *testing.T) {
+		members, err := delivery.GroupMembersByName("large")
+		require.NoError(t, err)
+		require.Len(t, members, groupMembersPerPage+1)
+	})
+
+	t.Run("groups that cannot be mentioned are not found", func(t *testing.T) {
+		for _, name := range []string{"private", "deleted", "missing"} {
+			_, err := delivery.GroupMembersByName(name)
+			require.True(t, model.IsErrNotFound(err), name)
+		}
+	})
+}
+
+func TestOnlineUserIDs(t *testing.T) {
+	servicesAPI := &groupServicesAPIMock{
+		servicesAPIMock: newServicesAPIMock(mockUsers),
+		statuses: []*mm_model.Status{
+			{UserId: user1.Id, Status: mm_model.StatusOnline},
+			{UserId: user2.Id, Status: mm_model.StatusAway},
+			{UserId: user3.Id, Status: mm_model.StatusOffline},
+		},
+	}
+	delivery := New("bot_id", "server_root", servicesAPI)
+
+	online, err := delivery.OnlineUserIDs([]string{user1.Id, user2.Id, user3.Id})
+	require.NoError(t, err)
+	require.Equal(t, []string{user1.Id}, online)
+}
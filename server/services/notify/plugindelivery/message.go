@@ -13,6 +13,10 @@ const (
 	// TODO: localize these when i18n is available.
 	defCommentTemplate     = "@%s mentioned you in a comment on the card [%s](%s) in board [%s](%s)\n> %s"
 	defDescriptionTemplate = "@%s mentioned you in the card [%s](%s) in board [%s](%s)\n> %s"
+
+	defReminderBeforeTemplate  = "The card [%s](%s) in board [%s](%s) is due in %s (%s)"
+	defReminderDueTemplate     = "The card [%s](%s) in board [%s](%s) is due (%s)"
+	defReminderOverdueTemplate = "The card [%s](%s) in board [%s](%s) is overdue by %s (%s)"
 )
 
 func formatMessage(author string, extract string, card string, link string, block *model.Block, boardLink string, board string) string {
@@ -22,3 +26,30 @@ func formatMessage(author string, extract string, card string, link string, bloc
 	}
 	return fmt.Sprintf(template, author, card, link, board, boardLink, extract)
 }
+
+func formatReminderMessage(card string, link string, board string, boardLink string, property string, offset int64) string {
+	switch {
+	case offset < 0:
+		return fmt.Sprintf(defReminderBeforeTemplate, card, link, board, boardLink, formatReminderOffset(-offset), property)
+	case offset > 0:
+		return fmt.Sprintf(defReminderOverdueTemplate, card, link, board, boardLink, formatReminderOffset(offset), property)
+	default:
+		return fmt.Sprintf(defReminderDueTemplate, card, link, board, boardLink, property)
+	}
+}
+
+// formatReminderOffset describes an offset in minutes with the largest unit
+// that divides it.
+func formatReminderOffset(minutes int64) string {
+	value, unit := minutes, "minute"
+	switch {
+	case minutes%(24*60) == 0:
+		value, unit = minutes/(24*60), "day"
+	case minutes%60 == 0:
+		value, unit = minutes/60, "hour"
+	}
+	if value != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", value, unit)
+}
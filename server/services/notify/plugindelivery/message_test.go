@@ -0,0 +1,47 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatReminderMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int64
+		want   string
+	}{
+		{"before", -24 * 60, "The card [Card](card-link) in board [Board](board-link) is due in 1 day (Due)"},
+		{"due", 0, "The card [Card](card-link) in board [Board](board-link) is due (Due)"},
+		{"overdue", 90, "The card [Card](card-link) in board [Board](board-link) is overdue by 90 minutes (Due)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatReminderMessage("Card", "card-link", "Board", "board-link", "Due", tt.offset)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatReminderOffset(t *testing.T) {
+	tests := []struct {
+		minutes int64
+		want    string
+	}{
+		{1, "1 minute"},
+		{45, "45 minutes"},
+		{60, "1 hour"},
+		{120, "2 hours"},
+		{24 * 60, "1 day"},
+		{3 * 24 * 60, "3 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatReminderOffset(tt.minutes))
+		})
+	}
+}
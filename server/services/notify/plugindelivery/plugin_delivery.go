@@ -21,6 +21,15 @@ type servicesAPI interface {
 	// GetUserByUsername gets a user by their username.
 	GetUserByUsername(name string) (*mm_model.User, error)
 
+	// GetUserStatusesByIds gets the statuses of the specified users.
+	GetUserStatusesByIds(userIDs []string) ([]*mm_model.Status, error)
+
+	// GetGroupByName gets a user group by its name.
+	GetGroupByName(name string) (*mm_model.Group, error)
+
+	// GetGroupMemberUsers gets a page of the users that belong to a group.
+	GetGroupMemberUsers(groupID string, page, perPage int) ([]*mm_model.User, error)
+
 	// GetTeamMember gets a team member by their user id.
 	GetTeamMember(teamID string, userID string) (*mm_model.TeamMember, error)
 
@@ -0,0 +1,41 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+// DueDateReminderDeliver sends the reminder of a card's due date to a user via the plugin API.
+func (pd *PluginDelivery) DueDateReminderDeliver(userID string, evt notify.DueDateReminderEvent) error {
+	if _, err := pd.api.GetUserByID(userID); err != nil {
+		if model.IsErrNotFound(err) {
+			// the user no longer exists; fail silently.
+			return nil
+		}
+		return fmt.Errorf("cannot find user: %w", err)
+	}
+
+	channel, err := pd.getDirectChannel(evt.TeamID, userID, pd.botID)
+	if err != nil {
+		return fmt.Errorf("cannot get direct channel: %w", err)
+	}
+	link := utils.MakeCardLink(pd.serverRoot, evt.Board.TeamID, evt.Board.ID, evt.Card.ID)
+	boardLink := utils.MakeBoardLink(pd.serverRoot, evt.Board.TeamID, evt.Board.ID)
+
+	post := &mm_model.Post{
+		UserId:    pd.botID,
+		ChannelId: channel.Id,
+		Message:   formatReminderMessage(evt.Card.Title, link, evt.Board.Title, boardLink, evt.PropertyName, evt.Offset),
+	}
+
+	_, err = pd.api.CreatePost(post)
+	return err
+}
@@ -16,17 +16,38 @@ var (
 	ErrUnsupportedSubscriberType = errors.New("invalid subscriber type")
 )
 
-// SubscriptionDeliverSlashAttachments notifies a user that changes were made to a block they are subscribed to.
+// SubscriptionDeliverSlashAttachments notifies a user or posts to a channel that changes were made to a block they are subscribed to.
 func (pd *PluginDelivery) SubscriptionDeliverSlackAttachments(teamID string, subscriberID string, subscriptionType model.SubscriberType,
 	attachments []*mm_model.SlackAttachment) error {
-	// check subscriber is member of channel
-	_, err := pd.api.GetUserByID(subscriberID)
-	if err != nil {
-		if model.IsErrNotFound(err) {
-			// subscriber is not a member of the channel; fail silently.
+	switch subscriptionType {
+	case model.SubTypeChannel:
+		// check the channel still exists
+		channel, err := pd.api.GetChannelByID(subscriberID)
+		if err != nil {
+			if model.IsErrNotFound(err) {
+				// channel was removed; fail silently.
+				return nil
+			}
+			return fmt.Errorf("cannot fetch channel %s: %w", subscriberID, err)
+		}
+		if channel.DeleteAt != 0 {
 			return nil
 		}
-		return fmt.Errorf("cannot fetch channel member for user %s: %w", subscriberID, err)
+
+		// the bot must be a member of the team to post in its channels.
+		if _, err = pd.api.CreateMember(teamID, pd.botID); err != nil {
+			return fmt.Errorf("cannot add bot to team %s: %w", teamID, err)
+		}
+	default:
+		// check subscriber is member of channel
+		_, err := pd.api.GetUserByID(subscriberID)
+		if err != nil {
+			if model.IsErrNotFound(err) {
+				// subscriber is not a member of the channel; fail silently.
+				return nil
+			}
+			return fmt.Errorf("cannot fetch channel member for user %s: %w", subscriberID, err)
+		}
 	}
 
 	channelID, err := pd.getDirectChannelID(teamID, subscriberID, subscriptionType, pd.botID)
@@ -45,6 +66,31 @@ func (pd *PluginDelivery) SubscriptionDeliverSlackAttachments(teamID string, sub
 	return err
 }
 
+// SubscriptionDeliverDigest sends a user the digest of the changes made to the blocks they subscribed to in digest mode.
+func (pd *PluginDelivery) SubscriptionDeliverDigest(teamID string, userID string, message string) error {
+	if _, err := pd.api.GetUserByID(userID); err != nil {
+		if model.IsErrNotFound(err) {
+			// the user no longer exists; fail silently.
+			return nil
+		}
+		return fmt.Errorf("cannot find user: %w", err)
+	}
+
+	channel, err := pd.getDirectChannel(teamID, userID, pd.botID)
+	if err != nil {
+		return fmt.Errorf("cannot get direct channel: %w", err)
+	}
+
+	post := &mm_model.Post{
+		UserId:    pd.botID,
+		ChannelId: channel.Id,
+		Message:   message,
+	}
+
+	_, err = pd.api.CreatePost(post)
+	return err
+}
+
 func (pd *PluginDelivery) getDirectChannelID(teamID string, subscriberID string, subscriberType model.SubscriberType, botID string) (string, error) {
 	switch subscriberType {
 	case model.SubTypeUser:
@@ -0,0 +1,67 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+type channelServicesAPIMock struct {
+	servicesAPIMock
+	channels map[string]*mm_model.Channel
+	posts    []*mm_model.Post
+}
+
+func (m *channelServicesAPIMock) GetChannelByID(channelID string) (*mm_model.Channel, error) {
+	channel, ok := m.channels[channelID]
+	if !ok {
+		return nil, model.NewErrNotFound(channelID)
+	}
+	return channel, nil
+}
+
+func (m *channelServicesAPIMock) CreatePost(post *mm_model.Post) (*mm_model.Post, error) {
+	m.posts = append(m.posts, post)
+	return post, nil
+}
+
+func TestSubscriptionDeliverSlackAttachmentsToChannel(t *testing.T) {
+	channel := &mm_model.Channel{Id: mm_model.NewId(), TeamId: defTeamID}
+	deletedChannel := &mm_model.Channel{Id: mm_model.NewId(), TeamId: defTeamID, DeleteAt: 1}
+
+	servicesAPI := &channelServicesAPIMock{
+		servicesAPIMock: newServicesAPIMock(mockUsers),
+		channels: map[string]*mm_model.Channel{
+			channel.Id:        channel,
+			deletedChannel.Id: deletedChannel,
+		},
+	}
+	delivery := New("bot_id", "server_root", servicesAPI)
+	attachments := []*mm_model.SlackAttachment{{Pretext: "card changed"}}
+
+	t.Run("posts into the channel", func(t *testing.T) {
+		err := delivery.SubscriptionDeliverSlackAttachments(defTeamID, channel.Id, model.SubTypeChannel, attachments)
+		require.NoError(t, err)
+		require.Len(t, servicesAPI.posts, 1)
+		require.Equal(t, channel.Id, servicesAPI.posts[0].ChannelId)
+		require.Equal(t, "bot_id", servicesAPI.posts[0].UserId)
+		require.NotEmpty(t, servicesAPI.posts[0].Attachments())
+	})
+
+	t.Run("deleted or missing channels are skipped", func(t *testing.T) {
+		servicesAPI.posts = nil
+
+		err := delivery.SubscriptionDeliverSlackAttachments(defTeamID, deletedChannel.Id, model.SubTypeChannel, attachments)
+		require.NoError(t, err)
+
+		err = delivery.SubscriptionDeliverSlackAttachments(defTeamID, mm_model.NewId(), model.SubTypeChannel, attachments)
+		require.NoError(t, err)
+		require.Empty(t, servicesAPI.posts)
+	})
+}
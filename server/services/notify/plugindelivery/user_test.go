@@ -118,6 +118,18 @@ func (m servicesAPIMock) GetUserByID(userID string) (*mm_model.User, error) {
 	return nil, model.NewErrNotFound(userID)
 }
 
+func (m servicesAPIMock) GetUserStatusesByIds(userIDs []string) ([]*mm_model.Status, error) {
+	return nil, nil
+}
+
+func (m servicesAPIMock) GetGroupByName(name string) (*mm_model.Group, error) {
+	return nil, model.NewErrNotFound(name)
+}
+
+func (m servicesAPIMock) GetGroupMemberUsers(groupID string, page, perPage int) ([]*mm_model.User, error) {
+	return nil, nil
+}
+
 func (m servicesAPIMock) GetTeamMember(teamID string, userID string) (*mm_model.TeamMember, error) {
 	user, err := m.GetUserByID(userID)
 	if err != nil {
@@ -30,6 +30,25 @@ type BlockChangeEvent struct {
 	ModifiedBy   *model.BoardMember
 }
 
+type MemberChangeEvent struct {
+	Action Action
+	TeamID string
+	Board  *model.Board
+	Member *model.BoardMember
+}
+
+// DueDateReminderEvent describes the reminder of a card's due date. The
+// offset, in minutes, is negative before the due date and positive when
+// the card is overdue.
+type DueDateReminderEvent struct {
+	TeamID       string
+	Board        *model.Board
+	Card         *model.Block
+	PropertyName string
+	DueAt        int64
+	Offset       int64
+}
+
 // Backend provides an interface for sending notifications.
 type Backend interface {
 	Start() error
@@ -38,6 +57,12 @@ type Backend interface {
 	Name() string
 }
 
+// MemberChangeBackend is implemented by backends that also want to be
+// informed of board membership changes.
+type MemberChangeBackend interface {
+	MemberChanged(evt MemberChangeEvent) error
+}
+
 // Service is a service that sends notifications based on block activity using one or more backends.
 type Service struct {
 	mux      sync.RWMutex
@@ -106,3 +131,25 @@ func (s *Service) BlockChanged(evt BlockChangeEvent) {
 		}
 	}
 }
+
+// MemberChanged should be called whenever a board member is added.
+// The backends that implement MemberChangeBackend are informed of the event.
+func (s *Service) MemberChanged(evt MemberChangeEvent) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	for _, backend := range s.backends {
+		memberBackend, ok := backend.(MemberChangeBackend)
+		if !ok {
+			continue
+		}
+		if err := memberBackend.MemberChanged(evt); err != nil {
+			s.logger.Error("Error delivering member change notification",
+				mlog.String("backend", backend.Name()),
+				mlog.String("action", string(evt.Action)),
+				mlog.String("board_id", evt.Board.ID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
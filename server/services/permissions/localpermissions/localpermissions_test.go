@@ -69,6 +69,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
 			model.PermissionDeleteOthersComments,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
 			model.PermissionCommentBoardCards,
@@ -100,6 +101,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
 			model.PermissionDeleteOthersComments,
+			model.PermissionManageBoardWebhooks,
 		}
 
 		th.checkBoardPermissions("editor", member, hasPermissionTo, hasNotPermissionTo)
@@ -123,6 +125,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
 			model.PermissionDeleteOthersComments,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
 		}
@@ -147,6 +150,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
 			model.PermissionDeleteOthersComments,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
 			model.PermissionCommentBoardCards,
@@ -170,6 +174,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 				model.PermissionManageBoardRoles,
 				model.PermissionShareBoard,
 				model.PermissionDeleteOthersComments,
+				model.PermissionManageBoardWebhooks,
 				model.PermissionManageBoardCards,
 				model.PermissionManageBoardProperties,
 				model.PermissionCommentBoardCards,
@@ -200,6 +205,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 				model.PermissionManageBoardRoles,
 				model.PermissionShareBoard,
 				model.PermissionDeleteOthersComments,
+				model.PermissionManageBoardWebhooks,
 			}
 
 			th.checkBoardPermissions("viewer-with-editor-minimum-role", member, hasPermissionTo, hasNotPermissionTo)
@@ -174,6 +174,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 		}
 
 		for _, p := range adminOnlyPermissions {
@@ -219,6 +220,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionViewBoard,
 			model.PermissionManageBoardProperties,
@@ -247,6 +249,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 		}
 
 		th.checkBoardPermissions("editor", member, teamID, hasPermissionTo, hasNotPermissionTo)
@@ -268,6 +271,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
 		}
@@ -291,6 +295,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
 		}
@@ -310,6 +315,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionManageBoardWebhooks,
 			model.PermissionManageBoardCards,
 			model.PermissionViewBoard,
 			model.PermissionManageBoardProperties,
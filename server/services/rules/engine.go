@@ -0,0 +1,103 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rules
+
+import (
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// Store is the part of the store used to evaluate board rules.
+type Store interface {
+	GetBoardRulesForBoard(boardID string) ([]*model.BoardRule, error)
+	GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error)
+	InsertBoardRuleRun(run *model.BoardRuleRun) error
+	GetBoardsBotID() (string, error)
+}
+
+// Engine finds the board rules triggered by card changes, and keeps the
+// audit trail of their runs. Applying the actions is left to the app, so
+// that the changes are broadcast and notified like any other.
+type Engine struct {
+	store  Store
+	logger mlog.LoggerIFace
+}
+
+// New creates a new Engine.
+func New(store Store, logger mlog.LoggerIFace) *Engine {
+	return &Engine{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// MatchingRules returns the enabled rules of the board of the card that
+// are triggered by the change.
+func (e *Engine) MatchingRules(change *model.RuleChange) ([]*model.BoardRule, error) {
+	rules, err := e.store.GetBoardRulesForBoard(change.Card.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []*model.BoardRule{}
+	for _, rule := range rules {
+		if !rule.Enabled || !rule.Trigger.Matches(change) {
+			continue
+		}
+		matching = append(matching, rule)
+	}
+
+	if len(matching) == 0 || change.Type != model.RuleTriggerCheckboxesChecked {
+		return matching, nil
+	}
+
+	allChecked, err := e.allCheckboxesChecked(change.Card)
+	if err != nil {
+		return nil, err
+	}
+	if !allChecked {
+		return []*model.BoardRule{}, nil
+	}
+	return matching, nil
+}
+
+func (e *Engine) allCheckboxesChecked(card *model.Block) (bool, error) {
+	checkboxes, err := e.store.GetBlocks(model.QueryBlocksOptions{
+		BoardID:   card.BoardID,
+		ParentID:  card.ID,
+		BlockType: model.TypeCheckbox,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(checkboxes) == 0 {
+		return false, nil
+	}
+	for _, checkbox := range checkboxes {
+		if checked, _ := checkbox.Fields["value"].(bool); !checked {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// BotID returns the id of the Boards bot, which applies the actions of
+// the rules.
+func (e *Engine) BotID() (string, error) {
+	return e.store.GetBoardsBotID()
+}
+
+// RecordRun adds a run to the audit trail of its rule. Errors are logged,
+// as they must not stop the chain of rules.
+func (e *Engine) RecordRun(run *model.BoardRuleRun) {
+	if err := e.store.InsertBoardRuleRun(run); err != nil {
+		e.logger.Error("Cannot record board rule run",
+			mlog.String("rule_id", run.RuleID),
+			mlog.String("card_id", run.CardID),
+			mlog.Err(err),
+		)
+	}
+}
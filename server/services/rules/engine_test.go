@@ -0,0 +1,91 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rules
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type testStore struct {
+	rules      []*model.BoardRule
+	checkboxes []*model.Block
+	runs       []*model.BoardRuleRun
+}
+
+func (s *testStore) GetBoardRulesForBoard(boardID string) ([]*model.BoardRule, error) {
+	return s.rules, nil
+}
+
+func (s *testStore) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
+	return s.checkboxes, nil
+}
+
+func (s *testStore) InsertBoardRuleRun(run *model.BoardRuleRun) error {
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *testStore) GetBoardsBotID() (string, error) {
+	return "bot-id", nil
+}
+
+func TestMatchingRules(t *testing.T) {
+	card := &model.Block{ID: "card-id", BoardID: "board-id", Type: model.TypeCard}
+	checkedRule := &model.BoardRule{
+		ID:      "checked-rule",
+		Enabled: true,
+		Trigger: model.RuleTrigger{Type: model.RuleTriggerCheckboxesChecked},
+	}
+	createdRule := &model.BoardRule{
+		ID:      "created-rule",
+		Enabled: true,
+		Trigger: model.RuleTrigger{Type: model.RuleTriggerCardCreated},
+	}
+	disabledRule := &model.BoardRule{
+		ID:      "disabled-rule",
+		Trigger: model.RuleTrigger{Type: model.RuleTriggerCardCreated},
+	}
+
+	checkbox := func(checked bool) *model.Block {
+		return &model.Block{Type: model.TypeCheckbox, Fields: map[string]any{"value": checked}}
+	}
+
+	t.Run("only enabled rules with a matching trigger", func(t *testing.T) {
+		store := &testStore{rules: []*model.BoardRule{checkedRule, createdRule, disabledRule}}
+		engine := New(store, mlog.CreateConsoleTestLogger(t))
+
+		rules, err := engine.MatchingRules(&model.RuleChange{Type: model.RuleTriggerCardCreated, Card: card})
+		require.NoError(t, err)
+		require.Equal(t, []*model.BoardRule{createdRule}, rules)
+	})
+
+	t.Run("all checkboxes checked", func(t *testing.T) {
+		store := &testStore{
+			rules:      []*model.BoardRule{checkedRule, createdRule},
+			checkboxes: []*model.Block{checkbox(true), checkbox(true)},
+		}
+		engine := New(store, mlog.CreateConsoleTestLogger(t))
+
+		rules, err := engine.MatchingRules(&model.RuleChange{Type: model.RuleTriggerCheckboxesChecked, Card: card})
+		require.NoError(t, err)
+		require.Equal(t, []*model.BoardRule{checkedRule}, rules)
+	})
+
+	t.Run("some checkboxes unchecked", func(t *testing.T) {
+		store := &testStore{
+			rules:      []*model.BoardRule{checkedRule},
+			checkboxes: []*model.Block{checkbox(true), checkbox(false)},
+		}
+		engine := New(store, mlog.CreateConsoleTestLogger(t))
+
+		rules, err := engine.MatchingRules(&model.RuleChange{Type: model.RuleTriggerCheckboxesChecked, Card: card})
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
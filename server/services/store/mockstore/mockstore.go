@@ -50,6 +50,21 @@ func (mr *MockStoreMockRecorder) AddUpdateCategoryBoard(userID, categoryID, boar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUpdateCategoryBoard", reflect.TypeOf((*MockStore)(nil).AddUpdateCategoryBoard), userID, categoryID, boardIDs)
 }
 
+// AdvanceCardRecurrence mocks base method.
+func (m *MockStore) AdvanceCardRecurrence(cardID string, runAt, nextRunAt int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceCardRecurrence", cardID, runAt, nextRunAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceCardRecurrence indicates an expected call of AdvanceCardRecurrence.
+func (mr *MockStoreMockRecorder) AdvanceCardRecurrence(cardID, runAt, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceCardRecurrence", reflect.TypeOf((*MockStore)(nil).AdvanceCardRecurrence), cardID, runAt, nextRunAt)
+}
+
 // CanSeeUser mocks base method.
 func (m *MockStore) CanSeeUser(seerID, seenID string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,21 @@ func (mr *MockStoreMockRecorder) CanSeeUser(seerID, seenID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSeeUser", reflect.TypeOf((*MockStore)(nil).CanSeeUser), seerID, seenID)
 }
 
+// ClaimWebhookDelivery mocks base method.
+func (m *MockStore) ClaimWebhookDelivery(deliveryID string, nextAttemptAt, leaseUntil int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDelivery", deliveryID, nextAttemptAt, leaseUntil)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDelivery indicates an expected call of ClaimWebhookDelivery.
+func (mr *MockStoreMockRecorder) ClaimWebhookDelivery(deliveryID, nextAttemptAt, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDelivery), deliveryID, nextAttemptAt, leaseUntil)
+}
+
 // CreateBoardsAndBlocks mocks base method.
 func (m *MockStore) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +126,22 @@ func (mr *MockStoreMockRecorder) CreateBoardsAndBlocksWithAdmin(bab, userID inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBoardsAndBlocksWithAdmin", reflect.TypeOf((*MockStore)(nil).CreateBoardsAndBlocksWithAdmin), bab, userID)
 }
 
+// CreateBoardsAndBlocksWithMembers mocks base method.
+func (m *MockStore) CreateBoardsAndBlocksWithMembers(bab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBoardsAndBlocksWithMembers", bab, members, userID)
+	ret0, _ := ret[0].(*model.BoardsAndBlocks)
+	ret1, _ := ret[1].([]*model.BoardMember)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateBoardsAndBlocksWithMembers indicates an expected call of CreateBoardsAndBlocksWithMembers.
+func (mr *MockStoreMockRecorder) CreateBoardsAndBlocksWithMembers(bab, members, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBoardsAndBlocksWithMembers", reflect.TypeOf((*MockStore)(nil).CreateBoardsAndBlocksWithMembers), bab, members, userID)
+}
+
 // CreateCategory mocks base method.
 func (m *MockStore) CreateCategory(category model.Category) error {
 	m.ctrl.T.Helper()
@@ -209,6 +255,34 @@ func (mr *MockStoreMockRecorder) DeleteBoardRecord(boardID, modifiedBy interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardRecord", reflect.TypeOf((*MockStore)(nil).DeleteBoardRecord), boardID, modifiedBy)
 }
 
+// DeleteBoardRule mocks base method.
+func (m *MockStore) DeleteBoardRule(ruleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBoardRule", ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBoardRule indicates an expected call of DeleteBoardRule.
+func (mr *MockStoreMockRecorder) DeleteBoardRule(ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardRule", reflect.TypeOf((*MockStore)(nil).DeleteBoardRule), ruleID)
+}
+
+// DeleteBoardWebhook mocks base method.
+func (m *MockStore) DeleteBoardWebhook(webhookID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBoardWebhook", webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBoardWebhook indicates an expected call of DeleteBoardWebhook.
+func (mr *MockStoreMockRecorder) DeleteBoardWebhook(webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardWebhook", reflect.TypeOf((*MockStore)(nil).DeleteBoardWebhook), webhookID)
+}
+
 // DeleteBoardsAndBlocks mocks base method.
 func (m *MockStore) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
 	m.ctrl.T.Helper()
@@ -223,6 +297,34 @@ func (mr *MockStoreMockRecorder) DeleteBoardsAndBlocks(dbab, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardsAndBlocks", reflect.TypeOf((*MockStore)(nil).DeleteBoardsAndBlocks), dbab, userID)
 }
 
+// DeleteCardLink mocks base method.
+func (m *MockStore) DeleteCardLink(linkID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCardLink", linkID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCardLink indicates an expected call of DeleteCardLink.
+func (mr *MockStoreMockRecorder) DeleteCardLink(linkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCardLink", reflect.TypeOf((*MockStore)(nil).DeleteCardLink), linkID)
+}
+
+// DeleteCardRecurrence mocks base method.
+func (m *MockStore) DeleteCardRecurrence(cardID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCardRecurrence", cardID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCardRecurrence indicates an expected call of DeleteCardRecurrence.
+func (mr *MockStoreMockRecorder) DeleteCardRecurrence(cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCardRecurrence", reflect.TypeOf((*MockStore)(nil).DeleteCardRecurrence), cardID)
+}
+
 // DeleteCategory mocks base method.
 func (m *MockStore) DeleteCategory(categoryID, userID, teamID string) error {
 	m.ctrl.T.Helper()
@@ -237,6 +339,35 @@ func (mr *MockStoreMockRecorder) DeleteCategory(categoryID, userID, teamID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), categoryID, userID, teamID)
 }
 
+// DeleteDueDateRemindersSentBefore mocks base method.
+func (m *MockStore) DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDueDateRemindersSentBefore", sentAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDueDateRemindersSentBefore indicates an expected call of DeleteDueDateRemindersSentBefore.
+func (mr *MockStoreMockRecorder) DeleteDueDateRemindersSentBefore(sentAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDueDateRemindersSentBefore", reflect.TypeOf((*MockStore)(nil).DeleteDueDateRemindersSentBefore), sentAt)
+}
+
+// DeleteImportSession mocks base method.
+func (m *MockStore) DeleteImportSession(sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImportSession", sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImportSession indicates an expected call of DeleteImportSession.
+func (mr *MockStoreMockRecorder) DeleteImportSession(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImportSession", reflect.TypeOf((*MockStore)(nil).DeleteImportSession), sessionID)
+}
+
 // DeleteMember mocks base method.
 func (m *MockStore) DeleteMember(boardID, userID string) error {
 	m.ctrl.T.Helper()
@@ -279,6 +410,49 @@ func (mr *MockStoreMockRecorder) DeleteSubscription(blockID, subscriberID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockStore)(nil).DeleteSubscription), blockID, subscriberID)
 }
 
+// DeleteTimeEntry mocks base method.
+func (m *MockStore) DeleteTimeEntry(entryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTimeEntry", entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTimeEntry indicates an expected call of DeleteTimeEntry.
+func (mr *MockStoreMockRecorder) DeleteTimeEntry(entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTimeEntry", reflect.TypeOf((*MockStore)(nil).DeleteTimeEntry), entryID)
+}
+
+// DeleteWebhookDeliveriesFinishedBefore mocks base method.
+func (m *MockStore) DeleteWebhookDeliveriesFinishedBefore(updatedAt int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDeliveriesFinishedBefore", updatedAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookDeliveriesFinishedBefore indicates an expected call of DeleteWebhookDeliveriesFinishedBefore.
+func (mr *MockStoreMockRecorder) DeleteWebhookDeliveriesFinishedBefore(updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDeliveriesFinishedBefore", reflect.TypeOf((*MockStore)(nil).DeleteWebhookDeliveriesFinishedBefore), updatedAt)
+}
+
+// DisableCardRecurrence mocks base method.
+func (m *MockStore) DisableCardRecurrence(cardID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableCardRecurrence", cardID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableCardRecurrence indicates an expected call of DisableCardRecurrence.
+func (mr *MockStoreMockRecorder) DisableCardRecurrence(cardID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableCardRecurrence", reflect.TypeOf((*MockStore)(nil).DisableCardRecurrence), cardID, reason)
+}
+
 // DuplicateBlock mocks base method.
 func (m *MockStore) DuplicateBlock(boardID, blockID, userID string, asTemplate bool) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +484,21 @@ func (mr *MockStoreMockRecorder) DuplicateBoard(boardID, userID, toTeam, asTempl
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DuplicateBoard", reflect.TypeOf((*MockStore)(nil).DuplicateBoard), boardID, userID, toTeam, asTemplate)
 }
 
+// GetActiveExportJobs mocks base method.
+func (m *MockStore) GetActiveExportJobs(limit int) ([]*model.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveExportJobs", limit)
+	ret0, _ := ret[0].([]*model.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveExportJobs indicates an expected call of GetActiveExportJobs.
+func (mr *MockStoreMockRecorder) GetActiveExportJobs(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveExportJobs", reflect.TypeOf((*MockStore)(nil).GetActiveExportJobs), limit)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +574,21 @@ func (mr *MockStoreMockRecorder) GetBlockHistory(blockID, opts interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHistory", reflect.TypeOf((*MockStore)(nil).GetBlockHistory), blockID, opts)
 }
 
+// GetBlockHistoryChildren mocks base method.
+func (m *MockStore) GetBlockHistoryChildren(parentID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHistoryChildren", parentID, opts)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHistoryChildren indicates an expected call of GetBlockHistoryChildren.
+func (mr *MockStoreMockRecorder) GetBlockHistoryChildren(parentID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHistoryChildren", reflect.TypeOf((*MockStore)(nil).GetBlockHistoryChildren), parentID, opts)
+}
+
 // GetBlockHistoryDescendants mocks base method.
 func (m *MockStore) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -599,6 +803,21 @@ func (mr *MockStoreMockRecorder) GetBoardHistory(boardID, opts interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardHistory", reflect.TypeOf((*MockStore)(nil).GetBoardHistory), boardID, opts)
 }
 
+// GetBoardIDsChangedSince mocks base method.
+func (m *MockStore) GetBoardIDsChangedSince(teamID string, since int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardIDsChangedSince", teamID, since)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardIDsChangedSince indicates an expected call of GetBoardIDsChangedSince.
+func (mr *MockStoreMockRecorder) GetBoardIDsChangedSince(teamID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardIDsChangedSince", reflect.TypeOf((*MockStore)(nil).GetBoardIDsChangedSince), teamID, since)
+}
+
 // GetBoardMemberHistory mocks base method.
 func (m *MockStore) GetBoardMemberHistory(boardID, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
 	m.ctrl.T.Helper()
@@ -614,6 +833,96 @@ func (mr *MockStoreMockRecorder) GetBoardMemberHistory(boardID, userID, limit in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMemberHistory", reflect.TypeOf((*MockStore)(nil).GetBoardMemberHistory), boardID, userID, limit)
 }
 
+// GetBoardRule mocks base method.
+func (m *MockStore) GetBoardRule(ruleID string) (*model.BoardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardRule", ruleID)
+	ret0, _ := ret[0].(*model.BoardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardRule indicates an expected call of GetBoardRule.
+func (mr *MockStoreMockRecorder) GetBoardRule(ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardRule", reflect.TypeOf((*MockStore)(nil).GetBoardRule), ruleID)
+}
+
+// GetBoardRuleRuns mocks base method.
+func (m *MockStore) GetBoardRuleRuns(ruleID string, page, perPage int) ([]*model.BoardRuleRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardRuleRuns", ruleID, page, perPage)
+	ret0, _ := ret[0].([]*model.BoardRuleRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardRuleRuns indicates an expected call of GetBoardRuleRuns.
+func (mr *MockStoreMockRecorder) GetBoardRuleRuns(ruleID, page, perPage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardRuleRuns", reflect.TypeOf((*MockStore)(nil).GetBoardRuleRuns), ruleID, page, perPage)
+}
+
+// GetBoardRulesForBoard mocks base method.
+func (m *MockStore) GetBoardRulesForBoard(boardID string) ([]*model.BoardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardRulesForBoard", boardID)
+	ret0, _ := ret[0].([]*model.BoardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardRulesForBoard indicates an expected call of GetBoardRulesForBoard.
+func (mr *MockStoreMockRecorder) GetBoardRulesForBoard(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardRulesForBoard", reflect.TypeOf((*MockStore)(nil).GetBoardRulesForBoard), boardID)
+}
+
+// GetBoardWebhook mocks base method.
+func (m *MockStore) GetBoardWebhook(webhookID string) (*model.BoardWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardWebhook", webhookID)
+	ret0, _ := ret[0].(*model.BoardWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardWebhook indicates an expected call of GetBoardWebhook.
+func (mr *MockStoreMockRecorder) GetBoardWebhook(webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardWebhook", reflect.TypeOf((*MockStore)(nil).GetBoardWebhook), webhookID)
+}
+
+// GetBoardWebhooksForBoard mocks base method.
+func (m *MockStore) GetBoardWebhooksForBoard(boardID string) ([]*model.BoardWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardWebhooksForBoard", boardID)
+	ret0, _ := ret[0].([]*model.BoardWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardWebhooksForBoard indicates an expected call of GetBoardWebhooksForBoard.
+func (mr *MockStoreMockRecorder) GetBoardWebhooksForBoard(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardWebhooksForBoard", reflect.TypeOf((*MockStore)(nil).GetBoardWebhooksForBoard), boardID)
+}
+
+// GetBoardsBotID mocks base method.
+func (m *MockStore) GetBoardsBotID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsBotID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsBotID indicates an expected call of GetBoardsBotID.
+func (mr *MockStoreMockRecorder) GetBoardsBotID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsBotID", reflect.TypeOf((*MockStore)(nil).GetBoardsBotID))
+}
+
 // GetBoardsComplianceHistory mocks base method.
 func (m *MockStore) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	m.ctrl.T.Helper()
@@ -691,6 +1000,66 @@ func (mr *MockStoreMockRecorder) GetCardLimitTimestamp() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLimitTimestamp", reflect.TypeOf((*MockStore)(nil).GetCardLimitTimestamp))
 }
 
+// GetCardLink mocks base method.
+func (m *MockStore) GetCardLink(linkID string) (*model.CardLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardLink", linkID)
+	ret0, _ := ret[0].(*model.CardLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardLink indicates an expected call of GetCardLink.
+func (mr *MockStoreMockRecorder) GetCardLink(linkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLink", reflect.TypeOf((*MockStore)(nil).GetCardLink), linkID)
+}
+
+// GetCardLinksForBoard mocks base method.
+func (m *MockStore) GetCardLinksForBoard(boardID string) ([]*model.CardLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardLinksForBoard", boardID)
+	ret0, _ := ret[0].([]*model.CardLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardLinksForBoard indicates an expected call of GetCardLinksForBoard.
+func (mr *MockStoreMockRecorder) GetCardLinksForBoard(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLinksForBoard", reflect.TypeOf((*MockStore)(nil).GetCardLinksForBoard), boardID)
+}
+
+// GetCardLinksForCard mocks base method.
+func (m *MockStore) GetCardLinksForCard(cardID string) ([]*model.CardLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardLinksForCard", cardID)
+	ret0, _ := ret[0].([]*model.CardLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardLinksForCard indicates an expected call of GetCardLinksForCard.
+func (mr *MockStoreMockRecorder) GetCardLinksForCard(cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLinksForCard", reflect.TypeOf((*MockStore)(nil).GetCardLinksForCard), cardID)
+}
+
+// GetCardRecurrence mocks base method.
+func (m *MockStore) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardRecurrence", cardID)
+	ret0, _ := ret[0].(*model.CardRecurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardRecurrence indicates an expected call of GetCardRecurrence.
+func (mr *MockStoreMockRecorder) GetCardRecurrence(cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardRecurrence", reflect.TypeOf((*MockStore)(nil).GetCardRecurrence), cardID)
+}
+
 // GetCardsCount mocks base method.
 func (m *MockStore) GetCardsCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -721,6 +1090,22 @@ func (mr *MockStoreMockRecorder) GetCategory(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockStore)(nil).GetCategory), id)
 }
 
+// GetChanges mocks base method.
+func (m *MockStore) GetChanges(opts model.QueryChangesOptions) ([]*model.Change, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", opts)
+	ret0, _ := ret[0].([]*model.Change)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockStoreMockRecorder) GetChanges(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockStore)(nil).GetChanges), opts)
+}
+
 // GetChannel mocks base method.
 func (m *MockStore) GetChannel(teamID, channelID string) (*model0.Channel, error) {
 	m.ctrl.T.Helper()
@@ -736,78 +1121,228 @@ func (mr *MockStoreMockRecorder) GetChannel(teamID, channelID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockStore)(nil).GetChannel), teamID, channelID)
 }
 
-// GetFileInfo mocks base method.
-func (m *MockStore) GetFileInfo(id string) (*model0.FileInfo, error) {
+// GetDigestSettings mocks base method.
+func (m *MockStore) GetDigestSettings(userID string) (*model.DigestSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFileInfo", id)
-	ret0, _ := ret[0].(*model0.FileInfo)
+	ret := m.ctrl.Call(m, "GetDigestSettings", userID)
+	ret0, _ := ret[0].(*model.DigestSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFileInfo indicates an expected call of GetFileInfo.
-func (mr *MockStoreMockRecorder) GetFileInfo(id interface{}) *gomock.Call {
+// GetDigestSettings indicates an expected call of GetDigestSettings.
+func (mr *MockStoreMockRecorder) GetDigestSettings(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockStore)(nil).GetFileInfo), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestSettings", reflect.TypeOf((*MockStore)(nil).GetDigestSettings), userID)
 }
 
-// GetLicense mocks base method.
-func (m *MockStore) GetLicense() *model0.License {
+// GetDigestSettingsList mocks base method.
+func (m *MockStore) GetDigestSettingsList() ([]*model.DigestSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLicense")
-	ret0, _ := ret[0].(*model0.License)
-	return ret0
+	ret := m.ctrl.Call(m, "GetDigestSettingsList")
+	ret0, _ := ret[0].([]*model.DigestSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetLicense indicates an expected call of GetLicense.
-func (mr *MockStoreMockRecorder) GetLicense() *gomock.Call {
+// GetDigestSettingsList indicates an expected call of GetDigestSettingsList.
+func (mr *MockStoreMockRecorder) GetDigestSettingsList() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicense", reflect.TypeOf((*MockStore)(nil).GetLicense))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestSettingsList", reflect.TypeOf((*MockStore)(nil).GetDigestSettingsList))
 }
 
-// GetMemberForBoard mocks base method.
-func (m *MockStore) GetMemberForBoard(boardID, userID string) (*model.BoardMember, error) {
+// GetDueCardRecurrences mocks base method.
+func (m *MockStore) GetDueCardRecurrences(now int64, limit int) ([]*model.CardRecurrence, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMemberForBoard", boardID, userID)
-	ret0, _ := ret[0].(*model.BoardMember)
+	ret := m.ctrl.Call(m, "GetDueCardRecurrences", now, limit)
+	ret0, _ := ret[0].([]*model.CardRecurrence)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMemberForBoard indicates an expected call of GetMemberForBoard.
-func (mr *MockStoreMockRecorder) GetMemberForBoard(boardID, userID interface{}) *gomock.Call {
+// GetDueCardRecurrences indicates an expected call of GetDueCardRecurrences.
+func (mr *MockStoreMockRecorder) GetDueCardRecurrences(now, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberForBoard", reflect.TypeOf((*MockStore)(nil).GetMemberForBoard), boardID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueCardRecurrences", reflect.TypeOf((*MockStore)(nil).GetDueCardRecurrences), now, limit)
 }
 
-// GetMembersForBoard mocks base method.
-func (m *MockStore) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+// GetDueDateReminderSettings mocks base method.
+func (m *MockStore) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMembersForBoard", boardID)
-	ret0, _ := ret[0].([]*model.BoardMember)
+	ret := m.ctrl.Call(m, "GetDueDateReminderSettings", boardID)
+	ret0, _ := ret[0].(*model.DueDateReminderSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMembersForBoard indicates an expected call of GetMembersForBoard.
-func (mr *MockStoreMockRecorder) GetMembersForBoard(boardID interface{}) *gomock.Call {
+// GetDueDateReminderSettings indicates an expected call of GetDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) GetDueDateReminderSettings(boardID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembersForBoard", reflect.TypeOf((*MockStore)(nil).GetMembersForBoard), boardID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).GetDueDateReminderSettings), boardID)
 }
 
-// GetMembersForUser mocks base method.
-func (m *MockStore) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
+// GetEnabledDueDateReminderSettings mocks base method.
+func (m *MockStore) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMembersForUser", userID)
-	ret0, _ := ret[0].([]*model.BoardMember)
+	ret := m.ctrl.Call(m, "GetEnabledDueDateReminderSettings")
+	ret0, _ := ret[0].([]*model.DueDateReminderSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMembersForUser indicates an expected call of GetMembersForUser.
-func (mr *MockStoreMockRecorder) GetMembersForUser(userID interface{}) *gomock.Call {
+// GetEnabledDueDateReminderSettings indicates an expected call of GetEnabledDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) GetEnabledDueDateReminderSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembersForUser", reflect.TypeOf((*MockStore)(nil).GetMembersForUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).GetEnabledDueDateReminderSettings))
+}
+
+// GetExportJob mocks base method.
+func (m *MockStore) GetExportJob(jobID string) (*model.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportJob", jobID)
+	ret0, _ := ret[0].(*model.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportJob indicates an expected call of GetExportJob.
+func (mr *MockStoreMockRecorder) GetExportJob(jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportJob", reflect.TypeOf((*MockStore)(nil).GetExportJob), jobID)
+}
+
+// GetExportJobsForTeam mocks base method.
+func (m *MockStore) GetExportJobsForTeam(teamID string, limit int) ([]*model.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportJobsForTeam", teamID, limit)
+	ret0, _ := ret[0].([]*model.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportJobsForTeam indicates an expected call of GetExportJobsForTeam.
+func (mr *MockStoreMockRecorder) GetExportJobsForTeam(teamID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportJobsForTeam", reflect.TypeOf((*MockStore)(nil).GetExportJobsForTeam), teamID, limit)
+}
+
+// GetFileInfo mocks base method.
+func (m *MockStore) GetFileInfo(id string) (*model0.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileInfo", id)
+	ret0, _ := ret[0].(*model0.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileInfo indicates an expected call of GetFileInfo.
+func (mr *MockStoreMockRecorder) GetFileInfo(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockStore)(nil).GetFileInfo), id)
+}
+
+// GetImportSession mocks base method.
+func (m *MockStore) GetImportSession(sessionID string) (*model.ImportSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportSession", sessionID)
+	ret0, _ := ret[0].(*model.ImportSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportSession indicates an expected call of GetImportSession.
+func (mr *MockStoreMockRecorder) GetImportSession(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportSession", reflect.TypeOf((*MockStore)(nil).GetImportSession), sessionID)
+}
+
+// GetImportSessionsUpdatedBefore mocks base method.
+func (m *MockStore) GetImportSessionsUpdatedBefore(before int64, limit int) ([]*model.ImportSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportSessionsUpdatedBefore", before, limit)
+	ret0, _ := ret[0].([]*model.ImportSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportSessionsUpdatedBefore indicates an expected call of GetImportSessionsUpdatedBefore.
+func (mr *MockStoreMockRecorder) GetImportSessionsUpdatedBefore(before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportSessionsUpdatedBefore", reflect.TypeOf((*MockStore)(nil).GetImportSessionsUpdatedBefore), before, limit)
+}
+
+// GetIncomingWebhook mocks base method.
+func (m *MockStore) GetIncomingWebhook(boardID string) (*model.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhook", boardID)
+	ret0, _ := ret[0].(*model.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhook indicates an expected call of GetIncomingWebhook.
+func (mr *MockStoreMockRecorder) GetIncomingWebhook(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhook", reflect.TypeOf((*MockStore)(nil).GetIncomingWebhook), boardID)
+}
+
+// GetLicense mocks base method.
+func (m *MockStore) GetLicense() *model0.License {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicense")
+	ret0, _ := ret[0].(*model0.License)
+	return ret0
+}
+
+// GetLicense indicates an expected call of GetLicense.
+func (mr *MockStoreMockRecorder) GetLicense() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicense", reflect.TypeOf((*MockStore)(nil).GetLicense))
+}
+
+// GetMemberForBoard mocks base method.
+func (m *MockStore) GetMemberForBoard(boardID, userID string) (*model.BoardMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberForBoard", boardID, userID)
+	ret0, _ := ret[0].(*model.BoardMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberForBoard indicates an expected call of GetMemberForBoard.
+func (mr *MockStoreMockRecorder) GetMemberForBoard(boardID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberForBoard", reflect.TypeOf((*MockStore)(nil).GetMemberForBoard), boardID, userID)
+}
+
+// GetMembersForBoard mocks base method.
+func (m *MockStore) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembersForBoard", boardID)
+	ret0, _ := ret[0].([]*model.BoardMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembersForBoard indicates an expected call of GetMembersForBoard.
+func (mr *MockStoreMockRecorder) GetMembersForBoard(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembersForBoard", reflect.TypeOf((*MockStore)(nil).GetMembersForBoard), boardID)
+}
+
+// GetMembersForUser mocks base method.
+func (m *MockStore) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembersForUser", userID)
+	ret0, _ := ret[0].([]*model.BoardMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembersForUser indicates an expected call of GetMembersForUser.
+func (mr *MockStoreMockRecorder) GetMembersForUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembersForUser", reflect.TypeOf((*MockStore)(nil).GetMembersForUser), userID)
 }
 
 // GetNextNotificationHint mocks base method.
@@ -840,6 +1375,21 @@ func (mr *MockStoreMockRecorder) GetNotificationHint(blockID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationHint", reflect.TypeOf((*MockStore)(nil).GetNotificationHint), blockID)
 }
 
+// GetPendingWebhookDeliveries mocks base method.
+func (m *MockStore) GetPendingWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingWebhookDeliveries", now, limit)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingWebhookDeliveries indicates an expected call of GetPendingWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetPendingWebhookDeliveries(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetPendingWebhookDeliveries), now, limit)
+}
+
 // GetRegisteredUserCount mocks base method.
 func (m *MockStore) GetRegisteredUserCount() (int, error) {
 	m.ctrl.T.Helper()
@@ -855,6 +1405,21 @@ func (mr *MockStoreMockRecorder) GetRegisteredUserCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredUserCount", reflect.TypeOf((*MockStore)(nil).GetRegisteredUserCount))
 }
 
+// GetRunningTimeEntry mocks base method.
+func (m *MockStore) GetRunningTimeEntry(userID string) (*model.TimeEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningTimeEntry", userID)
+	ret0, _ := ret[0].(*model.TimeEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningTimeEntry indicates an expected call of GetRunningTimeEntry.
+func (mr *MockStoreMockRecorder) GetRunningTimeEntry(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningTimeEntry", reflect.TypeOf((*MockStore)(nil).GetRunningTimeEntry), userID)
+}
+
 // GetSharing mocks base method.
 func (m *MockStore) GetSharing(rootID string) (*model.Sharing, error) {
 	m.ctrl.T.Helper()
@@ -1035,6 +1600,97 @@ func (mr *MockStoreMockRecorder) GetTemplateBoards(teamID, userID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateBoards), teamID, userID)
 }
 
+// GetTimeEntriesForBoard mocks base method.
+func (m *MockStore) GetTimeEntriesForBoard(boardID string) ([]*model.TimeEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeEntriesForBoard", boardID)
+	ret0, _ := ret[0].([]*model.TimeEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeEntriesForBoard indicates an expected call of GetTimeEntriesForBoard.
+func (mr *MockStoreMockRecorder) GetTimeEntriesForBoard(boardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeEntriesForBoard", reflect.TypeOf((*MockStore)(nil).GetTimeEntriesForBoard), boardID)
+}
+
+// GetTimeEntriesForCard mocks base method.
+func (m *MockStore) GetTimeEntriesForCard(cardID string) ([]*model.TimeEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeEntriesForCard", cardID)
+	ret0, _ := ret[0].([]*model.TimeEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeEntriesForCard indicates an expected call of GetTimeEntriesForCard.
+func (mr *MockStoreMockRecorder) GetTimeEntriesForCard(cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeEntriesForCard", reflect.TypeOf((*MockStore)(nil).GetTimeEntriesForCard), cardID)
+}
+
+// GetTimeEntriesForCompliance mocks base method.
+func (m *MockStore) GetTimeEntriesForCompliance(opts model.QueryTimeEntriesComplianceOptions) ([]*model.TimeEntry, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeEntriesForCompliance", opts)
+	ret0, _ := ret[0].([]*model.TimeEntry)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTimeEntriesForCompliance indicates an expected call of GetTimeEntriesForCompliance.
+func (mr *MockStoreMockRecorder) GetTimeEntriesForCompliance(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeEntriesForCompliance", reflect.TypeOf((*MockStore)(nil).GetTimeEntriesForCompliance), opts)
+}
+
+// GetTimeEntry mocks base method.
+func (m *MockStore) GetTimeEntry(entryID string) (*model.TimeEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeEntry", entryID)
+	ret0, _ := ret[0].(*model.TimeEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeEntry indicates an expected call of GetTimeEntry.
+func (mr *MockStoreMockRecorder) GetTimeEntry(entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeEntry", reflect.TypeOf((*MockStore)(nil).GetTimeEntry), entryID)
+}
+
+// GetTimeSpent mocks base method.
+func (m *MockStore) GetTimeSpent(opts model.QueryTimeSummaryOptions) ([]*model.TimeSpent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeSpent", opts)
+	ret0, _ := ret[0].([]*model.TimeSpent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeSpent indicates an expected call of GetTimeSpent.
+func (mr *MockStoreMockRecorder) GetTimeSpent(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeSpent", reflect.TypeOf((*MockStore)(nil).GetTimeSpent), opts)
+}
+
+// GetTimeSpentForCards mocks base method.
+func (m *MockStore) GetTimeSpentForCards(cardIDs []string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeSpentForCards", cardIDs)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeSpentForCards indicates an expected call of GetTimeSpentForCards.
+func (mr *MockStoreMockRecorder) GetTimeSpentForCards(cardIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeSpentForCards", reflect.TypeOf((*MockStore)(nil).GetTimeSpentForCards), cardIDs)
+}
+
 // GetUsedCardsCount mocks base method.
 func (m *MockStore) GetUsedCardsCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -1185,6 +1841,21 @@ func (mr *MockStoreMockRecorder) GetUsersList(userIDs, showEmail, showName inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersList", reflect.TypeOf((*MockStore)(nil).GetUsersList), userIDs, showEmail, showName)
 }
 
+// GetWebhookDeliveries mocks base method.
+func (m *MockStore) GetWebhookDeliveries(webhookID string, page, perPage int) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", webhookID, page, perPage)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(webhookID, page, perPage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), webhookID, page, perPage)
+}
+
 // InsertBlock mocks base method.
 func (m *MockStore) InsertBlock(block *model.Block, userID string) error {
 	m.ctrl.T.Helper()
@@ -1228,6 +1899,50 @@ func (mr *MockStoreMockRecorder) InsertBoard(board, userID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoard", reflect.TypeOf((*MockStore)(nil).InsertBoard), board, userID)
 }
 
+// InsertBoardRule mocks base method.
+func (m *MockStore) InsertBoardRule(rule *model.BoardRule) (*model.BoardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBoardRule", rule)
+	ret0, _ := ret[0].(*model.BoardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBoardRule indicates an expected call of InsertBoardRule.
+func (mr *MockStoreMockRecorder) InsertBoardRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardRule", reflect.TypeOf((*MockStore)(nil).InsertBoardRule), rule)
+}
+
+// InsertBoardRuleRun mocks base method.
+func (m *MockStore) InsertBoardRuleRun(run *model.BoardRuleRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBoardRuleRun", run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertBoardRuleRun indicates an expected call of InsertBoardRuleRun.
+func (mr *MockStoreMockRecorder) InsertBoardRuleRun(run interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardRuleRun", reflect.TypeOf((*MockStore)(nil).InsertBoardRuleRun), run)
+}
+
+// InsertBoardWebhook mocks base method.
+func (m *MockStore) InsertBoardWebhook(webhook *model.BoardWebhook) (*model.BoardWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBoardWebhook", webhook)
+	ret0, _ := ret[0].(*model.BoardWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBoardWebhook indicates an expected call of InsertBoardWebhook.
+func (mr *MockStoreMockRecorder) InsertBoardWebhook(webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWebhook", reflect.TypeOf((*MockStore)(nil).InsertBoardWebhook), webhook)
+}
+
 // InsertBoardWithAdmin mocks base method.
 func (m *MockStore) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
 	m.ctrl.T.Helper()
@@ -1244,6 +1959,107 @@ func (mr *MockStoreMockRecorder) InsertBoardWithAdmin(board, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWithAdmin", reflect.TypeOf((*MockStore)(nil).InsertBoardWithAdmin), board, userID)
 }
 
+// InsertCardLink mocks base method.
+func (m *MockStore) InsertCardLink(link *model.CardLink) (*model.CardLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCardLink", link)
+	ret0, _ := ret[0].(*model.CardLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCardLink indicates an expected call of InsertCardLink.
+func (mr *MockStoreMockRecorder) InsertCardLink(link interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCardLink", reflect.TypeOf((*MockStore)(nil).InsertCardLink), link)
+}
+
+// InsertExportJob mocks base method.
+func (m *MockStore) InsertExportJob(job *model.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertExportJob", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertExportJob indicates an expected call of InsertExportJob.
+func (mr *MockStoreMockRecorder) InsertExportJob(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertExportJob", reflect.TypeOf((*MockStore)(nil).InsertExportJob), job)
+}
+
+// InsertImportSession mocks base method.
+func (m *MockStore) InsertImportSession(session *model.ImportSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertImportSession", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertImportSession indicates an expected call of InsertImportSession.
+func (mr *MockStoreMockRecorder) InsertImportSession(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertImportSession", reflect.TypeOf((*MockStore)(nil).InsertImportSession), session)
+}
+
+// InsertTimeEntry mocks base method.
+func (m *MockStore) InsertTimeEntry(entry *model.TimeEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTimeEntry", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertTimeEntry indicates an expected call of InsertTimeEntry.
+func (mr *MockStoreMockRecorder) InsertTimeEntry(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTimeEntry", reflect.TypeOf((*MockStore)(nil).InsertTimeEntry), entry)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockStore) InsertWebhookDelivery(delivery *model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockStoreMockRecorder) InsertWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockStore)(nil).InsertWebhookDelivery), delivery)
+}
+
+// MarkDigestSent mocks base method.
+func (m *MockStore) MarkDigestSent(userID string, lastSentAt, sentAt int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDigestSent", userID, lastSentAt, sentAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDigestSent indicates an expected call of MarkDigestSent.
+func (mr *MockStoreMockRecorder) MarkDigestSent(userID, lastSentAt, sentAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDigestSent", reflect.TypeOf((*MockStore)(nil).MarkDigestSent), userID, lastSentAt, sentAt)
+}
+
+// MarkDueDateReminderSent mocks base method.
+func (m *MockStore) MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDueDateReminderSent", reminder)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDueDateReminderSent indicates an expected call of MarkDueDateReminderSent.
+func (mr *MockStoreMockRecorder) MarkDueDateReminderSent(reminder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDueDateReminderSent", reflect.TypeOf((*MockStore)(nil).MarkDueDateReminderSent), reminder)
+}
+
 // PatchBlock mocks base method.
 func (m *MockStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	m.ctrl.T.Helper()
@@ -1287,6 +2103,36 @@ func (mr *MockStoreMockRecorder) PatchBoard(boardID, boardPatch, userID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBoard", reflect.TypeOf((*MockStore)(nil).PatchBoard), boardID, boardPatch, userID)
 }
 
+// PatchBoardRule mocks base method.
+func (m *MockStore) PatchBoardRule(ruleID string, patch *model.BoardRulePatch, modifiedBy string) (*model.BoardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBoardRule", ruleID, patch, modifiedBy)
+	ret0, _ := ret[0].(*model.BoardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBoardRule indicates an expected call of PatchBoardRule.
+func (mr *MockStoreMockRecorder) PatchBoardRule(ruleID, patch, modifiedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBoardRule", reflect.TypeOf((*MockStore)(nil).PatchBoardRule), ruleID, patch, modifiedBy)
+}
+
+// PatchBoardWebhook mocks base method.
+func (m *MockStore) PatchBoardWebhook(webhookID string, patch *model.BoardWebhookPatch, modifiedBy string) (*model.BoardWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBoardWebhook", webhookID, patch, modifiedBy)
+	ret0, _ := ret[0].(*model.BoardWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBoardWebhook indicates an expected call of PatchBoardWebhook.
+func (mr *MockStoreMockRecorder) PatchBoardWebhook(webhookID, patch, modifiedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBoardWebhook", reflect.TypeOf((*MockStore)(nil).PatchBoardWebhook), webhookID, patch, modifiedBy)
+}
+
 // PatchBoardsAndBlocks mocks base method.
 func (m *MockStore) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	m.ctrl.T.Helper()
@@ -1331,6 +2177,22 @@ func (mr *MockStoreMockRecorder) PostMessage(message, postType, channelID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockStore)(nil).PostMessage), message, postType, channelID)
 }
 
+// QueryCards mocks base method.
+func (m *MockStore) QueryCards(opts model.QueryCardsOptions) ([]*model.Block, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryCards", opts)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryCards indicates an expected call of QueryCards.
+func (mr *MockStoreMockRecorder) QueryCards(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryCards", reflect.TypeOf((*MockStore)(nil).QueryCards), opts)
+}
+
 // RemoveDefaultTemplates mocks base method.
 func (m *MockStore) RemoveDefaultTemplates(boards []*model.Board) error {
 	m.ctrl.T.Helper()
@@ -1375,6 +2237,20 @@ func (mr *MockStoreMockRecorder) ReorderCategoryBoards(categoryID, newBoardsOrde
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategoryBoards", reflect.TypeOf((*MockStore)(nil).ReorderCategoryBoards), categoryID, newBoardsOrder)
 }
 
+// RestoreCardRevision mocks base method.
+func (m *MockStore) RestoreCardRevision(restore *model.CardRevisionRestore, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCardRevision", restore, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreCardRevision indicates an expected call of RestoreCardRevision.
+func (mr *MockStoreMockRecorder) RestoreCardRevision(restore, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCardRevision", reflect.TypeOf((*MockStore)(nil).RestoreCardRevision), restore, userID)
+}
+
 // RestoreFiles mocks base method.
 func (m *MockStore) RestoreFiles(fileIDs []string) error {
 	m.ctrl.T.Helper()
@@ -1463,6 +2339,21 @@ func (mr *MockStoreMockRecorder) SearchBoardsForUserInTeam(teamID, term, userID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBoardsForUserInTeam", reflect.TypeOf((*MockStore)(nil).SearchBoardsForUserInTeam), teamID, term, userID)
 }
 
+// SearchCardsForUser mocks base method.
+func (m *MockStore) SearchCardsForUser(opts model.SearchCardsOptions) ([]*model.CardSearchMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCardsForUser", opts)
+	ret0, _ := ret[0].([]*model.CardSearchMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCardsForUser indicates an expected call of SearchCardsForUser.
+func (mr *MockStoreMockRecorder) SearchCardsForUser(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCardsForUser", reflect.TypeOf((*MockStore)(nil).SearchCardsForUser), opts)
+}
+
 // SearchUserChannels mocks base method.
 func (m *MockStore) SearchUserChannels(teamID, userID, query string) ([]*model0.Channel, error) {
 	m.ctrl.T.Helper()
@@ -1549,6 +2440,21 @@ func (mr *MockStoreMockRecorder) Shutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStore)(nil).Shutdown))
 }
 
+// StartTimeEntry mocks base method.
+func (m *MockStore) StartTimeEntry(entry *model.TimeEntry) ([]*model.TimeEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTimeEntry", entry)
+	ret0, _ := ret[0].([]*model.TimeEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartTimeEntry indicates an expected call of StartTimeEntry.
+func (mr *MockStoreMockRecorder) StartTimeEntry(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTimeEntry", reflect.TypeOf((*MockStore)(nil).StartTimeEntry), entry)
+}
+
 // UndeleteBlock mocks base method.
 func (m *MockStore) UndeleteBlock(blockID, modifiedBy string) error {
 	m.ctrl.T.Helper()
@@ -1606,6 +2512,49 @@ func (mr *MockStoreMockRecorder) UpdateCategory(category interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockStore)(nil).UpdateCategory), category)
 }
 
+// UpdateExportJob mocks base method.
+func (m *MockStore) UpdateExportJob(job *model.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExportJob", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExportJob indicates an expected call of UpdateExportJob.
+func (mr *MockStoreMockRecorder) UpdateExportJob(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExportJob", reflect.TypeOf((*MockStore)(nil).UpdateExportJob), job)
+}
+
+// UpdateImportSession mocks base method.
+func (m *MockStore) UpdateImportSession(session *model.ImportSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportSession", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImportSession indicates an expected call of UpdateImportSession.
+func (mr *MockStoreMockRecorder) UpdateImportSession(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportSession", reflect.TypeOf((*MockStore)(nil).UpdateImportSession), session)
+}
+
+// UpdateImportSessionStatus mocks base method.
+func (m *MockStore) UpdateImportSessionStatus(sessionID string, oldStatus, newStatus model.ImportSessionStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportSessionStatus", sessionID, oldStatus, newStatus)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImportSessionStatus indicates an expected call of UpdateImportSessionStatus.
+func (mr *MockStoreMockRecorder) UpdateImportSessionStatus(sessionID, oldStatus, newStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportSessionStatus", reflect.TypeOf((*MockStore)(nil).UpdateImportSessionStatus), sessionID, oldStatus, newStatus)
+}
+
 // UpdateSubscribersNotifiedAt mocks base method.
 func (m *MockStore) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
 	m.ctrl.T.Helper()
@@ -1620,6 +2569,90 @@ func (mr *MockStoreMockRecorder) UpdateSubscribersNotifiedAt(blockID, notifiedAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscribersNotifiedAt", reflect.TypeOf((*MockStore)(nil).UpdateSubscribersNotifiedAt), blockID, notifiedAt)
 }
 
+// UpdateTimeEntry mocks base method.
+func (m *MockStore) UpdateTimeEntry(entry *model.TimeEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTimeEntry", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTimeEntry indicates an expected call of UpdateTimeEntry.
+func (mr *MockStoreMockRecorder) UpdateTimeEntry(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTimeEntry", reflect.TypeOf((*MockStore)(nil).UpdateTimeEntry), entry)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockStoreMockRecorder) UpdateWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDelivery), delivery)
+}
+
+// UpsertCardRecurrence mocks base method.
+func (m *MockStore) UpsertCardRecurrence(recurrence *model.CardRecurrence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCardRecurrence", recurrence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCardRecurrence indicates an expected call of UpsertCardRecurrence.
+func (mr *MockStoreMockRecorder) UpsertCardRecurrence(recurrence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCardRecurrence", reflect.TypeOf((*MockStore)(nil).UpsertCardRecurrence), recurrence)
+}
+
+// UpsertDigestSettings mocks base method.
+func (m *MockStore) UpsertDigestSettings(settings *model.DigestSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDigestSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertDigestSettings indicates an expected call of UpsertDigestSettings.
+func (mr *MockStoreMockRecorder) UpsertDigestSettings(settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDigestSettings", reflect.TypeOf((*MockStore)(nil).UpsertDigestSettings), settings)
+}
+
+// UpsertDueDateReminderSettings mocks base method.
+func (m *MockStore) UpsertDueDateReminderSettings(settings *model.DueDateReminderSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDueDateReminderSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertDueDateReminderSettings indicates an expected call of UpsertDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) UpsertDueDateReminderSettings(settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).UpsertDueDateReminderSettings), settings)
+}
+
+// UpsertIncomingWebhook mocks base method.
+func (m *MockStore) UpsertIncomingWebhook(webhook model.IncomingWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertIncomingWebhook", webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertIncomingWebhook indicates an expected call of UpsertIncomingWebhook.
+func (mr *MockStoreMockRecorder) UpsertIncomingWebhook(webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertIncomingWebhook", reflect.TypeOf((*MockStore)(nil).UpsertIncomingWebhook), webhook)
+}
+
 // UpsertNotificationHint mocks base method.
 func (m *MockStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	m.ctrl.T.Helper()
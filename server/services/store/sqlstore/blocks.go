@@ -186,48 +186,63 @@ func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]*model.Block, error) {
 	results := []*model.Block{}
 
 	for rows.Next() {
-		var block model.Block
-		var fieldsJSON string
-		var modifiedBy sql.NullString
-		var insertAt sql.NullString
-
-		err := rows.Scan(
-			&block.ID,
-			&block.ParentID,
-			&block.CreatedBy,
-			&modifiedBy,
-			&block.Schema,
-			&block.Type,
-			&block.Title,
-			&fieldsJSON,
-			&insertAt,
-			&block.CreateAt,
-			&block.UpdateAt,
-			&block.DeleteAt,
-			&block.BoardID)
+		block, err := s.blockFromRow(rows)
 		if err != nil {
-			// handle this error
-			s.logger.Error(`ERROR blocksFromRows`, mlog.Err(err))
-
 			return nil, err
 		}
 
-		if modifiedBy.Valid {
-			block.ModifiedBy = modifiedBy.String
-		}
+		results = append(results, block)
+	}
 
-		err = json.Unmarshal([]byte(fieldsJSON), &block.Fields)
-		if err != nil {
-			// handle this error
-			s.logger.Error(`ERROR blocksFromRows fields`, mlog.Err(err))
+	return results, nil
+}
 
-			return nil, err
-		}
+// blockFromRow scans the current row, selected with blockFields, into a
+// block. Any extra destinations are scanned from the columns that follow
+// the block fields.
+func (s *SQLStore) blockFromRow(rows *sql.Rows, extra ...interface{}) (*model.Block, error) {
+	var block model.Block
+	var fieldsJSON string
+	var modifiedBy sql.NullString
+	var insertAt sql.NullString
+
+	dest := []interface{}{
+		&block.ID,
+		&block.ParentID,
+		&block.CreatedBy,
+		&modifiedBy,
+		&block.Schema,
+		&block.Type,
+		&block.Title,
+		&fieldsJSON,
+		&insertAt,
+		&block.CreateAt,
+		&block.UpdateAt,
+		&block.DeleteAt,
+		&block.BoardID,
+	}
+
+	err := rows.Scan(append(dest, extra...)...)
+	if err != nil {
+		// handle this error
+		s.logger.Error(`ERROR blocksFromRows`, mlog.Err(err))
 
-		results = append(results, &block)
+		return nil, err
 	}
 
-	return results, nil
+	if modifiedBy.Valid {
+		block.ModifiedBy = modifiedBy.String
+	}
+
+	err = json.Unmarshal([]byte(fieldsJSON), &block.Fields)
+	if err != nil {
+		// handle this error
+		s.logger.Error(`ERROR blocksFromRows fields`, mlog.Err(err))
+
+		return nil, err
+	}
+
+	return &block, nil
 }
 
 func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID string) error {
@@ -325,12 +340,36 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 	return nil
 }
 
+// lockForPatch locks the row of a block or board until the end of the
+// transaction if it still has the update time a patch expects, so that
+// concurrent patches expecting the same version conflict.
+func (s *SQLStore) lockForPatch(db sq.BaseRunner, table, id string, expectedUpdateAt *int64) error {
+	if expectedUpdateAt == nil {
+		return nil
+	}
+
+	_, err := s.getQueryBuilder(db).Update(s.tablePrefix+table).
+		Set("update_at", sq.Expr("update_at")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"update_at": *expectedUpdateAt}).
+		Exec()
+	return err
+}
+
 func (s *SQLStore) patchBlock(db sq.BaseRunner, blockID string, blockPatch *model.BlockPatch, userID string) error {
+	if err := s.lockForPatch(db, "blocks", blockID, blockPatch.ExpectedUpdateAt); err != nil {
+		return err
+	}
+
 	existingBlock, err := s.getBlock(db, blockID)
 	if err != nil {
 		return err
 	}
 
+	if blockPatch.IsStale(existingBlock) {
+		return model.NewErrConflict("block ID="+blockID, existingBlock)
+	}
+
 	block := blockPatch.Patch(existingBlock)
 	return s.insertBlock(db, block, userID)
 }
@@ -345,6 +384,21 @@ func (s *SQLStore) patchBlocks(db sq.BaseRunner, blockPatches *model.BlockPatchB
 	return nil
 }
 
+func (s *SQLStore) restoreCardRevision(db sq.BaseRunner, restore *model.CardRevisionRestore, userID string) error {
+	if err := s.insertBlocks(db, restore.InsertBlocks, userID); err != nil {
+		return err
+	}
+	if err := s.patchBlocks(db, &restore.PatchBlocks, userID); err != nil {
+		return err
+	}
+	for _, blockID := range restore.DeleteBlockIDs {
+		if err := s.deleteBlock(db, blockID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *SQLStore) insertBlocks(db sq.BaseRunner, blocks []*model.Block, userID string) error {
 	for _, block := range blocks {
 		if err := block.IsValid(); err != nil {
@@ -696,6 +750,42 @@ func (s *SQLStore) getBlockHistoryDescendants(db sq.BaseRunner, boardID string,
 	return s.blocksFromRows(rows)
 }
 
+// getBlockHistoryChildren returns all the versions of the blocks of the
+// specified parent from the blocks_history table, including the deleted ones.
+func (s *SQLStore) getBlockHistoryChildren(db sq.BaseRunner, parentID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	var order string
+	if opts.Descending {
+		order = descClause
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"parent_id": parentID}).
+		OrderBy("insert_at " + order + ", update_at" + order)
+
+	if opts.BeforeUpdateAt != 0 {
+		query = query.Where(sq.Lt{"update_at": opts.BeforeUpdateAt})
+	}
+
+	if opts.AfterUpdateAt != 0 {
+		query = query.Where(sq.Gt{"update_at": opts.AfterUpdateAt})
+	}
+
+	if opts.Limit != 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetBlockHistoryChildren ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
 // getBlockHistoryNewestChildren returns the newest (latest) version child blocks for the
 // specified parent from the blocks_history table. This includes any deleted children.
 func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
@@ -376,11 +376,19 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 }
 
 func (s *SQLStore) patchBoard(db sq.BaseRunner, boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
+	if err := s.lockForPatch(db, "boards", boardID, boardPatch.ExpectedUpdateAt); err != nil {
+		return nil, err
+	}
+
 	existingBoard, err := s.getBoard(db, boardID)
 	if err != nil {
 		return nil, err
 	}
 
+	if boardPatch.IsStale(existingBoard) {
+		return nil, model.NewErrConflict("board ID="+boardID, existingBoard)
+	}
+
 	board := boardPatch.Patch(existingBoard)
 	return s.insertBoard(db, board, userID)
 }
@@ -449,6 +457,10 @@ func (s *SQLStore) deleteBoardAndChildren(db sq.BaseRunner, boardID, userID stri
 		return nil
 	}
 
+	if err := s.deleteBoardWebhooksForBoard(db, boardID); err != nil {
+		return err
+	}
+
 	return s.deleteBlockChildren(db, boardID, "", userID)
 }
 
@@ -0,0 +1,318 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var boardRuleFields = []string{
+	"id",
+	"board_id",
+	"title",
+	"enabled",
+	"trigger_data",
+	"actions",
+	"created_by",
+	"modified_by",
+	"create_at",
+	"update_at",
+}
+
+var boardRuleRunFields = []string{
+	"id",
+	"rule_id",
+	"board_id",
+	"card_id",
+	"actor_id",
+	"depth",
+	"status",
+	"changes",
+	"error",
+	"create_at",
+}
+
+func (s *SQLStore) boardRulesFromRows(rows *sql.Rows) ([]*model.BoardRule, error) {
+	rules := []*model.BoardRule{}
+
+	for rows.Next() {
+		var rule model.BoardRule
+		var trigger string
+		var actions string
+		err := rows.Scan(
+			&rule.ID,
+			&rule.BoardID,
+			&rule.Title,
+			&rule.Enabled,
+			&trigger,
+			&actions,
+			&rule.CreatedBy,
+			&rule.ModifiedBy,
+			&rule.CreateAt,
+			&rule.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(trigger), &rule.Trigger); err != nil {
+			s.logger.Error("board rule trigger unmarshal error", mlog.String("rule_id", rule.ID), mlog.Err(err))
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(actions), &rule.Actions); err != nil {
+			s.logger.Error("board rule actions unmarshal error", mlog.String("rule_id", rule.ID), mlog.Err(err))
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStore) boardRuleRunsFromRows(rows *sql.Rows) ([]*model.BoardRuleRun, error) {
+	runs := []*model.BoardRuleRun{}
+
+	for rows.Next() {
+		var run model.BoardRuleRun
+		var changes string
+		err := rows.Scan(
+			&run.ID,
+			&run.RuleID,
+			&run.BoardID,
+			&run.CardID,
+			&run.ActorID,
+			&run.Depth,
+			&run.Status,
+			&changes,
+			&run.Error,
+			&run.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(changes), &run.Changes); err != nil {
+			s.logger.Error("board rule run changes unmarshal error", mlog.String("run_id", run.ID), mlog.Err(err))
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+	return runs, nil
+}
+
+func (s *SQLStore) insertBoardRule(db sq.BaseRunner, rule *model.BoardRule) (*model.BoardRule, error) {
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	trigger, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	ruleAdd := *rule
+	ruleAdd.CreateAt = now
+	ruleAdd.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"board_rules").
+		Columns(boardRuleFields...).
+		Values(
+			ruleAdd.ID,
+			ruleAdd.BoardID,
+			ruleAdd.Title,
+			ruleAdd.Enabled,
+			string(trigger),
+			string(actions),
+			ruleAdd.CreatedBy,
+			ruleAdd.ModifiedBy,
+			ruleAdd.CreateAt,
+			ruleAdd.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert board rule",
+			mlog.String("board_id", rule.BoardID),
+			mlog.Err(err),
+		)
+		return nil, err
+	}
+	return &ruleAdd, nil
+}
+
+func (s *SQLStore) getBoardRule(db sq.BaseRunner, ruleID string) (*model.BoardRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardRuleFields...).
+		From(s.tablePrefix + "board_rules").
+		Where(sq.Eq{"id": ruleID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardRule ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	rules, err := s.boardRulesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, model.NewErrNotFound("board rule ID=" + ruleID)
+	}
+	return rules[0], nil
+}
+
+func (s *SQLStore) getBoardRulesForBoard(db sq.BaseRunner, boardID string) ([]*model.BoardRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardRuleFields...).
+		From(s.tablePrefix+"board_rules").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardRulesForBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardRulesFromRows(rows)
+}
+
+func (s *SQLStore) patchBoardRule(db sq.BaseRunner, ruleID string, patch *model.BoardRulePatch, modifiedBy string) (*model.BoardRule, error) {
+	if err := patch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getBoardRule(db, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := patch.Patch(existing)
+	rule.ModifiedBy = modifiedBy
+	rule.UpdateAt = utils.GetMillis()
+
+	trigger, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"board_rules").
+		Set("title", rule.Title).
+		Set("enabled", rule.Enabled).
+		Set("trigger_data", string(trigger)).
+		Set("actions", string(actions)).
+		Set("modified_by", rule.ModifiedBy).
+		Set("update_at", rule.UpdateAt).
+		Where(sq.Eq{"id": ruleID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`patchBoardRule ERROR`, mlog.String("rule_id", ruleID), mlog.Err(err))
+		return nil, err
+	}
+	return rule, nil
+}
+
+// deleteBoardRule deletes a rule together with its runs.
+func (s *SQLStore) deleteBoardRule(db sq.BaseRunner, ruleID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_rules").
+		Where(sq.Eq{"id": ruleID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("board rule ID=" + ruleID)
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_rule_runs").
+		Where(sq.Eq{"rule_id": ruleID}).
+		Exec()
+	return err
+}
+
+func (s *SQLStore) insertBoardRuleRun(db sq.BaseRunner, run *model.BoardRuleRun) error {
+	if run.Changes == nil {
+		run.Changes = map[string]any{}
+	}
+
+	changes, err := json.Marshal(run.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"board_rule_runs").
+		Columns(boardRuleRunFields...).
+		Values(
+			run.ID,
+			run.RuleID,
+			run.BoardID,
+			run.CardID,
+			run.ActorID,
+			run.Depth,
+			run.Status,
+			string(changes),
+			run.Error,
+			run.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert board rule run",
+			mlog.String("rule_id", run.RuleID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// getBoardRuleRuns returns the runs of a rule, newest first.
+func (s *SQLStore) getBoardRuleRuns(db sq.BaseRunner, ruleID string, page, perPage int) ([]*model.BoardRuleRun, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardRuleRunFields...).
+		From(s.tablePrefix+"board_rule_runs").
+		Where(sq.Eq{"rule_id": ruleID}).
+		OrderBy("create_at DESC", "id DESC")
+
+	if perPage > 0 {
+		query = query.
+			Offset(offset(page, perPage)).
+			Limit(limit(perPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardRuleRuns ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardRuleRunsFromRows(rows)
+}
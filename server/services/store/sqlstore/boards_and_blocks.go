@@ -44,6 +44,27 @@ func (s *SQLStore) createBoardsAndBlocksWithAdmin(db sq.BaseRunner, bab *model.B
 	return newBab, members, nil
 }
 
+// createBoardsAndBlocksWithMembers creates the boards and blocks, and adds
+// the members to the boards, so that an imported board is created whole or
+// not at all.
+func (s *SQLStore) createBoardsAndBlocksWithMembers(db sq.BaseRunner, bab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	newBab, err := s.createBoardsAndBlocks(db, bab, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newMembers := make([]*model.BoardMember, 0, len(members))
+	for _, member := range members {
+		nbm, err := s.saveMember(db, member)
+		if err != nil {
+			return nil, nil, err
+		}
+		newMembers = append(newMembers, nbm)
+	}
+
+	return newBab, newMembers, nil
+}
+
 func (s *SQLStore) createBoardsAndBlocks(db sq.BaseRunner, bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	boards := []*model.Board{}
 	blocks := []*model.Block{}
@@ -177,10 +198,31 @@ func (s *SQLStore) duplicateBoard(db sq.BaseRunner, boardID string, userID strin
 	}
 	bab.Blocks = newBlocks
 
+	// the ids are replaced in place, keep the old ones to copy the card links
+	oldCardIDs := map[*model.Block]string{}
+	for _, b := range newBlocks {
+		if b.Type == model.TypeCard {
+			oldCardIDs[b] = b.ID
+		}
+	}
+
 	bab, err = model.GenerateBoardsAndBlocksIDs(bab, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return s.createBoardsAndBlocksWithAdmin(db, bab, userID)
+	newBab, members, err := s.createBoardsAndBlocksWithAdmin(db, bab, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cardIDs := make(map[string]string, len(oldCardIDs))
+	for b, oldID := range oldCardIDs {
+		cardIDs[oldID] = b.ID
+	}
+	if err := s.duplicateCardLinks(db, boardID, bab.Boards[0].ID, cardIDs, userID); err != nil {
+		return nil, nil, err
+	}
+
+	return newBab, members, nil
 }
@@ -0,0 +1,209 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var cardLinkFields = []string{
+	"id",
+	"link_type",
+	"source_card_id",
+	"source_board_id",
+	"target_card_id",
+	"target_board_id",
+	"created_by",
+	"create_at",
+}
+
+func (s *SQLStore) cardLinksFromRows(rows *sql.Rows) ([]*model.CardLink, error) {
+	links := []*model.CardLink{}
+
+	for rows.Next() {
+		var link model.CardLink
+		err := rows.Scan(
+			&link.ID,
+			&link.Type,
+			&link.SourceCardID,
+			&link.SourceBoardID,
+			&link.TargetCardID,
+			&link.TargetBoardID,
+			&link.CreatedBy,
+			&link.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, &link)
+	}
+	return links, nil
+}
+
+func (s *SQLStore) insertCardLink(db sq.BaseRunner, link *model.CardLink) (*model.CardLink, error) {
+	linkAdd := *link
+	linkAdd.Normalize()
+	if err := linkAdd.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if linkAdd.ID == "" {
+		linkAdd.ID = utils.NewID(utils.IDTypeNone)
+	}
+	if linkAdd.CreateAt == 0 {
+		linkAdd.CreateAt = utils.GetMillis()
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_links").
+		Columns(cardLinkFields...).
+		Values(
+			linkAdd.ID,
+			linkAdd.Type,
+			linkAdd.SourceCardID,
+			linkAdd.SourceBoardID,
+			linkAdd.TargetCardID,
+			linkAdd.TargetBoardID,
+			linkAdd.CreatedBy,
+			linkAdd.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert card link",
+			mlog.String("source_card_id", linkAdd.SourceCardID),
+			mlog.String("target_card_id", linkAdd.TargetCardID),
+			mlog.Err(err),
+		)
+		return nil, err
+	}
+	return &linkAdd, nil
+}
+
+func (s *SQLStore) getCardLink(db sq.BaseRunner, linkID string) (*model.CardLink, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardLinkFields...).
+		From(s.tablePrefix + "card_links").
+		Where(sq.Eq{"id": linkID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardLink ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	links, err := s.cardLinksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, model.NewErrNotFound("card link ID=" + linkID)
+	}
+	return links[0], nil
+}
+
+// getCardLinksForCard returns the links starting from or pointing to a card.
+func (s *SQLStore) getCardLinksForCard(db sq.BaseRunner, cardID string) ([]*model.CardLink, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardLinkFields...).
+		From(s.tablePrefix+"card_links").
+		Where(sq.Or{
+			sq.Eq{"source_card_id": cardID},
+			sq.Eq{"target_card_id": cardID},
+		}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardLinksForCard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardLinksFromRows(rows)
+}
+
+// getCardLinksForBoard returns the links with at least one end on a board.
+func (s *SQLStore) getCardLinksForBoard(db sq.BaseRunner, boardID string) ([]*model.CardLink, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardLinkFields...).
+		From(s.tablePrefix+"card_links").
+		Where(sq.Or{
+			sq.Eq{"source_board_id": boardID},
+			sq.Eq{"target_board_id": boardID},
+		}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardLinksForBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardLinksFromRows(rows)
+}
+
+func (s *SQLStore) deleteCardLink(db sq.BaseRunner, linkID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_links").
+		Where(sq.Eq{"id": linkID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("card link ID=" + linkID)
+	}
+	return nil
+}
+
+// duplicateCardLinks copies the links of the cards of a board to their
+// copies. `cardIDs` maps the ids of the original cards to their copies,
+// which are all on `newBoardID`. Links to cards of other boards are kept.
+func (s *SQLStore) duplicateCardLinks(db sq.BaseRunner, boardID, newBoardID string, cardIDs map[string]string, userID string) error {
+	links, err := s.getCardLinksForBoard(db, boardID)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	for _, link := range links {
+		newLink := *link
+		newLink.ID = ""
+		newLink.CreatedBy = userID
+		newLink.CreateAt = now
+
+		sourceID, sourceCopied := cardIDs[link.SourceCardID]
+		if sourceCopied {
+			newLink.SourceCardID = sourceID
+			newLink.SourceBoardID = newBoardID
+		}
+		targetID, targetCopied := cardIDs[link.TargetCardID]
+		if targetCopied {
+			newLink.TargetCardID = targetID
+			newLink.TargetBoardID = newBoardID
+		}
+		if !sourceCopied && !targetCopied {
+			// the card of the board was deleted
+			continue
+		}
+
+		if _, err := s.insertCardLink(db, &newLink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
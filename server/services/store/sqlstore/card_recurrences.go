@@ -0,0 +1,235 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var cardRecurrenceFields = []string{
+	"card_id",
+	"board_id",
+	"rule",
+	"enabled",
+	"COALESCE(disabled_reason, '')",
+	"start_at",
+	"next_run_at",
+	"last_run_at",
+	"created_by",
+	"modified_by",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) cardRecurrencesFromRows(rows *sql.Rows) ([]*model.CardRecurrence, error) {
+	recurrences := []*model.CardRecurrence{}
+
+	for rows.Next() {
+		var recurrence model.CardRecurrence
+		var rule string
+		err := rows.Scan(
+			&recurrence.CardID,
+			&recurrence.BoardID,
+			&rule,
+			&recurrence.Enabled,
+			&recurrence.DisabledReason,
+			&recurrence.StartAt,
+			&recurrence.NextRunAt,
+			&recurrence.LastRunAt,
+			&recurrence.CreatedBy,
+			&recurrence.ModifiedBy,
+			&recurrence.CreateAt,
+			&recurrence.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(rule), &recurrence.Rule); err != nil {
+			s.logger.Error("card recurrence rule unmarshal error", mlog.String("card_id", recurrence.CardID), mlog.Err(err))
+			return nil, err
+		}
+		recurrences = append(recurrences, &recurrence)
+	}
+	return recurrences, nil
+}
+
+func (s *SQLStore) upsertCardRecurrence(db sq.BaseRunner, recurrence *model.CardRecurrence) error {
+	if err := recurrence.Rule.IsValid(); err != nil {
+		return err
+	}
+
+	rule, err := json.Marshal(recurrence.Rule)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_recurrences").
+		Columns(
+			"card_id",
+			"board_id",
+			"rule",
+			"enabled",
+			"disabled_reason",
+			"start_at",
+			"next_run_at",
+			"last_run_at",
+			"created_by",
+			"modified_by",
+			"create_at",
+			"update_at",
+		).
+		Values(
+			recurrence.CardID,
+			recurrence.BoardID,
+			string(rule),
+			recurrence.Enabled,
+			recurrence.DisabledReason,
+			recurrence.StartAt,
+			recurrence.NextRunAt,
+			recurrence.LastRunAt,
+			recurrence.CreatedBy,
+			recurrence.ModifiedBy,
+			now,
+			now,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE rule = ?, enabled = ?, disabled_reason = ?, start_at = ?, next_run_at = ?, modified_by = ?, update_at = ?",
+			string(rule), recurrence.Enabled, recurrence.DisabledReason, recurrence.StartAt, recurrence.NextRunAt, recurrence.ModifiedBy, now)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (card_id)
+			 DO UPDATE SET rule = EXCLUDED.rule, enabled = EXCLUDED.enabled, disabled_reason = EXCLUDED.disabled_reason, start_at = EXCLUDED.start_at,
+			 next_run_at = EXCLUDED.next_run_at, modified_by = EXCLUDED.modified_by, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert card recurrence",
+			mlog.String("card_id", recurrence.CardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getCardRecurrence(db sq.BaseRunner, cardID string) (*model.CardRecurrence, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardRecurrenceFields...).
+		From(s.tablePrefix + "card_recurrences").
+		Where(sq.Eq{"card_id": cardID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardRecurrence ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	recurrences, err := s.cardRecurrencesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(recurrences) == 0 {
+		return nil, model.NewErrNotFound("card recurrence for card ID=" + cardID)
+	}
+	return recurrences[0], nil
+}
+
+func (s *SQLStore) deleteCardRecurrence(db sq.BaseRunner, cardID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_recurrences").
+		Where(sq.Eq{"card_id": cardID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("card recurrence for card ID=" + cardID)
+	}
+	return nil
+}
+
+// disableCardRecurrence stops a recurrence that can't create the copies
+// of its card anymore, keeping the reason for its owner to see.
+func (s *SQLStore) disableCardRecurrence(db sq.BaseRunner, cardID, reason string) error {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"card_recurrences").
+		Set("enabled", false).
+		Set("disabled_reason", reason).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"card_id": cardID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("card recurrence for card ID=" + cardID)
+	}
+	return nil
+}
+
+// getDueCardRecurrences returns the enabled recurrences whose next
+// occurrence is due at `now`, the most overdue first.
+func (s *SQLStore) getDueCardRecurrences(db sq.BaseRunner, now int64, maxRecurrences int) ([]*model.CardRecurrence, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardRecurrenceFields...).
+		From(s.tablePrefix+"card_recurrences").
+		Where(sq.Eq{"enabled": true}).
+		Where(sq.LtOrEq{"next_run_at": now}).
+		OrderBy("next_run_at", "card_id").
+		Limit(uint64(maxRecurrences))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDueCardRecurrences ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardRecurrencesFromRows(rows)
+}
+
+// advanceCardRecurrence records the occurrence at `runAt` and schedules
+// the next one, but only if nobody else did it since the recurrence was
+// read, so that each occurrence is created once.
+func (s *SQLStore) advanceCardRecurrence(db sq.BaseRunner, cardID string, runAt, nextRunAt int64) (bool, error) {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"card_recurrences").
+		Set("next_run_at", nextRunAt).
+		Set("last_run_at", runAt).
+		Where(sq.Eq{"card_id": cardID}).
+		Where(sq.Eq{"next_run_at": runAt}).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
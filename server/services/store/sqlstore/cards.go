@@ -0,0 +1,714 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// createdTime and updatedTime filters compare full timestamps with
+	// a date, so the webapp widens them by half a day on each side.
+	filterHalfDayMillis = 12 * 60 * 60 * 1000
+
+	likeEscapeChar = "!"
+)
+
+var (
+	sqlTrue  = sq.Expr("1=1")
+	sqlFalse = sq.Expr("1=0")
+)
+
+type cardSortKeyKind int
+
+const (
+	cardSortKeyText cardSortKeyKind = iota
+	cardSortKeyNumber
+)
+
+// cardSortKey is one of the expressions a card query is ordered by. Its
+// value is selected alongside the card so it can be stored in the cursor.
+type cardSortKey struct {
+	expr     sq.Sqlizer
+	kind     cardSortKeyKind
+	reversed bool
+}
+
+func (s *SQLStore) queryCards(db sq.BaseRunner, opts model.QueryCardsOptions) ([]*model.Block, string, error) {
+	board, err := s.getBoard(db, opts.BoardID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("b")...).
+		From(s.tablePrefix + "blocks as b").
+		Where(sq.Eq{
+			"b.board_id": opts.BoardID,
+			"b.type":     model.TypeCard,
+		}).
+		Where(s.cardIsNotTemplate())
+
+	if opts.Filter != nil {
+		query = query.Where(s.cardFilterGroupCondition(opts.Filter, schema))
+	}
+
+	keys := s.cardSortKeys(opts.SortOptions, schema)
+	for _, key := range keys {
+		query = query.Column(key.expr)
+		if key.reversed {
+			query = query.OrderByClause(sq.Expr("? DESC", key.expr))
+		} else {
+			query = query.OrderByClause(key.expr)
+		}
+	}
+
+	if opts.Cursor != nil {
+		cond, cErr := cardCursorCondition(keys, opts.Cursor)
+		if cErr != nil {
+			return nil, "", cErr
+		}
+		query = query.Where(cond)
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`queryCards ERROR`, mlog.Err(err))
+		return nil, "", err
+	}
+	defer s.CloseRows(rows)
+
+	cards := []*model.Block{}
+	var lastKeys []interface{}
+	for rows.Next() {
+		dest := make([]interface{}, len(keys))
+		for i, key := range keys {
+			if key.kind == cardSortKeyNumber {
+				dest[i] = &sql.NullFloat64{}
+			} else {
+				dest[i] = &sql.NullString{}
+			}
+		}
+
+		card, err := s.blockFromRow(rows, dest...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if opts.PerPage > 0 && len(cards) == opts.PerPage {
+			// this is the extra row, so there is a next page
+			cursor := &model.CardsCursor{
+				Keys: lastKeys,
+				ID:   cards[len(cards)-1].ID,
+			}
+			return cards, cursor.Encode(), nil
+		}
+
+		cards = append(cards, card)
+		// the last key is the card id, which the cursor stores on its own
+		lastKeys = cardSortKeyValues(dest[:len(dest)-1])
+	}
+
+	return cards, "", nil
+}
+
+func cardSortKeyValues(dest []interface{}) []interface{} {
+	values := make([]interface{}, len(dest))
+	for i, d := range dest {
+		switch v := d.(type) {
+		case *sql.NullFloat64:
+			values[i] = v.Float64
+		case *sql.NullString:
+			values[i] = v.String
+		}
+	}
+	return values
+}
+
+// cardCursorCondition returns the keyset condition that selects the cards
+// that are sorted after the card the cursor points to.
+func cardCursorCondition(keys []cardSortKey, cursor *model.CardsCursor) (sq.Sqlizer, error) {
+	if len(cursor.Keys) != len(keys)-1 {
+		return nil, model.NewErrBadRequest(model.ErrInvalidCardsCursor.Error())
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys[:len(keys)-1] {
+		switch v := cursor.Keys[i].(type) {
+		case float64:
+			if key.kind != cardSortKeyNumber {
+				return nil, model.NewErrBadRequest(model.ErrInvalidCardsCursor.Error())
+			}
+			values[i] = v
+		case string:
+			if key.kind != cardSortKeyText {
+				return nil, model.NewErrBadRequest(model.ErrInvalidCardsCursor.Error())
+			}
+			values[i] = v
+		default:
+			return nil, model.NewErrBadRequest(model.ErrInvalidCardsCursor.Error())
+		}
+	}
+
+	values[len(values)-1] = cursor.ID
+
+	cond := sq.Or{}
+	for i, key := range keys {
+		and := sq.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Expr("? = ?", keys[j].expr, values[j]))
+		}
+
+		op := ">"
+		if key.reversed {
+			op = "<"
+		}
+		and = append(and, sq.Expr("? "+op+" ?", key.expr, values[i]))
+		cond = append(cond, and)
+	}
+	return cond, nil
+}
+
+// cardSortKeys returns the expressions to order the cards by, following
+// the sort rules of the webapp: empty values always go last, and cards
+// are finally ordered by id so the order is total and can be paginated.
+func (s *SQLStore) cardSortKeys(sortOptions []model.CardSortOption, schema model.PropSchema) []cardSortKey {
+	keys := []cardSortKey{}
+
+	if len(sortOptions) == 0 {
+		keys = append(keys, cardSortKey{expr: sq.Expr("b.create_at"), kind: cardSortKeyNumber})
+	}
+
+	for _, opt := range sortOptions {
+		var value sq.Sqlizer
+		kind := cardSortKeyText
+
+		if opt.PropertyID == model.TitlePropertyID {
+			value = sq.Expr("lower(b.title)")
+		} else {
+			def, ok := schema[opt.PropertyID]
+			if !ok {
+				// the webapp ignores sort options on deleted properties
+				continue
+			}
+
+			switch def.Type {
+			case "createdBy":
+				value = sq.Expr("b.created_by")
+			case "updatedBy":
+				value = sq.Expr("b.modified_by")
+			case "createdTime":
+				value = sq.Expr("b.create_at")
+				kind = cardSortKeyNumber
+			case "updatedTime":
+				value = sq.Expr("b.update_at")
+				kind = cardSortKeyNumber
+			case "date":
+				value = s.dateFromText(s.cardPropertyText(opt.PropertyID), "from")
+				kind = cardSortKeyNumber
+			case "number":
+				value = s.numberFromText(s.cardPropertyText(opt.PropertyID))
+				kind = cardSortKeyNumber
+			case "select", "multiSelect":
+				value = s.cardPropertyOptionValue(opt.PropertyID, def)
+			default:
+				value = sq.Expr("lower(?)", s.cardPropertyText(opt.PropertyID))
+			}
+		}
+
+		if kind == cardSortKeyNumber {
+			keys = append(keys,
+				cardSortKey{expr: sq.Expr("CASE WHEN ? IS NULL THEN 1 ELSE 0 END", value), kind: cardSortKeyNumber},
+				cardSortKey{expr: sq.Expr("COALESCE(?, 0)", value), kind: cardSortKeyNumber, reversed: opt.Reversed},
+			)
+		} else {
+			value = sq.Expr("COALESCE(?, '')", value)
+			keys = append(keys,
+				cardSortKey{expr: sq.Expr("CASE WHEN ? = '' THEN 1 ELSE 0 END", value), kind: cardSortKeyNumber},
+				cardSortKey{expr: value, kind: cardSortKeyText, reversed: opt.Reversed},
+			)
+		}
+	}
+
+	return append(keys, cardSortKey{expr: sq.Expr("b.id"), kind: cardSortKeyText})
+}
+
+// cardFilterGroupCondition translates a view filter group into a SQL
+// condition with the same semantics as the webapp's CardFilter.
+func (s *SQLStore) cardFilterGroupCondition(group *model.FilterGroup, schema model.PropSchema) sq.Sqlizer {
+	if len(group.Filters) == 0 {
+		// no filters means the group is always met
+		return sqlTrue
+	}
+
+	conditions := make([]sq.Sqlizer, 0, len(group.Filters))
+	for _, item := range group.Filters {
+		if item.Group != nil {
+			conditions = append(conditions, s.cardFilterGroupCondition(item.Group, schema))
+		} else if item.Clause != nil {
+			conditions = append(conditions, s.cardFilterClauseCondition(item.Clause, schema))
+		}
+	}
+
+	if group.Operation == model.FilterOperationOr {
+		return sq.Or(conditions)
+	}
+	return sq.And(conditions)
+}
+
+func (s *SQLStore) cardFilterClauseCondition(clause *model.FilterClause, schema model.PropSchema) sq.Sqlizer {
+	def, hasDef := schema[clause.PropertyID]
+
+	// value is the text of the property, dateColumn is set for the
+	// properties that are computed from a block timestamp and column for
+	// the ones that are read directly from a block column
+	var value sq.Sqlizer
+	var column string
+	var dateColumn string
+
+	switch {
+	case clause.PropertyID == model.TitleFilterPropertyID:
+		column = "lower(b.title)"
+	case hasDef && def.Type == "createdBy":
+		column = "b.created_by"
+	case hasDef && def.Type == "updatedBy":
+		column = "b.modified_by"
+	case hasDef && def.Type == "createdTime":
+		dateColumn = "b.create_at"
+	case hasDef && def.Type == "updatedTime":
+		dateColumn = "b.update_at"
+	}
+
+	switch {
+	case column != "":
+		value = sq.Expr(column)
+	case dateColumn != "":
+		value = sq.Expr("CAST(" + dateColumn + " AS CHAR(20))")
+		if s.dbType == model.PostgresDBType {
+			value = sq.Expr("CAST(" + dateColumn + " AS TEXT)")
+		}
+	default:
+		value = s.cardPropertyText(clause.PropertyID)
+	}
+	text := sq.Expr("COALESCE(?, '')", value)
+
+	isDate := dateColumn != "" || (hasDef && def.Type == "date")
+
+	switch clause.Condition {
+	case model.FilterConditionIncludes, model.FilterConditionNotIncludes:
+		if len(clause.Values) == 0 {
+			// no values means the clause is ignored
+			return sqlTrue
+		}
+
+		var includes sq.Sqlizer
+		if column != "" || dateColumn != "" {
+			includes = sq.Expr("? IN ("+sq.Placeholders(len(clause.Values))+")", append([]interface{}{text}, stringsToArgs(clause.Values)...)...)
+		} else {
+			or := sq.Or{}
+			for _, v := range clause.Values {
+				or = append(or, s.cardPropertyIncludes(clause.PropertyID, v))
+			}
+			includes = or
+		}
+
+		if clause.Condition == model.FilterConditionNotIncludes {
+			return sq.Expr("NOT (?)", includes)
+		}
+		return includes
+
+	case model.FilterConditionIsEmpty:
+		return sq.Expr("? IN ('', '[]')", text)
+	case model.FilterConditionIsNotEmpty:
+		return sq.Expr("? NOT IN ('', '[]')", text)
+	case model.FilterConditionIsSet:
+		return sq.Expr("? <> ''", text)
+	case model.FilterConditionIsNotSet:
+		return sq.Expr("? = ''", text)
+	}
+
+	if len(clause.Values) == 0 {
+		// the rest of the conditions are always met without values
+		return sqlTrue
+	}
+	filterValue := clause.Values[0]
+
+	switch clause.Condition {
+	case model.FilterConditionIs:
+		if isDate {
+			return s.cardDateCondition(clause.Condition, value, dateColumn, filterValue)
+		}
+		return sq.Expr("lower(?) = ?", text, strings.ToLower(filterValue))
+	case model.FilterConditionContains:
+		return s.likeCondition(text, "%"+escapeLike(filterValue)+"%", false)
+	case model.FilterConditionNotContains:
+		return s.likeCondition(text, "%"+escapeLike(filterValue)+"%", true)
+	case model.FilterConditionStartsWith:
+		return s.likeCondition(text, escapeLike(filterValue)+"%", false)
+	case model.FilterConditionNotStartsWith:
+		return s.likeCondition(text, escapeLike(filterValue)+"%", true)
+	case model.FilterConditionEndsWith:
+		return s.likeCondition(text, "%"+escapeLike(filterValue), false)
+	case model.FilterConditionNotEndsWith:
+		return s.likeCondition(text, "%"+escapeLike(filterValue), true)
+	case model.FilterConditionIsBefore, model.FilterConditionIsAfter:
+		if !isDate {
+			return sqlFalse
+		}
+		return s.cardDateCondition(clause.Condition, value, dateColumn, filterValue)
+	}
+
+	return sqlTrue
+}
+
+// cardDateCondition builds the is, isBefore and isAfter conditions for
+// date properties and for the created and updated time of the card.
+func (s *SQLStore) cardDateCondition(condition model.FilterCondition, value sq.Sqlizer, dateColumn string, filterValue string) sq.Sqlizer {
+	date, err := strconv.ParseInt(filterValue, 10, 64)
+	if err != nil {
+		// the webapp compares against NaN, which is never true
+		return sqlFalse
+	}
+
+	if dateColumn != "" {
+		switch condition {
+		case model.FilterConditionIs:
+			return sq.And{
+				sq.Gt{dateColumn: date - filterHalfDayMillis},
+				sq.Lt{dateColumn: date + filterHalfDayMillis},
+			}
+		case model.FilterConditionIsBefore:
+			return sq.Lt{dateColumn: date - filterHalfDayMillis}
+		default:
+			return sq.Gt{dateColumn: date + filterHalfDayMillis}
+		}
+	}
+
+	from := s.dateFromText(value, "from")
+	to := s.dateFromText(value, "to")
+
+	switch condition {
+	case model.FilterConditionIs:
+		return sq.Or{
+			sq.Expr("(? IS NOT NULL AND ? IS NOT NULL AND ? <= ? AND ? >= ?)", from, to, from, date, to, date),
+			sq.Expr("(? IS NULL AND ? = ?)", to, from, date),
+		}
+	case model.FilterConditionIsBefore:
+		return sq.Expr("(? IS NOT NULL AND ? < ?)", from, from, date)
+	default:
+		return sq.Or{
+			sq.Expr("(? IS NOT NULL AND ? > ?)", to, to, date),
+			sq.Expr("(? IS NULL AND ? IS NOT NULL AND ? > ?)", to, from, from, date),
+		}
+	}
+}
+
+func (s *SQLStore) likeCondition(text sq.Sqlizer, pattern string, negate bool) sq.Sqlizer {
+	op := "LIKE"
+	if negate {
+		op = "NOT LIKE"
+	}
+	return sq.Expr("lower(?) "+op+" ? ESCAPE '"+likeEscapeChar+"'", text, strings.ToLower(pattern))
+}
+
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, likeEscapeChar, likeEscapeChar+likeEscapeChar)
+	s = strings.ReplaceAll(s, "%", likeEscapeChar+"%")
+	return strings.ReplaceAll(s, "_", likeEscapeChar+"_")
+}
+
+func stringsToArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// cardPropertyPath returns the JSON path of a card property for the
+// MySQL and SQLite JSON functions.
+func cardPropertyPath(propertyID string) string {
+	id := strings.ReplaceAll(propertyID, `\`, `\\`)
+	id = strings.ReplaceAll(id, `"`, `\"`)
+	return fmt.Sprintf(`$.properties."%s"`, id)
+}
+
+// cardIsNotTemplate returns a condition that excludes card templates.
+func (s *SQLStore) cardIsNotTemplate() sq.Sqlizer {
+	switch s.dbType {
+	case model.PostgresDBType:
+		return sq.Expr("COALESCE(b.fields->>'isTemplate', 'false') <> 'true'")
+	case model.MysqlDBType:
+		return sq.Expr("COALESCE(JSON_UNQUOTE(JSON_EXTRACT(b.fields, '$.isTemplate')), 'false') <> 'true'")
+	default:
+		return sq.Expr("COALESCE(json_type(b.fields, '$.isTemplate'), 'false') <> 'true'")
+	}
+}
+
+// cardPropertyText returns the value of a card property as text. Array
+// values, like the ones of multiSelect properties, are returned as their
+// JSON representation.
+func (s *SQLStore) cardPropertyText(propertyID string) sq.Sqlizer {
+	switch s.dbType {
+	case model.PostgresDBType:
+		return sq.Expr("(b.fields->'properties'->>?)", propertyID)
+	case model.MysqlDBType:
+		return sq.Expr("JSON_UNQUOTE(JSON_EXTRACT(b.fields, ?))", cardPropertyPath(propertyID))
+	default:
+		return sq.Expr("json_extract(b.fields, ?)", cardPropertyPath(propertyID))
+	}
+}
+
+// cardPropertyIncludes returns a condition that is true if the property
+// is equal to the value or, for array properties, contains it.
+func (s *SQLStore) cardPropertyIncludes(propertyID, value string) sq.Sqlizer {
+	switch s.dbType {
+	case model.PostgresDBType:
+		valueJSON, _ := json.Marshal(value)
+		return sq.Expr("COALESCE((b.fields::jsonb->'properties'->?) @> CAST(? AS JSONB), false)", propertyID, string(valueJSON))
+	case model.MysqlDBType:
+		valueJSON, _ := json.Marshal(value)
+		return sq.Expr("COALESCE(JSON_CONTAINS(JSON_EXTRACT(b.fields, ?), ?), 0) = 1", cardPropertyPath(propertyID), string(valueJSON))
+	default:
+		return sq.Expr("EXISTS (SELECT 1 FROM json_each(b.fields, ?) WHERE json_each.value = ?)", cardPropertyPath(propertyID), value)
+	}
+}
+
+// cardPropertyOptionValue returns the lowercased value of the selected
+// option of a select property, or of the first selected option of a
+// multiSelect one, which is what the webapp sorts by.
+func (s *SQLStore) cardPropertyOptionValue(propertyID string, def model.PropDef) sq.Sqlizer {
+	if len(def.Options) == 0 {
+		return sq.Expr("''")
+	}
+
+	var first sq.Sqlizer
+	switch s.dbType {
+	case model.PostgresDBType:
+		first = sq.Expr("COALESCE(b.fields->'properties'->?->>0, b.fields->'properties'->>?)", propertyID, propertyID)
+	case model.MysqlDBType:
+		// MySQL treats a scalar as a single element array
+		first = sq.Expr("JSON_UNQUOTE(JSON_EXTRACT(b.fields, ?))", cardPropertyPath(propertyID)+"[0]")
+	default:
+		first = sq.Expr("COALESCE(json_extract(b.fields, ?), json_extract(b.fields, ?))", cardPropertyPath(propertyID)+"[0]", cardPropertyPath(propertyID))
+	}
+
+	// iterate the options in a stable order so the query text doesn't change
+	ids := make([]string, 0, len(def.Options))
+	for id := range def.Options {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sql := "CASE ?"
+	args := []interface{}{first}
+	for _, id := range ids {
+		sql += " WHEN ? THEN ?"
+		args = append(args, id, strings.ToLower(def.Options[id].Value))
+	}
+	sql += " ELSE '' END"
+
+	return sq.Expr(sql, args...)
+}
+
+// dateFromText extracts a timestamp from a date property value, which
+// is a JSON document of the form {"from": 1642161600000, "to": 1642161600000}
+// stored as a string. Malformed values yield NULL instead of an error.
+func (s *SQLStore) dateFromText(text sq.Sqlizer, field string) sq.Sqlizer {
+	switch s.dbType {
+	case model.PostgresDBType:
+		return sq.Expr("CAST(substring(? from ?) AS BIGINT)", text, `"`+field+`"\s*:\s*(-?[0-9]+)`)
+	case model.MysqlDBType:
+		return sq.Expr("CAST(SUBSTRING_INDEX(REGEXP_SUBSTR(?, ?), ':', -1) AS SIGNED)", text, `"`+field+`"[[:space:]]*:[[:space:]]*-?[0-9]+`)
+	default:
+		return sq.Expr("CASE WHEN json_valid(?) THEN json_extract(?, ?) END", text, text, "$."+field)
+	}
+}
+
+// numberFromText converts the value of a number property to a number,
+// yielding NULL for empty and non numeric values.
+func (s *SQLStore) numberFromText(text sq.Sqlizer) sq.Sqlizer {
+	const pattern = `^[[:space:]]*-?[0-9]+(\.[0-9]+)?[[:space:]]*$`
+
+	switch s.dbType {
+	case model.PostgresDBType:
+		return sq.Expr("CASE WHEN ? ~ ? THEN CAST(? AS DOUBLE PRECISION) END", text, pattern, text)
+	case model.MysqlDBType:
+		return sq.Expr("CASE WHEN ? REGEXP ? THEN CAST(? AS DECIMAL(65,10)) END", text, pattern, text)
+	default:
+		return sq.Expr("CASE WHEN COALESCE(?, '') <> '' THEN CAST(? AS REAL) END", text, text)
+	}
+}
+
+// searchableBlockTypes are the types of the blocks whose title is
+// searched when looking for cards: the card itself and its content.
+var searchableBlockTypes = []model.BlockType{model.TypeCard, model.TypeText, model.TypeCheckbox, model.TypeComment}
+
+// searchCardsForUser returns the cards that match all the words in their
+// title or in one of their text, checkbox or comment blocks, most
+// recently updated matches first.
+func (s *SQLStore) searchCardsForUser(db sq.BaseRunner, opts model.SearchCardsOptions) ([]*model.CardSearchMatch, error) {
+	if len(opts.Words) == 0 {
+		return []*model.CardSearchMatch{}, nil
+	}
+
+	// content blocks are children of their card
+	cardIDExpr := "CASE WHEN m.type = '" + string(model.TypeCard) + "' THEN m.id ELSE m.parent_id END"
+
+	visibleBoards := sq.Or{
+		sq.Expr("bo.id IN (SELECT board_id FROM "+s.tablePrefix+"board_members WHERE user_id = ?)", opts.UserID),
+		sq.Expr("bo.channel_id IN (SELECT channelId FROM ChannelMembers WHERE userId = ?)", opts.UserID),
+	}
+	if opts.IncludePublicBoards {
+		visibleBoards = append(visibleBoards, sq.Eq{"bo.type": model.BoardTypeOpen})
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("b.id", "MAX(m.update_at) AS last_match").
+		From(s.tablePrefix+"blocks AS m").
+		Join(s.tablePrefix+"blocks AS b ON b.id = "+cardIDExpr).
+		Join(s.tablePrefix+"boards AS bo ON bo.id = b.board_id").
+		Where(sq.Eq{
+			"m.type":         searchableBlockTypes,
+			"b.type":         model.TypeCard,
+			"bo.team_id":     opts.TeamID,
+			"bo.is_template": false,
+		}).
+		Where(s.cardIsNotTemplate()).
+		Where(visibleBoards).
+		Where(s.blockTitleMatchesCondition(opts.Words)).
+		GroupBy("b.id").
+		OrderBy("last_match DESC", "b.id")
+
+	if opts.Page != 0 {
+		query = query.Offset(offset(opts.Page, opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		query = query.Limit(limit(opts.PerPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`searchCardsForUser ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	cardIDs := []string{}
+	for rows.Next() {
+		var cardID string
+		var lastMatch int64
+		if err = rows.Scan(&cardID, &lastMatch); err != nil {
+			return nil, err
+		}
+		cardIDs = append(cardIDs, cardID)
+	}
+
+	if len(cardIDs) == 0 {
+		return []*model.CardSearchMatch{}, nil
+	}
+
+	cards, err := s.getBlocksByIDs(db, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make(map[string]*model.CardSearchMatch, len(cards))
+	for _, card := range cards {
+		matches[card.ID] = &model.CardSearchMatch{Card: card}
+	}
+
+	// find the text that matched for each card, preferring the card
+	// title over the most recent content block
+	matchesQuery := s.getQueryBuilder(db).
+		Select(cardIDExpr+" AS card_id", "m.type", "COALESCE(m.title, '')").
+		From(s.tablePrefix + "blocks AS m").
+		Where(sq.Eq{"m.type": searchableBlockTypes}).
+		Where(sq.Or{
+			sq.Eq{"m.id": cardIDs},
+			sq.Eq{"m.parent_id": cardIDs},
+		}).
+		Where(s.blockTitleMatchesCondition(opts.Words)).
+		OrderBy("m.update_at DESC")
+
+	matchRows, err := matchesQuery.Query()
+	if err != nil {
+		s.logger.Error(`searchCardsForUser ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(matchRows)
+
+	for matchRows.Next() {
+		var cardID, text string
+		var blockType model.BlockType
+		if err = matchRows.Scan(&cardID, &blockType, &text); err != nil {
+			return nil, err
+		}
+
+		match, ok := matches[cardID]
+		if !ok || match.MatchType == model.TypeCard {
+			continue
+		}
+		if match.MatchType == "" || blockType == model.TypeCard {
+			match.MatchType = blockType
+			match.MatchText = text
+		}
+	}
+
+	result := make([]*model.CardSearchMatch, 0, len(cardIDs))
+	for _, id := range cardIDs {
+		if match, ok := matches[id]; ok {
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+// blockTitleMatchesCondition returns a condition that is true when the
+// title of the block aliased as m contains all the words. Postgres and
+// MySQL use their full text indexes, matching the words as prefixes,
+// while Sqlite falls back to LIKE.
+func (s *SQLStore) blockTitleMatchesCondition(words []string) sq.Sqlizer {
+	switch s.dbType {
+	case model.PostgresDBType:
+		terms := make([]string, len(words))
+		for i, w := range words {
+			terms[i] = w + ":*"
+		}
+		return sq.Expr("to_tsvector('simple', COALESCE(m.title, '')) @@ to_tsquery('simple', ?)", strings.Join(terms, " & "))
+	case model.MysqlDBType:
+		terms := make([]string, len(words))
+		for i, w := range words {
+			terms[i] = "+" + w + "*"
+		}
+		return sq.Expr("MATCH(m.title) AGAINST (? IN BOOLEAN MODE)", strings.Join(terms, " "))
+	default:
+		conditions := sq.And{}
+		for _, w := range words {
+			conditions = append(conditions, s.likeCondition(sq.Expr("COALESCE(m.title, '')"), "%"+escapeLike(w)+"%", false))
+		}
+		return conditions
+	}
+}
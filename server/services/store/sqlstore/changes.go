@@ -0,0 +1,303 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// getChanges returns the changes of the boards, blocks, memberships and
+// categories visible to a user in a team after a cursor, oldest first.
+// Each kind of change is read from its own table, limited to a page, and
+// the results are merged, so that every change of a page precedes the
+// ones that were not fetched.
+func (s *SQLStore) getChanges(db sq.BaseRunner, opts model.QueryChangesOptions) ([]*model.Change, bool, error) {
+	boardChanges, err := s.getBoardChanges(db, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	blockChanges, err := s.getBlockChanges(db, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	memberChanges, err := s.getMemberChanges(db, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	categoryChanges, err := s.getCategoryChanges(db, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changes := make([]*model.Change, 0, len(boardChanges)+len(blockChanges)+len(memberChanges)+len(categoryChanges))
+	changes = append(changes, boardChanges...)
+	changes = append(changes, blockChanges...)
+	changes = append(changes, memberChanges...)
+	changes = append(changes, categoryChanges...)
+
+	// the changes of each type are already sorted by their queries
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].UpdateAt != changes[j].UpdateAt {
+			return changes[i].UpdateAt < changes[j].UpdateAt
+		}
+		return changes[i].Type.Order() < changes[j].Type.Order()
+	})
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(changes) > opts.PerPage {
+		changes = changes[0:opts.PerPage]
+		hasMore = true
+	}
+	return changes, hasMore, nil
+}
+
+func (s *SQLStore) getBoardChanges(db sq.BaseRunner, opts model.QueryChangesOptions) ([]*model.Change, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardHistoryFields()...).
+		From(s.tablePrefix+"boards_history").
+		Where(sq.Eq{"team_id": opts.TeamID}).
+		Where(s.changesVisibleBoardCondition("id", opts)).
+		OrderBy("update_at", "id")
+
+	if opts.Cursor != nil {
+		query = query.Where(changesCursorCondition(opts.Cursor, model.ChangeTypeBoard, "update_at", "id"))
+	}
+	if opts.Until > 0 {
+		query = query.Where(sq.Lt{"update_at": opts.Until})
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardChanges ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boards, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.Change, len(boards))
+	for i, board := range boards {
+		changes[i] = model.NewBoardChange(board)
+	}
+	return changes, nil
+}
+
+func (s *SQLStore) getBlockChanges(db sq.BaseRunner, opts model.QueryChangesOptions) ([]*model.Change, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix+"blocks_history").
+		Where(s.changesVisibleBoardCondition("board_id", opts)).
+		OrderBy("update_at", "id")
+
+	if opts.Cursor != nil {
+		query = query.Where(changesCursorCondition(opts.Cursor, model.ChangeTypeBlock, "update_at", "id"))
+	}
+	if opts.Until > 0 {
+		query = query.Where(sq.Lt{"update_at": opts.Until})
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBlockChanges ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	blocks, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.Change, len(blocks))
+	for i, block := range blocks {
+		changes[i] = model.NewBlockChange(block)
+	}
+	return changes, nil
+}
+
+func (s *SQLStore) getMemberChanges(db sq.BaseRunner, opts model.QueryChangesOptions) ([]*model.Change, error) {
+	query := s.getQueryBuilder(db).
+		Select("board_id", "user_id", "action", "insert_at").
+		From(s.tablePrefix+"board_members_history").
+		// the user's own memberships are always included, so that the
+		// removal of one tells the client to drop the board
+		Where(sq.Or{
+			s.changesVisibleBoardCondition("board_id", opts),
+			sq.And{
+				sq.Eq{"user_id": opts.UserID},
+				s.changesTeamBoardCondition("board_id", opts),
+			},
+		}).
+		OrderBy("insert_at", "board_id", "user_id")
+
+	if opts.Cursor != nil {
+		query = query.Where(memberChangesCursorCondition(opts.Cursor))
+	}
+	if opts.Until > 0 {
+		query = query.Where(sq.Lt{"insert_at": time.UnixMilli(opts.Until).UTC()})
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getMemberChanges ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	entries, err := s.boardMemberHistoryEntriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.Change, len(entries))
+	for i, entry := range entries {
+		changes[i] = model.NewMemberChange(entry)
+	}
+	return changes, nil
+}
+
+func (s *SQLStore) getCategoryChanges(db sq.BaseRunner, opts model.QueryChangesOptions) ([]*model.Change, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.categoryFields()...).
+		From(s.tablePrefix+"categories").
+		Where(sq.Eq{
+			"user_id": opts.UserID,
+			"team_id": opts.TeamID,
+		}).
+		OrderBy("update_at", "id")
+
+	if opts.Cursor != nil {
+		query = query.Where(changesCursorCondition(opts.Cursor, model.ChangeTypeCategory, "update_at", "id"))
+	}
+	if opts.Until > 0 {
+		query = query.Where(sq.Lt{"update_at": opts.Until})
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCategoryChanges ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	categories, err := s.categoriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.Change, len(categories))
+	for i := range categories {
+		changes[i] = model.NewCategoryChange(&categories[i])
+	}
+	return changes, nil
+}
+
+// changesVisibleBoardCondition selects the rows of the boards of the team
+// the user has access to, as HasPermissionToBoard grants it: the boards
+// they are a member of and, with opts.IncludeImplicitBoards, the boards
+// linked to the channels they are a member of and the open templates.
+// Deleted boards keep their members and their last version, so their
+// deletion is part of the feed.
+func (s *SQLStore) changesVisibleBoardCondition(column string, opts model.QueryChangesOptions) sq.Sqlizer {
+	memberBoards := sq.Expr(column+" IN (SELECT board_id FROM "+s.tablePrefix+"board_members WHERE user_id = ?)", opts.UserID)
+	if !opts.IncludeImplicitBoards {
+		return sq.And{memberBoards, s.changesTeamBoardCondition(column, opts)}
+	}
+
+	implicitAccess := "(channel_id IN (SELECT channelId FROM ChannelMembers WHERE userId = ?) OR (type = ? AND is_template = ?))"
+	implicitArgs := []interface{}{opts.UserID, model.BoardTypeOpen, true}
+	return sq.And{
+		s.changesTeamBoardCondition(column, opts),
+		sq.Or{
+			memberBoards,
+			sq.Expr(column+" IN (SELECT id FROM "+s.tablePrefix+"boards WHERE "+implicitAccess+")", implicitArgs...),
+			sq.Expr(column+" IN (SELECT id FROM "+s.tablePrefix+"boards_history WHERE delete_at > 0 AND "+implicitAccess+
+				" AND id NOT IN (SELECT id FROM "+s.tablePrefix+"boards))", implicitArgs...),
+		},
+	}
+}
+
+// changesTeamBoardCondition selects the rows of the boards of the team,
+// deleted ones included.
+func (s *SQLStore) changesTeamBoardCondition(column string, opts model.QueryChangesOptions) sq.Sqlizer {
+	return sq.Expr(column+" IN (SELECT id FROM "+s.tablePrefix+"boards_history WHERE team_id = ?)", opts.TeamID)
+}
+
+// changesCursorCondition returns the keyset condition that selects the
+// changes of a type that are sorted after the cursor.
+func changesCursorCondition(cursor *model.ChangesCursor, changeType model.ChangeType, updateAtColumn, idColumn string) sq.Sqlizer {
+	switch {
+	case changeType.Order() < cursor.Type.Order():
+		return sq.Gt{updateAtColumn: cursor.UpdateAt}
+	case changeType.Order() > cursor.Type.Order():
+		return sq.GtOrEq{updateAtColumn: cursor.UpdateAt}
+	}
+
+	return sq.Or{
+		sq.Gt{updateAtColumn: cursor.UpdateAt},
+		sq.And{
+			sq.Eq{updateAtColumn: cursor.UpdateAt},
+			sq.Gt{idColumn: cursor.ID},
+		},
+	}
+}
+
+// memberChangesCursorCondition returns the keyset condition that selects
+// the membership changes that are sorted after the cursor. The history
+// of the memberships has no update time, so they are sorted by their
+// insertion time, which is more precise than the millisecond.
+func memberChangesCursorCondition(cursor *model.ChangesCursor) sq.Sqlizer {
+	updateAt := time.UnixMilli(cursor.UpdateAt).UTC()
+
+	switch {
+	case model.ChangeTypeMember.Order() < cursor.Type.Order():
+		return sq.GtOrEq{"insert_at": updateAt.Add(time.Millisecond)}
+	case model.ChangeTypeMember.Order() > cursor.Type.Order():
+		return sq.GtOrEq{"insert_at": updateAt}
+	}
+
+	insertAt := cursor.InsertAt.UTC()
+	return sq.Or{
+		sq.Gt{"insert_at": insertAt},
+		sq.And{
+			sq.Eq{"insert_at": insertAt},
+			sq.Or{
+				sq.Gt{"board_id": cursor.BoardID},
+				sq.And{
+					sq.Eq{"board_id": cursor.BoardID},
+					sq.Gt{"user_id": cursor.ID},
+				},
+			},
+		},
+	}
+}
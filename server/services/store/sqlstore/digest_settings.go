@@ -0,0 +1,149 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var digestSettingsFields = []string{
+	"user_id",
+	"frequency",
+	"send_hour",
+	"send_weekday",
+	"last_sent_at",
+	"update_at",
+}
+
+func (s *SQLStore) digestSettingsFromRows(rows *sql.Rows) ([]*model.DigestSettings, error) {
+	settingsList := []*model.DigestSettings{}
+
+	for rows.Next() {
+		var settings model.DigestSettings
+		err := rows.Scan(
+			&settings.UserID,
+			&settings.Frequency,
+			&settings.Hour,
+			&settings.Weekday,
+			&settings.LastSentAt,
+			&settings.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		settingsList = append(settingsList, &settings)
+	}
+	return settingsList, nil
+}
+
+// upsertDigestSettings stores the digest schedule of a user. The time the
+// last digest was sent is only set when the settings are created.
+func (s *SQLStore) upsertDigestSettings(db sq.BaseRunner, settings *model.DigestSettings) error {
+	if err := settings.IsValid(); err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"digest_settings").
+		Columns(digestSettingsFields...).
+		Values(
+			settings.UserID,
+			settings.Frequency,
+			settings.Hour,
+			settings.Weekday,
+			settings.LastSentAt,
+			now,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE frequency = ?, send_hour = ?, send_weekday = ?, update_at = ?",
+			settings.Frequency, settings.Hour, settings.Weekday, now)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (user_id)
+			 DO UPDATE SET frequency = EXCLUDED.frequency, send_hour = EXCLUDED.send_hour,
+			 send_weekday = EXCLUDED.send_weekday, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert digest settings",
+			mlog.String("user_id", settings.UserID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getDigestSettings(db sq.BaseRunner, userID string) (*model.DigestSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(digestSettingsFields...).
+		From(s.tablePrefix + "digest_settings").
+		Where(sq.Eq{"user_id": userID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDigestSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	settingsList, err := s.digestSettingsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(settingsList) == 0 {
+		return nil, model.NewErrNotFound("digest settings for user ID=" + userID)
+	}
+	return settingsList[0], nil
+}
+
+func (s *SQLStore) getDigestSettingsList(db sq.BaseRunner) ([]*model.DigestSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(digestSettingsFields...).
+		From(s.tablePrefix + "digest_settings").
+		OrderBy("user_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDigestSettingsList ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.digestSettingsFromRows(rows)
+}
+
+// markDigestSent records that the digest of a user was sent at `sentAt`.
+// It returns false if the last sent time is no longer `lastSentAt`, so
+// that each digest is sent once even when several servers send them.
+func (s *SQLStore) markDigestSent(db sq.BaseRunner, userID string, lastSentAt, sentAt int64) (bool, error) {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"digest_settings").
+		Set("last_sent_at", sentAt).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"last_sent_at": lastSentAt}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot mark digest as sent",
+			mlog.String("user_id", userID),
+			mlog.Err(err),
+		)
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var dueDateReminderSettingsFields = []string{
+	"board_id",
+	"enabled",
+	"property_ids",
+	"offsets",
+	"modified_by",
+	"update_at",
+}
+
+func (s *SQLStore) dueDateReminderSettingsFromRows(rows *sql.Rows) ([]*model.DueDateReminderSettings, error) {
+	settingsList := []*model.DueDateReminderSettings{}
+
+	for rows.Next() {
+		var settings model.DueDateReminderSettings
+		var propertyIDs string
+		var offsets string
+		err := rows.Scan(
+			&settings.BoardID,
+			&settings.Enabled,
+			&propertyIDs,
+			&offsets,
+			&settings.ModifiedBy,
+			&settings.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(propertyIDs), &settings.PropertyIDs); err != nil {
+			s.logger.Error("due date reminder properties unmarshal error", mlog.String("board_id", settings.BoardID), mlog.Err(err))
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(offsets), &settings.Offsets); err != nil {
+			s.logger.Error("due date reminder offsets unmarshal error", mlog.String("board_id", settings.BoardID), mlog.Err(err))
+			return nil, err
+		}
+		settingsList = append(settingsList, &settings)
+	}
+	return settingsList, nil
+}
+
+func (s *SQLStore) upsertDueDateReminderSettings(db sq.BaseRunner, settings *model.DueDateReminderSettings) error {
+	if settings.PropertyIDs == nil {
+		settings.PropertyIDs = []string{}
+	}
+	if settings.Offsets == nil {
+		settings.Offsets = []int64{}
+	}
+
+	propertyIDs, err := json.Marshal(settings.PropertyIDs)
+	if err != nil {
+		return err
+	}
+	offsets, err := json.Marshal(settings.Offsets)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"due_date_reminder_settings").
+		Columns(dueDateReminderSettingsFields...).
+		Values(
+			settings.BoardID,
+			settings.Enabled,
+			string(propertyIDs),
+			string(offsets),
+			settings.ModifiedBy,
+			now,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE enabled = ?, property_ids = ?, offsets = ?, modified_by = ?, update_at = ?",
+			settings.Enabled, string(propertyIDs), string(offsets), settings.ModifiedBy, now)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (board_id)
+			 DO UPDATE SET enabled = EXCLUDED.enabled, property_ids = EXCLUDED.property_ids, offsets = EXCLUDED.offsets,
+			 modified_by = EXCLUDED.modified_by, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert due date reminder settings",
+			mlog.String("board_id", settings.BoardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getDueDateReminderSettings(db sq.BaseRunner, boardID string) (*model.DueDateReminderSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(dueDateReminderSettingsFields...).
+		From(s.tablePrefix + "due_date_reminder_settings").
+		Where(sq.Eq{"board_id": boardID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDueDateReminderSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	settingsList, err := s.dueDateReminderSettingsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(settingsList) == 0 {
+		return nil, model.NewErrNotFound("due date reminder settings for board ID=" + boardID)
+	}
+	return settingsList[0], nil
+}
+
+func (s *SQLStore) getEnabledDueDateReminderSettings(db sq.BaseRunner) ([]*model.DueDateReminderSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(dueDateReminderSettingsFields...).
+		From(s.tablePrefix + "due_date_reminder_settings").
+		Where(sq.Eq{"enabled": true}).
+		OrderBy("board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getEnabledDueDateReminderSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.dueDateReminderSettingsFromRows(rows)
+}
+
+// markDueDateReminderSent records that a reminder was sent. It returns
+// false if it was already recorded, so that each reminder is sent once
+// even when several servers look for due reminders.
+func (s *SQLStore) markDueDateReminderSent(db sq.BaseRunner, reminder *model.DueDateReminder) (bool, error) {
+	if reminder.SentAt == 0 {
+		reminder.SentAt = utils.GetMillis()
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"due_date_reminders").
+		Columns(
+			"card_id",
+			"property_id",
+			"offset_minutes",
+			"due_at",
+			"sent_at",
+		).
+		Values(
+			reminder.CardID,
+			reminder.PropertyID,
+			reminder.Offset,
+			reminder.DueAt,
+			reminder.SentAt,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Options("IGNORE")
+	} else {
+		query = query.Suffix("ON CONFLICT DO NOTHING")
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot mark due date reminder as sent",
+			mlog.String("card_id", reminder.CardID),
+			mlog.Err(err),
+		)
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (s *SQLStore) deleteDueDateRemindersSentBefore(db sq.BaseRunner, sentAt int64) (int64, error) {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "due_date_reminders").
+		Where(sq.Lt{"sent_at": sentAt}).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var exportJobFields = []string{
+	"id",
+	"team_id",
+	"status",
+	"changed_since",
+	"board_ids",
+	"boards_total",
+	"boards_done",
+	"chunks",
+	"size",
+	"error",
+	"created_by",
+	"create_at",
+	"update_at",
+	"complete_at",
+}
+
+func (s *SQLStore) exportJobsFromRows(rows *sql.Rows) ([]*model.ExportJob, error) {
+	jobs := []*model.ExportJob{}
+
+	for rows.Next() {
+		var job model.ExportJob
+		var boardIDs sql.NullString
+		err := rows.Scan(
+			&job.ID,
+			&job.TeamID,
+			&job.Status,
+			&job.ChangedSince,
+			&boardIDs,
+			&job.BoardsTotal,
+			&job.BoardsDone,
+			&job.Chunks,
+			&job.Size,
+			&job.Error,
+			&job.CreatedBy,
+			&job.CreateAt,
+			&job.UpdateAt,
+			&job.CompleteAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if boardIDs.String != "" {
+			if err := json.Unmarshal([]byte(boardIDs.String), &job.BoardIDs); err != nil {
+				s.logger.Error("export job board ids unmarshal error", mlog.String("job_id", job.ID), mlog.Err(err))
+				return nil, err
+			}
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+func (s *SQLStore) insertExportJob(db sq.BaseRunner, job *model.ExportJob) error {
+	boardIDs, err := json.Marshal(job.BoardIDs)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"export_jobs").
+		Columns(exportJobFields...).
+		Values(
+			job.ID,
+			job.TeamID,
+			job.Status,
+			job.ChangedSince,
+			string(boardIDs),
+			job.BoardsTotal,
+			job.BoardsDone,
+			job.Chunks,
+			job.Size,
+			job.Error,
+			job.CreatedBy,
+			job.CreateAt,
+			job.UpdateAt,
+			job.CompleteAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert export job",
+			mlog.String("job_id", job.ID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getExportJob(db sq.BaseRunner, jobID string) (*model.ExportJob, error) {
+	query := s.getQueryBuilder(db).
+		Select(exportJobFields...).
+		From(s.tablePrefix + "export_jobs").
+		Where(sq.Eq{"id": jobID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getExportJob ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	jobs, err := s.exportJobsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, model.NewErrNotFound("export job ID=" + jobID)
+	}
+	return jobs[0], nil
+}
+
+// getExportJobsForTeam returns the most recent export jobs of a team.
+func (s *SQLStore) getExportJobsForTeam(db sq.BaseRunner, teamID string, maxJobs int) ([]*model.ExportJob, error) {
+	query := s.getQueryBuilder(db).
+		Select(exportJobFields...).
+		From(s.tablePrefix+"export_jobs").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("create_at DESC", "id").
+		Limit(uint64(maxJobs))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getExportJobsForTeam ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.exportJobsFromRows(rows)
+}
+
+// getActiveExportJobs returns the jobs that are pending or running, the
+// oldest first.
+func (s *SQLStore) getActiveExportJobs(db sq.BaseRunner, maxJobs int) ([]*model.ExportJob, error) {
+	query := s.getQueryBuilder(db).
+		Select(exportJobFields...).
+		From(s.tablePrefix+"export_jobs").
+		Where(sq.Eq{"status": []model.ExportJobStatus{model.ExportJobPending, model.ExportJobRunning}}).
+		OrderBy("create_at", "id").
+		Limit(uint64(maxJobs))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getActiveExportJobs ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.exportJobsFromRows(rows)
+}
+
+func (s *SQLStore) updateExportJob(db sq.BaseRunner, job *model.ExportJob) error {
+	boardIDs, err := json.Marshal(job.BoardIDs)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"export_jobs").
+		Set("status", job.Status).
+		Set("board_ids", string(boardIDs)).
+		Set("boards_total", job.BoardsTotal).
+		Set("boards_done", job.BoardsDone).
+		Set("chunks", job.Chunks).
+		Set("size", job.Size).
+		Set("error", job.Error).
+		Set("update_at", job.UpdateAt).
+		Set("complete_at", job.CompleteAt).
+		Where(sq.Eq{"id": job.ID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update export job",
+			mlog.String("job_id", job.ID),
+			mlog.Err(err),
+		)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("export job ID=" + job.ID)
+	}
+	return nil
+}
+
+// getBoardIDsChangedSince returns the ids of the boards of a team. If
+// `since` is not zero, only the boards updated since then, or with blocks
+// added, updated or deleted since then, are returned.
+func (s *SQLStore) getBoardIDsChangedSince(db sq.BaseRunner, teamID string, since int64) ([]string, error) {
+	query := s.getQueryBuilder(db).
+		Select("b.id").
+		From(s.tablePrefix + "boards as b").
+		Where(sq.Eq{"b.team_id": teamID}).
+		OrderBy("b.id")
+
+	if since > 0 {
+		changedBlocks := sq.Select("1").
+			From(s.tablePrefix + "blocks_history as bh").
+			Where("bh.board_id = b.id").
+			Where(sq.Gt{"bh.update_at": since})
+		query = query.Where(sq.Or{
+			sq.Gt{"b.update_at": since},
+			sq.Expr("EXISTS (?)", changedBlocks),
+		})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardIDsChangedSince ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boardIDs := []string{}
+	for rows.Next() {
+		var boardID string
+		if err := rows.Scan(&boardID); err != nil {
+			return nil, err
+		}
+		boardIDs = append(boardIDs, boardID)
+	}
+	return boardIDs, nil
+}
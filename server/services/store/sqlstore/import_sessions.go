@@ -0,0 +1,229 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var importSessionFields = []string{
+	"id",
+	"team_id",
+	"status",
+	"data",
+	"created_by",
+	"create_at",
+	"update_at",
+}
+
+// importSessionData is the content of an import session stored as JSON.
+type importSessionData struct {
+	Boards          []*model.ImportSessionBoard `json:"boards"`
+	Users           []string                    `json:"users"`
+	UnresolvedUsers []string                    `json:"unresolvedUsers"`
+	Errors          []string                    `json:"errors,omitempty"`
+}
+
+func importSessionDataJSON(session *model.ImportSession) (string, error) {
+	data, err := json.Marshal(importSessionData{
+		Boards:          session.Boards,
+		Users:           session.Users,
+		UnresolvedUsers: session.UnresolvedUsers,
+		Errors:          session.Errors,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *SQLStore) importSessionsFromRows(rows *sql.Rows) ([]*model.ImportSession, error) {
+	sessions := []*model.ImportSession{}
+
+	for rows.Next() {
+		var session model.ImportSession
+		var dataJSON sql.NullString
+		err := rows.Scan(
+			&session.ID,
+			&session.TeamID,
+			&session.Status,
+			&dataJSON,
+			&session.CreatedBy,
+			&session.CreateAt,
+			&session.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if dataJSON.String != "" {
+			var data importSessionData
+			if err := json.Unmarshal([]byte(dataJSON.String), &data); err != nil {
+				s.logger.Error("import session data unmarshal error", mlog.String("session_id", session.ID), mlog.Err(err))
+				return nil, err
+			}
+			session.Boards = data.Boards
+			session.Users = data.Users
+			session.UnresolvedUsers = data.UnresolvedUsers
+			session.Errors = data.Errors
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+func (s *SQLStore) insertImportSession(db sq.BaseRunner, session *model.ImportSession) error {
+	data, err := importSessionDataJSON(session)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"import_sessions").
+		Columns(importSessionFields...).
+		Values(
+			session.ID,
+			session.TeamID,
+			session.Status,
+			data,
+			session.CreatedBy,
+			session.CreateAt,
+			session.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert import session",
+			mlog.String("session_id", session.ID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getImportSession(db sq.BaseRunner, sessionID string) (*model.ImportSession, error) {
+	query := s.getQueryBuilder(db).
+		Select(importSessionFields...).
+		From(s.tablePrefix + "import_sessions").
+		Where(sq.Eq{"id": sessionID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getImportSession ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	sessions, err := s.importSessionsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, model.NewErrNotFound("import session ID=" + sessionID)
+	}
+	return sessions[0], nil
+}
+
+func (s *SQLStore) updateImportSession(db sq.BaseRunner, session *model.ImportSession) error {
+	data, err := importSessionDataJSON(session)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"import_sessions").
+		Set("status", session.Status).
+		Set("data", data).
+		Set("update_at", session.UpdateAt).
+		Where(sq.Eq{"id": session.ID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update import session",
+			mlog.String("session_id", session.ID),
+			mlog.Err(err),
+		)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("import session ID=" + session.ID)
+	}
+	return nil
+}
+
+// updateImportSessionStatus changes the status of a session only if it is
+// still `oldStatus`, and returns false if it isn't, so that a session is
+// confirmed only once.
+func (s *SQLStore) updateImportSessionStatus(db sq.BaseRunner, sessionID string, oldStatus, newStatus model.ImportSessionStatus) (bool, error) {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"import_sessions").
+		Set("status", newStatus).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": sessionID}).
+		Where(sq.Eq{"status": oldStatus}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update import session status",
+			mlog.String("session_id", sessionID),
+			mlog.Err(err),
+		)
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// getImportSessionsUpdatedBefore returns the sessions not updated since
+// `before`, the oldest first.
+func (s *SQLStore) getImportSessionsUpdatedBefore(db sq.BaseRunner, before int64, maxSessions int) ([]*model.ImportSession, error) {
+	query := s.getQueryBuilder(db).
+		Select(importSessionFields...).
+		From(s.tablePrefix+"import_sessions").
+		Where(sq.Lt{"update_at": before}).
+		OrderBy("update_at", "id").
+		Limit(uint64(maxSessions))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getImportSessionsUpdatedBefore ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.importSessionsFromRows(rows)
+}
+
+func (s *SQLStore) deleteImportSession(db sq.BaseRunner, sessionID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "import_sessions").
+		Where(sq.Eq{"id": sessionID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("import session ID=" + sessionID)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (s *SQLStore) upsertIncomingWebhook(db sq.BaseRunner, webhook model.IncomingWebhook) error {
+	now := utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"incoming_webhooks").
+		Columns(
+			"id",
+			"enabled",
+			"token",
+			"modified_by",
+			"update_at",
+		).
+		Values(
+			webhook.ID,
+			webhook.Enabled,
+			webhook.Token,
+			webhook.ModifiedBy,
+			now,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE enabled = ?, token = ?, modified_by = ?, update_at = ?",
+			webhook.Enabled, webhook.Token, webhook.ModifiedBy, now)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (id)
+			 DO UPDATE SET enabled = EXCLUDED.enabled, token = EXCLUDED.token, modified_by = EXCLUDED.modified_by, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	_, err := query.Exec()
+	return err
+}
+
+func (s *SQLStore) getIncomingWebhook(db sq.BaseRunner, boardID string) (*model.IncomingWebhook, error) {
+	query := s.getQueryBuilder(db).
+		Select(
+			"id",
+			"enabled",
+			"token",
+			"modified_by",
+			"update_at",
+		).
+		From(s.tablePrefix + "incoming_webhooks").
+		Where(sq.Eq{"id": boardID})
+	row := query.QueryRow()
+	webhook := model.IncomingWebhook{}
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.Enabled,
+		&webhook.Token,
+		&webhook.ModifiedBy,
+		&webhook.UpdateAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewErrNotFound("incoming webhook for board ID=" + boardID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
-func (s *SQLStore) getBoardsBotID() (string, error) {
+func (s *SQLStore) getBoardsBotID(_ sq.BaseRunner) (string, error) {
 	var boardsBotID string
 	if boardsBotID == "" {
 		var err error
@@ -24,7 +24,7 @@ func (s *SQLStore) getBoardsBotID() (string, error) {
 }
 
 func (s *SQLStore) sendMessage(db sq.BaseRunner, message, postType string, receipts []string) error {
-	botID, err := s.getBoardsBotID()
+	botID, err := s.getBoardsBotID(db)
 	if err != nil {
 		return err
 	}
@@ -53,8 +53,8 @@ func (s *SQLStore) sendMessage(db sq.BaseRunner, message, postType string, recei
 	return nil
 }
 
-func (s *SQLStore) postMessage(_ sq.BaseRunner, message, postType, channelID string) error {
-	botID, err := s.getBoardsBotID()
+func (s *SQLStore) postMessage(db sq.BaseRunner, message, postType, channelID string) error {
+	botID, err := s.getBoardsBotID(db)
 	if err != nil {
 		return err
 	}
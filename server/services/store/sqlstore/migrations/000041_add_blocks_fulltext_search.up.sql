@@ -0,0 +1,24 @@
+{{if .postgres}}
+CREATE INDEX IF NOT EXISTS idx_blocks_title_fts ON {{.prefix}}blocks USING GIN (to_tsvector('simple', COALESCE(title, '')));
+{{end}}
+
+{{if .mysql}}
+SET @stmt = (SELECT IF(
+    (
+      SELECT COUNT(index_name) FROM INFORMATION_SCHEMA.STATISTICS
+      WHERE table_name = '{{.prefix}}blocks'
+      AND table_schema = DATABASE()
+      AND index_name = 'idx_blocks_title_fts'
+    ) > 0,
+    'SELECT 1;',
+    'CREATE FULLTEXT INDEX idx_blocks_title_fts ON {{.prefix}}blocks (title);'
+));
+PREPARE createFulltextIndexIfNeeded FROM @stmt;
+EXECUTE createFulltextIndexIfNeeded;
+DEALLOCATE PREPARE createFulltextIndexIfNeeded;
+{{end}}
+
+{{if .sqlite}}
+-- Sqlite has no full text index; searches fall back to LIKE
+SELECT 1;
+{{end}}
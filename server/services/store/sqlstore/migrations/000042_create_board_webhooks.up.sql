@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}board_webhooks (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    url TEXT,
+    secret VARCHAR(100),
+    events TEXT,
+    enabled BOOLEAN,
+    created_by VARCHAR(36),
+    modified_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}webhook_deliveries (
+    id VARCHAR(36) NOT NULL,
+    webhook_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    event VARCHAR(50),
+    payload {{if .mysql}}MEDIUMTEXT{{else}}TEXT{{end}},
+    status VARCHAR(20),
+    attempts INT,
+    next_attempt_at BIGINT,
+    last_attempt_at BIGINT,
+    response_code INT,
+    error TEXT,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "board_webhooks" "board_id" }}
+{{ createIndexIfNeeded "webhook_deliveries" "status, next_attempt_at" }}
+{{ createIndexIfNeeded "webhook_deliveries" "webhook_id, create_at" }}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_recurrences (
+    card_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    rule TEXT,
+    enabled BOOLEAN,
+    start_at BIGINT,
+    next_run_at BIGINT,
+    last_run_at BIGINT,
+    created_by VARCHAR(36),
+    modified_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (card_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "card_recurrences" "enabled, next_run_at" }}
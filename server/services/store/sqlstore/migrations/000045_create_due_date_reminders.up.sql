@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}due_date_reminder_settings (
+    board_id VARCHAR(36) NOT NULL,
+    enabled BOOLEAN,
+    property_ids TEXT,
+    offsets TEXT,
+    modified_by VARCHAR(36),
+    update_at BIGINT,
+    PRIMARY KEY (board_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}due_date_reminders (
+    card_id VARCHAR(36) NOT NULL,
+    property_id VARCHAR(36) NOT NULL,
+    offset_minutes BIGINT NOT NULL,
+    due_at BIGINT NOT NULL,
+    sent_at BIGINT,
+    PRIMARY KEY (card_id, property_id, offset_minutes, due_at)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "due_date_reminders" "sent_at" }}
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}board_rules (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    title VARCHAR(255),
+    enabled BOOLEAN,
+    trigger_data TEXT,
+    actions TEXT,
+    created_by VARCHAR(36),
+    modified_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}board_rule_runs (
+    id VARCHAR(36) NOT NULL,
+    rule_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    actor_id VARCHAR(36),
+    depth INT,
+    status VARCHAR(20),
+    changes TEXT,
+    error TEXT,
+    create_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "board_rules" "board_id" }}
+{{ createIndexIfNeeded "board_rule_runs" "rule_id, create_at" }}
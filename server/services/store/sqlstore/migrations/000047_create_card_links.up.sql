@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_links (
+    id VARCHAR(36) NOT NULL,
+    link_type VARCHAR(20) NOT NULL,
+    source_card_id VARCHAR(36) NOT NULL,
+    source_board_id VARCHAR(36) NOT NULL,
+    target_card_id VARCHAR(36) NOT NULL,
+    target_board_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36),
+    create_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "card_links" "source_card_id" }}
+{{ createIndexIfNeeded "card_links" "target_card_id" }}
+{{ createIndexIfNeeded "card_links" "source_board_id" }}
+{{ createIndexIfNeeded "card_links" "target_board_id" }}
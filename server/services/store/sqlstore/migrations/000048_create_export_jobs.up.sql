@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}export_jobs (
+    id VARCHAR(36) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    changed_since BIGINT,
+    board_ids {{if .mysql}}MEDIUMTEXT{{else}}TEXT{{end}},
+    boards_total INT,
+    boards_done INT,
+    chunks INT,
+    size BIGINT,
+    error TEXT,
+    created_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    complete_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "export_jobs" "team_id, create_at" }}
+{{ createIndexIfNeeded "export_jobs" "status, create_at" }}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}import_sessions (
+    id VARCHAR(36) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    data {{if .mysql}}MEDIUMTEXT{{else}}TEXT{{end}},
+    created_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "import_sessions" "update_at" }}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}time_entries (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    start_at BIGINT NOT NULL,
+    duration BIGINT NOT NULL,
+    running BOOLEAN,
+    note {{if .mysql}}MEDIUMTEXT{{else}}TEXT{{end}},
+    create_at BIGINT,
+    update_at BIGINT,
+    delete_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "time_entries" "card_id" }}
+{{ createIndexIfNeeded "time_entries" "board_id, start_at" }}
+{{ createIndexIfNeeded "time_entries" "user_id, start_at" }}
+{{ createIndexIfNeeded "time_entries" "update_at" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "subscriptions" "filter" "text" "" }}
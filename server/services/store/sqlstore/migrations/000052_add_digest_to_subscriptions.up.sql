@@ -0,0 +1 @@
+{{ addColumnIfNeeded "subscriptions" "digest" "boolean" "DEFAULT FALSE" }}
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}digest_settings (
+    user_id VARCHAR(36) NOT NULL,
+    frequency VARCHAR(16) NOT NULL,
+    send_hour INT NOT NULL,
+    send_weekday INT NOT NULL,
+    last_sent_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (user_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
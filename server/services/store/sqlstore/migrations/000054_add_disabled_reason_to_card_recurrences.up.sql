@@ -0,0 +1 @@
+{{ addColumnIfNeeded "card_recurrences" "disabled_reason" "varchar(255)" "DEFAULT ''" }}
@@ -0,0 +1,53 @@
+{{- /* the timers started concurrently are stopped when the latest one of the user started */ -}}
+{{if .mysql}}
+UPDATE {{.prefix}}time_entries e
+JOIN (
+    SELECT e1.id, MIN(e2.start_at) AS stopped_at
+    FROM {{.prefix}}time_entries e1
+    JOIN {{.prefix}}time_entries e2 ON e2.user_id = e1.user_id
+        AND e2.running = true AND e2.delete_at = 0
+        AND (e2.start_at > e1.start_at OR (e2.start_at = e1.start_at AND e2.id > e1.id))
+    WHERE e1.running = true AND e1.delete_at = 0
+    GROUP BY e1.id
+) s ON s.id = e.id
+SET e.running = false, e.duration = s.stopped_at - e.start_at;
+{{else}}
+UPDATE {{.prefix}}time_entries
+SET running = false, duration = (
+    SELECT MIN(e2.start_at)
+    FROM {{.prefix}}time_entries e2
+    WHERE e2.user_id = {{.prefix}}time_entries.user_id
+        AND e2.running = true AND e2.delete_at = 0
+        AND (e2.start_at > {{.prefix}}time_entries.start_at OR (e2.start_at = {{.prefix}}time_entries.start_at AND e2.id > {{.prefix}}time_entries.id))
+) - start_at
+WHERE running = true AND delete_at = 0 AND EXISTS (
+    SELECT 1
+    FROM {{.prefix}}time_entries e2
+    WHERE e2.user_id = {{.prefix}}time_entries.user_id
+        AND e2.running = true AND e2.delete_at = 0
+        AND (e2.start_at > {{.prefix}}time_entries.start_at OR (e2.start_at = {{.prefix}}time_entries.start_at AND e2.id > {{.prefix}}time_entries.id))
+);
+{{end}}
+
+{{- /* a user has at most one running timer. MySQL has no partial indexes, so the
+       unique index is on a column that is only set for the running timers */ -}}
+{{if .mysql}}
+{{ addColumnIfNeeded "time_entries" "running_user_id" "VARCHAR(36)" "AS (CASE WHEN running = true AND delete_at = 0 THEN user_id END) STORED" }}
+
+SET @stmt = (SELECT IF(
+    (
+      SELECT COUNT(index_name) FROM INFORMATION_SCHEMA.STATISTICS
+      WHERE table_name = '{{.prefix}}time_entries'
+      AND table_schema = DATABASE()
+      AND index_name = 'idx_time_entries_running_user_id'
+    ) > 0,
+    'SELECT 1;',
+    'CREATE UNIQUE INDEX idx_time_entries_running_user_id ON {{.prefix}}time_entries (running_user_id);'
+));
+PREPARE createIndexIfNeeded FROM @stmt;
+EXECUTE createIndexIfNeeded;
+DEALLOCATE PREPARE createIndexIfNeeded;
+{{else}}
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running_user_id ON {{.prefix}}time_entries (user_id)
+    WHERE running = true AND delete_at = 0;
+{{end}}
@@ -46,11 +46,21 @@ func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boar
 
 }
 
+func (s *SQLStore) AdvanceCardRecurrence(cardID string, runAt int64, nextRunAt int64) (bool, error) {
+	return s.advanceCardRecurrence(s.db, cardID, runAt, nextRunAt)
+
+}
+
 func (s *SQLStore) CanSeeUser(seerID string, seenID string) (bool, error) {
 	return s.canSeeUser(s.db, seerID, seenID)
 
 }
 
+func (s *SQLStore) ClaimWebhookDelivery(deliveryID string, nextAttemptAt int64, leaseUntil int64) (bool, error) {
+	return s.claimWebhookDelivery(s.db, deliveryID, nextAttemptAt, leaseUntil)
+
+}
+
 func (s *SQLStore) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.createBoardsAndBlocks(s.db, bab, userID)
@@ -99,6 +109,30 @@ func (s *SQLStore) CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, us
 
 }
 
+func (s *SQLStore) CreateBoardsAndBlocksWithMembers(bab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.createBoardsAndBlocksWithMembers(s.db, bab, members, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, nil, txErr
+	}
+	result, resultVar1, err := s.createBoardsAndBlocksWithMembers(tx, bab, members, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateBoardsAndBlocksWithMembers"))
+		}
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return result, resultVar1, nil
+
+}
+
 func (s *SQLStore) CreateCategory(category model.Category) error {
 	if s.dbType == model.SqliteDBType {
 		return s.createCategory(s.db, category)
@@ -186,6 +220,54 @@ func (s *SQLStore) DeleteBoardRecord(boardID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) DeleteBoardRule(ruleID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteBoardRule(s.db, ruleID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteBoardRule(tx, ruleID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteBoardRule"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteBoardWebhook(webhookID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteBoardWebhook(s.db, webhookID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteBoardWebhook(tx, webhookID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteBoardWebhook"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBoardsAndBlocks(s.db, dbab, userID)
@@ -210,11 +292,31 @@ func (s *SQLStore) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, user
 
 }
 
+func (s *SQLStore) DeleteCardLink(linkID string) error {
+	return s.deleteCardLink(s.db, linkID)
+
+}
+
+func (s *SQLStore) DeleteCardRecurrence(cardID string) error {
+	return s.deleteCardRecurrence(s.db, cardID)
+
+}
+
 func (s *SQLStore) DeleteCategory(categoryID string, userID string, teamID string) error {
 	return s.deleteCategory(s.db, categoryID, userID, teamID)
 
 }
 
+func (s *SQLStore) DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error) {
+	return s.deleteDueDateRemindersSentBefore(s.db, sentAt)
+
+}
+
+func (s *SQLStore) DeleteImportSession(sessionID string) error {
+	return s.deleteImportSession(s.db, sessionID)
+
+}
+
 func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 	return s.deleteMember(s.db, boardID, userID)
 
@@ -230,6 +332,21 @@ func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error
 
 }
 
+func (s *SQLStore) DeleteTimeEntry(entryID string) error {
+	return s.deleteTimeEntry(s.db, entryID)
+
+}
+
+func (s *SQLStore) DeleteWebhookDeliveriesFinishedBefore(updatedAt int64) (int64, error) {
+	return s.deleteWebhookDeliveriesFinishedBefore(s.db, updatedAt)
+
+}
+
+func (s *SQLStore) DisableCardRecurrence(cardID string, reason string) error {
+	return s.disableCardRecurrence(s.db, cardID, reason)
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -278,6 +395,11 @@ func (s *SQLStore) DuplicateBoard(boardID string, userID string, toTeam string,
 
 }
 
+func (s *SQLStore) GetActiveExportJobs(limit int) ([]*model.ExportJob, error) {
+	return s.getActiveExportJobs(s.db, limit)
+
+}
+
 func (s *SQLStore) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
 	return s.getActiveUserCount(s.db, updatedSecondsAgo)
 
@@ -303,6 +425,11 @@ func (s *SQLStore) GetBlockHistory(blockID string, opts model.QueryBlockHistoryO
 
 }
 
+func (s *SQLStore) GetBlockHistoryChildren(parentID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return s.getBlockHistoryChildren(s.db, parentID, opts)
+
+}
+
 func (s *SQLStore) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
 	return s.getBlockHistoryDescendants(s.db, boardID, opts)
 
@@ -373,11 +500,46 @@ func (s *SQLStore) GetBoardHistory(boardID string, opts model.QueryBoardHistoryO
 
 }
 
+func (s *SQLStore) GetBoardIDsChangedSince(teamID string, since int64) ([]string, error) {
+	return s.getBoardIDsChangedSince(s.db, teamID, since)
+
+}
+
 func (s *SQLStore) GetBoardMemberHistory(boardID string, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
 	return s.getBoardMemberHistory(s.db, boardID, userID, limit)
 
 }
 
+func (s *SQLStore) GetBoardRule(ruleID string) (*model.BoardRule, error) {
+	return s.getBoardRule(s.db, ruleID)
+
+}
+
+func (s *SQLStore) GetBoardRuleRuns(ruleID string, page int, perPage int) ([]*model.BoardRuleRun, error) {
+	return s.getBoardRuleRuns(s.db, ruleID, page, perPage)
+
+}
+
+func (s *SQLStore) GetBoardRulesForBoard(boardID string) ([]*model.BoardRule, error) {
+	return s.getBoardRulesForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetBoardWebhook(webhookID string) (*model.BoardWebhook, error) {
+	return s.getBoardWebhook(s.db, webhookID)
+
+}
+
+func (s *SQLStore) GetBoardWebhooksForBoard(boardID string) ([]*model.BoardWebhook, error) {
+	return s.getBoardWebhooksForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetBoardsBotID() (string, error) {
+	return s.getBoardsBotID(s.db)
+
+}
+
 func (s *SQLStore) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	return s.getBoardsComplianceHistory(s.db, opts)
 
@@ -403,6 +565,26 @@ func (s *SQLStore) GetCardLimitTimestamp() (int64, error) {
 
 }
 
+func (s *SQLStore) GetCardLink(linkID string) (*model.CardLink, error) {
+	return s.getCardLink(s.db, linkID)
+
+}
+
+func (s *SQLStore) GetCardLinksForBoard(boardID string) ([]*model.CardLink, error) {
+	return s.getCardLinksForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetCardLinksForCard(cardID string) ([]*model.CardLink, error) {
+	return s.getCardLinksForCard(s.db, cardID)
+
+}
+
+func (s *SQLStore) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	return s.getCardRecurrence(s.db, cardID)
+
+}
+
 func (s *SQLStore) GetCardsCount() (int64, error) {
 	return s.getCardsCount(s.db)
 
@@ -413,16 +595,71 @@ func (s *SQLStore) GetCategory(id string) (*model.Category, error) {
 
 }
 
+func (s *SQLStore) GetChanges(opts model.QueryChangesOptions) ([]*model.Change, bool, error) {
+	return s.getChanges(s.db, opts)
+
+}
+
 func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
 	return s.getChannel(s.db, teamID, channelID)
 
 }
 
+func (s *SQLStore) GetDigestSettings(userID string) (*model.DigestSettings, error) {
+	return s.getDigestSettings(s.db, userID)
+
+}
+
+func (s *SQLStore) GetDigestSettingsList() ([]*model.DigestSettings, error) {
+	return s.getDigestSettingsList(s.db)
+
+}
+
+func (s *SQLStore) GetDueCardRecurrences(now int64, limit int) ([]*model.CardRecurrence, error) {
+	return s.getDueCardRecurrences(s.db, now, limit)
+
+}
+
+func (s *SQLStore) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
+	return s.getDueDateReminderSettings(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	return s.getEnabledDueDateReminderSettings(s.db)
+
+}
+
+func (s *SQLStore) GetExportJob(jobID string) (*model.ExportJob, error) {
+	return s.getExportJob(s.db, jobID)
+
+}
+
+func (s *SQLStore) GetExportJobsForTeam(teamID string, limit int) ([]*model.ExportJob, error) {
+	return s.getExportJobsForTeam(s.db, teamID, limit)
+
+}
+
 func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
 	return s.getFileInfo(s.db, id)
 
 }
 
+func (s *SQLStore) GetImportSession(sessionID string) (*model.ImportSession, error) {
+	return s.getImportSession(s.db, sessionID)
+
+}
+
+func (s *SQLStore) GetImportSessionsUpdatedBefore(before int64, limit int) ([]*model.ImportSession, error) {
+	return s.getImportSessionsUpdatedBefore(s.db, before, limit)
+
+}
+
+func (s *SQLStore) GetIncomingWebhook(boardID string) (*model.IncomingWebhook, error) {
+	return s.getIncomingWebhook(s.db, boardID)
+
+}
+
 func (s *SQLStore) GetLicense() *mmModel.License {
 	return s.getLicense(s.db)
 
@@ -453,11 +690,21 @@ func (s *SQLStore) GetNotificationHint(blockID string) (*model.NotificationHint,
 
 }
 
+func (s *SQLStore) GetPendingWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error) {
+	return s.getPendingWebhookDeliveries(s.db, now, limit)
+
+}
+
 func (s *SQLStore) GetRegisteredUserCount() (int, error) {
 	return s.getRegisteredUserCount(s.db)
 
 }
 
+func (s *SQLStore) GetRunningTimeEntry(userID string) (*model.TimeEntry, error) {
+	return s.getRunningTimeEntry(s.db, userID)
+
+}
+
 func (s *SQLStore) GetSharing(rootID string) (*model.Sharing, error) {
 	return s.getSharing(s.db, rootID)
 
@@ -518,6 +765,36 @@ func (s *SQLStore) GetTemplateBoards(teamID string, userID string) ([]*model.Boa
 
 }
 
+func (s *SQLStore) GetTimeEntriesForBoard(boardID string) ([]*model.TimeEntry, error) {
+	return s.getTimeEntriesForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetTimeEntriesForCard(cardID string) ([]*model.TimeEntry, error) {
+	return s.getTimeEntriesForCard(s.db, cardID)
+
+}
+
+func (s *SQLStore) GetTimeEntriesForCompliance(opts model.QueryTimeEntriesComplianceOptions) ([]*model.TimeEntry, bool, error) {
+	return s.getTimeEntriesForCompliance(s.db, opts)
+
+}
+
+func (s *SQLStore) GetTimeEntry(entryID string) (*model.TimeEntry, error) {
+	return s.getTimeEntry(s.db, entryID)
+
+}
+
+func (s *SQLStore) GetTimeSpent(opts model.QueryTimeSummaryOptions) ([]*model.TimeSpent, error) {
+	return s.getTimeSpent(s.db, opts)
+
+}
+
+func (s *SQLStore) GetTimeSpentForCards(cardIDs []string) (map[string]int64, error) {
+	return s.getTimeSpentForCards(s.db, cardIDs)
+
+}
+
 func (s *SQLStore) GetUsedCardsCount() (int64, error) {
 	return s.getUsedCardsCount(s.db)
 
@@ -568,6 +845,11 @@ func (s *SQLStore) GetUsersList(userIDs []string, showEmail bool, showName bool)
 
 }
 
+func (s *SQLStore) GetWebhookDeliveries(webhookID string, page int, perPage int) ([]*model.WebhookDelivery, error) {
+	return s.getWebhookDeliveries(s.db, webhookID, page, perPage)
+
+}
+
 func (s *SQLStore) InsertBlock(block *model.Block, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.insertBlock(s.db, block, userID)
@@ -621,6 +903,21 @@ func (s *SQLStore) InsertBoard(board *model.Board, userID string) (*model.Board,
 
 }
 
+func (s *SQLStore) InsertBoardRule(rule *model.BoardRule) (*model.BoardRule, error) {
+	return s.insertBoardRule(s.db, rule)
+
+}
+
+func (s *SQLStore) InsertBoardRuleRun(run *model.BoardRuleRun) error {
+	return s.insertBoardRuleRun(s.db, run)
+
+}
+
+func (s *SQLStore) InsertBoardWebhook(webhook *model.BoardWebhook) (*model.BoardWebhook, error) {
+	return s.insertBoardWebhook(s.db, webhook)
+
+}
+
 func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.insertBoardWithAdmin(s.db, board, userID)
@@ -645,6 +942,41 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) InsertCardLink(link *model.CardLink) (*model.CardLink, error) {
+	return s.insertCardLink(s.db, link)
+
+}
+
+func (s *SQLStore) InsertExportJob(job *model.ExportJob) error {
+	return s.insertExportJob(s.db, job)
+
+}
+
+func (s *SQLStore) InsertImportSession(session *model.ImportSession) error {
+	return s.insertImportSession(s.db, session)
+
+}
+
+func (s *SQLStore) InsertTimeEntry(entry *model.TimeEntry) error {
+	return s.insertTimeEntry(s.db, entry)
+
+}
+
+func (s *SQLStore) InsertWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return s.insertWebhookDelivery(s.db, delivery)
+
+}
+
+func (s *SQLStore) MarkDigestSent(userID string, lastSentAt int64, sentAt int64) (bool, error) {
+	return s.markDigestSent(s.db, userID, lastSentAt, sentAt)
+
+}
+
+func (s *SQLStore) MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error) {
+	return s.markDueDateReminderSent(s.db, reminder)
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -717,6 +1049,16 @@ func (s *SQLStore) PatchBoard(boardID string, boardPatch *model.BoardPatch, user
 
 }
 
+func (s *SQLStore) PatchBoardRule(ruleID string, patch *model.BoardRulePatch, modifiedBy string) (*model.BoardRule, error) {
+	return s.patchBoardRule(s.db, ruleID, patch, modifiedBy)
+
+}
+
+func (s *SQLStore) PatchBoardWebhook(webhookID string, patch *model.BoardWebhookPatch, modifiedBy string) (*model.BoardWebhook, error) {
+	return s.patchBoardWebhook(s.db, webhookID, patch, modifiedBy)
+
+}
+
 func (s *SQLStore) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBoardsAndBlocks(s.db, pbab, userID)
@@ -751,6 +1093,11 @@ func (s *SQLStore) PostMessage(message string, postType string, channelID string
 
 }
 
+func (s *SQLStore) QueryCards(opts model.QueryCardsOptions) ([]*model.Block, string, error) {
+	return s.queryCards(s.db, opts)
+
+}
+
 func (s *SQLStore) RemoveDefaultTemplates(boards []*model.Board) error {
 	return s.removeDefaultTemplates(s.db, boards)
 
@@ -766,6 +1113,35 @@ func (s *SQLStore) ReorderCategoryBoards(categoryID string, newBoardsOrder []str
 
 }
 
+func (s *SQLStore) RestoreCardRevision(restore *model.CardRevisionRestore, userID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreCardRevision(s.db, restore, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.restoreCardRevision(tx, restore, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreCardRevision"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) RestoreFiles(fileIDs []string) error {
+	return s.restoreFiles(s.db, fileIDs)
+
+}
+
 func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.runDataRetention(s.db, globalRetentionDate, batchSize)
@@ -795,11 +1171,6 @@ func (s *SQLStore) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 
 }
 
-func (s *SQLStore) RestoreFiles(fileIDs []string) error {
-	return s.restoreFiles(s.db, fileIDs)
-
-}
-
 func (s *SQLStore) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
 	return s.saveMember(s.db, bm)
 
@@ -815,6 +1186,11 @@ func (s *SQLStore) SearchBoardsForUserInTeam(teamID string, term string, userID
 
 }
 
+func (s *SQLStore) SearchCardsForUser(opts model.SearchCardsOptions) ([]*model.CardSearchMatch, error) {
+	return s.searchCardsForUser(s.db, opts)
+
+}
+
 func (s *SQLStore) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
 	return s.searchUserChannels(s.db, teamID, userID, query)
 
@@ -840,6 +1216,30 @@ func (s *SQLStore) SetSystemSetting(key string, value string) error {
 
 }
 
+func (s *SQLStore) StartTimeEntry(entry *model.TimeEntry) ([]*model.TimeEntry, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.startTimeEntry(s.db, entry)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.startTimeEntry(tx, entry)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "StartTimeEntry"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) UndeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.undeleteBlock(s.db, blockID, modifiedBy)
@@ -898,11 +1298,56 @@ func (s *SQLStore) UpdateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) UpdateExportJob(job *model.ExportJob) error {
+	return s.updateExportJob(s.db, job)
+
+}
+
+func (s *SQLStore) UpdateImportSession(session *model.ImportSession) error {
+	return s.updateImportSession(s.db, session)
+
+}
+
+func (s *SQLStore) UpdateImportSessionStatus(sessionID string, oldStatus model.ImportSessionStatus, newStatus model.ImportSessionStatus) (bool, error) {
+	return s.updateImportSessionStatus(s.db, sessionID, oldStatus, newStatus)
+
+}
+
 func (s *SQLStore) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
 	return s.updateSubscribersNotifiedAt(s.db, blockID, notifiedAt)
 
 }
 
+func (s *SQLStore) UpdateTimeEntry(entry *model.TimeEntry) error {
+	return s.updateTimeEntry(s.db, entry)
+
+}
+
+func (s *SQLStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return s.updateWebhookDelivery(s.db, delivery)
+
+}
+
+func (s *SQLStore) UpsertCardRecurrence(recurrence *model.CardRecurrence) error {
+	return s.upsertCardRecurrence(s.db, recurrence)
+
+}
+
+func (s *SQLStore) UpsertDigestSettings(settings *model.DigestSettings) error {
+	return s.upsertDigestSettings(s.db, settings)
+
+}
+
+func (s *SQLStore) UpsertDueDateReminderSettings(settings *model.DueDateReminderSettings) error {
+	return s.upsertDueDateReminderSettings(s.db, settings)
+
+}
+
+func (s *SQLStore) UpsertIncomingWebhook(webhook model.IncomingWebhook) error {
+	return s.upsertIncomingWebhook(s.db, webhook)
+
+}
+
 func (s *SQLStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	return s.upsertNotificationHint(s.db, hint, notificationFreq)
 
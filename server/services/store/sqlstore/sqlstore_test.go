@@ -13,7 +13,18 @@ import (
 
 func TestSQLStore(t *testing.T) {
 	t.Run("BlocksStore", func(t *testing.T) { storetests.StoreTestBlocksStore(t, SetupTests) })
+	t.Run("CardsStore", func(t *testing.T) { storetests.StoreTestCardsStore(t, SetupTests) })
 	t.Run("SharingStore", func(t *testing.T) { storetests.StoreTestSharingStore(t, SetupTests) })
+	t.Run("WebhooksStore", func(t *testing.T) { storetests.StoreTestWebhooksStore(t, SetupTests) })
+	t.Run("IncomingWebhooksStore", func(t *testing.T) { storetests.StoreTestIncomingWebhooksStore(t, SetupTests) })
+	t.Run("CardRecurrencesStore", func(t *testing.T) { storetests.StoreTestCardRecurrencesStore(t, SetupTests) })
+	t.Run("DueDateRemindersStore", func(t *testing.T) { storetests.StoreTestDueDateRemindersStore(t, SetupTests) })
+	t.Run("DigestSettingsStore", func(t *testing.T) { storetests.StoreTestDigestSettingsStore(t, SetupTests) })
+	t.Run("BoardRulesStore", func(t *testing.T) { storetests.StoreTestBoardRulesStore(t, SetupTests) })
+	t.Run("CardLinksStore", func(t *testing.T) { storetests.StoreTestCardLinksStore(t, SetupTests) })
+	t.Run("ExportJobsStore", func(t *testing.T) { storetests.StoreTestExportJobsStore(t, SetupTests) })
+	t.Run("ImportSessionsStore", func(t *testing.T) { storetests.StoreTestImportSessionsStore(t, SetupTests) })
+	t.Run("TimeEntriesStore", func(t *testing.T) { storetests.StoreTestTimeEntriesStore(t, SetupTests) })
 	t.Run("SystemStore", func(t *testing.T) { storetests.StoreTestSystemStore(t, SetupTests) })
 	t.Run("UserStore", func(t *testing.T) { storetests.StoreTestUserStore(t, SetupTests) })
 	t.Run("TeamStore", func(t *testing.T) { storetests.StoreTestTeamStore(t, SetupTests) })
@@ -26,6 +37,7 @@ func TestSQLStore(t *testing.T) {
 	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
 	t.Run("StoreTestCategoryBoardsStore", func(t *testing.T) { storetests.StoreTestCategoryBoardsStore(t, SetupTests) })
 	t.Run("ComplianceHistoryStore", func(t *testing.T) { storetests.StoreTestComplianceHistoryStore(t, SetupTests) })
+	t.Run("ChangesStore", func(t *testing.T) { storetests.StoreTestChangesStore(t, SetupTests) })
 }
 
 //  tests for  utility functions inside sqlstore.go
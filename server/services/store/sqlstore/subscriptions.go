@@ -5,6 +5,7 @@ package sqlstore
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -21,9 +22,11 @@ var subscriptionFields = []string{
 	"notified_at",
 	"create_at",
 	"delete_at",
+	"filter",
+	"digest",
 }
 
-func valuesForSubscription(sub *model.Subscription) []interface{} {
+func valuesForSubscription(sub *model.Subscription, filter string) []interface{} {
 	return []interface{}{
 		sub.BlockType,
 		sub.BlockID,
@@ -32,14 +35,41 @@ func valuesForSubscription(sub *model.Subscription) []interface{} {
 		sub.NotifiedAt,
 		sub.CreateAt,
 		sub.DeleteAt,
+		filter,
+		sub.Digest,
 	}
 }
 
+// subscriptionFilterToJSON returns the stored value of a subscription filter;
+// empty filters are stored as an empty string.
+func subscriptionFilterToJSON(filter *model.SubscriptionFilter) (string, error) {
+	if filter.IsEmpty() {
+		return "", nil
+	}
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	return string(filterJSON), nil
+}
+
+func subscriptionFilterFromJSON(filterJSON string) (*model.SubscriptionFilter, error) {
+	if filterJSON == "" {
+		return nil, nil
+	}
+	var filter model.SubscriptionFilter
+	if err := json.Unmarshal([]byte(filterJSON), &filter); err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
 func (s *SQLStore) subscriptionsFromRows(rows *sql.Rows) ([]*model.Subscription, error) {
 	subscriptions := []*model.Subscription{}
 
 	for rows.Next() {
 		var sub model.Subscription
+		var filterJSON sql.NullString
 		err := rows.Scan(
 			&sub.BlockType,
 			&sub.BlockID,
@@ -48,41 +78,60 @@ func (s *SQLStore) subscriptionsFromRows(rows *sql.Rows) ([]*model.Subscription,
 			&sub.NotifiedAt,
 			&sub.CreateAt,
 			&sub.DeleteAt,
+			&filterJSON,
+			&sub.Digest,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if sub.Filter, err = subscriptionFilterFromJSON(filterJSON.String); err != nil {
+			s.logger.Error("subscription filter unmarshal error",
+				mlog.String("block_id", sub.BlockID),
+				mlog.String("subscriber_id", sub.SubscriberID),
+				mlog.Err(err),
+			)
+			return nil, err
+		}
 		subscriptions = append(subscriptions, &sub)
 	}
 	return subscriptions, nil
 }
 
-// createSubscription creates a new subscription, or returns an existing subscription
-// for the block & subscriber.
+// createSubscription creates a new subscription, or updates the filter of an existing
+// subscription for the block & subscriber.
 func (s *SQLStore) createSubscription(db sq.BaseRunner, sub *model.Subscription) (*model.Subscription, error) {
 	if err := sub.IsValid(); err != nil {
 		return nil, err
 	}
 
+	filter, err := subscriptionFilterToJSON(sub.Filter)
+	if err != nil {
+		return nil, err
+	}
+
 	now := model.GetMillis()
 
 	subAdd := *sub
 	subAdd.NotifiedAt = now // notified_at set so first notification doesn't pick up all history
 	subAdd.CreateAt = now
 	subAdd.DeleteAt = 0
+	if subAdd.Filter.IsEmpty() {
+		subAdd.Filter = nil
+	}
 
 	query := s.getQueryBuilder(db).
 		Insert(s.tablePrefix + "subscriptions").
 		Columns(subscriptionFields...).
-		Values(valuesForSubscription(&subAdd)...)
+		Values(valuesForSubscription(&subAdd, filter)...)
 
 	if s.dbType == model.MysqlDBType {
-		query = query.Suffix("ON DUPLICATE KEY UPDATE delete_at = 0, notified_at = ?", now)
+		query = query.Suffix("ON DUPLICATE KEY UPDATE delete_at = 0, notified_at = ?, filter = ?, digest = ?", now, filter, sub.Digest)
 	} else {
-		query = query.Suffix("ON CONFLICT (block_id,subscriber_id) DO UPDATE SET delete_at = 0, notified_at = ?", now)
+		query = query.Suffix("ON CONFLICT (block_id,subscriber_id) DO UPDATE SET delete_at = 0, notified_at = ?, filter = ?, digest = ?",
+			now, filter, sub.Digest)
 	}
 
-	if _, err := query.Exec(); err != nil {
+	if _, err = query.Exec(); err != nil {
 		s.logger.Error("Cannot create subscription",
 			mlog.String("block_id", sub.BlockID),
 			mlog.String("subscriber_id", sub.SubscriberID),
@@ -185,6 +234,8 @@ func (s *SQLStore) getSubscribersForBlock(db sq.BaseRunner, blockID string) ([]*
 			"subscriber_type",
 			"subscriber_id",
 			"notified_at",
+			"filter",
+			"digest",
 		).
 		From(s.tablePrefix + "subscriptions").
 		Where(sq.Eq{"block_id": blockID}).
@@ -205,14 +256,25 @@ func (s *SQLStore) getSubscribersForBlock(db sq.BaseRunner, blockID string) ([]*
 
 	for rows.Next() {
 		var sub model.Subscriber
+		var filterJSON sql.NullString
 		err := rows.Scan(
 			&sub.SubscriberType,
 			&sub.SubscriberID,
 			&sub.NotifiedAt,
+			&filterJSON,
+			&sub.Digest,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if sub.Filter, err = subscriptionFilterFromJSON(filterJSON.String); err != nil {
+			s.logger.Error("subscriber filter unmarshal error",
+				mlog.String("block_id", blockID),
+				mlog.String("subscriber_id", sub.SubscriberID),
+				mlog.Err(err),
+			)
+			return nil, err
+		}
 		subscribers = append(subscribers, &sub)
 	}
 	return subscribers, nil
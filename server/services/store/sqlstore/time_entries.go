@@ -0,0 +1,476 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func timeEntryFields(prefix string) []string {
+	fields := []string{
+		"id",
+		"board_id",
+		"card_id",
+		"user_id",
+		"start_at",
+		"duration",
+		"running",
+		"COALESCE(note, '')",
+		"create_at",
+		"update_at",
+		"delete_at",
+	}
+
+	if prefix == "" {
+		return fields
+	}
+
+	prefixedFields := make([]string, len(fields))
+	for i, field := range fields {
+		switch field {
+		case "COALESCE(note, '')":
+			prefixedFields[i] = "COALESCE(" + prefix + "note, '')"
+		default:
+			prefixedFields[i] = prefix + field
+		}
+	}
+	return prefixedFields
+}
+
+func (s *SQLStore) timeEntriesFromRows(rows *sql.Rows) ([]*model.TimeEntry, error) {
+	entries := []*model.TimeEntry{}
+
+	for rows.Next() {
+		var entry model.TimeEntry
+		var running sql.NullBool
+		err := rows.Scan(
+			&entry.ID,
+			&entry.BoardID,
+			&entry.CardID,
+			&entry.UserID,
+			&entry.StartAt,
+			&entry.Duration,
+			&running,
+			&entry.Note,
+			&entry.CreateAt,
+			&entry.UpdateAt,
+			&entry.DeleteAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Running = running.Bool
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (s *SQLStore) insertTimeEntry(db sq.BaseRunner, entry *model.TimeEntry) error {
+	query, err := s.timeEntryInsertQuery(db, entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err = query.Exec(); err != nil {
+		s.logger.Error("Cannot insert time entry",
+			mlog.String("card_id", entry.CardID),
+			mlog.String("user_id", entry.UserID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// timeEntryInsertQuery returns the query that inserts a valid entry.
+func (s *SQLStore) timeEntryInsertQuery(db sq.BaseRunner, entry *model.TimeEntry) (sq.InsertBuilder, error) {
+	if err := entry.IsValid(); err != nil {
+		return sq.InsertBuilder{}, err
+	}
+
+	now := utils.GetMillis()
+	if entry.CreateAt == 0 {
+		entry.CreateAt = now
+	}
+	if entry.UpdateAt == 0 {
+		entry.UpdateAt = now
+	}
+
+	return s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"time_entries").
+		Columns(
+			"id",
+			"board_id",
+			"card_id",
+			"user_id",
+			"start_at",
+			"duration",
+			"running",
+			"note",
+			"create_at",
+			"update_at",
+			"delete_at",
+		).
+		Values(
+			entry.ID,
+			entry.BoardID,
+			entry.CardID,
+			entry.UserID,
+			entry.StartAt,
+			entry.Duration,
+			entry.Running,
+			entry.Note,
+			entry.CreateAt,
+			entry.UpdateAt,
+			entry.DeleteAt,
+		), nil
+}
+
+func (s *SQLStore) getTimeEntry(db sq.BaseRunner, entryID string) (*model.TimeEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("")...).
+		From(s.tablePrefix + "time_entries").
+		Where(sq.Eq{"id": entryID}).
+		Where(sq.Eq{"delete_at": 0})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTimeEntry ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	entries, err := s.timeEntriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, model.NewErrNotFound("time entry ID=" + entryID)
+	}
+	return entries[0], nil
+}
+
+func (s *SQLStore) updateTimeEntry(db sq.BaseRunner, entry *model.TimeEntry) error {
+	if err := entry.IsValid(); err != nil {
+		return err
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"time_entries").
+		Set("start_at", entry.StartAt).
+		Set("duration", entry.Duration).
+		Set("running", entry.Running).
+		Set("note", entry.Note).
+		Set("update_at", entry.UpdateAt).
+		Where(sq.Eq{"id": entry.ID}).
+		Where(sq.Eq{"delete_at": 0}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot update time entry",
+			mlog.String("time_entry_id", entry.ID),
+			mlog.Err(err),
+		)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("time entry ID=" + entry.ID)
+	}
+	return nil
+}
+
+// deleteTimeEntry marks a time entry as deleted, so that it is still
+// included in compliance exports.
+func (s *SQLStore) deleteTimeEntry(db sq.BaseRunner, entryID string) error {
+	now := utils.GetMillis()
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"time_entries").
+		Set("running", false).
+		Set("update_at", now).
+		Set("delete_at", now).
+		Where(sq.Eq{"id": entryID}).
+		Where(sq.Eq{"delete_at": 0}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("time entry ID=" + entryID)
+	}
+	return nil
+}
+
+// getTimeEntriesForCard returns the time entries of a card, the most recent
+// first.
+func (s *SQLStore) getTimeEntriesForCard(db sq.BaseRunner, cardID string) ([]*model.TimeEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("")...).
+		From(s.tablePrefix+"time_entries").
+		Where(sq.Eq{"card_id": cardID}).
+		Where(sq.Eq{"delete_at": 0}).
+		OrderBy("start_at DESC", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTimeEntriesForCard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.timeEntriesFromRows(rows)
+}
+
+// getTimeEntriesForBoard returns the time entries of the cards of a board,
+// the oldest first.
+func (s *SQLStore) getTimeEntriesForBoard(db sq.BaseRunner, boardID string) ([]*model.TimeEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("")...).
+		From(s.tablePrefix+"time_entries").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"delete_at": 0}).
+		OrderBy("start_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTimeEntriesForBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.timeEntriesFromRows(rows)
+}
+
+// getRunningTimeEntry returns the time entry of the running timer of a
+// user.
+func (s *SQLStore) getRunningTimeEntry(db sq.BaseRunner, userID string) (*model.TimeEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("")...).
+		From(s.tablePrefix+"time_entries").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"running": true}).
+		Where(sq.Eq{"delete_at": 0}).
+		OrderBy("start_at DESC", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getRunningTimeEntry ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	entries, err := s.timeEntriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, model.NewErrNotFound("running time entry for userID=" + userID)
+	}
+	return entries[0], nil
+}
+
+// startTimeEntry inserts the running entry of a timer after stopping the
+// timers that are running for the same user, which are returned, so that a
+// user has at most one running timer. If another timer of the user was
+// started concurrently, the unique index on the running timers keeps the
+// entry from being inserted, and a conflict error is returned.
+func (s *SQLStore) startTimeEntry(db sq.BaseRunner, entry *model.TimeEntry) ([]*model.TimeEntry, error) {
+	insertQuery, err := s.timeEntryInsertQuery(db, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("")...).
+		From(s.tablePrefix + "time_entries").
+		Where(sq.Eq{"user_id": entry.UserID}).
+		Where(sq.Eq{"running": true}).
+		Where(sq.Eq{"delete_at": 0})
+	// the running entries are locked, so that concurrent starts don't both
+	// stop them
+	if s.dbType != model.SqliteDBType {
+		query = query.Suffix("FOR UPDATE")
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`startTimeEntry ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	running, err := s.timeEntriesFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range running {
+		r.Stop(entry.StartAt)
+		if err = s.updateTimeEntry(db, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.dbType == model.MysqlDBType {
+		// only the unique key violation is ignored, unlike with INSERT IGNORE
+		insertQuery = insertQuery.Suffix("ON DUPLICATE KEY UPDATE id = id")
+	} else {
+		insertQuery = insertQuery.Suffix("ON CONFLICT (user_id) WHERE running = true AND delete_at = 0 DO NOTHING")
+	}
+
+	result, err := insertQuery.Exec()
+	if err != nil {
+		s.logger.Error("Cannot start time entry",
+			mlog.String("card_id", entry.CardID),
+			mlog.String("user_id", entry.UserID),
+			mlog.Err(err),
+		)
+		return nil, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrConflict("running time entry for user ID="+entry.UserID, nil)
+	}
+	return running, nil
+}
+
+// getTimeSpentForCards returns the time spent on each of the cards, summed
+// from their stopped time entries.
+func (s *SQLStore) getTimeSpentForCards(db sq.BaseRunner, cardIDs []string) (map[string]int64, error) {
+	timeSpent := make(map[string]int64, len(cardIDs))
+	if len(cardIDs) == 0 {
+		return timeSpent, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("card_id", "COALESCE(SUM(duration), 0)").
+		From(s.tablePrefix + "time_entries").
+		Where(sq.Eq{"card_id": cardIDs}).
+		Where(sq.Eq{"delete_at": 0}).
+		GroupBy("card_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTimeSpentForCards ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	for rows.Next() {
+		var cardID string
+		var duration int64
+		if err := rows.Scan(&cardID, &duration); err != nil {
+			return nil, err
+		}
+		timeSpent[cardID] = duration
+	}
+	return timeSpent, nil
+}
+
+// getTimeSpent returns the time spent by each user on each card, summed
+// from the stopped time entries selected by the options.
+func (s *SQLStore) getTimeSpent(db sq.BaseRunner, opts model.QueryTimeSummaryOptions) ([]*model.TimeSpent, error) {
+	query := s.getQueryBuilder(db).
+		Select("board_id", "card_id", "user_id", "COALESCE(SUM(duration), 0)", "COUNT(*)").
+		From(s.tablePrefix+"time_entries").
+		Where(sq.Eq{"delete_at": 0}).
+		Where(sq.Eq{"running": false}).
+		Where(sq.GtOrEq{"start_at": opts.From}).
+		Where(sq.Lt{"start_at": opts.To}).
+		GroupBy("board_id", "card_id", "user_id")
+
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"board_id": opts.BoardID})
+	}
+	if opts.UserID != "" {
+		query = query.Where(sq.Eq{"user_id": opts.UserID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getTimeSpent ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	spent := []*model.TimeSpent{}
+	for rows.Next() {
+		var ts model.TimeSpent
+		if err := rows.Scan(&ts.BoardID, &ts.CardID, &ts.UserID, &ts.Duration, &ts.Count); err != nil {
+			return nil, err
+		}
+		spent = append(spent, &ts)
+	}
+	return spent, nil
+}
+
+func (s *SQLStore) getTimeEntriesForCompliance(db sq.BaseRunner, opts model.QueryTimeEntriesComplianceOptions) ([]*model.TimeEntry, bool, error) {
+	query := s.getQueryBuilder(db).
+		Select(timeEntryFields("te.")...).
+		From(s.tablePrefix + "time_entries as te").
+		Join(s.tablePrefix + "boards as b on b.id=te.board_id")
+
+	if !opts.IncludeDeleted {
+		query = query.Where(sq.Eq{"te.delete_at": 0})
+	}
+
+	if opts.TeamID != "" {
+		query = query.Where(sq.Eq{"b.team_id": opts.TeamID})
+	}
+
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"te.board_id": opts.BoardID})
+	}
+
+	if opts.ModifiedSince != 0 {
+		query = query.Where(sq.Gt{"te.update_at": opts.ModifiedSince})
+	}
+
+	query = query.OrderBy("te.update_at", "te.id")
+
+	if opts.Page != 0 {
+		query = query.Offset(offset(opts.Page, opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(limit(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetTimeEntriesForCompliance ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	entries, err := s.timeEntriesFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(entries) > opts.PerPage {
+		entries = entries[0:opts.PerPage]
+		hasMore = true
+	}
+	return entries, hasMore, nil
+}
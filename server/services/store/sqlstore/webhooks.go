@@ -0,0 +1,394 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var boardWebhookFields = []string{
+	"id",
+	"board_id",
+	"url",
+	"secret",
+	"events",
+	"enabled",
+	"created_by",
+	"modified_by",
+	"create_at",
+	"update_at",
+}
+
+var webhookDeliveryFields = []string{
+	"id",
+	"webhook_id",
+	"board_id",
+	"event",
+	"payload",
+	"status",
+	"attempts",
+	"next_attempt_at",
+	"last_attempt_at",
+	"response_code",
+	"error",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) boardWebhooksFromRows(rows *sql.Rows) ([]*model.BoardWebhook, error) {
+	webhooks := []*model.BoardWebhook{}
+
+	for rows.Next() {
+		var webhook model.BoardWebhook
+		var events string
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.BoardID,
+			&webhook.URL,
+			&webhook.Secret,
+			&events,
+			&webhook.Enabled,
+			&webhook.CreatedBy,
+			&webhook.ModifiedBy,
+			&webhook.CreateAt,
+			&webhook.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+			s.logger.Error("board webhook events unmarshal error", mlog.String("webhook_id", webhook.ID), mlog.Err(err))
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, nil
+}
+
+func (s *SQLStore) webhookDeliveriesFromRows(rows *sql.Rows) ([]*model.WebhookDelivery, error) {
+	deliveries := []*model.WebhookDelivery{}
+
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.BoardID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastAttemptAt,
+			&delivery.ResponseCode,
+			&delivery.Error,
+			&delivery.CreateAt,
+			&delivery.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
+
+func (s *SQLStore) insertBoardWebhook(db sq.BaseRunner, webhook *model.BoardWebhook) (*model.BoardWebhook, error) {
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	webhookAdd := *webhook
+	webhookAdd.CreateAt = now
+	webhookAdd.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"board_webhooks").
+		Columns(boardWebhookFields...).
+		Values(
+			webhookAdd.ID,
+			webhookAdd.BoardID,
+			webhookAdd.URL,
+			webhookAdd.Secret,
+			string(events),
+			webhookAdd.Enabled,
+			webhookAdd.CreatedBy,
+			webhookAdd.ModifiedBy,
+			webhookAdd.CreateAt,
+			webhookAdd.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert board webhook",
+			mlog.String("board_id", webhook.BoardID),
+			mlog.Err(err),
+		)
+		return nil, err
+	}
+	return &webhookAdd, nil
+}
+
+func (s *SQLStore) getBoardWebhook(db sq.BaseRunner, webhookID string) (*model.BoardWebhook, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardWebhookFields...).
+		From(s.tablePrefix + "board_webhooks").
+		Where(sq.Eq{"id": webhookID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardWebhook ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	webhooks, err := s.boardWebhooksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, model.NewErrNotFound("board webhook ID=" + webhookID)
+	}
+	return webhooks[0], nil
+}
+
+func (s *SQLStore) getBoardWebhooksForBoard(db sq.BaseRunner, boardID string) ([]*model.BoardWebhook, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardWebhookFields...).
+		From(s.tablePrefix+"board_webhooks").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardWebhooksForBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardWebhooksFromRows(rows)
+}
+
+func (s *SQLStore) patchBoardWebhook(db sq.BaseRunner, webhookID string, patch *model.BoardWebhookPatch, modifiedBy string) (*model.BoardWebhook, error) {
+	if err := patch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getBoardWebhook(db, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := patch.Patch(existing)
+	webhook.ModifiedBy = modifiedBy
+	webhook.UpdateAt = utils.GetMillis()
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"board_webhooks").
+		Set("url", webhook.URL).
+		Set("secret", webhook.Secret).
+		Set("events", string(events)).
+		Set("enabled", webhook.Enabled).
+		Set("modified_by", webhook.ModifiedBy).
+		Set("update_at", webhook.UpdateAt).
+		Where(sq.Eq{"id": webhookID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`patchBoardWebhook ERROR`, mlog.String("webhook_id", webhookID), mlog.Err(err))
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// deleteBoardWebhook deletes a webhook together with its delivery log.
+func (s *SQLStore) deleteBoardWebhook(db sq.BaseRunner, webhookID string) error {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_webhooks").
+		Where(sq.Eq{"id": webhookID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("board webhook ID=" + webhookID)
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID}).
+		Exec()
+	return err
+}
+
+// deleteBoardWebhooksForBoard deletes the webhooks of a board together
+// with their delivery logs.
+func (s *SQLStore) deleteBoardWebhooksForBoard(db sq.BaseRunner, boardID string) error {
+	_, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Eq{"board_id": boardID}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_webhooks").
+		Where(sq.Eq{"board_id": boardID}).
+		Exec()
+	return err
+}
+
+func (s *SQLStore) insertWebhookDelivery(db sq.BaseRunner, delivery *model.WebhookDelivery) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"webhook_deliveries").
+		Columns(webhookDeliveryFields...).
+		Values(
+			delivery.ID,
+			delivery.WebhookID,
+			delivery.BoardID,
+			delivery.Event,
+			delivery.Payload,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastAttemptAt,
+			delivery.ResponseCode,
+			delivery.Error,
+			delivery.CreateAt,
+			delivery.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert webhook delivery",
+			mlog.String("webhook_id", delivery.WebhookID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// getWebhookDeliveries returns the delivery log of a webhook, newest first.
+func (s *SQLStore) getWebhookDeliveries(db sq.BaseRunner, webhookID string, page, perPage int) ([]*model.WebhookDelivery, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookDeliveryFields...).
+		From(s.tablePrefix+"webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID}).
+		OrderBy("create_at DESC", "id DESC")
+
+	if perPage > 0 {
+		query = query.
+			Offset(offset(page, perPage)).
+			Limit(limit(perPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getWebhookDeliveries ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.webhookDeliveriesFromRows(rows)
+}
+
+// getPendingWebhookDeliveries returns the pending deliveries due at `now`,
+// the ones waiting the longest first.
+func (s *SQLStore) getPendingWebhookDeliveries(db sq.BaseRunner, now int64, maxDeliveries int) ([]*model.WebhookDelivery, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookDeliveryFields...).
+		From(s.tablePrefix+"webhook_deliveries").
+		Where(sq.Eq{"status": model.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at", "id").
+		Limit(uint64(maxDeliveries))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getPendingWebhookDeliveries ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.webhookDeliveriesFromRows(rows)
+}
+
+// claimWebhookDelivery pushes the next attempt of a pending delivery to
+// `leaseUntil`, but only if nobody else did it since it was read. This
+// makes sure that a single server of a cluster attempts each delivery,
+// and that it is retried if that server dies before recording the result.
+func (s *SQLStore) claimWebhookDelivery(db sq.BaseRunner, deliveryID string, nextAttemptAt, leaseUntil int64) (bool, error) {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"webhook_deliveries").
+		Set("next_attempt_at", leaseUntil).
+		Where(sq.Eq{"id": deliveryID}).
+		Where(sq.Eq{"status": model.WebhookDeliveryPending}).
+		Where(sq.Eq{"next_attempt_at": nextAttemptAt}).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (s *SQLStore) updateWebhookDelivery(db sq.BaseRunner, delivery *model.WebhookDelivery) error {
+	_, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("last_attempt_at", delivery.LastAttemptAt).
+		Set("response_code", delivery.ResponseCode).
+		Set("error", delivery.Error).
+		Set("update_at", delivery.UpdateAt).
+		Where(sq.Eq{"id": delivery.ID}).
+		Exec()
+	if err != nil {
+		s.logger.Error(`updateWebhookDelivery ERROR`, mlog.String("delivery_id", delivery.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// deleteWebhookDeliveriesFinishedBefore deletes the deliveries that
+// succeeded or failed before `updatedAt`, and returns how many there were.
+func (s *SQLStore) deleteWebhookDeliveriesFinishedBefore(db sq.BaseRunner, updatedAt int64) (int64, error) {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Eq{"status": []model.WebhookDeliveryStatus{model.WebhookDeliverySuccess, model.WebhookDeliveryFailed}}).
+		Where(sq.Lt{"update_at": updatedAt}).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
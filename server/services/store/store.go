@@ -24,6 +24,8 @@ type Store interface {
 	GetBlocksWithType(boardID, blockType string) ([]*model.Block, error)
 	GetSubTree2(boardID, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error)
 	GetBlocksForBoard(boardID string) ([]*model.Block, error)
+	QueryCards(opts model.QueryCardsOptions) ([]*model.Block, string, error)
+	SearchCardsForUser(opts model.SearchCardsOptions) ([]*model.CardSearchMatch, error)
 	// @withTransaction
 	InsertBlock(block *model.Block, userID string) error
 	// @withTransaction
@@ -41,6 +43,7 @@ type Store interface {
 	PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error
 	GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
+	GetBlockHistoryChildren(parentID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
 	GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error)
 	GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error)
@@ -51,6 +54,8 @@ type Store interface {
 	DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error)
 	// @withTransaction
 	PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error
+	// @withTransaction
+	RestoreCardRevision(restore *model.CardRevisionRestore, userID string) error
 
 	Shutdown() error
 
@@ -73,6 +78,78 @@ type Store interface {
 	UpsertSharing(sharing model.Sharing) error
 	GetSharing(rootID string) (*model.Sharing, error)
 
+	UpsertIncomingWebhook(webhook model.IncomingWebhook) error
+	GetIncomingWebhook(boardID string) (*model.IncomingWebhook, error)
+
+	InsertBoardWebhook(webhook *model.BoardWebhook) (*model.BoardWebhook, error)
+	GetBoardWebhook(webhookID string) (*model.BoardWebhook, error)
+	GetBoardWebhooksForBoard(boardID string) ([]*model.BoardWebhook, error)
+	PatchBoardWebhook(webhookID string, patch *model.BoardWebhookPatch, modifiedBy string) (*model.BoardWebhook, error)
+	// @withTransaction
+	DeleteBoardWebhook(webhookID string) error
+	InsertWebhookDelivery(delivery *model.WebhookDelivery) error
+	GetWebhookDeliveries(webhookID string, page, perPage int) ([]*model.WebhookDelivery, error)
+	GetPendingWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error)
+	ClaimWebhookDelivery(deliveryID string, nextAttemptAt, leaseUntil int64) (bool, error)
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	DeleteWebhookDeliveriesFinishedBefore(updatedAt int64) (int64, error)
+
+	UpsertCardRecurrence(recurrence *model.CardRecurrence) error
+	GetCardRecurrence(cardID string) (*model.CardRecurrence, error)
+	DeleteCardRecurrence(cardID string) error
+	GetDueCardRecurrences(now int64, limit int) ([]*model.CardRecurrence, error)
+	AdvanceCardRecurrence(cardID string, runAt, nextRunAt int64) (bool, error)
+	DisableCardRecurrence(cardID, reason string) error
+
+	UpsertDueDateReminderSettings(settings *model.DueDateReminderSettings) error
+	GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error)
+	GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error)
+	MarkDueDateReminderSent(reminder *model.DueDateReminder) (bool, error)
+	DeleteDueDateRemindersSentBefore(sentAt int64) (int64, error)
+
+	InsertBoardRule(rule *model.BoardRule) (*model.BoardRule, error)
+	GetBoardRule(ruleID string) (*model.BoardRule, error)
+	GetBoardRulesForBoard(boardID string) ([]*model.BoardRule, error)
+	PatchBoardRule(ruleID string, patch *model.BoardRulePatch, modifiedBy string) (*model.BoardRule, error)
+	// @withTransaction
+	DeleteBoardRule(ruleID string) error
+	InsertBoardRuleRun(run *model.BoardRuleRun) error
+	GetBoardRuleRuns(ruleID string, page, perPage int) ([]*model.BoardRuleRun, error)
+	GetBoardsBotID() (string, error)
+
+	InsertCardLink(link *model.CardLink) (*model.CardLink, error)
+	GetCardLink(linkID string) (*model.CardLink, error)
+	GetCardLinksForCard(cardID string) ([]*model.CardLink, error)
+	GetCardLinksForBoard(boardID string) ([]*model.CardLink, error)
+	DeleteCardLink(linkID string) error
+
+	InsertExportJob(job *model.ExportJob) error
+	GetExportJob(jobID string) (*model.ExportJob, error)
+	GetExportJobsForTeam(teamID string, limit int) ([]*model.ExportJob, error)
+	GetActiveExportJobs(limit int) ([]*model.ExportJob, error)
+	UpdateExportJob(job *model.ExportJob) error
+	GetBoardIDsChangedSince(teamID string, since int64) ([]string, error)
+
+	InsertImportSession(session *model.ImportSession) error
+	GetImportSession(sessionID string) (*model.ImportSession, error)
+	UpdateImportSession(session *model.ImportSession) error
+	UpdateImportSessionStatus(sessionID string, oldStatus, newStatus model.ImportSessionStatus) (bool, error)
+	GetImportSessionsUpdatedBefore(before int64, limit int) ([]*model.ImportSession, error)
+	DeleteImportSession(sessionID string) error
+
+	InsertTimeEntry(entry *model.TimeEntry) error
+	GetTimeEntry(entryID string) (*model.TimeEntry, error)
+	UpdateTimeEntry(entry *model.TimeEntry) error
+	DeleteTimeEntry(entryID string) error
+	GetTimeEntriesForCard(cardID string) ([]*model.TimeEntry, error)
+	GetTimeEntriesForBoard(boardID string) ([]*model.TimeEntry, error)
+	GetRunningTimeEntry(userID string) (*model.TimeEntry, error)
+	// @withTransaction
+	StartTimeEntry(entry *model.TimeEntry) ([]*model.TimeEntry, error)
+	GetTimeSpentForCards(cardIDs []string) (map[string]int64, error)
+	GetTimeSpent(opts model.QueryTimeSummaryOptions) ([]*model.TimeSpent, error)
+	GetTimeEntriesForCompliance(opts model.QueryTimeEntriesComplianceOptions) ([]*model.TimeEntry, bool, error)
+
 	UpsertTeamSignupToken(team model.Team) error
 	UpsertTeamSettings(team model.Team) error
 	GetTeam(ID string) (*model.Team, error)
@@ -106,6 +183,8 @@ type Store interface {
 	// @withTransaction
 	CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
+	CreateBoardsAndBlocksWithMembers(bab *model.BoardsAndBlocks, members []*model.BoardMember, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error)
+	// @withTransaction
 	PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
 	DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error
@@ -138,6 +217,11 @@ type Store interface {
 	GetSubscribersCountForBlock(blockID string) (int, error)
 	UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error
 
+	UpsertDigestSettings(settings *model.DigestSettings) error
+	GetDigestSettings(userID string) (*model.DigestSettings, error)
+	GetDigestSettingsList() ([]*model.DigestSettings, error)
+	MarkDigestSent(userID string, lastSentAt, sentAt int64) (bool, error)
+
 	UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error)
 	DeleteNotificationHint(blockID string) error
 	GetNotificationHint(blockID string) (*model.NotificationHint, error)
@@ -170,6 +254,9 @@ type Store interface {
 	GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error)
 	GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error)
 
+	// Changes feed
+	GetChanges(opts model.QueryChangesOptions) ([]*model.Change, bool, error)
+
 	// For unit testing only
 	DeleteBoardRecord(boardID, modifiedBy string) error
 	DeleteBlockRecord(blockID, modifiedBy string) error
@@ -45,6 +45,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testPatchBlocks(t, store)
 	})
+	t.Run("RestoreCardRevision", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRestoreCardRevision(t, store)
+	})
 	t.Run("DeleteBlock", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -90,6 +95,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testGetBlockHistoryNewestChildren(t, store)
 	})
+	t.Run("GetBlockHistoryChildren", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBlockHistoryChildren(t, store)
+	})
 }
 
 func testInsertBlock(t *testing.T, store store.Store) {
@@ -556,6 +566,47 @@ func testPatchBlock(t *testing.T, store store.Store) {
 		require.Equal(t, "test value 2", retrievedBlock.Fields["test2"])
 		require.Equal(t, nil, retrievedBlock.Fields["test3"])
 	})
+
+	t.Run("stale patch", func(t *testing.T) {
+		block, err := store.GetBlock(testBlockID)
+		require.NoError(t, err)
+
+		staleUpdateAt := block.UpdateAt - 1
+		staleTitle := "Stale title"
+		blockPatch := &model.BlockPatch{
+			Title:            &staleTitle,
+			ExpectedUpdateAt: &staleUpdateAt,
+		}
+
+		err = store.PatchBlock(testBlockID, blockPatch, testUserID2)
+		require.True(t, model.IsErrConflict(err))
+
+		retrievedBlock, err := store.GetBlock(testBlockID)
+		require.NoError(t, err)
+		require.Equal(t, block.Title, retrievedBlock.Title)
+		require.Equal(t, block.UpdateAt, retrievedBlock.UpdateAt)
+	})
+
+	t.Run("patch of the current version", func(t *testing.T) {
+		block, err := store.GetBlock(testBlockID)
+		require.NoError(t, err)
+
+		// Wait for not colliding the ID+insert_at key
+		time.Sleep(1 * time.Millisecond)
+
+		currentTitle := "Current title"
+		blockPatch := &model.BlockPatch{
+			Title:            &currentTitle,
+			ExpectedUpdateAt: &block.UpdateAt,
+		}
+
+		err = store.PatchBlock(testBlockID, blockPatch, testUserID2)
+		require.NoError(t, err)
+
+		retrievedBlock, err := store.GetBlock(testBlockID)
+		require.NoError(t, err)
+		require.Equal(t, "Current title", retrievedBlock.Title)
+	})
 }
 
 func testPatchBlocks(t *testing.T, store store.Store) {
@@ -649,6 +700,68 @@ func testPatchBlocks(t *testing.T, store store.Store) {
 	})
 }
 
+func testRestoreCardRevision(t *testing.T, store store.Store) {
+	userID := testUserID
+	boardID := utils.NewID(utils.IDTypeBoard)
+	cardID := utils.NewID(utils.IDTypeCard)
+	textID := utils.NewID(utils.IDTypeBlock)
+	newTextID := utils.NewID(utils.IDTypeBlock)
+
+	_, err := store.InsertBoard(&model.Board{ID: boardID, TeamID: testTeamID, Type: model.BoardTypeOpen}, userID)
+	require.NoError(t, err)
+
+	err = store.InsertBlocks([]*model.Block{
+		{ID: cardID, BoardID: boardID, Type: model.TypeCard, Title: "new title"},
+		{ID: newTextID, BoardID: boardID, ParentID: cardID, Type: model.TypeText, Title: "new text"},
+	}, userID)
+	require.NoError(t, err)
+
+	restoredTitle := "old title"
+	restore := func(patchedBlockID string) *model.CardRevisionRestore {
+		return &model.CardRevisionRestore{
+			InsertBlocks: []*model.Block{
+				{ID: textID, BoardID: boardID, ParentID: cardID, Type: model.TypeText, Title: "old text"},
+			},
+			PatchBlocks: model.BlockPatchBatch{
+				BlockIDs:     []string{patchedBlockID},
+				BlockPatches: []model.BlockPatch{{Title: &restoredTitle}},
+			},
+			DeleteBlockIDs: []string{newTextID},
+		}
+	}
+
+	t.Run("a failed restore changes nothing", func(t *testing.T) {
+		// the patched block doesn't exist, so the insertion is rolled back
+		time.Sleep(1 * time.Millisecond)
+		err := store.RestoreCardRevision(restore(utils.NewID(utils.IDTypeCard)), userID)
+		var nf *model.ErrNotFound
+		require.ErrorAs(t, err, &nf)
+
+		_, err = store.GetBlock(textID)
+		require.True(t, model.IsErrNotFound(err))
+
+		_, err = store.GetBlock(newTextID)
+		require.NoError(t, err)
+	})
+
+	t.Run("the revision is restored", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		err := store.RestoreCardRevision(restore(cardID), userID)
+		require.NoError(t, err)
+
+		card, err := store.GetBlock(cardID)
+		require.NoError(t, err)
+		require.Equal(t, restoredTitle, card.Title)
+
+		text, err := store.GetBlock(textID)
+		require.NoError(t, err)
+		require.Equal(t, "old text", text.Title)
+
+		_, err = store.GetBlock(newTextID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
 var (
 	subtreeSampleBlocks = []*model.Block{
 		{
@@ -1539,3 +1652,48 @@ func testGetBlockHistoryNewestChildren(t *testing.T, store store.Store) {
 		}
 	})
 }
+
+func testGetBlockHistoryChildren(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 1)
+	board := boards[0]
+
+	cards := createTestCards(t, store, testUserID, board.ID, 2)
+	card := cards[0]
+	content := createTestBlocksForCard(t, store, card.ID, 3)
+	createTestBlocksForCard(t, store, cards[1].ID, 2)
+
+	// patch and delete a content block to create some history records
+	title := "patched"
+	err := store.PatchBlock(content[0].ID, &model.BlockPatch{Title: &title}, testUserID)
+	require.NoError(t, err)
+	err = store.DeleteBlock(content[1].ID, testUserID)
+	require.NoError(t, err)
+
+	t.Run("all the versions of the children", func(t *testing.T) {
+		blocks, err := store.GetBlockHistoryChildren(card.ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Len(t, blocks, 5)
+
+		for _, block := range blocks {
+			require.Equal(t, card.ID, block.ParentID)
+		}
+
+		last := blocks[len(blocks)-1]
+		require.Equal(t, content[1].ID, last.ID)
+		require.NotZero(t, last.DeleteAt)
+	})
+
+	t.Run("newest first with a limit", func(t *testing.T) {
+		opts := model.QueryBlockHistoryOptions{Descending: true, Limit: 1}
+		blocks, err := store.GetBlockHistoryChildren(card.ID, opts)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		require.Equal(t, content[1].ID, blocks[0].ID)
+	})
+
+	t.Run("invalid parent", func(t *testing.T) {
+		blocks, err := store.GetBlockHistoryChildren(utils.NewID(utils.IDTypeCard), model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+	})
+}
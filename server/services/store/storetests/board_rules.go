@@ -0,0 +1,155 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestBoardRulesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("BoardRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBoardRules(t, store)
+	})
+	t.Run("BoardRuleRuns", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBoardRuleRuns(t, store)
+	})
+}
+
+func createTestBoardRule(t *testing.T, store store.Store, boardID string) *model.BoardRule {
+	rule, err := store.InsertBoardRule(&model.BoardRule{
+		ID:      utils.NewID(utils.IDTypeNone),
+		BoardID: boardID,
+		Title:   "Complete cards",
+		Enabled: true,
+		Trigger: model.RuleTrigger{
+			Type:       model.RuleTriggerPropertyChanged,
+			PropertyID: "status-id",
+			Value:      "done-id",
+		},
+		Actions: []model.RuleAction{
+			{Type: model.RuleActionSetDateNow, PropertyID: "completed-id"},
+			{Type: model.RuleActionClearProperty, PropertyID: "owner-id"},
+		},
+		CreatedBy:  testUserID,
+		ModifiedBy: testUserID,
+	})
+	require.NoError(t, err)
+	return rule
+}
+
+func testBoardRules(t *testing.T, store store.Store) {
+	t.Run("insert and get", func(t *testing.T) {
+		rule := createTestBoardRule(t, store, "board-id-1")
+		require.NotZero(t, rule.CreateAt)
+
+		fetched, err := store.GetBoardRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, fetched)
+
+		rules, err := store.GetBoardRulesForBoard("board-id-1")
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule, rules[0])
+
+		rules, err = store.GetBoardRulesForBoard("other-board")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := store.InsertBoardRule(&model.BoardRule{
+			ID:      utils.NewID(utils.IDTypeNone),
+			BoardID: "board-id-1",
+			Trigger: model.RuleTrigger{Type: model.RuleTriggerCardCreated},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		rule := createTestBoardRule(t, store, "board-id-2")
+
+		enabled := false
+		patched, err := store.PatchBoardRule(rule.ID, &model.BoardRulePatch{
+			Enabled: &enabled,
+			Actions: []model.RuleAction{{Type: model.RuleActionSetProperty, PropertyID: "status-id", Value: "archived-id"}},
+		}, "user-id-2")
+		require.NoError(t, err)
+		require.Equal(t, "Complete cards", patched.Title)
+		require.False(t, patched.Enabled)
+		require.Equal(t, rule.Trigger, patched.Trigger)
+		require.Len(t, patched.Actions, 1)
+		require.Equal(t, "user-id-2", patched.ModifiedBy)
+
+		fetched, err := store.GetBoardRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, patched, fetched)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rule := createTestBoardRule(t, store, "board-id-3")
+		require.NoError(t, store.InsertBoardRuleRun(&model.BoardRuleRun{
+			ID:       utils.NewID(utils.IDTypeNone),
+			RuleID:   rule.ID,
+			BoardID:  rule.BoardID,
+			CardID:   "card-id",
+			ActorID:  testUserID,
+			Status:   model.BoardRuleRunSuccess,
+			CreateAt: utils.GetMillis(),
+		}))
+
+		require.NoError(t, store.DeleteBoardRule(rule.ID))
+
+		_, err := store.GetBoardRule(rule.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		runs, err := store.GetBoardRuleRuns(rule.ID, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, runs)
+
+		err = store.DeleteBoardRule(rule.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testBoardRuleRuns(t *testing.T, store store.Store) {
+	rule := createTestBoardRule(t, store, "board-id-1")
+
+	newRun := func(status model.BoardRuleRunStatus, createAt int64) *model.BoardRuleRun {
+		run := &model.BoardRuleRun{
+			ID:       utils.NewID(utils.IDTypeNone),
+			RuleID:   rule.ID,
+			BoardID:  rule.BoardID,
+			CardID:   "card-id",
+			ActorID:  testUserID,
+			Status:   status,
+			Changes:  map[string]any{"completed-id": `{"from":100}`},
+			CreateAt: createAt,
+		}
+		require.NoError(t, store.InsertBoardRuleRun(run))
+		return run
+	}
+
+	first := newRun(model.BoardRuleRunSuccess, 100)
+	second := newRun(model.BoardRuleRunFailed, 200)
+
+	runs, err := store.GetBoardRuleRuns(rule.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, second, runs[0])
+	require.Equal(t, first, runs[1])
+
+	runs, err = store.GetBoardRuleRuns(rule.ID, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, first.ID, runs[0].ID)
+}
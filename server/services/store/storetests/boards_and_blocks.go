@@ -194,6 +194,61 @@ func testCreateBoardsAndBlocks(t *testing.T, store store.Store) {
 		require.Empty(t, bab)
 		require.Empty(t, members)
 	})
+
+	t.Run("create boards and blocks with members", func(t *testing.T) {
+		testBoardID := utils.NewID(utils.IDTypeBoard)
+		testBlockID := utils.NewID(utils.IDTypeBlock)
+
+		newBab := &model.BoardsAndBlocks{
+			Boards: []*model.Board{
+				{ID: testBoardID, TeamID: teamID, Type: model.BoardTypePrivate},
+			},
+			Blocks: []*model.Block{
+				{ID: testBlockID, BoardID: testBoardID, Type: model.TypeCard},
+			},
+		}
+		newMembers := []*model.BoardMember{
+			{BoardID: testBoardID, UserID: userID, SchemeAdmin: true},
+			{BoardID: testBoardID, UserID: "other-user", SchemeEditor: true},
+		}
+
+		bab, members, err := store.CreateBoardsAndBlocksWithMembers(newBab, newMembers, userID)
+		require.NoError(t, err)
+		require.Len(t, bab.Boards, 1)
+		require.Len(t, bab.Blocks, 1)
+		require.Len(t, members, 2)
+
+		members, err = store.GetMembersForBoard(testBoardID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+	})
+
+	t.Run("members are not added if a block fails", func(t *testing.T) {
+		testBoardID := utils.NewID(utils.IDTypeBoard)
+
+		newBab := &model.BoardsAndBlocks{
+			Boards: []*model.Board{
+				{ID: testBoardID, TeamID: teamID, Type: model.BoardTypePrivate},
+			},
+			Blocks: []*model.Block{
+				{ID: utils.NewID(utils.IDTypeBlock), BoardID: testBoardID, Type: model.TypeCard, Title: strings.Repeat("A", model.BlockTitleMaxRunes+1)},
+			},
+		}
+		newMembers := []*model.BoardMember{
+			{BoardID: testBoardID, UserID: userID, SchemeAdmin: true},
+		}
+
+		bab, members, err := store.CreateBoardsAndBlocksWithMembers(newBab, newMembers, userID)
+		require.ErrorIs(t, err, model.ErrBlockTitleSizeLimitExceeded)
+		require.Empty(t, bab)
+		require.Empty(t, members)
+
+		_, err = store.GetBoard(testBoardID)
+		require.True(t, model.IsErrNotFound(err))
+		members, err = store.GetMembersForBoard(testBoardID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
 }
 
 func testPatchBoardsAndBlocks(t *testing.T, store store.Store) {
@@ -0,0 +1,169 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestCardLinksStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CardLinks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCardLinks(t, store)
+	})
+	t.Run("DuplicateBoardCardLinks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDuplicateBoardCardLinks(t, store)
+	})
+}
+
+func testCardLinks(t *testing.T, store store.Store) {
+	t.Run("insert and get", func(t *testing.T) {
+		link, err := store.InsertCardLink(&model.CardLink{
+			ID:            utils.NewID(utils.IDTypeNone),
+			Type:          model.CardLinkBlocks,
+			SourceCardID:  "card-id-1",
+			SourceBoardID: "board-id-1",
+			TargetCardID:  "card-id-2",
+			TargetBoardID: "board-id-2",
+			CreatedBy:     testUserID,
+		})
+		require.NoError(t, err)
+		require.NotZero(t, link.CreateAt)
+
+		fetched, err := store.GetCardLink(link.ID)
+		require.NoError(t, err)
+		require.Equal(t, link, fetched)
+
+		links, err := store.GetCardLinksForCard("card-id-2")
+		require.NoError(t, err)
+		require.Equal(t, []*model.CardLink{link}, links)
+
+		links, err = store.GetCardLinksForBoard("board-id-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.CardLink{link}, links)
+
+		links, err = store.GetCardLinksForCard("card-id-3")
+		require.NoError(t, err)
+		require.Empty(t, links)
+	})
+
+	t.Run("blocked by links are stored as blocks links", func(t *testing.T) {
+		link, err := store.InsertCardLink(&model.CardLink{
+			ID:            utils.NewID(utils.IDTypeNone),
+			Type:          model.CardLinkBlockedBy,
+			SourceCardID:  "card-id-4",
+			SourceBoardID: "board-id-1",
+			TargetCardID:  "card-id-5",
+			TargetBoardID: "board-id-1",
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.CardLinkBlocks, link.Type)
+		require.Equal(t, "card-id-5", link.SourceCardID)
+		require.Equal(t, "card-id-4", link.TargetCardID)
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		_, err := store.InsertCardLink(&model.CardLink{
+			ID:            utils.NewID(utils.IDTypeNone),
+			Type:          model.CardLinkRelatesTo,
+			SourceCardID:  "card-id-1",
+			SourceBoardID: "board-id-1",
+			TargetCardID:  "card-id-1",
+			TargetBoardID: "board-id-1",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		link, err := store.InsertCardLink(&model.CardLink{
+			ID:            utils.NewID(utils.IDTypeNone),
+			Type:          model.CardLinkDuplicates,
+			SourceCardID:  "card-id-6",
+			SourceBoardID: "board-id-3",
+			TargetCardID:  "card-id-7",
+			TargetBoardID: "board-id-3",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteCardLink(link.ID))
+
+		_, err = store.GetCardLink(link.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.DeleteCardLink(link.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testDuplicateBoardCardLinks(t *testing.T, store store.Store) {
+	boardID := utils.NewID(utils.IDTypeBoard)
+	cardID1 := utils.NewID(utils.IDTypeCard)
+	cardID2 := utils.NewID(utils.IDTypeCard)
+
+	_, err := store.CreateBoardsAndBlocks(&model.BoardsAndBlocks{
+		Boards: []*model.Board{
+			{ID: boardID, TeamID: testTeamID, Type: model.BoardTypeOpen},
+		},
+		Blocks: []*model.Block{
+			{ID: cardID1, BoardID: boardID, Type: model.TypeCard},
+			{ID: cardID2, BoardID: boardID, Type: model.TypeCard},
+		},
+	}, testUserID)
+	require.NoError(t, err)
+
+	_, err = store.InsertCardLink(&model.CardLink{
+		ID:            utils.NewID(utils.IDTypeNone),
+		Type:          model.CardLinkBlocks,
+		SourceCardID:  cardID1,
+		SourceBoardID: boardID,
+		TargetCardID:  cardID2,
+		TargetBoardID: boardID,
+	})
+	require.NoError(t, err)
+	_, err = store.InsertCardLink(&model.CardLink{
+		ID:            utils.NewID(utils.IDTypeNone),
+		Type:          model.CardLinkRelatesTo,
+		SourceCardID:  cardID2,
+		SourceBoardID: boardID,
+		TargetCardID:  "other-card-id",
+		TargetBoardID: "other-board-id",
+	})
+	require.NoError(t, err)
+
+	bab, _, err := store.DuplicateBoard(boardID, testUserID, testTeamID, false)
+	require.NoError(t, err)
+	newBoardID := bab.Boards[0].ID
+
+	links, err := store.GetCardLinksForBoard(newBoardID)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	newCardIDs := map[string]bool{}
+	for _, block := range bab.Blocks {
+		newCardIDs[block.ID] = true
+	}
+	for _, link := range links {
+		require.True(t, newCardIDs[link.SourceCardID])
+		require.Equal(t, newBoardID, link.SourceBoardID)
+		if link.Type == model.CardLinkBlocks {
+			require.True(t, newCardIDs[link.TargetCardID])
+			require.Equal(t, newBoardID, link.TargetBoardID)
+		} else {
+			require.Equal(t, "other-card-id", link.TargetCardID)
+		}
+	}
+
+	// the links of the original board are unchanged
+	links, err = store.GetCardLinksForBoard(boardID)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+}
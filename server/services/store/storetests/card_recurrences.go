@@ -0,0 +1,148 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestCardRecurrencesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CardRecurrences", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCardRecurrences(t, store)
+	})
+	t.Run("DueCardRecurrences", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueCardRecurrences(t, store)
+	})
+}
+
+func newTestCardRecurrence(cardID string, nextRunAt int64) *model.CardRecurrence {
+	return &model.CardRecurrence{
+		CardID:     cardID,
+		BoardID:    "board-id",
+		Rule:       model.RecurrenceRule{Frequency: model.RecurrenceWeekly, Weekdays: []int{1}, Hour: 9},
+		Enabled:    true,
+		StartAt:    nextRunAt,
+		NextRunAt:  nextRunAt,
+		CreatedBy:  testUserID,
+		ModifiedBy: testUserID,
+	}
+}
+
+func testCardRecurrences(t *testing.T, store store.Store) {
+	t.Run("upsert and get", func(t *testing.T) {
+		recurrence := newTestCardRecurrence("card-id-1", 1000)
+		require.NoError(t, store.UpsertCardRecurrence(recurrence))
+
+		fetched, err := store.GetCardRecurrence("card-id-1")
+		require.NoError(t, err)
+		require.NotZero(t, fetched.CreateAt)
+		require.Equal(t, recurrence.Rule, fetched.Rule)
+		require.Equal(t, int64(1000), fetched.NextRunAt)
+
+		recurrence.Rule = model.RecurrenceRule{Frequency: model.RecurrenceCron, Cron: "0 9 * * 1-5"}
+		recurrence.NextRunAt = 2000
+		recurrence.ModifiedBy = "user-id-2"
+		require.NoError(t, store.UpsertCardRecurrence(recurrence))
+
+		updated, err := store.GetCardRecurrence("card-id-1")
+		require.NoError(t, err)
+		require.Equal(t, recurrence.Rule, updated.Rule)
+		require.Equal(t, int64(2000), updated.NextRunAt)
+		require.Equal(t, "user-id-2", updated.ModifiedBy)
+		require.Equal(t, testUserID, updated.CreatedBy)
+		require.Equal(t, fetched.CreateAt, updated.CreateAt)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		recurrence := newTestCardRecurrence("card-id-2", 1000)
+		recurrence.Rule.Frequency = "yearly"
+		require.Error(t, store.UpsertCardRecurrence(recurrence))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.UpsertCardRecurrence(newTestCardRecurrence("card-id-3", 1000)))
+		require.NoError(t, store.DeleteCardRecurrence("card-id-3"))
+
+		_, err := store.GetCardRecurrence("card-id-3")
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.DeleteCardRecurrence("card-id-3")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testDueCardRecurrences(t *testing.T, store store.Store) {
+	require.NoError(t, store.UpsertCardRecurrence(newTestCardRecurrence("card-id-1", 1000)))
+	require.NoError(t, store.UpsertCardRecurrence(newTestCardRecurrence("card-id-2", 500)))
+	require.NoError(t, store.UpsertCardRecurrence(newTestCardRecurrence("card-id-3", 5000)))
+	disabled := newTestCardRecurrence("card-id-4", 100)
+	disabled.Enabled = false
+	require.NoError(t, store.UpsertCardRecurrence(disabled))
+
+	t.Run("due recurrences", func(t *testing.T) {
+		due, err := store.GetDueCardRecurrences(2000, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		require.Equal(t, "card-id-2", due[0].CardID)
+		require.Equal(t, "card-id-1", due[1].CardID)
+
+		due, err = store.GetDueCardRecurrences(2000, 1)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+	})
+
+	t.Run("advance", func(t *testing.T) {
+		advanced, err := store.AdvanceCardRecurrence("card-id-1", 1000, 8000)
+		require.NoError(t, err)
+		require.True(t, advanced)
+
+		// the occurrence was already recorded
+		advanced, err = store.AdvanceCardRecurrence("card-id-1", 1000, 8000)
+		require.NoError(t, err)
+		require.False(t, advanced)
+
+		recurrence, err := store.GetCardRecurrence("card-id-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), recurrence.LastRunAt)
+		require.Equal(t, int64(8000), recurrence.NextRunAt)
+
+		due, err := store.GetDueCardRecurrences(2000, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.Equal(t, "card-id-2", due[0].CardID)
+	})
+	t.Run("disable", func(t *testing.T) {
+		require.NoError(t, store.DisableCardRecurrence("card-id-2", "permission denied"))
+
+		recurrence, err := store.GetCardRecurrence("card-id-2")
+		require.NoError(t, err)
+		require.False(t, recurrence.Enabled)
+		require.Equal(t, "permission denied", recurrence.DisabledReason)
+
+		due, err := store.GetDueCardRecurrences(2000, 10)
+		require.NoError(t, err)
+		require.Empty(t, due)
+
+		// enabling the recurrence again clears the reason
+		recurrence.Enabled = true
+		recurrence.DisabledReason = ""
+		require.NoError(t, store.UpsertCardRecurrence(recurrence))
+
+		recurrence, err = store.GetCardRecurrence("card-id-2")
+		require.NoError(t, err)
+		require.True(t, recurrence.Enabled)
+		require.Empty(t, recurrence.DisabledReason)
+
+		err = store.DisableCardRecurrence("unknown-card-id", "permission denied")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
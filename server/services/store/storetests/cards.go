@@ -0,0 +1,407 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testStatusPropertyID   = "status-prop"
+	testEstimatePropertyID = "estimate-prop"
+	testStatusTodo         = "status-todo"
+	testStatusDone         = "status-done"
+)
+
+func StoreTestCardsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("QueryCardsFilter", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testQueryCardsFilter(t, store)
+	})
+	t.Run("QueryCardsSort", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testQueryCardsSort(t, store)
+	})
+	t.Run("QueryCardsPagination", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testQueryCardsPagination(t, store)
+	})
+	t.Run("SearchCardsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSearchCardsForUser(t, store)
+	})
+}
+
+// createTestCardsBoard creates a board with a select and a number
+// property and a card for each of the given titles, statuses and
+// estimates, plus a card template that queries must never return.
+func createTestCardsBoard(t *testing.T, store store.Store, titles, statuses, estimates []string) (*model.Board, []*model.Block) {
+	board := &model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   testStatusPropertyID,
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": testStatusTodo, "value": "To do", "color": ""},
+					map[string]interface{}{"id": testStatusDone, "value": "Done", "color": ""},
+				},
+			},
+			{
+				"id":      testEstimatePropertyID,
+				"name":    "Estimate",
+				"type":    "number",
+				"options": []interface{}{},
+			},
+		},
+	}
+	board, err := store.InsertBoard(board, testUserID)
+	require.NoError(t, err)
+
+	cards := make([]*model.Block, 0, len(titles))
+	for i, title := range titles {
+		properties := map[string]interface{}{}
+		if statuses[i] != "" {
+			properties[testStatusPropertyID] = statuses[i]
+		}
+		if estimates[i] != "" {
+			properties[testEstimatePropertyID] = estimates[i]
+		}
+
+		cards = append(cards, &model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    title,
+			Fields:   map[string]interface{}{"properties": properties},
+		})
+	}
+
+	template := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Title:    "template",
+		Fields: map[string]interface{}{
+			"isTemplate": true,
+			"properties": map[string]interface{}{testStatusPropertyID: testStatusTodo},
+		},
+	}
+
+	InsertBlocks(t, store, append(cards, template), testUserID)
+	return board, cards
+}
+
+func cardTitles(blocks []*model.Block) []string {
+	titles := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		titles = append(titles, b.Title)
+	}
+	return titles
+}
+
+func testQueryCardsFilter(t *testing.T, store store.Store) {
+	board, _ := createTestCardsBoard(t, store,
+		[]string{"Alpha", "beta", "Gamma 100%", "delta"},
+		[]string{testStatusTodo, testStatusDone, testStatusTodo, ""},
+		[]string{"1", "2", "", "4"},
+	)
+
+	query := func(filter *model.FilterGroup) []string {
+		cards, cursor, err := store.QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			Filter:      filter,
+			SortOptions: []model.CardSortOption{{PropertyID: model.TitlePropertyID}},
+		})
+		require.NoError(t, err)
+		require.Empty(t, cursor)
+		return cardTitles(cards)
+	}
+
+	clause := func(propertyID string, condition model.FilterCondition, values ...string) model.FilterGroupItem {
+		return model.FilterGroupItem{Clause: &model.FilterClause{PropertyID: propertyID, Condition: condition, Values: values}}
+	}
+
+	t.Run("no filter excludes templates", func(t *testing.T) {
+		require.Equal(t, []string{"Alpha", "beta", "delta", "Gamma 100%"}, query(nil))
+	})
+
+	t.Run("empty group matches everything", func(t *testing.T) {
+		require.Len(t, query(&model.FilterGroup{Operation: model.FilterOperationOr}), 4)
+	})
+
+	t.Run("includes", func(t *testing.T) {
+		require.Equal(t, []string{"Alpha", "Gamma 100%"}, query(&model.FilterGroup{
+			Operation: model.FilterOperationAnd,
+			Filters:   []model.FilterGroupItem{clause(testStatusPropertyID, model.FilterConditionIncludes, testStatusTodo)},
+		}))
+	})
+
+	t.Run("not includes", func(t *testing.T) {
+		require.Equal(t, []string{"beta", "delta"}, query(&model.FilterGroup{
+			Operation: model.FilterOperationAnd,
+			Filters:   []model.FilterGroupItem{clause(testStatusPropertyID, model.FilterConditionNotIncludes, testStatusTodo)},
+		}))
+	})
+
+	t.Run("is empty", func(t *testing.T) {
+		require.Equal(t, []string{"Gamma 100%"}, query(&model.FilterGroup{
+			Operation: model.FilterOperationAnd,
+			Filters:   []model.FilterGroupItem{clause(testEstimatePropertyID, model.FilterConditionIsEmpty)},
+		}))
+	})
+
+	t.Run("title contains is case insensitive and escapes wildcards", func(t *testing.T) {
+		require.Equal(t, []string{"Gamma 100%"}, query(&model.FilterGroup{
+			Operation: model.FilterOperationAnd,
+			Filters:   []model.FilterGroupItem{clause(model.TitleFilterPropertyID, model.FilterConditionContains, "MA 100%")},
+		}))
+	})
+
+	t.Run("nested groups", func(t *testing.T) {
+		require.Equal(t, []string{"Alpha", "delta"}, query(&model.FilterGroup{
+			Operation: model.FilterOperationOr,
+			Filters: []model.FilterGroupItem{
+				clause(model.TitleFilterPropertyID, model.FilterConditionStartsWith, "del"),
+				{Group: &model.FilterGroup{
+					Operation: model.FilterOperationAnd,
+					Filters: []model.FilterGroupItem{
+						clause(testStatusPropertyID, model.FilterConditionIncludes, testStatusTodo),
+						clause(testEstimatePropertyID, model.FilterConditionIsNotEmpty),
+					},
+				}},
+			},
+		}))
+	})
+}
+
+func testQueryCardsSort(t *testing.T, store store.Store) {
+	board, _ := createTestCardsBoard(t, store,
+		[]string{"c", "A", "b", "d"},
+		[]string{testStatusTodo, testStatusDone, "", testStatusTodo},
+		[]string{"10", "9", "", "100"},
+	)
+
+	query := func(sortOptions ...model.CardSortOption) []string {
+		cards, _, err := store.QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			SortOptions: sortOptions,
+		})
+		require.NoError(t, err)
+		return cardTitles(cards)
+	}
+
+	t.Run("title", func(t *testing.T) {
+		require.Equal(t, []string{"A", "b", "c", "d"}, query(model.CardSortOption{PropertyID: model.TitlePropertyID}))
+		require.Equal(t, []string{"d", "c", "b", "A"}, query(model.CardSortOption{PropertyID: model.TitlePropertyID, Reversed: true}))
+	})
+
+	t.Run("numbers sort numerically with empty values last", func(t *testing.T) {
+		require.Equal(t, []string{"A", "c", "d", "b"}, query(model.CardSortOption{PropertyID: testEstimatePropertyID}))
+		require.Equal(t, []string{"d", "c", "A", "b"}, query(model.CardSortOption{PropertyID: testEstimatePropertyID, Reversed: true}))
+	})
+
+	t.Run("select sorts by option value", func(t *testing.T) {
+		require.Equal(t, []string{"A", "c", "d", "b"}, query(
+			model.CardSortOption{PropertyID: testStatusPropertyID},
+			model.CardSortOption{PropertyID: model.TitlePropertyID},
+		))
+	})
+}
+
+func testQueryCardsPagination(t *testing.T, store store.Store) {
+	const cardCount = 7
+
+	titles := make([]string, cardCount)
+	statuses := make([]string, cardCount)
+	estimates := make([]string, cardCount)
+	for i := 0; i < cardCount; i++ {
+		titles[i] = string(rune('a' + i))
+		if i%2 == 0 {
+			statuses[i] = testStatusTodo
+		} else {
+			statuses[i] = testStatusDone
+		}
+	}
+	board, _ := createTestCardsBoard(t, store, titles, statuses, estimates)
+
+	sortOptions := []model.CardSortOption{
+		{PropertyID: testStatusPropertyID, Reversed: true},
+		{PropertyID: model.TitlePropertyID},
+	}
+
+	all, cursor, err := store.QueryCards(model.QueryCardsOptions{
+		BoardID:     board.ID,
+		SortOptions: sortOptions,
+	})
+	require.NoError(t, err)
+	require.Empty(t, cursor)
+	require.Equal(t, []string{"a", "c", "e", "g", "b", "d", "f"}, cardTitles(all))
+
+	t.Run("pages follow the full ordering", func(t *testing.T) {
+		var paged []*model.Block
+		var pages int
+		var cursor *model.CardsCursor
+		for {
+			cards, next, err := store.QueryCards(model.QueryCardsOptions{
+				BoardID:     board.ID,
+				SortOptions: sortOptions,
+				Cursor:      cursor,
+				PerPage:     3,
+			})
+			require.NoError(t, err)
+			paged = append(paged, cards...)
+			pages++
+
+			if next == "" {
+				break
+			}
+			cursor, err = model.DecodeCardsCursor(next)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 3, pages)
+		require.Equal(t, cardTitles(all), cardTitles(paged))
+	})
+
+	t.Run("cursor from a different sort is rejected", func(t *testing.T) {
+		cursor := &model.CardsCursor{Keys: []interface{}{"x"}, ID: all[0].ID}
+		_, _, err := store.QueryCards(model.QueryCardsOptions{
+			BoardID:     board.ID,
+			SortOptions: sortOptions,
+			Cursor:      cursor,
+		})
+		require.Error(t, err)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func testSearchCardsForUser(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	insertBoard := func(boardType model.BoardType, isMember bool) *model.Board {
+		board, err := store.InsertBoard(&model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: testTeamID,
+			Type:   boardType,
+		}, testUserID)
+		require.NoError(t, err)
+
+		if isMember {
+			_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: userID, SchemeViewer: true})
+			require.NoError(t, err)
+		}
+		return board
+	}
+
+	newBlock := func(board *model.Board, parentID string, blockType model.BlockType, title string) *model.Block {
+		if parentID == "" {
+			parentID = board.ID
+		}
+		return &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: parentID,
+			Type:     blockType,
+			Title:    title,
+			Fields:   map[string]interface{}{},
+		}
+	}
+
+	openBoard := insertBoard(model.BoardTypeOpen, false)
+	memberBoard := insertBoard(model.BoardTypePrivate, true)
+	otherBoard := insertBoard(model.BoardTypePrivate, false)
+
+	titleCard := newBlock(openBoard, "", model.TypeCard, "Deploy the release")
+	textCard := newBlock(openBoard, "", model.TypeCard, "Other card")
+	text := newBlock(openBoard, textCard.ID, model.TypeText, "We must deploy on Friday")
+	commentCard := newBlock(memberBoard, "", model.TypeCard, "Nothing to see")
+	comment := newBlock(memberBoard, commentCard.ID, model.TypeComment, "Deployment notes")
+	secretCard := newBlock(otherBoard, "", model.TypeCard, "Deploy the secret")
+	template := newBlock(openBoard, "", model.TypeCard, "Deploy template")
+	template.Fields["isTemplate"] = true
+
+	InsertBlocks(t, store, []*model.Block{titleCard, textCard, text, commentCard, comment, secretCard, template}, testUserID)
+
+	search := func(term string, includePublicBoards bool) map[string]*model.CardSearchMatch {
+		matches, err := store.SearchCardsForUser(model.SearchCardsOptions{
+			TeamID:              testTeamID,
+			UserID:              userID,
+			Words:               model.SearchTermWords(term),
+			IncludePublicBoards: includePublicBoards,
+		})
+		require.NoError(t, err)
+
+		byID := map[string]*model.CardSearchMatch{}
+		for _, m := range matches {
+			byID[m.Card.ID] = m
+		}
+		require.Len(t, byID, len(matches), "cards must not be repeated")
+		return byID
+	}
+
+	t.Run("matches titles and content of visible boards", func(t *testing.T) {
+		matches := search("deploy", true)
+		require.Len(t, matches, 3)
+
+		require.Contains(t, matches, titleCard.ID)
+		require.Equal(t, model.TypeCard, matches[titleCard.ID].MatchType)
+		require.Equal(t, titleCard.Title, matches[titleCard.ID].MatchText)
+
+		require.Contains(t, matches, textCard.ID)
+		require.Equal(t, model.TypeText, matches[textCard.ID].MatchType)
+		require.Equal(t, text.Title, matches[textCard.ID].MatchText)
+
+		require.Contains(t, matches, commentCard.ID)
+		require.Equal(t, model.TypeComment, matches[commentCard.ID].MatchType)
+	})
+
+	t.Run("public boards can be excluded", func(t *testing.T) {
+		matches := search("deploy", false)
+		require.Len(t, matches, 1)
+		require.Contains(t, matches, commentCard.ID)
+	})
+
+	t.Run("all words must match", func(t *testing.T) {
+		matches := search("deploy friday", true)
+		require.Len(t, matches, 1)
+		require.Contains(t, matches, textCard.ID)
+	})
+
+	t.Run("no words", func(t *testing.T) {
+		require.Empty(t, search("  ", true))
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		seen := map[string]bool{}
+		for page := 0; page < 3; page++ {
+			matches, err := store.SearchCardsForUser(model.SearchCardsOptions{
+				TeamID:              testTeamID,
+				UserID:              userID,
+				Words:               []string{"deploy"},
+				IncludePublicBoards: true,
+				Page:                page,
+				PerPage:             1,
+			})
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			seen[matches[0].Card.ID] = true
+		}
+		require.Len(t, seen, 3)
+	})
+}
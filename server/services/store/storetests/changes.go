@@ -0,0 +1,215 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestChangesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetChanges", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetChanges(t, store)
+	})
+	t.Run("GetChangesAccess", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetChangesAccess(t, store)
+	})
+	t.Run("GetChangesPagination", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetChangesPagination(t, store)
+	})
+}
+
+// getAllChanges follows the cursors of the changes feed until its end.
+func getAllChanges(t *testing.T, store store.Store, teamID, userID string, perPage int) []*model.Change {
+	opts := model.QueryChangesOptions{TeamID: teamID, UserID: userID, PerPage: perPage}
+	all := []*model.Change{}
+	for {
+		changes, hasMore, err := store.GetChanges(opts)
+		require.NoError(t, err)
+		all = append(all, changes...)
+		if !hasMore {
+			return all
+		}
+		require.Len(t, changes, perPage)
+		opts.Cursor = changes[len(changes)-1].Cursor()
+	}
+}
+
+func testGetChanges(t *testing.T, store store.Store) {
+	otherUserID := utils.NewID(utils.IDTypeUser)
+
+	board := &model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypeOpen}
+	_, _, err := store.InsertBoardWithAdmin(board, testUserID)
+	require.NoError(t, err)
+
+	hiddenBoard := &model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypeOpen}
+	_, _, err = store.InsertBoardWithAdmin(hiddenBoard, otherUserID)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	card := &model.Block{ID: utils.NewID(utils.IDTypeCard), BoardID: board.ID, Type: model.TypeCard, Title: "card"}
+	require.NoError(t, store.InsertBlock(card, testUserID))
+	hiddenCard := &model.Block{ID: utils.NewID(utils.IDTypeCard), BoardID: hiddenBoard.ID, Type: model.TypeCard}
+	require.NoError(t, store.InsertBlock(hiddenCard, otherUserID))
+
+	category := model.Category{
+		ID:       utils.NewID(utils.IDTypeNone),
+		Name:     "Category",
+		UserID:   testUserID,
+		TeamID:   testTeamID,
+		CreateAt: utils.GetMillis(),
+		UpdateAt: utils.GetMillis(),
+		Type:     model.CategoryTypeCustom,
+	}
+	require.NoError(t, store.CreateCategory(category))
+
+	t.Run("only the changes visible to the user are returned", func(t *testing.T) {
+		changes := getAllChanges(t, store, testTeamID, testUserID, 0)
+
+		types := map[model.ChangeType][]string{}
+		for _, change := range changes {
+			types[change.Type] = append(types[change.Type], change.ID)
+		}
+		require.Equal(t, []string{board.ID}, types[model.ChangeTypeBoard])
+		require.Equal(t, []string{card.ID}, types[model.ChangeTypeBlock])
+		require.Equal(t, []string{testUserID}, types[model.ChangeTypeMember])
+		require.Equal(t, []string{category.ID}, types[model.ChangeTypeCategory])
+
+		for i := 1; i < len(changes); i++ {
+			require.LessOrEqual(t, changes[i-1].UpdateAt, changes[i].UpdateAt)
+		}
+
+		require.Empty(t, getAllChanges(t, store, utils.NewID(utils.IDTypeTeam), testUserID, 0))
+	})
+
+	t.Run("deletions are returned after the cursor", func(t *testing.T) {
+		changes := getAllChanges(t, store, testTeamID, testUserID, 0)
+		cursor := changes[len(changes)-1].Cursor()
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+		require.NoError(t, store.DeleteBoard(board.ID, testUserID))
+
+		newChanges, hasMore, err := store.GetChanges(model.QueryChangesOptions{
+			TeamID: testTeamID,
+			UserID: testUserID,
+			Cursor: cursor,
+		})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+
+		deleted := map[string]bool{}
+		for _, change := range newChanges {
+			require.NotZero(t, change.DeleteAt)
+			deleted[change.ID] = true
+		}
+		require.True(t, deleted[card.ID])
+		require.True(t, deleted[board.ID])
+	})
+}
+
+func testGetChangesAccess(t *testing.T, store store.Store) {
+	otherUserID := utils.NewID(utils.IDTypeUser)
+
+	template := &model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypeOpen, IsTemplate: true}
+	_, _, err := store.InsertBoardWithAdmin(template, otherUserID)
+	require.NoError(t, err)
+
+	board, _, err := store.InsertBoardWithAdmin(&model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypePrivate}, otherUserID)
+	require.NoError(t, err)
+	_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: testUserID, SchemeViewer: true})
+	require.NoError(t, err)
+
+	boardIDs := func(changes []*model.Change) map[string]bool {
+		ids := map[string]bool{}
+		for _, change := range changes {
+			if change.Type == model.ChangeTypeBoard {
+				ids[change.ID] = true
+			}
+		}
+		return ids
+	}
+
+	t.Run("the open templates are visible without a membership", func(t *testing.T) {
+		opts := model.QueryChangesOptions{TeamID: testTeamID, UserID: testUserID}
+		changes, _, err := store.GetChanges(opts)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{board.ID: true}, boardIDs(changes))
+
+		opts.IncludeImplicitBoards = true
+		changes, _, err = store.GetChanges(opts)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{board.ID: true, template.ID: true}, boardIDs(changes))
+	})
+
+	t.Run("the recent changes are left for later", func(t *testing.T) {
+		changes, _, err := store.GetChanges(model.QueryChangesOptions{TeamID: testTeamID, UserID: testUserID, Until: board.UpdateAt})
+		require.NoError(t, err)
+		require.Empty(t, boardIDs(changes))
+	})
+
+	t.Run("the removal of the user's membership is returned", func(t *testing.T) {
+		changes := getAllChanges(t, store, testTeamID, testUserID, 0)
+		cursor := changes[len(changes)-1].Cursor()
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, store.DeleteMember(board.ID, testUserID))
+
+		newChanges, _, err := store.GetChanges(model.QueryChangesOptions{
+			TeamID: testTeamID,
+			UserID: testUserID,
+			Cursor: cursor,
+		})
+		require.NoError(t, err)
+		require.Len(t, newChanges, 1)
+		require.Equal(t, model.ChangeTypeMember, newChanges[0].Type)
+		require.Equal(t, testUserID, newChanges[0].ID)
+		require.Equal(t, board.ID, newChanges[0].BoardID)
+		require.NotZero(t, newChanges[0].DeleteAt)
+	})
+}
+
+func testGetChangesPagination(t *testing.T, store store.Store) {
+	board := &model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypeOpen}
+	_, _, err := store.InsertBoardWithAdmin(board, testUserID)
+	require.NoError(t, err)
+
+	// the blocks are inserted together, so many share their update time
+	blocks := []*model.Block{}
+	for i := 0; i < 10; i++ {
+		blocks = append(blocks, &model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			Type:     model.TypeCard,
+			CreateAt: 1000,
+			UpdateAt: 1000,
+		})
+	}
+	require.NoError(t, store.InsertBlocks(blocks, testUserID))
+
+	for i := 0; i < 3; i++ {
+		_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: utils.NewID(utils.IDTypeUser), SchemeViewer: true})
+		require.NoError(t, err)
+	}
+
+	all := getAllChanges(t, store, testTeamID, testUserID, 0)
+	require.Len(t, all, 1+10+4)
+
+	for _, perPage := range []int{1, 3, 4} {
+		paginated := getAllChanges(t, store, testTeamID, testUserID, perPage)
+		require.Equal(t, all, paginated, "perPage=%d", perPage)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestDigestSettingsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("DigestSettings", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDigestSettings(t, store)
+	})
+	t.Run("MarkDigestSent", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMarkDigestSent(t, store)
+	})
+}
+
+func testDigestSettings(t *testing.T, store store.Store) {
+	t.Run("get missing settings", func(t *testing.T) {
+		_, err := store.GetDigestSettings("user-id-missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		settings := model.NewDigestSettings("user-id-1")
+		settings.Hour = 24
+		require.True(t, model.IsErrBadRequest(store.UpsertDigestSettings(settings)))
+	})
+
+	t.Run("upsert and get", func(t *testing.T) {
+		settings := model.NewDigestSettings("user-id-1")
+		settings.LastSentAt = 1000
+		require.NoError(t, store.UpsertDigestSettings(settings))
+
+		fetched, err := store.GetDigestSettings("user-id-1")
+		require.NoError(t, err)
+		require.Equal(t, model.DigestFrequencyDaily, fetched.Frequency)
+		require.Equal(t, model.DefaultDigestHour, fetched.Hour)
+		require.Equal(t, int64(1000), fetched.LastSentAt)
+		require.NotZero(t, fetched.UpdateAt)
+
+		// the last sent time is kept on update
+		settings.Frequency = model.DigestFrequencyWeekly
+		settings.Weekday = 5
+		settings.LastSentAt = 2000
+		require.NoError(t, store.UpsertDigestSettings(settings))
+
+		updated, err := store.GetDigestSettings("user-id-1")
+		require.NoError(t, err)
+		require.Equal(t, model.DigestFrequencyWeekly, updated.Frequency)
+		require.Equal(t, 5, updated.Weekday)
+		require.Equal(t, int64(1000), updated.LastSentAt)
+	})
+
+	t.Run("get settings list", func(t *testing.T) {
+		require.NoError(t, store.UpsertDigestSettings(model.NewDigestSettings("user-id-2")))
+
+		settingsList, err := store.GetDigestSettingsList()
+		require.NoError(t, err)
+		require.Len(t, settingsList, 2)
+		require.Equal(t, "user-id-1", settingsList[0].UserID)
+		require.Equal(t, "user-id-2", settingsList[1].UserID)
+	})
+}
+
+func testMarkDigestSent(t *testing.T, store store.Store) {
+	settings := model.NewDigestSettings("user-id-1")
+	settings.LastSentAt = 1000
+	require.NoError(t, store.UpsertDigestSettings(settings))
+
+	marked, err := store.MarkDigestSent("user-id-1", 1000, 2000)
+	require.NoError(t, err)
+	require.True(t, marked)
+
+	// the digest was already sent by someone else
+	marked, err = store.MarkDigestSent("user-id-1", 1000, 3000)
+	require.NoError(t, err)
+	require.False(t, marked)
+
+	fetched, err := store.GetDigestSettings("user-id-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2000), fetched.LastSentAt)
+
+	marked, err = store.MarkDigestSent("user-id-missing", 0, 2000)
+	require.NoError(t, err)
+	require.False(t, marked)
+}
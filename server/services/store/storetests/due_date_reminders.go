@@ -0,0 +1,107 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestDueDateRemindersStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("DueDateReminderSettings", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueDateReminderSettings(t, store)
+	})
+	t.Run("DueDateRemindersSent", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueDateRemindersSent(t, store)
+	})
+}
+
+func testDueDateReminderSettings(t *testing.T, store store.Store) {
+	t.Run("get missing settings", func(t *testing.T) {
+		_, err := store.GetDueDateReminderSettings("board-id-missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("upsert and get", func(t *testing.T) {
+		settings := &model.DueDateReminderSettings{
+			BoardID:     "board-id-1",
+			Enabled:     true,
+			PropertyIDs: []string{"due-id"},
+			Offsets:     []int64{-1440, 0},
+			ModifiedBy:  testUserID,
+		}
+		require.NoError(t, store.UpsertDueDateReminderSettings(settings))
+
+		fetched, err := store.GetDueDateReminderSettings("board-id-1")
+		require.NoError(t, err)
+		require.True(t, fetched.Enabled)
+		require.Equal(t, []string{"due-id"}, fetched.PropertyIDs)
+		require.Equal(t, []int64{-1440, 0}, fetched.Offsets)
+		require.NotZero(t, fetched.UpdateAt)
+
+		settings.Offsets = []int64{60}
+		require.NoError(t, store.UpsertDueDateReminderSettings(settings))
+
+		updated, err := store.GetDueDateReminderSettings("board-id-1")
+		require.NoError(t, err)
+		require.Equal(t, []int64{60}, updated.Offsets)
+	})
+
+	t.Run("get enabled settings", func(t *testing.T) {
+		require.NoError(t, store.UpsertDueDateReminderSettings(&model.DueDateReminderSettings{
+			BoardID:    "board-id-2",
+			ModifiedBy: testUserID,
+		}))
+
+		enabled, err := store.GetEnabledDueDateReminderSettings()
+		require.NoError(t, err)
+		require.Len(t, enabled, 1)
+		require.Equal(t, "board-id-1", enabled[0].BoardID)
+	})
+}
+
+func testDueDateRemindersSent(t *testing.T, store store.Store) {
+	reminder := &model.DueDateReminder{
+		CardID:     "card-id",
+		PropertyID: "due-id",
+		Offset:     -1440,
+		DueAt:      1000,
+		SentAt:     100,
+	}
+
+	t.Run("mark once", func(t *testing.T) {
+		marked, err := store.MarkDueDateReminderSent(reminder)
+		require.NoError(t, err)
+		require.True(t, marked)
+
+		marked, err = store.MarkDueDateReminderSent(reminder)
+		require.NoError(t, err)
+		require.False(t, marked)
+	})
+
+	t.Run("new due date", func(t *testing.T) {
+		moved := *reminder
+		moved.DueAt = 2000
+		marked, err := store.MarkDueDateReminderSent(&moved)
+		require.NoError(t, err)
+		require.True(t, marked)
+	})
+
+	t.Run("delete old reminders", func(t *testing.T) {
+		count, err := store.DeleteDueDateRemindersSentBefore(200)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		marked, err := store.MarkDueDateReminderSent(reminder)
+		require.NoError(t, err)
+		require.True(t, marked)
+	})
+}
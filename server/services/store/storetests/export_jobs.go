@@ -0,0 +1,138 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestExportJobsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("ExportJobs", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testExportJobs(t, store)
+	})
+	t.Run("BoardIDsChangedSince", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBoardIDsChangedSince(t, store)
+	})
+}
+
+func newTestExportJob(id, teamID string, status model.ExportJobStatus, createAt int64) *model.ExportJob {
+	return &model.ExportJob{
+		ID:        id,
+		TeamID:    teamID,
+		Status:    status,
+		CreatedBy: testUserID,
+		CreateAt:  createAt,
+		UpdateAt:  createAt,
+	}
+}
+
+func testExportJobs(t *testing.T, store store.Store) {
+	t.Run("insert, get and update", func(t *testing.T) {
+		job := newTestExportJob("job-id-1", testTeamID, model.ExportJobPending, 1000)
+		job.ChangedSince = 500
+		require.NoError(t, store.InsertExportJob(job))
+
+		fetched, err := store.GetExportJob("job-id-1")
+		require.NoError(t, err)
+		require.Equal(t, model.ExportJobPending, fetched.Status)
+		require.Equal(t, int64(500), fetched.ChangedSince)
+		require.Empty(t, fetched.BoardIDs)
+
+		job.Status = model.ExportJobRunning
+		job.BoardIDs = []string{"board-id-1", "board-id-2"}
+		job.BoardsTotal = 2
+		job.BoardsDone = 1
+		job.Chunks = 1
+		job.UpdateAt = 2000
+		require.NoError(t, store.UpdateExportJob(job))
+
+		updated, err := store.GetExportJob("job-id-1")
+		require.NoError(t, err)
+		require.Equal(t, model.ExportJobRunning, updated.Status)
+		require.Equal(t, []string{"board-id-1", "board-id-2"}, updated.BoardIDs)
+		require.Equal(t, 1, updated.BoardsDone)
+		require.Equal(t, 1, updated.Chunks)
+		require.Equal(t, int64(2000), updated.UpdateAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := store.GetExportJob("missing")
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.UpdateExportJob(newTestExportJob("missing", testTeamID, model.ExportJobFailed, 1000))
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("jobs for team and active jobs", func(t *testing.T) {
+		require.NoError(t, store.InsertExportJob(newTestExportJob("job-id-2", testTeamID, model.ExportJobSuccess, 3000)))
+		require.NoError(t, store.InsertExportJob(newTestExportJob("job-id-3", testTeamID, model.ExportJobPending, 4000)))
+		require.NoError(t, store.InsertExportJob(newTestExportJob("job-id-4", "other-team", model.ExportJobPending, 500)))
+
+		jobs, err := store.GetExportJobsForTeam(testTeamID, 10)
+		require.NoError(t, err)
+		require.Len(t, jobs, 3)
+		require.Equal(t, "job-id-3", jobs[0].ID)
+		require.Equal(t, "job-id-1", jobs[2].ID)
+
+		jobs, err = store.GetExportJobsForTeam(testTeamID, 1)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+
+		active, err := store.GetActiveExportJobs(10)
+		require.NoError(t, err)
+		require.Len(t, active, 3)
+		require.Equal(t, "job-id-4", active[0].ID)
+		require.Equal(t, "job-id-1", active[1].ID)
+		require.Equal(t, "job-id-3", active[2].ID)
+	})
+}
+
+func testBoardIDsChangedSince(t *testing.T, store store.Store) {
+	for _, boardID := range []string{"board-id-1", "board-id-2"} {
+		_, err := store.InsertBoard(&model.Board{ID: boardID, TeamID: testTeamID, Type: model.BoardTypeOpen}, testUserID)
+		require.NoError(t, err)
+	}
+	_, err := store.InsertBoard(&model.Board{ID: "board-id-3", TeamID: "other-team", Type: model.BoardTypeOpen}, testUserID)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	since := utils.GetMillis()
+	time.Sleep(10 * time.Millisecond)
+
+	t.Run("all boards of the team", func(t *testing.T) {
+		boardIDs, err := store.GetBoardIDsChangedSince(testTeamID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"board-id-1", "board-id-2"}, boardIDs)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		boardIDs, err := store.GetBoardIDsChangedSince(testTeamID, since)
+		require.NoError(t, err)
+		require.Empty(t, boardIDs)
+	})
+
+	t.Run("boards with changed content", func(t *testing.T) {
+		block := &model.Block{
+			ID:         utils.NewID(utils.IDTypeBlock),
+			BoardID:    "board-id-2",
+			Type:       model.TypeCard,
+			ModifiedBy: testUserID,
+		}
+		require.NoError(t, store.InsertBlock(block, testUserID))
+
+		boardIDs, err := store.GetBoardIDsChangedSince(testTeamID, since)
+		require.NoError(t, err)
+		require.Equal(t, []string{"board-id-2"}, boardIDs)
+	})
+}
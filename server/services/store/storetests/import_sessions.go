@@ -0,0 +1,98 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestImportSessionsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("ImportSessions", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testImportSessions(t, store)
+	})
+}
+
+func newTestImportSession(id string, updateAt int64) *model.ImportSession {
+	return &model.ImportSession{
+		ID:     id,
+		TeamID: testTeamID,
+		Status: model.ImportSessionPending,
+		Boards: []*model.ImportSessionBoard{
+			{ArchiveID: "board-id-1", Title: "Board 1", Cards: 2, Blocks: 3},
+		},
+		Users:           []string{testUserID, "user-id-2"},
+		UnresolvedUsers: []string{"user-id-2"},
+		CreatedBy:       testUserID,
+		CreateAt:        updateAt,
+		UpdateAt:        updateAt,
+	}
+}
+
+func testImportSessions(t *testing.T, store store.Store) {
+	t.Run("insert, get and update", func(t *testing.T) {
+		session := newTestImportSession("session-id-1", 1000)
+		require.NoError(t, store.InsertImportSession(session))
+
+		fetched, err := store.GetImportSession("session-id-1")
+		require.NoError(t, err)
+		require.Equal(t, session, fetched)
+
+		session.Status = model.ImportSessionCompleted
+		session.Boards[0].Action = model.ImportBoardCreate
+		session.Boards[0].BoardID = "new-board-id"
+		session.UpdateAt = 2000
+		require.NoError(t, store.UpdateImportSession(session))
+
+		updated, err := store.GetImportSession("session-id-1")
+		require.NoError(t, err)
+		require.Equal(t, session, updated)
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		require.NoError(t, store.InsertImportSession(newTestImportSession("session-id-2", 1000)))
+
+		updated, err := store.UpdateImportSessionStatus("session-id-2", model.ImportSessionPending, model.ImportSessionImporting)
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		// the session is not pending anymore
+		updated, err = store.UpdateImportSessionStatus("session-id-2", model.ImportSessionPending, model.ImportSessionImporting)
+		require.NoError(t, err)
+		require.False(t, updated)
+
+		session, err := store.GetImportSession("session-id-2")
+		require.NoError(t, err)
+		require.Equal(t, model.ImportSessionImporting, session.Status)
+	})
+
+	t.Run("expired sessions and delete", func(t *testing.T) {
+		require.NoError(t, store.InsertImportSession(newTestImportSession("session-id-3", 100)))
+
+		sessions, err := store.GetImportSessionsUpdatedBefore(1500, 10)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		require.Equal(t, "session-id-3", sessions[0].ID)
+
+		require.NoError(t, store.DeleteImportSession("session-id-3"))
+		_, err = store.GetImportSession("session-id-3")
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.DeleteImportSession("session-id-3")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := store.GetImportSession("missing")
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.UpdateImportSession(newTestImportSession("missing", 1000))
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestIncomingWebhooksStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("UpsertIncomingWebhookAndGetIncomingWebhook", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpsertIncomingWebhookAndGetIncomingWebhook(t, store)
+	})
+}
+
+func testUpsertIncomingWebhookAndGetIncomingWebhook(t *testing.T, store store.Store) {
+	t.Run("Insert incoming webhook and get it", func(t *testing.T) {
+		webhook := model.IncomingWebhook{
+			ID:         "board-id",
+			Enabled:    true,
+			Token:      "token",
+			ModifiedBy: testUserID,
+		}
+
+		err := store.UpsertIncomingWebhook(webhook)
+		require.NoError(t, err)
+		newWebhook, err := store.GetIncomingWebhook("board-id")
+		require.NoError(t, err)
+		require.NotZero(t, newWebhook.UpdateAt)
+		newWebhook.UpdateAt = 0
+		require.Equal(t, webhook, *newWebhook)
+	})
+
+	t.Run("Upsert the inserted incoming webhook and get it", func(t *testing.T) {
+		webhook := model.IncomingWebhook{
+			ID:         "board-id",
+			Enabled:    false,
+			Token:      "token2",
+			ModifiedBy: "user-id2",
+		}
+
+		err := store.UpsertIncomingWebhook(webhook)
+		require.NoError(t, err)
+		newWebhook, err := store.GetIncomingWebhook("board-id")
+		require.NoError(t, err)
+		newWebhook.UpdateAt = 0
+		require.Equal(t, webhook, *newWebhook)
+	})
+
+	t.Run("Get not existing incoming webhook", func(t *testing.T) {
+		_, err := store.GetIncomingWebhook("not-existing")
+		require.Error(t, err)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
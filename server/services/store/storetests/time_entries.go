@@ -0,0 +1,299 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestTimeEntriesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("TimeEntries", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTimeEntries(t, store)
+	})
+	t.Run("StartTimeEntry", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testStartTimeEntry(t, store)
+	})
+	t.Run("StartTimeEntryConcurrently", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testStartTimeEntryConcurrently(t, store)
+	})
+	t.Run("TimeSpent", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTimeSpent(t, store)
+	})
+	t.Run("TimeEntriesForCompliance", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTimeEntriesForCompliance(t, store)
+	})
+}
+
+func newTestTimeEntry(boardID, cardID, userID string, startAt, duration int64) *model.TimeEntry {
+	return &model.TimeEntry{
+		ID:       utils.NewID(utils.IDTypeNone),
+		BoardID:  boardID,
+		CardID:   cardID,
+		UserID:   userID,
+		StartAt:  startAt,
+		Duration: duration,
+	}
+}
+
+func testTimeEntries(t *testing.T, store store.Store) {
+	t.Run("insert, get and update", func(t *testing.T) {
+		entry := newTestTimeEntry("board-id-1", "card-id-1", testUserID, 1000, 60000)
+		entry.Note = "design review"
+		require.NoError(t, store.InsertTimeEntry(entry))
+		require.NotZero(t, entry.CreateAt)
+
+		fetched, err := store.GetTimeEntry(entry.ID)
+		require.NoError(t, err)
+		require.Equal(t, entry, fetched)
+
+		fetched.Duration = 120000
+		fetched.Note = ""
+		fetched.UpdateAt = entry.UpdateAt + 1
+		require.NoError(t, store.UpdateTimeEntry(fetched))
+
+		updated, err := store.GetTimeEntry(entry.ID)
+		require.NoError(t, err)
+		require.Equal(t, fetched, updated)
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		entry := newTestTimeEntry("board-id-1", "card-id-1", testUserID, 1000, -1)
+		require.True(t, model.IsErrBadRequest(store.InsertTimeEntry(entry)))
+	})
+
+	t.Run("get for card and board", func(t *testing.T) {
+		first := newTestTimeEntry("board-id-2", "card-id-2", testUserID, 1000, 100)
+		second := newTestTimeEntry("board-id-2", "card-id-2", "user-id-2", 2000, 200)
+		other := newTestTimeEntry("board-id-2", "card-id-3", testUserID, 3000, 300)
+		require.NoError(t, store.InsertTimeEntry(first))
+		require.NoError(t, store.InsertTimeEntry(second))
+		require.NoError(t, store.InsertTimeEntry(other))
+
+		entries, err := store.GetTimeEntriesForCard("card-id-2")
+		require.NoError(t, err)
+		require.Equal(t, []*model.TimeEntry{second, first}, entries)
+
+		entries, err = store.GetTimeEntriesForBoard("board-id-2")
+		require.NoError(t, err)
+		require.Equal(t, []*model.TimeEntry{first, second, other}, entries)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		entry := newTestTimeEntry("board-id-3", "card-id-4", testUserID, 1000, 100)
+		require.NoError(t, store.InsertTimeEntry(entry))
+
+		require.NoError(t, store.DeleteTimeEntry(entry.ID))
+
+		_, err := store.GetTimeEntry(entry.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		entries, err := store.GetTimeEntriesForCard("card-id-4")
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		require.True(t, model.IsErrNotFound(store.DeleteTimeEntry(entry.ID)))
+		require.True(t, model.IsErrNotFound(store.UpdateTimeEntry(entry)))
+	})
+}
+
+func testStartTimeEntry(t *testing.T, store store.Store) {
+	_, err := store.GetRunningTimeEntry(testUserID)
+	require.True(t, model.IsErrNotFound(err))
+
+	first := newTestTimeEntry("board-id-1", "card-id-1", testUserID, 1000, 0)
+	first.Running = true
+	stopped, err := store.StartTimeEntry(first)
+	require.NoError(t, err)
+	require.Empty(t, stopped)
+
+	running, err := store.GetRunningTimeEntry(testUserID)
+	require.NoError(t, err)
+	require.Equal(t, first.ID, running.ID)
+
+	// the timers of other users are not stopped
+	other := newTestTimeEntry("board-id-1", "card-id-1", "user-id-2", 1500, 0)
+	other.Running = true
+	stopped, err = store.StartTimeEntry(other)
+	require.NoError(t, err)
+	require.Empty(t, stopped)
+
+	second := newTestTimeEntry("board-id-1", "card-id-2", testUserID, 61000, 0)
+	second.Running = true
+	stopped, err = store.StartTimeEntry(second)
+	require.NoError(t, err)
+	require.Len(t, stopped, 1)
+	require.Equal(t, first.ID, stopped[0].ID)
+	require.False(t, stopped[0].Running)
+	require.Equal(t, int64(60000), stopped[0].Duration)
+
+	running, err = store.GetRunningTimeEntry(testUserID)
+	require.NoError(t, err)
+	require.Equal(t, second.ID, running.ID)
+
+	fetched, err := store.GetTimeEntry(first.ID)
+	require.NoError(t, err)
+	require.False(t, fetched.Running)
+	require.Equal(t, int64(60000), fetched.Duration)
+
+	running, err = store.GetRunningTimeEntry("user-id-2")
+	require.NoError(t, err)
+	require.Equal(t, other.ID, running.ID)
+}
+
+func testStartTimeEntryConcurrently(t *testing.T, store store.Store) {
+	const timers = 10
+
+	errs := make([]error, timers)
+	var wg sync.WaitGroup
+	for i := 0; i < timers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry := newTestTimeEntry("board-id-1", fmt.Sprintf("card-id-%d", i), testUserID, int64(1000+i), 0)
+			entry.Running = true
+			_, errs[i] = store.StartTimeEntry(entry)
+		}(i)
+	}
+	wg.Wait()
+
+	// the timers that weren't started conflict with the ones that were
+	started := 0
+	for _, err := range errs {
+		if err == nil {
+			started++
+			continue
+		}
+		require.True(t, model.IsErrConflict(err), err)
+	}
+	require.NotZero(t, started)
+
+	entries, err := store.GetTimeEntriesForBoard("board-id-1")
+	require.NoError(t, err)
+	require.Len(t, entries, started)
+
+	running := 0
+	for _, entry := range entries {
+		if entry.Running {
+			running++
+		}
+	}
+	require.Equal(t, 1, running)
+}
+
+func testTimeSpent(t *testing.T, store store.Store) {
+	entries := []*model.TimeEntry{
+		newTestTimeEntry("board-id-1", "card-id-1", testUserID, 1000, 100),
+		newTestTimeEntry("board-id-1", "card-id-1", testUserID, 2000, 200),
+		newTestTimeEntry("board-id-1", "card-id-1", "user-id-2", 3000, 400),
+		newTestTimeEntry("board-id-1", "card-id-2", testUserID, 9000, 800),
+		newTestTimeEntry("board-id-2", "card-id-3", testUserID, 4000, 1600),
+	}
+	for _, entry := range entries {
+		require.NoError(t, store.InsertTimeEntry(entry))
+	}
+
+	deleted := newTestTimeEntry("board-id-1", "card-id-1", testUserID, 5000, 3200)
+	require.NoError(t, store.InsertTimeEntry(deleted))
+	require.NoError(t, store.DeleteTimeEntry(deleted.ID))
+
+	running := newTestTimeEntry("board-id-1", "card-id-1", testUserID, 6000, 0)
+	running.Running = true
+	require.NoError(t, store.InsertTimeEntry(running))
+
+	t.Run("for cards", func(t *testing.T) {
+		timeSpent, err := store.GetTimeSpentForCards([]string{"card-id-1", "card-id-3", "card-id-4"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"card-id-1": 700, "card-id-3": 1600}, timeSpent)
+
+		timeSpent, err = store.GetTimeSpentForCards(nil)
+		require.NoError(t, err)
+		require.Empty(t, timeSpent)
+	})
+
+	t.Run("for a board", func(t *testing.T) {
+		spent, err := store.GetTimeSpent(model.QueryTimeSummaryOptions{BoardID: "board-id-1", From: 1000, To: 9000})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*model.TimeSpent{
+			{BoardID: "board-id-1", CardID: "card-id-1", UserID: testUserID, Duration: 300, Count: 2},
+			{BoardID: "board-id-1", CardID: "card-id-1", UserID: "user-id-2", Duration: 400, Count: 1},
+		}, spent)
+	})
+
+	t.Run("for a user", func(t *testing.T) {
+		spent, err := store.GetTimeSpent(model.QueryTimeSummaryOptions{UserID: testUserID, From: 1500, To: 10000})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*model.TimeSpent{
+			{BoardID: "board-id-1", CardID: "card-id-1", UserID: testUserID, Duration: 200, Count: 1},
+			{BoardID: "board-id-1", CardID: "card-id-2", UserID: testUserID, Duration: 800, Count: 1},
+			{BoardID: "board-id-2", CardID: "card-id-3", UserID: testUserID, Duration: 1600, Count: 1},
+		}, spent)
+	})
+}
+
+func testTimeEntriesForCompliance(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 2)
+	otherBoards := createTestBoards(t, store, "other-team-id", testUserID, 1)
+
+	first := newTestTimeEntry(boards[0].ID, "card-id-1", testUserID, 1000, 100)
+	first.UpdateAt = 1000
+	second := newTestTimeEntry(boards[1].ID, "card-id-2", testUserID, 2000, 200)
+	second.UpdateAt = 2000
+	other := newTestTimeEntry(otherBoards[0].ID, "card-id-3", testUserID, 3000, 300)
+	other.UpdateAt = 3000
+	deleted := newTestTimeEntry(boards[0].ID, "card-id-1", testUserID, 4000, 400)
+	for _, entry := range []*model.TimeEntry{first, second, other, deleted} {
+		require.NoError(t, store.InsertTimeEntry(entry))
+	}
+	require.NoError(t, store.DeleteTimeEntry(deleted.ID))
+
+	t.Run("for a team", func(t *testing.T) {
+		entries, hasMore, err := store.GetTimeEntriesForCompliance(model.QueryTimeEntriesComplianceOptions{TeamID: testTeamID})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Equal(t, []*model.TimeEntry{first, second}, entries)
+	})
+
+	t.Run("including deleted", func(t *testing.T) {
+		entries, _, err := store.GetTimeEntriesForCompliance(model.QueryTimeEntriesComplianceOptions{
+			TeamID:         testTeamID,
+			BoardID:        boards[0].ID,
+			IncludeDeleted: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, deleted.ID, entries[1].ID)
+		require.NotZero(t, entries[1].DeleteAt)
+	})
+
+	t.Run("modified since, paginated", func(t *testing.T) {
+		opts := model.QueryTimeEntriesComplianceOptions{ModifiedSince: 1000, PerPage: 1}
+		entries, hasMore, err := store.GetTimeEntriesForCompliance(opts)
+		require.NoError(t, err)
+		require.True(t, hasMore)
+		require.Equal(t, []*model.TimeEntry{second}, entries)
+
+		opts.Page = 1
+		entries, hasMore, err = store.GetTimeEntriesForCompliance(opts)
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Equal(t, []*model.TimeEntry{other}, entries)
+	})
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestWebhooksStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("BoardWebhooks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBoardWebhooks(t, store)
+	})
+	t.Run("WebhookDeliveries", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testWebhookDeliveries(t, store)
+	})
+}
+
+func createTestBoardWebhook(t *testing.T, store store.Store, boardID string) *model.BoardWebhook {
+	webhook, err := store.InsertBoardWebhook(&model.BoardWebhook{
+		ID:         utils.NewID(utils.IDTypeNone),
+		BoardID:    boardID,
+		URL:        "https://example.com/hook",
+		Secret:     "secret",
+		Events:     []model.WebhookEvent{model.WebhookEventCardCreated, model.WebhookEventCommentAdded},
+		Enabled:    true,
+		CreatedBy:  testUserID,
+		ModifiedBy: testUserID,
+	})
+	require.NoError(t, err)
+	return webhook
+}
+
+func testBoardWebhooks(t *testing.T, store store.Store) {
+	t.Run("insert and get", func(t *testing.T) {
+		webhook := createTestBoardWebhook(t, store, "board-id-1")
+		require.NotZero(t, webhook.CreateAt)
+
+		fetched, err := store.GetBoardWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, webhook, fetched)
+
+		webhooks, err := store.GetBoardWebhooksForBoard("board-id-1")
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, webhook, webhooks[0])
+
+		webhooks, err = store.GetBoardWebhooksForBoard("other-board")
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+
+	t.Run("invalid webhook", func(t *testing.T) {
+		_, err := store.InsertBoardWebhook(&model.BoardWebhook{
+			ID:      utils.NewID(utils.IDTypeNone),
+			BoardID: "board-id-1",
+			URL:     "ftp://example.com",
+			Events:  []model.WebhookEvent{model.WebhookEventCardCreated},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		webhook := createTestBoardWebhook(t, store, "board-id-2")
+
+		enabled := false
+		newURL := "https://example.com/other"
+		patched, err := store.PatchBoardWebhook(webhook.ID, &model.BoardWebhookPatch{
+			URL:     &newURL,
+			Events:  []model.WebhookEvent{model.WebhookEventMemberAdded},
+			Enabled: &enabled,
+		}, "user-id-2")
+		require.NoError(t, err)
+		require.Equal(t, newURL, patched.URL)
+		require.Equal(t, "secret", patched.Secret)
+		require.False(t, patched.Enabled)
+		require.Equal(t, "user-id-2", patched.ModifiedBy)
+
+		fetched, err := store.GetBoardWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, patched, fetched)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		webhook := createTestBoardWebhook(t, store, "board-id-3")
+		now := utils.GetMillis()
+		require.NoError(t, store.InsertWebhookDelivery(&model.WebhookDelivery{
+			ID:        utils.NewID(utils.IDTypeNone),
+			WebhookID: webhook.ID,
+			BoardID:   webhook.BoardID,
+			Event:     model.WebhookEventCardCreated,
+			Payload:   "{}",
+			Status:    model.WebhookDeliveryPending,
+			CreateAt:  now,
+			UpdateAt:  now,
+		}))
+
+		require.NoError(t, store.DeleteBoardWebhook(webhook.ID))
+
+		_, err := store.GetBoardWebhook(webhook.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		deliveries, err := store.GetWebhookDeliveries(webhook.ID, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+
+		err = store.DeleteBoardWebhook(webhook.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("deleted with the board", func(t *testing.T) {
+		board, err := store.InsertBoard(&model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+		}, testUserID)
+		require.NoError(t, err)
+
+		webhook := createTestBoardWebhook(t, store, board.ID)
+		other := createTestBoardWebhook(t, store, "board-id-4")
+		now := utils.GetMillis()
+		require.NoError(t, store.InsertWebhookDelivery(&model.WebhookDelivery{
+			ID:        utils.NewID(utils.IDTypeNone),
+			WebhookID: webhook.ID,
+			BoardID:   webhook.BoardID,
+			Event:     model.WebhookEventCardCreated,
+			Payload:   "{}",
+			Status:    model.WebhookDeliveryPending,
+			CreateAt:  now,
+			UpdateAt:  now,
+		}))
+
+		// wait to avoid hitting pk uniqueness constraint in history
+		time.Sleep(10 * time.Millisecond)
+
+		require.NoError(t, store.DeleteBoard(board.ID, testUserID))
+
+		webhooks, err := store.GetBoardWebhooksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+
+		deliveries, err := store.GetWebhookDeliveries(webhook.ID, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+
+		_, err = store.GetBoardWebhook(other.ID)
+		require.NoError(t, err)
+	})
+}
+
+func testWebhookDeliveries(t *testing.T, store store.Store) {
+	webhook := createTestBoardWebhook(t, store, "board-id-1")
+
+	newDelivery := func(nextAttemptAt, createAt int64) *model.WebhookDelivery {
+		delivery := &model.WebhookDelivery{
+			ID:            utils.NewID(utils.IDTypeNone),
+			WebhookID:     webhook.ID,
+			BoardID:       webhook.BoardID,
+			Event:         model.WebhookEventCardCreated,
+			Payload:       `{"event":"card_created"}`,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: nextAttemptAt,
+			CreateAt:      createAt,
+			UpdateAt:      createAt,
+		}
+		require.NoError(t, store.InsertWebhookDelivery(delivery))
+		return delivery
+	}
+
+	due := newDelivery(1000, 100)
+	later := newDelivery(5000, 200)
+
+	t.Run("pending deliveries", func(t *testing.T) {
+		pending, err := store.GetPendingWebhookDeliveries(2000, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, due, pending[0])
+
+		pending, err = store.GetPendingWebhookDeliveries(6000, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		pending, err = store.GetPendingWebhookDeliveries(6000, 1)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		claimed, err := store.ClaimWebhookDelivery(due.ID, due.NextAttemptAt, 3000)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// a second claim with the stale next attempt time fails
+		claimed, err = store.ClaimWebhookDelivery(due.ID, due.NextAttemptAt, 3000)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		pending, err := store.GetPendingWebhookDeliveries(2000, 10)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		due.Status = model.WebhookDeliverySuccess
+		due.Attempts = 1
+		due.NextAttemptAt = 0
+		due.LastAttemptAt = 2500
+		due.ResponseCode = 200
+		due.UpdateAt = 2500
+		require.NoError(t, store.UpdateWebhookDelivery(due))
+
+		pending, err := store.GetPendingWebhookDeliveries(10000, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, later.ID, pending[0].ID)
+	})
+
+	t.Run("delivery log", func(t *testing.T) {
+		deliveries, err := store.GetWebhookDeliveries(webhook.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		require.Equal(t, later.ID, deliveries[0].ID)
+		require.Equal(t, due, deliveries[1])
+
+		deliveries, err = store.GetWebhookDeliveries(webhook.ID, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, due.ID, deliveries[0].ID)
+	})
+
+	t.Run("finished deliveries are purged", func(t *testing.T) {
+		deleted, err := store.DeleteWebhookDeliveriesFinishedBefore(2500)
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+
+		// pending deliveries are kept however old they are
+		deleted, err = store.DeleteWebhookDeliveriesFinishedBefore(10000)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		deliveries, err := store.GetWebhookDeliveries(webhook.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, later.ID, deliveries[0].ID)
+	})
+}
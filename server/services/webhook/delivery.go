@@ -0,0 +1,282 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	deliveryBatchSize    = 50
+	deliveryPollInterval = time.Minute
+	deliveryTimeout      = time.Second * 10
+	// deliveryLease is how long a claimed delivery is reserved for the
+	// server attempting it. It must be longer than deliveryTimeout.
+	deliveryLease       = time.Minute * 2
+	maxDeliveryAttempts = 8
+	retryBaseDelay      = time.Second * 30
+	maxResponseBodySize = 64 * 1024
+	maxErrorLength      = 1024
+
+	// finished deliveries are kept in the delivery log for a while, and
+	// purged every deliveryPurgeInterval.
+	deliveryRetention     = time.Hour * 48
+	deliveryPurgeInterval = time.Hour
+)
+
+// Sign returns the value of the signature header for a body posted to a
+// webhook with the given secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the time to wait before the next attempt of a
+// delivery that failed `attempts` times.
+func retryDelay(attempts int) time.Duration {
+	return retryBaseDelay << (attempts - 1)
+}
+
+// Start starts delivering the queued events.
+func (wh *Client) Start() error {
+	wh.mux.Lock()
+	defer wh.mux.Unlock()
+
+	if wh.done == nil {
+		wh.done = make(chan struct{})
+		go wh.safeLoop(wh.done)
+	}
+	return nil
+}
+
+// ShutDown stops delivering events. The pending deliveries stay queued.
+func (wh *Client) ShutDown() error {
+	wh.mux.Lock()
+	defer wh.mux.Unlock()
+
+	if wh.done != nil {
+		close(wh.done)
+		wh.done = nil
+	}
+	return nil
+}
+
+// wake makes the delivery loop check the queue without waiting for the
+// next poll.
+func (wh *Client) wake() {
+	select {
+	case wh.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (wh *Client) safeLoop(done chan struct{}) {
+	for {
+		if wh.runLoopOnce(done) {
+			return
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+func (wh *Client) runLoopOnce(done chan struct{}) (finished bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			wh.logger.Error("panic recovered in webhook delivery loop",
+				mlog.Any("panic", r),
+				mlog.String("stack", string(debug.Stack())),
+			)
+			finished = false
+		}
+	}()
+	wh.loop(done)
+	return true
+}
+
+func (wh *Client) loop(done chan struct{}) {
+	var lastPurgeAt time.Time
+	for {
+		if time.Since(lastPurgeAt) >= deliveryPurgeInterval {
+			wh.purgeFinished()
+			lastPurgeAt = time.Now()
+		}
+
+		more := wh.deliverPending()
+		if more {
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-done:
+			return
+		case <-wh.wakeup:
+		case <-time.After(deliveryPollInterval):
+		}
+	}
+}
+
+// deliverPending attempts a batch of the deliveries that are due, and
+// returns true if there may be more of them waiting.
+func (wh *Client) deliverPending() bool {
+	now := utils.GetMillis()
+	deliveries, err := wh.store.GetPendingWebhookDeliveries(now, deliveryBatchSize)
+	if err != nil {
+		wh.logger.Error("webhook delivery loop - error fetching pending deliveries", mlog.Err(err))
+		return false
+	}
+
+	leaseUntil := now + deliveryLease.Milliseconds()
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		claimed, err := wh.store.ClaimWebhookDelivery(delivery.ID, delivery.NextAttemptAt, leaseUntil)
+		if err != nil {
+			wh.logger.Error("webhook delivery loop - error claiming delivery", mlog.String("delivery_id", delivery.ID), mlog.Err(err))
+			continue
+		}
+		if !claimed {
+			// another server is delivering it
+			continue
+		}
+
+		wg.Add(1)
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+			wh.deliver(delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries) == deliveryBatchSize
+}
+
+// purgeFinished deletes the finished deliveries older than the retention.
+func (wh *Client) purgeFinished() {
+	updatedBefore := utils.GetMillis() - deliveryRetention.Milliseconds()
+	deleted, err := wh.store.DeleteWebhookDeliveriesFinishedBefore(updatedBefore)
+	if err != nil {
+		wh.logger.Error("webhook delivery loop - error purging finished deliveries", mlog.Err(err))
+		return
+	}
+	if deleted > 0 {
+		wh.logger.Debug("webhook delivery loop - purged finished deliveries", mlog.Int("count", deleted))
+	}
+}
+
+// deliver makes an attempt to post a delivery to its webhook and records
+// the result, scheduling a retry if it failed.
+func (wh *Client) deliver(delivery *model.WebhookDelivery) {
+	webhook, err := wh.store.GetBoardWebhook(delivery.WebhookID)
+	if err != nil && !model.IsErrNotFound(err) {
+		// the lease will expire and the delivery will be retried
+		wh.logger.Error("webhook delivery - error fetching webhook", mlog.String("webhook_id", delivery.WebhookID), mlog.Err(err))
+		return
+	}
+
+	now := utils.GetMillis()
+	delivery.UpdateAt = now
+
+	if webhook == nil || !webhook.Enabled {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = 0
+		delivery.Error = "webhook deleted or disabled"
+		wh.updateDelivery(delivery)
+		return
+	}
+
+	code, err := wh.send(webhook, delivery)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+	delivery.ResponseCode = code
+	delivery.UpdateAt = utils.GetMillis()
+
+	switch {
+	case err == nil:
+		delivery.Status = model.WebhookDeliverySuccess
+		delivery.NextAttemptAt = 0
+		delivery.Error = ""
+	case delivery.Attempts >= maxDeliveryAttempts, errors.Is(err, errInternalAddress):
+		// retrying a refused address won't help
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = 0
+		delivery.Error = truncate(err.Error(), maxErrorLength)
+	default:
+		delivery.NextAttemptAt = now + retryDelay(delivery.Attempts).Milliseconds()
+		delivery.Error = truncate(err.Error(), maxErrorLength)
+	}
+
+	wh.logger.Debug("webhook delivery attempt",
+		mlog.String("delivery_id", delivery.ID),
+		mlog.String("webhook_id", webhook.ID),
+		mlog.String("event", string(delivery.Event)),
+		mlog.Int("attempts", delivery.Attempts),
+		mlog.Int("response_code", code),
+		mlog.String("status", string(delivery.Status)),
+	)
+	wh.updateDelivery(delivery)
+}
+
+func (wh *Client) updateDelivery(delivery *model.WebhookDelivery) {
+	if err := wh.store.UpdateWebhookDelivery(delivery); err != nil {
+		wh.logger.Error("webhook delivery - error updating delivery", mlog.String("delivery_id", delivery.ID), mlog.Err(err))
+	}
+}
+
+// send posts the payload of a delivery, signed with the webhook secret. It
+// returns the status code of the response, or 0 if there was none.
+func (wh *Client) send(webhook *model.BoardWebhook, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(model.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(model.WebhookSignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := wh.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+var errInternalAddress = errors.New("webhook address is not allowed")
+
+// reservedNetworks are the networks that aren't reachable from the
+// internet, besides the loopback, private and link-local ones.
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+)
+
+// newHTTPClient returns the client that delivers the events to the board
+// webhooks. As the URLs are set by the board admins, it doesn't follow
+// redirects and it refuses to connect to internal addresses, unless they
+// are allowed by the AllowedInternalHosts setting (the server's
+// AllowedUntrustedInternalConnections in plugin mode), so that webhooks
+// can't be used to reach the services of the server's network.
+func (wh *Client) newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would make the connections on our behalf, bypassing the checks
+	transport.Proxy = nil
+	transport.DialContext = wh.dialContext
+
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			// the redirect response is recorded as the delivery result
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialContext connects to the address of a webhook. The IP addresses are
+// checked once resolved, so that a host name can't point to an internal
+// address.
+func (wh *Client) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := wh.allowedInternalHosts()
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if !isAllowedHost(host, allowed) {
+		dialer.Control = func(_, resolved string, _ syscall.RawConn) error {
+			ipStr, _, err := net.SplitHostPort(resolved)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(ipStr)
+			if ip == nil || (!isPublicIP(ip) && !isAllowedIP(ip, allowed)) {
+				return fmt.Errorf("%w: %s resolves to %s", errInternalAddress, host, ipStr)
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// allowedInternalHosts returns the host names, IP addresses and
+// networks of the configuration that webhooks may connect to even if
+// they are internal.
+func (wh *Client) allowedInternalHosts() []string {
+	if wh.config == nil {
+		return nil
+	}
+	return strings.FieldsFunc(wh.config.AllowedInternalHosts, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+func isAllowedHost(host string, allowed []string) bool {
+	for _, entry := range allowed {
+		if strings.EqualFold(entry, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowedIP(ip net.IP, allowed []string) bool {
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backendName = "webhooks"
+)
+
+func (wh *Client) Name() string {
+	return backendName
+}
+
+// BlockChanged queues the events caused by a block change for the
+// webhooks of the board subscribed to them.
+func (wh *Client) BlockChanged(evt notify.BlockChangeEvent) error {
+	if evt.Board == nil || evt.BlockChanged == nil {
+		return nil
+	}
+
+	webhooks, err := wh.enabledWebhooks(evt.Board.ID)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload := model.WebhookPayload{
+		TeamID:  evt.TeamID,
+		BoardID: evt.Board.ID,
+	}
+	if evt.ModifiedBy != nil {
+		payload.ActorID = evt.ModifiedBy.UserID
+	}
+
+	block := evt.BlockChanged
+	payloads := []model.WebhookPayload{}
+
+	switch {
+	case evt.Action == notify.Add && block.Type == model.TypeCard:
+		payload.Event = model.WebhookEventCardCreated
+		payload.Card = block
+		payloads = append(payloads, payload)
+
+	case evt.Action == notify.Add && block.Type == model.TypeComment:
+		payload.Event = model.WebhookEventCommentAdded
+		payload.Card = evt.Card
+		payload.Comment = block
+		payloads = append(payloads, payload)
+
+	case evt.Action == notify.Update && block.Type == model.TypeCard && evt.BlockOld != nil:
+		changes := propertyChanges(evt.Board, evt.BlockOld, block)
+		if len(changes) == 0 {
+			return nil
+		}
+
+		payload.Card = block
+		changed := payload
+		changed.Event = model.WebhookEventCardPropertyChanged
+		changed.Changes = changes
+		payloads = append(payloads, changed)
+
+		if subscribed(webhooks, model.WebhookEventCardMoved) {
+			groupByIDs, err := wh.groupByPropertyIDs(evt.Board.ID)
+			if err != nil {
+				return err
+			}
+			for _, change := range changes {
+				if groupByIDs[change.PropertyID] {
+					moved := payload
+					moved.Event = model.WebhookEventCardMoved
+					moved.Changes = []model.WebhookPropertyChange{change}
+					payloads = append(payloads, moved)
+				}
+			}
+		}
+	}
+
+	return wh.enqueue(webhooks, payloads)
+}
+
+// MemberChanged queues the member_added event for the webhooks of the
+// board subscribed to it.
+func (wh *Client) MemberChanged(evt notify.MemberChangeEvent) error {
+	if evt.Action != notify.Add || evt.Board == nil || evt.Member == nil {
+		return nil
+	}
+
+	webhooks, err := wh.enabledWebhooks(evt.Board.ID)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload := model.WebhookPayload{
+		Event:   model.WebhookEventMemberAdded,
+		TeamID:  evt.TeamID,
+		BoardID: evt.Board.ID,
+		Member:  evt.Member,
+	}
+	return wh.enqueue(webhooks, []model.WebhookPayload{payload})
+}
+
+func (wh *Client) enabledWebhooks(boardID string) ([]*model.BoardWebhook, error) {
+	webhooks, err := wh.store.GetBoardWebhooksForBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]*model.BoardWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.Enabled {
+			enabled = append(enabled, webhook)
+		}
+	}
+	return enabled, nil
+}
+
+// enqueue stores a delivery of each payload for every webhook subscribed
+// to its event, and wakes up the delivery loop.
+func (wh *Client) enqueue(webhooks []*model.BoardWebhook, payloads []model.WebhookPayload) error {
+	now := utils.GetMillis()
+	merr := merror.New()
+	queued := 0
+
+	for _, webhook := range webhooks {
+		for _, payload := range payloads {
+			if !webhook.Subscribes(payload.Event) {
+				continue
+			}
+
+			delivery := &model.WebhookDelivery{
+				ID:            utils.NewID(utils.IDTypeNone),
+				WebhookID:     webhook.ID,
+				BoardID:       webhook.BoardID,
+				Event:         payload.Event,
+				Status:        model.WebhookDeliveryPending,
+				NextAttemptAt: now,
+				CreateAt:      now,
+				UpdateAt:      now,
+			}
+
+			payload.DeliveryID = delivery.ID
+			payload.Timestamp = now
+			data, err := json.Marshal(payload)
+			if err != nil {
+				merr.Append(err)
+				continue
+			}
+			delivery.Payload = string(data)
+
+			if err := wh.store.InsertWebhookDelivery(delivery); err != nil {
+				merr.Append(err)
+				continue
+			}
+			queued++
+		}
+	}
+
+	if queued > 0 {
+		wh.logger.Debug("webhook deliveries queued", mlog.Int("count", queued))
+		wh.wake()
+	}
+	return merr.ErrorOrNil()
+}
+
+// groupByPropertyIDs returns the ids of the properties the board and table
+// views of a board group their cards by, so a card changing one of them
+// moves between groups.
+func (wh *Client) groupByPropertyIDs(boardID string) (map[string]bool, error) {
+	views, err := wh.store.GetBlocks(model.QueryBlocksOptions{BoardID: boardID, BlockType: model.TypeView})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, block := range views {
+		view, err := model.Block2View(block)
+		if err != nil {
+			wh.logger.Warn("webhook events - cannot parse view", mlog.String("view_id", block.ID), mlog.Err(err))
+			continue
+		}
+		if view.GroupByID != "" && (view.ViewType == "board" || view.ViewType == "table") {
+			ids[view.GroupByID] = true
+		}
+	}
+	return ids, nil
+}
+
+func subscribed(webhooks []*model.BoardWebhook, event model.WebhookEvent) bool {
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// propertyChanges returns the card properties that differ between two
+// versions of a card, ordered by property id.
+func propertyChanges(board *model.Board, oldCard, newCard *model.Block) []model.WebhookPropertyChange {
+	oldProps, _ := oldCard.Fields["properties"].(map[string]interface{})
+	newProps, _ := newCard.Fields["properties"].(map[string]interface{})
+
+	ids := make([]string, 0, len(oldProps)+len(newProps))
+	for id := range oldProps {
+		ids = append(ids, id)
+	}
+	for id := range newProps {
+		if _, ok := oldProps[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	schema, _ := model.ParsePropertySchema(board)
+
+	changes := []model.WebhookPropertyChange{}
+	for _, id := range ids {
+		if reflect.DeepEqual(oldProps[id], newProps[id]) {
+			continue
+		}
+		change := model.WebhookPropertyChange{
+			PropertyID: id,
+			OldValue:   oldProps[id],
+			NewValue:   newProps[id],
+		}
+		if prop, ok := schema[id]; ok {
+			change.PropertyName = prop.Name
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/config"
@@ -15,6 +16,18 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+// Store is the part of the store used to deliver board webhooks.
+type Store interface {
+	GetBoardWebhook(webhookID string) (*model.BoardWebhook, error)
+	GetBoardWebhooksForBoard(boardID string) ([]*model.BoardWebhook, error)
+	GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error)
+	InsertWebhookDelivery(delivery *model.WebhookDelivery) error
+	GetPendingWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error)
+	ClaimWebhookDelivery(deliveryID string, nextAttemptAt, leaseUntil int64) (bool, error)
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	DeleteWebhookDeliveriesFinishedBefore(updatedAt int64) (int64, error)
+}
+
 // NotifyUpdate calls webhooks.
 func (wh *Client) NotifyUpdate(block *model.Block) {
 	if len(wh.config.WebhookUpdate) < 1 {
@@ -26,7 +39,11 @@ func (wh *Client) NotifyUpdate(block *model.Block) {
 		wh.logger.Fatal("NotifyUpdate: json.Marshal", mlog.Err(err))
 	}
 	for _, url := range wh.config.WebhookUpdate {
-		resp, _ := http.Post(url, "application/json", bytes.NewBuffer(json)) //nolint:gosec
+		resp, err := http.Post(url, "application/json", bytes.NewBuffer(json)) //nolint:gosec
+		if err != nil {
+			wh.logger.Warn("webhook.NotifyUpdate failed", mlog.String("url", url), mlog.Err(err))
+			continue
+		}
 		_, _ = io.ReadAll(resp.Body)
 		resp.Body.Close()
 
@@ -34,16 +51,29 @@ func (wh *Client) NotifyUpdate(block *model.Block) {
 	}
 }
 
-// Client is a webhook client.
+// Client is a webhook client. Besides calling the webhooks of the
+// configuration on every block update, it is a notification backend that
+// queues the events of the boards with webhooks, and delivers them.
 type Client struct {
-	config *config.Configuration
-	logger mlog.LoggerIFace
+	config     *config.Configuration
+	store      Store
+	logger     mlog.LoggerIFace
+	httpClient *http.Client
+
+	wakeup chan struct{}
+
+	mux  sync.Mutex
+	done chan struct{}
 }
 
 // NewClient creates a new Client.
-func NewClient(config *config.Configuration, logger mlog.LoggerIFace) *Client {
-	return &Client{
+func NewClient(config *config.Configuration, store Store, logger mlog.LoggerIFace) *Client {
+	wh := &Client{
 		config: config,
+		store:  store,
 		logger: logger,
+		wakeup: make(chan struct{}, 1),
 	}
+	wh.httpClient = wh.newHTTPClient()
+	return wh
 }
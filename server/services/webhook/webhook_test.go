@@ -4,13 +4,22 @@
 package webhook
 
 import (
+	"encoding/json"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
 	"github.com/mattermost/mattermost-plugin-boards/server/services/config"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/notify"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/store/mockstore"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -32,7 +41,7 @@ func TestClientUpdateNotify(t *testing.T) {
 		assert.NoError(t, err)
 	}()
 
-	client := NewClient(cfg, logger)
+	client := NewClient(cfg, nil, logger)
 
 	client.NotifyUpdate(&model.Block{})
 
@@ -40,3 +49,352 @@ func TestClientUpdateNotify(t *testing.T) {
 		t.Error("webhook url not be notified")
 	}
 }
+
+func TestClientUpdateNotifyUnreachable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachableURL := ts.URL
+	ts.Close()
+
+	cfg := &config.Configuration{
+		WebhookUpdate: []string{unreachableURL},
+	}
+
+	client := NewClient(cfg, nil, mlog.CreateConsoleTestLogger(t))
+
+	require.NotPanics(t, func() {
+		client.NotifyUpdate(&model.Block{})
+	})
+}
+
+func setupTestClient(t *testing.T) (*Client, *mockstore.MockStore) {
+	ctrl := gomock.NewController(t)
+	store := mockstore.NewMockStore(ctrl)
+	// the test servers listen on the loopback address
+	client := NewClient(&config.Configuration{AllowedInternalHosts: "127.0.0.1"}, store, mlog.CreateConsoleTestLogger(t))
+	return client, store
+}
+
+func TestSign(t *testing.T) {
+	// printf '{"a":1}' | openssl dgst -sha256 -hmac secret
+	require.Equal(t,
+		"sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494",
+		Sign("secret", []byte(`{"a":1}`)),
+	)
+}
+
+func TestDeliver(t *testing.T) {
+	webhook := &model.BoardWebhook{
+		ID:      "webhook-id",
+		BoardID: "board-id",
+		Secret:  "secret",
+		Events:  []model.WebhookEvent{model.WebhookEventCardCreated},
+		Enabled: true,
+	}
+
+	newDelivery := func(attempts int) *model.WebhookDelivery {
+		return &model.WebhookDelivery{
+			ID:        "delivery-id",
+			WebhookID: webhook.ID,
+			BoardID:   webhook.BoardID,
+			Event:     model.WebhookEventCardCreated,
+			Payload:   `{"event":"card_created"}`,
+			Status:    model.WebhookDeliveryPending,
+			Attempts:  attempts,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer ts.Close()
+
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.URL = ts.URL
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(0)
+		client.deliver(delivery)
+
+		require.NotNil(t, received)
+		require.Equal(t, delivery.Payload, string(body))
+		require.Equal(t, Sign("secret", body), received.Header.Get(model.WebhookSignatureHeader))
+		require.Equal(t, "card_created", received.Header.Get(model.WebhookEventHeader))
+		require.Equal(t, "delivery-id", received.Header.Get(model.WebhookDeliveryHeader))
+
+		require.Equal(t, model.WebhookDeliverySuccess, delivery.Status)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.ResponseCode)
+		require.Zero(t, delivery.NextAttemptAt)
+		require.Empty(t, delivery.Error)
+	})
+
+	t.Run("failure is retried with backoff", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.URL = ts.URL
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(2)
+		client.deliver(delivery)
+
+		require.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+		require.Equal(t, 3, delivery.Attempts)
+		require.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+		require.Equal(t, delivery.LastAttemptAt+retryDelay(3).Milliseconds(), delivery.NextAttemptAt)
+		require.NotEmpty(t, delivery.Error)
+	})
+
+	t.Run("last attempt fails the delivery", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.URL = ts.URL
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(maxDeliveryAttempts - 1)
+		client.deliver(delivery)
+
+		require.Equal(t, model.WebhookDeliveryFailed, delivery.Status)
+		require.Equal(t, maxDeliveryAttempts, delivery.Attempts)
+		require.Zero(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("internal addresses are refused", func(t *testing.T) {
+		var called bool
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer ts.Close()
+
+		client, store := setupTestClient(t)
+		client.config.AllowedInternalHosts = ""
+		hook := *webhook
+		hook.URL = ts.URL
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(0)
+		client.deliver(delivery)
+
+		require.False(t, called)
+		require.Equal(t, model.WebhookDeliveryFailed, delivery.Status)
+		require.Zero(t, delivery.ResponseCode)
+		require.Contains(t, delivery.Error, errInternalAddress.Error())
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		var redirected bool
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer target.Close()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target.URL, http.StatusFound)
+		}))
+		defer ts.Close()
+
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.URL = ts.URL
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(0)
+		client.deliver(delivery)
+
+		require.False(t, redirected)
+		require.Equal(t, http.StatusFound, delivery.ResponseCode)
+		require.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+	})
+
+	t.Run("disabled webhook", func(t *testing.T) {
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.Enabled = false
+		store.EXPECT().GetBoardWebhook(webhook.ID).Return(&hook, nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.Any()).Return(nil)
+
+		delivery := newDelivery(0)
+		client.deliver(delivery)
+
+		require.Equal(t, model.WebhookDeliveryFailed, delivery.Status)
+		require.Zero(t, delivery.Attempts)
+	})
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		require.False(t, isPublicIP(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"} {
+		require.True(t, isPublicIP(net.ParseIP(addr)), addr)
+	}
+
+	allowed := []string{"10.0.0.0/8", "192.168.1.1"}
+	require.True(t, isAllowedIP(net.ParseIP("10.20.30.40"), allowed))
+	require.True(t, isAllowedIP(net.ParseIP("192.168.1.1"), allowed))
+	require.False(t, isAllowedIP(net.ParseIP("192.168.1.2"), allowed))
+}
+
+func TestDeliverPending(t *testing.T) {
+	client, store := setupTestClient(t)
+
+	delivery := &model.WebhookDelivery{ID: "delivery-id", WebhookID: "webhook-id", NextAttemptAt: 1000}
+	store.EXPECT().GetPendingWebhookDeliveries(gomock.Any(), deliveryBatchSize).Return([]*model.WebhookDelivery{delivery}, nil)
+	// another server claimed the delivery first, so it is not attempted
+	store.EXPECT().ClaimWebhookDelivery("delivery-id", int64(1000), gomock.Any()).Return(false, nil)
+
+	require.False(t, client.deliverPending())
+}
+
+func TestPurgeFinished(t *testing.T) {
+	client, store := setupTestClient(t)
+
+	before := utils.GetMillis() - deliveryRetention.Milliseconds()
+	store.EXPECT().DeleteWebhookDeliveriesFinishedBefore(gomock.Any()).
+		DoAndReturn(func(updatedAt int64) (int64, error) {
+			require.GreaterOrEqual(t, updatedAt, before)
+			require.Less(t, updatedAt, before+time.Minute.Milliseconds())
+			return 3, nil
+		})
+
+	client.purgeFinished()
+}
+
+func TestBlockChanged(t *testing.T) {
+	board := &model.Board{
+		ID:     "board-id",
+		TeamID: "team-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select"},
+			{"id": "priority", "name": "Priority", "type": "select"},
+		},
+	}
+	member := &model.BoardMember{BoardID: board.ID, UserID: "user-id"}
+	webhook := &model.BoardWebhook{
+		ID:      "webhook-id",
+		BoardID: board.ID,
+		Events: []model.WebhookEvent{
+			model.WebhookEventCardCreated,
+			model.WebhookEventCardPropertyChanged,
+			model.WebhookEventCommentAdded,
+			model.WebhookEventCardMoved,
+		},
+		Enabled: true,
+	}
+
+	card := func(props map[string]interface{}) *model.Block {
+		return &model.Block{
+			ID:      "card-id",
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Fields:  map[string]interface{}{"properties": props},
+		}
+	}
+
+	captureDeliveries := func(store *mockstore.MockStore) *[]*model.WebhookDelivery {
+		deliveries := []*model.WebhookDelivery{}
+		store.EXPECT().InsertWebhookDelivery(gomock.Any()).DoAndReturn(func(d *model.WebhookDelivery) error {
+			deliveries = append(deliveries, d)
+			return nil
+		}).AnyTimes()
+		return &deliveries
+	}
+
+	t.Run("card created", func(t *testing.T) {
+		client, store := setupTestClient(t)
+		store.EXPECT().GetBoardWebhooksForBoard(board.ID).Return([]*model.BoardWebhook{webhook}, nil)
+		deliveries := captureDeliveries(store)
+
+		err := client.BlockChanged(notify.BlockChangeEvent{
+			Action:       notify.Add,
+			TeamID:       board.TeamID,
+			Board:        board,
+			BlockChanged: card(nil),
+			ModifiedBy:   member,
+		})
+		require.NoError(t, err)
+		require.Len(t, *deliveries, 1)
+
+		delivery := (*deliveries)[0]
+		require.Equal(t, model.WebhookEventCardCreated, delivery.Event)
+		require.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+
+		var payload model.WebhookPayload
+		require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+		require.Equal(t, delivery.ID, payload.DeliveryID)
+		require.Equal(t, "user-id", payload.ActorID)
+		require.Equal(t, "card-id", payload.Card.ID)
+	})
+
+	t.Run("property changed and card moved", func(t *testing.T) {
+		client, store := setupTestClient(t)
+		store.EXPECT().GetBoardWebhooksForBoard(board.ID).Return([]*model.BoardWebhook{webhook}, nil)
+		store.EXPECT().GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeView}).Return([]*model.Block{
+			{ID: "view-id", BoardID: board.ID, Type: model.TypeView, Fields: map[string]interface{}{"viewType": "board", "groupById": "status"}},
+		}, nil)
+		deliveries := captureDeliveries(store)
+
+		err := client.BlockChanged(notify.BlockChangeEvent{
+			Action:       notify.Update,
+			TeamID:       board.TeamID,
+			Board:        board,
+			BlockChanged: card(map[string]interface{}{"status": "done", "priority": "high"}),
+			BlockOld:     card(map[string]interface{}{"status": "todo", "priority": "high"}),
+			ModifiedBy:   member,
+		})
+		require.NoError(t, err)
+		require.Len(t, *deliveries, 2)
+		require.Equal(t, model.WebhookEventCardPropertyChanged, (*deliveries)[0].Event)
+		require.Equal(t, model.WebhookEventCardMoved, (*deliveries)[1].Event)
+
+		var payload model.WebhookPayload
+		require.NoError(t, json.Unmarshal([]byte((*deliveries)[1].Payload), &payload))
+		require.Equal(t, []model.WebhookPropertyChange{
+			{PropertyID: "status", PropertyName: "Status", OldValue: "todo", NewValue: "done"},
+		}, payload.Changes)
+	})
+
+	t.Run("no webhooks", func(t *testing.T) {
+		client, store := setupTestClient(t)
+		store.EXPECT().GetBoardWebhooksForBoard(board.ID).Return([]*model.BoardWebhook{}, nil)
+
+		err := client.BlockChanged(notify.BlockChangeEvent{
+			Action:       notify.Add,
+			Board:        board,
+			BlockChanged: card(nil),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("unsubscribed event", func(t *testing.T) {
+		client, store := setupTestClient(t)
+		hook := *webhook
+		hook.Events = []model.WebhookEvent{model.WebhookEventMemberAdded}
+		store.EXPECT().GetBoardWebhooksForBoard(board.ID).Return([]*model.BoardWebhook{&hook}, nil)
+
+		err := client.BlockChanged(notify.BlockChangeEvent{
+			Action:       notify.Add,
+			Board:        board,
+			BlockChanged: card(nil),
+		})
+		require.NoError(t, err)
+	})
+}
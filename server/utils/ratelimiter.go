@@ -0,0 +1,75 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter that keeps a separate bucket per key.
+// Each bucket holds up to `burst` tokens and is refilled at `perSecond` tokens
+// per second.
+type RateLimiter struct {
+	perSecond float64
+	burst     float64
+
+	mux     sync.Mutex
+	buckets map[string]*rateBucket
+
+	now func() time.Time
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		buckets:   make(map[string]*rateBucket),
+		now:       time.Now,
+	}
+}
+
+// Allow consumes a token from the bucket for the key and returns true, or
+// returns false if the bucket is empty.
+func (rl *RateLimiter) Allow(key string) bool {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	now := rl.now()
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		rl.prune(now)
+		bucket = &rateBucket{tokens: rl.burst, last: now}
+		rl.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rl.perSecond
+	if bucket.tokens > rl.burst {
+		bucket.tokens = rl.burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// prune drops the buckets that have refilled completely, as they are
+// equivalent to a new bucket.
+func (rl *RateLimiter) prune(now time.Time) {
+	for key, bucket := range rl.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*rl.perSecond >= rl.burst {
+			delete(rl.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	rl := NewRateLimiter(1, 3)
+	rl.now = func() time.Time { return now }
+
+	t.Run("burst", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			assert.True(t, rl.Allow("key1"))
+		}
+		assert.False(t, rl.Allow("key1"))
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		assert.True(t, rl.Allow("key2"))
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		now = now.Add(time.Second)
+		assert.True(t, rl.Allow("key1"))
+		assert.False(t, rl.Allow("key1"))
+
+		now = now.Add(time.Minute)
+		for i := 0; i < 3; i++ {
+			assert.True(t, rl.Allow("key1"))
+		}
+		assert.False(t, rl.Allow("key1"))
+	})
+
+	t.Run("full buckets are pruned", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.True(t, rl.Allow("key3"))
+		assert.Len(t, rl.buckets, 1)
+	})
+}
@@ -25,11 +25,30 @@ const (
 	websocketActionUpdateCardLimitTimestamp = "UPDATE_CARD_LIMIT_TIMESTAMP"
 	websocketActionReorderCategories        = "REORDER_CATEGORIES"
 	websocketActionReorderCategoryBoards    = "REORDER_CATEGORY_BOARDS"
+	websocketActionSetPresence              = "SET_PRESENCE"
+	websocketActionClearPresence            = "CLEAR_PRESENCE"
+	websocketActionUpdatePresence           = "UPDATE_PRESENCE"
+	websocketActionDeletePresence           = "DELETE_PRESENCE"
+	websocketActionResyncRequired           = "RESYNC_REQUIRED"
 )
 
 type Store interface {
 	GetBlock(blockID string) (*model.Block, error)
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
+	GetMembersForUser(userID string) ([]*model.BoardMember, error)
+}
+
+// memberBoardIDs returns the IDs of the boards the user is a member of.
+func memberBoardIDs(store Store, userID string) (map[string]bool, error) {
+	members, err := store.GetMembersForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	boardIDs := make(map[string]bool, len(members))
+	for _, member := range members {
+		boardIDs[member.BoardID] = true
+	}
+	return boardIDs, nil
 }
 
 type Adapter interface {
@@ -46,4 +65,5 @@ type Adapter interface {
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
 	BroadcastCategoryReorder(teamID, userID string, categoryOrder []string)
 	BroadcastCategoryBoardsReorder(teamID, userID, categoryID string, boardsOrder []string)
+	GetBoardPresence(boardID string) []*model.Presence
 }
@@ -42,6 +42,27 @@ type UpdateSubscription struct {
 	Subscription *model.Subscription `json:"subscription"`
 }
 
+// UpdatePresenceMsg is sent when a user starts or stops viewing or
+// editing a board or card. DELETE_PRESENCE messages only have the user
+// and board of the presence.
+type UpdatePresenceMsg struct {
+	Action   string          `json:"action"`
+	TeamID   string          `json:"teamId"`
+	Presence *model.Presence `json:"presence"`
+}
+
+// ResyncRequiredMsg is sent when a client subscribes with the last
+// sequence it saw of a board and the events it missed are not available
+// anymore, so it needs to fetch the board again. The events that follow
+// have the sequences after the one of the message.
+type ResyncRequiredMsg struct {
+	Action        string `json:"action"`
+	TeamID        string `json:"teamId"`
+	BoardID       string `json:"boardId"`
+	SequenceEpoch string `json:"sequenceEpoch"`
+	Sequence      int64  `json:"sequence"`
+}
+
 // UpdateClientConfig is sent on block updates.
 type UpdateClientConfig struct {
 	Action       string             `json:"action"`
@@ -61,6 +82,15 @@ type WebsocketCommand struct {
 	Token     string   `json:"token"`
 	ReadToken string   `json:"readToken"`
 	BlockIDs  []string `json:"blockIds"`
+	BoardID   string   `json:"boardId"`
+	CardID    string   `json:"cardId"`
+	Activity  string   `json:"activity"`
+
+	// the epoch and the last sequence the client saw of each board, to
+	// replay the events it missed while it was disconnected. At most
+	// maxReplayBoards boards are accepted
+	SequenceEpoch string           `json:"sequenceEpoch"`
+	Sequences     map[string]int64 `json:"sequences"`
 }
 
 type CategoryReorderMessage struct {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/mattermost/mattermost-plugin-boards/server/auth"
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/services/scheduler"
 	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
@@ -24,6 +25,7 @@ const websocketMessagePrefix = "custom_focalboard_"
 var (
 	errMissingTeamInCommand = fmt.Errorf("command doesn't contain teamId")
 	errInvalidFieldType     = fmt.Errorf("invalid field type in websocket command")
+	errTooManySequences     = fmt.Errorf("too many sequences in websocket command")
 )
 
 func invalidFieldTypeError(field string, value interface{}) error {
@@ -41,6 +43,8 @@ type PluginAdapterInterface interface {
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
 	BroadcastCardLimitTimestampChange(cardLimitTimestamp int64)
 	HandleClusterEvent(ev mmModel.PluginClusterEvent)
+	StartHeartbeat()
+	StopHeartbeat()
 }
 
 type PluginAdapter struct {
@@ -57,6 +61,11 @@ type PluginAdapter struct {
 	subscriptionsMU  sync.RWMutex
 	listenersByTeam  map[string][]*PluginAdapterClient
 	listenersByBlock map[string][]*PluginAdapterClient
+
+	nodeID        string
+	presence      *presenceTracker
+	heartbeatTask *scheduler.ScheduledTask
+	replay        *replayLog
 }
 
 // servicesAPI is the interface required by the PluginAdapter to interact with
@@ -67,7 +76,7 @@ type servicesAPI interface {
 }
 
 func NewPluginAdapter(api servicesAPI, auth auth.AuthInterface, store Store, logger mlog.LoggerIFace) *PluginAdapter {
-	return &PluginAdapter{
+	pa := &PluginAdapter{
 		api:               api,
 		auth:              auth,
 		store:             store,
@@ -79,7 +88,16 @@ func NewPluginAdapter(api servicesAPI, auth auth.AuthInterface, store Store, log
 		listenersByBlock:  make(map[string][]*PluginAdapterClient),
 		listenersMU:       sync.RWMutex{},
 		subscriptionsMU:   sync.RWMutex{},
+		nodeID:            mmModel.NewId(),
+		presence:          newPresenceTracker(),
 	}
+	pa.replay = newReplayLog(pa.isBoardOpen)
+	return pa
+}
+
+// isBoardOpen returns whether any connection of the cluster is on a board.
+func (pa *PluginAdapter) isBoardOpen(boardID string) bool {
+	return len(pa.presence.getForBoard(boardID)) > 0
 }
 
 func (pa *PluginAdapter) GetListenerByWebConnID(webConnID string) (pac *PluginAdapterClient, ok bool) {
@@ -313,6 +331,11 @@ func (pa *PluginAdapter) OnWebSocketDisconnect(webConnID, userID string) {
 	}
 
 	atomic.StoreInt64(&pac.inactiveAt, mmModel.GetMillis())
+
+	// the presence of the connection ends with it.
+	if pa.presence.has(webConnID) {
+		pa.changePresence(&PresenceChange{WebConnID: webConnID})
+	}
 }
 
 func commandFromRequest(req *mmModel.WebSocketRequest) (*WebsocketCommand, error) {
@@ -336,6 +359,21 @@ func commandFromRequest(req *mmModel.WebSocketRequest) (*WebsocketCommand, error
 		c.ReadToken = readToken
 	}
 
+	for field, value := range map[string]*string{
+		"boardId":       &c.BoardID,
+		"cardId":        &c.CardID,
+		"activity":      &c.Activity,
+		"sequenceEpoch": &c.SequenceEpoch,
+	} {
+		if rawValue, ok := req.Data[field]; ok {
+			s, ok := rawValue.(string)
+			if !ok {
+				return nil, invalidFieldTypeError(field, rawValue)
+			}
+			*value = s
+		}
+	}
+
 	if rawBlockIDs, ok := req.Data["blockIds"]; ok {
 		rawList, ok := rawBlockIDs.([]interface{})
 		if !ok {
@@ -352,6 +390,26 @@ func commandFromRequest(req *mmModel.WebSocketRequest) (*WebsocketCommand, error
 		c.BlockIDs = blockIDs
 	}
 
+	if rawSequences, ok := req.Data["sequences"]; ok {
+		rawMap, ok := rawSequences.(map[string]interface{})
+		if !ok {
+			return nil, invalidFieldTypeError("sequences", rawSequences)
+		}
+		if len(rawMap) > maxReplayBoards {
+			return nil, fmt.Errorf("%w: sequences has %d boards, the maximum is %d", errTooManySequences, len(rawMap), maxReplayBoards)
+		}
+		sequences := make(map[string]int64, len(rawMap))
+		for boardID, item := range rawMap {
+			// JSON numbers are decoded as float64
+			sequence, ok := item.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%w: sequences[%s] has type %T", errInvalidFieldType, boardID, item)
+			}
+			sequences[boardID] = int64(sequence)
+		}
+		c.Sequences = sequences
+	}
+
 	return c, nil
 }
 
@@ -418,6 +476,7 @@ func (pa *PluginAdapter) WebSocketMessageHasBeenPosted(webConnID, userID string,
 		}
 
 		pa.subscribeListenerToTeam(pac, command.TeamID)
+		pa.replayMissedEvents(webConnID, userID, command)
 	case websocketActionUnsubscribeTeam:
 		pa.logger.Debug(`Command: UNSUBSCRIBE_WORKSPACE`,
 			mlog.String("webConnID", webConnID),
@@ -426,6 +485,34 @@ func (pa *PluginAdapter) WebSocketMessageHasBeenPosted(webConnID, userID string,
 		)
 
 		pa.unsubscribeListenerFromTeam(pac, command.TeamID)
+	case websocketActionSetPresence:
+		pa.logger.Debug(`Command: SET_PRESENCE`,
+			mlog.String("webConnID", webConnID),
+			mlog.String("userID", userID),
+			mlog.String("teamID", command.TeamID),
+			mlog.String("boardID", command.BoardID),
+		)
+
+		presence, err := pa.presenceFromCommand(userID, command)
+		if err != nil {
+			pa.logger.Debug("invalid presence",
+				mlog.String("webConnID", webConnID),
+				mlog.String("userID", userID),
+				mlog.Err(err),
+			)
+			return
+		}
+
+		pa.changePresence(&PresenceChange{WebConnID: webConnID, Presence: presence})
+	case websocketActionClearPresence:
+		pa.logger.Debug(`Command: CLEAR_PRESENCE`,
+			mlog.String("webConnID", webConnID),
+			mlog.String("userID", userID),
+		)
+
+		if pa.presence.has(webConnID) {
+			pa.changePresence(&PresenceChange{WebConnID: webConnID})
+		}
 	}
 }
 
@@ -470,10 +557,12 @@ func (pa *PluginAdapter) sendTeamMessage(event, teamID string, payload map[strin
 }
 
 // sendBoardMessageSkipCluster sends a message to all the users
-// subscribed to a given team that belong to one of its boards.
+// subscribed to a given team that belong to one of its boards. The
+// message gets the next sequence of the board on this node, and is kept
+// to be replayed.
 func (pa *PluginAdapter) sendBoardMessageSkipCluster(teamID, boardID string, payload map[string]interface{}, ensureUserIDs ...string) {
 	userIDs := pa.getUserIDsForTeamAndBoard(teamID, boardID, ensureUserIDs...)
-	pa.sendUserMessageSkipCluster(websocketActionUpdateBoard, payload, userIDs...)
+	pa.sendUserMessageSkipCluster(websocketActionUpdateBoard, pa.replay.add(boardID, payload), userIDs...)
 }
 
 // sendBoardMessage sends and propagates a message that is aimed for
@@ -714,3 +803,202 @@ func (pa *PluginAdapter) BroadcastCardLimitTimestampChange(cardLimitTimestamp in
 
 	pa.sendMessageToAll(websocketActionUpdateCardLimitTimestamp, utils.StructToMap(message))
 }
+
+// presenceFromCommand builds the presence a SET_PRESENCE command
+// announces, checking that the user can see the board and the card.
+func (pa *PluginAdapter) presenceFromCommand(userID string, command *WebsocketCommand) (*model.Presence, error) {
+	presence := &model.Presence{
+		UserID:   userID,
+		TeamID:   command.TeamID,
+		BoardID:  command.BoardID,
+		CardID:   command.CardID,
+		Activity: model.PresenceActivity(command.Activity),
+		UpdateAt: utils.GetMillis(),
+	}
+	if err := presence.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if !pa.auth.DoesUserHaveTeamAccess(userID, command.TeamID) {
+		return nil, model.NewErrPermission("access denied to team")
+	}
+
+	if !pa.auth.DoesUserHaveBoardAccess(userID, command.BoardID) {
+		return nil, model.NewErrPermission("access denied to board")
+	}
+
+	if command.CardID != "" {
+		card, err := pa.store.GetBlock(command.CardID)
+		if err != nil {
+			return nil, err
+		}
+		if card.BoardID != command.BoardID {
+			return nil, model.NewErrBadRequest("card does not belong to the board")
+		}
+	}
+	return presence, nil
+}
+
+// changePresence applies and propagates a presence change of a
+// websocket connection of this node.
+func (pa *PluginAdapter) changePresence(change *PresenceChange) {
+	change.NodeID = pa.nodeID
+
+	go func() {
+		clusterMessage := &ClusterMessage{
+			Presence: change,
+		}
+
+		pa.sendMessageToCluster(clusterMessage)
+	}()
+
+	pa.applyPresenceChangeSkipCluster(change)
+}
+
+// applyPresenceChangeSkipCluster applies a presence change and notifies
+// the users of the boards the connection left and joined.
+func (pa *PluginAdapter) applyPresenceChangeSkipCluster(change *PresenceChange) {
+	previous := pa.presence.apply(change)
+
+	if previous != nil {
+		pa.broadcastPresenceSkipCluster(previous.TeamID, previous.BoardID, previous.UserID)
+	}
+	if current := change.Presence; current != nil {
+		if previous == nil || previous.BoardID != current.BoardID || previous.UserID != current.UserID {
+			pa.broadcastPresenceSkipCluster(current.TeamID, current.BoardID, current.UserID)
+		}
+	}
+}
+
+// broadcastPresenceSkipCluster sends the presence of a user on a board
+// to the users of the board, or that the user left it.
+func (pa *PluginAdapter) broadcastPresenceSkipCluster(teamID, boardID, userID string) {
+	pa.logger.Trace("BroadcastingPresence",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+		mlog.String("userID", userID),
+	)
+
+	message := UpdatePresenceMsg{
+		Action:   websocketActionUpdatePresence,
+		TeamID:   teamID,
+		Presence: pa.presence.getForUser(boardID, userID),
+	}
+	if message.Presence == nil {
+		message.Action = websocketActionDeletePresence
+		message.Presence = &model.Presence{UserID: userID, TeamID: teamID, BoardID: boardID}
+	}
+
+	// presence is ephemeral, so it has no sequence and is not replayed
+	userIDs := pa.getUserIDsForTeamAndBoard(teamID, boardID)
+	pa.sendUserMessageSkipCluster(websocketActionUpdateBoard, utils.StructToMap(message), userIDs...)
+}
+
+// StartHeartbeat starts telling the other cluster nodes that this node is
+// running, and dropping the presence of the nodes that stopped doing so.
+func (pa *PluginAdapter) StartHeartbeat() {
+	pa.heartbeatTask = scheduler.CreateRecurringTask("presenceHeartbeat", pa.sendHeartbeat, nodeHeartbeatInterval)
+}
+
+// StopHeartbeat stops the heartbeat and tells the other cluster nodes
+// that this node is leaving, so they drop the presence of its connections.
+func (pa *PluginAdapter) StopHeartbeat() {
+	if pa.heartbeatTask != nil {
+		pa.heartbeatTask.Cancel()
+		pa.heartbeatTask = nil
+	}
+
+	pa.sendMessageToCluster(&ClusterMessage{
+		Heartbeat: &NodeHeartbeat{NodeID: pa.nodeID, Leaving: true},
+	})
+}
+
+func (pa *PluginAdapter) sendHeartbeat() {
+	pa.sendMessageToCluster(&ClusterMessage{
+		Heartbeat: &NodeHeartbeat{NodeID: pa.nodeID},
+	})
+
+	pa.presence.seen(pa.nodeID)
+	for _, nodeID := range pa.presence.staleNodes(time.Now().Add(-nodeTimeout)) {
+		pa.logger.Debug("dropping the presence of a node without heartbeat", mlog.String("nodeID", nodeID))
+		pa.dropNodePresenceSkipCluster(nodeID)
+	}
+}
+
+// applyHeartbeatSkipCluster records a heartbeat of another node, dropping
+// the presence of its connections if it is leaving.
+func (pa *PluginAdapter) applyHeartbeatSkipCluster(heartbeat *NodeHeartbeat) {
+	if !heartbeat.Leaving {
+		pa.presence.seen(heartbeat.NodeID)
+		return
+	}
+
+	pa.logger.Debug("dropping the presence of a node leaving the cluster", mlog.String("nodeID", heartbeat.NodeID))
+	pa.dropNodePresenceSkipCluster(heartbeat.NodeID)
+}
+
+// dropNodePresenceSkipCluster removes the presence of the connections of
+// a node and notifies the users of the boards they were on.
+func (pa *PluginAdapter) dropNodePresenceSkipCluster(nodeID string) {
+	broadcasted := map[string]bool{}
+	for _, presence := range pa.presence.removeNode(nodeID) {
+		key := presence.BoardID + "/" + presence.UserID
+		if broadcasted[key] {
+			continue
+		}
+		broadcasted[key] = true
+		pa.broadcastPresenceSkipCluster(presence.TeamID, presence.BoardID, presence.UserID)
+	}
+}
+
+func (pa *PluginAdapter) GetBoardPresence(boardID string) []*model.Presence {
+	return pa.presence.getForBoard(boardID)
+}
+
+// replayMissedEvents sends to a connection that subscribes to a team the
+// events of its boards after the sequences the client last saw, or that
+// it needs to fetch a board again if they are not available anymore.
+func (pa *PluginAdapter) replayMissedEvents(webConnID, userID string, command *WebsocketCommand) {
+	if len(command.Sequences) == 0 {
+		return
+	}
+
+	boardIDs, err := memberBoardIDs(pa.store, userID)
+	if err != nil {
+		pa.logger.Error("cannot check board membership to replay events",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	broadcast := &mmModel.WebsocketBroadcast{ConnectionId: webConnID}
+	for boardID, sequence := range command.Sequences {
+		if !boardIDs[boardID] {
+			continue
+		}
+
+		events, ok := pa.replay.since(command.SequenceEpoch, boardID, sequence)
+		if !ok {
+			epoch, current := pa.replay.current(boardID)
+			message := ResyncRequiredMsg{
+				Action:        websocketActionResyncRequired,
+				TeamID:        command.TeamID,
+				BoardID:       boardID,
+				SequenceEpoch: epoch,
+				Sequence:      current,
+			}
+			pa.api.PublishWebSocketEvent(websocketActionUpdateBoard, utils.StructToMap(message), broadcast)
+			continue
+		}
+
+		pa.logger.Debug("replaying missed events",
+			mlog.String("webConnID", webConnID),
+			mlog.String("boardID", boardID),
+			mlog.Int("count", len(events)),
+		)
+		for _, event := range events {
+			pa.api.PublishWebSocketEvent(websocketActionUpdateBoard, event, broadcast)
+		}
+	}
+}
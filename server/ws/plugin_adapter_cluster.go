@@ -16,6 +16,8 @@ type ClusterMessage struct {
 	UserID      string
 	Payload     map[string]interface{}
 	EnsureUsers []string
+	Presence    *PresenceChange
+	Heartbeat   *NodeHeartbeat
 }
 
 func (pa *PluginAdapter) sendMessageToCluster(clusterMessage *ClusterMessage) {
@@ -54,6 +56,16 @@ func (pa *PluginAdapter) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
 		return
 	}
 
+	if clusterMessage.Presence != nil {
+		pa.applyPresenceChangeSkipCluster(clusterMessage.Presence)
+		return
+	}
+
+	if clusterMessage.Heartbeat != nil {
+		pa.applyHeartbeatSkipCluster(clusterMessage.Heartbeat)
+		return
+	}
+
 	if clusterMessage.BoardID != "" {
 		pa.sendBoardMessageSkipCluster(clusterMessage.TeamID, clusterMessage.BoardID, clusterMessage.Payload, clusterMessage.EnsureUsers...)
 		return
@@ -4,13 +4,17 @@
 package ws
 
 import (
+	"encoding/json"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -76,6 +80,63 @@ func TestCommandFromRequest(t *testing.T) {
 		})
 	})
 
+	t.Run("accepts the presence fields", func(t *testing.T) {
+		req := &mmModel.WebSocketRequest{
+			Action: websocketMessagePrefix + websocketActionSetPresence,
+			Data: map[string]interface{}{
+				"teamId":   "team1",
+				"boardId":  "board1",
+				"cardId":   "card1",
+				"activity": "editing",
+			},
+		}
+		c, err := commandFromRequest(req)
+		require.NoError(t, err)
+		require.Equal(t, "board1", c.BoardID)
+		require.Equal(t, "card1", c.CardID)
+		require.Equal(t, "editing", c.Activity)
+	})
+
+	t.Run("accepts the last seen sequences", func(t *testing.T) {
+		req := &mmModel.WebSocketRequest{
+			Action: websocketMessagePrefix + websocketActionSubscribeTeam,
+			Data: map[string]interface{}{
+				"teamId":        "team1",
+				"sequenceEpoch": "epoch1",
+				"sequences":     map[string]interface{}{"board1": float64(12)},
+			},
+		}
+		c, err := commandFromRequest(req)
+		require.NoError(t, err)
+		require.Equal(t, "epoch1", c.SequenceEpoch)
+		require.Equal(t, map[string]int64{"board1": 12}, c.Sequences)
+	})
+
+	t.Run("rejects non-numeric sequences", func(t *testing.T) {
+		req := &mmModel.WebSocketRequest{
+			Action: websocketMessagePrefix + websocketActionSubscribeTeam,
+			Data: map[string]interface{}{
+				"teamId":    "team1",
+				"sequences": map[string]interface{}{"board1": "12"},
+			},
+		}
+		_, err := commandFromRequest(req)
+		require.Error(t, err)
+		require.ErrorIs(t, err, errInvalidFieldType)
+	})
+
+	t.Run("rejects non-string boardId", func(t *testing.T) {
+		req := &mmModel.WebSocketRequest{
+			Action: websocketMessagePrefix + websocketActionSetPresence,
+			Data:   map[string]interface{}{"teamId": "team1", "boardId": 1.0},
+		}
+		require.NotPanics(t, func() {
+			_, err := commandFromRequest(req)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "boardId")
+		})
+	})
+
 	t.Run("rejects blockIds that are not a JSON array", func(t *testing.T) {
 		req := &mmModel.WebSocketRequest{
 			Action: websocketMessagePrefix + websocketActionSubscribeBlocks,
@@ -644,3 +705,239 @@ func TestParallelSubscriptionsOnMultipleConnections(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPluginAdapterPresence(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	teamID := mmModel.NewId()
+	boardID := mmModel.NewId()
+	cardID := mmModel.NewId()
+	userID := mmModel.NewId()
+	webConnID := mmModel.NewId()
+
+	th.pa.OnWebSocketConnect(webConnID, userID)
+	th.SubscribeWebConnToTeam(webConnID, userID, teamID)
+
+	th.auth.EXPECT().DoesUserHaveTeamAccess(gomock.Any(), teamID).Return(true).AnyTimes()
+	th.auth.EXPECT().DoesUserHaveBoardAccess(userID, boardID).Return(true).AnyTimes()
+	th.auth.EXPECT().DoesUserHaveBoardAccess(gomock.Any(), boardID).Return(false).AnyTimes()
+	th.store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{{UserID: userID}}, nil).AnyTimes()
+	th.store.EXPECT().GetBlock(cardID).Return(&model.Block{ID: cardID, BoardID: boardID}, nil).AnyTimes()
+	th.api.EXPECT().PublishPluginClusterEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	setPresence := func(webConnID, userID string, data map[string]interface{}) {
+		th.ReceiveWebSocketMessage(webConnID, userID, websocketActionSetPresence, data)
+	}
+
+	t.Run("users with access to the board announce their presence", func(t *testing.T) {
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: userID}).
+			Do(func(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+				require.Equal(t, websocketActionUpdatePresence, payload["action"])
+			})
+
+		setPresence(webConnID, userID, map[string]interface{}{
+			"teamId":   teamID,
+			"boardId":  boardID,
+			"cardId":   cardID,
+			"activity": string(model.PresenceActivityEditing),
+		})
+
+		presences := th.pa.GetBoardPresence(boardID)
+		require.Len(t, presences, 1)
+		require.Equal(t, userID, presences[0].UserID)
+		require.Equal(t, cardID, presences[0].CardID)
+		require.Equal(t, model.PresenceActivityEditing, presences[0].Activity)
+	})
+
+	t.Run("users without access to the board are ignored", func(t *testing.T) {
+		otherUserID := mmModel.NewId()
+		otherWebConnID := mmModel.NewId()
+		th.pa.OnWebSocketConnect(otherWebConnID, otherUserID)
+
+		setPresence(otherWebConnID, otherUserID, map[string]interface{}{
+			"teamId":   teamID,
+			"boardId":  boardID,
+			"activity": string(model.PresenceActivityViewing),
+		})
+
+		require.Len(t, th.pa.GetBoardPresence(boardID), 1)
+	})
+
+	t.Run("invalid activities are ignored", func(t *testing.T) {
+		otherWebConnID := mmModel.NewId()
+		th.pa.OnWebSocketConnect(otherWebConnID, userID)
+
+		setPresence(otherWebConnID, userID, map[string]interface{}{
+			"teamId":   teamID,
+			"boardId":  boardID,
+			"activity": "sleeping",
+		})
+
+		require.False(t, th.pa.presence.has(otherWebConnID))
+	})
+
+	remoteNodeID := mmModel.NewId()
+	receiveClusterMessage := func(clusterMessage *ClusterMessage) {
+		data, err := json.Marshal(clusterMessage)
+		require.NoError(t, err)
+
+		th.pa.HandleClusterEvent(mmModel.PluginClusterEvent{Id: "websocket_message", Data: data})
+	}
+
+	t.Run("presence from other nodes is applied", func(t *testing.T) {
+		remoteUserID := mmModel.NewId()
+		clusterMessage := &ClusterMessage{
+			Presence: &PresenceChange{
+				NodeID:    remoteNodeID,
+				WebConnID: mmModel.NewId(),
+				Presence: &model.Presence{
+					UserID:   remoteUserID,
+					TeamID:   teamID,
+					BoardID:  boardID,
+					Activity: model.PresenceActivityViewing,
+					UpdateAt: utils.GetMillis(),
+				},
+			},
+		}
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: userID})
+
+		receiveClusterMessage(clusterMessage)
+
+		require.Len(t, th.pa.GetBoardPresence(boardID), 2)
+	})
+
+	t.Run("presence from nodes without heartbeat is dropped", func(t *testing.T) {
+		th.pa.presence.nodeSeenAt[remoteNodeID] = time.Now().Add(-nodeTimeout - time.Minute)
+
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: userID}).
+			Do(func(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+				require.Equal(t, websocketActionDeletePresence, payload["action"])
+			})
+
+		th.pa.sendHeartbeat()
+
+		presences := th.pa.GetBoardPresence(boardID)
+		require.Len(t, presences, 1)
+		require.Equal(t, userID, presences[0].UserID)
+	})
+
+	t.Run("presence from nodes leaving the cluster is dropped", func(t *testing.T) {
+		var actions []interface{}
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: userID}).
+			Do(func(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+				actions = append(actions, payload["action"])
+			}).Times(2)
+
+		receiveClusterMessage(&ClusterMessage{
+			Presence: &PresenceChange{
+				NodeID:    remoteNodeID,
+				WebConnID: mmModel.NewId(),
+				Presence: &model.Presence{
+					UserID:   mmModel.NewId(),
+					TeamID:   teamID,
+					BoardID:  boardID,
+					Activity: model.PresenceActivityViewing,
+					UpdateAt: utils.GetMillis(),
+				},
+			},
+		})
+		require.Len(t, th.pa.GetBoardPresence(boardID), 2)
+
+		receiveClusterMessage(&ClusterMessage{
+			Heartbeat: &NodeHeartbeat{NodeID: remoteNodeID, Leaving: true},
+		})
+
+		presences := th.pa.GetBoardPresence(boardID)
+		require.Len(t, presences, 1)
+		require.Equal(t, userID, presences[0].UserID)
+		require.Equal(t, []interface{}{websocketActionUpdatePresence, websocketActionDeletePresence}, actions)
+	})
+
+	t.Run("presence ends when the connection is closed", func(t *testing.T) {
+		// the only connection subscribed to the team is the one closed, so
+		// no message is sent on this node
+		th.pa.OnWebSocketDisconnect(webConnID, userID)
+
+		require.Empty(t, th.pa.GetBoardPresence(boardID))
+	})
+}
+
+func TestPluginAdapterReplay(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	teamID := mmModel.NewId()
+	boardID := mmModel.NewId()
+	userID := mmModel.NewId()
+	webConnID := mmModel.NewId()
+
+	th.pa.OnWebSocketConnect(webConnID, userID)
+	th.SubscribeWebConnToTeam(webConnID, userID, teamID)
+
+	th.auth.EXPECT().DoesUserHaveTeamAccess(userID, teamID).Return(true).AnyTimes()
+	th.store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{{UserID: userID}}, nil).AnyTimes()
+	th.store.EXPECT().GetMembersForUser(userID).Return([]*model.BoardMember{{BoardID: boardID, UserID: userID}}, nil).AnyTimes()
+	th.api.EXPECT().PublishPluginClusterEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: userID}).AnyTimes()
+
+	for i := 0; i < 3; i++ {
+		th.pa.BroadcastBlockChange(teamID, &model.Block{ID: mmModel.NewId(), BoardID: boardID})
+	}
+	epoch, sequence := th.pa.replay.current(boardID)
+	require.Equal(t, int64(3), sequence)
+
+	subscribe := func(data map[string]interface{}) {
+		data["teamId"] = teamID
+		th.ReceiveWebSocketMessage(webConnID, userID, websocketActionSubscribeTeam, data)
+	}
+
+	t.Run("missed events are replayed to the connection", func(t *testing.T) {
+		var replayed []interface{}
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{ConnectionId: webConnID}).
+			Do(func(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+				require.Equal(t, websocketActionUpdateBlock, payload["action"])
+				replayed = append(replayed, payload[sequenceField])
+			}).Times(2)
+
+		subscribe(map[string]interface{}{
+			"sequenceEpoch": epoch,
+			"sequences":     map[string]interface{}{boardID: float64(1)},
+		})
+
+		require.Equal(t, []interface{}{int64(2), int64(3)}, replayed)
+	})
+
+	t.Run("a resync is required for a different epoch", func(t *testing.T) {
+		th.api.EXPECT().PublishWebSocketEvent(websocketActionUpdateBoard, gomock.Any(), &mmModel.WebsocketBroadcast{ConnectionId: webConnID}).
+			Do(func(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+				require.Equal(t, websocketActionResyncRequired, payload["action"])
+				require.Equal(t, boardID, payload["boardId"])
+				require.Equal(t, epoch, payload["sequenceEpoch"])
+				require.EqualValues(t, 3, payload["sequence"])
+			})
+
+		subscribe(map[string]interface{}{
+			"sequenceEpoch": mmModel.NewId(),
+			"sequences":     map[string]interface{}{boardID: float64(1)},
+		})
+	})
+
+	t.Run("boards the user is not a member of are not replayed", func(t *testing.T) {
+		subscribe(map[string]interface{}{
+			"sequenceEpoch": epoch,
+			"sequences":     map[string]interface{}{mmModel.NewId(): float64(0)},
+		})
+	})
+
+	t.Run("the number of sequences is limited", func(t *testing.T) {
+		sequences := map[string]interface{}{boardID: float64(1)}
+		for i := 0; i < maxReplayBoards; i++ {
+			sequences[mmModel.NewId()] = float64(0)
+		}
+
+		_, err := commandFromRequest(&mmModel.WebSocketRequest{
+			Action: websocketActionSubscribeTeam,
+			Data:   map[string]interface{}{"teamId": teamID, "sequences": sequences},
+		})
+		require.ErrorIs(t, err, errTooManySequences)
+	})
+}
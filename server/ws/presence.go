@@ -0,0 +1,192 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ws
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+// presenceTimeout is how long a presence lasts if it is not announced
+// again, so that the presence of the connections of a node that stopped
+// without disconnecting them expires.
+const presenceTimeout = 5 * time.Minute
+
+// nodeHeartbeatInterval is how often a node tells the cluster it is
+// still running, and nodeTimeout how long the other nodes keep the
+// presence of its connections after its last heartbeat.
+const (
+	nodeHeartbeatInterval = time.Minute
+	nodeTimeout           = 3 * nodeHeartbeatInterval
+)
+
+// PresenceChange is a websocket connection of a cluster node announcing
+// its presence on a board, or leaving it when Presence is nil.
+type PresenceChange struct {
+	NodeID    string
+	WebConnID string
+	Presence  *model.Presence
+}
+
+// NodeHeartbeat is a cluster node telling the other nodes that it is
+// running, or that it is leaving the cluster.
+type NodeHeartbeat struct {
+	NodeID  string
+	Leaving bool
+}
+
+// presenceTracker keeps the presence of the websocket connections of all
+// the cluster nodes. Each connection is on one board at most.
+type presenceTracker struct {
+	mu              sync.RWMutex
+	byWebConnID     map[string]*model.Presence
+	byBoard         map[string]map[string]*model.Presence
+	nodeByWebConnID map[string]string
+	nodeSeenAt      map[string]time.Time
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{
+		byWebConnID:     make(map[string]*model.Presence),
+		byBoard:         make(map[string]map[string]*model.Presence),
+		nodeByWebConnID: make(map[string]string),
+		nodeSeenAt:      make(map[string]time.Time),
+	}
+}
+
+// apply applies a presence change and returns the previous presence of
+// the connection, if any.
+func (pt *presenceTracker) apply(change *PresenceChange) *model.Presence {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	previous := pt.removeLocked(change.WebConnID)
+
+	if presence := change.Presence; presence != nil {
+		pt.byWebConnID[change.WebConnID] = presence
+		if pt.byBoard[presence.BoardID] == nil {
+			pt.byBoard[presence.BoardID] = make(map[string]*model.Presence)
+		}
+		pt.byBoard[presence.BoardID][change.WebConnID] = presence
+
+		if change.NodeID != "" {
+			pt.nodeByWebConnID[change.WebConnID] = change.NodeID
+			pt.nodeSeenAt[change.NodeID] = time.Now()
+		}
+	}
+	return previous
+}
+
+func (pt *presenceTracker) removeLocked(webConnID string) *model.Presence {
+	previous := pt.byWebConnID[webConnID]
+	if previous != nil {
+		delete(pt.byWebConnID, webConnID)
+		delete(pt.byBoard[previous.BoardID], webConnID)
+		if len(pt.byBoard[previous.BoardID]) == 0 {
+			delete(pt.byBoard, previous.BoardID)
+		}
+	}
+	delete(pt.nodeByWebConnID, webConnID)
+	return previous
+}
+
+// seen records that a node is running.
+func (pt *presenceTracker) seen(nodeID string) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.nodeSeenAt[nodeID] = time.Now()
+}
+
+// staleNodes returns the nodes that have not been seen since the given time.
+func (pt *presenceTracker) staleNodes(since time.Time) []string {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	var nodeIDs []string
+	for nodeID, seenAt := range pt.nodeSeenAt {
+		if seenAt.Before(since) {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	return nodeIDs
+}
+
+// removeNode forgets a node and the presence of its connections, and
+// returns the presences removed.
+func (pt *presenceTracker) removeNode(nodeID string) []*model.Presence {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	var removed []*model.Presence
+	for webConnID, connNodeID := range pt.nodeByWebConnID {
+		if connNodeID != nodeID {
+			continue
+		}
+		if presence := pt.removeLocked(webConnID); presence != nil {
+			removed = append(removed, presence)
+		}
+	}
+	delete(pt.nodeSeenAt, nodeID)
+	return removed
+}
+
+func (pt *presenceTracker) has(webConnID string) bool {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	_, ok := pt.byWebConnID[webConnID]
+	return ok
+}
+
+// getForBoard returns the presence of each user on a board, sorted by user.
+// A user on the board from several connections is editing if they are
+// editing from any of them.
+func (pt *presenceTracker) getForBoard(boardID string) []*model.Presence {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	expiredAt := utils.GetMillisForTime(time.Now().Add(-presenceTimeout))
+
+	byUser := map[string]*model.Presence{}
+	for _, presence := range pt.byBoard[boardID] {
+		if presence.UpdateAt < expiredAt {
+			continue
+		}
+		if current, ok := byUser[presence.UserID]; !ok || isMorePresent(presence, current) {
+			byUser[presence.UserID] = presence
+		}
+	}
+
+	presences := make([]*model.Presence, 0, len(byUser))
+	for _, presence := range byUser {
+		presences = append(presences, presence)
+	}
+	sort.Slice(presences, func(i, j int) bool {
+		return presences[i].UserID < presences[j].UserID
+	})
+	return presences
+}
+
+// getForUser returns the presence of a user on a board, or nil if the user
+// is not on it.
+func (pt *presenceTracker) getForUser(boardID, userID string) *model.Presence {
+	for _, presence := range pt.getForBoard(boardID) {
+		if presence.UserID == userID {
+			return presence
+		}
+	}
+	return nil
+}
+
+func isMorePresent(presence, other *model.Presence) bool {
+	if presence.Activity != other.Activity {
+		return presence.Activity == model.PresenceActivityEditing
+	}
+	return presence.UpdateAt > other.UpdateAt
+}
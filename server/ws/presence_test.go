@@ -0,0 +1,81 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/model"
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceTracker(t *testing.T) {
+	now := utils.GetMillis()
+	newPresence := func(userID, boardID string, activity model.PresenceActivity, updateAt int64) *model.Presence {
+		return &model.Presence{UserID: userID, TeamID: "team-id", BoardID: boardID, Activity: activity, UpdateAt: updateAt}
+	}
+
+	t.Run("a connection is on one board at most", func(t *testing.T) {
+		pt := newPresenceTracker()
+
+		first := newPresence("user-1", "board-1", model.PresenceActivityViewing, now)
+		require.Nil(t, pt.apply(&PresenceChange{WebConnID: "conn-1", Presence: first}))
+
+		second := newPresence("user-1", "board-2", model.PresenceActivityViewing, now)
+		require.Equal(t, first, pt.apply(&PresenceChange{WebConnID: "conn-1", Presence: second}))
+		require.Empty(t, pt.getForBoard("board-1"))
+		require.Equal(t, []*model.Presence{second}, pt.getForBoard("board-2"))
+
+		require.Equal(t, second, pt.apply(&PresenceChange{WebConnID: "conn-1"}))
+		require.False(t, pt.has("conn-1"))
+		require.Empty(t, pt.getForBoard("board-2"))
+	})
+
+	t.Run("users on several connections are editing if any of them is", func(t *testing.T) {
+		pt := newPresenceTracker()
+
+		editing := newPresence("user-1", "board-1", model.PresenceActivityEditing, now-1000)
+		pt.apply(&PresenceChange{WebConnID: "conn-1", Presence: editing})
+		pt.apply(&PresenceChange{WebConnID: "conn-2", Presence: newPresence("user-1", "board-1", model.PresenceActivityViewing, now)})
+		pt.apply(&PresenceChange{WebConnID: "conn-3", Presence: newPresence("user-2", "board-1", model.PresenceActivityViewing, now)})
+
+		presences := pt.getForBoard("board-1")
+		require.Len(t, presences, 2)
+		require.Equal(t, editing, presences[0])
+		require.Equal(t, "user-2", presences[1].UserID)
+		require.Equal(t, editing, pt.getForUser("board-1", "user-1"))
+		require.Nil(t, pt.getForUser("board-1", "user-3"))
+	})
+
+	t.Run("expired presence is skipped", func(t *testing.T) {
+		pt := newPresenceTracker()
+
+		expiredAt := utils.GetMillisForTime(time.Now().Add(-presenceTimeout - time.Minute))
+		pt.apply(&PresenceChange{WebConnID: "conn-1", Presence: newPresence("user-1", "board-1", model.PresenceActivityViewing, expiredAt)})
+
+		require.True(t, pt.has("conn-1"))
+		require.Empty(t, pt.getForBoard("board-1"))
+	})
+
+	t.Run("the presence of a node is removed with it", func(t *testing.T) {
+		pt := newPresenceTracker()
+
+		pt.apply(&PresenceChange{NodeID: "node-1", WebConnID: "conn-1", Presence: newPresence("user-1", "board-1", model.PresenceActivityViewing, now)})
+		pt.apply(&PresenceChange{NodeID: "node-1", WebConnID: "conn-2", Presence: newPresence("user-2", "board-2", model.PresenceActivityViewing, now)})
+		pt.apply(&PresenceChange{NodeID: "node-2", WebConnID: "conn-3", Presence: newPresence("user-3", "board-1", model.PresenceActivityViewing, now)})
+
+		require.Empty(t, pt.staleNodes(time.Now().Add(-time.Minute)))
+		require.ElementsMatch(t, []string{"node-1", "node-2"}, pt.staleNodes(time.Now().Add(time.Minute)))
+
+		require.Len(t, pt.removeNode("node-1"), 2)
+		require.False(t, pt.has("conn-1"))
+		require.False(t, pt.has("conn-2"))
+		require.Len(t, pt.getForBoard("board-1"), 1)
+		require.Empty(t, pt.getForBoard("board-2"))
+		require.Equal(t, []string{"node-2"}, pt.staleNodes(time.Now().Add(time.Minute)))
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-boards/server/utils"
+)
+
+const (
+	// replayLogSize is the number of events kept per board to be
+	// replayed to the clients that reconnect.
+	replayLogSize = 100
+
+	// replayLogRetention is how long the events of a board are kept
+	// after the last one was sent.
+	replayLogRetention = 15 * time.Minute
+
+	// replayLogPruneInterval is how often the events of the boards
+	// without recent events are removed from the log.
+	replayLogPruneInterval = time.Minute
+
+	// maxReplayBoards is the maximum number of boards a client can ask
+	// the missed events of when it subscribes.
+	maxReplayBoards = 500
+
+	sequenceField      = "sequence"
+	sequenceEpochField = "sequenceEpoch"
+)
+
+type boardReplayLog struct {
+	sequence int64
+	events   []map[string]interface{}
+	updateAt time.Time
+}
+
+// replayLog assigns sequence numbers to the events of each board and
+// keeps the last ones, so that the clients that reconnect can receive
+// the events they missed. Sequences are only meaningful in the epoch of
+// the log, which changes when the server restarts.
+//
+// Each node of a cluster has its own log, with its own epoch, as the
+// events can reach the nodes in different orders. A client that
+// reconnects to another node than the one it was connected to is told to
+// fetch its boards again, so replays only avoid that when the load
+// balancer keeps the clients on the same node.
+type replayLog struct {
+	mu             sync.Mutex
+	epoch          string
+	boards         map[string]*boardReplayLog
+	lastPruneAt    time.Time
+	hasSubscribers func(boardID string) bool
+
+	// prunedSequence is the highest sequence of the boards removed from
+	// the log. The sequences of a board added again start after it, so
+	// that the clients that saw the removed ones fetch the board again.
+	prunedSequence int64
+}
+
+func newReplayLog(hasSubscribers func(boardID string) bool) *replayLog {
+	return &replayLog{
+		epoch:          utils.NewID(utils.IDTypeNone),
+		boards:         make(map[string]*boardReplayLog),
+		lastPruneAt:    time.Now(),
+		hasSubscribers: hasSubscribers,
+	}
+}
+
+// add assigns the next sequence of a board to an event and keeps it to
+// be replayed. It returns a copy of the payload with the sequence, as the
+// payload may be shared with the cluster message of the event.
+func (rl *replayLog) add(boardID string, payload map[string]interface{}) map[string]interface{} {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(rl.lastPruneAt) > replayLogPruneInterval {
+		rl.prune(now)
+	}
+
+	board, ok := rl.boards[boardID]
+	if !ok {
+		board = &boardReplayLog{sequence: rl.prunedSequence}
+		rl.boards[boardID] = board
+	}
+	board.sequence++
+	board.updateAt = now
+
+	event := make(map[string]interface{}, len(payload)+2)
+	for key, value := range payload {
+		event[key] = value
+	}
+	event[sequenceField] = board.sequence
+	event[sequenceEpochField] = rl.epoch
+
+	board.events = append(board.events, event)
+	if len(board.events) > replayLogSize {
+		board.events = board.events[len(board.events)-replayLogSize:]
+	}
+	return event
+}
+
+// since returns the events of a board after the sequence a client last
+// saw. It returns false if the log doesn't cover all of them, and the
+// client needs to fetch the board again.
+func (rl *replayLog) since(epoch, boardID string, sequence int64) ([]map[string]interface{}, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if epoch != rl.epoch {
+		return nil, false
+	}
+
+	board, ok := rl.boards[boardID]
+	if !ok {
+		// the board has had no events in this epoch
+		return nil, sequence == 0
+	}
+	if sequence > board.sequence {
+		return nil, false
+	}
+	if sequence == board.sequence {
+		return nil, true
+	}
+
+	// the events may have been pruned since the client saw one
+	if int64(len(board.events)) < board.sequence-sequence {
+		return nil, false
+	}
+
+	missed := board.events[len(board.events)-int(board.sequence-sequence):]
+	events := make([]map[string]interface{}, len(missed))
+	copy(events, missed)
+	return events, true
+}
+
+// current returns the epoch of the log and the last sequence of a board.
+func (rl *replayLog) current(boardID string) (string, int64) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if board, ok := rl.boards[boardID]; ok {
+		return rl.epoch, board.sequence
+	}
+	return rl.epoch, 0
+}
+
+// prune removes the events of the boards without recent events. The
+// boards that nobody is on anymore are removed too, and the others keep
+// their sequences, so that they keep increasing in the epoch.
+func (rl *replayLog) prune(now time.Time) {
+	for boardID, board := range rl.boards {
+		if now.Sub(board.updateAt) <= replayLogRetention {
+			continue
+		}
+		board.events = nil
+
+		if !rl.hasSubscribers(boardID) {
+			if board.sequence > rl.prunedSequence {
+				rl.prunedSequence = board.sequence
+			}
+			delete(rl.boards, boardID)
+		}
+	}
+	rl.lastPruneAt = now
+}
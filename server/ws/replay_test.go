@@ -0,0 +1,116 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayLog(t *testing.T) {
+	openBoards := map[string]bool{}
+	isBoardOpen := func(boardID string) bool {
+		return openBoards[boardID]
+	}
+
+	t.Run("sequences increase per board", func(t *testing.T) {
+		rl := newReplayLog(isBoardOpen)
+
+		payload := map[string]interface{}{"action": websocketActionUpdateBlock}
+		first := rl.add("board-1", payload)
+		require.Equal(t, int64(1), first[sequenceField])
+		require.Equal(t, rl.epoch, first[sequenceEpochField])
+		require.NotContains(t, payload, sequenceField)
+
+		require.Equal(t, int64(2), rl.add("board-1", payload)[sequenceField])
+		require.Equal(t, int64(1), rl.add("board-2", payload)[sequenceField])
+
+		epoch, sequence := rl.current("board-1")
+		require.Equal(t, rl.epoch, epoch)
+		require.Equal(t, int64(2), sequence)
+	})
+
+	t.Run("missed events are returned", func(t *testing.T) {
+		rl := newReplayLog(isBoardOpen)
+		for i := 0; i < 5; i++ {
+			rl.add("board-1", map[string]interface{}{})
+		}
+
+		events, ok := rl.since(rl.epoch, "board-1", 3)
+		require.True(t, ok)
+		require.Len(t, events, 2)
+		require.Equal(t, int64(4), events[0][sequenceField])
+		require.Equal(t, int64(5), events[1][sequenceField])
+
+		events, ok = rl.since(rl.epoch, "board-1", 5)
+		require.True(t, ok)
+		require.Empty(t, events)
+
+		events, ok = rl.since(rl.epoch, "board-2", 0)
+		require.True(t, ok)
+		require.Empty(t, events)
+	})
+
+	t.Run("a resync is required if the log doesn't cover the gap", func(t *testing.T) {
+		rl := newReplayLog(isBoardOpen)
+		for i := 0; i < replayLogSize+10; i++ {
+			rl.add("board-1", map[string]interface{}{})
+		}
+
+		_, ok := rl.since(rl.epoch, "board-1", 5)
+		require.False(t, ok)
+
+		events, ok := rl.since(rl.epoch, "board-1", 10)
+		require.True(t, ok)
+		require.Len(t, events, replayLogSize)
+
+		_, ok = rl.since("other-epoch", "board-1", replayLogSize+10)
+		require.False(t, ok)
+
+		_, ok = rl.since(rl.epoch, "board-1", replayLogSize+11)
+		require.False(t, ok)
+
+		_, ok = rl.since(rl.epoch, "board-2", 1)
+		require.False(t, ok)
+	})
+
+	t.Run("pruned boards keep their sequence while they are open", func(t *testing.T) {
+		openBoards["board-1"] = true
+		defer delete(openBoards, "board-1")
+
+		rl := newReplayLog(isBoardOpen)
+		rl.add("board-1", map[string]interface{}{})
+		rl.add("board-1", map[string]interface{}{})
+
+		rl.prune(time.Now().Add(replayLogRetention + time.Minute))
+
+		_, ok := rl.since(rl.epoch, "board-1", 1)
+		require.False(t, ok)
+		_, ok = rl.since(rl.epoch, "board-1", 2)
+		require.True(t, ok)
+		require.Equal(t, int64(3), rl.add("board-1", map[string]interface{}{})[sequenceField])
+	})
+
+	t.Run("pruned boards are removed when they are not open", func(t *testing.T) {
+		rl := newReplayLog(isBoardOpen)
+		for i := 0; i < 3; i++ {
+			rl.add("board-1", map[string]interface{}{})
+		}
+
+		rl.prune(time.Now().Add(replayLogRetention + time.Minute))
+		require.NotContains(t, rl.boards, "board-1")
+
+		_, ok := rl.since(rl.epoch, "board-1", 3)
+		require.False(t, ok)
+
+		// the sequences of the board start after the removed ones, so that
+		// the clients that saw them still fetch the board again
+		require.Equal(t, int64(4), rl.add("board-1", map[string]interface{}{})[sequenceField])
+		require.Equal(t, int64(4), rl.add("board-2", map[string]interface{}{})[sequenceField])
+		_, ok = rl.since(rl.epoch, "board-1", 2)
+		require.False(t, ok)
+	})
+}
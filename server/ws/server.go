@@ -55,6 +55,7 @@ type Server struct {
 	isMattermostAuth bool
 	logger           mlog.LoggerIFace
 	store            Store
+	replay           *replayLog
 }
 
 type websocketSession struct {
@@ -71,7 +72,7 @@ func (wss *websocketSession) isAuthenticated() bool {
 
 // NewServer creates a new Server.
 func NewServer(auth *auth.Auth, logger mlog.LoggerIFace, store Store) *Server {
-	return &Server{
+	ws := &Server{
 		listeners:        make(map[*websocketSession]bool),
 		listenersByTeam:  make(map[string][]*websocketSession),
 		listenersByBlock: make(map[string][]*websocketSession),
@@ -85,6 +86,17 @@ func NewServer(auth *auth.Auth, logger mlog.LoggerIFace, store Store) *Server {
 		logger:           logger,
 		store:            store,
 	}
+	ws.replay = newReplayLog(ws.hasBoardListeners)
+	return ws
+}
+
+// hasBoardListeners returns whether any connection is subscribed to the
+// blocks of a board.
+func (ws *Server) hasBoardListeners(boardID string) bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	return len(ws.listenersByBlock[boardID]) > 0
 }
 
 // RegisterRoutes registers routes.
@@ -146,6 +158,16 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		if len(command.Sequences) > maxReplayBoards {
+			ws.logger.Error(`ERROR webSocket command with too many sequences`,
+				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
+				mlog.String("action", command.Action),
+				mlog.Int("count", len(command.Sequences)),
+			)
+
+			continue
+		}
+
 		if command.Action == websocketActionAuth {
 			ws.logger.Debug(`Command: AUTH`, mlog.Stringer("client", wsSession.conn.RemoteAddr()))
 			ws.authenticateListener(wsSession, command.Token)
@@ -173,6 +195,9 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			}
 
 			ws.subscribeListenerToBlocks(wsSession, command.BlockIDs)
+			ws.replayMissedEvents(wsSession, command, func() (map[string]bool, error) {
+				return ws.readTokenBoardIDs(command)
+			})
 			continue
 		}
 
@@ -220,6 +245,9 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			}
 
 			ws.subscribeListenerToTeam(wsSession, command.TeamID)
+			ws.replayMissedEvents(wsSession, command, func() (map[string]bool, error) {
+				return memberBoardIDs(ws.store, wsSession.userID)
+			})
 		case websocketActionUnsubscribeTeam:
 			ws.logger.Debug(`Command: UNSUBSCRIBE_TEAM`,
 				mlog.String("teamID", command.TeamID),
@@ -271,6 +299,62 @@ func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
 	return isValid
 }
 
+// readTokenBoardIDs returns the boards of the sequences of a command
+// that its read token is valid for.
+func (ws *Server) readTokenBoardIDs(command WebsocketCommand) (map[string]bool, error) {
+	boardIDs := map[string]bool{}
+	for boardID := range command.Sequences {
+		isValid, err := ws.auth.IsValidReadToken(boardID, command.ReadToken)
+		if err != nil {
+			return nil, err
+		}
+		boardIDs[boardID] = isValid
+	}
+	return boardIDs, nil
+}
+
+// replayMissedEvents sends to a session that subscribes the events of
+// the boards after the sequences the client last saw, or that it needs to
+// fetch a board again if they are not available anymore. Only the boards
+// that allowedBoardIDs returns are considered.
+func (ws *Server) replayMissedEvents(wsSession *websocketSession, command WebsocketCommand, allowedBoardIDs func() (map[string]bool, error)) {
+	if len(command.Sequences) == 0 {
+		return
+	}
+
+	boardIDs, err := allowedBoardIDs()
+	if err != nil {
+		ws.logger.Error("cannot check board access to replay events", mlog.Err(err))
+		return
+	}
+
+	for boardID, sequence := range command.Sequences {
+		if !boardIDs[boardID] {
+			continue
+		}
+
+		events, ok := ws.replay.since(command.SequenceEpoch, boardID, sequence)
+		if !ok {
+			epoch, current := ws.replay.current(boardID)
+			events = []map[string]interface{}{utils.StructToMap(ResyncRequiredMsg{
+				Action:        websocketActionResyncRequired,
+				TeamID:        command.TeamID,
+				BoardID:       boardID,
+				SequenceEpoch: epoch,
+				Sequence:      current,
+			})}
+		}
+
+		for _, event := range events {
+			if err = wsSession.WriteJSON(event); err != nil {
+				ws.logger.Error("replay error", mlog.Err(err))
+				wsSession.conn.Close()
+				return
+			}
+		}
+	}
+}
+
 // addListener adds a listener to the websocket server. The listener
 // should not receive any update from the server until it subscribes
 // itself to some entity changes. Adding a listener to the server
@@ -505,11 +589,11 @@ func (ws *Server) BroadcastBlockDelete(teamID, blockID, boardID string) {
 func (ws *Server) BroadcastBlockChange(teamID string, block *model.Block) {
 	blockIDsToNotify := []string{block.ID, block.ParentID}
 
-	message := UpdateBlockMsg{
+	message := ws.replay.add(block.BoardID, utils.StructToMap(UpdateBlockMsg{
 		Action: websocketActionUpdateBlock,
 		TeamID: teamID,
 		Block:  block,
-	}
+	}))
 
 	listeners := ws.getListenersForTeamAndBoard(teamID, block.BoardID)
 	ws.logger.Trace("listener(s) for teamID",
@@ -658,11 +742,11 @@ func (ws *Server) BroadcastConfigChange(clientConfig model.ClientConfig) {
 }
 
 func (ws *Server) BroadcastBoardChange(teamID string, board *model.Board) {
-	message := UpdateBoardMsg{
+	message := ws.replay.add(board.ID, utils.StructToMap(UpdateBoardMsg{
 		Action: websocketActionUpdateBoard,
 		TeamID: teamID,
 		Board:  board,
-	}
+	}))
 
 	listeners := ws.getListenersForTeamAndBoard(teamID, board.ID)
 	ws.logger.Trace("listener(s) for teamID and boardID",
@@ -698,11 +782,11 @@ func (ws *Server) BroadcastBoardDelete(teamID, boardID string) {
 }
 
 func (ws *Server) BroadcastMemberChange(teamID, boardID string, member *model.BoardMember) {
-	message := UpdateMemberMsg{
+	message := ws.replay.add(boardID, utils.StructToMap(UpdateMemberMsg{
 		Action: websocketActionUpdateMember,
 		TeamID: teamID,
 		Member: member,
-	}
+	}))
 
 	listeners := ws.getListenersForTeamAndBoard(teamID, boardID)
 	ws.logger.Trace("listener(s) for teamID and boardID",
@@ -727,11 +811,11 @@ func (ws *Server) BroadcastMemberChange(teamID, boardID string, member *model.Bo
 }
 
 func (ws *Server) BroadcastMemberDelete(teamID, boardID, userID string) {
-	message := UpdateMemberMsg{
+	message := ws.replay.add(boardID, utils.StructToMap(UpdateMemberMsg{
 		Action: websocketActionDeleteMember,
 		TeamID: teamID,
 		Member: &model.BoardMember{UserID: userID, BoardID: boardID},
-	}
+	}))
 
 	// when fetching the members of the board that should receive the
 	// member deletion message, the deleted member will not be one of
@@ -765,3 +849,8 @@ func (ws *Server) BroadcastSubscriptionChange(workspaceID string, subscription *
 func (ws *Server) BroadcastCardLimitTimestampChange(cardLimitTimestamp int64) {
 	// not implemented for standalone server.
 }
+
+func (ws *Server) GetBoardPresence(boardID string) []*model.Presence {
+	// presence is only tracked in plugin mode.
+	return []*model.Presence{}
+}
@@ -32,6 +32,16 @@ describe('block tests', () => {
             expect(result).toMatchSnapshot()
         })
 
+        it('should only patch the card properties that changed', () => {
+            const oldBlock = TestBlockFactory.createCard(board)
+            oldBlock.fields.properties = {kept: 'a', changed: 'b', removed: 'c'}
+            const newBlock = createBlock(oldBlock)
+            newBlock.fields.properties = {kept: 'a', changed: 'new', added: 'd'}
+            const [patch, undoPatch] = createPatchesFromBlocks(newBlock, oldBlock)
+            expect(patch.updatedFields).toEqual({properties: {changed: 'new', added: 'd', removed: null}})
+            expect(undoPatch.updatedFields).toEqual({properties: {changed: 'b', removed: 'c', added: null}})
+        })
+
         it('should update propertie on the main object and revert it back on the undo', () => {
             const oldBlock = TestBlockFactory.createText(card)
             const newBlock = createBlock(oldBlock)
@@ -71,6 +71,28 @@ function createBlock(block?: Block): Block {
     }
 }
 
+// createPropertiesPatch returns the card properties that changed from
+// the old properties to the new ones. The server merges the properties
+// per key, so the removed ones are set to null
+function createPropertiesPatch(newProperties: Record<string, any>, oldProperties: Record<string, any>): Record<string, any> {
+    const patch: Record<string, any> = {}
+    Object.keys(newProperties).forEach((key) => {
+        if (oldProperties[key] !== newProperties[key]) {
+            patch[key] = newProperties[key]
+        }
+    })
+    difference(Object.keys(oldProperties), Object.keys(newProperties)).forEach((key) => {
+        patch[key] = null
+    })
+    return patch
+}
+
+function isPropertiesField(val: string, newBlock: Block, oldBlock: Block): boolean {
+    return val === 'properties' &&
+        typeof newBlock.fields.properties === 'object' && newBlock.fields.properties !== null &&
+        typeof oldBlock.fields.properties === 'object' && oldBlock.fields.properties !== null
+}
+
 // createPatchesFromBlocks creates two BlockPatch instances, one that
 // contains the delta to update the block and another one for the undo
 // action, in case it happens
@@ -80,6 +102,10 @@ function createPatchesFromBlocks(newBlock: Block, oldBlock: Block): BlockPatch[]
     const newUpdatedData: Record<string, any> = {}
     Object.keys(newBlock.fields).forEach((val) => {
         if (oldBlock.fields[val] !== newBlock.fields[val]) {
+            if (isPropertiesField(val, newBlock, oldBlock)) {
+                newUpdatedFields[val] = createPropertiesPatch(newBlock.fields.properties, oldBlock.fields.properties)
+                return
+            }
             newUpdatedFields[val] = newBlock.fields[val]
         }
     })
@@ -94,6 +120,10 @@ function createPatchesFromBlocks(newBlock: Block, oldBlock: Block): BlockPatch[]
     const oldUpdatedData: Record<string, any> = {}
     Object.keys(oldBlock.fields).forEach((val) => {
         if (oldBlock.fields[val] !== newBlock.fields[val]) {
+            if (isPropertiesField(val, newBlock, oldBlock)) {
+                oldUpdatedFields[val] = createPropertiesPatch(oldBlock.fields.properties, newBlock.fields.properties)
+                return
+            }
             oldUpdatedFields[val] = oldBlock.fields[val]
         }
     })